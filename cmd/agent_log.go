@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+func agentLogsEnabled(cfg core.Config) bool {
+	return cfg.AgentLogs != nil && *cfg.AgentLogs
+}
+
+// startAgentPaneLogging mirrors the agent pane into
+// ~/.wtx/logs/<repo>/<branch>-<ts>.log when agent logging is enabled.
+func startAgentPaneLogging(paneID string, worktreePath string, branch string) (string, error) {
+	paneID = strings.TrimSpace(paneID)
+	if paneID == "" {
+		return "", nil
+	}
+	cfg, err := core.LoadConfig()
+	if err != nil || !agentLogsEnabled(cfg) {
+		return "", err
+	}
+	dir, err := agentLogDir(worktreePath)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	// Make room for the new log before it is created.
+	if err := rotateAgentLogs(dir, core.AgentLogKeep(cfg)-1); err != nil {
+		return "", err
+	}
+	core.MaybePruneLogs()
+	path := filepath.Join(dir, agentLogFileName(branch, time.Now()))
+	out, err := core.TmuxCombinedOutput("pipe-pane", "-o", "-t", paneID, tmuxFormatEscape("cat >> "+core.ShellQuote(path)))
+	if err != nil {
+		return "", core.CommandErrorWithOutput(err, out)
+	}
+	return path, nil
+}
+
+func agentLogDir(worktreePath string) (string, error) {
+	_, repoRoot, err := core.RequireGitContext(worktreePath)
+	if err != nil {
+		return "", err
+	}
+	home, err := core.WtxHomeDir()
+	if err != nil {
+		return "", err
+	}
+	repoName := sanitizeLogName(filepath.Base(core.WorktreeLayoutRoot(repoRoot, "git")))
+	return filepath.Join(home, "logs", repoName), nil
+}
+
+func agentLogFileName(branch string, now time.Time) string {
+	branch = strings.TrimSpace(branch)
+	if branch == "" {
+		branch = "detached"
+	}
+	return sanitizeLogName(branch) + "-" + now.Format("20060102-150405") + ".log"
+}
+
+func sanitizeLogName(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '_', r == '-':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('-')
+		}
+	}
+	out := strings.Trim(b.String(), "-.")
+	if out == "" {
+		return "unnamed"
+	}
+	return out
+}
+
+// rotateAgentLogs keeps the newest keep logs in dir and removes the rest.
+func rotateAgentLogs(dir string, keep int) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	type logFile struct {
+		path    string
+		modTime time.Time
+	}
+	logs := make([]logFile, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".log" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		logs = append(logs, logFile{path: filepath.Join(dir, entry.Name()), modTime: info.ModTime()})
+	}
+	if keep < 0 {
+		keep = 0
+	}
+	if len(logs) <= keep {
+		return nil
+	}
+	sort.Slice(logs, func(i, j int) bool {
+		return logs[i].modTime.After(logs[j].modTime)
+	})
+	for _, log := range logs[keep:] {
+		if err := os.Remove(log.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
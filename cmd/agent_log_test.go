@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAgentLogFileName(t *testing.T) {
+	now := time.Date(2024, 3, 9, 14, 5, 7, 0, time.UTC)
+	got := agentLogFileName("feature/login flow", now)
+	want := "feature-login-flow-20240309-140507.log"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestRotateAgentLogsKeepsNewest(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Now().Add(-time.Hour)
+	for i, name := range []string{"a.log", "b.log", "c.log", "notes.txt"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("x"), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		ts := base.Add(time.Duration(i) * time.Minute)
+		if err := os.Chtimes(path, ts, ts); err != nil {
+			t.Fatalf("chtimes %s: %v", name, err)
+		}
+	}
+
+	if err := rotateAgentLogs(dir, 2); err != nil {
+		t.Fatalf("rotateAgentLogs: %v", err)
+	}
+	for name, wantExists := range map[string]bool{"a.log": false, "b.log": true, "c.log": true, "notes.txt": true} {
+		_, err := os.Stat(filepath.Join(dir, name))
+		if exists := err == nil; exists != wantExists {
+			t.Fatalf("%s exists=%v, want %v", name, exists, wantExists)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+// agentPaneForLock finds the tmux pane whose process holds the worktree lock.
+func agentPaneForLock(lockMgr *core.LockManager, repoRoot string, worktreePath string) string {
+	pid, ok := lockMgr.HolderPID(repoRoot, worktreePath)
+	if !ok {
+		return ""
+	}
+	out, err := core.TmuxOutput("list-panes", "-a", "-F", "#{pane_id} #{pane_pid}")
+	if err != nil {
+		return ""
+	}
+	return paneWithPID(string(out), pid)
+}
+
+func paneWithPID(output string, pid int) string {
+	for _, line := range strings.Split(output, "\n") {
+		paneID, panePID, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if ok && panePID == strconv.Itoa(pid) {
+			return paneID
+		}
+	}
+	return ""
+}
+
+// respawnAgentPane replaces whatever runs in the worktree's agent pane with
+// command and moves the lock to the new process, so the worktree stays held
+// throughout: this process holds it while tmux kills the old one. The pane is
+// the one holding the lock, or the popup's source pane when the lock is
+// already gone; takeLock then locks it again.
+func respawnAgentPane(basePath string, sourcePane string, command string, takeLock bool) error {
+	_, repoRoot, err := core.RequireGitContext(basePath)
+	if err != nil {
+		return err
+	}
+	lockMgr := core.NewLockManager()
+	paneID := agentPaneForLock(lockMgr, repoRoot, basePath)
+	if paneID == "" {
+		paneID = popupSourcePane(sourcePane)
+	}
+	if paneID == "" {
+		return errors.New("unable to find the agent pane")
+	}
+	ownerID, held := lockMgr.HolderOwnerID(repoRoot, basePath)
+	var lock *core.WorktreeLock
+	previousPID := 0
+	if held {
+		if lock, err = lockMgr.Adopt(repoRoot, basePath); err != nil {
+			return err
+		}
+		previousPID = lock.PID
+		if err := lock.Rebind(lockMgr.SelfOwnerID(), os.Getpid()); err != nil {
+			return err
+		}
+	} else if takeLock {
+		ownerID = lockMgr.SelfOwnerID()
+		if lock, err = lockMgr.Acquire(repoRoot, basePath); err != nil {
+			return err
+		}
+	}
+	if err := core.TmuxRun("respawn-pane", "-k", "-c", tmuxFormatEscape(basePath), "-t", paneID, command); err != nil {
+		if held {
+			_ = lock.Rebind(ownerID, previousPID)
+		} else {
+			lock.Release()
+		}
+		return err
+	}
+	if lock == nil {
+		return nil
+	}
+	pid, err := panePID(paneID)
+	if err != nil {
+		lock.Release()
+		return err
+	}
+	return lock.Rebind(ownerID, pid)
+}
+
+// killAgent swaps the agent for a login shell in the same pane. tmux kills
+// the agent with SIGHUP, which the agent wrapper does not trap, so its exit
+// is recorded here.
+func killAgent(basePath string, sourcePane string) error {
+	if err := respawnAgentPane(basePath, sourcePane, core.LoginShellCommand, false); err != nil {
+		return err
+	}
+	previous, _ := core.ReadTmuxAgentState(basePath)
+	_ = writeTmuxAgentState(basePath, core.TmuxAgentState{
+		State:         "exited",
+		ExitCode:      130,
+		StartedAtUnix: previous.StartedAtUnix,
+		ExitedAtUnix:  time.Now().Unix(),
+		AgentCommand:  previous.AgentCommand,
+	})
+	return nil
+}
+
+// restartAgent runs the agent command the pane last started again.
+func restartAgent(basePath string, sourcePane string) error {
+	state, _ := core.ReadTmuxAgentState(basePath)
+	runCmd := agentResumeBase(state.AgentCommand)
+	if runCmd == "" {
+		runCmd = core.SavedAgentCommand(basePath)
+	}
+	if runCmd == "" {
+		return errors.New("no agent command recorded for this worktree")
+	}
+	return respawnAgentPane(basePath, sourcePane, commandToRunInTmux(basePath, false, agentLaunch{Command: runCmd, Base: runCmd}), true)
+}
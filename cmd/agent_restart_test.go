@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+func TestKillAgentKeepsTheLockOnTheRespawnedPane(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake tmux is a shell script")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	bin := filepath.Join(home, "bin")
+	if err := os.MkdirAll(bin, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	calls := filepath.Join(home, "tmux.log")
+	fake := "#!/bin/sh\necho \"$@\" >> " + core.ShellQuote(calls) + "\n" +
+		"case \"$1\" in\n" +
+		"list-panes) echo '%0 1'; echo '%1 " + strconv.Itoa(os.Getpid()) + "' ;;\n" +
+		"display-message) echo 1 ;;\n" +
+		"esac\n"
+	if err := os.WriteFile(filepath.Join(bin, "tmux"), []byte(fake), 0o755); err != nil {
+		t.Fatalf("write fake tmux: %v", err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	repo := initRenameTestRepo(t)
+	lockMgr := core.NewLockManager()
+	if _, err := lockMgr.AcquireForPID(repo, repo, os.Getpid()); err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+	if err := writeTmuxAgentState(repo, core.TmuxAgentState{State: "running", StartedAtUnix: 1, AgentCommand: "claude"}); err != nil {
+		t.Fatalf("write agent state: %v", err)
+	}
+
+	if err := killAgent(repo, ""); err != nil {
+		t.Fatalf("killAgent: %v", err)
+	}
+	data, err := os.ReadFile(calls)
+	if err != nil {
+		t.Fatalf("read tmux log: %v", err)
+	}
+	if !strings.Contains(string(data), "respawn-pane -k -c "+repo+" -t %1 "+core.LoginShellCommand) {
+		t.Fatalf("expected the agent pane respawned with a shell, got:\n%s", data)
+	}
+	if pid, ok := lockMgr.HolderPID(repo, repo); !ok || pid != 1 {
+		t.Fatalf("expected the lock moved to the new pane process, got %d (%v)", pid, ok)
+	}
+	if state, _ := core.ReadTmuxAgentState(repo); state.State != "exited" || state.ExitCode != 130 || state.AgentCommand != "claude" {
+		t.Fatalf("agent state = %+v", state)
+	}
+}
+
+func TestRespawnAgentPaneHoldsTheLockDuringTheRespawn(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake tmux is a shell script")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	repo := initRenameTestRepo(t)
+	lockMgr := core.NewLockManager()
+	lockPath, err := lockMgr.LockPath(repo, repo)
+	if err != nil {
+		t.Fatalf("lock path: %v", err)
+	}
+	bin := filepath.Join(home, "bin")
+	if err := os.MkdirAll(bin, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	held := filepath.Join(home, "held.json")
+	fake := "#!/bin/sh\n" +
+		"case \"$1\" in\n" +
+		"list-panes) echo '%1 " + strconv.Itoa(os.Getpid()) + "' ;;\n" +
+		"respawn-pane) cp " + core.ShellQuote(lockPath) + " " + core.ShellQuote(held) + " ;;\n" +
+		"display-message) echo 1 ;;\n" +
+		"esac\n"
+	if err := os.WriteFile(filepath.Join(bin, "tmux"), []byte(fake), 0o755); err != nil {
+		t.Fatalf("write fake tmux: %v", err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	if _, err := lockMgr.AcquireForOwner(repo, repo, "explicit:agent", os.Getpid()); err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+
+	if err := respawnAgentPane(repo, "", core.LoginShellCommand, false); err != nil {
+		t.Fatalf("respawnAgentPane: %v", err)
+	}
+	during, err := core.ReadLockPayload(held)
+	if err != nil {
+		t.Fatalf("expected the lock to exist while the pane respawned: %v", err)
+	}
+	if during.OwnerID != lockMgr.SelfOwnerID() || during.PID != os.Getpid() {
+		t.Fatalf("expected wtx to hold the lock during the respawn, got %+v", during)
+	}
+	if owner, _ := lockMgr.HolderOwnerID(repo, repo); owner != "explicit:agent" {
+		t.Fatalf("expected the original owner back after the respawn, got %q", owner)
+	}
+	if pid, _ := lockMgr.HolderPID(repo, repo); pid != 1 {
+		t.Fatalf("expected the lock on the new pane process, got %d", pid)
+	}
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+const (
+	agentSessionNew    = "New session"
+	agentSessionResume = "Resume session"
+)
+
+// agentResumeArgs holds the flags that continue the most recent conversation
+// for agents that support it.
+var agentResumeArgs = map[string]string{
+	"claude":   "--continue",
+	"codex":    "resume --last",
+	"opencode": "--continue",
+}
+
+// chooseAgentSession offers to resume the agent that last ran in the worktree
+// once it has exited. It starts baseCmd with prompt when there is nothing to
+// resume, a prompt was given, or wtx may not prompt.
+func chooseAgentSession(cfg core.Config, worktreePath string, baseCmd string, prompt string, interactive bool) (agentLaunch, error) {
+	newLaunch := agentLaunch{
+		Command: agentCommandWithPrompt(baseCmd, prompt),
+		Base:    agentCommandWithPrompt(baseCmd, ""),
+	}
+	if strings.TrimSpace(prompt) != "" {
+		return newLaunch, nil
+	}
+	if !interactive || !isInteractiveTerminalFn(os.Stdin) || !isInteractiveTerminalFn(os.Stdout) {
+		return newLaunch, nil
+	}
+	state, ok := core.ReadTmuxAgentState(worktreePath)
+	if !ok || state.State != "exited" {
+		return newLaunch, nil
+	}
+	resumeCmd := agentResumeCommand(cfg, state.AgentCommand)
+	if resumeCmd == "" {
+		return newLaunch, nil
+	}
+	selected, err := promptCommandSelectionFn("Agent session", []string{agentSessionNew, agentSessionResume}, "agent command")
+	if err != nil {
+		return agentLaunch{}, err
+	}
+	switch selected {
+	case agentSessionNew:
+		return newLaunch, nil
+	case agentSessionResume:
+		return agentLaunch{Command: resumeCmd, Base: agentResumeBase(state.AgentCommand)}, nil
+	default:
+		return agentLaunch{Command: selected, Base: selected}, nil
+	}
+}
+
+// agentResumeCommand returns the command that resumes a previous run of
+// agentCommand, keeping the flags it was started with, or "" when the agent
+// has no known resume flag.
+func agentResumeCommand(cfg core.Config, agentCommand string) string {
+	base := agentResumeBase(agentCommand)
+	fields := strings.Fields(base)
+	if len(fields) == 0 {
+		return ""
+	}
+	if v := strings.TrimSpace(cfg.AgentResumeCommand); v != "" {
+		return v
+	}
+	args, ok := agentResumeArgs[agentBinaryName(fields[0])]
+	if !ok {
+		return ""
+	}
+	return base + " " + args
+}
+
+// agentResumeBase strips resume flags from a recorded agent command. Older
+// wtx versions recorded the resumed command itself, which would otherwise
+// stack the flags on every resume.
+func agentResumeBase(agentCommand string) string {
+	base := strings.TrimSpace(agentCommand)
+	fields := strings.Fields(base)
+	if len(fields) == 0 {
+		return ""
+	}
+	args, ok := agentResumeArgs[agentBinaryName(fields[0])]
+	if !ok {
+		return base
+	}
+	for strings.HasSuffix(base, " "+args) {
+		base = strings.TrimSpace(strings.TrimSuffix(base, " "+args))
+	}
+	return base
+}
+
+func agentBinaryName(field string) string {
+	return filepath.Base(strings.Trim(field, `'"`))
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+func TestAgentResumeCommand(t *testing.T) {
+	cases := []struct {
+		name  string
+		cfg   core.Config
+		agent string
+		want  string
+	}{
+		{name: "claude", agent: "claude --model opus", want: "claude --model opus --continue"},
+		{name: "codex path", agent: "/usr/local/bin/codex", want: "/usr/local/bin/codex resume --last"},
+		{name: "unknown agent", agent: "aider", want: ""},
+		{name: "no previous agent", cfg: core.Config{AgentResumeCommand: "aider --restore"}, agent: "", want: ""},
+		{name: "configured override", cfg: core.Config{AgentResumeCommand: "aider --restore"}, agent: "aider", want: "aider --restore"},
+		{name: "already resumed claude", agent: "claude --continue --continue", want: "claude --continue"},
+		{name: "already resumed codex", agent: "codex resume --last", want: "codex resume --last"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := agentResumeCommand(tc.cfg, tc.agent); got != tc.want {
+				t.Fatalf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestChooseAgentSessionOnlyResumesExitedAgents(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	prevInteractive := isInteractiveTerminalFn
+	prevPrompt := promptCommandSelectionFn
+	t.Cleanup(func() {
+		isInteractiveTerminalFn = prevInteractive
+		promptCommandSelectionFn = prevPrompt
+	})
+	isInteractiveTerminalFn = func(_ *os.File) bool { return true }
+	prompted := false
+	promptCommandSelectionFn = func(string, []string, string) (string, error) {
+		prompted = true
+		return agentSessionResume, nil
+	}
+	worktree := initRenameTestRepo(t)
+	if err := writeTmuxAgentState(worktree, core.TmuxAgentState{State: "running", AgentCommand: "claude --model opus"}); err != nil {
+		t.Fatalf("write state: %v", err)
+	}
+	if got, err := chooseAgentSession(core.Config{}, worktree, "claude", "", true); err != nil || got.Command != "claude" || prompted {
+		t.Fatalf("expected no resume offer while the agent runs, got %q prompted=%v err=%v", got, prompted, err)
+	}
+	if err := writeTmuxAgentState(worktree, core.TmuxAgentState{State: "exited", AgentCommand: "claude --model opus"}); err != nil {
+		t.Fatalf("write state: %v", err)
+	}
+	got, err := chooseAgentSession(core.Config{}, worktree, "claude", "", true)
+	if err != nil || !prompted {
+		t.Fatalf("expected a resume offer, prompted=%v err=%v", prompted, err)
+	}
+	if got.Command != "claude --model opus --continue" {
+		t.Fatalf("expected the resumed agent to keep its flags, got %q", got.Command)
+	}
+	if got.Base != "claude --model opus" {
+		t.Fatalf("expected the base command without resume flags, got %q", got.Base)
+	}
+}
+
+func TestChooseAgentSessionSkipsPromptWhenPromptGiven(t *testing.T) {
+	prevInteractive := isInteractiveTerminalFn
+	prevPrompt := promptCommandSelectionFn
+	t.Cleanup(func() {
+		isInteractiveTerminalFn = prevInteractive
+		promptCommandSelectionFn = prevPrompt
+	})
+	isInteractiveTerminalFn = func(_ *os.File) bool { return true }
+	promptCommandSelectionFn = func(string, []string, string) (string, error) {
+		t.Fatalf("did not expect session picker")
+		return "", nil
+	}
+	got, err := chooseAgentSession(core.Config{}, t.TempDir(), "claude", "fix it", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Command != "claude 'fix it'" {
+		t.Fatalf("expected new command, got %q", got.Command)
+	}
+	if got.Base != "claude" {
+		t.Fatalf("expected the prompt to stay out of the base command, got %q", got.Base)
+	}
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aixolotls/wtx/internal/core"
+	"github.com/spf13/cobra"
+)
+
+func newHistoryCommand() *cobra.Command {
+	var all bool
+	var asJSON bool
+	var action string
+	var since time.Duration
+	var limit int
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Show recorded worktree actions (create, delete, lock, rename, agent launch)",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			filter := core.AuditFilter{Action: strings.TrimSpace(action)}
+			if since > 0 {
+				filter.Since = time.Now().Add(-since)
+			}
+			if !all {
+				_, repoRoot, err := core.RequireGitContext("")
+				if err != nil {
+					return fmt.Errorf("%w (use --all for every repo)", err)
+				}
+				filter.Repo = core.AuditRepo(repoRoot)
+			}
+			return runHistory(os.Stdout, filter, limit, asJSON)
+		},
+	}
+	cmd.Flags().BoolVar(&all, "all", false, "Show actions in every repo, not just the current one")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print machine-readable JSON")
+	cmd.Flags().StringVar(&action, "action", "", "Only show this action ("+strings.Join(auditActions(), ", ")+")")
+	cmd.Flags().DurationVar(&since, "since", 0, "Only show actions newer than this, e.g. 24h")
+	cmd.Flags().IntVarP(&limit, "limit", "n", 50, "Show at most this many of the latest actions (0 for all)")
+	return cmd
+}
+
+func auditActions() []string {
+	return []string{core.AuditCreate, core.AuditDelete, core.AuditDeleteBranch, core.AuditLock, core.AuditLockSteal, core.AuditUnlock, core.AuditRename, core.AuditAgent}
+}
+
+func runHistory(w io.Writer, filter core.AuditFilter, limit int, asJSON bool) error {
+	entries, err := core.ReadAuditLog(filter)
+	if err != nil {
+		return err
+	}
+	if limit > 0 && len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+	if asJSON {
+		if entries == nil {
+			entries = []core.AuditEntry{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "No recorded actions.")
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tACTION\tUSER\tWORKTREE\tBRANCH\tDETAILS")
+	for _, entry := range entries {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			entry.Time.Local().Format("2006-01-02 15:04:05"),
+			entry.Action,
+			core.LockHolder{User: entry.User, Host: entry.Host}.UserHost(),
+			filepath.Base(entry.Worktree),
+			entry.Branch,
+			formatAuditDetails(entry.Details))
+	}
+	return tw.Flush()
+}
+
+func formatAuditDetails(details map[string]string) string {
+	keys := make([]string, 0, len(details))
+	for key := range details {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, key+"="+details[key])
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+func TestAuditLogRecordsWorktreeActions(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USER", "alice")
+	repo := initRenameTestRepo(t)
+	lockMgr := core.NewLockManager()
+	mgr := core.NewWorktreeManager(repo, lockMgr)
+
+	wt, err := mgr.CreateWorktree("feature", "HEAD", nil)
+	if err != nil {
+		t.Fatalf("CreateWorktree: %v", err)
+	}
+	stealer := &core.LockManager{}
+	if _, err := stealer.AcquireForOwner(repo, wt.Path, "gone-owner", 999999); err != nil {
+		t.Fatalf("AcquireForOwner: %v", err)
+	}
+	lock, err := stealer.Acquire(wt.Path, wt.Path)
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	lock.Release()
+	if _, err := mgr.DeleteWorktree(wt.Path, false); err != nil {
+		t.Fatalf("DeleteWorktree: %v", err)
+	}
+
+	entries, err := core.ReadAuditLog(core.AuditFilter{Repo: core.AuditRepo(repo)})
+	if err != nil {
+		t.Fatalf("readAuditLog: %v", err)
+	}
+	var actions []string
+	for _, entry := range entries {
+		actions = append(actions, entry.Action)
+		if entry.User != "alice" || entry.PID == 0 {
+			t.Fatalf("expected user and pid on %+v", entry)
+		}
+	}
+	// Create and delete each take a short-lived lock on the slot.
+	want := []string{core.AuditLock, core.AuditCreate, core.AuditLock, core.AuditLockSteal, core.AuditLock, core.AuditDelete}
+	if strings.Join(actions, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected actions %v, got %v", want, actions)
+	}
+	steal := entries[3]
+	if steal.Repo != core.AuditRepo(repo) || steal.Details["previous_owner"] != "gone-owner" {
+		t.Fatalf("expected the steal to be filed under the main repo with the previous owner, got %+v", steal)
+	}
+	if entries[1].Branch != "feature" || entries[5].Branch != "feature" {
+		t.Fatalf("expected create and delete to name the branch, got %+v / %+v", entries[1], entries[5])
+	}
+
+	var out bytes.Buffer
+	if err := runHistory(&out, core.AuditFilter{Repo: core.AuditRepo(repo), Action: core.AuditCreate}, 0, false); err != nil {
+		t.Fatalf("runHistory: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(out.String()), "\n"); len(lines) != 2 || !strings.Contains(lines[1], filepath.Base(wt.Path)) || !strings.Contains(lines[1], "feature") {
+		t.Fatalf("expected a header and the create entry, got:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := runHistory(&out, core.AuditFilter{Repo: core.AuditRepo(repo)}, 2, true); err != nil {
+		t.Fatalf("runHistory json: %v", err)
+	}
+	var latest []core.AuditEntry
+	if err := json.Unmarshal(out.Bytes(), &latest); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(latest) != 2 || latest[1].Action != core.AuditDelete {
+		t.Fatalf("expected the two latest entries, got %+v", latest)
+	}
+}
+
+func TestRunHistoryWithoutLog(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	var out bytes.Buffer
+	if err := runHistory(&out, core.AuditFilter{}, 0, true); err != nil {
+		t.Fatalf("runHistory: %v", err)
+	}
+	if strings.TrimSpace(out.String()) != "[]" {
+		t.Fatalf("expected an empty JSON list, got %q", out.String())
+	}
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/aixolotls/wtx/internal/core"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	defaultFetchIntervalMinutes = 15
+	backgroundFetchTick         = time.Minute
+)
+
+type backgroundFetchedMsg struct {
+	fetched bool
+	err     error
+}
+
+// backgroundFetchInterval is how often the repo is fetched while wtx runs;
+// ok is false when background_fetch is off.
+func backgroundFetchInterval(cfg core.Config) (time.Duration, bool) {
+	if !cfg.BackgroundFetch {
+		return 0, false
+	}
+	minutes := cfg.FetchIntervalMinutes
+	if minutes <= 0 {
+		minutes = defaultFetchIntervalMinutes
+	}
+	return time.Duration(minutes) * time.Minute, true
+}
+
+func backgroundFetchTickCmd() tea.Cmd {
+	return tea.Tick(backgroundFetchTick, func(time.Time) tea.Msg {
+		return backgroundFetchTickMsg{}
+	})
+}
+
+type backgroundFetchTickMsg struct{}
+
+// backgroundFetchCmd reads the config on every tick, so turning
+// background_fetch on or off applies without restarting wtx.
+func backgroundFetchCmd(mgr *core.WorktreeManager) tea.Cmd {
+	return func() tea.Msg {
+		cfg, err := core.LoadConfig()
+		if err != nil || mgr == nil {
+			return backgroundFetchedMsg{}
+		}
+		interval, ok := backgroundFetchInterval(cfg)
+		if !ok {
+			return backgroundFetchedMsg{}
+		}
+		fetched, err := mgr.BackgroundFetch(interval)
+		if err != nil {
+			core.DebugLog("background fetch failed", "err", err.Error())
+		}
+		return backgroundFetchedMsg{fetched: fetched, err: err}
+	}
+}
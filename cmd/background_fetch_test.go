@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+func TestBackgroundFetchPrunesOncePerInterval(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	repo := initRenameTestRepo(t)
+	remote := filepath.Join(home, "remote.git")
+	runGitInRepo(t, repo, "init", "--bare", remote)
+	runGitInRepo(t, repo, "remote", "add", "origin", remote)
+	runGitInRepo(t, repo, "push", "origin", "HEAD:refs/heads/main", "HEAD:refs/heads/gone")
+	runGitInRepo(t, repo, "fetch", "origin")
+	runGitInRepo(t, remote, "branch", "-D", "gone")
+
+	mgr := core.NewWorktreeManager(repo, core.NewLockManager())
+	fetched, err := mgr.BackgroundFetch(time.Hour)
+	if err != nil || !fetched {
+		t.Fatalf("expected a fetch, got fetched=%v err=%v", fetched, err)
+	}
+	if err := exec.Command("git", "-C", repo, "rev-parse", "--verify", "refs/remotes/origin/gone").Run(); err == nil {
+		t.Fatalf("expected origin/gone pruned")
+	}
+	if fetched, err := mgr.BackgroundFetch(time.Hour); err != nil || fetched {
+		t.Fatalf("expected the second fetch inside the interval to be skipped, got fetched=%v err=%v", fetched, err)
+	}
+
+	if err := runConfigSet(false, "background_fetch", "true"); err != nil {
+		t.Fatalf("set background_fetch: %v", err)
+	}
+	runGitInRepo(t, repo, "commit", "--allow-empty", "-m", "newer")
+	// Pushing by path leaves origin/main behind until something fetches.
+	runGitInRepo(t, repo, "push", remote, "HEAD:refs/heads/main")
+	if err := mgr.FetchRepoBaseRef("origin/main", nil); err != nil {
+		t.Fatalf("fetch base ref: %v", err)
+	}
+	revParse := func(ref string) string {
+		out, err := exec.Command("git", "-C", repo, "rev-parse", ref).Output()
+		if err != nil {
+			t.Fatalf("rev-parse %s: %v", ref, err)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	if got, want := revParse("origin/main"), revParse("HEAD"); got != want {
+		t.Fatalf("expected an explicit fetch despite the recent background fetch, origin/main=%s want %s", got, want)
+	}
+}
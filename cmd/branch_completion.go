@@ -3,6 +3,8 @@ package cmd
 import (
 	"sort"
 	"strings"
+
+	"github.com/aixolotls/wtx/internal/core"
 )
 
 const (
@@ -17,10 +19,10 @@ func branchExistsLocalOrRemote(repoRoot string, gitPath string, branch string) (
 	if branch == "" {
 		return false, nil
 	}
-	if localBranchExists(repoRoot, gitPath, branch) {
+	if core.LocalBranchExists(repoRoot, gitPath, branch) {
 		return true, nil
 	}
-	if _, err := gitOutputInDir(repoRoot, gitPath, "show-ref", "--verify", "refs/remotes/"+branch); err == nil {
+	if _, err := core.GitOutputInDir(repoRoot, gitPath, "show-ref", "--verify", "refs/remotes/"+branch); err == nil {
 		return true, nil
 	}
 	remotes, err := listRemoteTrackingBranchNames(repoRoot, gitPath, 0)
@@ -36,7 +38,7 @@ func branchExistsLocalOrRemote(repoRoot string, gitPath string, branch string) (
 }
 
 func completeBranchSuggestions(toComplete string) []string {
-	gitPath, repoRoot, err := requireGitContext("")
+	gitPath, repoRoot, err := core.RequireGitContext("")
 	if err != nil {
 		return []string{}
 	}
@@ -59,7 +61,7 @@ func completeBranchSuggestions(toComplete string) []string {
 		}
 	}
 
-	if recent, err := readRecentBranches(repoRoot, completionTier0Limit); err == nil {
+	if recent, err := core.ReadRecentBranches(repoRoot, completionTier0Limit); err == nil {
 		appendMatching(recent)
 	}
 
@@ -90,7 +92,7 @@ func listLocalBranchNames(repoRoot string, gitPath string, limit int) ([]string,
 	if limit > 0 {
 		args = append(args, "--count", itoa(limit))
 	}
-	out, err := commandOutputInDir(repoRoot, gitPath, args...)
+	out, err := core.CommandOutputInDir(repoRoot, gitPath, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -107,7 +109,7 @@ func listRemoteTrackingBranchNames(repoRoot string, gitPath string, limit int) (
 	if limit > 0 {
 		args = append(args, "--count", itoa(limit))
 	}
-	out, err := commandOutputInDir(repoRoot, gitPath, args...)
+	out, err := core.CommandOutputInDir(repoRoot, gitPath, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -115,7 +117,7 @@ func listRemoteTrackingBranchNames(repoRoot string, gitPath string, limit int) (
 	branches := make([]string, 0, len(refs))
 	seen := map[string]bool{}
 	for _, ref := range refs {
-		name := shortBranch(ref)
+		name := core.ShortBranch(ref)
 		if name == "" || name == "detached" || strings.EqualFold(name, "head") || seen[name] {
 			continue
 		}
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/aixolotls/wtx/internal/core"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 )
@@ -20,6 +21,7 @@ func newCheckoutCommand() *cobra.Command {
 	var baseOverride string
 	var fetch bool
 	var noFetch bool
+	var prompt string
 
 	cmd := &cobra.Command{
 		Use:     "checkout <existing_branch>",
@@ -34,6 +36,7 @@ func newCheckoutCommand() *cobra.Command {
 			"  wtx co bugfix/login-timeout",
 			"  wtx checkout -b feature/new-api",
 			"  wtx checkout -b feature/new-api --from origin/main --fetch",
+			"  wtx checkout -b fix/flaky-test --prompt \"fix the flaky login test\"",
 		}, "\n"),
 		Args: func(cmd *cobra.Command, args []string) error {
 			if len(args) == 1 {
@@ -62,7 +65,12 @@ func newCheckoutCommand() *cobra.Command {
 				fetchOverride = &v
 			}
 
-			return runCheckout(args[0], create, baseOverride, fetchOverride, os.Args)
+			return runCheckout(args[0], checkoutOptions{
+				Create:        create,
+				BaseOverride:  baseOverride,
+				FetchOverride: fetchOverride,
+				Prompt:        prompt,
+			}, os.Args)
 		},
 	}
 
@@ -70,6 +78,7 @@ func newCheckoutCommand() *cobra.Command {
 	cmd.Flags().StringVar(&baseOverride, "from", "", "Base branch/ref for one-time branch creation (requires -b)")
 	cmd.Flags().BoolVar(&fetch, "fetch", false, "Fetch before one-time branch creation (requires -b)")
 	cmd.Flags().BoolVar(&noFetch, "no-fetch", false, "Do not fetch before one-time branch creation (requires -b)")
+	cmd.Flags().StringVar(&prompt, "prompt", "", "Initial task to send to the agent on launch")
 	cmd.ValidArgsFunction = checkoutBranchCompletion
 	_ = cmd.RegisterFlagCompletionFunc("from", checkoutFromCompletion)
 	return cmd
@@ -101,26 +110,42 @@ func checkoutFromCompletion(cmd *cobra.Command, _ []string, toComplete string) (
 	return completeBranchSuggestions(toComplete), cobra.ShellCompDirectiveNoFileComp
 }
 
-func runCheckout(branch string, create bool, baseOverride string, fetchOverride *bool, args []string) error {
+// checkoutOptions controls runCheckout. NonInteractive replaces the
+// create-worktree prompt with CreateWorktree and makes the agent launch fail
+// rather than show a picker.
+type checkoutOptions struct {
+	Create         bool
+	BaseOverride   string
+	FetchOverride  *bool
+	Prompt         string
+	Shell          bool
+	NonInteractive bool
+	CreateWorktree bool
+}
+
+func runCheckout(branch string, opts checkoutOptions, args []string) error {
+	create := opts.Create
+	baseOverride := opts.BaseOverride
+	fetchOverride := opts.FetchOverride
 	branch = strings.TrimSpace(branch)
 	if branch == "" {
 		return errors.New("branch name required")
 	}
 
-	exists, err := ConfigExists()
+	exists, err := core.ConfigExists()
 	if err != nil || !exists {
-		if err := ensureConfigReady(); err != nil {
+		if err := ensureConfigReadyIf(!opts.NonInteractive); err != nil {
 			return err
 		}
 	}
 
-	lockMgr := NewLockManager()
-	mgr := NewWorktreeManager("", lockMgr)
-	orchestrator := NewWorktreeOrchestrator(mgr, lockMgr, NewGHManager())
+	lockMgr := core.NewLockManager()
+	mgr := core.NewWorktreeManager("", lockMgr)
+	orchestrator := core.NewWorktreeOrchestrator(mgr, lockMgr, core.NewGHManager())
 	runner := NewRunner(lockMgr)
 
 	var (
-		status            WorktreeStatus
+		status            core.WorktreeStatus
 		gitPath, repoRoot string
 		baseRef           string
 		doFetch           bool
@@ -131,13 +156,13 @@ func runCheckout(branch string, create bool, baseOverride string, fetchOverride
 			return status.Err
 		}
 		if !status.GitInstalled {
-			return errGitNotInstalled
+			return core.ErrGitNotInstalled
 		}
 		if !status.InRepo {
-			return errNotInGitRepository
+			return core.ErrNotInGitRepository
 		}
 		var err error
-		gitPath, repoRoot, err = requireGitContext("")
+		gitPath, repoRoot, err = core.RequireGitContext("")
 		if err != nil {
 			return err
 		}
@@ -177,7 +202,7 @@ func runCheckout(branch string, create bool, baseOverride string, fetchOverride
 	}
 	setStartupStatusBanner()
 
-	var slots []openSlotState
+	var slots []core.OpenSlotState
 	if err := runCheckoutStep("Preparing worktree", func() error {
 		var err error
 		slots, err = loadOpenSlotsForCheckout(orchestrator, status)
@@ -204,10 +229,17 @@ func runCheckout(branch string, create bool, baseOverride string, fetchOverride
 			return err
 		}
 	} else {
-		fmt.Fprintln(os.Stderr, "No worktree is available for this target branch.")
-		createNew, err := promptCreateWorktree(branch)
-		if err != nil {
-			return err
+		if opts.NonInteractive && !opts.CreateWorktree {
+			return fmt.Errorf("no worktree is available for %s; pass --create to add one", branch)
+		}
+		createNew := opts.CreateWorktree
+		if !opts.NonInteractive {
+			fmt.Fprintln(os.Stderr, "No worktree is available for this target branch.")
+			var err error
+			createNew, err = promptCreateWorktree(branch)
+			if err != nil {
+				return err
+			}
 		}
 		if !createNew {
 			return nil
@@ -227,6 +259,9 @@ func runCheckout(branch string, create bool, baseOverride string, fetchOverride
 	if strings.TrimSpace(openResult.path) == "" {
 		return errors.New("checkout did not resolve a worktree")
 	}
+	if openResult.warning != "" {
+		fmt.Fprintln(os.Stderr, "wtx warning:", openResult.warning)
+	}
 
 	shouldResetTabColor := true
 	defer func() {
@@ -236,8 +271,19 @@ func runCheckout(branch string, create bool, baseOverride string, fetchOverride
 	}()
 
 	shouldResetTabColor = false
-	if err := runCheckoutStep("Launching agent", func() error {
-		_, err := runner.RunInWorktree(openResult.path, openResult.branch, openResult.lock)
+	launchStep := "Launching agent"
+	if opts.Shell {
+		launchStep = "Launching shell"
+	}
+	if err := runCheckoutStep(launchStep, func() error {
+		if opts.Shell {
+			_, err := runner.RunShellInWorktree(openResult.path, openResult.branch, openResult.lock)
+			return err
+		}
+		_, err := runner.RunInWorktreeWithOptions(openResult.path, openResult.branch, openResult.lock, AgentRunOptions{
+			Prompt:         opts.Prompt,
+			NonInteractive: opts.NonInteractive,
+		})
 		return err
 	}); err != nil {
 		if openResult.lock != nil {
@@ -248,10 +294,10 @@ func runCheckout(branch string, create bool, baseOverride string, fetchOverride
 	return nil
 }
 
-func checkoutDefaults(status WorktreeStatus) (string, bool) {
+func checkoutDefaults(status core.WorktreeStatus) (string, bool) {
 	base := resolveNewBranchBaseRef("", status.BaseRef, status.HasRemote)
 	fetch := true
-	if cfg, err := LoadConfig(); err == nil {
+	if cfg, err := core.LoadConfigForDir(status.CWD); err == nil {
 		if status.HasRemote {
 			if v := strings.TrimSpace(cfg.NewBranchBaseRef); v != "" {
 				base = v
@@ -277,13 +323,13 @@ func runCheckoutStep(step string, fn func() error) error {
 	return nil
 }
 
-func loadOpenSlotsForCheckout(orchestrator *WorktreeOrchestrator, status WorktreeStatus) ([]openSlotState, error) {
+func loadOpenSlotsForCheckout(orchestrator *core.WorktreeOrchestrator, status core.WorktreeStatus) ([]core.OpenSlotState, error) {
 	if orchestrator == nil {
-		return []openSlotState{}, nil
+		return []core.OpenSlotState{}, nil
 	}
-	slots := make([]openSlotState, len(status.Worktrees))
+	slots := make([]core.OpenSlotState, len(status.Worktrees))
 	for i, wt := range status.Worktrees {
-		slot := openSlotState{
+		slot := core.OpenSlotState{
 			Path:   wt.Path,
 			Branch: wt.Branch,
 			Locked: !wt.Available,
@@ -316,7 +362,11 @@ func promptCreateWorktree(branch string) (bool, error) {
 	if branch == "" {
 		branch = "branch"
 	}
-	fmt.Fprintf(os.Stderr, "Create a new worktree for %s? [y/N]: ", branch)
+	return promptYesNo(fmt.Sprintf("Create a new worktree for %s?", branch))
+}
+
+func promptYesNo(question string) (bool, error) {
+	fmt.Fprintf(os.Stderr, "%s [y/N]: ", question)
 	reader := bufio.NewReader(os.Stdin)
 	line, err := reader.ReadString('\n')
 	if err != nil && !errors.Is(err, io.EOF) {
@@ -331,12 +381,12 @@ func validateCreateCheckoutBaseRef(repoRoot string, gitPath string, baseRef stri
 	if baseRef == "" {
 		baseRef = "HEAD"
 	}
-	resolved := baseRefForWorktreeAdd(repoRoot, gitPath, baseRef)
-	if _, err := gitOutputInDir(repoRoot, gitPath, "rev-parse", "--verify", resolved+"^{commit}"); err == nil {
+	resolved := core.BaseRefForWorktreeAdd(repoRoot, gitPath, baseRef)
+	if _, err := core.GitOutputInDir(repoRoot, gitPath, "rev-parse", "--verify", resolved+"^{commit}"); err == nil {
 		return nil
 	}
 
-	remotes, remoteErr := listGitRemotes(repoRoot, gitPath)
+	remotes, remoteErr := core.ListGitRemotes(repoRoot, gitPath)
 	if remoteErr == nil && len(remotes) == 0 {
 		return fmt.Errorf("base ref %q is not valid in this repository and no remotes are configured; use --from <local-branch> or update defaults in `wtx config`", baseRef)
 	}
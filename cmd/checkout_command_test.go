@@ -3,6 +3,8 @@ package cmd
 import (
 	"strings"
 	"testing"
+
+	"github.com/aixolotls/wtx/internal/core"
 )
 
 func TestCheckoutRejectsOverrideFlagsWithoutCreate(t *testing.T) {
@@ -37,16 +39,16 @@ func TestCheckoutDefaults_UseConfigValues(t *testing.T) {
 	t.Setenv("HOME", home)
 
 	fetch := false
-	if err := SaveConfig(Config{
-		AgentCommand:          defaultAgentCommand,
+	if err := core.SaveConfig(core.Config{
+		AgentCommand:          core.DefaultAgentCommand,
 		NewBranchBaseRef:      "origin/develop",
 		NewBranchFetchFirst:   &fetch,
-		MainScreenBranchLimit: defaultMainScreenBranchLimit,
+		MainScreenBranchLimit: core.DefaultMainScreenBranchLimit,
 	}); err != nil {
 		t.Fatalf("save config: %v", err)
 	}
 
-	base, doFetch := checkoutDefaults(WorktreeStatus{BaseRef: "origin/main", HasRemote: true})
+	base, doFetch := checkoutDefaults(core.WorktreeStatus{BaseRef: "origin/main", HasRemote: true})
 	if base != "origin/develop" {
 		t.Fatalf("expected config base ref, got %q", base)
 	}
@@ -60,16 +62,16 @@ func TestCheckoutDefaults_NoRemoteAlwaysUsesMain(t *testing.T) {
 	t.Setenv("HOME", home)
 
 	fetch := true
-	if err := SaveConfig(Config{
-		AgentCommand:          defaultAgentCommand,
+	if err := core.SaveConfig(core.Config{
+		AgentCommand:          core.DefaultAgentCommand,
 		NewBranchBaseRef:      "origin/develop",
 		NewBranchFetchFirst:   &fetch,
-		MainScreenBranchLimit: defaultMainScreenBranchLimit,
+		MainScreenBranchLimit: core.DefaultMainScreenBranchLimit,
 	}); err != nil {
 		t.Fatalf("save config: %v", err)
 	}
 
-	base, doFetch := checkoutDefaults(WorktreeStatus{BaseRef: "feature/local-only", HasRemote: false})
+	base, doFetch := checkoutDefaults(core.WorktreeStatus{BaseRef: "feature/local-only", HasRemote: false})
 	if base != "main" {
 		t.Fatalf("expected main for no-remote repo, got %q", base)
 	}
@@ -3,11 +3,13 @@ package cmd
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"strings"
 
+	"github.com/aixolotls/wtx/internal/core"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 )
@@ -18,34 +20,71 @@ var initializeConfigFn = initializeConfig
 
 func newRootCommand(args []string) *cobra.Command {
 	var showVersion bool
+	var asJSON bool
+	var repoPath string
+	var verbose bool
 	root := &cobra.Command{
 		Use:           "wtx",
 		Short:         "Interactive Git worktree picker",
 		SilenceUsage:  true,
 		SilenceErrors: true,
+		PersistentPreRunE: func(_ *cobra.Command, _ []string) error {
+			if verbose || core.DebugEnvEnabled() {
+				if err := core.EnableDebugLog(); err != nil {
+					fmt.Fprintln(os.Stderr, "wtx warning: debug log unavailable:", err)
+				}
+			}
+			if err := core.PinRepo(repoPath); err != nil {
+				return err
+			}
+			loadConfiguredTheme()
+			return nil
+		},
 		RunE: func(_ *cobra.Command, _ []string) error {
 			if showVersion {
 				return runVersionCommand()
 			}
-			return runDefault(args)
+			return runDefault(args, asJSON)
 		},
 	}
 	root.Flags().BoolVarP(&showVersion, "version", "v", false, "Print wtx version and exit")
+	root.Flags().BoolVar(&asJSON, "json", false, "Print worktrees as JSON instead of starting the interactive UI")
+	root.PersistentFlags().StringVar(&repoPath, "repo", "", "Act on the git repo at this path instead of the current directory")
+	root.PersistentFlags().BoolVar(&verbose, "verbose", false, "Log external commands and lock decisions to ~/.wtx/logs/wtx.log (same as WTX_DEBUG=1)")
 
 	root.AddCommand(
 		newCheckoutCommand(),
+		newOpenCommand(),
+		newRmCommand(),
+		newExecCommand(),
 		newPRCommand(),
 		newConfigCommand(),
 		newCompletionCommand(),
 		newUpdateCommand(),
+		newResumeCommand(),
+		newRunCommand(),
+		newListCommand(),
 		newTmuxStatusCommand(),
 		newTmuxTitleCommand(),
 		newTmuxAgentStartCommand(),
 		newTmuxAgentExitCommand(),
+		newJobRunCommand(),
 		newTmuxActionsCommand(),
 		newShellCommand(),
 		newIDECommand(),
 		newIDEPickerCommand(),
+		newDaemonCommand(),
+		newServeCommand(),
+		newMCPCommand(),
+		newPromptCommand(),
+		newPickCommand(),
+		newInstallGitAliasCommand(),
+		newActionsCommand(),
+		newRepairCommand(),
+		newDebugCommand(),
+		newHistoryCommand(),
+		newLogsCommand(),
+		newGCCommand(),
 	)
 
 	if len(args) > 1 {
@@ -55,7 +94,7 @@ func newRootCommand(args []string) *cobra.Command {
 }
 
 func newConfigCommand() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "config",
 		Short: "Open interactive configuration",
 		Args:  cobra.NoArgs,
@@ -63,24 +102,86 @@ func newConfigCommand() *cobra.Command {
 			return launchConfigUIFn()
 		},
 	}
+	cmd.AddCommand(
+		newConfigGetCommand(),
+		newConfigSetCommand(),
+		newConfigUnsetCommand(),
+		newConfigListCommand(),
+		newConfigMigrateCommand(),
+		newConfigTrustCommand(),
+	)
+	return cmd
 }
 
 func newUpdateCommand() *cobra.Command {
 	var checkOnly bool
 	var quiet bool
+	var rollback bool
+	var skipVerify bool
 	cmd := &cobra.Command{
 		Use:   "update",
 		Short: "Check for and install the latest wtx version",
 		Args:  cobra.NoArgs,
-		RunE: func(_ *cobra.Command, _ []string) error {
-			return runUpdateCommand(checkOnly, quiet)
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if rollback {
+				if checkOnly {
+					return usageError(cmd, "--rollback cannot be combined with --check")
+				}
+				return runUpdateRollback(quiet)
+			}
+			return runUpdateCommand(checkOnly, quiet, skipVerify)
 		},
 	}
 	cmd.Flags().BoolVar(&checkOnly, "check", false, "Check for updates only")
+	cmd.Flags().BoolVar(&rollback, "rollback", false, "Restore the version replaced by the last update")
+	cmd.Flags().BoolVar(&skipVerify, "insecure-skip-verify", false, "Install without verifying the release signature")
 	cmd.Flags().BoolVar(&quiet, "quiet", false, "Print machine-friendly output")
 	return cmd
 }
 
+func newDaemonCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "daemon",
+		Short: "Serve status and PR data to other wtx processes over ~/.wtx/daemon.sock",
+		Long: "Keeps worktree status and PR data warm for the picker, the tmux status line and commands like wtx list.\n\n" +
+			"Locks and actions are not served: each wtx process still takes its own locks and runs its own actions.",
+		Args: cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return core.RunDaemonCommand()
+		},
+	}
+}
+
+func newServeCommand() *cobra.Command {
+	var socket string
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve list, create, delete, lock, and open over JSON-RPC for editor integrations",
+		Long: "Listens on a unix socket for newline-delimited JSON-RPC 2.0 requests.\n\n" +
+			"Methods: list, create, delete, lock, unlock, open. Locks taken through\n" +
+			"lock and open are held until unlock is called or the server exits.",
+		Args: cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runServeCommand(socket)
+		},
+	}
+	cmd.Flags().StringVar(&socket, "socket", "", "Socket path (default ~/.wtx/serve.sock)")
+	return cmd
+}
+
+func newMCPCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "mcp",
+		Short: "Run a Model Context Protocol server on stdio for agents in a worktree",
+		Long: "Exposes list_worktrees, create_worktree, pr_status, and release_lock as MCP tools.\n\n" +
+			"Register it with your agent, for example: claude mcp add wtx -- wtx mcp",
+		Args: cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runMCPCommand()
+		},
+	}
+}
+
 func newTmuxStatusCommand() *cobra.Command {
 	var worktree string
 	cmd := &cobra.Command{
@@ -113,16 +214,18 @@ func newTmuxTitleCommand() *cobra.Command {
 
 func newTmuxAgentStartCommand() *cobra.Command {
 	var worktree string
+	var agent string
 	cmd := &cobra.Command{
 		Use:    "tmux-agent-start",
 		Short:  "Mark tmux agent as running",
 		Args:   cobra.NoArgs,
 		Hidden: true,
 		RunE: func(_ *cobra.Command, _ []string) error {
-			return runTmuxAgentStart([]string{"--worktree", worktree})
+			return runTmuxAgentStart([]string{"--worktree", worktree, "--agent", agent})
 		},
 	}
 	cmd.Flags().StringVar(&worktree, "worktree", "", "Worktree path")
+	cmd.Flags().StringVar(&agent, "agent", "", "Agent command being started")
 	return cmd
 }
 
@@ -165,6 +268,19 @@ func newTmuxActionsCommand() *cobra.Command {
 	return cmd
 }
 
+func newActionsCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "actions [path]",
+		Short: "Open the action palette (rename branch, open PR, IDE, shell)",
+		Long: "Opens the same palette as the tmux popup. Outside tmux it runs full screen in the\n" +
+			"current terminal for the worktree containing [path] or the current directory.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runTmuxActions(args)
+		},
+	}
+}
+
 func newShellCommand() *cobra.Command {
 	return &cobra.Command{
 		Use:   "shell",
@@ -198,11 +314,15 @@ func newIDEPickerCommand() *cobra.Command {
 	}
 }
 
-func runDefault(args []string) error {
-	if testModeEnabled() {
+func runDefault(args []string, asJSON bool) error {
+	if core.TestModeEnabled() {
 		fmt.Println("wtx test mode: interactive UI bypassed")
 		return nil
 	}
+	if asJSON || !isInteractiveTerminal(os.Stdout) {
+		// Piped or scripted: print the worktree table instead of starting the TUI.
+		return runList(os.Stdout, asJSON, true)
+	}
 	if err := ensureConfigReady(); err != nil {
 		return err
 	}
@@ -225,16 +345,21 @@ func runDefault(args []string) error {
 		}
 	}()
 
-	p := tea.NewProgram(newModel(), tea.WithMouseCellMotion())
-	finalModel, err := p.Run()
+	finalModel, err := runProgram(newModel(), tea.WithMouseCellMotion())
 	if err != nil {
 		return err
 	}
 	if m, ok := finalModel.(model); ok {
+		for _, warning := range m.FlushPendingDeletes() {
+			fmt.Fprintln(os.Stderr, "wtx warning:", warning)
+		}
 		path, branch, openShell, lock := m.PendingWorktree()
+		if warning := m.PendingWarning(); warning != "" {
+			fmt.Fprintln(os.Stderr, "wtx warning:", warning)
+		}
 		if strings.TrimSpace(path) != "" {
 			shouldResetTabColor = false
-			runner := NewRunner(NewLockManager())
+			runner := NewRunner(core.NewLockManager())
 			if openShell {
 				if _, err := runner.RunShellInWorktree(path, branch, lock); err != nil {
 					if lock != nil {
@@ -256,7 +381,7 @@ func runDefault(args []string) error {
 }
 
 func ensureConfigReady() error {
-	exists, err := ConfigExists()
+	exists, err := core.ConfigExists()
 	if err != nil {
 		return err
 	}
@@ -266,27 +391,45 @@ func ensureConfigReady() error {
 	return initializeConfigFn()
 }
 
+// ensureConfigReadyIf is ensureConfigReady for callers that may not open the
+// config form; without interactive a missing config is an error.
+func ensureConfigReadyIf(interactive bool) error {
+	if interactive {
+		return ensureConfigReady()
+	}
+	exists, err := core.ConfigExists()
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errors.New("wtx is not configured; run `wtx config` in an interactive terminal")
+	}
+	return nil
+}
+
 func launchConfigUI() error {
-	if testModeEnabled() {
+	if core.TestModeEnabled() {
 		return initializeConfig()
 	}
-	p := tea.NewProgram(newConfigModel(), tea.WithMouseCellMotion())
-	return p.Start()
+	return runConfigForm()
 }
 
 func initializeConfig() error {
 	fetch := true
-	return SaveConfig(Config{
+	return core.SaveConfig(core.Config{
 		AgentCommand:          "",
 		NewBranchFetchFirst:   &fetch,
 		IDECommand:            "",
-		MainScreenBranchLimit: defaultMainScreenBranchLimit,
+		MainScreenBranchLimit: core.DefaultMainScreenBranchLimit,
 	})
 }
 
 func runVersionCommand() error {
-	cur := currentVersion()
+	cur := core.CurrentVersion()
 	fmt.Println(cur)
+	if !updateChecksEnabled() {
+		return nil
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), resolveUpdateTimeout)
 	defer cancel()
@@ -300,11 +443,13 @@ func runVersionCommand() error {
 		CurrentVersion:  cur,
 		LatestVersion:   latest,
 		UpdateAvailable: isUpdateAvailableForInstall(cur, latest),
+		Channel:         configuredUpdateChannel(),
 	}
 	printUpdateCheckResultTo(os.Stderr, result, false)
 	if !result.UpdateAvailable || !isInteractiveTerminal(os.Stdin) || !isInteractiveTerminal(os.Stdout) {
 		return nil
 	}
+	printReleaseNotes(os.Stdout, result.LatestVersion)
 	return promptAndMaybeInstallVersionUpdate(os.Stdin, os.Stdout, result)
 }
 
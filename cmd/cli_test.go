@@ -7,6 +7,8 @@ import (
 	"os"
 	"strings"
 	"testing"
+
+	"github.com/aixolotls/wtx/internal/core"
 )
 
 func TestRunVersionFlag(t *testing.T) {
@@ -42,7 +44,7 @@ func TestRunVersionFlag(t *testing.T) {
 	}
 
 	got := strings.TrimSpace(out.String())
-	want := currentVersion()
+	want := core.CurrentVersion()
 	if got != want {
 		t.Fatalf("expected %q, got %q", want, got)
 	}
@@ -81,7 +83,7 @@ func TestRunVersionFlagAlias(t *testing.T) {
 	}
 
 	got := strings.TrimSpace(out.String())
-	want := currentVersion()
+	want := core.CurrentVersion()
 	if got != want {
 		t.Fatalf("expected %q, got %q", want, got)
 	}
@@ -172,11 +174,11 @@ func TestEnsureConfigReady_ConfigExistsSkipsInit(t *testing.T) {
 	t.Setenv("HOME", home)
 
 	fetch := true
-	if err := SaveConfig(Config{
+	if err := core.SaveConfig(core.Config{
 		AgentCommand:          "",
 		NewBranchFetchFirst:   &fetch,
 		IDECommand:            "",
-		MainScreenBranchLimit: defaultMainScreenBranchLimit,
+		MainScreenBranchLimit: core.DefaultMainScreenBranchLimit,
 	}); err != nil {
 		t.Fatalf("save config: %v", err)
 	}
@@ -208,11 +210,11 @@ func TestEnsureConfigReady_InitializesWhenMissing(t *testing.T) {
 	initializeConfigFn = func() error {
 		called = true
 		fetch := true
-		return SaveConfig(Config{
+		return core.SaveConfig(core.Config{
 			AgentCommand:          "",
 			NewBranchFetchFirst:   &fetch,
 			IDECommand:            "",
-			MainScreenBranchLimit: defaultMainScreenBranchLimit,
+			MainScreenBranchLimit: core.DefaultMainScreenBranchLimit,
 		})
 	}
 	t.Cleanup(func() {
@@ -226,3 +228,10 @@ func TestEnsureConfigReady_InitializesWhenMissing(t *testing.T) {
 		t.Fatalf("expected config init to run")
 	}
 }
+
+func TestTmuxAgentStartAcceptsAgentFlag(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if err := Run([]string{"wtx", "tmux-agent-start", "--agent", "claude"}); err != nil {
+		t.Fatalf("tmux-agent-start --agent: %v", err)
+	}
+}
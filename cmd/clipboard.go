@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aixolotls/wtx/internal/core"
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/termenv"
+)
+
+// What the copy actions put on the clipboard.
+const (
+	copyPath   = "path"
+	copyBranch = "branch"
+	copyPRURL  = "PR URL"
+)
+
+// copyToClipboard uses the system clipboard tool (pbcopy, xclip, xsel or
+// wl-copy), and falls back to an OSC 52 escape for terminals on remote hosts
+// where none is installed.
+func copyToClipboard(text string) error {
+	if err := clipboard.WriteAll(text); err == nil {
+		return nil
+	}
+	termenv.Copy(text)
+	return nil
+}
+
+func worktreeCopyValue(row core.WorktreeInfo, what string) string {
+	switch what {
+	case copyPath:
+		return row.Path
+	case copyBranch:
+		return row.Branch
+	case copyPRURL:
+		return strings.TrimSpace(row.PRURL)
+	default:
+		return ""
+	}
+}
+
+// copySelected puts the selected worktree's path, branch or PR URL on the
+// clipboard.
+func (m model) copySelected(what string) (tea.Model, tea.Cmd) {
+	row, ok := selectedWorktree(m.listStatus(), m.listIndex)
+	if !ok {
+		return m, nil
+	}
+	text := worktreeCopyValue(row, what)
+	if text == "" {
+		m.errMsg = fmt.Sprintf("No %s for selected worktree.", what)
+		return m, nil
+	}
+	if err := m.runner.CopyToClipboard(text); err != nil {
+		return m.setError(err), nil
+	}
+	m.errMsg = ""
+	m.warnMsg = fmt.Sprintf("Copied %s: %s", what, text)
+	return m, nil
+}
+
+// currentPRURL looks up the URL of the PR for the branch checked out at path,
+// the way the tmux status line does.
+func currentPRURL(path string) string {
+	branch := core.CurrentBranchInWorktree(path)
+	repoRoot, err := core.RepoRootForDir(path, "")
+	if branch == "" || err != nil {
+		return ""
+	}
+	data, err := core.LookupPRData(core.NewGHManager(), repoRoot, []string{branch}, false)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(data[branch].URL)
+}
+
+// copyFromPopup copies for the popup and confirms in the tmux message line.
+func copyFromPopup(basePath string, what string) error {
+	var text string
+	switch what {
+	case copyPath:
+		text = basePath
+	case copyBranch:
+		text = core.CurrentBranchInWorktree(basePath)
+	case copyPRURL:
+		text = currentPRURL(basePath)
+	}
+	if text == "" {
+		return fmt.Errorf("no %s for this worktree", what)
+	}
+	if err := copyToClipboard(text); err != nil {
+		return err
+	}
+	if !showTmuxActionErrorMessage(fmt.Sprintf("Copied %s: %s", what, text)) {
+		fmt.Printf("Copied %s: %s\n", what, text)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/aixolotls/wtx/internal/core"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestCopyKeysPickTheSelectedWorktreeValue(t *testing.T) {
+	row := core.WorktreeInfo{Path: "/repo/wt.1", Branch: "feature", Available: true, PRURL: " https://github.com/o/r/pull/3 "}
+	for what, want := range map[string]string{copyPath: "/repo/wt.1", copyBranch: "feature", copyPRURL: "https://github.com/o/r/pull/3"} {
+		if got := worktreeCopyValue(row, what); got != want {
+			t.Fatalf("%s = %q, want %q", what, got, want)
+		}
+	}
+
+	m := newModel()
+	m.mode = modeList
+	m.ready = true
+	m.status = core.WorktreeStatus{InRepo: true, Worktrees: []core.WorktreeInfo{{Path: "/repo/wt.1", Branch: "feature", Available: true}}}
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("U")})
+	if got := next.(model).errMsg; got != "No PR URL for selected worktree." {
+		t.Fatalf("errMsg = %q", got)
+	}
+	if action := parseTmuxAction("copy_branch"); action != tmuxActionCopyBranch {
+		t.Fatalf("parseTmuxAction = %q", action)
+	}
+}
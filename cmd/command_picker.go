@@ -7,12 +7,14 @@ import (
 	"os/exec"
 	"strings"
 
+	"github.com/aixolotls/wtx/internal/core"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 var lookPathFn = exec.LookPath
 var isInteractiveTerminalFn = isInteractiveTerminal
+var promptCommandSelectionFn = promptCommandSelection
 
 type commandPickerType string
 
@@ -24,13 +26,20 @@ const (
 	commandPickerIDE   commandPickerType = "ide"
 )
 
+var errAgentCommandNotConfigured = errors.New("agent command not configured; run `wtx config` in an interactive terminal")
+
 var agentCandidates = []string{"claude", "codex", "gemini", "opencode"}
-var ideCandidates = []string{"code", "cursor", "sublime", "atom"}
+var ideCandidates = []string{"code", "cursor", "sublime", "atom", "idea", "goland", "pycharm", "webstorm"}
 
-func ensureAgentCommandConfigured(cfg Config) (Config, string, error) {
+// ensureAgentCommandConfigured returns the agent command, asking for one when
+// it is unset and interactive allows a picker.
+func ensureAgentCommandConfigured(cfg core.Config, interactive bool) (core.Config, string, error) {
 	if v := strings.TrimSpace(cfg.AgentCommand); v != "" {
 		return cfg, v, nil
 	}
+	if !interactive {
+		return cfg, "", errAgentCommandNotConfigured
+	}
 	selected, err := chooseAndSaveCommand(cfg, commandPickerAgent)
 	if err != nil {
 		return cfg, "", err
@@ -38,7 +47,54 @@ func ensureAgentCommandConfigured(cfg Config) (Config, string, error) {
 	return selected, selected.AgentCommand, nil
 }
 
-func ensureIDECommandConfigured(cfg Config) (Config, string, error) {
+// selectAgentProfile picks the profile to launch: the configured default, the
+// only profile, or an interactive choice when interactive allows one. ok is
+// false when no profiles exist.
+func selectAgentProfile(cfg core.Config, interactive bool) (core.AgentProfile, bool, error) {
+	profiles := core.NormalizeAgentProfiles(cfg.AgentProfiles)
+	if len(profiles) == 0 {
+		return core.AgentProfile{}, false, nil
+	}
+	if name := strings.TrimSpace(cfg.DefaultAgentProfile); name != "" {
+		if p, found := findAgentProfile(profiles, name); found {
+			return p, true, nil
+		}
+		return core.AgentProfile{}, false, fmt.Errorf("agent profile %q not found", name)
+	}
+	if len(profiles) == 1 {
+		return profiles[0], true, nil
+	}
+	if !interactive || !isInteractiveTerminalFn(os.Stdin) || !isInteractiveTerminalFn(os.Stdout) {
+		if strings.TrimSpace(cfg.AgentCommand) != "" {
+			return core.AgentProfile{}, false, nil
+		}
+		return profiles[0], true, nil
+	}
+	names := make([]string, 0, len(profiles))
+	for _, p := range profiles {
+		names = append(names, p.Name)
+	}
+	selected, err := promptCommandSelectionFn("Select agent profile", names, "agent command")
+	if err != nil {
+		return core.AgentProfile{}, false, err
+	}
+	if p, found := findAgentProfile(profiles, selected); found {
+		return p, true, nil
+	}
+	return core.AgentProfile{Name: "custom", Command: selected}, true, nil
+}
+
+func findAgentProfile(profiles []core.AgentProfile, name string) (core.AgentProfile, bool) {
+	name = strings.TrimSpace(name)
+	for _, p := range profiles {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return core.AgentProfile{}, false
+}
+
+func ensureIDECommandConfigured(cfg core.Config) (core.Config, string, error) {
 	if v := strings.TrimSpace(cfg.IDECommand); v != "" {
 		return cfg, v, nil
 	}
@@ -49,7 +105,7 @@ func ensureIDECommandConfigured(cfg Config) (Config, string, error) {
 	return selected, selected.IDECommand, nil
 }
 
-func chooseAndSaveCommand(cfg Config, pickerType commandPickerType) (Config, error) {
+func chooseAndSaveCommand(cfg core.Config, pickerType commandPickerType) (core.Config, error) {
 	var candidates []string
 	var title string
 	var placeholder string
@@ -70,7 +126,7 @@ func chooseAndSaveCommand(cfg Config, pickerType commandPickerType) (Config, err
 	if !isInteractiveTerminalFn(os.Stdin) || !isInteractiveTerminalFn(os.Stdout) {
 		switch pickerType {
 		case commandPickerAgent:
-			return cfg, errors.New("agent command not configured; run `wtx config` in an interactive terminal")
+			return cfg, errAgentCommandNotConfigured
 		case commandPickerIDE:
 			return cfg, errors.New("IDE command not configured; run `wtx config` in an interactive terminal")
 		default:
@@ -88,7 +144,7 @@ func chooseAndSaveCommand(cfg Config, pickerType commandPickerType) (Config, err
 	case commandPickerIDE:
 		cfg.IDECommand = selected
 	}
-	return cfg, SaveConfig(cfg)
+	return cfg, core.SaveConfig(cfg)
 }
 
 func detectInstalledCommands(candidates []string, lookPath func(file string) (string, error)) []string {
@@ -107,8 +163,7 @@ func detectInstalledCommands(candidates []string, lookPath func(file string) (st
 
 func promptCommandSelection(title string, detected []string, placeholder string) (string, error) {
 	m := newCommandPickerModel(title, detected, placeholder)
-	p := tea.NewProgram(m, tea.WithMouseCellMotion(), tea.WithAltScreen())
-	finalModel, err := p.Run()
+	finalModel, err := runProgram(m, tea.WithMouseCellMotion(), tea.WithAltScreen())
 	if err != nil {
 		return "", err
 	}
@@ -5,6 +5,8 @@ import (
 	"os"
 	"strings"
 	"testing"
+
+	"github.com/aixolotls/wtx/internal/core"
 )
 
 func TestDetectInstalledCommands_PreservesOrder(t *testing.T) {
@@ -53,7 +55,7 @@ func TestChooseAndSaveCommand_NonInteractiveRequiresConfig(t *testing.T) {
 		isInteractiveTerminalFn = oldInteractive
 	})
 
-	cfg, err := chooseAndSaveCommand(Config{}, commandPickerIDE)
+	cfg, err := chooseAndSaveCommand(core.Config{}, commandPickerIDE)
 	if err == nil {
 		t.Fatalf("expected error")
 	}
@@ -64,3 +66,114 @@ func TestChooseAndSaveCommand_NonInteractiveRequiresConfig(t *testing.T) {
 		t.Fatalf("expected IDE command to remain unset, got %q", cfg.IDECommand)
 	}
 }
+
+func TestSelectAgentProfile(t *testing.T) {
+	profiles := []core.AgentProfile{
+		{Name: "claude-opus", Command: "claude --model opus"},
+		{Name: "codex", Command: "codex"},
+		{Name: "shell-only"},
+	}
+
+	t.Run("no profiles", func(t *testing.T) {
+		if _, ok, err := selectAgentProfile(core.Config{AgentCommand: "claude"}, true); ok || err != nil {
+			t.Fatalf("expected no profile, got ok=%v err=%v", ok, err)
+		}
+	})
+
+	t.Run("default profile", func(t *testing.T) {
+		got, ok, err := selectAgentProfile(core.Config{AgentProfiles: profiles, DefaultAgentProfile: "codex"}, true)
+		if err != nil || !ok {
+			t.Fatalf("selectAgentProfile: ok=%v err=%v", ok, err)
+		}
+		if got.Command != "codex" {
+			t.Fatalf("expected codex profile, got %+v", got)
+		}
+	})
+
+	t.Run("unknown default", func(t *testing.T) {
+		if _, _, err := selectAgentProfile(core.Config{AgentProfiles: profiles, DefaultAgentProfile: "missing"}, true); err == nil {
+			t.Fatalf("expected unknown profile error")
+		}
+	})
+
+	t.Run("interactive picker", func(t *testing.T) {
+		oldInteractive := isInteractiveTerminalFn
+		oldPrompt := promptCommandSelectionFn
+		isInteractiveTerminalFn = func(_ *os.File) bool { return true }
+		promptCommandSelectionFn = func(_ string, options []string, _ string) (string, error) {
+			if len(options) != 3 {
+				t.Fatalf("expected 3 profile options, got %#v", options)
+			}
+			return "shell-only", nil
+		}
+		t.Cleanup(func() {
+			isInteractiveTerminalFn = oldInteractive
+			promptCommandSelectionFn = oldPrompt
+		})
+		got, ok, err := selectAgentProfile(core.Config{AgentProfiles: profiles}, true)
+		if err != nil || !ok {
+			t.Fatalf("selectAgentProfile: ok=%v err=%v", ok, err)
+		}
+		if got.Name != "shell-only" || got.Command != "" {
+			t.Fatalf("expected shell-only profile, got %+v", got)
+		}
+	})
+
+	t.Run("non-interactive falls back to agent command", func(t *testing.T) {
+		oldInteractive := isInteractiveTerminalFn
+		isInteractiveTerminalFn = func(_ *os.File) bool { return false }
+		t.Cleanup(func() {
+			isInteractiveTerminalFn = oldInteractive
+		})
+		if _, ok, err := selectAgentProfile(core.Config{AgentCommand: "claude", AgentProfiles: profiles}, true); ok || err != nil {
+			t.Fatalf("expected fallback to agent command, got ok=%v err=%v", ok, err)
+		}
+	})
+
+	t.Run("no picker when not interactive on a tty", func(t *testing.T) {
+		oldInteractive := isInteractiveTerminalFn
+		oldPrompt := promptCommandSelectionFn
+		isInteractiveTerminalFn = func(_ *os.File) bool { return true }
+		promptCommandSelectionFn = func(string, []string, string) (string, error) {
+			t.Fatalf("did not expect a profile picker")
+			return "", nil
+		}
+		t.Cleanup(func() {
+			isInteractiveTerminalFn = oldInteractive
+			promptCommandSelectionFn = oldPrompt
+		})
+		got, ok, err := selectAgentProfile(core.Config{AgentProfiles: profiles}, false)
+		if err != nil || !ok || got.Name != "claude-opus" {
+			t.Fatalf("expected the first profile, got %+v ok=%v err=%v", got, ok, err)
+		}
+	})
+}
+
+func TestEnsureAgentCommandConfiguredNonInteractive(t *testing.T) {
+	oldInteractive := isInteractiveTerminalFn
+	isInteractiveTerminalFn = func(_ *os.File) bool { return true }
+	t.Cleanup(func() {
+		isInteractiveTerminalFn = oldInteractive
+	})
+	if _, _, err := ensureAgentCommandConfigured(core.Config{}, false); !errors.Is(err, errAgentCommandNotConfigured) {
+		t.Fatalf("expected a not-configured error instead of a picker, got %v", err)
+	}
+	if _, got, err := ensureAgentCommandConfigured(core.Config{AgentCommand: "codex"}, false); err != nil || got != "codex" {
+		t.Fatalf("expected the configured agent, got %q err=%v", got, err)
+	}
+}
+
+func TestEnsureConfigReadyIfNonInteractive(t *testing.T) {
+	t.Setenv(core.ConfigDirOverrideEnv, t.TempDir())
+	oldInit := initializeConfigFn
+	initializeConfigFn = func() error {
+		t.Fatalf("did not expect the config form")
+		return nil
+	}
+	t.Cleanup(func() {
+		initializeConfigFn = oldInit
+	})
+	if err := ensureConfigReadyIf(false); err == nil {
+		t.Fatalf("expected a missing config to fail without prompting")
+	}
+}
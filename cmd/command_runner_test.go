@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+func TestRunExternalCommandStreamsToProgress(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("cmd.exe cannot print a bare carriage return")
+	}
+	log := newProgressLog()
+	out, err := core.RunExternalCommand(context.Background(), core.CommandSpec{
+		Name:     "/bin/sh",
+		Args:     []string{"-c", `printf 'one\n'; printf 'Receiving 10%%\rReceiving 100%%\n' >&2`},
+		Timeout:  5 * time.Second,
+		Progress: log,
+	})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if got := string(out); got != "one\nReceiving 10%\rReceiving 100%\n" {
+		t.Fatalf("expected combined output returned, got %q", got)
+	}
+	if got := strings.Join(log.Tail(10), "|"); got != "one|Receiving 100%" {
+		t.Fatalf("expected carriage return to overwrite the line, got %q", got)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/aixolotls/wtx/internal/core"
 	"github.com/spf13/cobra"
 )
 
@@ -169,9 +170,9 @@ func newCompletionStatusCommand() *cobra.Command {
 }
 
 func detectZshCompletionStatus() (zshCompletionStatus, error) {
-	home := strings.TrimSpace(os.Getenv("HOME"))
-	if home == "" {
-		return zshCompletionStatus{}, errors.New("HOME not set")
+	home, err := core.UserHomeDir()
+	if err != nil {
+		return zshCompletionStatus{}, err
 	}
 	scriptPath := filepath.Join(home, ".wtx", "completions", "_wtx")
 	zshrcPath := filepath.Join(home, ".zshrc")
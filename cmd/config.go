@@ -1,99 +1,37 @@
 package cmd
 
 import (
-	"encoding/json"
-	"errors"
-	"os"
-	"path/filepath"
-	"strconv"
 	"strings"
-)
-
-type Config struct {
-	AgentCommand          string `json:"agent_command"`
-	NewBranchBaseRef      string `json:"new_branch_base_ref,omitempty"`
-	NewBranchFetchFirst   *bool  `json:"new_branch_fetch_first,omitempty"`
-	IDECommand            string `json:"ide_command,omitempty"`
-	MainScreenBranchLimit int    `json:"main_screen_branch_limit,omitempty"`
-}
+	"time"
 
-const defaultAgentCommand = "claude"
-const defaultIDECommand = "code"
-const defaultMainScreenBranchLimit = 5
-const configDirOverrideEnv = "WTX_CONFIG_DIR"
+	"github.com/aixolotls/wtx/internal/core"
+)
 
-func LoadConfig() (Config, error) {
-	path, err := configPath()
-	if err != nil {
-		return Config{}, err
+// updateChecksEnabled reports whether wtx may look for new releases; on by
+// default. WTX_NO_UPDATE_CHECK or update_check=off turn it off.
+func updateChecksEnabled() bool {
+	if core.UpdateCheckDisabledByEnv() {
+		return false
 	}
-	data, err := os.ReadFile(path)
+	cfg, err := core.LoadConfig()
 	if err != nil {
-		return Config{}, err
-	}
-	var cfg Config
-	if err := json.Unmarshal(data, &cfg); err != nil {
-		return Config{}, err
+		return true
 	}
-	cfg.AgentCommand = strings.TrimSpace(cfg.AgentCommand)
-	cfg.IDECommand = strings.TrimSpace(cfg.IDECommand)
-	cfg.NewBranchBaseRef = strings.TrimSpace(cfg.NewBranchBaseRef)
-	if cfg.MainScreenBranchLimit <= 0 {
-		cfg.MainScreenBranchLimit = defaultMainScreenBranchLimit
-	}
-	return cfg, nil
-}
-
-func normalizeMainScreenBranchLimit(input string) (int, error) {
-	input = strings.TrimSpace(input)
-	if input == "" {
-		return defaultMainScreenBranchLimit, nil
-	}
-	limit, err := strconv.Atoi(input)
-	if err != nil || limit <= 0 {
-		return 0, errors.New("main screen branch count must be a positive number")
-	}
-	return limit, nil
+	return !strings.EqualFold(strings.TrimSpace(cfg.UpdateCheck), core.UpdateCheckOff)
 }
 
-func ConfigExists() (bool, error) {
-	path, err := configPath()
+// updateCheckInterval is how often background update checks may hit the
+// network.
+func updateCheckInterval() time.Duration {
+	cfg, err := core.LoadConfig()
 	if err != nil {
-		return false, err
-	}
-	_, err = os.Stat(path)
-	if err == nil {
-		return true, nil
-	}
-	if errors.Is(err, os.ErrNotExist) {
-		return false, nil
-	}
-	return false, err
-}
-
-func SaveConfig(cfg Config) error {
-	path, err := configPath()
-	if err != nil {
-		return err
-	}
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		return err
-	}
-	data, err := json.MarshalIndent(cfg, "", "  ")
-	if err != nil {
-		return err
-	}
-	data = append(data, '\n')
-	return os.WriteFile(path, data, 0o644)
-}
-
-func configPath() (string, error) {
-	if dir := strings.TrimSpace(os.Getenv(configDirOverrideEnv)); dir != "" {
-		return filepath.Join(dir, "config.json"), nil
+		return defaultUpdateInterval
 	}
-	home := os.Getenv("HOME")
-	if strings.TrimSpace(home) == "" {
-		return "", errors.New("HOME not set")
+	switch strings.ToLower(strings.TrimSpace(cfg.UpdateCheck)) {
+	case core.UpdateCheckDaily:
+		return 24 * time.Hour
+	case core.UpdateCheckWeekly:
+		return 7 * 24 * time.Hour
 	}
-	return filepath.Join(home, ".wtx", "config.json"), nil
+	return defaultUpdateInterval
 }
@@ -0,0 +1,551 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aixolotls/wtx/internal/core"
+	"github.com/spf13/cobra"
+)
+
+type configKeyKind int
+
+const (
+	configKindString configKeyKind = iota
+	configKindBool
+	configKindPositiveInt
+	configKindStringList
+)
+
+type configKeySpec struct {
+	Name    string
+	Kind    configKeyKind
+	Allowed []string
+}
+
+var globalConfigKeys = []configKeySpec{
+	{Name: "agent_command"},
+	{Name: "default_agent_profile"},
+	{Name: "ide_command"},
+	{Name: "ide_vscode_workspace", Kind: configKindBool},
+	{Name: "ide_window", Allowed: ideWindowModes},
+	{Name: "git_ui_command"},
+	{Name: "linux_terminal", Allowed: linuxTerminals},
+	{Name: "new_branch_base_ref"},
+	{Name: "new_branch_fetch_first", Kind: configKindBool},
+	{Name: "update_strategy", Allowed: core.UpdateStrategies},
+	{Name: "main_screen_branch_limit", Kind: configKindPositiveInt},
+	{Name: "agent_logs", Kind: configKindBool},
+	{Name: "agent_log_keep", Kind: configKindPositiveInt},
+	{Name: "log_retention_days", Kind: configKindPositiveInt},
+	{Name: "log_max_mb", Kind: configKindPositiveInt},
+	{Name: "agent_idle_minutes", Kind: configKindPositiveInt},
+	{Name: "agent_idle_release", Kind: configKindBool},
+	{Name: "pr_refresh_seconds", Kind: configKindPositiveInt},
+	{Name: "background_fetch", Kind: configKindBool},
+	{Name: "background_fetch_minutes", Kind: configKindPositiveInt},
+	{Name: "delete_grace_seconds", Kind: configKindPositiveInt},
+	{Name: "gc_merged_days", Kind: configKindPositiveInt},
+	{Name: "gc_untouched_days", Kind: configKindPositiveInt},
+	{Name: "gc_on_startup", Kind: configKindBool},
+	{Name: "agent_layout", Allowed: []string{agentLayoutSplit, agentLayoutWindow}},
+	{Name: "agent_resume_command"},
+	{Name: "bootstrap_command"},
+	{Name: "test_command"},
+	{Name: "lfs_skip_smudge", Kind: configKindBool},
+	{Name: "direnv", Kind: configKindBool},
+	{Name: "worktree_dir"},
+	{Name: "worktree_volume"},
+	{Name: "copy_files", Kind: configKindStringList},
+	{Name: "update_check", Allowed: updateCheckFrequencies},
+	{Name: "update_channel", Allowed: updateChannels},
+	{Name: "update_base_url"},
+	{Name: "theme", Allowed: themeNames},
+}
+
+var repoConfigKeys = []configKeySpec{
+	{Name: "agent_command"},
+	{Name: "agent_profile"},
+	{Name: "ide_command"},
+	{Name: "ide_window", Allowed: ideWindowModes},
+	{Name: "git_ui_command"},
+	{Name: "new_branch_base_ref"},
+	{Name: "update_strategy", Allowed: core.UpdateStrategies},
+	{Name: "bootstrap_command"},
+	{Name: "test_command"},
+	{Name: "lfs_skip_smudge", Kind: configKindBool},
+	{Name: "worktree_dir"},
+	{Name: "copy_files", Kind: configKindStringList},
+}
+
+var configKeyAliases = map[string]string{
+	"agent":     "agent_command",
+	"ide":       "ide_command",
+	"base_ref":  "new_branch_base_ref",
+	"bootstrap": "bootstrap_command",
+	"test":      "test_command",
+	"layout":    "agent_layout",
+}
+
+func newConfigGetCommand() *cobra.Command {
+	var repo bool
+	cmd := &cobra.Command{
+		Use:   "get <key>",
+		Short: "Print a config value",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runConfigGet(os.Stdout, repo, args[0])
+		},
+	}
+	cmd.Flags().BoolVar(&repo, "repo", false, "Use the repo's "+core.RepoConfigFileName+" instead of the global config")
+	return cmd
+}
+
+func newConfigSetCommand() *cobra.Command {
+	var repo bool
+	cmd := &cobra.Command{
+		Use:     "set <key> <value>",
+		Short:   "Set a config value",
+		Example: "  wtx config set agent_command \"claude --model opus\"\n  wtx config set --repo agent_profile review",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runConfigSet(repo, args[0], args[1])
+		},
+	}
+	cmd.Flags().BoolVar(&repo, "repo", false, "Use the repo's "+core.RepoConfigFileName+" instead of the global config")
+	return cmd
+}
+
+func newConfigUnsetCommand() *cobra.Command {
+	var repo bool
+	cmd := &cobra.Command{
+		Use:   "unset <key>",
+		Short: "Remove a config value",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runConfigUnset(repo, args[0])
+		},
+	}
+	cmd.Flags().BoolVar(&repo, "repo", false, "Use the repo's "+core.RepoConfigFileName+" instead of the global config")
+	return cmd
+}
+
+func newConfigListCommand() *cobra.Command {
+	var repo bool
+	var keys bool
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List config values",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if keys {
+				return runConfigListKeys(os.Stdout, repo)
+			}
+			return runConfigList(os.Stdout, repo)
+		},
+	}
+	cmd.Flags().BoolVar(&repo, "repo", false, "Use the repo's "+core.RepoConfigFileName+" instead of the global config")
+	cmd.Flags().BoolVar(&keys, "keys", false, "List the supported keys instead of values")
+	return cmd
+}
+
+func runConfigGet(w io.Writer, repo bool, key string) error {
+	if action, ok, err := keybindingConfigKey(repo, key); ok || err != nil {
+		if err != nil {
+			return err
+		}
+		values, _, err := readConfigValues(repo)
+		if err != nil {
+			return err
+		}
+		bindings, err := decodeConfigMap(values, "keybindings")
+		if err != nil {
+			return err
+		}
+		keys, ok := bindings[action]
+		if !ok {
+			return fmt.Errorf("%s%s is not set", keybindingsConfigPrefix, action)
+		}
+		fmt.Fprintln(w, keys)
+		return nil
+	}
+	if event, ok, err := hookConfigKey(key); ok || err != nil {
+		if err != nil {
+			return err
+		}
+		values, _, err := readConfigValues(repo)
+		if err != nil {
+			return err
+		}
+		hooks, err := decodeConfigMap(values, "hooks")
+		if err != nil {
+			return err
+		}
+		command, ok := hooks[event]
+		if !ok {
+			return fmt.Errorf("%s%s is not set", hooksConfigPrefix, event)
+		}
+		fmt.Fprintln(w, command)
+		return nil
+	}
+	spec, err := lookupConfigKey(repo, key)
+	if err != nil {
+		return err
+	}
+	values, _, err := readConfigValues(repo)
+	if err != nil {
+		return err
+	}
+	raw, ok := values[spec.Name]
+	if !ok {
+		return fmt.Errorf("%s is not set", spec.Name)
+	}
+	fmt.Fprintln(w, formatConfigValue(raw))
+	return nil
+}
+
+func runConfigSet(repo bool, key string, value string) error {
+	if action, ok, err := keybindingConfigKey(repo, key); ok || err != nil {
+		if err != nil {
+			return err
+		}
+		keys := parseKeyList(value)
+		if len(keys) == 0 {
+			return fmt.Errorf("%s%s cannot be empty; use `wtx config unset %s%s`", keybindingsConfigPrefix, action, keybindingsConfigPrefix, action)
+		}
+		return updateConfigMapEntry(false, "keybindings", action, strings.Join(keys, ","))
+	}
+	if event, ok, err := hookConfigKey(key); ok || err != nil {
+		if err != nil {
+			return err
+		}
+		if value = strings.TrimSpace(value); value == "" {
+			return fmt.Errorf("%s%s cannot be empty; use `wtx config unset %s%s`", hooksConfigPrefix, event, hooksConfigPrefix, event)
+		}
+		return updateConfigMapEntry(repo, "hooks", event, value)
+	}
+	spec, err := lookupConfigKey(repo, key)
+	if err != nil {
+		return err
+	}
+	parsed, err := parseConfigValue(spec, value)
+	if err != nil {
+		return err
+	}
+	values, path, err := readConfigValues(repo)
+	if err != nil {
+		return err
+	}
+	encoded, err := json.Marshal(parsed)
+	if err != nil {
+		return err
+	}
+	values[spec.Name] = encoded
+	return writeConfigValues(repo, path, values)
+}
+
+func runConfigUnset(repo bool, key string) error {
+	if action, ok, err := keybindingConfigKey(repo, key); ok || err != nil {
+		if err != nil {
+			return err
+		}
+		return updateConfigMapEntry(false, "keybindings", action, "")
+	}
+	if event, ok, err := hookConfigKey(key); ok || err != nil {
+		if err != nil {
+			return err
+		}
+		return updateConfigMapEntry(repo, "hooks", event, "")
+	}
+	spec, err := lookupConfigKey(repo, key)
+	if err != nil {
+		return err
+	}
+	values, path, err := readConfigValues(repo)
+	if err != nil {
+		return err
+	}
+	if _, ok := values[spec.Name]; !ok {
+		return nil
+	}
+	delete(values, spec.Name)
+	return writeConfigValues(repo, path, values)
+}
+
+func runConfigList(w io.Writer, repo bool) error {
+	values, _, err := readConfigValues(repo)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "%s=%s\n", name, formatConfigValue(values[name]))
+	}
+	return nil
+}
+
+func runConfigListKeys(w io.Writer, repo bool) error {
+	for _, spec := range configKeysForScope(repo) {
+		kind := "string"
+		switch spec.Kind {
+		case configKindBool:
+			kind = "bool"
+		case configKindPositiveInt:
+			kind = "number"
+		case configKindStringList:
+			kind = "comma-separated list"
+		}
+		if len(spec.Allowed) > 0 {
+			kind = strings.Join(spec.Allowed, "|")
+		}
+		fmt.Fprintf(w, "%s\t%s\n", spec.Name, kind)
+	}
+	for _, event := range hookEvents() {
+		fmt.Fprintf(w, "%s%s\tshell command\n", hooksConfigPrefix, event)
+	}
+	if !repo {
+		for _, name := range keyBindingActionNames() {
+			fmt.Fprintf(w, "%s%s\tcomma-separated keys\n", keybindingsConfigPrefix, name)
+		}
+	}
+	return nil
+}
+
+const keybindingsConfigPrefix = "keybindings."
+
+// keybindingConfigKey reports whether key addresses a single keybinding such
+// as keybindings.delete, and returns the action name.
+func keybindingConfigKey(repo bool, key string) (string, bool, error) {
+	key = strings.TrimSpace(key)
+	if !strings.HasPrefix(strings.ToLower(key), keybindingsConfigPrefix) {
+		return "", false, nil
+	}
+	if repo {
+		return "", false, errors.New("keybindings can only be set in the global config")
+	}
+	action := strings.ToLower(key[len(keybindingsConfigPrefix):])
+	for _, name := range keyBindingActionNames() {
+		if name == action {
+			return action, true, nil
+		}
+	}
+	return "", false, fmt.Errorf("unknown keybinding action %q; run `wtx config list --keys`", action)
+}
+
+// decodeConfigMap reads an object-valued key such as keybindings or hooks.
+func decodeConfigMap(values map[string]json.RawMessage, field string) (map[string]string, error) {
+	entries := map[string]string{}
+	raw, ok := values[field]
+	if !ok {
+		return entries, nil
+	}
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return nil, fmt.Errorf("%s: %w", field, err)
+	}
+	return entries, nil
+}
+
+// updateConfigMapEntry sets or, with an empty value, removes one entry of an
+// object-valued key.
+func updateConfigMapEntry(repo bool, field string, name string, value string) error {
+	values, path, err := readConfigValues(repo)
+	if err != nil {
+		return err
+	}
+	entries, err := decodeConfigMap(values, field)
+	if err != nil {
+		return err
+	}
+	if value == "" {
+		if _, ok := entries[name]; !ok {
+			return nil
+		}
+		delete(entries, name)
+	} else {
+		entries[name] = value
+	}
+	if len(entries) == 0 {
+		delete(values, field)
+	} else {
+		encoded, err := json.Marshal(entries)
+		if err != nil {
+			return err
+		}
+		values[field] = encoded
+	}
+	return writeConfigValues(repo, path, values)
+}
+
+const hooksConfigPrefix = "hooks."
+
+// hookConfigKey reports whether key addresses a hook such as
+// hooks.post_create, and returns the event.
+func hookConfigKey(key string) (string, bool, error) {
+	key = strings.TrimSpace(key)
+	if !strings.HasPrefix(strings.ToLower(key), hooksConfigPrefix) {
+		return "", false, nil
+	}
+	event := strings.ToLower(key[len(hooksConfigPrefix):])
+	for _, name := range hookEvents() {
+		if name == event {
+			return event, true, nil
+		}
+	}
+	return "", false, fmt.Errorf("unknown hook %q; hooks are %s", event, strings.Join(hookEvents(), ", "))
+}
+
+func configKeysForScope(repo bool) []configKeySpec {
+	if repo {
+		return repoConfigKeys
+	}
+	return globalConfigKeys
+}
+
+func lookupConfigKey(repo bool, key string) (configKeySpec, error) {
+	key = strings.ToLower(strings.TrimSpace(key))
+	if alias, ok := configKeyAliases[key]; ok {
+		key = alias
+	}
+	for _, spec := range configKeysForScope(repo) {
+		if spec.Name == key {
+			return spec, nil
+		}
+	}
+	scope := "global"
+	if repo {
+		scope = "repo"
+	}
+	return configKeySpec{}, fmt.Errorf("unknown %s config key %q; run `wtx config list --keys`", scope, key)
+}
+
+func parseConfigValue(spec configKeySpec, value string) (any, error) {
+	value = strings.TrimSpace(value)
+	switch spec.Kind {
+	case configKindBool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be true or false", spec.Name)
+		}
+		return parsed, nil
+	case configKindPositiveInt:
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("%s must be a positive number", spec.Name)
+		}
+		return parsed, nil
+	case configKindStringList:
+		list := core.NormalizeCopyFiles(strings.Split(value, ","))
+		if len(list) == 0 {
+			return nil, fmt.Errorf("%s cannot be empty; use `wtx config unset %s`", spec.Name, spec.Name)
+		}
+		return list, nil
+	}
+	if len(spec.Allowed) > 0 {
+		for _, allowed := range spec.Allowed {
+			if strings.EqualFold(value, allowed) {
+				return allowed, nil
+			}
+		}
+		return nil, fmt.Errorf("%s must be one of: %s", spec.Name, strings.Join(spec.Allowed, ", "))
+	}
+	if value == "" {
+		return nil, fmt.Errorf("%s cannot be empty; use `wtx config unset %s`", spec.Name, spec.Name)
+	}
+	return value, nil
+}
+
+func formatConfigValue(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
+
+func configFilePath(repo bool) (string, error) {
+	if !repo {
+		return core.ConfigPath()
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	path, err := core.RepoConfigPath(cwd)
+	if err != nil {
+		return "", core.ErrNotInGitRepository
+	}
+	return path, nil
+}
+
+// readConfigValues loads the raw JSON object so keys wtx does not know about
+// survive a set or unset. An older global config is migrated first, so edits
+// apply to the current schema.
+func readConfigValues(repo bool) (map[string]json.RawMessage, string, error) {
+	path, err := configFilePath(repo)
+	if err != nil {
+		return nil, "", err
+	}
+	values := map[string]json.RawMessage{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return values, path, nil
+		}
+		return nil, "", err
+	}
+	if !repo {
+		if data, _, _, err = core.MigrateConfigData(data); err != nil {
+			return nil, "", fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, "", fmt.Errorf("%s: %w", path, err)
+	}
+	return values, path, nil
+}
+
+func writeConfigValues(repo bool, path string, values map[string]json.RawMessage) error {
+	data, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return err
+	}
+	if !repo {
+		var cfg core.Config
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return err
+		}
+		if _, err := newKeymap(cfg.Keybindings); err != nil {
+			return err
+		}
+		return core.SaveConfigValues(path, values)
+	}
+	var cfg core.RepoConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return err
+	}
+	// Editing a .wtx.json the user already trusts keeps it trusted; an
+	// untrusted file stays untrusted, whatever key was changed.
+	trusted := core.RepoConfigFileTrusted(path)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if err := core.WriteFileAtomic(path, data); err != nil {
+		return err
+	}
+	if trusted {
+		return core.TrustRepoConfigData(path, data)
+	}
+	return nil
+}
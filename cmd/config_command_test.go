@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+func TestConfigSetGetUnset(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(core.ConfigDirOverrideEnv, "")
+
+	if err := runConfigSet(false, "base_ref", "origin/develop"); err != nil {
+		t.Fatalf("set base_ref: %v", err)
+	}
+	if err := runConfigSet(false, "agent_logs", "true"); err != nil {
+		t.Fatalf("set agent_logs: %v", err)
+	}
+	cfg, err := core.LoadConfig()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.NewBranchBaseRef != "origin/develop" {
+		t.Fatalf("expected base ref origin/develop, got %q", cfg.NewBranchBaseRef)
+	}
+	if cfg.AgentLogs == nil || !*cfg.AgentLogs {
+		t.Fatalf("expected agent_logs true, got %v", cfg.AgentLogs)
+	}
+
+	var out bytes.Buffer
+	if err := runConfigGet(&out, false, "new_branch_base_ref"); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got := strings.TrimSpace(out.String()); got != "origin/develop" {
+		t.Fatalf("expected origin/develop, got %q", got)
+	}
+
+	if err := runConfigUnset(false, "base_ref"); err != nil {
+		t.Fatalf("unset: %v", err)
+	}
+	if err := runConfigGet(&out, false, "base_ref"); err == nil {
+		t.Fatalf("expected error for unset key")
+	}
+}
+
+func TestConfigSetWritesCurrentVersion(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(core.ConfigDirOverrideEnv, dir)
+	path := filepath.Join(dir, "config.json")
+
+	if err := runConfigSet(false, "update_check", "weekly"); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	var out bytes.Buffer
+	if err := runConfigMigrate(&out, true); err != nil {
+		t.Fatalf("expected a config written by set to need no migration: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"version": 1, "update_checks": false}`), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if err := runConfigSet(false, "agent_command", "codex"); err != nil {
+		t.Fatalf("set on an older config: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	if got := string(data); strings.Contains(got, "update_checks") || !strings.Contains(got, `"update_check": "off"`) {
+		t.Fatalf("expected the older config migrated before the edit, got %s", got)
+	}
+
+	newer := []byte(`{"version": 99, "agent_command": "claude"}`)
+	if err := os.WriteFile(path, newer, 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if err := runConfigSet(false, "agent_command", "codex"); err == nil || !strings.Contains(err.Error(), "newer than this wtx") {
+		t.Fatalf("expected set to refuse a newer config, got %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != string(newer) {
+		t.Fatalf("expected the newer config left alone, got %s", data)
+	}
+}
+
+func TestConfigSetValidatesKeysAndValues(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(core.ConfigDirOverrideEnv, "")
+
+	if err := runConfigSet(false, "not_a_key", "x"); err == nil {
+		t.Fatalf("expected unknown key error")
+	}
+	if err := runConfigSet(false, "agent_layout", "tabs"); err == nil {
+		t.Fatalf("expected invalid layout error")
+	}
+	if err := runConfigSet(false, "main_screen_branch_limit", "0"); err == nil {
+		t.Fatalf("expected invalid number error")
+	}
+	if _, err := lookupConfigKey(true, "agent_idle_release"); err == nil {
+		t.Fatalf("expected agent_idle_release to be rejected for repo scope")
+	}
+}
+
+func TestConfigSetRepoScopeWritesRepoFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo := initTestRepo(t)
+	t.Chdir(repo)
+
+	if err := runConfigSet(true, "agent_command", "codex"); err != nil {
+		t.Fatalf("set repo agent_command: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(repo, core.RepoConfigFileName))
+	if err != nil {
+		t.Fatalf("read repo config: %v", err)
+	}
+	if !strings.Contains(string(data), `"agent_command": "codex"`) {
+		t.Fatalf("expected agent_command in repo config, got %s", data)
+	}
+}
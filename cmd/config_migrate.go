@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/aixolotls/wtx/internal/core"
+	"github.com/spf13/cobra"
+)
+
+func newConfigMigrateCommand() *cobra.Command {
+	var check bool
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Upgrade the config file to the current schema version",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runConfigMigrate(os.Stdout, check)
+		},
+	}
+	cmd.Flags().BoolVar(&check, "check", false, "Only report whether a migration is needed (exit 1 if so)")
+	return cmd
+}
+
+func runConfigMigrate(w io.Writer, check bool) error {
+	path, err := core.ConfigPath()
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			fmt.Fprintln(w, "No config file; nothing to migrate.")
+			return nil
+		}
+		return err
+	}
+	migrated, from, changed, err := core.MigrateConfigData(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if from > core.CurrentConfigVersion {
+		return core.NewerConfigError(path, from)
+	}
+	if !changed {
+		fmt.Fprintf(w, "Config is up to date (version %d).\n", from)
+		return nil
+	}
+	if check {
+		return fmt.Errorf("config needs migration from version %d to %d; run `wtx config migrate`", from, core.CurrentConfigVersion)
+	}
+	if err := core.WriteFileAtomic(path, migrated); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Migrated config from version %d to %d.\n", from, core.CurrentConfigVersion)
+	return nil
+}
@@ -1,36 +1,81 @@
 package cmd
 
 import (
+	"bytes"
+	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/aixolotls/wtx/internal/core"
 )
 
-func TestConfigPath_UsesOverrideEnv(t *testing.T) {
-	override := t.TempDir()
-	t.Setenv(configDirOverrideEnv, override)
-	t.Setenv("HOME", "")
+func TestConfigFormApplyPreservesOtherFields(t *testing.T) {
+	logs := true
+	cfg := core.Config{
+		AgentCommand:  "claude",
+		AgentProfiles: []core.AgentProfile{{Name: "review", Command: "claude --review"}},
+		AgentLogs:     &logs,
+		AgentLayout:   agentLayoutWindow,
+		Theme:         themeLight,
+	}
+	values := configFormValuesFrom(cfg)
+	if values.AgentLayout != agentLayoutWindow || values.Theme != themeLight || !values.UpdateChecks {
+		t.Fatalf("unexpected form values %+v", values)
+	}
+	values.AgentCommand = "codex"
+	values.AgentLayout = agentLayoutSplit
+	values.Theme = themeDark
+	values.UpdateChecks = false
 
-	path, err := configPath()
+	got, err := values.apply(cfg)
 	if err != nil {
-		t.Fatalf("configPath with override: %v", err)
+		t.Fatalf("apply: %v", err)
+	}
+	if got.AgentCommand != "codex" {
+		t.Fatalf("expected agent codex, got %q", got.AgentCommand)
 	}
-	want := filepath.Join(override, "config.json")
-	if path != want {
-		t.Fatalf("expected %q, got %q", want, path)
+	if got.AgentLayout != "" || got.Theme != "" {
+		t.Fatalf("expected defaults to be stored empty, got layout=%q theme=%q", got.AgentLayout, got.Theme)
+	}
+	if got.UpdateCheck != core.UpdateCheckOff {
+		t.Fatalf("expected update checks off, got %q", got.UpdateCheck)
+	}
+	if len(got.AgentProfiles) != 1 || got.AgentLogs == nil || !*got.AgentLogs {
+		t.Fatalf("expected unrelated settings to survive, got %+v", got)
+	}
+
+	values.BranchLimit = "0"
+	if _, err := values.apply(cfg); err == nil {
+		t.Fatalf("expected invalid branch count error")
 	}
 }
 
-func TestConfigPath_UsesHomeByDefault(t *testing.T) {
-	home := t.TempDir()
-	t.Setenv(configDirOverrideEnv, "")
-	t.Setenv("HOME", home)
+func TestConfigMigrateCheck(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(core.ConfigDirOverrideEnv, dir)
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte(`{"agent_command": "claude"}`), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
 
-	path, err := configPath()
+	if _, err := core.LoadConfig(); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	var out bytes.Buffer
+	if err := runConfigMigrate(&out, true); err == nil {
+		t.Fatalf("expected --check to fail for an unversioned config, even after it was loaded")
+	}
+	if err := runConfigMigrate(&out, false); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if err := runConfigMigrate(&out, true); err != nil {
+		t.Fatalf("expected --check to pass after migration: %v", err)
+	}
+	cfg, err := core.LoadConfig()
 	if err != nil {
-		t.Fatalf("configPath default: %v", err)
+		t.Fatalf("load: %v", err)
 	}
-	want := filepath.Join(home, ".wtx", "config.json")
-	if path != want {
-		t.Fatalf("expected %q, got %q", want, path)
+	if cfg.Version != core.CurrentConfigVersion || cfg.AgentCommand != "claude" {
+		t.Fatalf("unexpected config after migration: %+v", cfg)
 	}
 }
@@ -18,16 +18,57 @@ const (
 	confirmOpenPickLocked
 	confirmOpenBaseDefault
 	confirmOpenFetchDefault
+	confirmBulk
+	confirmStashSwitch
 )
 
+// wtxHuhTheme styles forms from the active palette, so they follow the
+// configured theme like every other view.
 func wtxHuhTheme() *huh.Theme {
-	t := *huh.ThemeCharm()
-	t.Focused.FocusedButton = t.Focused.FocusedButton.Background(lipgloss.Color("#7D56F4"))
+	p := activePalette
+	var (
+		text     = lipgloss.Color(p.Text)
+		muted    = lipgloss.Color(p.Muted)
+		faint    = lipgloss.Color(p.Faint)
+		errColor = lipgloss.Color(p.Error)
+		success  = lipgloss.Color(p.Success)
+	)
+	t := huh.ThemeBase()
+	t.Focused.Base = t.Focused.Base.BorderForeground(faint)
+	t.Focused.Card = t.Focused.Base
+	t.Focused.Title = t.Focused.Title.Foreground(accentColor).Bold(true)
+	t.Focused.NoteTitle = t.Focused.NoteTitle.Foreground(accentColor).Bold(true).MarginBottom(1)
+	t.Focused.Directory = t.Focused.Directory.Foreground(accentColor)
+	t.Focused.Description = t.Focused.Description.Foreground(muted)
+	t.Focused.ErrorIndicator = t.Focused.ErrorIndicator.Foreground(errColor)
+	t.Focused.ErrorMessage = t.Focused.ErrorMessage.Foreground(errColor)
+	t.Focused.SelectSelector = t.Focused.SelectSelector.Foreground(accentColor)
+	t.Focused.NextIndicator = t.Focused.NextIndicator.Foreground(accentColor)
+	t.Focused.PrevIndicator = t.Focused.PrevIndicator.Foreground(accentColor)
+	t.Focused.Option = t.Focused.Option.Foreground(text)
+	t.Focused.MultiSelectSelector = t.Focused.MultiSelectSelector.Foreground(accentColor)
+	t.Focused.SelectedOption = t.Focused.SelectedOption.Foreground(success)
+	t.Focused.SelectedPrefix = lipgloss.NewStyle().Foreground(success).SetString("✓ ")
+	t.Focused.UnselectedPrefix = lipgloss.NewStyle().Foreground(muted).SetString("• ")
+	t.Focused.UnselectedOption = t.Focused.UnselectedOption.Foreground(text)
+	t.Focused.FocusedButton = t.Focused.FocusedButton.Foreground(lipgloss.Color(p.OnAccent)).Background(accentColor)
 	t.Focused.Next = t.Focused.FocusedButton
+	t.Focused.BlurredButton = t.Focused.BlurredButton.Foreground(text).Background(faint)
+	t.Focused.TextInput.Placeholder = t.Focused.TextInput.Placeholder.Foreground(lipgloss.Color(p.Disabled))
+	t.Focused.TextInput.Prompt = t.Focused.TextInput.Prompt.Foreground(accentColor)
 	// Keep placeholder text fully readable on first paint by avoiding a block-style cursor.
 	t.Focused.TextInput.Cursor = lipgloss.NewStyle()
 	t.Focused.TextInput.CursorText = lipgloss.NewStyle()
-	return &t
+
+	t.Blurred = t.Focused
+	t.Blurred.Base = t.Focused.Base.BorderStyle(lipgloss.HiddenBorder())
+	t.Blurred.Card = t.Blurred.Base
+	t.Blurred.NextIndicator = lipgloss.NewStyle()
+	t.Blurred.PrevIndicator = lipgloss.NewStyle()
+
+	t.Group.Title = t.Focused.Title
+	t.Group.Description = t.Focused.Description
+	return t
 }
 
 func newConfirmForm(title string, description string, result *bool) *huh.Form {
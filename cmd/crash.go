@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/aixolotls/wtx/internal/core"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// crashLogLines is how much of the debug log a crash report carries.
+const crashLogLines = 50
+
+// terminalReset leaves the alt screen, turns mouse reporting off and shows
+// the cursor, undoing whatever a TUI left behind.
+const terminalReset = "\x1b[?1049l\x1b[?1000l\x1b[?1002l\x1b[?1003l\x1b[?1006l\x1b[?25h"
+
+type crash struct {
+	value any
+	stack []byte
+}
+
+func crashDir() (string, error) {
+	home, err := core.WtxHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "crashes"), nil
+}
+
+// writeCrashReport saves the panic, stack, version and the tail of the debug
+// log under ~/.wtx/crashes and returns the file path.
+func writeCrashReport(c crash) (string, error) {
+	dir, err := crashDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	now := time.Now()
+	var b strings.Builder
+	fmt.Fprintf(&b, "wtx %s crashed at %s\n", core.CurrentVersion(), now.Format(time.RFC3339))
+	fmt.Fprintf(&b, "args: %q\n", os.Args)
+	if cwd, err := os.Getwd(); err == nil {
+		fmt.Fprintf(&b, "cwd: %s\n", cwd)
+	}
+	fmt.Fprintf(&b, "\npanic: %v\n\n%s", c.value, c.stack)
+	if lines := recentDebugLogLines(crashLogLines); len(lines) > 0 {
+		fmt.Fprintf(&b, "\nlast %d log lines:\n%s\n", len(lines), strings.Join(lines, "\n"))
+	}
+	path := filepath.Join(dir, fmt.Sprintf("crash-%s-%d.txt", now.Format("20060102-150405"), os.Getpid()))
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func recentDebugLogLines(n int) []string {
+	path, err := core.DebugLogPath()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	lines := strings.Split(strings.TrimRight(string(bytes.ToValidUTF8(data, nil)), "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	if len(lines) == 1 && lines[0] == "" {
+		return nil
+	}
+	return lines
+}
+
+// crashError writes the report and turns the panic into an error, so main
+// prints it and deferred cleanup such as lock release still runs.
+func crashError(c crash) error {
+	core.DebugLog("panic", "value", fmt.Sprint(c.value))
+	path, err := writeCrashReport(c)
+	if err != nil {
+		return fmt.Errorf("wtx crashed: %v (could not write crash report: %v)\n%s", c.value, err, c.stack)
+	}
+	return fmt.Errorf("wtx crashed: %v\ncrash report: %s", c.value, path)
+}
+
+// recoverCrash is deferred by CLI entry points with a named error result.
+func recoverCrash(err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if isInteractiveTerminal(os.Stdout) {
+		fmt.Fprint(os.Stdout, terminalReset)
+	}
+	*err = crashError(crash{value: r, stack: debug.Stack()})
+}
+
+// runProgram runs a bubbletea program whose model, and the commands it
+// returns, turn a panic into a clean quit. Bubbletea then restores the
+// terminal, and the panic comes back as an error with a crash report.
+func runProgram(m tea.Model, opts ...tea.ProgramOption) (tea.Model, error) {
+	guard := &crashGuard{}
+	guard.program = tea.NewProgram(crashSafeModel{inner: m, guard: guard}, opts...)
+	finalModel, err := guard.program.Run()
+	if guard.crash != nil {
+		return nil, crashError(*guard.crash)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if safe, ok := finalModel.(crashSafeModel); ok {
+		return safe.inner, nil
+	}
+	return finalModel, nil
+}
+
+type crashGuard struct {
+	program *tea.Program
+	crash   *crash
+}
+
+// crashMsg carries a panic out of a command's goroutine.
+type crashMsg struct {
+	crash crash
+}
+
+type crashSafeModel struct {
+	inner tea.Model
+	guard *crashGuard
+}
+
+func (m crashSafeModel) Init() (cmd tea.Cmd) {
+	defer func() {
+		if r := recover(); r != nil {
+			cmd = m.crashed(crash{value: r, stack: debug.Stack()})
+		}
+	}()
+	return guardCmd(m.inner.Init())
+}
+
+func (m crashSafeModel) Update(msg tea.Msg) (next tea.Model, cmd tea.Cmd) {
+	if m.guard.crash != nil {
+		return m, nil
+	}
+	if msg, ok := msg.(crashMsg); ok {
+		return m, m.crashed(msg.crash)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			next, cmd = m, m.crashed(crash{value: r, stack: debug.Stack()})
+		}
+	}()
+	inner, cmd := m.inner.Update(msg)
+	m.inner = inner
+	return m, guardCmd(cmd)
+}
+
+func (m crashSafeModel) View() (view string) {
+	if m.guard.crash != nil {
+		return ""
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			m.crashed(crash{value: r, stack: debug.Stack()})
+			// View runs on the event loop, which Quit would block on.
+			go m.guard.program.Quit()
+			view = ""
+		}
+	}()
+	return m.inner.View()
+}
+
+func (m crashSafeModel) crashed(c crash) tea.Cmd {
+	if m.guard.crash == nil {
+		m.guard.crash = &c
+	}
+	return tea.Quit
+}
+
+// guardCmd wraps cmd, and the commands of a batch it returns, so a panic
+// becomes a crashMsg instead of killing the program with the terminal raw.
+func guardCmd(cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() (msg tea.Msg) {
+		defer func() {
+			if r := recover(); r != nil {
+				msg = crashMsg{crash: crash{value: r, stack: debug.Stack()}}
+			}
+		}()
+		msg = cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			guarded := make(tea.BatchMsg, len(batch))
+			for i, c := range batch {
+				guarded[i] = guardCmd(c)
+			}
+			return guarded
+		}
+		return msg
+	}
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aixolotls/wtx/internal/core"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type panickyModel struct {
+	initCmd tea.Cmd
+}
+
+func (m panickyModel) Init() tea.Cmd { return m.initCmd }
+
+func (m panickyModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(tea.KeyMsg); ok {
+		panic("boom in update")
+	}
+	if msg == "done" {
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m panickyModel) View() string { return "" }
+
+func runHeadless(m tea.Model) (tea.Model, error) {
+	return runProgram(m, tea.WithInput(nil), tea.WithOutput(io.Discard), tea.WithoutRenderer(), tea.WithoutSignalHandler())
+}
+
+func TestRunProgramWritesCrashReport(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	logPath, _ := core.DebugLogPath()
+	if err := os.MkdirAll(filepath.Dir(logPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(logPath, []byte("{\"msg\":\"command\",\"cmd\":\"git\"}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	panicky := func() tea.Msg { panic("boom in cmd") }
+	_, err := runHeadless(panickyModel{initCmd: tea.Batch(func() tea.Msg { return nil }, panicky)})
+	if err == nil || !strings.Contains(err.Error(), "boom in cmd") {
+		t.Fatalf("expected crash error, got %v", err)
+	}
+	_, path, ok := strings.Cut(err.Error(), "crash report: ")
+	if !ok {
+		t.Fatalf("expected the report path in %q", err)
+	}
+	data, readErr := os.ReadFile(path)
+	if readErr != nil {
+		t.Fatalf("read report: %v", readErr)
+	}
+	dir, _ := crashDir()
+	if filepath.Dir(path) != dir {
+		t.Fatalf("expected report under %s, got %s", dir, path)
+	}
+	for _, want := range []string{"panic: boom in cmd", "goroutine", "last 1 log lines", `"cmd":"git"`} {
+		if !strings.Contains(string(data), want) {
+			t.Fatalf("expected %q in report:\n%s", want, data)
+		}
+	}
+}
+
+func TestRunProgramReturnsInnerModel(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	final, err := runHeadless(panickyModel{initCmd: func() tea.Msg { return "done" }})
+	if err != nil {
+		t.Fatalf("runProgram: %v", err)
+	}
+	if _, ok := final.(panickyModel); !ok {
+		t.Fatalf("expected the inner model back, got %T", final)
+	}
+}
+
+func TestCrashSafeModelQuitsOnUpdatePanic(t *testing.T) {
+	guard := &crashGuard{}
+	m := crashSafeModel{inner: panickyModel{}, guard: guard}
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if guard.crash == nil || guard.crash.value != "boom in update" {
+		t.Fatalf("expected the panic to be recorded, got %+v", guard.crash)
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Fatalf("expected a quit command")
+	}
+}
+
+func TestRecoverCrashReturnsError(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	err := func() (err error) {
+		defer recoverCrash(&err)
+		panic(errors.New("cli boom"))
+	}()
+	if err == nil || !strings.Contains(err.Error(), "cli boom") || !strings.Contains(err.Error(), "crash report: ") {
+		t.Fatalf("expected crash error, got %v", err)
+	}
+}
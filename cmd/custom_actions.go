@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+// customActionPrefix marks the tmuxAction of a custom_actions entry; the
+// alias follows it.
+const customActionPrefix = "custom:"
+
+func customTmuxAction(alias string) tmuxAction {
+	return tmuxAction(customActionPrefix + strings.ToLower(alias))
+}
+
+func customActionAlias(action tmuxAction) (string, bool) {
+	return strings.CutPrefix(string(action), customActionPrefix)
+}
+
+// customActionItems turns the actions configured for basePath into popup
+// items. Aliases taken by a built-in item are skipped, and keys the popup
+// already uses, or single characters that would steal typing into the
+// filter, are dropped.
+func customActionItems(basePath string, builtin []tmuxActionItem, keys keymap) []tmuxActionItem {
+	cfg, err := core.LoadConfigForDir(basePath)
+	if err != nil {
+		core.DebugLog("custom actions config unreadable", "err", err.Error())
+		return nil
+	}
+	var items []tmuxActionItem
+	for _, a := range cfg.CustomActions {
+		taken := false
+		for _, item := range builtin {
+			if strings.EqualFold(item.Alias, a.Alias) {
+				taken = true
+				break
+			}
+		}
+		if taken {
+			core.DebugLog("custom action clashes with a built-in action", "alias", a.Alias)
+			continue
+		}
+		key := a.Key
+		if _, bound := keys.action(key, true); bound || utf8.RuneCountInString(key) <= 1 || slices.Contains(reservedPopupKeys, key) {
+			key = ""
+		}
+		items = append(items, tmuxActionItem{Alias: a.Alias, Label: a.Label, Description: a.Label, Keybinding: key, Action: customTmuxAction(a.Alias)})
+	}
+	return items
+}
+
+// expandCustomActionCommand fills in the {{path}} and {{branch}} placeholders,
+// quoted for the shell shellScriptCommand runs.
+func expandCustomActionCommand(command string, path string, branch string) string {
+	return strings.NewReplacer("{{path}}", core.ShellScriptQuote(path), "{{branch}}", core.ShellScriptQuote(branch)).Replace(command)
+}
+
+// runCustomAction runs the custom action in a tmux split below the agent,
+// which waits for enter once the command exits so its output can be read.
+// Without tmux it runs in the foreground.
+func runCustomAction(basePath string, alias string) error {
+	cfg, err := core.LoadConfigForDir(basePath)
+	if err != nil {
+		return err
+	}
+	action := core.FindCustomAction(cfg.CustomActions, alias)
+	if action == nil {
+		return fmt.Errorf("unknown custom action %q", alias)
+	}
+	script := expandCustomActionCommand(action.Command, basePath, core.CurrentBranchInWorktree(basePath))
+	if tmuxAvailable() {
+		wrapped := script + "\nprintf '\\n[exit %s] press enter to close' \"$?\"; read _"
+		return core.TmuxRun("split-window", "-v", "-p", "50", "-c", tmuxFormatEscape(basePath), wrapped)
+	}
+	clearPopupScreen()
+	cmd := core.ShellScriptCommand(script, false)
+	cmd.Dir = basePath
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/aixolotls/wtx/internal/core"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestCustomActionsJoinThePopup(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(core.ConfigDirOverrideEnv, "")
+	if err := os.MkdirAll(filepath.Join(home, ".wtx"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	global := `{"custom_actions": [
+		{"alias": "logs", "label": "Tail logs", "key": "ctrl+o", "command": "tail -f log/dev.log"},
+		{"alias": "test", "command": "make test"}
+	]}`
+	if err := os.WriteFile(filepath.Join(home, ".wtx", "config.json"), []byte(global), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	repo := initRenameTestRepo(t)
+	local := `{"custom_actions": [
+		{"alias": "test", "label": "Run tests", "key": "t", "command": "go test {{path}}/..."},
+		{"alias": "ide", "command": "true"}
+	]}`
+	writeTrustedRepoConfig(t, repo, local)
+
+	m := newTmuxActionsModel(repo, false, false, false)
+	byAlias := map[string]tmuxActionItem{}
+	for _, item := range m.items {
+		byAlias[item.Alias] = item
+	}
+	if item := byAlias["logs"]; item.Label != "Tail logs" || item.Keybinding != "ctrl+o" {
+		t.Fatalf("logs = %+v", item)
+	}
+	if item := byAlias["test"]; item.Label != "Run tests" || item.Keybinding != "" {
+		t.Fatalf("expected the repo's test action without the single-key binding, got %+v", item)
+	}
+	if item := byAlias["ide"]; item.Action != tmuxActionIDE {
+		t.Fatalf("expected the built-in ide to win, got %+v", item)
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlO})
+	if chosen := updated.(tmuxActionsModel).chosen; chosen != customTmuxAction("logs") {
+		t.Fatalf("expected ctrl+o to choose logs, got %q", chosen)
+	}
+	if action := parseTmuxAction("custom:Logs"); action != customTmuxAction("logs") {
+		t.Fatalf("parseTmuxAction = %q", action)
+	}
+	want := `go test '/w/it'\''s'/... # 'feat'`
+	if runtime.GOOS == "windows" {
+		want = `go test "/w/it's"/... # "feat"`
+	}
+	if got := expandCustomActionCommand("go test {{path}}/... # {{branch}}", "/w/it's", "feat"); got != want {
+		t.Fatalf("expand = %q, want %q", got, want)
+	}
+}
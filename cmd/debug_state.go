@@ -0,0 +1,271 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+// runDebugState prints what wtx sees about its config, locks, caches and
+// surroundings. Every section is best effort: a failure is printed in place
+// and the dump goes on, since it is most useful when something is broken.
+func runDebugState(w io.Writer) error {
+	home, err := core.WtxHomeDir()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	writeStateSection(w, "wtx")
+	exe, _ := os.Executable()
+	fmt.Fprintf(w, "version: %s\n", core.CurrentVersion())
+	fmt.Fprintf(w, "executable: %s\n", exe)
+	fmt.Fprintf(w, "platform: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(w, "state dir: %s\n", home)
+
+	writeStateSection(w, "environment")
+	writeDebugEnvironment(w)
+
+	writeStateSection(w, "repo")
+	repoRoot, branches := writeDebugRepo(w)
+
+	writeStateSection(w, "config")
+	if cfg, err := core.LoadConfigForDir(""); err != nil {
+		fmt.Fprintf(w, "error: %v\n", err)
+	} else if data, err := json.MarshalIndent(cfg, "", "  "); err == nil {
+		fmt.Fprintln(w, string(data))
+	}
+
+	writeStateSection(w, "locks")
+	writeDebugLocks(w, filepath.Join(home, "locks"), now)
+
+	writeStateSection(w, "update")
+	writeDebugUpdate(w, now)
+
+	writeStateSection(w, "gh cache")
+	writeDebugGHCache(w, filepath.Join(home, "cache", "pr"), repoRoot, branches, now)
+
+	writeStateSection(w, "tmux")
+	writeDebugTmux(w)
+	return nil
+}
+
+func writeStateSection(w io.Writer, title string) {
+	fmt.Fprintf(w, "\n== %s ==\n", title)
+}
+
+func writeDebugEnvironment(w io.Writer) {
+	tmuxState := "available"
+	switch {
+	case core.TmuxIntegrationDisabled():
+		tmuxState = "disabled"
+	case strings.TrimSpace(os.Getenv("TMUX")) == "":
+		tmuxState = "not inside tmux"
+	case !tmuxAvailable():
+		tmuxState = "tmux not on PATH"
+	}
+	fmt.Fprintf(w, "tmux integration: %s\n", tmuxState)
+	fmt.Fprintf(w, "parent terminal: %s\n", valueOrNone(resolveSessionParentTerminalProgram()))
+	fmt.Fprintf(w, "linux terminal: %s\n", valueOrNone(linuxTerminal()))
+	fmt.Fprintf(w, "can open shell tab: %t\n", canOpenShellTab())
+	fmt.Fprintf(w, "can open shell window: %t\n", canOpenShellWindow())
+	fmt.Fprintf(w, "interactive stdout: %t\n", isInteractiveTerminal(os.Stdout))
+	if git, err := core.GitPath(); err != nil {
+		fmt.Fprintf(w, "git: %v\n", err)
+	} else {
+		version, _ := core.GitOutputInDir("", git, "--version")
+		fmt.Fprintf(w, "git: %s (%s)\n", git, strings.TrimSpace(version))
+	}
+	if gh, err := exec.LookPath("gh"); err != nil {
+		fmt.Fprintln(w, "gh: not found")
+	} else {
+		fmt.Fprintf(w, "gh: %s\n", gh)
+	}
+	var vars []string
+	for _, kv := range os.Environ() {
+		if strings.HasPrefix(kv, "WTX_") || strings.HasPrefix(kv, "TMUX=") || strings.HasPrefix(kv, "TERM_PROGRAM=") {
+			vars = append(vars, kv)
+		}
+	}
+	sort.Strings(vars)
+	for _, kv := range vars {
+		fmt.Fprintf(w, "env: %s\n", kv)
+	}
+}
+
+func writeDebugRepo(w io.Writer) (string, []string) {
+	git, repoRoot, err := core.RequireGitContext("")
+	if err != nil {
+		fmt.Fprintf(w, "not in a repo: %v\n", err)
+		return "", nil
+	}
+	layoutRoot := core.WorktreeLayoutRoot(repoRoot, git)
+	fmt.Fprintf(w, "root: %s\n", repoRoot)
+	fmt.Fprintf(w, "main worktree: %s\n", layoutRoot)
+	fmt.Fprintf(w, "managed worktrees: %s\n", core.ManagedWorktreeRoot(layoutRoot))
+	worktrees, malformed, err := core.ListWorktrees(repoRoot, git)
+	if err != nil {
+		fmt.Fprintf(w, "worktrees: %v\n", err)
+		return repoRoot, nil
+	}
+	var branches []string
+	for _, wt := range worktrees {
+		state := ""
+		if problem := core.WorktreeAdminProblem(wt.Path); problem != "" {
+			state = " (broken: " + problem + ")"
+		}
+		fmt.Fprintf(w, "worktree: %s [%s]%s\n", wt.Path, wt.Branch, state)
+		if wt.Branch != "" && wt.Branch != "detached" {
+			branches = append(branches, wt.Branch)
+		}
+	}
+	for _, line := range malformed {
+		fmt.Fprintf(w, "malformed: %s\n", line)
+	}
+	return repoRoot, branches
+}
+
+func writeDebugLocks(w io.Writer, dir string, now time.Time) {
+	fmt.Fprintf(w, "dir: %s\n", dir)
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.lock"))
+	if len(paths) == 0 {
+		fmt.Fprintln(w, "no lock files")
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "FILE\tWORKTREE\tOWNER\tPID\tHOLDER\tAGE\tACTIVE")
+	for _, path := range paths {
+		name := filepath.Base(path)
+		payload, err := core.ReadLockPayload(path)
+		if err != nil {
+			fmt.Fprintf(tw, "%s\tunreadable: %v\t\t\t\t\t\n", name, err)
+			continue
+		}
+		age := "?"
+		if info, err := os.Stat(path); err == nil {
+			age = core.FormatTiming(now.Sub(info.ModTime()).Truncate(time.Second))
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\t%s\t%t\n",
+			name,
+			payload.WorktreePath,
+			payload.OwnerID,
+			payload.PID,
+			core.LockHolder{User: payload.User, Host: payload.Host}.UserHost(),
+			age,
+			core.LockOwnerStillActive(payload.OwnerID, payload.PID))
+	}
+	_ = tw.Flush()
+}
+
+func writeDebugUpdate(w io.Writer, now time.Time) {
+	fmt.Fprintf(w, "channel: %s\n", configuredUpdateChannel())
+	if state, err := readUpdateState(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		fmt.Fprintf(w, "state: %v\n", err)
+	} else {
+		checked := "never"
+		if state.LastCheckedUnix > 0 {
+			checked = core.FormatTiming(now.Sub(time.Unix(state.LastCheckedUnix, 0)).Truncate(time.Second)) + " ago"
+		}
+		fmt.Fprintf(w, "last check: %s\n", checked)
+		fmt.Fprintf(w, "last seen: %s (%s)\n", valueOrNone(state.LastSeenVersion), valueOrNone(state.LastSeenChannel))
+	}
+	if backups, err := readVersionBackups(); err != nil {
+		fmt.Fprintf(w, "rollback versions: %v\n", err)
+	} else {
+		versions := make([]string, 0, len(backups))
+		for _, b := range backups {
+			versions = append(versions, b.Version)
+		}
+		fmt.Fprintf(w, "rollback versions: %s\n", valueOrNone(strings.Join(versions, ", ")))
+	}
+}
+
+func writeDebugGHCache(w io.Writer, dir string, repoRoot string, branches []string, now time.Time) {
+	ttl := core.NewGHManager().Ttl
+	fmt.Fprintf(w, "dir: %s (fresh for %s)\n", dir, ttl)
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	fresh := 0
+	for _, path := range paths {
+		if fetchedAt, ok := sharedPRCacheFetchedAt(path); ok && now.Sub(fetchedAt) < ttl {
+			fresh++
+		}
+	}
+	fmt.Fprintf(w, "entries: %d, fresh: %d\n", len(paths), fresh)
+	for _, branch := range branches {
+		state := "not cached"
+		if path, err := core.SharedPRCachePath(repoRoot, branch); err == nil {
+			if fetchedAt, ok := sharedPRCacheFetchedAt(path); ok {
+				state = core.FormatTiming(now.Sub(fetchedAt).Truncate(time.Second)) + " old"
+				if now.Sub(fetchedAt) >= ttl {
+					state += ", stale"
+				}
+			}
+		}
+		fmt.Fprintf(w, "branch %s: %s\n", branch, state)
+	}
+}
+
+func sharedPRCacheFetchedAt(path string) (time.Time, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return time.Time{}, false
+	}
+	var entry core.SharedPRCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.FetchedAtUnix <= 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(entry.FetchedAtUnix, 0), true
+}
+
+func writeDebugTmux(w io.Writer) {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		fmt.Fprintln(w, "tmux not on PATH")
+		return
+	}
+	// tmux turns tabs in -F output into underscores; IDs have no spaces.
+	out, err := core.TmuxOutput("list-sessions", "-F", "#{session_id} #{session_name}")
+	if err != nil {
+		fmt.Fprintln(w, "no tmux server")
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		id, name, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(w, "session %s (%s)\n", name, id)
+		if opts, err := core.TmuxOutput("show-options", "-t", id); err == nil {
+			for _, opt := range strings.Split(string(opts), "\n") {
+				if strings.HasPrefix(opt, "@wtx_") {
+					fmt.Fprintf(w, "  %s\n", opt)
+				}
+			}
+		}
+	}
+	windows, err := core.TmuxOutput("list-windows", "-a", "-F", "#{window_id} #{@wtx_worktree_path}")
+	if err != nil {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(windows)), "\n") {
+		if target, path, ok := strings.Cut(line, " "); ok && strings.TrimSpace(path) != "" {
+			fmt.Fprintf(w, "window %s @wtx_worktree_path %s\n", target, path)
+		}
+	}
+}
+
+func valueOrNone(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return "(none)"
+	}
+	return value
+}
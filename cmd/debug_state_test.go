@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+func TestRunDebugState(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("WTX_DISABLE_TMUX", "1")
+	repo := initRenameTestRepo(t)
+	t.Chdir(repo)
+	// The default owner ID is cached per process and may be a tmux one.
+	lock, err := core.NewLockManager().AcquireForOwner(repo, repo, "debug-state-test", os.Getpid())
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	defer lock.Release()
+
+	var out bytes.Buffer
+	if err := runDebugState(&out); err != nil {
+		t.Fatalf("runDebugState: %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{
+		"== config ==",
+		"tmux integration: disabled",
+		"env: WTX_DISABLE_TMUX=1",
+		"worktree: " + repo,
+		"last check: never",
+		"branch master: not cached",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected %q in output:\n%s", want, got)
+		}
+	}
+	locks := got[strings.Index(got, "== locks =="):strings.Index(got, "== update ==")]
+	if !strings.Contains(locks, repo) || !strings.Contains(locks, "true") {
+		t.Fatalf("expected the held lock with its worktree and an active owner:\n%s", locks)
+	}
+}
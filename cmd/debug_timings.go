@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/aixolotls/wtx/internal/core"
+	"github.com/spf13/cobra"
+)
+
+func newDebugCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "debug",
+		Short: "Diagnostics for troubleshooting wtx",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "timings",
+		Short: "Run a status and PR refresh and show where the time goes",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return core.RunDebugTimings(os.Stdout)
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "state",
+		Short: "Print config, locks, caches, tmux options and environment for bug reports",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runDebugState(os.Stdout)
+		},
+	})
+	return cmd
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aixolotls/wtx/internal/core"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const defaultDeleteGrace = 5 * time.Second
+
+// deleteGracePeriod is how long a confirmed delete from the TUI can still be
+// undone before git worktree remove runs.
+func deleteGracePeriod(cfg core.Config) time.Duration {
+	if cfg.DeleteGraceSeconds <= 0 {
+		return defaultDeleteGrace
+	}
+	return time.Duration(cfg.DeleteGraceSeconds) * time.Second
+}
+
+// pendingDelete is a batch of confirmed deletes waiting out the grace period.
+// The worktrees are hidden from the table until the batch runs or is undone.
+type pendingDelete struct {
+	id        int
+	worktrees []core.WorktreeInfo
+	due       time.Time
+	// queued is set when the timer fired while another bulk action was
+	// running; the batch starts once that action finishes.
+	queued bool
+}
+
+type pendingDeleteDueMsg struct {
+	id int
+}
+
+func (m model) scheduleDelete(worktrees []core.WorktreeInfo) (tea.Model, tea.Cmd) {
+	m.nextDeleteID++
+	batch := pendingDelete{id: m.nextDeleteID, worktrees: worktrees, due: time.Now().Add(m.deleteGrace)}
+	m.pendingDeletes = append(append([]pendingDelete(nil), m.pendingDeletes...), batch)
+	m.listIndex = clampListIndex(m.listIndex, m.listStatus())
+	id := batch.id
+	return m, tea.Tick(m.deleteGrace, func(time.Time) tea.Msg {
+		return pendingDeleteDueMsg{id: id}
+	})
+}
+
+func (m model) runDueDelete(id int) (tea.Model, tea.Cmd) {
+	for i, batch := range m.pendingDeletes {
+		if batch.id != id {
+			continue
+		}
+		if m.bulkRunning {
+			batches := append([]pendingDelete(nil), m.pendingDeletes...)
+			batches[i].queued = true
+			m.pendingDeletes = batches
+			return m, nil
+		}
+		m.pendingDeletes = append(append([]pendingDelete(nil), m.pendingDeletes[:i]...), m.pendingDeletes[i+1:]...)
+		m.bulkRunning = true
+		m.bulkAction = bulkDelete
+		return m, bulkActionCmd(m.mgr, m.runner, m.status, bulkDelete, batch.worktrees)
+	}
+	// Already undone.
+	return m, nil
+}
+
+// runQueuedDelete starts the first batch whose timer fired while a bulk
+// action was running.
+func (m model) runQueuedDelete() (tea.Model, tea.Cmd) {
+	for _, batch := range m.pendingDeletes {
+		if batch.queued {
+			return m.runDueDelete(batch.id)
+		}
+	}
+	return m, nil
+}
+
+// undoLastDelete restores the most recently confirmed batch.
+func (m model) undoLastDelete() model {
+	if len(m.pendingDeletes) == 0 {
+		m.errMsg = "Nothing to undo."
+		return m
+	}
+	last := m.pendingDeletes[len(m.pendingDeletes)-1]
+	m.pendingDeletes = append([]pendingDelete(nil), m.pendingDeletes[:len(m.pendingDeletes)-1]...)
+	m.errMsg = ""
+	m.warnMsg = "Restored " + pendingDeleteLabel(last)
+	return m
+}
+
+func (m model) pendingDeletePaths() map[string]bool {
+	if len(m.pendingDeletes) == 0 {
+		return nil
+	}
+	paths := map[string]bool{}
+	for _, batch := range m.pendingDeletes {
+		for _, wt := range batch.worktrees {
+			paths[wt.Path] = true
+		}
+	}
+	return paths
+}
+
+func withoutPendingDeletes(status core.WorktreeStatus, paths map[string]bool) core.WorktreeStatus {
+	if len(paths) == 0 {
+		return status
+	}
+	keep := func(in []core.WorktreeInfo) []core.WorktreeInfo {
+		out := make([]core.WorktreeInfo, 0, len(in))
+		for _, wt := range in {
+			if !paths[wt.Path] {
+				out = append(out, wt)
+			}
+		}
+		return out
+	}
+	status.Worktrees = keep(status.Worktrees)
+	status.Orphaned = keep(status.Orphaned)
+	return status
+}
+
+func pendingDeleteLabel(batch pendingDelete) string {
+	if len(batch.worktrees) == 1 {
+		return batch.worktrees[0].Branch
+	}
+	return fmt.Sprintf("%d worktrees", len(batch.worktrees))
+}
+
+func renderPendingDeletes(m model, now time.Time) string {
+	if len(m.pendingDeletes) == 0 {
+		return ""
+	}
+	last := m.pendingDeletes[len(m.pendingDeletes)-1]
+	left := last.due.Sub(now).Round(time.Second)
+	if left < 0 {
+		left = 0
+	}
+	return warnStyle.Render(fmt.Sprintf("Deleting %s in %s. Press %s to undo.", pendingDeleteLabel(last), core.FormatAgentDuration(left), m.keys.label(keyActionUndo)))
+}
+
+// FlushPendingDeletes runs deletes still inside their grace period when the
+// TUI exits, since the user confirmed them and did not undo. It returns the
+// warnings for the caller to print.
+func (m model) FlushPendingDeletes() []string {
+	var out []string
+	for _, batch := range m.pendingDeletes {
+		for _, wt := range batch.worktrees {
+			branch := strings.TrimSpace(wt.Branch)
+			warnings, err := m.mgr.DeleteWorktree(wt.Path, isOrphanedPath(m.status, wt.Path))
+			if err != nil {
+				out = append(out, fmt.Sprintf("delete %s: %v", branch, err))
+			}
+			for _, w := range warnings {
+				out = append(out, fmt.Sprintf("%s: %s", branch, w))
+			}
+		}
+	}
+	return out
+}
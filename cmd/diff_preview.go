@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aixolotls/wtx/internal/core"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type diffPreviewLoadedMsg struct {
+	path string
+	full bool
+	text string
+	err  error
+}
+
+func loadDiffPreviewCmd(mgr *core.WorktreeManager, path string, baseRef string, full bool) tea.Cmd {
+	return func() tea.Msg {
+		text, err := mgr.DiffPreview(path, baseRef, full)
+		return diffPreviewLoadedMsg{path: path, full: full, text: text, err: err}
+	}
+}
+
+func (m model) openDiffPreview() (tea.Model, tea.Cmd) {
+	row, ok := selectedWorktree(m.listStatus(), m.listIndex)
+	if !ok {
+		return m, nil
+	}
+	if isOrphanedPath(m.status, row.Path) {
+		m.errMsg = "Cannot preview orphaned worktree."
+		return m, nil
+	}
+	m.mode = modePreview
+	m.previewPath = row.Path
+	m.previewBranch = row.Branch
+	m.previewFull = false
+	m.previewLines = nil
+	m.previewOffset = 0
+	m.previewLoading = true
+	m.errMsg = ""
+	return m, loadDiffPreviewCmd(m.mgr, row.Path, m.previewBaseRef(), false)
+}
+
+func (m model) previewBaseRef() string {
+	return resolveNewBranchBaseRef(m.openDefaultBaseRef, m.status.BaseRef, m.status.HasRemote)
+}
+
+func (m model) applyDiffPreview(msg diffPreviewLoadedMsg) model {
+	if m.mode != modePreview || msg.path != m.previewPath || msg.full != m.previewFull {
+		return m
+	}
+	m.previewLoading = false
+	if msg.err != nil {
+		m.previewLines = []string{"Error: " + msg.err.Error()}
+		return m
+	}
+	m.previewLines = strings.Split(strings.TrimRight(msg.text, "\n"), "\n")
+	m.previewOffset = 0
+	return m
+}
+
+// previewPageSize is how many diff lines fit between the header and footer.
+func (m model) previewPageSize() int {
+	if m.height <= 0 {
+		return 20
+	}
+	return max(1, m.height-5)
+}
+
+func (m model) updateDiffPreview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	maxOffset := max(0, len(m.previewLines)-m.previewPageSize())
+	key := msg.String()
+	if m.keys.translateTableKey(key) == "p" {
+		key = "esc"
+	}
+	switch key {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q":
+		m.mode = modeList
+		m.previewLines = nil
+		m.previewPath = ""
+		m.previewBranch = ""
+		return m, nil
+	case "tab":
+		m.previewFull = !m.previewFull
+		m.previewLoading = true
+		m.previewOffset = 0
+		return m, loadDiffPreviewCmd(m.mgr, m.previewPath, m.previewBaseRef(), m.previewFull)
+	case "up", "k":
+		m.previewOffset--
+	case "down", "j":
+		m.previewOffset++
+	case "pgup", "ctrl+u":
+		m.previewOffset -= m.previewPageSize()
+	case "pgdown", "ctrl+f", " ":
+		m.previewOffset += m.previewPageSize()
+	case "g", "home":
+		m.previewOffset = 0
+	case "G", "end":
+		m.previewOffset = maxOffset
+	}
+	m.previewOffset = min(max(m.previewOffset, 0), maxOffset)
+	return m, nil
+}
+
+func renderDiffPreview(m model) string {
+	var b strings.Builder
+	title := "Changes in " + branchInlineStyle.Render(m.previewBranch)
+	if m.previewFull {
+		title = "Diff of " + branchInlineStyle.Render(m.previewBranch) + " vs " + m.previewBaseRef()
+	}
+	b.WriteString(title + "\n")
+	b.WriteString(secondaryStyle.Render(truncatePathForWidth(m.previewPath, m.width, 0)))
+	b.WriteString("\n\n")
+	if m.previewLoading {
+		b.WriteString(secondaryStyle.Render("Loading..."))
+		b.WriteString("\n")
+	} else {
+		end := min(len(m.previewLines), m.previewOffset+m.previewPageSize())
+		for _, line := range m.previewLines[m.previewOffset:end] {
+			b.WriteString(renderDiffLine(line, m.width))
+			b.WriteString("\n")
+		}
+	}
+	footer := "tab toggles the full diff vs base, ↑/↓ scroll, esc to close."
+	if total := len(m.previewLines); total > m.previewPageSize() {
+		footer = fmt.Sprintf("Lines %d-%d of %d. %s", m.previewOffset+1, min(total, m.previewOffset+m.previewPageSize()), total, footer)
+	}
+	b.WriteString(secondaryStyle.Render(footer))
+	b.WriteString("\n")
+	return b.String()
+}
+
+func renderDiffLine(line string, width int) string {
+	line = strings.ReplaceAll(line, "\t", "    ")
+	if width > 0 {
+		if runes := []rune(line); len(runes) > width {
+			line = string(runes[:width])
+		}
+	}
+	switch {
+	case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"), strings.HasPrefix(line, "diff --git"):
+		return selectorHeaderStyle.Render(line)
+	case strings.HasPrefix(line, "@@"):
+		return titleStyle.Render(line)
+	case strings.HasPrefix(line, "+"):
+		return diffAddStyle.Render(line)
+	case strings.HasPrefix(line, "-"):
+		return diffRemoveStyle.Render(line)
+	default:
+		return actionNormalStyle.Render(line)
+	}
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aixolotls/wtx/internal/core"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestDiffPreviewPaneScrollsAndCloses(t *testing.T) {
+	m := newModel()
+	m.mode = modeList
+	m.ready = true
+	m.height = 10
+	m.status = core.WorktreeStatus{
+		InRepo:       true,
+		GitInstalled: true,
+		Worktrees:    []core.WorktreeInfo{{Path: "/repo/wt.1", Branch: "feature", Available: true}},
+	}
+
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	updated := updatedModel.(model)
+	if updated.mode != modePreview || cmd == nil {
+		t.Fatalf("expected p to open the diff preview")
+	}
+	lines := make([]string, 30)
+	for i := range lines {
+		lines[i] = " file" + string(rune('a'+i%26)) + " | 1 +"
+	}
+	updatedModel, _ = updated.Update(diffPreviewLoadedMsg{path: "/repo/wt.1", text: strings.Join(lines, "\n")})
+	updated = updatedModel.(model)
+	updatedModel, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'G'}})
+	updated = updatedModel.(model)
+	if want := 30 - updated.previewPageSize(); updated.previewOffset != want {
+		t.Fatalf("expected G to scroll to offset %d, got %d", want, updated.previewOffset)
+	}
+	if !strings.Contains(updated.View(), "of 30") {
+		t.Fatalf("expected scroll position in footer, got:\n%s", updated.View())
+	}
+
+	updatedModel, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if updatedModel.(model).mode != modeList {
+		t.Fatalf("expected esc to close the preview")
+	}
+}
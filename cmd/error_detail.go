@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/aixolotls/wtx/internal/core"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// errorDetail is what the error panel shows for the current errMsg.
+type errorDetail struct {
+	Message string
+	Command string
+	Dir     string
+	Output  string
+	Hint    string
+}
+
+// setError shows err on the status line and keeps it so the error panel can
+// show the command behind it.
+func (m model) setError(err error) model {
+	m.errMsg = err.Error()
+	m.errDetail = err
+	return m
+}
+
+// currentErrorDetail describes errMsg, using the kept error only when it is
+// still the one on screen.
+func currentErrorDetail(m model) (errorDetail, bool) {
+	msg := strings.TrimSpace(m.errMsg)
+	if msg == "" {
+		return errorDetail{}, false
+	}
+	detail := errorDetail{Message: msg}
+	var cmdErr *core.CommandError
+	if m.errDetail != nil && m.errDetail.Error() == m.errMsg && errors.As(m.errDetail, &cmdErr) {
+		detail.Command = cmdErr.Command
+		detail.Dir = cmdErr.Dir
+		detail.Output = cmdErr.Output
+		if detail.Output == "" {
+			detail.Output = cmdErr.Err.Error()
+		}
+	}
+	detail.Hint = errorRemediation(msg + "\n" + detail.Output)
+	return detail, true
+}
+
+var errorRemediations = []struct {
+	needles []string
+	hint    string
+}{
+	{[]string{"timed out"}, "The command did not finish in time. Check your network connection and retry with r."},
+	{[]string{"could not read username", "authentication failed", "terminal prompts disabled", "permission denied (publickey)"}, "Git could not authenticate with the remote. Check your credentials or SSH keys, or run gh auth login."},
+	{[]string{"gh auth login", "not logged into"}, "Run gh auth login, then refresh."},
+	{[]string{"already checked out", "is already used by worktree"}, "The branch is checked out in another worktree. Open that worktree instead, or pick a different branch."},
+	{[]string{"already exists"}, "A branch or path with that name already exists. Pick another name or delete the old one first."},
+	{[]string{"invalid reference", "not a valid object name", "couldn't find remote ref", "unknown revision"}, "The base ref is missing locally. Fetch the remote, or choose a different base branch."},
+	{[]string{"contains modified or untracked files", "uncommitted changes", "would be overwritten"}, "The worktree has local changes. Commit or stash them first."},
+	{[]string{"index.lock"}, "Another git process holds the index lock. Wait for it to finish, or remove the stale .git/index.lock."},
+	{[]string{"worktree locked"}, "Another wtx session is using this worktree. Unlock it with u if that session is gone."},
+	{[]string{"not a git repository"}, "Run wtx from inside a git repository."},
+	{[]string{"hook failed"}, "A wtx hook exited non-zero. Check hooks in your wtx config or .wtx.json."},
+	{[]string{"bootstrap command failed"}, "Check bootstrap_command in your wtx config; it failed in the new worktree."},
+}
+
+func errorRemediation(text string) string {
+	lower := strings.ToLower(text)
+	for _, r := range errorRemediations {
+		for _, needle := range r.needles {
+			if strings.Contains(lower, needle) {
+				return r.hint
+			}
+		}
+	}
+	return ""
+}
+
+func errorDetailText(d errorDetail) string {
+	var b strings.Builder
+	b.WriteString("Error: " + d.Message + "\n")
+	if d.Command != "" {
+		b.WriteString("Command: " + d.Command + "\n")
+	}
+	if d.Dir != "" {
+		b.WriteString("Directory: " + d.Dir + "\n")
+	}
+	if d.Output != "" && d.Output != d.Message {
+		b.WriteString("Output:\n" + d.Output + "\n")
+	}
+	if d.Hint != "" {
+		b.WriteString("Suggestion: " + d.Hint + "\n")
+	}
+	return b.String()
+}
+
+func (m model) updateErrorDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "c", "y":
+		detail, ok := currentErrorDetail(m)
+		if !ok {
+			m.showErrorDetail = false
+			return m, nil
+		}
+		if err := m.runner.CopyToClipboard(errorDetailText(detail)); err != nil {
+			m.errorDetailNote = "Copy failed: " + err.Error()
+		} else {
+			m.errorDetailNote = "Copied to clipboard."
+		}
+		return m, nil
+	}
+	m.showErrorDetail = false
+	m.errorDetailNote = ""
+	return m, nil
+}
+
+func renderErrorDetail(m model) string {
+	detail, _ := currentErrorDetail(m)
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Error details"))
+	b.WriteString("\n\n")
+	b.WriteString(errorStyle.Render(detail.Message))
+	b.WriteString("\n")
+	field := func(label string, value string) {
+		if value == "" {
+			return
+		}
+		b.WriteString("\n")
+		b.WriteString(selectorHeaderStyle.Render(label))
+		b.WriteString("\n")
+		b.WriteString(value)
+		b.WriteString("\n")
+	}
+	field("Command", detail.Command)
+	field("Directory", detail.Dir)
+	if detail.Output != detail.Message {
+		field("Output", detail.Output)
+	}
+	field("Suggestion", detail.Hint)
+	b.WriteString("\n")
+	if m.errorDetailNote != "" {
+		b.WriteString(secondaryStyle.Render(m.errorDetailNote))
+		b.WriteString("\n")
+	}
+	b.WriteString(secondaryStyle.Render("Press c to copy, any other key to close."))
+	b.WriteString("\n")
+	return b.String()
+}
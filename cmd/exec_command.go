@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aixolotls/wtx/internal/core"
+	"github.com/spf13/cobra"
+)
+
+type execTargetFilter struct {
+	All      bool
+	Free     bool
+	Branches []string
+}
+
+type execResult struct {
+	Worktree core.WorktreeInfo
+	ExitCode int
+	Err      error
+	Duration time.Duration
+}
+
+func newExecCommand() *cobra.Command {
+	var filter execTargetFilter
+	var jobs int
+	cmd := &cobra.Command{
+		Use:   "exec [--all | --free | --branch <glob>] -- <command>...",
+		Short: "Run a command in each matching worktree",
+		Example: strings.Join([]string{
+			"  wtx exec --all -- git status --short",
+			"  wtx exec --free -j 4 -- make test",
+			"  wtx exec --branch 'feature/*' -- 'git fetch && git rebase origin/main'",
+		}, "\n"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !filter.All && !filter.Free && len(filter.Branches) == 0 {
+				return usageError(cmd, "pass --all, --free or --branch <glob>")
+			}
+			if cmd.ArgsLenAtDash() != 0 || len(args) == 0 {
+				return usageError(cmd, "missing command after --")
+			}
+			if jobs < 1 {
+				return usageError(cmd, "-j must be at least 1")
+			}
+			return runExec(os.Stdout, filter, jobs, shellCommandFromArgs(args))
+		},
+	}
+	cmd.Flags().BoolVar(&filter.All, "all", false, "Run in every worktree")
+	cmd.Flags().BoolVar(&filter.Free, "free", false, "Only run in worktrees that are not in use")
+	cmd.Flags().StringArrayVar(&filter.Branches, "branch", nil, "Only run in worktrees whose branch matches this glob (repeatable)")
+	cmd.Flags().IntVarP(&jobs, "jobs", "j", 1, "Number of worktrees to run in parallel")
+	return cmd
+}
+
+func runExec(w io.Writer, filter execTargetFilter, jobs int, shellCmd string) error {
+	lockMgr := core.NewLockManager()
+	orchestrator := core.NewWorktreeOrchestrator(core.NewWorktreeManager("", lockMgr), lockMgr, nil)
+	status := orchestrator.Status()
+	if status.Err != nil {
+		return status.Err
+	}
+	if !status.InRepo {
+		return core.ErrNotInGitRepository
+	}
+	targets, err := execTargets(status, filter)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		fmt.Fprintln(w, "No matching worktrees.")
+		return nil
+	}
+
+	var mu sync.Mutex
+	results := make([]execResult, len(targets))
+	sem := make(chan struct{}, jobs)
+	var wg sync.WaitGroup
+	for i, wt := range targets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, wt core.WorktreeInfo) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			out := &prefixWriter{w: w, mu: &mu, prefix: "[" + batchLabel(batchRunResult{Branch: wt.Branch, Path: wt.Path}) + "] "}
+			results[i] = runExecInWorktree(wt, shellCmd, out)
+			out.Flush()
+		}(i, wt)
+	}
+	wg.Wait()
+
+	failed := 0
+	fmt.Fprintln(w)
+	for _, r := range results {
+		label := batchLabel(batchRunResult{Branch: r.Worktree.Branch, Path: r.Worktree.Path})
+		state := fmt.Sprintf("exit %d", r.ExitCode)
+		if r.Err != nil {
+			state = "error: " + r.Err.Error()
+		}
+		if r.Err != nil || r.ExitCode != 0 {
+			failed++
+		}
+		fmt.Fprintf(w, "%-40s %s (%s)\n", label, state, core.FormatAgentDuration(r.Duration))
+	}
+	if failed > 0 {
+		return fmt.Errorf("command failed in %d of %d worktree(s)", failed, len(results))
+	}
+	return nil
+}
+
+func runExecInWorktree(wt core.WorktreeInfo, shellCmd string, out io.Writer) execResult {
+	started := time.Now()
+	cmd := core.ShellScriptCommand(shellCmd, false)
+	cmd.Dir = wt.Path
+	cmd.Stdout = out
+	cmd.Stderr = out
+	err := cmd.Run()
+	result := execResult{Worktree: wt, Duration: time.Since(started)}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		result.ExitCode = exitErr.ExitCode()
+	} else if err != nil {
+		result.Err = err
+	}
+	return result
+}
+
+// execTargets returns non-orphaned, unbroken worktrees matching filter. Branch globs and
+// --free narrow the set; --all alone selects everything.
+func execTargets(status core.WorktreeStatus, filter execTargetFilter) ([]core.WorktreeInfo, error) {
+	for _, pattern := range filter.Branches {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid --branch %q: %w", pattern, err)
+		}
+	}
+	orphaned := make(map[string]bool, len(status.Orphaned))
+	for _, wt := range status.Orphaned {
+		orphaned[wt.Path] = true
+	}
+	out := make([]core.WorktreeInfo, 0, len(status.Worktrees))
+	for _, wt := range status.Worktrees {
+		if orphaned[wt.Path] || wt.Broken != "" {
+			continue
+		}
+		if filter.Free && !wt.Available {
+			continue
+		}
+		if len(filter.Branches) > 0 && !branchMatchesAny(wt.Branch, filter.Branches) {
+			continue
+		}
+		out = append(out, wt)
+	}
+	return out, nil
+}
+
+func branchMatchesAny(branch string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, branch); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// prefixWriter writes complete lines to w with prefix, holding partial lines
+// until the next newline or Flush so parallel output does not interleave.
+type prefixWriter struct {
+	w      io.Writer
+	mu     *sync.Mutex
+	prefix string
+	buf    bytes.Buffer
+}
+
+func (p *prefixWriter) Write(data []byte) (int, error) {
+	p.buf.Write(data)
+	for {
+		line, err := p.buf.ReadBytes('\n')
+		if err != nil {
+			p.buf.Write(line)
+			break
+		}
+		p.emit(line)
+	}
+	return len(data), nil
+}
+
+func (p *prefixWriter) Flush() {
+	if p.buf.Len() == 0 {
+		return
+	}
+	line := append(p.buf.Bytes(), '\n')
+	p.buf.Reset()
+	p.emit(line)
+}
+
+func (p *prefixWriter) emit(line []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, _ = io.WriteString(p.w, p.prefix)
+	_, _ = p.w.Write(line)
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+func TestPrefixWriterBuffersPartialLines(t *testing.T) {
+	var out bytes.Buffer
+	w := &prefixWriter{w: &out, mu: &sync.Mutex{}, prefix: "[a] "}
+	_, _ = w.Write([]byte("one\ntw"))
+	_, _ = w.Write([]byte("o\nthree"))
+	w.Flush()
+	want := "[a] one\n[a] two\n[a] three\n"
+	if out.String() != want {
+		t.Fatalf("expected %q, got %q", want, out.String())
+	}
+}
+
+func TestExecTargets(t *testing.T) {
+	status := core.WorktreeStatus{
+		Worktrees: []core.WorktreeInfo{
+			{Path: "/a", Branch: "feature/a", Available: true},
+			{Path: "/b", Branch: "feature/b", Available: false},
+			{Path: "/c", Branch: "fix/c", Available: true},
+			{Path: "/d", Branch: "feature/d", Available: false},
+		},
+		Orphaned: []core.WorktreeInfo{{Path: "/d", Branch: "feature/d"}},
+	}
+	cases := []struct {
+		name   string
+		filter execTargetFilter
+		want   []string
+	}{
+		{name: "all", filter: execTargetFilter{All: true}, want: []string{"/a", "/b", "/c"}},
+		{name: "free", filter: execTargetFilter{Free: true}, want: []string{"/a", "/c"}},
+		{name: "branch", filter: execTargetFilter{Branches: []string{"feature/*"}}, want: []string{"/a", "/b"}},
+		{name: "free branch", filter: execTargetFilter{Free: true, Branches: []string{"feature/*"}}, want: []string{"/a"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := execTargets(status, tc.filter)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("expected %v, got %+v", tc.want, got)
+			}
+			for i := range got {
+				if got[i].Path != tc.want[i] {
+					t.Fatalf("expected %v, got %+v", tc.want, got)
+				}
+			}
+		})
+	}
+}
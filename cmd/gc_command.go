@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aixolotls/wtx/internal/core"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+)
+
+const (
+	gcDelete = "delete"
+	gcWarn   = "warn"
+	// gcStartupInterval throttles gc_on_startup to one run per repo a day.
+	gcStartupInterval = 24 * time.Hour
+)
+
+// gcFinding is what the cleanup policies decided for one worktree.
+type gcFinding struct {
+	Path     string
+	Branch   string
+	Action   string
+	Reason   string
+	Err      error
+	Warnings []string
+}
+
+func gcPoliciesSet(cfg core.Config) bool {
+	return cfg.GCMergedDays > 0 || cfg.GCUntouchedDays > 0
+}
+
+// evaluateGCPolicies applies gc_merged_days (delete worktrees whose PR was
+// merged that many days ago) and gc_untouched_days (warn about worktrees not
+// used or committed to for that long) to the managed worktrees.
+func evaluateGCPolicies(cfg core.Config, status core.WorktreeStatus, prs map[string]core.PRData, now time.Time) []gcFinding {
+	var findings []gcFinding
+	for _, wt := range status.Worktrees {
+		if core.EnsureManagedWorktreePath(status.RepoRoot, wt.Path) != nil {
+			continue
+		}
+		if pr, ok := prs[wt.Branch]; ok && cfg.GCMergedDays > 0 && pr.BaseStatus == "merged" && pr.MergedAtUnix > 0 {
+			if days := daysSince(pr.MergedAtUnix, now); days >= cfg.GCMergedDays {
+				f := gcFinding{Path: wt.Path, Branch: wt.Branch, Action: gcDelete, Reason: fmt.Sprintf("PR #%d merged %d days ago", pr.Number, days)}
+				if blocker := gcDeleteBlocker(wt); blocker != "" {
+					f.Action = gcWarn
+					f.Reason += ", kept because it is " + blocker
+				}
+				findings = append(findings, f)
+				continue
+			}
+		}
+		last := wt.LastUsedUnix
+		if wt.LastCommit.CommittedUnix > last {
+			last = wt.LastCommit.CommittedUnix
+		}
+		if cfg.GCUntouchedDays > 0 && last > 0 {
+			if days := daysSince(last, now); days >= cfg.GCUntouchedDays {
+				findings = append(findings, gcFinding{Path: wt.Path, Branch: wt.Branch, Action: gcWarn, Reason: fmt.Sprintf("untouched for %d days", days)})
+			}
+		}
+	}
+	return findings
+}
+
+func gcDeleteBlocker(wt core.WorktreeInfo) string {
+	switch {
+	case wt.Pinned:
+		return "pinned"
+	case !wt.Available:
+		return "in use"
+	case wt.Broken != "":
+		return "broken"
+	default:
+		return ""
+	}
+}
+
+func daysSince(unix int64, now time.Time) int {
+	return int(now.Sub(time.Unix(unix, 0)) / (24 * time.Hour))
+}
+
+// runGC evaluates the policies against the repo and, unless dryRun, deletes
+// the worktrees they select. A worktree git refuses to remove, for example
+// one with uncommitted changes, is reported with its error.
+func runGC(orchestrator *core.WorktreeOrchestrator, mgr *core.WorktreeManager, cfg core.Config, now time.Time, dryRun bool) ([]gcFinding, error) {
+	status := orchestrator.Status()
+	if status.Err != nil {
+		return nil, status.Err
+	}
+	if !status.InRepo {
+		return nil, core.ErrNotInGitRepository
+	}
+	var prs map[string]core.PRData
+	if cfg.GCMergedDays > 0 {
+		var err error
+		if prs, err = orchestrator.PRDataForStatusWithError(status, false); err != nil {
+			return nil, err
+		}
+	}
+	findings := evaluateGCPolicies(cfg, status, prs, now)
+	if dryRun {
+		return findings, nil
+	}
+	for i, f := range findings {
+		if f.Action == gcDelete {
+			findings[i].Warnings, findings[i].Err = mgr.DeleteWorktree(f.Path, false)
+		}
+	}
+	return findings, nil
+}
+
+func newGCCommand() *cobra.Command {
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "Apply the cleanup policies to this repo's worktrees",
+		Long: "Deletes worktrees whose PR was merged at least gc_merged_days ago and warns about worktrees untouched for gc_untouched_days.\n\n" +
+			"Pinned, in-use and broken worktrees are never deleted. Set gc_on_startup to run it once a day when wtx starts.",
+		Example: strings.Join([]string{
+			"  wtx config set gc_merged_days 14",
+			"  wtx gc --dry-run",
+		}, "\n"),
+		Args: cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runGCCommand(os.Stdout, dryRun)
+		},
+	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List what the policies would do without deleting anything")
+	return cmd
+}
+
+func runGCCommand(w io.Writer, dryRun bool) error {
+	cfg, err := core.LoadConfig()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if !gcPoliciesSet(cfg) {
+		fmt.Fprintln(w, "No cleanup policies set; set gc_merged_days or gc_untouched_days with wtx config set.")
+		return nil
+	}
+	lockMgr := core.NewLockManager()
+	mgr := core.NewWorktreeManager("", lockMgr)
+	findings, err := runGC(core.NewWorktreeOrchestrator(mgr, lockMgr, core.NewGHManager()), mgr, cfg, time.Now(), dryRun)
+	if err != nil {
+		return err
+	}
+	deleted, warned, failed := 0, 0, 0
+	for _, f := range findings {
+		verb := "Warning"
+		switch {
+		case f.Action == gcWarn:
+			warned++
+		case f.Err != nil:
+			failed++
+			verb = "Could not delete"
+		case dryRun:
+			deleted++
+			verb = "Would delete"
+		default:
+			deleted++
+			verb = "Deleted"
+		}
+		fmt.Fprintf(w, "%s %s (%s): %s\n", verb, f.Path, f.Branch, f.Reason)
+		if f.Err != nil {
+			fmt.Fprintf(w, "  %v\n", f.Err)
+		}
+		for _, warning := range f.Warnings {
+			fmt.Fprintf(w, "  warning: %s\n", warning)
+		}
+	}
+	verb := "Deleted"
+	if dryRun {
+		verb = "Would delete"
+	}
+	fmt.Fprintf(w, "%s %d worktrees, %d warnings.\n", verb, deleted, warned)
+	if failed > 0 {
+		return fmt.Errorf("%d worktrees could not be deleted", failed)
+	}
+	return nil
+}
+
+type gcDoneMsg struct {
+	findings []gcFinding
+	err      error
+}
+
+// startupGCCmd runs gc for real when gc_on_startup is set and it has not
+// run for the repo in the last day.
+func startupGCCmd(orchestrator *core.WorktreeOrchestrator, mgr *core.WorktreeManager) tea.Cmd {
+	return func() tea.Msg {
+		cfg, err := core.LoadConfig()
+		if err != nil || !cfg.GCOnStartup || !gcPoliciesSet(cfg) || mgr == nil {
+			return gcDoneMsg{}
+		}
+		_, repoRoot, err := core.RequireGitContext(mgr.Cwd)
+		if err != nil {
+			return gcDoneMsg{}
+		}
+		home, err := core.WtxHomeDir()
+		if err != nil {
+			return gcDoneMsg{}
+		}
+		stamp := filepath.Join(home, "gc", core.HashString(core.AuditRepo(repoRoot))[:16])
+		if info, err := os.Stat(stamp); err == nil && time.Since(info.ModTime()) < gcStartupInterval {
+			return gcDoneMsg{}
+		}
+		findings, err := runGC(orchestrator, mgr, cfg, time.Now(), false)
+		// Only a completed run counts, so a failure such as gh being
+		// unavailable is retried on the next start.
+		if err == nil {
+			if mkErr := os.MkdirAll(filepath.Dir(stamp), 0o755); mkErr == nil {
+				_ = os.WriteFile(stamp, nil, 0o644)
+			}
+		}
+		return gcDoneMsg{findings: findings, err: err}
+	}
+}
+
+// gcSummary is the status line for a startup gc run, or "" when it found
+// nothing.
+func gcSummary(findings []gcFinding) string {
+	deleted, warned, failed := 0, 0, 0
+	for _, f := range findings {
+		switch {
+		case f.Action == gcWarn:
+			warned++
+		case f.Err != nil:
+			failed++
+		default:
+			deleted++
+		}
+	}
+	if deleted+warned+failed == 0 {
+		return ""
+	}
+	summary := fmt.Sprintf("gc: deleted %d worktrees, %d warnings", deleted, warned)
+	if failed > 0 {
+		summary += fmt.Sprintf(", %d could not be deleted", failed)
+	}
+	return summary + "; run wtx gc --dry-run for details."
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+func TestEvaluateGCPolicies(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo := initRenameTestRepo(t)
+	lockMgr := core.NewLockManager()
+	mgr := core.NewWorktreeManager(repo, lockMgr)
+	for _, branch := range []string{"merged", "pinned", "stale", "fresh"} {
+		if _, err := mgr.CreateWorktree(branch, "", nil); err != nil {
+			t.Fatalf("create %s: %v", branch, err)
+		}
+	}
+	status := core.NewWorktreeOrchestrator(mgr, lockMgr, nil).Status()
+	now := time.Now()
+	old := now.Add(-40 * 24 * time.Hour).Unix()
+	for i := range status.Worktrees {
+		wt := &status.Worktrees[i]
+		wt.Available = true
+		switch wt.Branch {
+		case "pinned":
+			wt.Pinned = true
+		case "stale", "merged":
+			wt.LastUsedUnix = old
+			wt.LastCommit.CommittedUnix = old
+		case "fresh":
+			wt.LastUsedUnix = old
+		}
+	}
+	mergedAt := now.Add(-20 * 24 * time.Hour).Unix()
+	prs := map[string]core.PRData{
+		"merged": {Number: 1, BaseStatus: "merged", MergedAtUnix: mergedAt},
+		"pinned": {Number: 2, BaseStatus: "merged", MergedAtUnix: mergedAt},
+		"fresh":  {Number: 3, BaseStatus: "merged", MergedAtUnix: now.Unix()},
+	}
+	cfg := core.Config{GCMergedDays: 14, GCUntouchedDays: 30}
+
+	got := map[string]gcFinding{}
+	for _, f := range evaluateGCPolicies(cfg, status, prs, now) {
+		got[f.Branch] = f
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected findings for merged, pinned and stale, got %+v", got)
+	}
+	if f := got["merged"]; f.Action != gcDelete || f.Reason != "PR #1 merged 20 days ago" {
+		t.Fatalf("merged = %+v", f)
+	}
+	if f := got["pinned"]; f.Action != gcWarn || !strings.Contains(f.Reason, "kept because it is pinned") {
+		t.Fatalf("pinned = %+v", f)
+	}
+	if f := got["stale"]; f.Action != gcWarn || f.Reason != "untouched for 40 days" {
+		t.Fatalf("stale = %+v", f)
+	}
+	if summary := gcSummary(evaluateGCPolicies(cfg, status, prs, now)); summary != "gc: deleted 1 worktrees, 2 warnings; run wtx gc --dry-run for details." {
+		t.Fatalf("summary = %q", summary)
+	}
+}
+
+func TestStartupGCStampsOnlyCompletedRuns(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := core.SaveConfig(core.Config{GCOnStartup: true, GCUntouchedDays: 30}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	repo := initRenameTestRepo(t)
+	lockMgr := core.NewLockManager()
+	mgr := core.NewWorktreeManager(repo, lockMgr)
+	newOrchestrator := func(dir string) *core.WorktreeOrchestrator {
+		o := core.NewWorktreeOrchestrator(core.NewWorktreeManager(dir, lockMgr), lockMgr, nil)
+		o.Daemon = false
+		return o
+	}
+	stamps := func() int {
+		entries, _ := os.ReadDir(filepath.Join(home, ".wtx", "gc"))
+		return len(entries)
+	}
+
+	// Status outside a repo fails, standing in for gh or git being down.
+	if msg := startupGCCmd(newOrchestrator(t.TempDir()), mgr)().(gcDoneMsg); msg.err == nil {
+		t.Fatalf("expected the startup run to fail")
+	}
+	if n := stamps(); n != 0 {
+		t.Fatalf("expected no stamp after a failed run, got %d", n)
+	}
+	if msg := startupGCCmd(newOrchestrator(repo), mgr)().(gcDoneMsg); msg.err != nil {
+		t.Fatalf("startup run: %v", msg.err)
+	}
+	if n := stamps(); n != 1 {
+		t.Fatalf("expected a stamp after a completed run, got %d", n)
+	}
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/aixolotls/wtx/internal/core"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func ghAuthWarning(keys keymap, authErr *core.GHAuthError) string {
+	fix := "gh " + strings.Join(authErr.FixArgs(), " ")
+	if len(authErr.Scopes) > 0 {
+		return fmt.Sprintf("gh token lacks %s — press %s to run %s", strings.Join(authErr.Scopes, ", "), keys.label(keyActionGHAuth), fix)
+	}
+	return fmt.Sprintf("gh not authenticated — press %s to run %s", keys.label(keyActionGHAuth), fix)
+}
+
+type ghAuthDoneMsg struct {
+	err error
+	// inPane is set when the login runs in a tmux split and is still going.
+	inPane bool
+}
+
+// runGHAuthFixCmd runs the login flow in a split below wtx when inside tmux,
+// otherwise in the foreground with the UI suspended until gh exits.
+func runGHAuthFixCmd(dir string, authErr *core.GHAuthError) tea.Cmd {
+	ghPath, err := exec.LookPath("gh")
+	if err != nil {
+		return func() tea.Msg { return ghAuthDoneMsg{err: err} }
+	}
+	args := authErr.FixArgs()
+	if tmuxAvailable() {
+		return func() tea.Msg {
+			script := core.ShellQuote(ghPath)
+			for _, arg := range args {
+				script += " " + core.ShellQuote(arg)
+			}
+			script += " || { echo; printf 'Press enter to close. '; read _; }"
+			paneID, err := splitCommandPane(dir, script)
+			if err == nil {
+				_ = core.TmuxRun("select-pane", "-t", paneID)
+			}
+			return ghAuthDoneMsg{err: err, inPane: true}
+		}
+	}
+	cmd := exec.Command(ghPath, args...)
+	cmd.Dir = dir
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return ghAuthDoneMsg{err: err}
+	})
+}
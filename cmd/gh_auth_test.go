@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aixolotls/wtx/internal/core"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestGHAuthWarningOffersFix(t *testing.T) {
+	t.Setenv("TMUX", "")
+	m := newModel()
+	m.mode = modeList
+	m.ready = true
+	m.status = core.WorktreeStatus{
+		InRepo:       true,
+		GitInstalled: true,
+		RepoRoot:     "/repo",
+		HasRemote:    true,
+		Worktrees:    []core.WorktreeInfo{{Path: "/repo/wt.1", Branch: "feature", Available: true}},
+	}
+	key := ghDataKeyForStatus(m.status)
+	m.ghFetchingKey = key
+	authErr := core.GHAuthErrorFromOutput("To get started with GitHub CLI, please run:  gh auth login")
+	updatedModel, _ := m.Update(ghDataMsg{
+		repoRoot:        "/repo",
+		key:             key,
+		fetchedByBranch: true,
+		byBranch:        map[string]core.PRData{},
+		err:             fmt.Errorf("fetch: %w", authErr),
+	})
+	updated := updatedModel.(model)
+	if !strings.Contains(updated.View(), "gh not authenticated — press a to run gh auth login") {
+		t.Fatalf("expected the auth hint, got:\n%s", updated.View())
+	}
+	if _, cmd := updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")}); cmd == nil {
+		t.Fatalf("expected a to start the login flow")
+	}
+
+	updatedModel, _ = updated.Update(ghAuthDoneMsg{})
+	updated = updatedModel.(model)
+	if updated.ghAuth != nil || updated.ghWarnMsg != "" || !updated.forceGHRefresh {
+		t.Fatalf("expected a finished login to clear the hint and refresh PR data")
+	}
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aixolotls/wtx/internal/core"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultGitAliasName = "wt"
+	gitAliasPrefix      = `!f() { cd "${GIT_PREFIX:-.}" && exec `
+)
+
+func newInstallGitAliasCommand() *cobra.Command {
+	var name string
+	var local bool
+	var force bool
+	var uninstall bool
+	cmd := &cobra.Command{
+		Use:   "install-git-alias",
+		Short: "Make `git wt` run wtx",
+		Long: "Adds a git alias that runs wtx from the directory git was invoked in, so `git wt`\n" +
+			"and git GUIs that expose aliases reach wtx with the right repo context.",
+		Example: strings.Join([]string{
+			"  wtx install-git-alias",
+			"  git wt list",
+			"  wtx install-git-alias --name worktree --local",
+		}, "\n"),
+		Args: cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if uninstall {
+				return runUninstallGitAlias(name, local)
+			}
+			return runInstallGitAlias(name, local, force)
+		},
+	}
+	cmd.Flags().StringVar(&name, "name", defaultGitAliasName, "Alias name")
+	cmd.Flags().BoolVar(&local, "local", false, "Install for the current repository only")
+	cmd.Flags().BoolVar(&force, "force", false, "Replace an existing alias with the same name")
+	cmd.Flags().BoolVar(&uninstall, "uninstall", false, "Remove the alias")
+	return cmd
+}
+
+// gitAliasValue runs wtx from GIT_PREFIX: git runs shell aliases from the
+// top of the worktree, which would lose a subdirectory like `wtx ide` cares
+// about.
+func gitAliasValue(bin string) string {
+	return gitAliasPrefix + core.ShellQuote(bin) + ` "$@"; }; f`
+}
+
+// gitAliasCurrent returns the alias set under key, or "" when there is none.
+func gitAliasCurrent(local bool, key string) string {
+	current, err := core.GitOutputInDir("", "git", append(gitAliasScopeArgs(local), "--get", key)...)
+	if err != nil {
+		return ""
+	}
+	return current
+}
+
+func gitAliasScopeArgs(local bool) []string {
+	if local {
+		return []string{"config", "--local"}
+	}
+	return []string{"config", "--global"}
+}
+
+func runInstallGitAlias(name string, local bool, force bool) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("alias name required")
+	}
+	bin := resolveStatusCommandBinary()
+	if bin == "" {
+		bin = "wtx"
+	}
+	key := "alias." + name
+	value := gitAliasValue(bin)
+	// An alias pointing at a moved wtx binary is ours to replace.
+	if current := gitAliasCurrent(local, key); current != "" && !strings.HasPrefix(current, gitAliasPrefix) && !force {
+		return fmt.Errorf("git alias %q already exists (%s); pass --force to replace it", name, current)
+	}
+	if err := core.GitRunInDir("", "git", append(gitAliasScopeArgs(local), key, value)...); err != nil {
+		return fmt.Errorf("git config %s: %w", key, err)
+	}
+	fmt.Fprintf(os.Stdout, "Installed `git %s` -> %s\n", name, bin)
+	return nil
+}
+
+func runUninstallGitAlias(name string, local bool) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("alias name required")
+	}
+	key := "alias." + name
+	current := gitAliasCurrent(local, key)
+	if current == "" {
+		return fmt.Errorf("git alias %q is not set", name)
+	}
+	if !strings.HasPrefix(current, gitAliasPrefix) {
+		return fmt.Errorf("git alias %q was not installed by wtx (%s); leaving it alone", name, current)
+	}
+	if err := core.GitRunInDir("", "git", append(gitAliasScopeArgs(local), "--unset", key)...); err != nil {
+		return fmt.Errorf("git config --unset %s: %w", key, err)
+	}
+	fmt.Fprintf(os.Stdout, "Removed `git %s`\n", name)
+	return nil
+}
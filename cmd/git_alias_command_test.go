@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInstallGitAliasKeepsForeignAliases(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(home, ".gitconfig"))
+	git, err := exec.LookPath("git")
+	if err != nil {
+		t.Fatalf("git not found: %v", err)
+	}
+	// Only git on PATH, so no installed wtx is picked up.
+	t.Setenv("PATH", filepath.Dir(git))
+	t.Setenv("WTX_STATUS_BIN", "")
+
+	if err := runInstallGitAlias("wt", false, false); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	out, err := exec.Command("git", "config", "--global", "--get", "alias.wt").Output()
+	if err != nil || !strings.HasPrefix(strings.TrimSpace(string(out)), gitAliasPrefix) {
+		t.Fatalf("unexpected alias %q err=%v", out, err)
+	}
+	if err := runInstallGitAlias("wt", false, false); err != nil {
+		t.Fatalf("reinstalling the same alias should succeed: %v", err)
+	}
+
+	if err := exec.Command("git", "config", "--global", "alias.co", "checkout").Run(); err != nil {
+		t.Fatal(err)
+	}
+	if err := runInstallGitAlias("co", false, false); err == nil {
+		t.Fatalf("expected an existing alias to be kept without --force")
+	}
+	if err := runUninstallGitAlias("co", false); err == nil {
+		t.Fatalf("expected uninstall to refuse a foreign alias")
+	}
+
+	if err := runUninstallGitAlias("wt", false); err != nil {
+		t.Fatalf("uninstall: %v", err)
+	}
+	if out, _ := exec.Command("git", "config", "--global", "--get", "alias.wt").Output(); len(out) != 0 {
+		t.Fatalf("expected alias removed, got %q", out)
+	}
+}
@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func initGitReadTestRepo(t *testing.T) string {
+	t.Helper()
+	repo := initRenameTestRepo(t)
+	runGitInRepo(t, repo, "branch", "-M", "main")
+	t.Setenv("GIT_COMMITTER_DATE", "2024-01-02T00:00:00Z")
+	runGitInRepo(t, repo, "checkout", "-q", "-b", "older")
+	runGitInRepo(t, repo, "commit", "-q", "--allow-empty", "-m", "older")
+	t.Setenv("GIT_COMMITTER_DATE", "2024-01-03T00:00:00Z")
+	runGitInRepo(t, repo, "checkout", "-q", "-b", "newer")
+	runGitInRepo(t, repo, "commit", "-q", "--allow-empty", "-m", "newer")
+	runGitInRepo(t, repo, "checkout", "-q", "main")
+	runGitInRepo(t, repo, "remote", "add", "upstream", "https://example.com/upstream.git")
+	runGitInRepo(t, repo, "remote", "add", "origin", "https://example.com/origin.git")
+	return repo
+}
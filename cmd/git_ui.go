@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+// gitUIClients are the TUI git clients tried, in order, when git_ui_command
+// is unset.
+var gitUIClients = []string{"lazygit", "gitui"}
+
+// gitUICommand returns the git client to launch for dir: git_ui_command when
+// set, otherwise the first of gitUIClients on PATH. It is "" when the client
+// is not installed.
+func gitUICommand(dir string) string {
+	cfg, err := core.LoadConfigForDir(dir)
+	if err == nil && cfg.GitUICommand != "" {
+		if _, err := exec.LookPath(strings.Fields(cfg.GitUICommand)[0]); err != nil {
+			return ""
+		}
+		return cfg.GitUICommand
+	}
+	for _, client := range gitUIClients {
+		if _, err := exec.LookPath(client); err == nil {
+			return client
+		}
+	}
+	return ""
+}
+
+// gitUIName is the client's program name, for labels and the tmux window.
+func gitUIName(command string) string {
+	if fields := strings.Fields(command); len(fields) > 0 {
+		return filepath.Base(fields[0])
+	}
+	return "git UI"
+}
+
+// openGitUI launches the git client in the worktree: in a new tmux window
+// under tmux, otherwise in the foreground.
+func openGitUI(basePath string) error {
+	command := gitUICommand(basePath)
+	if command == "" {
+		return errors.New("no git UI found; install lazygit or gitui, or set git_ui_command")
+	}
+	if tmuxAvailable() {
+		return core.TmuxRun("new-window", "-c", tmuxFormatEscape(basePath), "-n", gitUIName(command), command)
+	}
+	clearPopupScreen()
+	cmd := core.ShellScriptCommand(command, false)
+	cmd.Dir = basePath
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
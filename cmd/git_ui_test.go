@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+func TestGitUIActionFollowsDetection(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake clients are shell scripts")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(core.ConfigDirOverrideEnv, "")
+	bin := filepath.Join(home, "bin")
+	if err := os.MkdirAll(bin, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	gitPath, err := exec.LookPath("git")
+	if err != nil {
+		t.Skip("git not installed")
+	}
+	// Only git stays on PATH, so lazygit or gitui on the machine don't leak in.
+	if err := os.Symlink(gitPath, filepath.Join(bin, "git")); err != nil {
+		t.Fatalf("symlink git: %v", err)
+	}
+	t.Setenv("PATH", bin)
+	repo := initRenameTestRepo(t)
+	gitUIItem := func() tmuxActionItem {
+		t.Helper()
+		for _, item := range newTmuxActionsModel(repo, false, false, false).items {
+			if item.Action == tmuxActionGitUI {
+				return item
+			}
+		}
+		t.Fatal("no git UI item")
+		return tmuxActionItem{}
+	}
+
+	if item := gitUIItem(); !item.Disabled {
+		t.Fatalf("expected the action disabled without a client, got %+v", item)
+	}
+	if err := os.WriteFile(filepath.Join(bin, "gitui"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatalf("write fake gitui: %v", err)
+	}
+	if item := gitUIItem(); item.Disabled || item.Description != "Open gitui" {
+		t.Fatalf("expected gitui to be detected, got %+v", item)
+	}
+	writeTrustedRepoConfig(t, repo, `{"git_ui_command": "tig --all"}`)
+	if item := gitUIItem(); !item.Disabled {
+		t.Fatalf("expected a missing git_ui_command to disable the action, got %+v", item)
+	}
+	if err := os.WriteFile(filepath.Join(bin, "tig"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatalf("write fake tig: %v", err)
+	}
+	if item := gitUIItem(); item.Disabled || item.Description != "Open tig" {
+		t.Fatalf("expected the configured client, got %+v", item)
+	}
+}
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+type helpEntry struct {
+	Keys        string
+	Description string
+}
+
+type helpSection struct {
+	ID      string
+	Title   string
+	Entries []helpEntry
+}
+
+const helpKey = "?"
+
+const (
+	helpSectionTable      = "table"
+	helpSectionOpen       = "open"
+	helpSectionAction     = "action"
+	helpSectionBranchPick = "branch_pick"
+	helpSectionBranchName = "branch_name"
+	helpSectionBulk       = "bulk"
+	helpSectionPreview    = "preview"
+	helpSectionConfirm    = "confirm"
+	helpSectionPopup      = "popup"
+)
+
+// helpSections documents every screen. Remappable actions are read from the
+// keymap so the overlay always shows the keys actually bound.
+func (k keymap) helpSections() []helpSection {
+	actions := func(popup bool) []helpEntry {
+		out := []helpEntry{}
+		for _, d := range defaultKeyBindings {
+			if isPopupKeyAction(d.Action) != popup {
+				continue
+			}
+			keys := k.bound(d.Action)
+			if len(keys) == 0 {
+				continue
+			}
+			out = append(out, helpEntry{Keys: strings.Join(keys, ", "), Description: d.Help})
+		}
+		return out
+	}
+	nav := helpEntry{Keys: "up/k, down/j", Description: "Move the selection"}
+	quit := helpEntry{Keys: k.label(keyActionQuit) + ", ctrl+c", Description: "Quit"}
+	help := helpEntry{Keys: helpKey, Description: "Show or hide this help"}
+
+	table := []helpEntry{nav}
+	table = append(table, actions(false)...)
+	table = append(table,
+		helpEntry{Keys: "space", Description: "Mark the worktree for a bulk action"},
+		helpEntry{Keys: "/", Description: "Filter by branch, path or PR number"},
+		helpEntry{Keys: "esc", Description: "Clear the filter, then the marks"},
+		help,
+	)
+	popup := []helpEntry{
+		{Keys: "type", Description: "Search actions"},
+		{Keys: "up, down", Description: "Move the selection"},
+		{Keys: "enter", Description: "Run the selected action"},
+	}
+	popup = append(popup, actions(true)...)
+	popup = append(popup, helpEntry{Keys: "esc", Description: "Close the popup"}, help)
+
+	return []helpSection{
+		{ID: helpSectionTable, Title: "Worktree table", Entries: table},
+		{ID: helpSectionOpen, Title: "Open screen", Entries: []helpEntry{
+			{Keys: "up, down", Description: "Move the selection"},
+			{Keys: "type", Description: "Search branches"},
+			{Keys: "backspace", Description: "Edit the search"},
+			{Keys: "enter", Description: "Open the selected branch or start a new one"},
+			{Keys: "ctrl+d", Description: "Toggle the worktree debug view (d delete, u unlock, n new, ctrl+r refresh)"},
+			help,
+			quit,
+		}},
+		{ID: helpSectionAction, Title: "Actions menu", Entries: []helpEntry{
+			nav,
+			{Keys: "enter", Description: "Run the selected action"},
+			{Keys: "esc", Description: "Back to the table"},
+			help,
+		}},
+		{ID: helpSectionBulk, Title: "Bulk actions menu", Entries: []helpEntry{
+			nav,
+			{Keys: "enter", Description: "Confirm the action for every marked worktree"},
+			{Keys: "esc", Description: "Back to the table"},
+			help,
+		}},
+		{ID: helpSectionPreview, Title: "Diff preview", Entries: []helpEntry{
+			{Keys: "up/k, down/j", Description: "Scroll one line"},
+			{Keys: "pgup, pgdown, space", Description: "Scroll one page"},
+			{Keys: "g, G", Description: "Jump to the top or bottom"},
+			{Keys: "tab", Description: "Toggle between --stat and the full diff vs the base ref"},
+			{Keys: "esc, q, " + k.label(keyActionPreview), Description: "Back to the table"},
+			help,
+		}},
+		{ID: helpSectionBranchPick, Title: "Branch picker", Entries: []helpEntry{
+			{Keys: "type", Description: "Filter branches"},
+			{Keys: "up, down", Description: "Move the selection"},
+			{Keys: "enter", Description: "Use the selected branch"},
+			{Keys: "esc", Description: "Back to the actions menu"},
+			help,
+		}},
+		{ID: helpSectionBranchName, Title: "New branch name", Entries: []helpEntry{
+			{Keys: "tab", Description: "Fill in a draft-<timestamp> name"},
+			{Keys: "enter", Description: "Create the branch"},
+			{Keys: "esc", Description: "Cancel"},
+			help,
+		}},
+		{ID: helpSectionConfirm, Title: "Delete or unlock confirmation", Entries: []helpEntry{
+			{Keys: "left, right, y, n", Description: "Choose yes or no"},
+			{Keys: "enter", Description: "Confirm"},
+			{Keys: "esc", Description: "Cancel"},
+		}},
+		{ID: helpSectionPopup, Title: "tmux actions popup", Entries: popup},
+	}
+}
+
+// canShowHelp reports whether ? should open the overlay. Forms own their keys,
+// and ? cannot collide with typed text elsewhere since git rejects it in
+// branch names.
+func canShowHelp(m model) bool {
+	if m.confirmForm != nil || m.prForm != nil || m.mode == modeCreating || m.mode == modeNote || m.listFiltering {
+		return false
+	}
+	if m.mode == modeOpen && (m.openCreating || m.openStage == openStageNewBranchConfig) {
+		return false
+	}
+	return true
+}
+
+func helpSectionForMode(md uiMode) string {
+	switch md {
+	case modeOpen:
+		return helpSectionOpen
+	case modeAction:
+		return helpSectionAction
+	case modeBranchPick:
+		return helpSectionBranchPick
+	case modeBranchName:
+		return helpSectionBranchName
+	case modeBulkAction:
+		return helpSectionBulk
+	case modePreview:
+		return helpSectionPreview
+	case modeDelete, modeUnlock:
+		return helpSectionConfirm
+	default:
+		return helpSectionTable
+	}
+}
+
+// renderHelpOverlay lists every section, starting with current.
+func renderHelpOverlay(k keymap, current string) string {
+	order := []helpSection{}
+	for _, section := range k.helpSections() {
+		if section.ID == current {
+			order = append([]helpSection{section}, order...)
+		} else {
+			order = append(order, section)
+		}
+	}
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Keyboard shortcuts"))
+	b.WriteString("\n")
+	for _, section := range order {
+		b.WriteString("\n")
+		title := section.Title
+		if section.ID == current {
+			title += " (current)"
+		}
+		b.WriteString(selectorHeaderStyle.Render(title))
+		b.WriteString("\n")
+		keyWidth := 0
+		for _, e := range section.Entries {
+			keyWidth = max(keyWidth, len([]rune(e.Keys)))
+		}
+		for _, e := range section.Entries {
+			b.WriteString(fmt.Sprintf("  %s  %s\n", actionSelectedStyle.Render(fmt.Sprintf("%-*s", keyWidth, e.Keys)), actionNormalStyle.Render(e.Description)))
+		}
+	}
+	b.WriteString("\n")
+	b.WriteString(secondaryStyle.Render("Press any key to close."))
+	b.WriteString("\n")
+	return b.String()
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aixolotls/wtx/internal/core"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func hookEvents() []string {
+	return []string{core.HookPreCreate, core.HookPostCreate, core.HookPreDelete, core.HookPostOpen, core.HookAgentExit, core.HookPRMerged}
+}
+
+// prMergedHookRetry is how long a failed pr_merged hook waits before the
+// next PR refresh may run it again.
+const prMergedHookRetry = time.Hour
+
+// runHookOrWarn is for hooks that run once the TUI is gone, around the
+// agent; inside the TUI the failure goes back to the model instead.
+func runHookOrWarn(event string, ctx core.HookContext) {
+	if err := core.RunHook(event, ctx); err != nil {
+		fmt.Fprintln(os.Stderr, "wtx warning:", err)
+	}
+}
+
+type prMergedHooksMsg struct {
+	err error
+}
+
+// prMergedHooksCmd runs pr_merged once per merged PR of a worktree, off the
+// UI thread, and reports failures to the model.
+func prMergedHooksCmd(status core.WorktreeStatus, byBranch map[string]core.PRData) tea.Cmd {
+	return func() tea.Msg {
+		return prMergedHooksMsg{err: runPRMergedHooks(status, byBranch)}
+	}
+}
+
+// runPRMergedHooks claims each merged PR before running its hook so other
+// wtx processes and later refreshes skip it. The done marker is written only
+// once the hook succeeds; a failed run keeps its claim, which blocks retries
+// for prMergedHookRetry.
+func runPRMergedHooks(status core.WorktreeStatus, byBranch map[string]core.PRData) error {
+	var errs []error
+	for _, wt := range status.Worktrees {
+		pr, ok := byBranch[wt.Branch]
+		if !ok || pr.Number <= 0 || pr.Status != "merged" {
+			continue
+		}
+		marker, err := core.HookMarkerPath(core.HookPRMerged, core.AuditRepo(status.RepoRoot)+"#"+strconv.Itoa(pr.Number))
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(marker); err == nil {
+			continue
+		}
+		cfg, err := core.LoadConfigForDir(wt.Path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s hook: %w", core.HookPRMerged, err))
+			continue
+		}
+		if strings.TrimSpace(cfg.Hooks[core.HookPRMerged]) == "" || !core.ClaimHookMarker(marker+".claim", prMergedHookRetry) {
+			continue
+		}
+		if err := core.RunHook(core.HookPRMerged, core.HookContext{RepoRoot: status.RepoRoot, WorktreePath: wt.Path, Branch: wt.Branch, PR: &pr}); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !core.ClaimHookMarker(marker, 0) {
+			core.DebugLog("hook marker not written", "path", marker)
+		}
+		_ = os.Remove(marker + ".claim")
+	}
+	return errors.Join(errs...)
+}
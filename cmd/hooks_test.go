@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+func TestWorktreeHooksRunWithContext(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the recording hook is a POSIX shell script")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	repo := initRenameTestRepo(t)
+	out := filepath.Join(home, "hooks.log")
+	record := `echo "$WTX_HOOK $WTX_BRANCH $(basename "$WTX_WORKTREE_PATH") $(basename "$PWD")" >> ` + core.ShellQuote(out)
+	if err := runConfigSet(false, "hooks.pre_create", record); err != nil {
+		t.Fatalf("set pre_create: %v", err)
+	}
+	if err := runConfigSet(false, "hooks.post_create", record); err != nil {
+		t.Fatalf("set post_create: %v", err)
+	}
+	t.Chdir(repo)
+	if err := runConfigSet(true, "hooks.pre_delete", "echo not yet; exit 3"); err != nil {
+		t.Fatalf("set repo pre_delete: %v", err)
+	}
+	// Worktrees read .wtx.json from their own checkout.
+	runGitInRepo(t, repo, "add", core.RepoConfigFileName)
+	runGitInRepo(t, repo, "commit", "-m", "wtx config")
+
+	mgr := core.NewWorktreeManager(repo, core.NewLockManager())
+	wt, err := mgr.CreateWorktree("feature", "HEAD", nil)
+	if err != nil {
+		t.Fatalf("CreateWorktree: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read hook log: %v", err)
+	}
+	slot := filepath.Base(wt.Path)
+	want := "pre_create feature " + slot + " " + filepath.Base(repo) + "\npost_create feature " + slot + " " + slot + "\n"
+	if string(data) != want {
+		t.Fatalf("hook log = %q, want %q", data, want)
+	}
+
+	_, err = mgr.DeleteWorktree(wt.Path, false)
+	if err == nil || !strings.Contains(err.Error(), "pre_delete hook failed") || !strings.Contains(err.Error(), "not yet") {
+		t.Fatalf("expected the failing pre_delete hook to stop the delete, got %v", err)
+	}
+	if _, err := os.Stat(wt.Path); err != nil {
+		t.Fatalf("expected the worktree to survive: %v", err)
+	}
+}
+
+func TestHookConfigKeys(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if err := runConfigSet(false, "hooks.merged", "true"); err == nil || !strings.Contains(err.Error(), "unknown hook") {
+		t.Fatalf("expected unknown hook error, got %v", err)
+	}
+	if err := runConfigSet(false, "hooks.agent_exit", "notify-send done"); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	cfg, err := core.LoadConfig()
+	if err != nil || cfg.Hooks[core.HookAgentExit] != "notify-send done" {
+		t.Fatalf("expected hook in config, got %+v (%v)", cfg.Hooks, err)
+	}
+	if err := runConfigUnset(false, "hooks.agent_exit"); err != nil {
+		t.Fatalf("unset: %v", err)
+	}
+	if cfg, _ := core.LoadConfig(); len(cfg.Hooks) != 0 {
+		t.Fatalf("expected hooks removed, got %+v", cfg.Hooks)
+	}
+}
+
+func TestPRMergedHookFiresOnce(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	repo := initRenameTestRepo(t)
+	marker, err := core.HookMarkerPath(core.HookPRMerged, core.AuditRepo(repo)+"#7")
+	if err != nil {
+		t.Fatalf("hookMarkerPath: %v", err)
+	}
+	status := core.WorktreeStatus{RepoRoot: repo, Worktrees: []core.WorktreeInfo{{Path: repo, Branch: "main"}}}
+	merged := map[string]core.PRData{"main": {Number: 7, Status: "merged"}}
+
+	if err := runPRMergedHooks(status, merged); err != nil {
+		t.Fatalf("run without a hook: %v", err)
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Fatalf("expected no marker without a pr_merged hook")
+	}
+	if err := runConfigSet(false, "hooks.pr_merged", "echo merge broke && exit 2"); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if err := runPRMergedHooks(status, map[string]core.PRData{"main": {Number: 7, Status: "open"}}); err != nil {
+		t.Fatalf("run for an open PR: %v", err)
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Fatalf("expected no marker for an open PR")
+	}
+
+	// A failed hook is reported, leaves no done marker and is not retried
+	// straight away.
+	if err := runPRMergedHooks(status, merged); err == nil || !strings.Contains(err.Error(), "merge broke") {
+		t.Fatalf("expected the hook failure, got %v", err)
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Fatalf("expected no marker after a failed hook")
+	}
+	if err := runPRMergedHooks(status, merged); err != nil {
+		t.Fatalf("expected no immediate retry, got %v", err)
+	}
+
+	old := time.Now().Add(-2 * prMergedHookRetry)
+	if err := os.Chtimes(marker+".claim", old, old); err != nil {
+		t.Fatalf("age claim: %v", err)
+	}
+	if err := runConfigSet(false, "hooks.pr_merged", "exit 0"); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if err := runPRMergedHooks(status, merged); err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if _, err := os.Stat(marker); err != nil {
+		t.Fatalf("expected the merged PR to be marked done: %v", err)
+	}
+	if core.ClaimHookMarker(marker, 0) {
+		t.Fatalf("expected a second claim to fail")
+	}
+}
@@ -8,7 +8,6 @@ import (
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 )
 
 type dirPickerModel struct {
@@ -148,16 +147,11 @@ func (m dirPickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m dirPickerModel) View() string {
 	var b strings.Builder
 
-	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#7D56F4"))
-	selectedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#7D56F4")).Bold(true)
-	normalStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("251"))
-	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
-
 	b.WriteString(titleStyle.Render("Open in \"" + m.ideCmd + "\""))
 	b.WriteString("\n")
 	b.WriteString(m.filter.View())
 	b.WriteString("\n")
-	b.WriteString(dimStyle.Render("────────────────────────────────────────"))
+	b.WriteString(secondaryStyle.Render("────────────────────────────────────────"))
 	b.WriteString("\n")
 
 	maxVisible := 12
@@ -169,20 +163,20 @@ func (m dirPickerModel) View() string {
 	for i := start; i < len(m.filtered) && i < start+maxVisible; i++ {
 		entry := m.filtered[i]
 		if i == m.selected {
-			b.WriteString(selectedStyle.Render("> " + entry))
+			b.WriteString(selectorSelectedStyle.Render("> " + entry))
 		} else {
-			b.WriteString(normalStyle.Render("  " + entry))
+			b.WriteString(actionNormalStyle.Render("  " + entry))
 		}
 		b.WriteString("\n")
 	}
 
 	if len(m.filtered) == 0 {
-		b.WriteString(dimStyle.Render("  (no matches)"))
+		b.WriteString(secondaryStyle.Render("  (no matches)"))
 		b.WriteString("\n")
 	}
 
 	b.WriteString("\n")
-	b.WriteString(dimStyle.Render("↑/↓ navigate • enter select • esc cancel"))
+	b.WriteString(secondaryStyle.Render("↑/↓ navigate • enter select • esc cancel"))
 
 	return b.String()
 }
@@ -200,17 +194,12 @@ func runIDEPicker(args []string) error {
 		return err
 	}
 
-	cfg, err := LoadConfig()
-	if err != nil {
-		return err
-	}
-	_, ideCmd, err := ensureIDECommandConfigured(cfg)
+	ideCmd, err := resolveIDECommand(basePath)
 	if err != nil {
 		return err
 	}
 
-	p := tea.NewProgram(newDirPickerModel(basePath, ideCmd))
-	finalModel, err := p.Run()
+	finalModel, err := runProgram(newDirPickerModel(basePath, ideCmd))
 	if err != nil {
 		return err
 	}
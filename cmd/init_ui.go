@@ -1,242 +1,192 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
 
-	"github.com/charmbracelet/bubbles/textinput"
-	tea "github.com/charmbracelet/bubbletea"
+	"github.com/aixolotls/wtx/internal/core"
+	"github.com/charmbracelet/huh"
 )
 
-type configField int
-
-const (
-	fieldAgent configField = iota
-	fieldDefaultBranch
-	fieldMainScreenBranchCount
-	fieldDefaultFetch
-	fieldIDECommand
-	fieldCount
-)
-
-type configModel struct {
-	inputs      []textinput.Model
-	fetchToggle bool
-	focused     configField
-	err         string
-	done        bool
-	zshStatus   zshCompletionStatus
-	zshErr      string
+// configFormValues holds the editable subset of Config while the form runs.
+type configFormValues struct {
+	AgentCommand string
+	IDECommand   string
+	AgentLayout  string
+	BaseRef      string
+	FetchFirst   bool
+	BranchLimit  string
+	UpdateChecks bool
+	Theme        string
 }
 
-func newConfigModel() configModel {
-	inputs := make([]textinput.Model, fieldCount)
-
-	var cfg Config
-	if loaded, err := LoadConfig(); err == nil {
-		cfg = loaded
+func configFormValuesFrom(cfg core.Config) configFormValues {
+	v := configFormValues{
+		AgentCommand: strings.TrimSpace(cfg.AgentCommand),
+		IDECommand:   strings.TrimSpace(cfg.IDECommand),
+		AgentLayout:  agentLayoutSplit,
+		BaseRef:      strings.TrimSpace(cfg.NewBranchBaseRef),
+		FetchFirst:   true,
+		BranchLimit:  strconv.Itoa(core.DefaultMainScreenBranchLimit),
+		UpdateChecks: true,
+		Theme:        themeDark,
+	}
+	if strings.EqualFold(strings.TrimSpace(cfg.AgentLayout), agentLayoutWindow) {
+		v.AgentLayout = agentLayoutWindow
 	}
-
-	agentInput := textinput.New()
-	agentInput.Placeholder = defaultAgentCommand
-	agentInput.SetValue(strings.TrimSpace(cfg.AgentCommand))
-	agentInput.CharLimit = 200
-	agentInput.Width = 40
-	agentInput.Focus()
-	inputs[fieldAgent] = agentInput
-
-	branchInput := textinput.New()
-	branchInput.Placeholder = "origin/main"
-	if strings.TrimSpace(cfg.NewBranchBaseRef) != "" {
-		branchInput.SetValue(cfg.NewBranchBaseRef)
-	}
-	branchInput.CharLimit = 200
-	branchInput.Width = 40
-	inputs[fieldDefaultBranch] = branchInput
-
-	branchLimitInput := textinput.New()
-	branchLimitInput.Placeholder = strconv.Itoa(defaultMainScreenBranchLimit)
-	branchLimitInput.SetValue(strconv.Itoa(cfg.MainScreenBranchLimit))
-	branchLimitInput.CharLimit = 4
-	branchLimitInput.Width = 10
-	inputs[fieldMainScreenBranchCount] = branchLimitInput
-
-	fetchInput := textinput.New()
-	inputs[fieldDefaultFetch] = fetchInput
-
-	ideInput := textinput.New()
-	ideInput.Placeholder = defaultIDECommand
-	ideInput.SetValue(strings.TrimSpace(cfg.IDECommand))
-	ideInput.CharLimit = 200
-	ideInput.Width = 40
-	inputs[fieldIDECommand] = ideInput
-
-	fetchToggle := true
 	if cfg.NewBranchFetchFirst != nil {
-		fetchToggle = *cfg.NewBranchFetchFirst
+		v.FetchFirst = *cfg.NewBranchFetchFirst
 	}
-
-	model := configModel{
-		inputs:      inputs,
-		fetchToggle: fetchToggle,
-		focused:     fieldAgent,
+	if cfg.MainScreenBranchLimit > 0 {
+		v.BranchLimit = strconv.Itoa(cfg.MainScreenBranchLimit)
 	}
-	if status, err := detectZshCompletionStatus(); err == nil {
-		model.zshStatus = status
-	} else {
-		model.zshErr = err.Error()
-	}
-	return model
-}
-
-func (m configModel) Init() tea.Cmd {
-	return tea.Batch(tea.ExitAltScreen, tea.ClearScreen)
-}
-
-func (m configModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "esc":
-			return m, tea.Quit
-		case "tab", "down":
-			m.inputs[m.focused].Blur()
-			m.focused++
-			if m.focused >= fieldCount {
-				m.focused = 0
-			}
-			if m.focused != fieldDefaultFetch {
-				m.inputs[m.focused].Focus()
-			}
-			return m, nil
-		case "shift+tab", "up":
-			m.inputs[m.focused].Blur()
-			if m.focused == 0 {
-				m.focused = fieldCount - 1
-			} else {
-				m.focused--
-			}
-			if m.focused != fieldDefaultFetch {
-				m.inputs[m.focused].Focus()
-			}
-			return m, nil
-		case " ":
-			if m.focused == fieldDefaultFetch {
-				m.fetchToggle = !m.fetchToggle
-				return m, nil
-			}
-		case "enter":
-			if err := m.save(); err != nil {
-				m.err = err.Error()
-				return m, nil
-			}
-			m.done = true
-			return m, tea.Quit
-		}
+	if strings.EqualFold(strings.TrimSpace(cfg.UpdateCheck), core.UpdateCheckOff) {
+		v.UpdateChecks = false
 	}
-
-	if m.focused != fieldDefaultFetch {
-		var cmd tea.Cmd
-		m.inputs[m.focused], cmd = m.inputs[m.focused].Update(msg)
-		return m, cmd
+	if _, ok := palettes[cfg.Theme]; ok {
+		v.Theme = cfg.Theme
 	}
-	return m, nil
+	return v
 }
 
-func (m configModel) save() error {
-	agent := strings.TrimSpace(m.inputs[fieldAgent].Value())
-
-	branch := strings.TrimSpace(m.inputs[fieldDefaultBranch].Value())
-	branchLimit, err := normalizeMainScreenBranchLimit(m.inputs[fieldMainScreenBranchCount].Value())
+// apply writes the form values onto cfg, leaving every other field untouched.
+func (v configFormValues) apply(cfg core.Config) (core.Config, error) {
+	limit, err := core.NormalizeMainScreenBranchLimit(v.BranchLimit)
 	if err != nil {
-		return err
-	}
-
-	ide := strings.TrimSpace(m.inputs[fieldIDECommand].Value())
-
-	cfg := Config{
-		AgentCommand:          agent,
-		NewBranchBaseRef:      branch,
-		NewBranchFetchFirst:   &m.fetchToggle,
-		IDECommand:            ide,
-		MainScreenBranchLimit: branchLimit,
-	}
-	return SaveConfig(cfg)
+		return cfg, err
+	}
+	fetch := v.FetchFirst
+	cfg.AgentCommand = strings.TrimSpace(v.AgentCommand)
+	cfg.IDECommand = strings.TrimSpace(v.IDECommand)
+	cfg.AgentLayout = v.AgentLayout
+	if cfg.AgentLayout == agentLayoutSplit {
+		cfg.AgentLayout = ""
+	}
+	cfg.NewBranchBaseRef = strings.TrimSpace(v.BaseRef)
+	cfg.NewBranchFetchFirst = &fetch
+	cfg.MainScreenBranchLimit = limit
+	switch {
+	case !v.UpdateChecks:
+		cfg.UpdateCheck = core.UpdateCheckOff
+	case strings.EqualFold(strings.TrimSpace(cfg.UpdateCheck), core.UpdateCheckOff):
+		cfg.UpdateCheck = ""
+	}
+	cfg.Theme = v.Theme
+	if cfg.Theme == themeDark {
+		cfg.Theme = ""
+	}
+	return cfg, nil
 }
 
-func (m configModel) View() string {
-	if m.done {
-		return ""
-	}
-	var b strings.Builder
-	b.WriteString(bannerStyle.Render("WTX Config"))
-	b.WriteString("\n\n")
-
-	b.WriteString(m.renderField(fieldAgent, "Agent command:", m.inputs[fieldAgent].View()))
-	b.WriteString(m.renderField(fieldDefaultBranch, "Default branch:", m.inputs[fieldDefaultBranch].View()))
-	b.WriteString(m.renderField(fieldMainScreenBranchCount, "Main screen branch count:", m.inputs[fieldMainScreenBranchCount].View()))
-	b.WriteString(m.renderFetchField())
-	b.WriteString(m.renderField(fieldIDECommand, "IDE command:", m.inputs[fieldIDECommand].View()))
-	b.WriteString("\n")
-	b.WriteString(m.renderZshCompletionStatus())
-
-	b.WriteString("\n")
-	b.WriteString("Use tab/shift+tab to navigate, space to toggle, enter to save, esc to cancel.\n")
-
-	if m.err != "" {
-		b.WriteString("\n")
-		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %s", m.err)))
-		b.WriteString("\n")
-	}
-	return b.String()
+func newConfigForm(v *configFormValues) *huh.Form {
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Agent command").
+				Placeholder(core.DefaultAgentCommand).
+				Value(&v.AgentCommand),
+			huh.NewInput().
+				Title("IDE command").
+				Placeholder(core.DefaultIDECommand).
+				Value(&v.IDECommand),
+			huh.NewSelect[string]().
+				Title("Agent layout").
+				Options(
+					huh.NewOption("Split the wtx pane", agentLayoutSplit),
+					huh.NewOption("New tmux window", agentLayoutWindow),
+				).
+				Value(&v.AgentLayout),
+		).Title("Agent"),
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Default base branch").
+				Placeholder("origin/main").
+				Value(&v.BaseRef),
+			huh.NewConfirm().
+				Title("Fetch before creating a branch?").
+				Affirmative("Yes").
+				Negative("No").
+				Value(&v.FetchFirst),
+			huh.NewInput().
+				Title("Main screen branch count").
+				Placeholder(strconv.Itoa(core.DefaultMainScreenBranchLimit)).
+				Validate(func(s string) error {
+					_, err := core.NormalizeMainScreenBranchLimit(s)
+					return err
+				}).
+				Value(&v.BranchLimit),
+		).Title("Branches"),
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("Check for wtx updates?").
+				Affirmative("Yes").
+				Negative("No").
+				Value(&v.UpdateChecks),
+			huh.NewSelect[string]().
+				Title("Theme").
+				Options(
+					huh.NewOption("Dark terminal background", themeDark),
+					huh.NewOption("Light terminal background", themeLight),
+				).
+				Value(&v.Theme),
+			huh.NewNote().
+				Title("Shell completion").
+				Description(zshCompletionSummary()),
+		).Title("General"),
+	).WithTheme(wtxHuhTheme())
 }
 
-func (m configModel) renderField(field configField, label string, inputView string) string {
-	cursor := "  "
-	if m.focused == field {
-		cursor = "> "
+func runConfigForm() error {
+	cfg, err := core.LoadConfig()
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		cfg = core.Config{}
 	}
-	return fmt.Sprintf("%s%s\n  %s\n", cursor, label, inputStyle.Render(inputView))
-}
-
-func (m configModel) renderFetchField() string {
-	cursor := "  "
-	if m.focused == fieldDefaultFetch {
-		cursor = "> "
+	values := configFormValuesFrom(cfg)
+	if err := newConfigForm(&values).Run(); err != nil {
+		if errors.Is(err, huh.ErrUserAborted) {
+			return nil
+		}
+		return err
 	}
-	checked := "x"
-	if !m.fetchToggle {
-		checked = " "
+	next, err := values.apply(cfg)
+	if err != nil {
+		return err
 	}
-	return fmt.Sprintf("%sFetch before create: [%s]\n", cursor, checked)
+	return core.SaveConfig(next)
 }
 
-func (m configModel) renderZshCompletionStatus() string {
-	if m.zshErr != "" {
-		return fmt.Sprintf("Zsh completion: %s\n", errorStyle.Render("unavailable: "+m.zshErr))
+func zshCompletionSummary() string {
+	status, err := detectZshCompletionStatus()
+	if err != nil {
+		return fmt.Sprintf("Zsh completion: unavailable: %v", err)
 	}
 	parts := make([]string, 0, 2)
-	if !m.zshStatus.Installed {
+	if !status.Installed {
 		parts = append(parts, "not installed")
 	}
-	if !m.zshStatus.Enabled {
+	if !status.Enabled {
 		parts = append(parts, "not enabled")
 	}
-	completionLine := "Zsh completion: installed and enabled"
+	lines := []string{"Zsh completion: installed and enabled"}
 	if len(parts) > 0 {
-		completionLine = fmt.Sprintf("Zsh completion: %s", strings.Join(parts, ", "))
+		lines[0] = fmt.Sprintf("Zsh completion: %s", strings.Join(parts, ", "))
 	}
-	aliasesLine := "Zsh aliases: installed and enabled"
-	if !m.zshStatus.AliasesEnabled {
-		aliasesLine = "Zsh aliases: not enabled (optional)"
+	if status.AliasesEnabled {
+		lines = append(lines, "Zsh aliases: installed and enabled")
+	} else {
+		lines = append(lines, "Zsh aliases: not enabled (optional)")
 	}
-	helpLines := ""
-	if !m.zshStatus.Installed || !m.zshStatus.Enabled {
-		helpLines += "Install completion with: wtx completion install\n"
+	if !status.Installed || !status.Enabled {
+		lines = append(lines, "Install completion with: wtx completion install")
 	}
-	if !m.zshStatus.AliasesEnabled {
-		helpLines += "Install aliases with: wtx completion aliases install\n"
+	if !status.AliasesEnabled {
+		lines = append(lines, "Install aliases with: wtx completion aliases install")
 	}
-	return completionLine + "\n" + aliasesLine + "\n" + helpLines
+	return strings.Join(lines, "\n")
 }
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/aixolotls/wtx/internal/core"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// integrationIssue is an optional integration that is not working, so the
+// features built on it are missing from the UI.
+type integrationIssue struct {
+	// Name is the short label shown in the footer.
+	Name   string
+	Detail string
+	Fix    string
+}
+
+type integrationsCheckedMsg struct {
+	issues []integrationIssue
+}
+
+// checkIntegrationsCmd probes the environment off the UI thread; the
+// osascript probe can take seconds when macOS is slow to answer.
+func checkIntegrationsCmd() tea.Cmd {
+	return func() tea.Msg {
+		return integrationsCheckedMsg{issues: detectIntegrationIssues()}
+	}
+}
+
+func detectIntegrationIssues() []integrationIssue {
+	var issues []integrationIssue
+	if _, err := exec.LookPath("gh"); err != nil {
+		issues = append(issues, integrationIssue{
+			Name:   "gh",
+			Detail: "The GitHub CLI is not on PATH, so PR, CI and review status are not shown.",
+			Fix:    "Install gh from https://cli.github.com, then run gh auth login.",
+		})
+	}
+	if issue, ok := tmuxIntegrationIssue(); ok {
+		issues = append(issues, issue)
+	}
+	if issue, ok := osascriptIntegrationIssue(); ok {
+		issues = append(issues, issue)
+	}
+	if issue, ok := repoConfigTrustIssue(""); ok {
+		issues = append(issues, issue)
+	}
+	return issues
+}
+
+// tmuxIntegrationIssue reports tmux only when the user has not turned the
+// integration off; WTX_DISABLE_TMUX is the way to silence it.
+func tmuxIntegrationIssue() (integrationIssue, bool) {
+	if core.TmuxIntegrationDisabled() {
+		return integrationIssue{}, false
+	}
+	const lost = "agent panes, shell splits, the IDE subfolder picker and tab naming are unavailable."
+	if _, err := exec.LookPath("tmux"); err != nil {
+		return integrationIssue{
+			Name:   "tmux",
+			Detail: "tmux is not installed, so " + lost,
+			Fix:    "Install tmux and run wtx inside it, or set WTX_DISABLE_TMUX=1 to hide this.",
+		}, true
+	}
+	if strings.TrimSpace(os.Getenv("TMUX")) == "" {
+		return integrationIssue{
+			Name:   "tmux",
+			Detail: "wtx is not running inside tmux, so " + lost,
+			Fix:    "Start tmux and run wtx from there, or set WTX_DISABLE_TMUX=1 to hide this.",
+		}, true
+	}
+	return integrationIssue{}, false
+}
+
+// osascriptIntegrationIssue asks the parent terminal for its version, the
+// same probe canControlITerm uses, and reports when macOS refuses it.
+func osascriptIntegrationIssue() (integrationIssue, bool) {
+	if runtime.GOOS != "darwin" {
+		return integrationIssue{}, false
+	}
+	if _, err := exec.LookPath("osascript"); err != nil {
+		return integrationIssue{}, false
+	}
+	term := resolveSessionParentTerminalProgram()
+	app := ""
+	switch {
+	case isITermTerminal(term):
+		if core.ITermIntegrationDisabled() {
+			return integrationIssue{}, false
+		}
+		app = "iTerm"
+	case strings.EqualFold(term, "Apple_Terminal"):
+		app = "Terminal"
+	default:
+		return integrationIssue{}, false
+	}
+	err := core.OsascriptRun("-e", `tell application "`+app+`" to version`)
+	if err == nil || !osascriptDenied(err) {
+		return integrationIssue{}, false
+	}
+	return integrationIssue{
+		Name:   "osascript",
+		Detail: "macOS denied automation of " + app + ", so opening shells in new tabs and windows is unavailable.",
+		Fix:    "Allow your terminal to control " + app + " in System Settings > Privacy & Security > Automation, then restart wtx.",
+	}, true
+}
+
+// osascriptDenied matches the errAEEventNotPermitted (-1743) failure.
+func osascriptDenied(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "-1743") ||
+		strings.Contains(msg, "not authorized to send apple events") ||
+		strings.Contains(msg, "not allowed to send apple events")
+}
+
+// integrationIssues adds what the model learned at runtime (gh auth,
+// background fetch, update check) to the environment probes.
+func (m model) integrationIssues() []integrationIssue {
+	issues := append([]integrationIssue(nil), m.integrations...)
+	if m.ghAuth != nil {
+		issues = append(issues, integrationIssue{
+			Name:   "gh auth",
+			Detail: m.ghAuth.Error(),
+			Fix:    "Press " + m.keys.label(keyActionGHAuth) + " to run gh " + strings.Join(m.ghAuth.FixArgs(), " ") + ".",
+		})
+	}
+	if m.fetchErr != nil {
+		issues = append(issues, integrationIssue{
+			Name:   "fetch",
+			Detail: "Background git fetch failed, so ahead/behind counts may be stale: " + m.fetchErr.Error(),
+			Fix:    "Check the remote and your credentials, or run wtx config set background_fetch false.",
+		})
+	}
+	if m.updateHintIsError && m.updateHint != "" {
+		issues = append(issues, integrationIssue{
+			Name:   "update check",
+			Detail: m.updateHint,
+			Fix:    "Check your network connection, or run wtx config set update_check off to stop checking.",
+		})
+	}
+	return issues
+}
+
+func renderIntegrationFooter(m model) string {
+	issues := m.integrationIssues()
+	if len(issues) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		names = append(names, issue.Name)
+	}
+	return warnStyle.Render("Degraded: "+strings.Join(names, ", ")) +
+		secondaryStyle.Render("  ("+m.keys.label(keyActionIntegrations)+" for details)")
+}
+
+func renderIntegrationDetails(m model) string {
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Degraded integrations"))
+	b.WriteString("\n")
+	for _, issue := range m.integrationIssues() {
+		b.WriteString("\n")
+		b.WriteString(selectorHeaderStyle.Render(issue.Name))
+		b.WriteString("\n")
+		b.WriteString(issue.Detail)
+		b.WriteString("\n")
+		b.WriteString(secondaryStyle.Render("Fix: " + issue.Fix))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(secondaryStyle.Render("Press any key to close."))
+	b.WriteString("\n")
+	return b.String()
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aixolotls/wtx/internal/core"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestTmuxIntegrationIssue(t *testing.T) {
+	t.Setenv("WTX_DISABLE_TMUX", "1")
+	if _, ok := tmuxIntegrationIssue(); ok {
+		t.Fatalf("expected no issue when tmux integration is turned off")
+	}
+	t.Setenv("WTX_DISABLE_TMUX", "")
+	t.Setenv("PATH", t.TempDir())
+	issue, ok := tmuxIntegrationIssue()
+	if core.TmuxSupported && (!ok || !strings.Contains(issue.Detail, "not installed")) {
+		t.Fatalf("expected a missing tmux to be reported, got %+v", issue)
+	}
+}
+
+func TestOsascriptDenied(t *testing.T) {
+	denied := errors.New("execution error: Not authorized to send Apple events to iTerm. (-1743)")
+	if !osascriptDenied(denied) {
+		t.Fatalf("expected -1743 to count as denied")
+	}
+	if osascriptDenied(errors.New("execution error: Application isn't running. (-600)")) {
+		t.Fatalf("expected other failures not to count as denied")
+	}
+}
+
+func TestIntegrationFooterAndDetails(t *testing.T) {
+	m := newModel()
+	m.mode = modeList
+	m.ready = true
+	m.status = core.WorktreeStatus{
+		InRepo:       true,
+		GitInstalled: true,
+		RepoRoot:     "/repo",
+		Worktrees:    []core.WorktreeInfo{{Path: "/repo/wt.1", Branch: "feature", Available: true}},
+	}
+	updatedModel, _ := m.Update(integrationsCheckedMsg{})
+	updated := updatedModel.(model)
+	if strings.Contains(updated.View(), "Degraded") {
+		t.Fatalf("expected no footer without issues, got:\n%s", updated.View())
+	}
+	if updatedModel, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")}); updatedModel.(model).showIntegrations {
+		t.Fatalf("expected i to do nothing without issues")
+	}
+
+	updatedModel, _ = updated.Update(integrationsCheckedMsg{issues: []integrationIssue{{Name: "gh", Detail: "gh missing", Fix: "install gh"}}})
+	updatedModel, _ = updatedModel.Update(interactiveUpdateHintMsg{hint: "wtx update check failed: offline", isError: true})
+	updated = updatedModel.(model)
+	if !strings.Contains(updated.View(), "Degraded: gh, update check") {
+		t.Fatalf("expected the degraded footer, got:\n%s", updated.View())
+	}
+
+	updatedModel, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	updated = updatedModel.(model)
+	view := updated.View()
+	for _, want := range []string{"Degraded integrations", "gh missing", "Fix: install gh", "wtx update check failed: offline"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("expected %q in details, got:\n%s", want, view)
+		}
+	}
+	updatedModel, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	if updatedModel.(model).showIntegrations {
+		t.Fatalf("expected any key to close the details")
+	}
+}
@@ -0,0 +1,257 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+type keyAction string
+
+const (
+	keyActionOpen         keyAction = "open"
+	keyActionShell        keyAction = "shell"
+	keyActionDelete       keyAction = "delete"
+	keyActionUnlock       keyAction = "unlock"
+	keyActionOpenPR       keyAction = "open_pr"
+	keyActionCopyPath     keyAction = "copy_path"
+	keyActionCopyBranch   keyAction = "copy_branch"
+	keyActionCopyPRURL    keyAction = "copy_pr_url"
+	keyActionCreatePR     keyAction = "create_pr"
+	keyActionRefresh      keyAction = "refresh"
+	keyActionSort         keyAction = "sort"
+	keyActionPreview      keyAction = "preview"
+	keyActionUndo         keyAction = "undo"
+	keyActionError        keyAction = "error_detail"
+	keyActionNote         keyAction = "note"
+	keyActionPin          keyAction = "pin"
+	keyActionRepair       keyAction = "repair"
+	keyActionGHAuth       keyAction = "gh_auth"
+	keyActionIntegrations keyAction = "integrations"
+	keyActionTests        keyAction = "tests"
+	keyActionQuit         keyAction = "quit"
+
+	keyActionPopupBack   keyAction = "popup.back"
+	keyActionPopupIDE    keyAction = "popup.ide"
+	keyActionPopupPR     keyAction = "popup.pr"
+	keyActionPopupRename keyAction = "popup.rename"
+	keyActionPopupShell  keyAction = "popup.shell"
+	keyActionPopupTab    keyAction = "popup.tab"
+	keyActionPopupWindow keyAction = "popup.window"
+	keyActionPopupGitUI  keyAction = "popup.git_ui"
+)
+
+const popupKeyActionPrefix = "popup."
+
+type keyBindingDefault struct {
+	Action keyAction
+	Keys   []string
+	Help   string
+}
+
+// defaultKeyBindings lists every remappable action. The first key is the one
+// shown in help text and the one the table switch statements match on.
+var defaultKeyBindings = []keyBindingDefault{
+	{Action: keyActionOpen, Keys: []string{"enter"}, Help: "Open actions for the selected worktree"},
+	{Action: keyActionShell, Keys: []string{"s"}, Help: "Open a shell in the selected worktree"},
+	{Action: keyActionDelete, Keys: []string{"d"}, Help: "Delete the selected worktree"},
+	{Action: keyActionUnlock, Keys: []string{"u"}, Help: "Unlock a worktree that is in use"},
+	{Action: keyActionNote, Keys: []string{"n"}, Help: "Add or edit a note on the selected worktree"},
+	{Action: keyActionPin, Keys: []string{"f"}, Help: "Pin the worktree to the top of the table, or unpin it"},
+	{Action: keyActionTests, Keys: []string{"t"}, Help: "Run the test command in the selected worktree"},
+	{Action: keyActionPreview, Keys: []string{"p"}, Help: "Preview the diff of the selected worktree"},
+	{Action: keyActionOpenPR, Keys: []string{"P"}, Help: "Open the pull request in the browser"},
+	{Action: keyActionCopyPath, Keys: []string{"y"}, Help: "Copy the worktree path to the clipboard"},
+	{Action: keyActionCopyBranch, Keys: []string{"b"}, Help: "Copy the branch name to the clipboard"},
+	{Action: keyActionCopyPRURL, Keys: []string{"U"}, Help: "Copy the pull request URL to the clipboard"},
+	{Action: keyActionCreatePR, Keys: []string{"c"}, Help: "Push the branch and create a pull request"},
+	{Action: keyActionRefresh, Keys: []string{"r"}, Help: "Refresh status and GitHub data"},
+	{Action: keyActionUndo, Keys: []string{"z"}, Help: "Undo a delete during its grace period"},
+	{Action: keyActionRepair, Keys: []string{"R"}, Help: "Repair worktrees with a broken .git file or admin dir"},
+	{Action: keyActionGHAuth, Keys: []string{"a"}, Help: "Run gh auth login (or grant missing scopes) when PR data needs it"},
+	{Action: keyActionIntegrations, Keys: []string{"i"}, Help: "Show which integrations (gh, tmux, osascript, update check) are degraded and how to fix them"},
+	{Action: keyActionError, Keys: []string{"e"}, Help: "Show the full command, output and a fix for the last error"},
+	{Action: keyActionSort, Keys: []string{"S"}, Help: "Cycle the table sort order"},
+	{Action: keyActionQuit, Keys: []string{"q"}, Help: "Quit"},
+	{Action: keyActionPopupBack, Keys: []string{"ctrl+w", "ctrl+b"}, Help: "Back to wtx (stops the agent)"},
+	{Action: keyActionPopupIDE, Keys: []string{"ctrl+l"}, Help: "Open the IDE"},
+	{Action: keyActionPopupPR, Keys: []string{"ctrl+p"}, Help: "Open the pull request"},
+	{Action: keyActionPopupRename, Keys: []string{"ctrl+r"}, Help: "Rename the current branch"},
+	{Action: keyActionPopupShell, Keys: []string{"ctrl+s"}, Help: "Open a shell split"},
+	{Action: keyActionPopupTab, Keys: []string{"ctrl+t"}, Help: "Open a shell in a new terminal tab"},
+	{Action: keyActionPopupWindow, Keys: []string{"ctrl+n"}, Help: "Open a shell in a new terminal window"},
+	{Action: keyActionPopupGitUI, Keys: []string{"ctrl+g"}, Help: "Open lazygit or the configured git UI"},
+}
+
+// Keys that drive navigation and cancellation and can't be rebound.
+var (
+	reservedTableKeys = []string{"up", "down", "k", "j", "esc", "/", "?", " ", "ctrl+c", "ctrl+d"}
+	reservedPopupKeys = []string{"up", "down", "enter", "esc", "?", "ctrl+c", "backspace", "ctrl+u"}
+)
+
+type keymap struct {
+	keys map[keyAction][]string
+}
+
+func defaultKeymap() keymap {
+	km := keymap{keys: map[keyAction][]string{}}
+	for _, d := range defaultKeyBindings {
+		km.keys[d.Action] = append([]string(nil), d.Keys...)
+	}
+	return km
+}
+
+// newKeymap applies user overrides (action -> comma separated keys) on top of
+// the defaults and rejects unknown actions, reserved keys and conflicts.
+func newKeymap(overrides map[string]string) (keymap, error) {
+	km := defaultKeymap()
+	for name, value := range overrides {
+		action := keyAction(strings.TrimSpace(name))
+		if _, ok := km.keys[action]; !ok {
+			return defaultKeymap(), fmt.Errorf("unknown keybinding action %q", name)
+		}
+		keys := parseKeyList(value)
+		if len(keys) == 0 {
+			return defaultKeymap(), fmt.Errorf("keybinding %q has no keys", name)
+		}
+		km.keys[action] = keys
+	}
+	if err := km.validate(); err != nil {
+		return defaultKeymap(), err
+	}
+	return km, nil
+}
+
+// loadKeymap reads keybindings from the global config. On a bad config it
+// returns the defaults together with the error so callers can warn.
+func loadKeymap() (keymap, error) {
+	cfg, err := core.LoadConfig()
+	if err != nil {
+		return defaultKeymap(), nil
+	}
+	return newKeymap(cfg.Keybindings)
+}
+
+func parseKeyList(value string) []string {
+	out := []string{}
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		out = append(out, part)
+	}
+	return out
+}
+
+func (k keymap) validate() error {
+	owners := map[string]keyAction{}
+	for _, d := range defaultKeyBindings {
+		reserved := reservedTableKeys
+		if isPopupKeyAction(d.Action) {
+			reserved = reservedPopupKeys
+		}
+		for _, key := range k.keys[d.Action] {
+			for _, r := range reserved {
+				if key == r {
+					return fmt.Errorf("keybinding %s: %q is reserved", d.Action, key)
+				}
+			}
+			scoped := keyScope(d.Action) + key
+			if other, ok := owners[scoped]; ok {
+				return fmt.Errorf("keybinding conflict: %q is bound to both %s and %s", key, other, d.Action)
+			}
+			owners[scoped] = d.Action
+		}
+	}
+	return nil
+}
+
+func isPopupKeyAction(action keyAction) bool {
+	return strings.HasPrefix(string(action), popupKeyActionPrefix)
+}
+
+func keyScope(action keyAction) string {
+	if isPopupKeyAction(action) {
+		return popupKeyActionPrefix
+	}
+	return ""
+}
+
+// bound returns the keys for action. A zero keymap uses the defaults.
+func (k keymap) bound(action keyAction) []string {
+	if k.keys == nil {
+		for _, d := range defaultKeyBindings {
+			if d.Action == action {
+				return d.Keys
+			}
+		}
+		return nil
+	}
+	return k.keys[action]
+}
+
+// label returns the key shown in help text for action.
+func (k keymap) label(action keyAction) string {
+	keys := k.bound(action)
+	if len(keys) == 0 {
+		return ""
+	}
+	return keys[0]
+}
+
+// action returns the action bound to key within the scope of the given
+// action kind (table or popup).
+func (k keymap) action(key string, popup bool) (keyAction, bool) {
+	for _, d := range defaultKeyBindings {
+		if isPopupKeyAction(d.Action) != popup {
+			continue
+		}
+		for _, bound := range k.bound(d.Action) {
+			if bound == key {
+				return d.Action, true
+			}
+		}
+	}
+	return "", false
+}
+
+// translateTableKey maps a pressed key onto the default key of the table
+// action it is bound to, so existing key switches keep working. Default keys
+// that were remapped away translate to "" and other keys pass through.
+func (k keymap) translateTableKey(key string) string {
+	if action, ok := k.action(key, false); ok {
+		return defaultKeyFor(action)
+	}
+	for _, d := range defaultKeyBindings {
+		if isPopupKeyAction(d.Action) {
+			continue
+		}
+		for _, def := range d.Keys {
+			if def == key {
+				return ""
+			}
+		}
+	}
+	return key
+}
+
+func defaultKeyFor(action keyAction) string {
+	for _, d := range defaultKeyBindings {
+		if d.Action == action {
+			return d.Keys[0]
+		}
+	}
+	return ""
+}
+
+func keyBindingActionNames() []string {
+	names := make([]string, 0, len(defaultKeyBindings))
+	for _, d := range defaultKeyBindings {
+		names = append(names, string(d.Action))
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aixolotls/wtx/internal/core"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestNewKeymapRemapsTableKeys(t *testing.T) {
+	km, err := newKeymap(map[string]string{"delete": "x", "quit": "Q, ctrl+q"})
+	if err != nil {
+		t.Fatalf("newKeymap: %v", err)
+	}
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{key: "x", want: "d"},
+		{key: "d", want: ""},
+		{key: "Q", want: "q"},
+		{key: "ctrl+q", want: "q"},
+		{key: "q", want: ""},
+		{key: "enter", want: "enter"},
+		{key: "down", want: "down"},
+	}
+	for _, tt := range tests {
+		if got := km.translateTableKey(tt.key); got != tt.want {
+			t.Fatalf("translate %q: expected %q, got %q", tt.key, tt.want, got)
+		}
+	}
+	if got := km.label(keyActionQuit); got != "Q" {
+		t.Fatalf("expected quit label Q, got %q", got)
+	}
+}
+
+func TestNewKeymapRejectsInvalidBindings(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string]string
+		want      string
+	}{
+		{name: "unknown action", overrides: map[string]string{"launch": "l"}, want: "unknown keybinding action"},
+		{name: "conflict", overrides: map[string]string{"delete": "r"}, want: "bound to both"},
+		{name: "reserved", overrides: map[string]string{"refresh": "j"}, want: "reserved"},
+		{name: "popup conflict", overrides: map[string]string{"popup.ide": "ctrl+p"}, want: "bound to both"},
+		{name: "empty", overrides: map[string]string{"open": " "}, want: "no keys"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			km, err := newKeymap(tt.overrides)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("expected error containing %q, got %v", tt.want, err)
+			}
+			if got := km.label(keyActionDelete); got != "d" {
+				t.Fatalf("expected defaults on error, got delete=%q", got)
+			}
+		})
+	}
+}
+
+func TestNewKeymapAllowsSameKeyAcrossScopes(t *testing.T) {
+	if _, err := newKeymap(map[string]string{"refresh": "ctrl+r"}); err != nil {
+		t.Fatalf("expected table and popup keys to be independent, got %v", err)
+	}
+}
+
+func TestTmuxActionsModelUsesConfiguredKeybindings(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(core.ConfigDirOverrideEnv, "")
+	if err := core.SaveConfig(core.Config{AgentCommand: "claude", Keybindings: map[string]string{"popup.ide": "ctrl+o"}}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	m := newTmuxActionsModel("/tmp", true, false, false)
+	if !strings.Contains(m.View(), "ctrl+o") {
+		t.Fatalf("expected remapped ide key in view, got %q", m.View())
+	}
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlO})
+	if got := updatedModel.(tmuxActionsModel).chosen; got != tmuxActionIDE {
+		t.Fatalf("expected ctrl+o to choose ide, got %q", got)
+	}
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlL})
+	if got := updatedModel.(tmuxActionsModel).chosen; got != "" {
+		t.Fatalf("expected ctrl+l to be unbound, got %q", got)
+	}
+}
+
+func TestConfigSetKeybindingRejectsConflicts(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(core.ConfigDirOverrideEnv, "")
+	if err := runConfigSet(false, "keybindings.delete", "x"); err != nil {
+		t.Fatalf("set keybinding: %v", err)
+	}
+	cfg, err := core.LoadConfig()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.Keybindings["delete"] != "x" {
+		t.Fatalf("expected delete bound to x, got %v", cfg.Keybindings)
+	}
+	if err := runConfigSet(false, "keybindings.refresh", "x"); err == nil || !strings.Contains(err.Error(), "bound to both") {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+	if err := runConfigUnset(false, "keybindings.delete"); err != nil {
+		t.Fatalf("unset keybinding: %v", err)
+	}
+	cfg, err = core.LoadConfig()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if len(cfg.Keybindings) != 0 {
+		t.Fatalf("expected keybindings removed, got %v", cfg.Keybindings)
+	}
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+func TestLFSCheckoutEnvFollowsSkipSmudgeConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo := initRenameTestRepo(t)
+	runGitInRepo(t, repo, "config", "wtx.lfsSkipSmudge", "true")
+	if env := core.LFSCheckoutEnv(repo); env != nil {
+		t.Fatalf("expected no env for a repo without LFS, got %q", env)
+	}
+	if err := os.WriteFile(filepath.Join(repo, ".gitattributes"), []byte("*.bin filter=lfs diff=lfs merge=lfs -text\n"), 0o644); err != nil {
+		t.Fatalf("write .gitattributes: %v", err)
+	}
+	if env := core.LFSCheckoutEnv(repo); !reflect.DeepEqual(env, []string{core.LFSSkipSmudgeEnv}) {
+		t.Fatalf("expected skip smudge env, got %q", env)
+	}
+	runGitInRepo(t, repo, "config", "wtx.lfsSkipSmudge", "false")
+	if env := core.LFSCheckoutEnv(repo); env != nil {
+		t.Fatalf("expected git config to turn skip smudge off, got %q", env)
+	}
+
+	m := newTmuxActionsModel(repo, false, false, false)
+	found := false
+	for _, item := range m.items {
+		found = found || item.Action == tmuxActionHydrateLFS
+	}
+	if !found {
+		t.Fatalf("expected hydrate LFS action in an LFS repo")
+	}
+}
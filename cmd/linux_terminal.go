@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+const (
+	linuxTerminalGnome   = "gnome-terminal"
+	linuxTerminalKonsole = "konsole"
+	linuxTerminalGeneric = "x-terminal-emulator"
+)
+
+var linuxTerminals = []string{linuxTerminalGnome, linuxTerminalKonsole, linuxTerminalGeneric}
+
+// linuxTerminal returns the terminal the shell tab/window actions launch on a
+// Linux desktop, or "" outside one.
+func linuxTerminal() string {
+	if runtime.GOOS != "linux" {
+		return ""
+	}
+	if strings.TrimSpace(os.Getenv("DISPLAY")) == "" && strings.TrimSpace(os.Getenv("WAYLAND_DISPLAY")) == "" {
+		return ""
+	}
+	configured := ""
+	if cfg, err := core.LoadConfig(); err == nil {
+		configured = cfg.LinuxTerminal
+	}
+	return detectLinuxTerminal(configured)
+}
+
+// detectLinuxTerminal prefers the configured terminal, then the one wtx is
+// running in, then whichever is installed.
+func detectLinuxTerminal(configured string) string {
+	var candidates []string
+	if configured = strings.TrimSpace(configured); configured != "" {
+		candidates = []string{configured}
+	} else {
+		switch {
+		case os.Getenv("GNOME_TERMINAL_SCREEN") != "" || os.Getenv("GNOME_TERMINAL_SERVICE") != "":
+			candidates = append(candidates, linuxTerminalGnome)
+		case os.Getenv("KONSOLE_VERSION") != "":
+			candidates = append(candidates, linuxTerminalKonsole)
+		}
+		candidates = append(candidates, linuxTerminals...)
+	}
+	for _, term := range candidates {
+		if _, err := exec.LookPath(term); err == nil {
+			return term
+		}
+	}
+	return ""
+}
+
+// x-terminal-emulator is whatever Debian's alternatives point at, so only a
+// new window in the working directory can be relied on.
+func linuxTerminalSupportsTabs(term string) bool {
+	return term == linuxTerminalGnome || term == linuxTerminalKonsole
+}
+
+func linuxTerminalLabel(term string) string {
+	switch term {
+	case linuxTerminalGnome:
+		return "GNOME Terminal"
+	case linuxTerminalKonsole:
+		return "Konsole"
+	default:
+		return "terminal"
+	}
+}
+
+func linuxTerminalArgs(term string, path string, tab bool) []string {
+	switch term {
+	case linuxTerminalGnome:
+		if tab {
+			return []string{"--tab", "--working-directory=" + path}
+		}
+		return []string{"--window", "--working-directory=" + path}
+	case linuxTerminalKonsole:
+		if tab {
+			return []string{"--new-tab", "--workdir", path}
+		}
+		return []string{"--workdir", path}
+	default:
+		return nil
+	}
+}
+
+func openShellInLinuxTerminal(term string, path string, tab bool) error {
+	cmd := exec.Command(term, linuxTerminalArgs(term, path, tab)...)
+	cmd.Dir = path
+	// The new shell is outside this tmux session; inheriting TMUX would make
+	// wtx started there act on the wrong session.
+	cmd.Env = envWithout(os.Environ(), "TMUX", "TMUX_PANE")
+	cmd.SysProcAttr = core.DetachedProcAttr()
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	return cmd.Process.Release()
+}
+
+func envWithout(env []string, keys ...string) []string {
+	out := make([]string, 0, len(env))
+	for _, kv := range env {
+		drop := false
+		for _, key := range keys {
+			if strings.HasPrefix(kv, key+"=") {
+				drop = true
+				break
+			}
+		}
+		if !drop {
+			out = append(out, kv)
+		}
+	}
+	return out
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestDetectLinuxTerminal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("stub terminals are shell scripts")
+	}
+	bin := t.TempDir()
+	for _, name := range []string{linuxTerminalKonsole, linuxTerminalGeneric} {
+		if err := os.WriteFile(filepath.Join(bin, name), []byte("#!/bin/sh\n"), 0o755); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	t.Setenv("PATH", bin)
+	t.Setenv("GNOME_TERMINAL_SCREEN", "")
+	t.Setenv("GNOME_TERMINAL_SERVICE", "")
+	t.Setenv("KONSOLE_VERSION", "")
+
+	if got := detectLinuxTerminal(""); got != linuxTerminalKonsole {
+		t.Fatalf("expected first installed terminal, got %q", got)
+	}
+	if got := detectLinuxTerminal(linuxTerminalGeneric); got != linuxTerminalGeneric {
+		t.Fatalf("expected configured terminal, got %q", got)
+	}
+	if got := detectLinuxTerminal(linuxTerminalGnome); got != "" {
+		t.Fatalf("expected no terminal when the configured one is missing, got %q", got)
+	}
+	// Running inside GNOME Terminal does not help when it is not on PATH.
+	t.Setenv("GNOME_TERMINAL_SCREEN", "/org/gnome/Terminal/screen/1")
+	if got := detectLinuxTerminal(""); got != linuxTerminalKonsole {
+		t.Fatalf("expected fallback to an installed terminal, got %q", got)
+	}
+}
+
+func TestLinuxTerminalArgs(t *testing.T) {
+	cases := []struct {
+		term string
+		tab  bool
+		want []string
+	}{
+		{linuxTerminalGnome, true, []string{"--tab", "--working-directory=/repo.wt/wt.1"}},
+		{linuxTerminalGnome, false, []string{"--window", "--working-directory=/repo.wt/wt.1"}},
+		{linuxTerminalKonsole, true, []string{"--new-tab", "--workdir", "/repo.wt/wt.1"}},
+		{linuxTerminalKonsole, false, []string{"--workdir", "/repo.wt/wt.1"}},
+		{linuxTerminalGeneric, false, nil},
+	}
+	for _, tc := range cases {
+		if got := linuxTerminalArgs(tc.term, "/repo.wt/wt.1", tc.tab); !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("%s tab=%v: expected %q, got %q", tc.term, tc.tab, tc.want, got)
+		}
+	}
+	if linuxTerminalSupportsTabs(linuxTerminalGeneric) {
+		t.Fatalf("x-terminal-emulator cannot be relied on to open tabs")
+	}
+}
+
+func TestEnvWithoutDropsTmux(t *testing.T) {
+	got := envWithout([]string{"TMUX=/tmp/tmux-1/default,1,0", "TMUX_PANE=%1", "TMUXX=keep", "HOME=/home/a"}, "TMUX", "TMUX_PANE")
+	if want := []string{"TMUXX=keep", "HOME=/home/a"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,270 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aixolotls/wtx/internal/core"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type bulkAction int
+
+const (
+	bulkDelete bulkAction = iota
+	bulkUnlock
+	bulkFetch
+	bulkOpenCI
+)
+
+var bulkActions = []bulkAction{bulkDelete, bulkUnlock, bulkFetch, bulkOpenCI}
+
+const markKey = " "
+
+func (a bulkAction) label() string {
+	switch a {
+	case bulkUnlock:
+		return "Unlock"
+	case bulkFetch:
+		return "Fetch"
+	case bulkOpenCI:
+		return "Open CI"
+	default:
+		return "Delete"
+	}
+}
+
+func (a bulkAction) confirmTitle(n int) string {
+	noun := "worktree"
+	switch a {
+	case bulkFetch:
+		noun = "branch"
+	case bulkOpenCI:
+		noun = "CI page"
+	}
+	if n != 1 {
+		if noun == "branch" {
+			noun = "branches"
+		} else {
+			noun += "s"
+		}
+	}
+	return fmt.Sprintf("%s %d %s?", a.label(), n, noun)
+}
+
+type bulkActionDoneMsg struct {
+	action   bulkAction
+	done     []string
+	failures []string
+	warnings []string
+}
+
+// markedWorktrees returns the marked worktrees in table order. Marks survive
+// filtering, so this walks the full status rather than listStatus.
+func (m model) markedWorktrees() []core.WorktreeInfo {
+	if len(m.listMarked) == 0 {
+		return nil
+	}
+	status := withoutPendingDeletes(m.status, m.pendingDeletePaths())
+	status.ListSort = m.listSort
+	out := []core.WorktreeInfo{}
+	for _, wt := range worktreesForDisplay(status) {
+		if m.listMarked[wt.Path] {
+			out = append(out, wt)
+		}
+	}
+	return out
+}
+
+func (m model) toggleMark() model {
+	row, ok := selectedWorktree(m.listStatus(), m.listIndex)
+	if !ok {
+		return m
+	}
+	marked := make(map[string]bool, len(m.listMarked)+1)
+	for path := range m.listMarked {
+		marked[path] = true
+	}
+	if marked[row.Path] {
+		delete(marked, row.Path)
+	} else {
+		marked[row.Path] = true
+	}
+	m.listMarked = marked
+	if m.listIndex < selectorRowCount(m.listStatus())-1 {
+		m.listIndex++
+	}
+	return m
+}
+
+// bulkTargets splits rows into those action applies to and a reason for each
+// one it skips.
+func bulkTargets(m model, action bulkAction, rows []core.WorktreeInfo) ([]core.WorktreeInfo, []string) {
+	targets := []core.WorktreeInfo{}
+	skipped := []string{}
+	for _, wt := range rows {
+		orphaned := isOrphanedPath(m.status, wt.Path)
+		reason := ""
+		switch action {
+		case bulkDelete:
+			if err := m.mgr.CanDeleteWorktree(wt.Path); err != nil {
+				reason = err.Error()
+			}
+		case bulkUnlock:
+			if orphaned {
+				reason = "orphaned"
+			} else if wt.Broken != "" {
+				reason = "broken"
+			} else if wt.Available {
+				reason = "not in use"
+			}
+		case bulkFetch:
+			if orphaned {
+				reason = "orphaned"
+			} else if wt.Broken != "" {
+				reason = "broken"
+			} else if strings.TrimSpace(wt.Branch) == "" || wt.Branch == "detached" {
+				reason = "no branch"
+			} else if !m.status.HasRemote {
+				reason = "no remote"
+			}
+		case bulkOpenCI:
+			if strings.TrimSpace(wt.PRURL) == "" {
+				reason = "no PR"
+			}
+		}
+		if reason != "" {
+			skipped = append(skipped, fmt.Sprintf("%s (%s)", wt.Branch, reason))
+			continue
+		}
+		targets = append(targets, wt)
+	}
+	return targets, skipped
+}
+
+func (m model) startBulkConfirm(action bulkAction) (tea.Model, tea.Cmd) {
+	targets, skipped := bulkTargets(m, action, m.markedWorktrees())
+	m.mode = modeList
+	if len(targets) == 0 {
+		m.errMsg = fmt.Sprintf("%s does not apply to any marked worktree.", action.label())
+		return m, nil
+	}
+	lines := make([]string, 0, len(targets)+len(skipped)+1)
+	for _, wt := range targets {
+		lines = append(lines, fmt.Sprintf("%s  %s", wt.Branch, wt.Path))
+	}
+	if len(skipped) > 0 {
+		lines = append(lines, "", "Skipped: "+strings.Join(skipped, ", "))
+	}
+	m.bulkAction = action
+	m.bulkTargets = targets
+	m.confirmResult = false
+	m.confirmKind = confirmBulk
+	m.confirmForm = newConfirmForm(action.confirmTitle(len(targets)), strings.Join(lines, "\n"), &m.confirmResult)
+	m.errMsg = ""
+	return m, m.confirmForm.Init()
+}
+
+func bulkActionCmd(mgr *core.WorktreeManager, runner *Runner, status core.WorktreeStatus, action bulkAction, targets []core.WorktreeInfo) tea.Cmd {
+	return func() tea.Msg {
+		msg := bulkActionDoneMsg{action: action}
+		for _, wt := range targets {
+			var err error
+			switch action {
+			case bulkDelete:
+				var warnings []string
+				warnings, err = mgr.DeleteWorktree(wt.Path, isOrphanedPath(status, wt.Path))
+				for _, w := range warnings {
+					msg.warnings = append(msg.warnings, fmt.Sprintf("%s: %s", wt.Branch, w))
+				}
+			case bulkUnlock:
+				err = mgr.UnlockWorktree(wt.Path)
+			case bulkFetch:
+				err = mgr.FetchBranch(wt.Branch)
+			case bulkOpenCI:
+				err = runner.OpenURL(strings.TrimRight(wt.PRURL, "/") + "/checks")
+			}
+			if err != nil {
+				msg.failures = append(msg.failures, fmt.Sprintf("%s: %v", wt.Branch, err))
+				continue
+			}
+			msg.done = append(msg.done, wt.Path)
+		}
+		return msg
+	}
+}
+
+// applyBulkActionDone unmarks the worktrees the action succeeded on and
+// reports the rest.
+func (m model) applyBulkActionDone(msg bulkActionDoneMsg) (tea.Model, tea.Cmd) {
+	m.bulkRunning = false
+	marked := make(map[string]bool, len(m.listMarked))
+	for path := range m.listMarked {
+		marked[path] = true
+	}
+	for _, path := range msg.done {
+		delete(marked, path)
+	}
+	m.listMarked = marked
+	m.errMsg = ""
+	if len(msg.failures) > 0 {
+		m.errMsg = fmt.Sprintf("%s failed for %s", msg.action.label(), strings.Join(msg.failures, "; "))
+	}
+	if len(msg.warnings) > 0 {
+		m.warnMsg = strings.Join(msg.warnings, "; ")
+	}
+	next, queued := m.runQueuedDelete()
+	if msg.action == bulkOpenCI {
+		return next, queued
+	}
+	return next, tea.Batch(fetchStatusCmd(m.orchestrator), queued)
+}
+
+func (m model) updateBulkMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.mode = modeList
+		m.bulkIndex = 0
+		return m, nil
+	case "up", "k":
+		if m.bulkIndex > 0 {
+			m.bulkIndex--
+		}
+	case "down", "j":
+		if m.bulkIndex < len(bulkActions)-1 {
+			m.bulkIndex++
+		}
+	case "enter":
+		action := bulkActions[m.bulkIndex]
+		m.bulkIndex = 0
+		return m.startBulkConfirm(action)
+	}
+	return m, nil
+}
+
+func renderBulkMenu(m model) string {
+	var b strings.Builder
+	marked := m.markedWorktrees()
+	b.WriteString(fmt.Sprintf("Bulk actions for %d marked worktrees:\n", len(marked)))
+	for i, action := range bulkActions {
+		targets, _ := bulkTargets(m, action, marked)
+		item := fmt.Sprintf("%s (%d)", action.label(), len(targets))
+		line := "  " + actionNormalStyle.Render(item)
+		if len(targets) == 0 {
+			line = "  " + selectorDisabledStyle.Render(item)
+		}
+		if i == m.bulkIndex {
+			line = "  " + actionSelectedStyle.Render(item)
+		}
+		b.WriteString(line + "\n")
+	}
+	if m.errMsg != "" {
+		b.WriteString("\n")
+		b.WriteString(errorStyle.Render(m.errMsg))
+		b.WriteString("\n")
+	}
+	b.WriteString("\nPress enter to select, esc to cancel.\n")
+	return b.String()
+}
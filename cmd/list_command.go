@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aixolotls/wtx/internal/core"
+	"github.com/spf13/cobra"
+)
+
+type worktreeListEntry struct {
+	Path                string         `json:"path"`
+	Branch              string         `json:"branch"`
+	Available           bool           `json:"available"`
+	Orphaned            bool           `json:"orphaned"`
+	Broken              string         `json:"broken,omitempty"`
+	Idle                bool           `json:"idle"`
+	LockOwner           string         `json:"lock_owner,omitempty"`
+	LockPID             int            `json:"lock_pid,omitempty"`
+	LastUsedUnix        int64          `json:"last_used_unix,omitempty"`
+	AgentExit           string         `json:"agent_exit,omitempty"`
+	Bootstrap           string         `json:"bootstrap,omitempty"`
+	Tests               string         `json:"tests,omitempty"`
+	HasPR               bool           `json:"has_pr"`
+	PRNumber            int            `json:"pr_number,omitempty"`
+	PRURL               string         `json:"pr_url,omitempty"`
+	PRStatus            string         `json:"pr_status,omitempty"`
+	CIState             core.PRCIState `json:"ci_state,omitempty"`
+	CIDone              int            `json:"ci_done,omitempty"`
+	CITotal             int            `json:"ci_total,omitempty"`
+	CIFailingNames      string         `json:"ci_failing_names,omitempty"`
+	Approved            bool           `json:"approved"`
+	ReviewApproved      int            `json:"review_approved,omitempty"`
+	ReviewRequired      int            `json:"review_required,omitempty"`
+	ReviewKnown         bool           `json:"review_known"`
+	UnresolvedComments  int            `json:"unresolved_comments,omitempty"`
+	ResolvedComments    int            `json:"resolved_comments,omitempty"`
+	CommentThreadsTotal int            `json:"comment_threads_total,omitempty"`
+	CommentsKnown       bool           `json:"comments_known"`
+	LastCommitSubject   string         `json:"last_commit_subject,omitempty"`
+	LastCommitUnix      int64          `json:"last_commit_unix,omitempty"`
+	Note                string         `json:"note,omitempty"`
+	Pinned              bool           `json:"pinned,omitempty"`
+	LockHolder          string         `json:"lock_holder,omitempty"`
+}
+
+type worktreeListOutput struct {
+	RepoRoot  string              `json:"repo_root"`
+	BaseRef   string              `json:"base_ref,omitempty"`
+	Worktrees []worktreeListEntry `json:"worktrees"`
+	Malformed []string            `json:"malformed,omitempty"`
+}
+
+func newListCommand() *cobra.Command {
+	var asJSON bool
+	var noPR bool
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List worktrees without the interactive UI",
+		Args:    cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runList(os.Stdout, asJSON, !noPR)
+		},
+	}
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print machine-readable JSON")
+	cmd.Flags().BoolVar(&noPR, "no-pr", false, "Skip fetching pull request, CI, and review data")
+	return cmd
+}
+
+func runList(w io.Writer, asJSON bool, withPR bool) error {
+	lockMgr := core.NewLockManager()
+	orchestrator := core.NewWorktreeOrchestrator(core.NewWorktreeManager("", lockMgr), lockMgr, core.NewGHManager())
+	status := orchestrator.Status()
+	if status.Err != nil {
+		return status.Err
+	}
+	if !status.InRepo {
+		return fmt.Errorf("not inside a git repository")
+	}
+	if withPR {
+		byBranch, err := orchestrator.PRDataForStatusWithError(status, false)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "wtx warning: PR data unavailable:", err)
+		} else {
+			applyPRDataToStatus(&status, byBranch)
+		}
+	}
+	out := buildWorktreeListOutput(status, lockMgr)
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "BRANCH\tSTATE\tPR\tLAST COMMIT\tPATH")
+	now := time.Now()
+	for _, e := range out.Worktrees {
+		pr := "-"
+		if e.HasPR {
+			pr = fmt.Sprintf("#%d %s", e.PRNumber, strings.ToLower(e.PRStatus))
+		}
+		lastCommit := formatLastCommitLabel(core.WorktreeInfo{LastCommit: core.BranchCommit{Subject: e.LastCommitSubject, CommittedUnix: e.LastCommitUnix}}, now)
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", e.Branch, worktreeListState(e), strings.TrimSpace(pr), lastCommit, e.Path)
+	}
+	return tw.Flush()
+}
+
+func buildWorktreeListOutput(status core.WorktreeStatus, lockMgr *core.LockManager) worktreeListOutput {
+	orphaned := make(map[string]bool, len(status.Orphaned))
+	for _, wt := range status.Orphaned {
+		orphaned[wt.Path] = true
+	}
+	out := worktreeListOutput{
+		RepoRoot:  status.RepoRoot,
+		BaseRef:   status.BaseRef,
+		Worktrees: make([]worktreeListEntry, 0, len(status.Worktrees)),
+		Malformed: status.Malformed,
+	}
+	for _, wt := range status.Worktrees {
+		entry := worktreeListEntry{
+			Path:                wt.Path,
+			Branch:              wt.Branch,
+			Available:           wt.Available,
+			Orphaned:            orphaned[wt.Path],
+			Broken:              wt.Broken,
+			Idle:                wt.Idle,
+			LastUsedUnix:        wt.LastUsedUnix,
+			AgentExit:           wt.AgentExit,
+			Bootstrap:           wt.Bootstrap,
+			Tests:               wt.Tests,
+			HasPR:               wt.HasPR,
+			PRNumber:            wt.PRNumber,
+			PRURL:               wt.PRURL,
+			PRStatus:            wt.PRStatus,
+			CIDone:              wt.CIDone,
+			CITotal:             wt.CITotal,
+			CIFailingNames:      wt.CIFailingNames,
+			Approved:            wt.Approved,
+			ReviewApproved:      wt.ReviewApproved,
+			ReviewRequired:      wt.ReviewRequired,
+			ReviewKnown:         wt.ReviewKnown,
+			UnresolvedComments:  wt.UnresolvedComments,
+			ResolvedComments:    wt.ResolvedComments,
+			CommentThreadsTotal: wt.CommentThreadsTotal,
+			CommentsKnown:       wt.CommentsKnown,
+			LastCommitSubject:   wt.LastCommit.Subject,
+			LastCommitUnix:      wt.LastCommit.CommittedUnix,
+			Note:                wt.Note,
+			Pinned:              wt.Pinned,
+			LockHolder:          wt.LockHolder.String(),
+		}
+		if wt.HasPR {
+			entry.CIState = wt.CIState
+		}
+		if !wt.Available && lockMgr != nil && !entry.Orphaned && entry.Broken == "" {
+			entry.LockOwner, _ = lockMgr.HolderOwnerID(status.RepoRoot, wt.Path)
+			entry.LockPID, _ = lockMgr.HolderPID(status.RepoRoot, wt.Path)
+		}
+		out.Worktrees = append(out.Worktrees, entry)
+	}
+	return out
+}
+
+func worktreeListState(e worktreeListEntry) string {
+	switch {
+	case e.Orphaned:
+		return "orphaned"
+	case e.Broken != "":
+		return "broken"
+	case e.Idle:
+		return "idle"
+	case !e.Available:
+		return "in use"
+	case e.Bootstrap != "":
+		return e.Bootstrap
+	case e.AgentExit != "":
+		return e.AgentExit
+	default:
+		return "free"
+	}
+}
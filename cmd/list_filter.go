@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/aixolotls/wtx/internal/core"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// listStatus is the status as shown in the worktree table, narrowed to rows
+// matching the current filter and in the current sort order. listIndex always
+// indexes into this view.
+func (m model) listStatus() core.WorktreeStatus {
+	status := filterWorktreeStatus(withoutPendingDeletes(m.status, m.pendingDeletePaths()), m.listFilter)
+	status.ListSort = m.listSort
+	return status
+}
+
+// filterWorktreeStatus keeps worktrees whose branch, path or PR number
+// contain every whitespace separated term of query, ignoring case.
+func filterWorktreeStatus(status core.WorktreeStatus, query string) core.WorktreeStatus {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return status
+	}
+	filtered := status
+	filtered.Worktrees = make([]core.WorktreeInfo, 0, len(status.Worktrees))
+	for _, wt := range status.Worktrees {
+		if worktreeMatchesFilter(wt, terms) {
+			filtered.Worktrees = append(filtered.Worktrees, wt)
+		}
+	}
+	filtered.Orphaned = make([]core.WorktreeInfo, 0, len(status.Orphaned))
+	for _, wt := range status.Orphaned {
+		if worktreeMatchesFilter(wt, terms) {
+			filtered.Orphaned = append(filtered.Orphaned, wt)
+		}
+	}
+	return filtered
+}
+
+func worktreeMatchesFilter(wt core.WorktreeInfo, terms []string) bool {
+	haystack := strings.ToLower(wt.Branch + " " + wt.Path)
+	if wt.PRNumber > 0 {
+		haystack += " #" + strconv.Itoa(wt.PRNumber)
+	}
+	for _, term := range terms {
+		if !strings.Contains(haystack, term) {
+			return false
+		}
+	}
+	return true
+}
+
+// updateListFilter handles keys while the `/` filter prompt is active. Enter
+// keeps the filter and returns to normal table keys; esc drops it.
+func (m model) updateListFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.listFiltering = false
+		m.listFilter = ""
+	case "enter":
+		m.listFiltering = false
+		return m, nil
+	case "up":
+		if m.listIndex > 0 {
+			m.listIndex--
+		}
+		return m, nil
+	case "down":
+		if m.listIndex < selectorRowCount(m.listStatus())-1 {
+			m.listIndex++
+		}
+		return m, nil
+	case "backspace":
+		if m.listFilter != "" {
+			_, size := utf8.DecodeLastRuneInString(m.listFilter)
+			m.listFilter = m.listFilter[:len(m.listFilter)-size]
+		}
+	case "ctrl+u":
+		m.listFilter = ""
+	default:
+		if msg.Type != tea.KeyRunes && msg.Type != tea.KeySpace {
+			return m, nil
+		}
+		m.listFilter += string(msg.Runes)
+		m.listIndex = 0
+	}
+	m.listIndex = clampListIndex(m.listIndex, m.listStatus())
+	return m, nil
+}
+
+func renderListFilter(m model) string {
+	if !m.listFiltering && m.listFilter == "" {
+		return ""
+	}
+	shown := len(m.listStatus().Worktrees)
+	line := "/" + m.listFilter
+	if m.listFiltering {
+		line += "_"
+	}
+	return secondaryStyle.Render(line + "  (" + strconv.Itoa(shown) + " of " + strconv.Itoa(len(m.status.Worktrees)) + ")")
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+// worktreeLess orders worktrees for the table. Pinned worktrees come first in
+// every mode, and every mode falls back to the status order so rows never
+// jump around between refreshes.
+func worktreeLess(mode core.ListSortMode, a core.WorktreeInfo, b core.WorktreeInfo, orphaned map[string]bool) bool {
+	if a.Pinned != b.Pinned {
+		return a.Pinned
+	}
+	switch mode {
+	case core.ListSortBranch:
+		aBranch := strings.ToLower(strings.TrimSpace(a.Branch))
+		bBranch := strings.ToLower(strings.TrimSpace(b.Branch))
+		if aBranch != bBranch {
+			return aBranch < bBranch
+		}
+	case core.ListSortPR:
+		// Worktrees without a PR go last; newer PRs first.
+		if a.PRNumber != b.PRNumber {
+			if a.PRNumber == 0 || b.PRNumber == 0 {
+				return a.PRNumber != 0
+			}
+			return a.PRNumber > b.PRNumber
+		}
+	case core.ListSortCI:
+		if ar, br := ciSortRank(a.CIState), ciSortRank(b.CIState); ar != br {
+			return ar < br
+		}
+	case core.ListSortActivity:
+		if aAt, bAt := lastActivityUnix(a), lastActivityUnix(b); aAt != bAt {
+			return aAt > bAt
+		}
+	}
+	return worktreeLessByStatus(a, b, orphaned)
+}
+
+// worktreeLessByStatus lists free worktrees first, most recently used first.
+func worktreeLessByStatus(a core.WorktreeInfo, b core.WorktreeInfo, orphaned map[string]bool) bool {
+	aFree := a.Available && !orphaned[a.Path]
+	bFree := b.Available && !orphaned[b.Path]
+	if aFree != bFree {
+		return aFree
+	}
+	if aFree && bFree && a.LastUsedUnix != b.LastUsedUnix {
+		return a.LastUsedUnix > b.LastUsedUnix
+	}
+	aBranch := strings.ToLower(strings.TrimSpace(a.Branch))
+	bBranch := strings.ToLower(strings.TrimSpace(b.Branch))
+	if aBranch != bBranch {
+		return aBranch > bBranch
+	}
+	return a.Path > b.Path
+}
+
+// ciSortRank puts failing checks first since those need attention.
+func ciSortRank(state core.PRCIState) int {
+	switch state {
+	case core.PRCIFail:
+		return 0
+	case core.PRCIInProgress:
+		return 1
+	case core.PRCISuccess:
+		return 2
+	default:
+		return 3
+	}
+}
+
+func lastActivityUnix(wt core.WorktreeInfo) int64 {
+	if wt.LastCommit.CommittedUnix > wt.LastUsedUnix {
+		return wt.LastCommit.CommittedUnix
+	}
+	return wt.LastUsedUnix
+}
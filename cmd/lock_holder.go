@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+func formatLockHolderLine(wt core.WorktreeInfo) string {
+	holder := wt.LockHolder.String()
+	if holder == "" {
+		return ""
+	}
+	return fmt.Sprintf("In use by %s", holder)
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/aixolotls/wtx/internal/core"
+	"github.com/spf13/cobra"
+)
+
+func newLogsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Manage agent, debug and crash logs under ~/.wtx",
+	}
+	var dryRun bool
+	prune := &cobra.Command{
+		Use:   "prune",
+		Short: "Delete logs past log_retention_days, agent_log_keep or log_max_mb",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runLogsPrune(os.Stdout, dryRun)
+		},
+	}
+	prune.Flags().BoolVar(&dryRun, "dry-run", false, "List what would be deleted without deleting it")
+	cmd.AddCommand(prune)
+	return cmd
+}
+
+func runLogsPrune(w io.Writer, dryRun bool) error {
+	cfg, err := core.LoadConfig()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	pruned, err := core.PruneLogs(cfg, time.Now(), dryRun)
+	var freed int64
+	for _, p := range pruned {
+		freed += p.Size
+		fmt.Fprintf(w, "%s (%s)\n", p.Path, p.Reason)
+	}
+	verb := "Deleted"
+	if dryRun {
+		verb = "Would delete"
+	}
+	fmt.Fprintf(w, "%s %d files, %.1f MB.\n", verb, len(pruned), float64(freed)/(1<<20))
+	return err
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeAgedLog(t *testing.T, path string, size int, age time.Duration) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, bytes.Repeat([]byte("x"), size), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+	ts := time.Now().Add(-age)
+	if err := os.Chtimes(path, ts, ts); err != nil {
+		t.Fatalf("chtimes %s: %v", path, err)
+	}
+}
+
+func TestRunLogsPruneReportsFreedSpace(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeAgedLog(t, filepath.Join(home, ".wtx", "crashes", "crash.txt"), 1<<20, 60*24*time.Hour)
+
+	var out bytes.Buffer
+	if err := runLogsPrune(&out, true); err != nil {
+		t.Fatalf("runLogsPrune: %v", err)
+	}
+	if !strings.Contains(out.String(), "crash.txt (older than 30 days)") || !strings.Contains(out.String(), "Would delete 1 files, 1.0 MB.") {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+}
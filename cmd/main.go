@@ -1,6 +1,14 @@
 package cmd
 
-func Run(args []string) error {
+import (
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+func Run(args []string) (err error) {
+	core.EnableTracing(args)
+	defer func() { core.FinishTracing(err) }()
+	defer recoverCrash(&err)
+	cleanupReplacedExecutable()
 	maybeStartInvocationUpdateCheck(args)
 	cmd := newRootCommand(args)
 	return cmd.Execute()
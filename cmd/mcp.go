@@ -0,0 +1,300 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+const (
+	mcpProtocolVersion = "2025-06-18"
+
+	mcpToolListWorktrees  = "list_worktrees"
+	mcpToolCreateWorktree = "create_worktree"
+	mcpToolPRStatus       = "pr_status"
+	mcpToolReleaseLock    = "release_lock"
+)
+
+type mcpTool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+}
+
+type mcpToolCallParams struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+type mcpContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// mcpToolResult reports tool failures with IsError rather than a JSON-RPC
+// error, so the agent sees the message and can react to it.
+type mcpToolResult struct {
+	Content []mcpContent `json:"content"`
+	IsError bool         `json:"isError,omitempty"`
+}
+
+type mcpListArgs struct {
+	PR bool `json:"pr,omitempty"`
+}
+
+type mcpCreateArgs struct {
+	Branch   string `json:"branch"`
+	BaseRef  string `json:"base_ref,omitempty"`
+	Existing bool   `json:"existing,omitempty"`
+}
+
+type mcpPRStatusArgs struct {
+	Branch string `json:"branch,omitempty"`
+}
+
+type mcpReleaseLockArgs struct {
+	Path string `json:"path,omitempty"`
+}
+
+// mcpPRStatus uses the field names of `wtx list --json`.
+type mcpPRStatus struct {
+	Branch             string         `json:"branch"`
+	HasPR              bool           `json:"has_pr"`
+	PRNumber           int            `json:"pr_number,omitempty"`
+	PRURL              string         `json:"pr_url,omitempty"`
+	PRStatus           string         `json:"pr_status,omitempty"`
+	CIState            core.PRCIState `json:"ci_state,omitempty"`
+	CIDone             int            `json:"ci_done,omitempty"`
+	CITotal            int            `json:"ci_total,omitempty"`
+	CIFailingNames     string         `json:"ci_failing_names,omitempty"`
+	Approved           bool           `json:"approved"`
+	ReviewApproved     int            `json:"review_approved,omitempty"`
+	ReviewRequired     int            `json:"review_required,omitempty"`
+	UnresolvedComments int            `json:"unresolved_comments,omitempty"`
+}
+
+func mcpObjectSchema(required []string, props map[string]any) map[string]any {
+	schema := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+var mcpTools = []mcpTool{
+	{
+		Name:        mcpToolListWorktrees,
+		Description: "List the repository's worktrees with branch, path, whether each is free or in use, and who holds it.",
+		InputSchema: mcpObjectSchema(nil, map[string]any{
+			"pr": map[string]any{"type": "boolean", "description": "Include pull request, CI, and review status"},
+		}),
+	},
+	{
+		Name:        mcpToolCreateWorktree,
+		Description: "Create a sibling worktree on a new branch, or on an existing branch with existing=true. The worktree is not locked. The repo's bootstrap command and hooks run only when the user has trusted its .wtx.json.",
+		InputSchema: mcpObjectSchema([]string{"branch"}, map[string]any{
+			"branch":   map[string]any{"type": "string"},
+			"base_ref": map[string]any{"type": "string", "description": "Ref to start a new branch from; defaults to the configured base"},
+			"existing": map[string]any{"type": "boolean", "description": "Check out an existing branch instead of creating one"},
+		}),
+	},
+	{
+		Name:        mcpToolPRStatus,
+		Description: "Show the pull request, CI, and review state for a branch, defaulting to the current worktree's branch.",
+		InputSchema: mcpObjectSchema(nil, map[string]any{
+			"branch": map[string]any{"type": "string"},
+		}),
+	},
+	{
+		Name:        mcpToolReleaseLock,
+		Description: "Release the wtx lock on the current worktree so it can be reused once the task is done.",
+		InputSchema: mcpObjectSchema(nil, map[string]any{
+			"path": map[string]any{"type": "string", "description": "Worktree path; must be the current worktree"},
+		}),
+	},
+}
+
+// mcpServer speaks the Model Context Protocol over stdio for the agent
+// running in cwd. It reuses the serve handlers so both front ends agree.
+type mcpServer struct {
+	serve *serveServer
+	cwd   string
+}
+
+func newMCPServer(cwd string) *mcpServer {
+	return &mcpServer{serve: newServeServer(cwd), cwd: cwd}
+}
+
+func (s *mcpServer) handle(req rpcRequest) rpcResponse {
+	resp := rpcResponse{JSONRPC: jsonRPCVersion, ID: req.ID}
+	switch req.Method {
+	case "initialize":
+		resp.Result = map[string]any{
+			"protocolVersion": mcpProtocolVersion,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]any{"name": "wtx", "version": core.CurrentVersion()},
+		}
+	case "ping":
+		resp.Result = map[string]any{}
+	case "tools/list":
+		resp.Result = map[string]any{"tools": mcpTools}
+	case "tools/call":
+		var p mcpToolCallParams
+		if err := decodeRPCParams(req.Params, &p); err != nil {
+			resp.Error = err
+			return resp
+		}
+		result, err := s.callTool(p)
+		if err != nil {
+			resp.Error = err
+			return resp
+		}
+		resp.Result = result
+	default:
+		if strings.HasPrefix(req.Method, "notifications/") {
+			return resp
+		}
+		resp.Error = &rpcError{Code: rpcMethodNotFound, Message: fmt.Sprintf("unknown method %q", req.Method)}
+	}
+	return resp
+}
+
+func (s *mcpServer) callTool(p mcpToolCallParams) (mcpToolResult, *rpcError) {
+	var (
+		out any
+		err error
+	)
+	switch p.Name {
+	case mcpToolListWorktrees:
+		var args mcpListArgs
+		if rerr := decodeRPCParams(p.Arguments, &args); rerr != nil {
+			return mcpToolResult{}, rerr
+		}
+		out, err = s.serve.list(serveListParams{PR: args.PR})
+	case mcpToolCreateWorktree:
+		var args mcpCreateArgs
+		if rerr := decodeRPCParams(p.Arguments, &args); rerr != nil {
+			return mcpToolResult{}, rerr
+		}
+		out, err = s.serve.create(serveCreateParams{Branch: args.Branch, BaseRef: args.BaseRef, Existing: args.Existing})
+	case mcpToolPRStatus:
+		var args mcpPRStatusArgs
+		if rerr := decodeRPCParams(p.Arguments, &args); rerr != nil {
+			return mcpToolResult{}, rerr
+		}
+		out, err = s.prStatus(args)
+	case mcpToolReleaseLock:
+		var args mcpReleaseLockArgs
+		if rerr := decodeRPCParams(p.Arguments, &args); rerr != nil {
+			return mcpToolResult{}, rerr
+		}
+		out, err = s.releaseLock(args)
+	default:
+		return mcpToolResult{}, &rpcError{Code: rpcInvalidParams, Message: fmt.Sprintf("unknown tool %q", p.Name)}
+	}
+	if err != nil {
+		text := err.Error()
+		if hint := errorRemediation(text); hint != "" {
+			text += "\n" + hint
+		}
+		return mcpToolResult{Content: []mcpContent{{Type: "text", Text: text}}, IsError: true}, nil
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return mcpToolResult{}, &rpcError{Code: rpcServerError, Message: err.Error()}
+	}
+	return mcpToolResult{Content: []mcpContent{{Type: "text", Text: string(data)}}}, nil
+}
+
+// currentWorktree returns the worktree the agent runs in.
+func (s *mcpServer) currentWorktree() (core.WorktreeInfo, core.WorktreeStatus, error) {
+	_, orchestrator := s.serve.managers(s.cwd)
+	status, err := s.serve.status(orchestrator)
+	if err != nil {
+		return core.WorktreeInfo{}, status, err
+	}
+	root, err := core.RepoRootForDir(s.cwd, "git")
+	if err != nil {
+		return core.WorktreeInfo{}, status, err
+	}
+	for _, wt := range status.Worktrees {
+		if core.SameSessionPath(wt.Path, root) {
+			return wt, status, nil
+		}
+	}
+	return core.WorktreeInfo{}, status, fmt.Errorf("%s is not a worktree of %s", root, status.RepoRoot)
+}
+
+func (s *mcpServer) prStatus(args mcpPRStatusArgs) (mcpPRStatus, error) {
+	branch := strings.TrimSpace(args.Branch)
+	_, orchestrator := s.serve.managers(s.cwd)
+	var repoRoot string
+	if branch == "" {
+		wt, status, err := s.currentWorktree()
+		if err != nil {
+			return mcpPRStatus{}, err
+		}
+		branch, repoRoot = strings.TrimSpace(wt.Branch), status.RepoRoot
+		if branch == "" || branch == "detached" {
+			return mcpPRStatus{}, errors.New("current worktree has no branch checked out")
+		}
+	} else {
+		_, root, err := core.RequireGitContext(s.cwd)
+		if err != nil {
+			return mcpPRStatus{}, err
+		}
+		repoRoot = root
+	}
+	byBranch, err := orchestrator.PRDataForBranchesWithError(repoRoot, []string{branch}, false)
+	if err != nil {
+		return mcpPRStatus{}, err
+	}
+	out := mcpPRStatus{Branch: branch}
+	if pr, ok := byBranch[branch]; ok && pr.Number > 0 {
+		out.HasPR = true
+		out.PRNumber = pr.Number
+		out.PRURL = pr.URL
+		out.PRStatus = pr.Status
+		out.CIState = pr.CIState
+		out.CIDone = pr.CICompleted
+		out.CITotal = pr.CITotal
+		out.CIFailingNames = pr.CIFailingNames
+		out.Approved = pr.Approved
+		out.ReviewApproved = pr.ReviewApproved
+		out.ReviewRequired = pr.ReviewRequired
+		out.UnresolvedComments = pr.UnresolvedComments
+	}
+	return out, nil
+}
+
+// releaseLock only touches the agent's own worktree; sibling locks belong to
+// other sessions that may still be working in them.
+func (s *mcpServer) releaseLock(args mcpReleaseLockArgs) (serveWorktreeResult, error) {
+	wt, _, err := s.currentWorktree()
+	if err != nil {
+		return serveWorktreeResult{}, err
+	}
+	if path := strings.TrimSpace(args.Path); path != "" && !core.SameSessionPath(path, wt.Path) {
+		return serveWorktreeResult{}, fmt.Errorf("release_lock only releases the current worktree %s", wt.Path)
+	}
+	return s.serve.unlock(serveLockParams{serveParams: serveParams{CWD: s.cwd}, Path: wt.Path, Force: true})
+}
+
+func runMCP(r io.Reader, w io.Writer, cwd string) error {
+	server := newMCPServer(cwd)
+	defer server.serve.releaseAll()
+	return serveRPC(r, w, server.handle)
+}
+
+func runMCPCommand() error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	return runMCP(os.Stdin, os.Stdout, cwd)
+}
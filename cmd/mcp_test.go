@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+func TestMCPListsToolsAndReleasesOwnLock(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("TMUX", "")
+	t.Setenv("TMUX_PANE", "")
+	repo := initGitReadTestRepo(t)
+	lockMgr := core.NewLockManager()
+	if _, err := lockMgr.AcquireForOwner(repo, repo, "agent-session", os.Getpid()); err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+
+	in := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"release_lock","arguments":{"path":"/elsewhere"}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"release_lock"}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"list_worktrees"}}`,
+	}, "\n") + "\n"
+	var out bytes.Buffer
+	if err := runMCP(strings.NewReader(in), &out, repo); err != nil {
+		t.Fatalf("runMCP: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("expected 5 responses (none for the notification), got %d:\n%s", len(lines), out.String())
+	}
+	var tools struct {
+		Result struct {
+			Tools []mcpTool `json:"tools"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &tools); err != nil {
+		t.Fatalf("decode tools: %v", err)
+	}
+	var names []string
+	for _, tool := range tools.Result.Tools {
+		names = append(names, tool.Name)
+	}
+	if got := strings.Join(names, ","); got != "list_worktrees,create_worktree,pr_status,release_lock" {
+		t.Fatalf("unexpected tools %s", got)
+	}
+
+	call := func(line string) mcpToolResult {
+		t.Helper()
+		var resp struct {
+			Result mcpToolResult `json:"result"`
+		}
+		if err := json.Unmarshal([]byte(line), &resp); err != nil {
+			t.Fatalf("decode %s: %v", line, err)
+		}
+		return resp.Result
+	}
+	if res := call(lines[2]); !res.IsError || !strings.Contains(res.Content[0].Text, "only releases the current worktree") {
+		t.Fatalf("expected sibling release to be refused, got %+v", res)
+	}
+	if res := call(lines[3]); res.IsError {
+		t.Fatalf("release_lock failed: %+v", res)
+	}
+	if available, err := lockMgr.IsAvailable(repo, repo); err != nil || !available {
+		t.Fatalf("expected lock released, available=%v err=%v", available, err)
+	}
+	res := call(lines[4])
+	var listed worktreeListOutput
+	if res.IsError || json.Unmarshal([]byte(res.Content[0].Text), &listed) != nil || len(listed.Worktrees) != 1 {
+		t.Fatalf("unexpected list_worktrees result %+v", res)
+	}
+}
+
+func TestMCPCreateWorktreeSkipsUntrustedBootstrap(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("TMUX", "")
+	t.Setenv("TMUX_PANE", "")
+	repo := initRenameTestRepo(t)
+	if err := os.WriteFile(filepath.Join(repo, core.RepoConfigFileName), []byte(`{"bootstrap_command": "touch bootstrapped"}`), 0o644); err != nil {
+		t.Fatalf("write repo config: %v", err)
+	}
+	runGitInRepo(t, repo, "add", core.RepoConfigFileName)
+	runGitInRepo(t, repo, "commit", "-m", "wtx config")
+
+	create := func(branch string) serveWorktreeResult {
+		t.Helper()
+		in := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"create_worktree","arguments":{"branch":"` + branch + `","base_ref":"HEAD"}}}` + "\n"
+		var out bytes.Buffer
+		if err := runMCP(strings.NewReader(in), &out, repo); err != nil {
+			t.Fatalf("runMCP: %v", err)
+		}
+		var resp struct {
+			Result mcpToolResult `json:"result"`
+		}
+		if err := json.Unmarshal(out.Bytes(), &resp); err != nil || resp.Result.IsError || len(resp.Result.Content) != 1 {
+			t.Fatalf("create_worktree failed: %s (%v)", out.String(), err)
+		}
+		var result serveWorktreeResult
+		if err := json.Unmarshal([]byte(resp.Result.Content[0].Text), &result); err != nil {
+			t.Fatalf("decode result: %v", err)
+		}
+		return result
+	}
+
+	untrusted := create("agent-task")
+	if _, ok := core.ReadWorktreeJobState(core.JobBootstrap, untrusted.Path); ok {
+		t.Fatalf("expected no bootstrap from an untrusted .wtx.json")
+	}
+	if !strings.Contains(untrusted.Warning, "bootstrap_command") {
+		t.Fatalf("expected a warning naming the skipped bootstrap, got %q", untrusted.Warning)
+	}
+
+	t.Chdir(repo)
+	if err := runConfigTrust(&bytes.Buffer{}); err != nil {
+		t.Fatalf("trust: %v", err)
+	}
+	trusted := create("agent-task-2")
+	if state := waitForJob(t, core.JobBootstrap, trusted.Path); state.State != core.JobDone {
+		t.Fatalf("expected the trusted bootstrap to run, got %+v", state)
+	}
+	if trusted.Warning != "" {
+		t.Fatalf("expected no warning once trusted, got %q", trusted.Warning)
+	}
+}
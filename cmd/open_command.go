@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newOpenCommand() *cobra.Command {
+	var create bool
+	var shell bool
+	var prompt string
+	var fromPick bool
+
+	cmd := &cobra.Command{
+		Use:   "open <branch>",
+		Short: "Open an existing branch in its worktree without any prompts",
+		Long: "Resolves <branch> to a worktree, locks it, and launches the agent (or a shell with --shell).\n\n" +
+			"Fails instead of prompting when no worktree is free; pass --create to add one.",
+		Example: strings.Join([]string{
+			"  wtx open feature/auth-flow",
+			"  wtx open feature/auth-flow --create",
+			"  wtx open bugfix/login-timeout --shell",
+			"  wtx open --from-pick \"$(wtx pick | fzf)\"",
+		}, "\n"),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				return nil
+			}
+			if len(args) == 0 && fromPick {
+				return nil
+			}
+			if len(args) == 0 {
+				return usageError(cmd, "missing branch argument")
+			}
+			return usageError(cmd, "too many arguments; provide exactly one branch name")
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if shell && strings.TrimSpace(prompt) != "" {
+				return usageError(cmd, "--prompt cannot be used with --shell")
+			}
+			branch := ""
+			if len(args) == 1 {
+				branch = args[0]
+			}
+			if fromPick {
+				line := branch
+				if len(args) == 0 {
+					var err error
+					if line, err = readPickLine(os.Stdin); err != nil {
+						return err
+					}
+				}
+				var err error
+				if branch, err = parsePickLine(line); err != nil {
+					return err
+				}
+			}
+			return runCheckout(branch, checkoutOptions{
+				Prompt:         prompt,
+				Shell:          shell,
+				NonInteractive: true,
+				CreateWorktree: create,
+			}, os.Args)
+		},
+	}
+
+	cmd.Flags().BoolVar(&create, "create", false, "Create a new worktree when none is free")
+	cmd.Flags().BoolVar(&shell, "shell", false, "Open a shell instead of the agent")
+	cmd.Flags().StringVar(&prompt, "prompt", "", "Initial task to send to the agent on launch")
+	cmd.Flags().BoolVar(&fromPick, "from-pick", false, "Take the branch from a line printed by wtx pick (argument or stdin)")
+	cmd.ValidArgsFunction = openBranchCompletion
+	return cmd
+}
+
+func openBranchCompletion(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeBranchSuggestions(toComplete), cobra.ShellCompDirectiveNoFileComp
+}
@@ -10,6 +10,8 @@ const (
 	openNewFetchKey      = "open_new_fetch"
 )
 
+// newOpenNewBranchForm leaves out the fetch question when fetch is nil, as
+// for a repo without a remote.
 func newOpenNewBranchForm(branch *string, baseRef *string, fetch *bool) *huh.Form {
 	branchInput := huh.NewInput().
 		Key(openNewBranchNameKey).
@@ -26,16 +28,19 @@ func newOpenNewBranchForm(branch *string, baseRef *string, fetch *bool) *huh.For
 		Prompt("> ").
 		Value(baseRef)
 
-	fetchConfirm := huh.NewConfirm().
-		Key(openNewFetchKey).
-		Title("Fetch before checkout?").
-		Affirmative("Yes").
-		Negative("No").
-		Inline(true).
-		Value(fetch)
+	fields := []huh.Field{branchInput, baseInput}
+	if fetch != nil {
+		fields = append(fields, huh.NewConfirm().
+			Key(openNewFetchKey).
+			Title("Fetch before checkout?").
+			Affirmative("Yes").
+			Negative("No").
+			Inline(true).
+			Value(fetch))
+	}
 
 	return huh.NewForm(
-		huh.NewGroup(branchInput, baseInput, fetchConfirm),
+		huh.NewGroup(fields...),
 	).
 		WithTheme(wtxHuhTheme()).
 		WithShowHelp(false)
@@ -7,6 +7,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/aixolotls/wtx/internal/core"
+	uiview "github.com/aixolotls/wtx/ui"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/muesli/termenv"
 )
@@ -19,28 +21,20 @@ type openBranchOption struct {
 	PRLoading bool
 }
 
-type openSlotState struct {
-	Path      string
-	Branch    string
-	Locked    bool
-	Dirty     bool
-	HasPR     bool
-	PRNumber  int
-	PRLoading bool
-}
-
 type openScreenLoadedMsg struct {
-	status         WorktreeStatus
+	status         core.WorktreeStatus
 	branches       []openBranchOption
 	lockedBranches []openBranchOption
-	slots          []openSlotState
+	slots          []core.OpenSlotState
 	prBranches     []string
 	fetchID        string
 	err            error
 }
 
 type openScreenPRDataMsg struct {
-	byBranch map[string]PRData
+	byBranch map[string]core.PRData
+	// branches lists what the fetch covered; nil means every row.
+	branches []string
 	fetchID  string
 	err      error
 }
@@ -57,7 +51,11 @@ type openAllBranchesLoadedMsg struct {
 
 const openSearchMatchLimit = 200
 
-func loadOpenScreenCmd(orchestrator *WorktreeOrchestrator, mgr *WorktreeManager) tea.Cmd {
+// openPRPrefetchRows is how many rows beyond the visible window get PR data
+// ahead of scrolling.
+const openPRPrefetchRows = 5
+
+func loadOpenScreenCmd(orchestrator *core.WorktreeOrchestrator, mgr *core.WorktreeManager) tea.Cmd {
 	return func() tea.Msg {
 		if orchestrator == nil || mgr == nil {
 			return openScreenLoadedMsg{err: fmt.Errorf("open screen unavailable")}
@@ -71,16 +69,21 @@ func loadOpenScreenCmd(orchestrator *WorktreeOrchestrator, mgr *WorktreeManager)
 			return openScreenLoadedMsg{status: status, err: err}
 		}
 
-		slots := make([]openSlotState, len(status.Worktrees))
+		slots := make([]core.OpenSlotState, len(status.Worktrees))
 		for i, wt := range status.Worktrees {
-			slots[i] = openSlotState{
+			slots[i] = core.OpenSlotState{
 				Path:      wt.Path,
 				Branch:    wt.Branch,
 				Locked:    !wt.Available,
-				PRLoading: true,
+				Idle:      wt.Idle,
+				PRLoading: status.HasRemote,
 			}
 		}
-		openBranches, lockedList, prBranches := buildOpenBranchLists(branches, slots, true)
+		openBranches, lockedList, prBranches := buildOpenBranchLists(branches, slots, status.HasRemote)
+		if !status.HasRemote {
+			// Nothing to look up on GitHub for a purely local repo.
+			prBranches = nil
+		}
 
 		return openScreenLoadedMsg{
 			status:         status,
@@ -93,7 +96,7 @@ func loadOpenScreenCmd(orchestrator *WorktreeOrchestrator, mgr *WorktreeManager)
 	}
 }
 
-func loadAllOpenBranchesCmd(mgr *WorktreeManager, slots []openSlotState) tea.Cmd {
+func loadAllOpenBranchesCmd(mgr *core.WorktreeManager, slots []core.OpenSlotState) tea.Cmd {
 	return func() tea.Msg {
 		if mgr == nil {
 			return openAllBranchesLoadedMsg{err: fmt.Errorf("open screen unavailable")}
@@ -110,7 +113,7 @@ func loadAllOpenBranchesCmd(mgr *WorktreeManager, slots []openSlotState) tea.Cmd
 	}
 }
 
-func buildOpenBranchLists(branches []string, slots []openSlotState, prLoading bool) ([]openBranchOption, []openBranchOption, []string) {
+func buildOpenBranchLists(branches []string, slots []core.OpenSlotState, prLoading bool) ([]openBranchOption, []openBranchOption, []string) {
 	lockedOnlyBranches := make(map[string]bool, len(slots))
 	openSlotBranches := make(map[string]bool, len(slots))
 	seenPR := make(map[string]bool, len(branches)+len(slots))
@@ -216,23 +219,99 @@ func fetchDirtyStatusCmd(paths []string) tea.Cmd {
 	}
 }
 
-func fetchOpenPRDataCmd(orchestrator *WorktreeOrchestrator, repoRoot string, branches []string, fetchID string) tea.Cmd {
+func fetchOpenPRDataCmd(orchestrator *core.WorktreeOrchestrator, repoRoot string, branches []string, fetchID string) tea.Cmd {
 	return func() tea.Msg {
 		if orchestrator == nil {
-			return openScreenPRDataMsg{byBranch: map[string]PRData{}, fetchID: fetchID}
+			return openScreenPRDataMsg{byBranch: map[string]core.PRData{}, branches: branches, fetchID: fetchID}
 		}
 		byBranch, err := orchestrator.PRDataForBranchesWithError(repoRoot, branches, false)
 		if byBranch == nil {
-			byBranch = map[string]PRData{}
+			byBranch = map[string]core.PRData{}
 		}
-		return openScreenPRDataMsg{byBranch: byBranch, fetchID: fetchID, err: err}
+		return openScreenPRDataMsg{byBranch: byBranch, branches: branches, fetchID: fetchID, err: err}
 	}
 }
 
-func applyPRDataToOpenState(branches *[]openBranchOption, lockedBranches *[]openBranchOption, slots *[]openSlotState, byBranch map[string]PRData) {
+// openPRBranchesToFetch returns the branches on screen whose PR data has not
+// been requested yet: worktree slots and in-use branches, which are always
+// shown, plus the visible branch rows and a prefetch window around them.
+func openPRBranchesToFetch(m model) []string {
+	wanted := make(map[string]bool, len(m.openPRBranches))
+	for _, name := range m.openPRBranches {
+		wanted[name] = true
+	}
+	out := []string{}
+	add := func(name string) {
+		name = strings.TrimSpace(name)
+		if name == "" || name == "detached" || !wanted[name] || m.openPRRequested[name] {
+			return
+		}
+		m.openPRRequested[name] = true
+		out = append(out, name)
+	}
+	for _, slot := range m.openSlots {
+		add(slot.Branch)
+	}
+	if m.openSearchAllActive {
+		return out
+	}
+	for _, branch := range m.openLockedBranches {
+		add(branch.Name)
+	}
+	filtered := openFilteredIndices(m.openTypeahead, m.openBranches)
+	visible, _ := openVisibleFilteredIndices(filtered, m.openSelected, openBranchRenderLimit(m.height))
+	if len(visible) == 0 {
+		return out
+	}
+	start, end := 0, len(filtered)
+	for i, idx := range filtered {
+		if idx == visible[0] {
+			start = i
+		}
+		if idx == visible[len(visible)-1] {
+			end = i + 1
+		}
+	}
+	start = max(0, start-openPRPrefetchRows)
+	end = min(len(filtered), end+openPRPrefetchRows)
+	for _, idx := range filtered[start:end] {
+		add(m.openBranches[idx].Name)
+	}
+	return out
+}
+
+// requestVisibleOpenPRData starts a PR fetch for rows that just came into
+// view. It returns nil when everything visible is already loaded or loading.
+func (m *model) requestVisibleOpenPRData() tea.Cmd {
+	if m.openPRRequested == nil {
+		m.openPRRequested = map[string]bool{}
+	}
+	branches := openPRBranchesToFetch(*m)
+	if len(branches) == 0 {
+		return nil
+	}
+	m.openPRInFlight++
+	m.openLoading = true
+	return fetchOpenPRDataCmd(m.orchestrator, m.status.RepoRoot, branches, m.openFetchID)
+}
+
+// applyPRDataToOpenState updates rows for the fetched branches, or every row
+// when fetched is nil.
+func applyPRDataToOpenState(branches *[]openBranchOption, lockedBranches *[]openBranchOption, slots *[]core.OpenSlotState, byBranch map[string]core.PRData, fetched []string) {
+	covered := func(string) bool { return true }
+	if fetched != nil {
+		set := make(map[string]bool, len(fetched))
+		for _, name := range fetched {
+			set[name] = true
+		}
+		covered = func(name string) bool { return set[name] }
+	}
 	if branches != nil {
 		for i := range *branches {
 			b := strings.TrimSpace((*branches)[i].Name)
+			if !covered(b) {
+				continue
+			}
 			(*branches)[i].PRLoading = false
 			(*branches)[i].HasPR = false
 			(*branches)[i].PRNumber = 0
@@ -247,6 +326,9 @@ func applyPRDataToOpenState(branches *[]openBranchOption, lockedBranches *[]open
 	if lockedBranches != nil {
 		for i := range *lockedBranches {
 			b := strings.TrimSpace((*lockedBranches)[i].Name)
+			if !covered(b) {
+				continue
+			}
 			(*lockedBranches)[i].PRLoading = false
 			(*lockedBranches)[i].HasPR = false
 			(*lockedBranches)[i].PRNumber = 0
@@ -261,6 +343,9 @@ func applyPRDataToOpenState(branches *[]openBranchOption, lockedBranches *[]open
 	if slots != nil {
 		for i := range *slots {
 			b := strings.TrimSpace((*slots)[i].Branch)
+			if !covered(b) {
+				continue
+			}
 			(*slots)[i].PRLoading = false
 			(*slots)[i].HasPR = false
 			(*slots)[i].PRNumber = 0
@@ -283,6 +368,16 @@ func clampOpenSelection(index int, branchCount int) int {
 	return index
 }
 
+// renderOpenScreenSkeleton is shown until the first load finishes so the
+// screen appears immediately instead of waiting on git and gh.
+func renderOpenScreenSkeleton(m model) string {
+	var b strings.Builder
+	b.WriteString("Choose branch:\n")
+	b.WriteString(actionSelectedStyle.Render("  <new branch>") + "\n")
+	b.WriteString(secondaryStyle.Render("  "+m.ghSpinner.View()+" loading branches...") + "\n")
+	return b.String()
+}
+
 func renderOpenScreen(m model) string {
 	var b strings.Builder
 	if m.openCreating {
@@ -301,6 +396,7 @@ func renderOpenScreen(m model) string {
 		} else {
 			b.WriteString(fmt.Sprintf("Switching to %s%s...\n", branch, elapsed))
 		}
+		b.WriteString(renderProgressLog(m.createLog, m.width))
 		return b.String()
 	}
 	if m.openShowDebug {
@@ -312,9 +408,7 @@ func renderOpenScreen(m model) string {
 			if i == m.openDebugIndex {
 				rowRenderer = selectorSelectedStyle.Render
 			}
-			state := debugWorktreeState(slot)
-			line := fmt.Sprintf("%s%-12s %-24s %s", cursor, state, slot.Branch, slot.Path)
-			b.WriteString(rowRenderer(line) + "\n")
+			b.WriteString(rowRenderer(formatOpenSlotLine(cursor, slot, m.width)) + "\n")
 		}
 		if len(m.openSlots) == 0 {
 			b.WriteString("  (no worktrees)\n")
@@ -384,9 +478,7 @@ func renderOpenScreen(m model) string {
 			if rowIndex == m.openPickIndex {
 				render = selectorSelectedStyle.Render
 			}
-			state := debugWorktreeState(slot)
-			line := fmt.Sprintf("%s%-12s %-24s %s", cursor, state, slot.Branch, slot.Path)
-			b.WriteString(render(line) + "\n")
+			b.WriteString(render(formatOpenSlotLine(cursor, slot, m.width)) + "\n")
 		}
 		if m.openLoadErr != "" {
 			b.WriteString("\n")
@@ -419,7 +511,7 @@ func renderOpenScreen(m model) string {
 	} else {
 		b.WriteString(actionNormalStyle.Render(newBranchLine) + "\n")
 	}
-	branchColWidth := openBranchColumnWidth(m.openBranches, m.openLockedBranches)
+	branchColWidth := fitOpenBranchColumnWidth(openBranchColumnWidth(m.openBranches, m.openLockedBranches), m.width)
 	filtered := openFilteredIndices(m.openTypeahead, m.openBranches)
 	visibleFiltered, trimmed := openVisibleFilteredIndices(filtered, m.openSelected, openBranchRenderLimit(m.height))
 	for _, branchIndex := range visibleFiltered {
@@ -434,7 +526,7 @@ func renderOpenScreen(m model) string {
 				pr = termenv.Hyperlink(branch.PRURL, pr)
 			}
 		}
-		line := fmt.Sprintf("%s%-*s %s", cursor, branchColWidth, branch.Name, pr)
+		line := cursor + uiview.PadOrTrim(branch.Name, branchColWidth) + " " + pr
 		if m.openSelected == branchIndex+1 {
 			b.WriteString(actionSelectedStyle.Render(line) + "\n")
 		} else {
@@ -467,7 +559,7 @@ func renderOpenScreen(m model) string {
 					pr = termenv.Hyperlink(branch.PRURL, pr)
 				}
 			}
-			line := fmt.Sprintf("  %-*s %s", branchColWidth, branch.Name, pr)
+			line := "  " + uiview.PadOrTrim(branch.Name, branchColWidth) + " " + pr
 			b.WriteString(secondaryStyle.Render(line) + "\n")
 		}
 	}
@@ -516,45 +608,58 @@ func openBranchColumnWidth(openBranches []openBranchOption, lockedBranches []ope
 	return maxLen
 }
 
-func debugWorktreeState(slot openSlotState) string {
-	if slot.Locked {
-		return "in use"
+// openPRColumnWidth leaves room for a PR number or the loading spinner.
+const openPRColumnWidth = 8
+
+// fitOpenBranchColumnWidth narrows the branch column so a row and its PR
+// number fit the terminal. Names longer than the column are truncated.
+func fitOpenBranchColumnWidth(nameWidth int, termWidth int) int {
+	if termWidth <= 0 {
+		return nameWidth
 	}
-	if slot.Dirty {
-		return "unclean"
+	limit := max(termWidth-2-1-openPRColumnWidth, 12)
+	return min(nameWidth, limit)
+}
+
+// formatOpenSlotLine renders a worktree slot as state, branch and path. On
+// narrow terminals the path moves to its own line.
+func formatOpenSlotLine(cursor string, slot core.OpenSlotState, width int) string {
+	state := debugWorktreeState(slot)
+	if width > 0 && width < uiview.StackedWidth {
+		head := fmt.Sprintf("%s%-8s %s", cursor, state, slot.Branch)
+		return uiview.PadOrTrim(head, width) + "\n" + cursor + "  " + truncatePathForWidth(slot.Path, width, len(cursor)+2)
 	}
-	return "clean"
+	prefix := fmt.Sprintf("%s%-12s %-24s ", cursor, state, slot.Branch)
+	return prefix + truncatePathForWidth(slot.Path, width, len([]rune(prefix)))
 }
 
-func findReusableOpenSlot(slots []openSlotState, branch string) (openSlotState, bool) {
-	want := strings.TrimSpace(branch)
-	for _, slot := range slots {
-		if strings.TrimSpace(slot.Branch) != want {
-			continue
-		}
-		if slot.Locked || slot.Dirty {
-			continue
-		}
-		return slot, true
+// truncatePathForWidth middle-truncates path to what is left of width after
+// used columns. An unknown width leaves the path alone.
+func truncatePathForWidth(path string, width int, used int) string {
+	if width <= 0 {
+		return path
 	}
-	return openSlotState{}, false
+	return uiview.MiddleEllipsis(path, max(width-used, 16))
 }
 
-func findAnyAvailableOpenSlot(slots []openSlotState) (openSlotState, bool) {
-	for _, slot := range slots {
-		if slot.Locked || slot.Dirty {
-			continue
-		}
-		return slot, true
+func debugWorktreeState(slot core.OpenSlotState) string {
+	if slot.Locked && slot.Idle {
+		return "idle"
 	}
-	return openSlotState{}, false
+	if slot.Locked {
+		return "in use"
+	}
+	if slot.Dirty {
+		return "unclean"
+	}
+	return "clean"
 }
 
-func openPickRowCount(slots []openSlotState) int {
+func openPickRowCount(slots []core.OpenSlotState) int {
 	return len(slots) + 1
 }
 
-func clampOpenPickIndex(index int, slots []openSlotState) int {
+func clampOpenPickIndex(index int, slots []core.OpenSlotState) int {
 	maxIndex := openPickRowCount(slots) - 1
 	if maxIndex < 0 {
 		return 0
@@ -691,7 +796,7 @@ func ensureOpenSelectionVisible(current int, filtered []int) int {
 }
 
 func worktreeDirty(path string) (bool, error) {
-	gitOut, err := gitOutputInDir(path, "git", "status", "--porcelain")
+	gitOut, err := core.GitOutputInDir(path, "git", "status", "--porcelain")
 	if err != nil {
 		msg := strings.TrimSpace(gitOut)
 		if msg == "" {
@@ -702,11 +807,11 @@ func worktreeDirty(path string) (bool, error) {
 	return strings.TrimSpace(gitOut) != "", nil
 }
 
-func worktreeLockedByAny(orchestrator *WorktreeOrchestrator, repoRoot string, worktreePath string) (bool, error) {
-	if orchestrator == nil || orchestrator.lockMgr == nil {
+func worktreeLockedByAny(orchestrator *core.WorktreeOrchestrator, repoRoot string, worktreePath string) (bool, error) {
+	if orchestrator == nil || orchestrator.LockMgr == nil {
 		return false, nil
 	}
-	available, err := orchestrator.lockMgr.IsAvailable(repoRoot, worktreePath)
+	available, err := orchestrator.LockMgr.IsAvailable(repoRoot, worktreePath)
 	if err != nil {
 		return false, err
 	}
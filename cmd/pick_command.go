@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aixolotls/wtx/internal/core"
+	"github.com/spf13/cobra"
+)
+
+func newPickCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "pick",
+		Short: "Print worktrees as selectable lines for fzf",
+		Long: "Prints one tab-separated line per worktree: branch, state, path.\n\n" +
+			"Pass the chosen line to `wtx open --from-pick`.",
+		Example: strings.Join([]string{
+			"  wtx open --from-pick \"$(wtx pick | fzf --delimiter '\\t')\"",
+		}, "\n"),
+		Args: cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runPick(os.Stdout)
+		},
+	}
+}
+
+func runPick(w io.Writer) error {
+	lockMgr := core.NewLockManager()
+	status := core.NewWorktreeOrchestrator(core.NewWorktreeManager("", lockMgr), lockMgr, nil).Status()
+	if status.Err != nil {
+		return status.Err
+	}
+	if !status.InRepo {
+		return core.ErrNotInGitRepository
+	}
+	for _, e := range buildWorktreeListOutput(status, lockMgr).Worktrees {
+		if e.Orphaned {
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", e.Branch, worktreeListState(e), e.Path)
+	}
+	return nil
+}
+
+// parsePickLine returns the branch from a line printed by `wtx pick`.
+func parsePickLine(line string) (string, error) {
+	line = strings.TrimRight(line, "\r\n")
+	if strings.TrimSpace(line) == "" {
+		return "", errors.New("no worktree picked")
+	}
+	branch := strings.TrimSpace(strings.SplitN(line, "\t", 2)[0])
+	if branch == "" || branch == "detached" {
+		return "", fmt.Errorf("picked worktree has no branch: %q", line)
+	}
+	return branch, nil
+}
+
+// readPickLine reads the picked line from stdin, for `wtx pick | fzf | wtx
+// open --from-pick`.
+func readPickLine(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	return line, nil
+}
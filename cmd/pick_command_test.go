@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPickLinesRoundTripToBranch(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo := initGitReadTestRepo(t)
+	t.Chdir(repo)
+
+	var out bytes.Buffer
+	if err := runPick(&out); err != nil {
+		t.Fatalf("runPick: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 1 || !strings.HasPrefix(lines[0], "main\tfree\t") {
+		t.Fatalf("unexpected pick output %q", out.String())
+	}
+	branch, err := parsePickLine(lines[0] + "\n")
+	if err != nil || branch != "main" {
+		t.Fatalf("expected main, got %q err=%v", branch, err)
+	}
+	if _, err := parsePickLine("detached\tfree\t/tmp/wt"); err == nil {
+		t.Fatalf("expected detached pick to fail")
+	}
+	if _, err := parsePickLine(""); err == nil {
+		t.Fatalf("expected empty pick to fail")
+	}
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aixolotls/wtx/internal/core"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type popupHeaderMsg struct {
+	header string
+}
+
+// popupHeaderCmd loads the popup's status line without holding up the first
+// frame; the PR part comes from the same cache as the tmux status line.
+func popupHeaderCmd(basePath string) tea.Cmd {
+	return func() tea.Msg {
+		return popupHeaderMsg{header: buildPopupHeader(basePath)}
+	}
+}
+
+// buildPopupHeader summarizes the worktree as branch, changed files,
+// ahead/behind its upstream and the PR, or "" outside a worktree.
+func buildPopupHeader(basePath string) string {
+	out, err := core.GitOutputInDir(basePath, "git", "status", "--porcelain=v2", "--branch")
+	if err != nil {
+		return ""
+	}
+	branch, changed, ahead, behind, tracking := parsePorcelainV2Status(out)
+	parts := []string{branch}
+	if changed == 0 {
+		parts = append(parts, "clean")
+	} else {
+		parts = append(parts, fmt.Sprintf("%d changed", changed))
+	}
+	switch {
+	case !tracking:
+		parts = append(parts, "no upstream")
+	case ahead == 0 && behind == 0:
+		parts = append(parts, "up to date")
+	default:
+		parts = append(parts, fmt.Sprintf("↑%d ↓%d", ahead, behind))
+	}
+	if branch != "(detached)" {
+		summary := ghSummaryForBranchCached(basePath, branch)
+		if prSummaryHasNumber(summary) {
+			// PR and CI fit the popup; the full summary is in the status bar.
+			segments := strings.SplitN(summary, " | ", 3)
+			parts = append(parts, strings.Join(segments[:min(2, len(segments))], " | "))
+		} else if summary == localOnlyGHSummary {
+			parts = append(parts, summary)
+		} else {
+			parts = append(parts, "no PR")
+		}
+	}
+	return strings.Join(parts, " · ")
+}
+
+// parsePorcelainV2Status reads the branch headers and counts the entries of
+// git status --porcelain=v2 --branch.
+func parsePorcelainV2Status(out string) (branch string, changed int, ahead int, behind int, tracking bool) {
+	for _, line := range strings.Split(out, "\n") {
+		switch {
+		case strings.HasPrefix(line, "# branch.head "):
+			branch = strings.TrimPrefix(line, "# branch.head ")
+		case strings.HasPrefix(line, "# branch.ab "):
+			tracking = true
+			fields := strings.Fields(strings.TrimPrefix(line, "# branch.ab "))
+			if len(fields) == 2 {
+				ahead, _ = strconv.Atoi(strings.TrimPrefix(fields[0], "+"))
+				behind, _ = strconv.Atoi(strings.TrimPrefix(fields[1], "-"))
+			}
+		case line == "" || strings.HasPrefix(line, "#"):
+		default:
+			changed++
+		}
+	}
+	return branch, changed, ahead, behind, tracking
+}
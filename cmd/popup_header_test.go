@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+func TestPopupHeaderSummarizesTheWorktree(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo := initRenameTestRepo(t)
+	branch := core.CurrentBranchInWorktree(repo)
+	if got := buildPopupHeader(repo); got != branch+" · clean · no upstream · local only" {
+		t.Fatalf("header = %q", got)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "new.txt"), []byte("x\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "README.md"), []byte("changed\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	header := buildPopupHeader(repo)
+	if !strings.HasPrefix(header, branch+" · 2 changed · ") {
+		t.Fatalf("header = %q", header)
+	}
+
+	parsed, changed, ahead, behind, tracking := parsePorcelainV2Status("# branch.oid abc\n# branch.head feature\n# branch.upstream origin/feature\n# branch.ab +2 -1\n1 .M N... 100644 100644 100644 a b f.go\n? new.txt\n")
+	if parsed != "feature" || changed != 2 || ahead != 2 || behind != 1 || !tracking {
+		t.Fatalf("parsed %q %d %d %d %v", parsed, changed, ahead, behind, tracking)
+	}
+
+	m := newTmuxActionsModel(repo, false, false, false)
+	next, _ := m.Update(popupHeaderMsg{header: header})
+	if firstLine := strings.SplitN(next.View(), "\n", 2)[0]; !strings.Contains(firstLine, "2 changed") {
+		t.Fatalf("expected the header on the first line, got %q", firstLine)
+	}
+}
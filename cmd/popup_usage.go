@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"math"
+	"path/filepath"
+	"time"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+// popupUsageHalfLife is how quickly old picks stop counting when the popup
+// orders actions by use.
+const popupUsageHalfLife = 14 * 24 * time.Hour
+
+type popupActionUse struct {
+	Count    int   `json:"count"`
+	LastUnix int64 `json:"last_unix"`
+}
+
+// popupUsage maps a tmuxAction to how often and how recently it was run.
+type popupUsage map[tmuxAction]popupActionUse
+
+func popupUsagePath() (string, error) {
+	home, err := core.WtxHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "popup_usage.json"), nil
+}
+
+// readPopupUsage returns the recorded usage; a missing or unreadable file is
+// no usage.
+func readPopupUsage() popupUsage {
+	path, err := popupUsagePath()
+	if err != nil {
+		return popupUsage{}
+	}
+	usage := popupUsage{}
+	if err := core.ReadJSONStore(path, &usage); err != nil {
+		core.DebugLog("popup usage unreadable", "err", err.Error())
+		return popupUsage{}
+	}
+	return usage
+}
+
+func recordPopupActionUse(action tmuxAction, now time.Time) {
+	if action == "" {
+		return
+	}
+	path, err := popupUsagePath()
+	if err != nil {
+		return
+	}
+	usage := popupUsage{}
+	err = core.UpdateJSONStore(path, &usage, func() (bool, error) {
+		use := usage[action]
+		use.Count++
+		use.LastUnix = now.Unix()
+		usage[action] = use
+		return true, nil
+	})
+	if err != nil {
+		core.DebugLog("popup usage not saved", "err", err.Error())
+	}
+}
+
+// score weighs the pick count by recency, halving every popupUsageHalfLife
+// since the last pick.
+func (u popupUsage) score(action tmuxAction, now time.Time) float64 {
+	use, ok := u[action]
+	if !ok || use.Count <= 0 {
+		return 0
+	}
+	age := now.Sub(time.Unix(use.LastUnix, 0))
+	if age < 0 {
+		age = 0
+	}
+	return float64(use.Count) * math.Pow(0.5, float64(age)/float64(popupUsageHalfLife))
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPopupOrdersActionsByUse(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		recordPopupActionUse(tmuxActionShellSplit, now)
+	}
+	for i := 0; i < 4; i++ {
+		recordPopupActionUse(tmuxActionIDE, now.Add(-60*24*time.Hour))
+	}
+	recordPopupActionUse(tmuxActionBack, now)
+	recordPopupActionUse(tmuxActionShellWindow, now)
+
+	m := newTmuxActionsModel("/tmp", false, false, false)
+	var aliases []string
+	for _, i := range m.filtered {
+		aliases = append(aliases, m.items[i].Alias)
+	}
+	if len(aliases) < 3 || aliases[0] != "shell" || aliases[1] != "back" || aliases[2] != "ide" {
+		t.Fatalf("expected shell, back, then the stale ide first, got %v", aliases)
+	}
+	if last := m.items[len(m.items)-1]; !last.Disabled {
+		t.Fatalf("expected disabled actions to stay last, got %+v", last)
+	}
+	if selected, _ := m.selectedItem(); selected.Action != tmuxActionShellSplit {
+		t.Fatalf("expected the most used action selected, got %+v", selected)
+	}
+}
+
+func TestRecordPopupActionUseConcurrently(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	now := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			recordPopupActionUse(tmuxActionIDE, now)
+		}()
+	}
+	wg.Wait()
+	if got := readPopupUsage()[tmuxActionIDE].Count; got != 8 {
+		t.Fatalf("expected every concurrent pick to be counted, got %d", got)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/aixolotls/wtx/internal/core"
 	"github.com/spf13/cobra"
 )
 
@@ -49,7 +50,7 @@ func newPRCommand() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			return runCheckout(branch, false, "", nil, os.Args)
+			return runCheckout(branch, checkoutOptions{}, os.Args)
 		},
 	}
 	return cmd
@@ -82,7 +83,7 @@ func resolvePRBranch(number int) (string, error) {
 	if number <= 0 {
 		return "", errors.New("pull request number required")
 	}
-	_, repoRoot, err := requireGitContext("")
+	_, repoRoot, err := core.RequireGitContext("")
 	if err != nil {
 		return "", err
 	}
@@ -93,14 +94,11 @@ func resolvePRBranch(number int) (string, error) {
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), prResolveTimeout)
 	defer cancel()
-	cmd := exec.CommandContext(
-		ctx,
-		ghBin,
-		"pr", "view", strconv.Itoa(number),
-		"--json", "headRefName,state",
-	)
-	cmd.Dir = repoRoot
-	out, err := cmd.CombinedOutput()
+	out, err := core.RunExternalCommand(ctx, core.CommandSpec{
+		Dir:  repoRoot,
+		Name: ghBin,
+		Args: []string{"pr", "view", strconv.Itoa(number), "--json", "headRefName,state"},
+	})
 	if err != nil {
 		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
 			return "", fmt.Errorf("resolving PR #%d timed out after %s", number, prResolveTimeout.Round(time.Second))
@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aixolotls/wtx/internal/core"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+)
+
+const (
+	prFormTitleKey = "pr_title"
+	prFormBodyKey  = "pr_body"
+	prFormBaseKey  = "pr_base"
+	prFormDraftKey = "pr_draft"
+)
+
+// prDraft holds the PR form's values. The model keeps a pointer to it so the
+// form's field bindings survive model copies.
+type prDraft struct {
+	path   string
+	branch string
+	title  string
+	body   string
+	base   string
+	draft  bool
+}
+
+type prPushedMsg struct {
+	draft prDraft
+	err   error
+}
+
+type prCreatedMsg struct {
+	branch string
+	url    string
+	err    error
+}
+
+func newPRForm(d *prDraft) *huh.Form {
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Key(prFormTitleKey).
+				Title("Title").
+				Inline(true).
+				Prompt("> ").
+				Value(&d.title),
+			huh.NewText().
+				Key(prFormBodyKey).
+				Title("Body").
+				Lines(5).
+				Value(&d.body),
+			huh.NewInput().
+				Key(prFormBaseKey).
+				Title("Base branch").
+				Inline(true).
+				Prompt("> ").
+				Value(&d.base),
+			huh.NewConfirm().
+				Key(prFormDraftKey).
+				Title("Draft?").
+				Affirmative("Yes").
+				Negative("No").
+				Inline(true).
+				Value(&d.draft),
+		),
+	).
+		WithTheme(wtxHuhTheme()).
+		WithShowHelp(false)
+}
+
+// startCreatePR pushes the selected worktree's branch when it has no
+// upstream, then opens the PR form.
+func (m model) startCreatePR() (tea.Model, tea.Cmd) {
+	row, ok := selectedWorktree(m.listStatus(), m.listIndex)
+	if !ok {
+		m.errMsg = "No worktree selected."
+		return m, nil
+	}
+	switch {
+	case isOrphanedPath(m.status, row.Path):
+		m.errMsg = "Cannot create a PR from an orphaned worktree."
+		return m, nil
+	case row.Broken != "":
+		m.errMsg = brokenWorktreeMessage(m.keys, row)
+		return m, nil
+	case strings.TrimSpace(row.PRURL) != "":
+		m.errMsg = "Selected worktree already has a PR."
+		return m, nil
+	case row.Branch == "" || row.Branch == "detached":
+		m.errMsg = "Selected worktree is not on a branch."
+		return m, nil
+	case !m.status.HasRemote:
+		m.errMsg = "No remote to push to."
+		return m, nil
+	}
+	m.errMsg = ""
+	m.warnMsg = "Pushing " + row.Branch + "..."
+	base := resolveNewBranchBaseRef(m.openDefaultBaseRef, m.status.BaseRef, m.status.HasRemote)
+	return m, pushForPRCmd(row.Path, row.Branch, base)
+}
+
+func pushForPRCmd(worktreePath string, branch string, baseRef string) tea.Cmd {
+	return func() tea.Msg {
+		d := prDraft{path: worktreePath, branch: branch}
+		gitPath, repoRoot, err := core.RequireGitContext(worktreePath)
+		if err != nil {
+			return prPushedMsg{err: err}
+		}
+		remote := core.PreferredRemoteName(repoRoot, gitPath)
+		if remote == "" {
+			return prPushedMsg{err: errors.New("no remote to push to")}
+		}
+		if _, err := core.GitOutputInDir(worktreePath, gitPath, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{u}"); err != nil {
+			if err := core.RunCommandInDir(worktreePath, gitPath, "push", "-u", remote, branch); err != nil {
+				return prPushedMsg{err: err}
+			}
+		}
+		d.title, _ = core.GitOutputInDir(worktreePath, gitPath, "log", "-1", "--format=%s")
+		d.base = strings.TrimPrefix(baseRef, remote+"/")
+		return prPushedMsg{draft: d}
+	}
+}
+
+func (m model) applyPRPushed(msg prPushedMsg) (tea.Model, tea.Cmd) {
+	m.warnMsg = ""
+	if msg.err != nil {
+		return m.setError(msg.err), nil
+	}
+	d := msg.draft
+	m.prDraft = &d
+	m.prForm = newPRForm(m.prDraft)
+	return m, m.prForm.Init()
+}
+
+func (m model) updatePRForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.Type == tea.KeyEsc {
+		m.prForm = nil
+		m.prDraft = nil
+		m.errMsg = ""
+		return m, nil
+	}
+	form, cmd := m.prForm.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.prForm = f
+	}
+	switch m.prForm.State {
+	case huh.StateAborted:
+		m.prForm = nil
+		m.prDraft = nil
+		return m, nil
+	case huh.StateCompleted:
+		d := *m.prDraft
+		if strings.TrimSpace(d.title) == "" {
+			m.errMsg = "PR title required."
+			m.prForm = newPRForm(m.prDraft)
+			return m, m.prForm.Init()
+		}
+		m.prForm = nil
+		m.prDraft = nil
+		m.errMsg = ""
+		m.warnMsg = "Creating PR for " + d.branch + "..."
+		return m, createPRCmd(d)
+	}
+	return m, cmd
+}
+
+func createPRCmd(d prDraft) tea.Cmd {
+	return func() tea.Msg {
+		url, err := createPR(d)
+		return prCreatedMsg{branch: d.branch, url: url, err: err}
+	}
+}
+
+// createPR runs gh pr create and then refreshes the branch's PR data, so the
+// table and the tmux status bar show the new PR right away.
+func createPR(d prDraft) (string, error) {
+	args := []string{"pr", "create", "--head", d.branch, "--title", strings.TrimSpace(d.title), "--body", d.body}
+	if base := strings.TrimSpace(d.base); base != "" {
+		args = append(args, "--base", base)
+	}
+	if d.draft {
+		args = append(args, "--draft")
+	}
+	out, err := core.RunExternalCommand(context.Background(), core.CommandSpec{Dir: d.path, Name: "gh", Args: args, Timeout: core.GHCommandTimeout})
+	if err != nil {
+		return "", fmt.Errorf("gh pr create: %s", commandErrorMessage(err, out))
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	url := strings.TrimSpace(lines[len(lines)-1])
+	if repoRoot, err := core.RepoRootForDir(d.path, ""); err == nil {
+		if _, err := core.LookupPRData(core.NewGHManager(), repoRoot, []string{d.branch}, true); err != nil {
+			core.DebugLog("pr refresh after create failed", "branch", d.branch, "err", err.Error())
+		}
+	}
+	go refreshTmuxStatusNow()
+	return url, nil
+}
+
+func renderPRForm(m model) string {
+	var b strings.Builder
+	b.WriteString("New pull request for " + branchStyle.Render(m.prDraft.branch) + ":\n\n")
+	b.WriteString(m.prForm.View())
+	b.WriteString("\n")
+	if m.errMsg != "" {
+		b.WriteString(errorStyle.Render(m.errMsg))
+		b.WriteString("\n")
+	}
+	b.WriteString("\nPress enter to go to the next field and create the PR from the last one, esc to cancel.\n")
+	return b.String()
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/aixolotls/wtx/internal/core"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestCreatePRKeyPushesThenOpensForm(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake gh is a shell script")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	bin := filepath.Join(home, "bin")
+	if err := os.MkdirAll(bin, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	calls := filepath.Join(home, "gh.log")
+	fake := "#!/bin/sh\necho \"$@\" >> " + core.ShellQuote(calls) + "\necho https://github.com/o/r/pull/7\n"
+	if err := os.WriteFile(filepath.Join(bin, "gh"), []byte(fake), 0o755); err != nil {
+		t.Fatalf("write fake gh: %v", err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	repo := initRenameTestRepo(t)
+	remote := filepath.Join(home, "remote.git")
+	runGitInRepo(t, repo, "init", "--bare", remote)
+	runGitInRepo(t, repo, "remote", "add", "origin", remote)
+	runGitInRepo(t, repo, "branch", "-m", "feature")
+
+	lockMgr := core.NewLockManager()
+	mgr := core.NewWorktreeManager(repo, lockMgr)
+	orch := core.NewWorktreeOrchestrator(mgr, lockMgr, nil)
+	m := newModel()
+	m.mode = modeList
+	m.ready = true
+	m.orchestrator = orch
+	m.status = orch.Status()
+	m.status.BaseRef = "origin/main"
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	pushed, ok := cmd().(prPushedMsg)
+	if !ok || pushed.err != nil {
+		t.Fatalf("expected a push, got %+v", pushed)
+	}
+	if out, err := exec.Command("git", "-C", repo, "rev-parse", "--abbrev-ref", "@{u}").Output(); err != nil || strings.TrimSpace(string(out)) != "origin/feature" {
+		t.Fatalf("expected origin/feature as upstream, got %q (%v)", out, err)
+	}
+	if pushed.draft.title != "seed" || pushed.draft.base != "main" {
+		t.Fatalf("expected the form prefilled from the last commit and base, got %+v", pushed.draft)
+	}
+	next, _ := m.Update(pushed)
+	if view := next.View(); !strings.Contains(view, "New pull request for") {
+		t.Fatalf("expected the PR form, got:\n%s", view)
+	}
+
+	d := pushed.draft
+	d.body = "Adds a feature."
+	d.draft = true
+	url, err := createPR(d)
+	if err != nil || url != "https://github.com/o/r/pull/7" {
+		t.Fatalf("createPR = %q, %v", url, err)
+	}
+	data, err := os.ReadFile(calls)
+	if err != nil {
+		t.Fatalf("read gh log: %v", err)
+	}
+	want := "pr create --head feature --title seed --body Adds a feature. --base main --draft"
+	if !strings.Contains(string(data), want) {
+		t.Fatalf("expected %q in gh calls, got:\n%s", want, data)
+	}
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+// The flow Windows takes, and runs there in CI: no tmux, the agent runs
+// through the platform shell in the current console, then the worktree is
+// removed. The scripts are valid for both /bin/sh and cmd.exe.
+func TestWorktreeCreateOpenDeleteWithoutTmux(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("TMUX", "")
+	t.Setenv("WTX_DISABLE_TMUX", "1")
+	t.Setenv("WTX_DISABLE_OSC", "1")
+	repo := initRenameTestRepo(t)
+	runGitInRepo(t, repo, "config", "wtx.bootstrap", "git rev-parse HEAD > bootstrap.txt")
+
+	lockMgr := core.NewLockManager()
+	mgr := core.NewWorktreeManager(repo, lockMgr)
+	wt, err := mgr.CreateWorktree("feature", "", nil)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if state := waitForJob(t, core.JobBootstrap, wt.Path); state.State != core.JobDone {
+		t.Fatalf("expected bootstrap to succeed, got %+v", state)
+	}
+	if _, err := os.Stat(filepath.Join(wt.Path, "bootstrap.txt")); err != nil {
+		t.Fatalf("expected bootstrap command to run: %v", err)
+	}
+
+	if _, err := NewRunner(lockMgr).runWithoutTmux(wt.Path, wt.Branch, nil, false, "git rev-parse --abbrev-ref HEAD > agent.txt"); err != nil {
+		t.Fatalf("run without tmux: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(wt.Path, "agent.txt")); err != nil || strings.TrimSpace(string(data)) != "feature" {
+		t.Fatalf("expected the agent to run in the worktree, got %q (%v)", data, err)
+	}
+	if available, err := lockMgr.IsAvailable(repo, wt.Path); err != nil || !available {
+		t.Fatalf("expected lock released after the agent exits, available=%v err=%v", available, err)
+	}
+
+	for _, name := range []string{"bootstrap.txt", "agent.txt"} {
+		if err := os.Remove(filepath.Join(wt.Path, name)); err != nil {
+			t.Fatalf("remove %s: %v", name, err)
+		}
+	}
+	if _, err := mgr.DeleteWorktree(wt.Path, false); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := os.Stat(wt.Path); !os.IsNotExist(err) {
+		t.Fatalf("expected worktree removed, stat err=%v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"io"
+	"strings"
+	"sync"
+)
+
+const maxProgressLogLines = 200
+
+// progressLog collects command output streamed while a worktree is created
+// so the TUI can show it under the spinner. Carriage returns, which git uses
+// to redraw progress counters in place, replace the current line rather than
+// starting a new one.
+type progressLog struct {
+	mu      sync.Mutex
+	lines   []string
+	current string
+	sawCR   bool
+}
+
+func newProgressLog() *progressLog {
+	return &progressLog{}
+}
+
+// writer returns l as an io.Writer, or nil when l is nil so callers can
+// skip streaming.
+func (l *progressLog) writer() io.Writer {
+	if l == nil {
+		return nil
+	}
+	return l
+}
+
+func (l *progressLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, r := range string(p) {
+		switch r {
+		case '\n':
+			l.lines = append(l.lines, l.current)
+			if len(l.lines) > maxProgressLogLines {
+				l.lines = l.lines[len(l.lines)-maxProgressLogLines:]
+			}
+			l.current = ""
+			l.sawCR = false
+		case '\r':
+			l.sawCR = true
+		default:
+			if l.sawCR {
+				l.current = ""
+				l.sawCR = false
+			}
+			l.current += string(r)
+		}
+	}
+	return len(p), nil
+}
+
+// Tail returns up to n of the most recent lines, including one still being
+// written.
+func (l *progressLog) Tail(n int) []string {
+	if l == nil || n <= 0 {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lines := append([]string(nil), l.lines...)
+	if l.current != "" {
+		lines = append(lines, l.current)
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
+
+const progressLogVisibleLines = 6
+
+// renderProgressLog shows the tail of l indented under the spinner, with each
+// line cut to width so long git output does not wrap.
+func renderProgressLog(l *progressLog, width int) string {
+	lines := l.Tail(progressLogVisibleLines)
+	if len(lines) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	for _, line := range lines {
+		line = "  " + strings.TrimRight(line, " \t")
+		if runes := []rune(line); width > 1 && len(runes) > width {
+			line = string(runes[:width-1]) + "…"
+		}
+		b.WriteString(secondaryStyle.Render(line))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aixolotls/wtx/internal/core"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultPromptFormat  = "wt:{branch} {lock} {pr} {ci}"
+	defaultPromptTimeout = 50 * time.Millisecond
+	// The prompt never calls gh, so it shows whatever the UI, tmux status, or
+	// daemon last cached, as long as it is reasonably fresh.
+	promptPRCacheMaxAge = 15 * time.Minute
+)
+
+func newPromptCommand() *cobra.Command {
+	var format string
+	var timeout time.Duration
+	cmd := &cobra.Command{
+		Use:   "prompt",
+		Short: "Print a compact worktree segment for shell prompts",
+		Long: "Prints branch, lock, PR, and CI state for the current worktree using cached data only.\n\n" +
+			"Placeholders: {branch} {lock} {pr} {ci}. Prints nothing outside a git repo or when\n" +
+			"the segment takes longer than --timeout to build.",
+		Example: strings.Join([]string{
+			"  wtx prompt",
+			"  wtx prompt --format '{branch}{lock}'",
+			"  # starship: [custom.wtx] command = \"wtx prompt\" when = true",
+		}, "\n"),
+		Args: cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runPrompt(os.Stdout, "", format, timeout)
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", defaultPromptFormat, "Segment format")
+	cmd.Flags().DurationVar(&timeout, "timeout", defaultPromptTimeout, "Print nothing if the segment is not ready in time")
+	return cmd
+}
+
+func runPrompt(w io.Writer, dir string, format string, timeout time.Duration) error {
+	done := make(chan string, 1)
+	go func() { done <- buildPromptSegment(dir, format) }()
+	if timeout <= 0 {
+		timeout = defaultPromptTimeout
+	}
+	select {
+	case segment := <-done:
+		if segment != "" {
+			fmt.Fprintln(w, segment)
+		}
+	case <-time.After(timeout):
+	}
+	return nil
+}
+
+// buildPromptSegment reads HEAD, the lock file, and the shared PR cache
+// directly; it spawns no gh and no network calls.
+func buildPromptSegment(dir string, format string) string {
+	root, err := core.RepoRootForDir(dir, "")
+	if err != nil {
+		return ""
+	}
+	gitDir, _, err := core.ResolveGitDirs(root)
+	if err != nil {
+		return ""
+	}
+	branch := core.BranchFromHEADFile(filepath.Join(gitDir, "HEAD"))
+
+	lock := ""
+	if pid, ok := core.NewLockManager().HolderPID(root, root); ok && core.PIDAlive(pid) {
+		lock = "🔒"
+	}
+	pr, ci := "", ""
+	if branch != "detached" {
+		if entry, ok := core.ReadSharedPRCache(root, branch, promptPRCacheMaxAge); ok && entry.Found && entry.Data.Number > 0 {
+			pr = fmt.Sprintf("PR#%d", entry.Data.Number)
+			ci = promptCIGlyph(entry.Data)
+		}
+	}
+	if strings.TrimSpace(format) == "" {
+		format = defaultPromptFormat
+	}
+	out := strings.NewReplacer(
+		"{branch}", branch,
+		"{lock}", lock,
+		"{pr}", pr,
+		"{ci}", ci,
+	).Replace(format)
+	return strings.Join(strings.Fields(out), " ")
+}
+
+func promptCIGlyph(pr core.PRData) string {
+	if pr.CITotal == 0 {
+		return ""
+	}
+	switch pr.CIState {
+	case core.PRCISuccess:
+		return "✓"
+	case core.PRCIFail:
+		return "✗"
+	case core.PRCIInProgress:
+		return "…"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+func TestPromptSegmentUsesCachedPRAndLock(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("TMUX", "")
+	t.Setenv("TMUX_PANE", "")
+	repo := initGitReadTestRepo(t)
+
+	if got := buildPromptSegment(repo, ""); got != "wt:main" {
+		t.Fatalf("expected bare branch segment, got %q", got)
+	}
+
+	root, err := core.RepoRootForDir(repo, "")
+	if err != nil {
+		t.Fatalf("repoRootForDir: %v", err)
+	}
+	if err := core.WriteSharedPRCache(root, "main", core.CachedBranchPRData{FetchedAt: time.Now(), Found: true, Data: core.PRData{Number: 42, CIState: core.PRCISuccess, CICompleted: 3, CITotal: 3}}); err != nil {
+		t.Fatalf("writeSharedPRCache: %v", err)
+	}
+	if _, err := core.NewLockManager().AcquireForOwner(root, root, "someone-else", os.Getpid()); err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	if got := buildPromptSegment(repo, ""); got != "wt:main 🔒 PR#42 ✓" {
+		t.Fatalf("unexpected segment %q", got)
+	}
+	if got := buildPromptSegment(repo, "[{branch}|{pr}]"); got != "[main|PR#42]" {
+		t.Fatalf("unexpected formatted segment %q", got)
+	}
+
+	var out bytes.Buffer
+	if err := runPrompt(&out, t.TempDir(), "", time.Second); err != nil || out.Len() != 0 {
+		t.Fatalf("expected no output outside a repo, got %q err=%v", out.String(), err)
+	}
+}
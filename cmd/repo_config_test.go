@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+func initTestRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "init", dir).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	return dir
+}
+
+// writeTrustedRepoConfig writes the repo's .wtx.json and approves it, as
+// `wtx config trust` would.
+func writeTrustedRepoConfig(t *testing.T, repo string, content string) {
+	t.Helper()
+	path := filepath.Join(repo, core.RepoConfigFileName)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write repo config: %v", err)
+	}
+	if err := core.TrustRepoConfigData(path, []byte(content)); err != nil {
+		t.Fatalf("trust repo config: %v", err)
+	}
+}
+
+func TestResolveIDECommand_RepoOverride(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(core.ConfigDirOverrideEnv, "")
+	if err := core.SaveConfig(core.Config{AgentCommand: "claude", IDECommand: "code"}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	repo := initTestRepo(t)
+
+	got, err := resolveIDECommand(repo)
+	if err != nil {
+		t.Fatalf("resolveIDECommand: %v", err)
+	}
+	if got != "code" {
+		t.Fatalf("expected global ide, got %q", got)
+	}
+
+	writeTrustedRepoConfig(t, repo, `{"ide_command": "xed"}`)
+	got, err = resolveIDECommand(filepath.Join(repo, core.RepoConfigFileName))
+	if err != nil {
+		t.Fatalf("resolveIDECommand: %v", err)
+	}
+	if got != "xed" {
+		t.Fatalf("expected .wtx.json ide, got %q", got)
+	}
+
+	if out, err := exec.Command("git", "-C", repo, "config", "wtx.ide", "cursor").CombinedOutput(); err != nil {
+		t.Fatalf("git config: %v\n%s", err, out)
+	}
+	got, err = resolveIDECommand(repo)
+	if err != nil {
+		t.Fatalf("resolveIDECommand: %v", err)
+	}
+	if got != "cursor" {
+		t.Fatalf("expected git config ide, got %q", got)
+	}
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aixolotls/wtx/internal/core"
+	"github.com/spf13/cobra"
+)
+
+// repoConfigTrustIssue shows an untrusted .wtx.json with the integrations,
+// since the features it configures are silently missing until it is trusted.
+func repoConfigTrustIssue(dir string) (integrationIssue, bool) {
+	path, keys := core.UntrustedRepoConfigKeys(dir)
+	if len(keys) == 0 {
+		return integrationIssue{}, false
+	}
+	return integrationIssue{
+		Name:   "repo config",
+		Detail: fmt.Sprintf("%s sets %s, which wtx ignores until you trust the file.", path, strings.Join(keys, ", ")),
+		Fix:    "Review " + core.RepoConfigFileName + ", then run wtx config trust in the repo.",
+	}, true
+}
+
+func newConfigTrustCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "trust",
+		Short: "Allow the repo's " + core.RepoConfigFileName + " to run commands",
+		Long: "Approves the current content of the repo's " + core.RepoConfigFileName + ", so its agent, bootstrap, test,\n" +
+			"IDE and git UI commands, hooks, custom actions, sync_files and worktree_dir apply.\n" +
+			"Any change to the file needs a new approval.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runConfigTrust(cmd.OutOrStdout())
+		},
+	}
+}
+
+func runConfigTrust(w io.Writer) error {
+	path, err := configFilePath(true)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%s does not exist", path)
+		}
+		return err
+	}
+	cfg, err := core.ParseRepoConfig(data)
+	if err != nil {
+		return err
+	}
+	if err := core.TrustRepoConfigData(path, data); err != nil {
+		return err
+	}
+	keys := cfg.CommandKeys()
+	if len(keys) == 0 {
+		fmt.Fprintf(w, "Trusted %s (it sets no commands).\n", path)
+		return nil
+	}
+	fmt.Fprintf(w, "Trusted %s: %s now apply.\n", path, strings.Join(keys, ", "))
+	return nil
+}
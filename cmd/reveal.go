@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"errors"
+	"os/exec"
+	"runtime"
+)
+
+// fileManagerOpener is the program that shows a directory in the platform's
+// file manager, or "" when none is installed.
+func fileManagerOpener() string {
+	name := "xdg-open"
+	switch runtime.GOOS {
+	case "darwin":
+		name = "open"
+	case "windows":
+		name = "explorer"
+	}
+	if _, err := exec.LookPath(name); err != nil {
+		return ""
+	}
+	return name
+}
+
+func fileManagerLabel() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "Finder"
+	case "windows":
+		return "Explorer"
+	default:
+		return "Files"
+	}
+}
+
+// revealInFileManager opens path in the file manager without waiting for it.
+// The opener is reaped in the background so the long-running TUI and popup
+// do not collect zombies.
+func revealInFileManager(path string) error {
+	opener := fileManagerOpener()
+	if opener == "" {
+		return errors.New("no file manager opener found (open, xdg-open or explorer)")
+	}
+	cmd := exec.Command(opener, path)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
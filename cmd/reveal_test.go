@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestRevealActionFollowsOpenerDetection(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("uses a fake xdg-open")
+	}
+	bin := t.TempDir()
+	t.Setenv("PATH", bin)
+	revealItem := func() tmuxActionItem {
+		t.Helper()
+		for _, item := range newTmuxActionsModel(t.TempDir(), false, false, false).items {
+			if item.Action == tmuxActionReveal {
+				return item
+			}
+		}
+		t.Fatal("no reveal item")
+		return tmuxActionItem{}
+	}
+
+	if item := revealItem(); !item.Disabled || item.Label != "Open in Files" {
+		t.Fatalf("expected a disabled Files action without xdg-open, got %+v", item)
+	}
+	if err := os.WriteFile(filepath.Join(bin, "xdg-open"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatalf("write fake xdg-open: %v", err)
+	}
+	if item := revealItem(); item.Disabled {
+		t.Fatalf("expected xdg-open to enable the action, got %+v", item)
+	}
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aixolotls/wtx/internal/core"
+	"github.com/spf13/cobra"
+)
+
+func newRmCommand() *cobra.Command {
+	var force bool
+	var withBranch bool
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:     "rm <branch|path>",
+		Aliases: []string{"remove"},
+		Short:   "Delete a managed worktree",
+		Long: "Removes the worktree checked out on <branch>, or the worktree at <path>.\n\n" +
+			"Orphaned worktrees (directory already gone) are always force-removed.",
+		Example: strings.Join([]string{
+			"  wtx rm feature/auth-flow",
+			"  wtx rm ../repo.wt/wt.2 --force --yes",
+			"  wtx rm feature/done --with-branch",
+		}, "\n"),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				return nil
+			}
+			if len(args) == 0 {
+				return usageError(cmd, "missing branch or path argument")
+			}
+			return usageError(cmd, "too many arguments; provide exactly one branch or path")
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runRm(args[0], force, withBranch, yes)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Remove even with uncommitted changes (and delete unmerged branches)")
+	cmd.Flags().BoolVar(&withBranch, "with-branch", false, "Also delete the local branch")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Do not ask for confirmation")
+	cmd.ValidArgsFunction = openBranchCompletion
+	return cmd
+}
+
+func runRm(target string, force bool, withBranch bool, yes bool) error {
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return errors.New("branch or path required")
+	}
+	lockMgr := core.NewLockManager()
+	mgr := core.NewWorktreeManager("", lockMgr)
+	orchestrator := core.NewWorktreeOrchestrator(mgr, lockMgr, nil)
+	status := orchestrator.Status()
+	if status.Err != nil {
+		return status.Err
+	}
+	if !status.InRepo {
+		return core.ErrNotInGitRepository
+	}
+
+	wt, orphaned, ok := resolveRmTarget(status, target)
+	if !ok {
+		return fmt.Errorf("no worktree found for %q", target)
+	}
+	if err := mgr.CanDeleteWorktree(wt.Path); err != nil {
+		return err
+	}
+	if !yes {
+		if !isInteractiveTerminalFn(os.Stdin) {
+			return errors.New("refusing to delete without confirmation; pass --yes")
+		}
+		question := fmt.Sprintf("Delete worktree %s (%s)?", wt.Path, wt.Branch)
+		if withBranch {
+			question = fmt.Sprintf("Delete worktree %s and branch %s?", wt.Path, wt.Branch)
+		}
+		confirmed, err := promptYesNo(question)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("Aborted.")
+			return nil
+		}
+	}
+
+	warnings, err := mgr.DeleteWorktree(wt.Path, force || orphaned)
+	if err != nil {
+		return err
+	}
+	for _, w := range warnings {
+		fmt.Fprintln(os.Stderr, "wtx warning:", w)
+	}
+	fmt.Printf("Removed worktree %s\n", wt.Path)
+	if withBranch {
+		if err := mgr.DeleteBranch(wt.Branch, force); err != nil {
+			return fmt.Errorf("delete branch %s: %w", wt.Branch, err)
+		}
+		fmt.Printf("Deleted branch %s\n", wt.Branch)
+	}
+	return nil
+}
+
+// resolveRmTarget matches target against worktree branches first, then paths.
+func resolveRmTarget(status core.WorktreeStatus, target string) (core.WorktreeInfo, bool, bool) {
+	orphaned := make(map[string]bool, len(status.Orphaned))
+	for _, wt := range status.Orphaned {
+		orphaned[wt.Path] = true
+	}
+	for _, wt := range status.Worktrees {
+		if strings.TrimSpace(wt.Branch) == target {
+			return wt, orphaned[wt.Path], true
+		}
+	}
+	abs, err := filepath.Abs(target)
+	if err != nil {
+		return core.WorktreeInfo{}, false, false
+	}
+	for _, wt := range status.Worktrees {
+		if core.SameSessionPath(wt.Path, abs) {
+			return wt, orphaned[wt.Path], true
+		}
+		if resolved, err := core.RealPathOrAbs(abs); err == nil && core.SameSessionPath(wt.Path, resolved) {
+			return wt, orphaned[wt.Path], true
+		}
+	}
+	return core.WorktreeInfo{}, false, false
+}
@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/aixolotls/wtx/internal/core"
+	"github.com/spf13/cobra"
+)
+
+type batchRunResult struct {
+	Branch string
+	Path   string
+	Status string
+}
+
+func newRunCommand() *cobra.Command {
+	var all bool
+	var filter string
+	var prompt string
+	cmd := &cobra.Command{
+		Use:   "run [--all | --filter <glob>] [-- <command>...]",
+		Short: "Launch the agent or a command in every free worktree",
+		Example: "  wtx run --all\n" +
+			"  wtx run --filter 'feature/*' --prompt \"rebase on main\"\n" +
+			"  wtx run --all -- make test",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !all && strings.TrimSpace(filter) == "" {
+				return errors.New("pass --all or --filter <glob>")
+			}
+			if cmd.ArgsLenAtDash() > 0 {
+				return errors.New("commands must follow --")
+			}
+			return runBatch(filter, prompt, args)
+		},
+	}
+	cmd.Flags().BoolVar(&all, "all", false, "Run in every free worktree")
+	cmd.Flags().StringVar(&filter, "filter", "", "Only run in free worktrees whose branch matches this glob")
+	cmd.Flags().StringVar(&prompt, "prompt", "", "Initial prompt passed to the agent")
+	return cmd
+}
+
+func runBatch(filter string, prompt string, args []string) error {
+	if core.TmuxIntegrationDisabled() {
+		return errors.New("wtx run requires tmux (WTX_DISABLE_TMUX is set)")
+	}
+	if _, err := exec.LookPath("tmux"); err != nil {
+		return errors.New("wtx run requires tmux")
+	}
+	if err := ensureConfigReady(); err != nil {
+		return err
+	}
+
+	lockMgr := core.NewLockManager()
+	orchestrator := core.NewWorktreeOrchestrator(core.NewWorktreeManager("", lockMgr), lockMgr, core.NewGHManager())
+	status := orchestrator.Status()
+	if status.Err != nil {
+		return status.Err
+	}
+	targets, err := batchTargets(status.Worktrees, filter)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		fmt.Println("No free worktrees to run in.")
+		return nil
+	}
+
+	launch, err := batchCommand(status.RepoRoot, prompt, args)
+	if err != nil {
+		return err
+	}
+
+	runner := NewRunner(lockMgr)
+	inTmux := strings.TrimSpace(os.Getenv("TMUX")) != ""
+	tmuxSession := ""
+	if inTmux {
+		tmuxSession, _ = core.CurrentSessionID()
+	}
+	results := make([]batchRunResult, 0, len(targets))
+	started := 0
+	for _, wt := range targets {
+		result := batchRunResult{Branch: wt.Branch, Path: wt.Path}
+		if lock, err := lockMgr.Acquire(status.RepoRoot, wt.Path); err != nil {
+			result.Status = "skipped: " + err.Error()
+		} else if tmuxSession, err = runner.openLockedWorktreeWindow(tmuxSession, wt.Path, wt.Branch, lock, commandToRunInTmux(wt.Path, false, launch)); err != nil {
+			result.Status = "failed: " + err.Error()
+		} else {
+			result.Status = "started"
+			started++
+		}
+		fmt.Printf("%-40s %s\n", batchLabel(result), result.Status)
+		results = append(results, result)
+	}
+	fmt.Printf("Started %d of %d worktree(s).\n", started, len(results))
+	if started == 0 {
+		return errors.New("wtx run: nothing started")
+	}
+	if inTmux {
+		return nil
+	}
+	return enterTmuxSession(tmuxSession, false)
+}
+
+// openLockedWorktreeWindow opens runCmd in a new window of sessionID and
+// hands lock, taken before the launch so a worktree someone else grabbed is
+// skipped rather than shared, to the new window. The lock is released and the
+// pane killed when that fails. It returns the tmux session to use next.
+func (r *Runner) openLockedWorktreeWindow(sessionID string, worktreePath string, branch string, lock *core.WorktreeLock, runCmd string) (string, error) {
+	paneID, nextSession, err := openWorktreeWindow(sessionID, worktreePath, windowNameForBranch(branch), runCmd)
+	if err != nil {
+		lock.Release()
+		return sessionID, err
+	}
+	if err := r.lockWorktreeForWindowPane(worktreePath, paneID, lock); err != nil {
+		_ = core.TmuxRun("kill-pane", "-t", paneID)
+		lock.Release()
+		return sessionID, err
+	}
+	return nextSession, nil
+}
+
+// openLockedSplitPane is openLockedWorktreeWindow for the split layout: the
+// agent opens below the current pane and the lock follows the pane's process.
+func (r *Runner) openLockedSplitPane(worktreePath string, lock *core.WorktreeLock, runCmd string) error {
+	paneID, err := splitCommandPane(worktreePath, runCmd)
+	if err != nil {
+		lock.Release()
+		return err
+	}
+	if err := r.lockWorktreeForPane(worktreePath, paneID, lock); err != nil {
+		_ = core.TmuxRun("kill-pane", "-t", paneID)
+		lock.Release()
+		return err
+	}
+	return nil
+}
+
+// batchTargets returns free worktrees whose branch matches filter. An empty
+// filter matches every free worktree.
+func batchTargets(worktrees []core.WorktreeInfo, filter string) ([]core.WorktreeInfo, error) {
+	filter = strings.TrimSpace(filter)
+	if filter != "" {
+		if _, err := path.Match(filter, ""); err != nil {
+			return nil, fmt.Errorf("invalid --filter %q: %w", filter, err)
+		}
+	}
+	out := make([]core.WorktreeInfo, 0, len(worktrees))
+	for _, wt := range worktrees {
+		if !wt.Available {
+			continue
+		}
+		if filter != "" {
+			if ok, _ := path.Match(filter, wt.Branch); !ok {
+				continue
+			}
+		}
+		out = append(out, wt)
+	}
+	return out, nil
+}
+
+// batchCommand returns the command to run in each worktree: the explicit
+// command after --, or the configured agent with prompt.
+func batchCommand(repoRoot string, prompt string, args []string) (agentLaunch, error) {
+	if len(args) > 0 {
+		runCmd := shellCommandFromArgs(args)
+		return agentLaunch{Command: runCmd, Base: runCmd}, nil
+	}
+	cfg, err := core.LoadConfigForDir(repoRoot)
+	if err != nil {
+		return agentLaunch{}, err
+	}
+	profile, ok, err := selectAgentProfile(cfg, true)
+	if err != nil {
+		return agentLaunch{}, err
+	}
+	runCmd := profile.Command
+	if !ok || runCmd == "" {
+		_, runCmd, err = ensureAgentCommandConfigured(cfg, true)
+		if err != nil {
+			return agentLaunch{}, err
+		}
+	}
+	return agentLaunch{
+		Command: agentCommandWithPrompt(runCmd, prompt),
+		Base:    agentCommandWithPrompt(runCmd, ""),
+	}, nil
+}
+
+// shellCommandFromArgs treats a single argument as a shell snippet and quotes
+// each argument otherwise.
+func shellCommandFromArgs(args []string) string {
+	if len(args) == 1 {
+		return strings.TrimSpace(args[0])
+	}
+	quoted := make([]string, 0, len(args))
+	for _, arg := range args {
+		quoted = append(quoted, core.ShellQuote(arg))
+	}
+	return strings.Join(quoted, " ")
+}
+
+func batchLabel(result batchRunResult) string {
+	if strings.TrimSpace(result.Branch) != "" {
+		return result.Branch
+	}
+	return result.Path
+}
@@ -7,13 +7,15 @@ import (
 	"os/exec"
 	"runtime"
 	"strings"
+
+	"github.com/aixolotls/wtx/internal/core"
 )
 
 type Runner struct {
-	lockMgr *LockManager
+	lockMgr *core.LockManager
 }
 
-func NewRunner(lockMgr *LockManager) *Runner {
+func NewRunner(lockMgr *core.LockManager) *Runner {
 	return &Runner{lockMgr: lockMgr}
 }
 
@@ -22,51 +24,128 @@ type RunResult struct {
 	Warning string
 }
 
-const loginShellCommand = "exec \"${SHELL:-/bin/sh}\" -l"
+const agentPromptPlaceholder = "{{prompt}}"
+
+// agentLaunch is what an agent pane runs. Command is the full command line,
+// prompt and resume flags included; Base is the agent command alone, which is
+// what gets recorded so that a later resume or restart never repeats them.
+type agentLaunch struct {
+	Command string
+	Base    string
+}
+
+const (
+	agentLayoutSplit  = "split"
+	agentLayoutWindow = "window"
+)
+
+// AgentRunOptions tunes RunInWorktreeWithOptions. Prompt is handed to the
+// agent as its first message; NonInteractive fails instead of showing a
+// picker when a choice would be needed.
+type AgentRunOptions struct {
+	Prompt         string
+	NonInteractive bool
+}
+
+func (r *Runner) RunInWorktree(worktreePath string, branch string, lock *core.WorktreeLock) (RunResult, error) {
+	return r.RunInWorktreeWithOptions(worktreePath, branch, lock, AgentRunOptions{})
+}
 
-func (r *Runner) RunInWorktree(worktreePath string, branch string, lock *WorktreeLock) (RunResult, error) {
+// RunInWorktreeWithOptions launches the agent in the worktree.
+func (r *Runner) RunInWorktreeWithOptions(worktreePath string, branch string, lock *core.WorktreeLock, opts AgentRunOptions) (RunResult, error) {
 	worktreePath = strings.TrimSpace(worktreePath)
 	if worktreePath == "" {
 		return RunResult{}, errors.New("worktree path required")
 	}
 	branch = strings.TrimSpace(branch)
+	prompt := opts.Prompt
+	interactive := !opts.NonInteractive
 
-	if err := ensureConfigReady(); err != nil {
+	if err := ensureConfigReadyIf(interactive); err != nil {
 		return RunResult{}, err
 	}
 
-	cfg, err := LoadConfig()
+	cfg, err := core.LoadConfigForDir(worktreePath)
+	if err != nil {
+		return RunResult{}, err
+	}
+	profile, ok, err := selectAgentProfile(cfg, interactive)
+	if err != nil {
+		return RunResult{}, err
+	}
+	if ok {
+		if profile.Command == "" {
+			return r.runInWorktree(worktreePath, branch, lock, true, agentLaunch{})
+		}
+		launch, err := chooseAgentSession(cfg, worktreePath, profile.Command, prompt, interactive)
+		if err != nil {
+			return RunResult{}, err
+		}
+		return r.runInWorktree(worktreePath, branch, lock, false, launch)
+	}
+	_, runCmd, err := ensureAgentCommandConfigured(cfg, interactive)
 	if err != nil {
 		return RunResult{}, err
 	}
-	_, runCmd, err := ensureAgentCommandConfigured(cfg)
+	launch, err := chooseAgentSession(cfg, worktreePath, runCmd, prompt, interactive)
 	if err != nil {
 		return RunResult{}, err
 	}
 
-	return r.runInWorktree(worktreePath, branch, lock, false, runCmd)
+	return r.runInWorktree(worktreePath, branch, lock, false, launch)
 }
 
-func (r *Runner) RunShellInWorktree(worktreePath string, branch string, lock *WorktreeLock) (RunResult, error) {
-	return r.runInWorktree(worktreePath, branch, lock, true, "")
+// agentCommandWithPrompt substitutes {{prompt}} in the agent command, or appends
+// the prompt as a trailing argument when no placeholder is present.
+func agentCommandWithPrompt(runCmd string, prompt string) string {
+	prompt = strings.TrimSpace(prompt)
+	if prompt == "" {
+		return strings.TrimSpace(strings.ReplaceAll(runCmd, agentPromptPlaceholder, ""))
+	}
+	if strings.Contains(runCmd, agentPromptPlaceholder) {
+		return strings.ReplaceAll(runCmd, agentPromptPlaceholder, core.ShellScriptQuote(prompt))
+	}
+	return runCmd + " " + core.ShellScriptQuote(prompt)
 }
 
-func (r *Runner) runInWorktree(worktreePath string, branch string, lock *WorktreeLock, openShell bool, runCmd string) (RunResult, error) {
+func (r *Runner) RunShellInWorktree(worktreePath string, branch string, lock *core.WorktreeLock) (RunResult, error) {
+	return r.runInWorktree(worktreePath, branch, lock, true, agentLaunch{})
+}
+
+func (r *Runner) runInWorktree(worktreePath string, branch string, lock *core.WorktreeLock, openShell bool, launch agentLaunch) (RunResult, error) {
 	worktreePath = strings.TrimSpace(worktreePath)
 	if worktreePath == "" {
 		return RunResult{}, errors.New("worktree path required")
 	}
 	branch = strings.TrimSpace(branch)
 
+	endSpan := func(error) {}
+	if !openShell && strings.TrimSpace(launch.Command) != "" {
+		if _, repoRoot, err := core.RequireGitContext(worktreePath); err == nil {
+			core.RecordAction(core.AuditAgent, repoRoot, worktreePath, branch, map[string]string{"command": launch.Command})
+		}
+		endSpan = core.StartSpan("agent launch", "wtx.worktree", worktreePath, "wtx.branch", branch)
+	}
+	if err := core.NewWorktreeManager(worktreePath, r.lockMgr).SyncFiles(worktreePath, core.SyncOnOpen); err != nil {
+		fmt.Fprintln(os.Stderr, "wtx warning:", err)
+	}
+	var result RunResult
+	var err error
 	if tmuxAvailable() {
-		return r.runInTmux(worktreePath, branch, lock, openShell, runCmd)
+		result, err = r.runInTmux(worktreePath, branch, lock, openShell, launch)
+	} else {
+		result, err = r.runWithoutTmux(worktreePath, branch, lock, openShell, launch.Command)
 	}
-	return r.runWithoutTmux(worktreePath, branch, lock, openShell, runCmd)
+	endSpan(err)
+	return result, err
 }
 
-func (r *Runner) runInTmux(worktreePath string, branch string, lock *WorktreeLock, openShell bool, runCmd string) (RunResult, error) {
+func (r *Runner) runInTmux(worktreePath string, branch string, lock *core.WorktreeLock, openShell bool, launch agentLaunch) (RunResult, error) {
+	if !openShell && configuredAgentLayout() == agentLayoutWindow {
+		return r.runInTmuxWindow(worktreePath, branch, lock, launch)
+	}
 	paneID, _ := currentPaneID()
-	newPaneID, err := splitCommandPane(worktreePath, commandToRunInTmux(worktreePath, openShell, runCmd))
+	newPaneID, err := splitCommandPane(worktreePath, commandToRunInTmux(worktreePath, openShell, launch))
 	if err != nil {
 		return RunResult{}, err
 	}
@@ -74,23 +153,73 @@ func (r *Runner) runInTmux(worktreePath string, branch string, lock *WorktreeLoc
 		if err := r.lockWorktreeForPane(worktreePath, newPaneID, lock); err != nil {
 			return RunResult{}, err
 		}
+		_ = core.RememberSession(worktreePath, branch, agentLayoutSplit, launch.Base)
+		if _, err := startAgentPaneLogging(newPaneID, worktreePath, branch); err != nil {
+			fmt.Fprintln(os.Stderr, "wtx warning: agent logging unavailable:", err)
+		}
 	}
 	activateWorktreeUI(worktreePath, branch)
+	runPostOpenHook(worktreePath, branch)
 	if newPaneID != "" {
-		_ = exec.Command("tmux", "select-pane", "-t", newPaneID).Run()
+		_ = core.TmuxRun("select-pane", "-t", newPaneID)
 	}
 	if paneID != "" {
 		if openShell {
-			_ = exec.Command("tmux", "resize-pane", "-t", paneID, "-y", "1").Run()
+			_ = core.TmuxRun("resize-pane", "-t", paneID, "-y", "1")
 		} else {
-			_ = exec.Command("tmux", "kill-pane", "-t", paneID).Run()
+			_ = core.TmuxRun("kill-pane", "-t", paneID)
 		}
 	}
 	return RunResult{Started: true}, nil
 }
 
-func (r *Runner) runWithoutTmux(worktreePath string, branch string, lock *WorktreeLock, openShell bool, runCmd string) (RunResult, error) {
-	cmd := shellCommand(worktreePath, commandToRun(openShell, runCmd))
+// runInTmuxWindow runs the agent full-screen in its own tmux window. The lock
+// is owned by the new window since the wtx pane goes away.
+func (r *Runner) runInTmuxWindow(worktreePath string, branch string, lock *core.WorktreeLock, launch agentLaunch) (RunResult, error) {
+	paneID, _ := currentPaneID()
+	newPaneID, err := newCommandWindow(worktreePath, windowNameForBranch(branch), commandToRunInTmux(worktreePath, false, launch))
+	if err != nil {
+		return RunResult{}, err
+	}
+	if err := r.lockWorktreeForWindowPane(worktreePath, newPaneID, lock); err != nil {
+		return RunResult{}, err
+	}
+	_ = core.RememberSession(worktreePath, branch, agentLayoutWindow, launch.Base)
+	if _, err := startAgentPaneLogging(newPaneID, worktreePath, branch); err != nil {
+		fmt.Fprintln(os.Stderr, "wtx warning: agent logging unavailable:", err)
+	}
+	activateWorktreeUI(worktreePath, branch)
+	runPostOpenHook(worktreePath, branch)
+	if newPaneID != "" {
+		_ = core.TmuxRun("select-window", "-t", newPaneID)
+	}
+	if paneID != "" {
+		_ = core.TmuxRun("kill-pane", "-t", paneID)
+	}
+	return RunResult{Started: true}, nil
+}
+
+func configuredAgentLayout() string {
+	cfg, err := core.LoadConfig()
+	if err != nil {
+		return agentLayoutSplit
+	}
+	if strings.EqualFold(strings.TrimSpace(cfg.AgentLayout), agentLayoutWindow) {
+		return agentLayoutWindow
+	}
+	return agentLayoutSplit
+}
+
+func windowNameForBranch(branch string) string {
+	branch = strings.TrimSpace(branch)
+	if branch == "" {
+		return "wtx"
+	}
+	return branch
+}
+
+func (r *Runner) runWithoutTmux(worktreePath string, branch string, lock *core.WorktreeLock, openShell bool, runCmd string) (RunResult, error) {
+	cmd := shellCommand(worktreePath, commandToRun(openShell, core.DirenvAgentCommand(worktreePath, runCmd)))
 	if err := cmd.Start(); err != nil {
 		return RunResult{}, err
 	}
@@ -107,8 +236,16 @@ func (r *Runner) runWithoutTmux(worktreePath string, branch string, lock *Worktr
 	}
 
 	activateWorktreeUI(worktreePath, branch)
+	runPostOpenHook(worktreePath, branch)
 
 	runErr := cmd.Wait()
+	if !openShell {
+		exitCode := cmd.ProcessState.ExitCode()
+		notifyTerminal("wtx", agentFinishedMessage(branch, exitCode))
+		if _, repoRoot, err := core.RequireGitContext(worktreePath); err == nil {
+			runHookOrWarn(core.HookAgentExit, core.HookContext{RepoRoot: repoRoot, WorktreePath: worktreePath, Branch: branch, ExitCode: &exitCode})
+		}
+	}
 	result := RunResult{Started: true, Warning: "tmux unavailable; running in current terminal"}
 	if runErr != nil {
 		return result, fmt.Errorf("worktree command failed: %w", runErr)
@@ -117,7 +254,7 @@ func (r *Runner) runWithoutTmux(worktreePath string, branch string, lock *Worktr
 }
 
 func shellCommand(worktreePath string, runCmd string) *exec.Cmd {
-	cmd := exec.Command("/bin/sh", "-lc", runCmd)
+	cmd := core.ShellScriptCommand(runCmd, true)
 	cmd.Dir = worktreePath
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
@@ -127,29 +264,41 @@ func shellCommand(worktreePath string, runCmd string) *exec.Cmd {
 
 func commandToRun(openShell bool, runCmd string) string {
 	if openShell {
-		return loginShellCommand
+		return core.LoginShellCommand
 	}
 	return runCmd
 }
 
-func commandToRunInTmux(worktreePath string, openShell bool, runCmd string) string {
+// commandToRunInTmux wraps the agent so its start and exit are recorded. Only
+// the base command is recorded, never the prompt or the direnv wrapper.
+func commandToRunInTmux(worktreePath string, openShell bool, launch agentLaunch) string {
 	if openShell {
-		return loginShellCommand
+		return core.LoginShellCommand
 	}
+	agentCmd := core.DirenvAgentCommand(worktreePath, launch.Command)
 	bin := strings.TrimSpace(resolveAgentLifecycleBinary())
 	if bin == "" {
-		return runCmd + "; exec \"${SHELL:-/bin/sh}\" -l"
+		return agentCmd + "; exec \"${SHELL:-/bin/sh}\" -l"
 	}
-	startCmd := shellQuote(bin) + " tmux-agent-start --worktree " + shellQuote(worktreePath)
-	exitCmd := shellQuote(bin) + " tmux-agent-exit --worktree " + shellQuote(worktreePath)
+	startCmd := core.ShellQuote(bin) + " tmux-agent-start --worktree " + core.ShellQuote(worktreePath) + " --agent " + core.ShellQuote(launch.Base)
+	exitCmd := core.ShellQuote(bin) + " tmux-agent-exit --worktree " + core.ShellQuote(worktreePath)
 	return startCmd + "; " +
 		"finish(){ code=\"$1\"; " + exitCmd + " --code \"$code\"; exec \"${SHELL:-/bin/sh}\" -l; }; " +
 		"trap 'finish 130' INT TERM; " +
-		runCmd + "; code=$?; trap - INT TERM; finish \"$code\""
+		agentCmd + "; code=$?; trap - INT TERM; finish \"$code\""
+}
+
+func runPostOpenHook(worktreePath string, branch string) {
+	if _, repoRoot, err := core.RequireGitContext(worktreePath); err == nil {
+		runHookOrWarn(core.HookPostOpen, core.HookContext{RepoRoot: repoRoot, WorktreePath: worktreePath, Branch: branch})
+	}
 }
 
 func activateWorktreeUI(worktreePath string, branch string) {
-	recordRecentBranchForWorktree(worktreePath, branch)
+	if err := core.RecordRecentBranchForWorktree(worktreePath, branch); err != nil {
+		fmt.Fprintln(os.Stderr, "wtx warning: failed to update recent branch cache:", err)
+	}
+	setTerminalCwd(worktreePath)
 	if tmuxAvailable() {
 		// Avoid full-screen clears in tmux when swapping panes; this noticeably reduces flicker.
 		setDynamicWorktreeStatus(worktreePath)
@@ -161,7 +310,7 @@ func activateWorktreeUI(worktreePath string, branch string) {
 	setITermWTXBranchTab(branch)
 }
 
-func (r *Runner) lockWorktreeForPane(worktreePath string, paneID string, existingLock *WorktreeLock) error {
+func (r *Runner) lockWorktreeForPane(worktreePath string, paneID string, existingLock *core.WorktreeLock) error {
 	if strings.TrimSpace(paneID) == "" {
 		return nil
 	}
@@ -173,17 +322,44 @@ func (r *Runner) lockWorktreeForPane(worktreePath string, paneID string, existin
 	return err
 }
 
-func (r *Runner) lockWorktreeForPID(worktreePath string, pid int, existingLock *WorktreeLock) (*WorktreeLock, error) {
+func (r *Runner) lockWorktreeForWindowPane(worktreePath string, paneID string, existingLock *core.WorktreeLock) error {
+	if strings.TrimSpace(paneID) == "" {
+		return nil
+	}
+	pid, err := panePID(paneID)
+	if err != nil {
+		return err
+	}
+	ownerID, err := paneOwnerID(paneID)
+	if err != nil {
+		return err
+	}
+	if existingLock != nil {
+		return existingLock.Rebind(ownerID, pid)
+	}
+	_, repoRoot, err := core.RequireGitContext(worktreePath)
+	if err != nil {
+		return err
+	}
+	_, err = r.lockMgr.AcquireForOwner(repoRoot, worktreePath, ownerID, pid)
+	return err
+}
+
+func (r *Runner) lockWorktreeForPID(worktreePath string, pid int, existingLock *core.WorktreeLock) (*core.WorktreeLock, error) {
 	if existingLock != nil {
 		return existingLock, existingLock.RebindPID(pid)
 	}
-	_, repoRoot, err := requireGitContext(worktreePath)
+	_, repoRoot, err := core.RequireGitContext(worktreePath)
 	if err != nil {
 		return nil, err
 	}
 	return r.lockMgr.AcquireForPID(repoRoot, worktreePath, pid)
 }
 
+func (r *Runner) CopyToClipboard(text string) error {
+	return copyToClipboard(text)
+}
+
 func (r *Runner) OpenURL(url string) error {
 	url = strings.TrimSpace(url)
 	if url == "" {
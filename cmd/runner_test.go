@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+func TestAgentCommandWithPrompt(t *testing.T) {
+	tests := []struct {
+		name   string
+		runCmd string
+		prompt string
+		want   string
+	}{
+		{name: "no prompt", runCmd: "claude", prompt: "", want: "claude"},
+		{name: "appends prompt", runCmd: "claude", prompt: "fix flaky test", want: "claude 'fix flaky test'"},
+		{name: "quotes prompt", runCmd: "codex", prompt: "don't break it", want: `codex 'don'\''t break it'`},
+		{name: "placeholder", runCmd: "gemini -i {{prompt}} --yolo", prompt: "add docs", want: "gemini -i 'add docs' --yolo"},
+		{name: "placeholder without prompt", runCmd: "gemini -i {{prompt}}", prompt: " ", want: "gemini -i"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := agentCommandWithPrompt(tt.runCmd, tt.prompt); got != tt.want {
+				t.Fatalf("agentCommandWithPrompt(%q, %q)=%q, want %q", tt.runCmd, tt.prompt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfiguredAgentLayout(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(core.ConfigDirOverrideEnv, "")
+
+	if got := configuredAgentLayout(); got != agentLayoutSplit {
+		t.Fatalf("expected split without config, got %q", got)
+	}
+	if err := core.SaveConfig(core.Config{AgentLayout: " Window "}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	if got := configuredAgentLayout(); got != agentLayoutWindow {
+		t.Fatalf("expected window layout, got %q", got)
+	}
+}
+
+func TestBatchTargetsFiltersFreeWorktrees(t *testing.T) {
+	worktrees := []core.WorktreeInfo{
+		{Path: "/a", Branch: "feature/a", Available: true},
+		{Path: "/b", Branch: "feature/b", Available: false},
+		{Path: "/c", Branch: "fix/c", Available: true},
+	}
+	got, err := batchTargets(worktrees, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 free worktrees, got %d", len(got))
+	}
+	got, err = batchTargets(worktrees, "feature/*")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || got[0].Path != "/a" {
+		t.Fatalf("expected only /a, got %+v", got)
+	}
+	if _, err := batchTargets(worktrees, "["); err == nil {
+		t.Fatalf("expected invalid filter error")
+	}
+}
+
+func TestBatchCommandQuotesArgs(t *testing.T) {
+	got, err := batchCommand("", "", []string{"make", "test it"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Command != "'make' 'test it'" || got.Base != got.Command {
+		t.Fatalf("unexpected command %+v", got)
+	}
+}
+
+func TestBatchCommandKeepsPromptOutOfBase(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(core.ConfigDirOverrideEnv, "")
+	if err := core.SaveConfig(core.Config{AgentCommand: "claude"}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	got, err := batchCommand(t.TempDir(), "fix flaky test", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Command != "claude 'fix flaky test'" {
+		t.Fatalf("unexpected command %q", got.Command)
+	}
+	if got.Base != "claude" {
+		t.Fatalf("expected the prompt to stay out of the saved command, got %q", got.Base)
+	}
+}
@@ -0,0 +1,602 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+const (
+	serveSocketName = "serve.sock"
+	jsonRPCVersion  = "2.0"
+
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcServerError    = -32000
+
+	serveMethodList   = "list"
+	serveMethodCreate = "create"
+	serveMethodDelete = "delete"
+	serveMethodLock   = "lock"
+	serveMethodUnlock = "unlock"
+	serveMethodOpen   = "open"
+)
+
+// rpcRequest is one JSON-RPC 2.0 request. Requests are newline delimited and
+// a connection may carry any number of them; requests without an id are
+// notifications and get no response.
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int           `json:"code"`
+	Message string        `json:"message"`
+	Data    *rpcErrorData `json:"data,omitempty"`
+}
+
+// rpcErrorData carries the same details as the error panel in the UI.
+type rpcErrorData struct {
+	Command string `json:"command,omitempty"`
+	Output  string `json:"output,omitempty"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+type serveParams struct {
+	// CWD selects the repository; it defaults to where `wtx serve` started.
+	CWD string `json:"cwd,omitempty"`
+}
+
+type serveListParams struct {
+	serveParams
+	PR bool `json:"pr,omitempty"`
+}
+
+type serveCreateParams struct {
+	serveParams
+	Branch string `json:"branch"`
+	// Existing checks out an existing branch instead of creating one.
+	Existing bool   `json:"existing,omitempty"`
+	BaseRef  string `json:"base_ref,omitempty"`
+	Fetch    *bool  `json:"fetch,omitempty"`
+}
+
+type serveDeleteParams struct {
+	serveParams
+	// Target is a branch or worktree path, as for `wtx rm`.
+	Target     string `json:"target"`
+	Force      bool   `json:"force,omitempty"`
+	WithBranch bool   `json:"with_branch,omitempty"`
+}
+
+type serveLockParams struct {
+	serveParams
+	Path  string `json:"path"`
+	Force bool   `json:"force,omitempty"`
+}
+
+type serveOpenParams struct {
+	serveParams
+	Branch string `json:"branch"`
+	// New creates the branch, as the open screen's new branch form does.
+	New            bool   `json:"new,omitempty"`
+	BaseRef        string `json:"base_ref,omitempty"`
+	CreateWorktree bool   `json:"create_worktree,omitempty"`
+}
+
+type serveWorktreeResult struct {
+	Path   string `json:"path"`
+	Branch string `json:"branch,omitempty"`
+	Locked bool   `json:"locked"`
+	// Warning is what went wrong setting up a new worktree without stopping
+	// it: a failed post_create hook, or repo commands skipped because the
+	// .wtx.json is not trusted.
+	Warning string `json:"warning,omitempty"`
+}
+
+// serveServer answers JSON-RPC calls from editor plugins. Locks taken through
+// lock and open belong to the server process and are held until unlock is
+// called or the server exits.
+type serveServer struct {
+	cwd   string
+	ghMgr *core.GHManager
+	// work serializes calls that change worktrees so concurrent clients do
+	// not race each other through git.
+	work  sync.Mutex
+	mu    sync.Mutex
+	locks map[string]*core.WorktreeLock
+}
+
+func newServeServer(cwd string) *serveServer {
+	return &serveServer{cwd: cwd, ghMgr: core.NewGHManager(), locks: map[string]*core.WorktreeLock{}}
+}
+
+func (s *serveServer) managers(cwd string) (*core.WorktreeManager, *core.WorktreeOrchestrator) {
+	if strings.TrimSpace(cwd) == "" {
+		cwd = s.cwd
+	}
+	lockMgr := core.NewLockManager()
+	mgr := core.NewWorktreeManager(cwd, lockMgr)
+	orchestrator := core.NewWorktreeOrchestrator(mgr, lockMgr, s.ghMgr)
+	// serve is long running itself and reads status right after changing
+	// worktrees, so it does not go through the daemon's warm copy.
+	orchestrator.Daemon = false
+	return mgr, orchestrator
+}
+
+// status returns the repo status with worktrees locked by this server marked
+// as in use. The lock manager treats its own owner's locks as free, which
+// would let two clients open the same worktree.
+func (s *serveServer) status(orchestrator *core.WorktreeOrchestrator) (core.WorktreeStatus, error) {
+	status := orchestrator.Status()
+	if status.Err != nil {
+		return status, status.Err
+	}
+	if !status.GitInstalled {
+		return status, core.ErrGitNotInstalled
+	}
+	if !status.InRepo {
+		return status, core.ErrNotInGitRepository
+	}
+	for i := range status.Worktrees {
+		if s.holds(status.Worktrees[i].Path) {
+			status.Worktrees[i].Available = false
+		}
+	}
+	return status, nil
+}
+
+func (s *serveServer) holds(path string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.locks[filepath.Clean(path)]
+	return ok
+}
+
+// acquire takes the worktree lock for this server. Checking and recording it
+// under one mutex keeps two clients from both getting the same worktree,
+// since the lock manager lets its own owner take a lock again.
+func (s *serveServer) acquire(mgr *core.WorktreeManager, path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := filepath.Clean(path)
+	if _, ok := s.locks[key]; ok {
+		return core.ErrWorktreeLocked
+	}
+	lock, err := mgr.AcquireWorktreeLock(path)
+	if err != nil {
+		return err
+	}
+	s.locks[key] = lock
+	return nil
+}
+
+func (s *serveServer) release(path string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := filepath.Clean(path)
+	lock, ok := s.locks[key]
+	if ok {
+		lock.Release()
+		delete(s.locks, key)
+	}
+	return ok
+}
+
+func (s *serveServer) releaseAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, lock := range s.locks {
+		lock.Release()
+		delete(s.locks, key)
+	}
+}
+
+func (s *serveServer) handle(req rpcRequest) rpcResponse {
+	resp := rpcResponse{JSONRPC: jsonRPCVersion, ID: req.ID}
+	if req.JSONRPC != jsonRPCVersion || strings.TrimSpace(req.Method) == "" {
+		resp.Error = &rpcError{Code: rpcInvalidRequest, Message: "invalid request"}
+		return resp
+	}
+	var (
+		result any
+		err    error
+	)
+	switch req.Method {
+	case serveMethodList:
+		var p serveListParams
+		if err := decodeRPCParams(req.Params, &p); err != nil {
+			resp.Error = err
+			return resp
+		}
+		result, err = s.list(p)
+	case serveMethodCreate:
+		var p serveCreateParams
+		if err := decodeRPCParams(req.Params, &p); err != nil {
+			resp.Error = err
+			return resp
+		}
+		result, err = s.create(p)
+	case serveMethodDelete:
+		var p serveDeleteParams
+		if err := decodeRPCParams(req.Params, &p); err != nil {
+			resp.Error = err
+			return resp
+		}
+		result, err = s.delete(p)
+	case serveMethodLock:
+		var p serveLockParams
+		if err := decodeRPCParams(req.Params, &p); err != nil {
+			resp.Error = err
+			return resp
+		}
+		result, err = s.lock(p)
+	case serveMethodUnlock:
+		var p serveLockParams
+		if err := decodeRPCParams(req.Params, &p); err != nil {
+			resp.Error = err
+			return resp
+		}
+		result, err = s.unlock(p)
+	case serveMethodOpen:
+		var p serveOpenParams
+		if err := decodeRPCParams(req.Params, &p); err != nil {
+			resp.Error = err
+			return resp
+		}
+		result, err = s.open(p)
+	default:
+		resp.Error = &rpcError{Code: rpcMethodNotFound, Message: fmt.Sprintf("unknown method %q", req.Method)}
+		return resp
+	}
+	if err != nil {
+		resp.Error = rpcErrorFor(err)
+		return resp
+	}
+	resp.Result = result
+	return resp
+}
+
+func decodeRPCParams(raw json.RawMessage, v any) *rpcError {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return &rpcError{Code: rpcInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+func rpcErrorFor(err error) *rpcError {
+	rerr := &rpcError{Code: rpcServerError, Message: err.Error()}
+	data := &rpcErrorData{Hint: errorRemediation(err.Error())}
+	var cmdErr *core.CommandError
+	if errors.As(err, &cmdErr) {
+		data.Command = cmdErr.Command
+		data.Output = cmdErr.Output
+	}
+	if *data != (rpcErrorData{}) {
+		rerr.Data = data
+	}
+	return rerr
+}
+
+func (s *serveServer) list(p serveListParams) (worktreeListOutput, error) {
+	_, orchestrator := s.managers(p.CWD)
+	status, err := s.status(orchestrator)
+	if err != nil {
+		return worktreeListOutput{}, err
+	}
+	if p.PR {
+		byBranch, err := orchestrator.PRDataForStatusWithError(status, false)
+		if err != nil {
+			return worktreeListOutput{}, fmt.Errorf("PR data unavailable: %w", err)
+		}
+		applyPRDataToStatus(&status, byBranch)
+	}
+	return buildWorktreeListOutput(status, orchestrator.LockMgr), nil
+}
+
+func (s *serveServer) create(p serveCreateParams) (serveWorktreeResult, error) {
+	branch := strings.TrimSpace(p.Branch)
+	if branch == "" {
+		return serveWorktreeResult{}, errors.New("branch name required")
+	}
+	s.work.Lock()
+	defer s.work.Unlock()
+	mgr, orchestrator := s.managers(p.CWD)
+	status, err := s.status(orchestrator)
+	if err != nil {
+		return serveWorktreeResult{}, err
+	}
+	if p.Existing {
+		created, err := mgr.CreateWorktreeFromBranch(branch, nil)
+		if err != nil {
+			return serveWorktreeResult{}, err
+		}
+		return serveWorktreeResult{Path: created.Path, Branch: created.Branch, Warning: createWarning(created)}, nil
+	}
+	baseRef, doFetch := checkoutDefaults(status)
+	if v := strings.TrimSpace(p.BaseRef); v != "" {
+		baseRef = v
+	}
+	if p.Fetch != nil {
+		doFetch = *p.Fetch
+	}
+	if doFetch {
+		if err := mgr.FetchRepoBaseRef(baseRef, nil); err != nil {
+			return serveWorktreeResult{}, err
+		}
+	}
+	created, err := mgr.CreateWorktree(branch, baseRef, nil)
+	if err != nil {
+		return serveWorktreeResult{}, err
+	}
+	return serveWorktreeResult{Path: created.Path, Branch: created.Branch, Warning: createWarning(created)}, nil
+}
+
+func createWarning(created core.WorktreeInfo) string {
+	warnings := []string{}
+	for _, w := range []string{created.HookWarning, core.UntrustedRepoConfigWarning(created.Path)} {
+		if w != "" {
+			warnings = append(warnings, w)
+		}
+	}
+	return strings.Join(warnings, "; ")
+}
+
+func (s *serveServer) delete(p serveDeleteParams) (serveWorktreeResult, error) {
+	target := strings.TrimSpace(p.Target)
+	if target == "" {
+		return serveWorktreeResult{}, errors.New("branch or path required")
+	}
+	s.work.Lock()
+	defer s.work.Unlock()
+	mgr, orchestrator := s.managers(p.CWD)
+	status, err := s.status(orchestrator)
+	if err != nil {
+		return serveWorktreeResult{}, err
+	}
+	wt, orphaned, ok := resolveRmTarget(status, target)
+	if !ok {
+		return serveWorktreeResult{}, fmt.Errorf("no worktree found for %q", target)
+	}
+	if s.holds(wt.Path) {
+		return serveWorktreeResult{}, fmt.Errorf("worktree %s is locked; unlock it first", wt.Path)
+	}
+	if err := mgr.CanDeleteWorktree(wt.Path); err != nil {
+		return serveWorktreeResult{}, err
+	}
+	warnings, err := mgr.DeleteWorktree(wt.Path, p.Force || orphaned)
+	if err != nil {
+		return serveWorktreeResult{}, err
+	}
+	if p.WithBranch {
+		if err := mgr.DeleteBranch(wt.Branch, p.Force); err != nil {
+			return serveWorktreeResult{}, fmt.Errorf("delete branch %s: %w", wt.Branch, err)
+		}
+	}
+	return serveWorktreeResult{Path: wt.Path, Branch: wt.Branch, Warning: strings.Join(warnings, "; ")}, nil
+}
+
+func (s *serveServer) lock(p serveLockParams) (serveWorktreeResult, error) {
+	path := strings.TrimSpace(p.Path)
+	if path == "" {
+		return serveWorktreeResult{}, errors.New("worktree path required")
+	}
+	mgr, _ := s.managers(p.CWD)
+	if err := s.acquire(mgr, path); err != nil {
+		return serveWorktreeResult{}, err
+	}
+	return serveWorktreeResult{Path: path, Locked: true}, nil
+}
+
+// unlock releases a lock taken through this server. Force removes the lock
+// whoever holds it, like u in the UI.
+func (s *serveServer) unlock(p serveLockParams) (serveWorktreeResult, error) {
+	path := strings.TrimSpace(p.Path)
+	if path == "" {
+		return serveWorktreeResult{}, errors.New("worktree path required")
+	}
+	if s.release(path) {
+		return serveWorktreeResult{Path: path}, nil
+	}
+	if !p.Force {
+		return serveWorktreeResult{}, fmt.Errorf("worktree %s is not locked by this server; pass force to unlock anyway", path)
+	}
+	mgr, _ := s.managers(p.CWD)
+	if err := mgr.UnlockWorktree(path); err != nil {
+		return serveWorktreeResult{}, err
+	}
+	return serveWorktreeResult{Path: path}, nil
+}
+
+// open resolves a branch to a worktree the same way `wtx open` does and locks
+// it, leaving launching an editor or agent to the client.
+func (s *serveServer) open(p serveOpenParams) (serveWorktreeResult, error) {
+	branch := strings.TrimSpace(p.Branch)
+	if branch == "" {
+		return serveWorktreeResult{}, errors.New("branch name required")
+	}
+	s.work.Lock()
+	defer s.work.Unlock()
+	mgr, orchestrator := s.managers(p.CWD)
+	status, err := s.status(orchestrator)
+	if err != nil {
+		return serveWorktreeResult{}, err
+	}
+	gitPath, repoRoot, err := core.RequireGitContext(mgr.Cwd)
+	if err != nil {
+		return serveWorktreeResult{}, err
+	}
+	exists, err := branchExistsLocalOrRemote(repoRoot, gitPath, branch)
+	if err != nil {
+		return serveWorktreeResult{}, err
+	}
+	if p.New && exists {
+		return serveWorktreeResult{}, fmt.Errorf("branch %q already exists locally or on a remote", branch)
+	}
+	if !p.New && !exists {
+		return serveWorktreeResult{}, fmt.Errorf("branch %q does not exist locally or on known remote-tracking refs", branch)
+	}
+	baseRef, doFetch := checkoutDefaults(status)
+	if v := strings.TrimSpace(p.BaseRef); v != "" {
+		baseRef = v
+	}
+	slots, err := loadOpenSlotsForCheckout(orchestrator, status)
+	if err != nil {
+		return serveWorktreeResult{}, err
+	}
+	if slot, ok := orchestrator.ResolveOpenTargetSlot(slots, branch, p.New); ok {
+		if err := s.acquire(mgr, slot.Path); err != nil {
+			return serveWorktreeResult{}, err
+		}
+		switch {
+		case p.New:
+			err = mgr.CheckoutNewBranch(slot.Path, branch, baseRef, doFetch, nil)
+		case strings.TrimSpace(slot.Branch) != branch:
+			err = mgr.CheckoutExistingBranch(slot.Path, branch, nil)
+		}
+		if err != nil {
+			s.release(slot.Path)
+			return serveWorktreeResult{}, err
+		}
+		return serveWorktreeResult{Path: slot.Path, Branch: branch, Locked: true}, nil
+	}
+	if !p.CreateWorktree {
+		return serveWorktreeResult{}, fmt.Errorf("no worktree is available for %s; pass create_worktree to add one", branch)
+	}
+	var created core.WorktreeInfo
+	if p.New {
+		if doFetch {
+			if err := mgr.FetchRepoBaseRef(baseRef, nil); err != nil {
+				return serveWorktreeResult{}, err
+			}
+		}
+		created, err = mgr.CreateWorktree(branch, baseRef, nil)
+	} else {
+		created, err = mgr.CreateWorktreeFromBranch(branch, nil)
+	}
+	if err != nil {
+		return serveWorktreeResult{}, err
+	}
+	if err := s.acquire(mgr, created.Path); err != nil {
+		return serveWorktreeResult{}, err
+	}
+	return serveWorktreeResult{Path: created.Path, Branch: branch, Locked: true, Warning: createWarning(created)}, nil
+}
+
+func (s *serveServer) serveConn(conn net.Conn) {
+	defer conn.Close()
+	_ = serveRPC(conn, conn, s.handle)
+}
+
+// serveRPC answers newline-delimited JSON-RPC requests read from r until it
+// is closed.
+func serveRPC(r io.Reader, w io.Writer, handle func(rpcRequest) rpcResponse) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	enc := json.NewEncoder(w)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var req rpcRequest
+		if err := json.Unmarshal([]byte(line), &req); err != nil {
+			_ = enc.Encode(rpcResponse{JSONRPC: jsonRPCVersion, Error: &rpcError{Code: rpcParseError, Message: err.Error()}})
+			continue
+		}
+		resp := handle(req)
+		if len(req.ID) == 0 {
+			continue
+		}
+		if err := enc.Encode(resp); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+func serveSocketPath() (string, error) {
+	home, err := core.WtxHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, serveSocketName), nil
+}
+
+func runServe(ctx context.Context, path string, cwd string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if conn, err := net.DialTimeout("unix", path, core.DaemonDialTimeout); err == nil {
+		conn.Close()
+		return fmt.Errorf("wtx serve is already running on %s", path)
+	}
+	_ = os.Remove(path)
+	listener, err := core.ListenPrivateSocket(path)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(path)
+
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+	server := newServeServer(cwd)
+	defer server.releaseAll()
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go server.serveConn(conn)
+	}
+}
+
+func runServeCommand(socket string) error {
+	path := strings.TrimSpace(socket)
+	if path == "" {
+		var err error
+		if path, err = serveSocketPath(); err != nil {
+			return err
+		}
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	fmt.Fprintf(os.Stderr, "wtx serve: listening on %s\n", path)
+	return runServe(ctx, path, cwd)
+}
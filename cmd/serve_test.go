@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+func TestServeAnswersJSONRPC(t *testing.T) {
+	home, err := os.MkdirTemp("", "wtx")
+	if err != nil {
+		t.Fatalf("mkdir home: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(home) })
+	t.Setenv("HOME", home)
+	t.Setenv("TMUX", "")
+	t.Setenv("TMUX_PANE", "")
+	repo := initGitReadTestRepo(t)
+	socket := filepath.Join(home, "s.sock")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- runServe(ctx, socket, repo) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("serve exited with %v", err)
+		}
+	})
+	var conn net.Conn
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if conn, err = net.Dial("unix", socket); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("serve did not start: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(10 * time.Second))
+	reader := bufio.NewReader(conn)
+	call := func(line string) rpcResponse {
+		t.Helper()
+		if _, err := conn.Write([]byte(line + "\n")); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		raw, err := reader.ReadBytes('\n')
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		var resp rpcResponse
+		if err := json.Unmarshal(raw, &resp); err != nil {
+			t.Fatalf("decode %s: %v", raw, err)
+		}
+		return resp
+	}
+	listed := func(id int) worktreeListEntry {
+		t.Helper()
+		resp := call(`{"jsonrpc":"2.0","id":` + strconv.Itoa(id) + `,"method":"list"}`)
+		if resp.Error != nil {
+			t.Fatalf("list: %+v", resp.Error)
+		}
+		raw, _ := json.Marshal(resp.Result)
+		var out worktreeListOutput
+		if err := json.Unmarshal(raw, &out); err != nil || len(out.Worktrees) != 1 {
+			t.Fatalf("unexpected list result %s: %v", raw, err)
+		}
+		return out.Worktrees[0]
+	}
+
+	main := listed(1)
+	if main.Branch != "main" || !main.Available {
+		t.Fatalf("expected free main worktree, got %+v", main)
+	}
+
+	pathJSON, _ := json.Marshal(main.Path)
+	if resp := call(`{"jsonrpc":"2.0","id":2,"method":"lock","params":{"path":` + string(pathJSON) + `}}`); resp.Error != nil {
+		t.Fatalf("lock: %+v", resp.Error)
+	}
+	if resp := call(`{"jsonrpc":"2.0","id":3,"method":"lock","params":{"path":` + string(pathJSON) + `}}`); resp.Error == nil || resp.Error.Message != core.ErrWorktreeLocked.Error() {
+		t.Fatalf("expected second lock to fail, got %+v", resp)
+	}
+	if got := listed(4); got.Available {
+		t.Fatalf("expected locked worktree to be listed as in use")
+	}
+	if resp := call(`{"jsonrpc":"2.0","id":5,"method":"unlock","params":{"path":` + string(pathJSON) + `}}`); resp.Error != nil {
+		t.Fatalf("unlock: %+v", resp.Error)
+	}
+	if got := listed(6); !got.Available {
+		t.Fatalf("expected worktree to be free after unlock")
+	}
+
+	if resp := call(`{"jsonrpc":"2.0","id":7,"method":"nope"}`); resp.Error == nil || resp.Error.Code != rpcMethodNotFound {
+		t.Fatalf("expected method not found, got %+v", resp)
+	}
+	if resp := call(`{not json`); resp.Error == nil || resp.Error.Code != rpcParseError {
+		t.Fatalf("expected parse error, got %+v", resp)
+	}
+
+	resp := call(`{"jsonrpc":"2.0","id":8,"method":"open","params":{"branch":"main"}}`)
+	if resp.Error != nil {
+		t.Fatalf("open: %+v", resp.Error)
+	}
+	raw, _ := json.Marshal(resp.Result)
+	var opened serveWorktreeResult
+	if err := json.Unmarshal(raw, &opened); err != nil || opened.Path != main.Path || !opened.Locked {
+		t.Fatalf("expected open to lock the main worktree, got %s (%v)", raw, err)
+	}
+	if resp := call(`{"jsonrpc":"2.0","id":9,"method":"lock","params":{"path":` + string(pathJSON) + `}}`); resp.Error == nil || resp.Error.Message != core.ErrWorktreeLocked.Error() {
+		t.Fatalf("expected the opened worktree to stay locked, got %+v", resp)
+	}
+	// Windows has no permission bits on socket files.
+	if runtime.GOOS != "windows" {
+		if info, err := os.Stat(socket); err != nil || info.Mode().Perm() != 0o600 {
+			t.Fatalf("expected a private socket, got %v (%v)", info.Mode(), err)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/aixolotls/wtx/internal/core"
+	"github.com/spf13/cobra"
+)
+
+func newResumeCommand() *cobra.Command {
+	var listOnly bool
+	cmd := &cobra.Command{
+		Use:   "resume",
+		Short: "Recreate agent panes saved before a reboot or tmux restart",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runResume(listOnly)
+		},
+	}
+	cmd.Flags().BoolVar(&listOnly, "list", false, "List saved sessions without resuming them")
+	return cmd
+}
+
+func runResume(listOnly bool) error {
+	if listOnly {
+		sessions, err := core.ReadSavedSessions()
+		if err != nil {
+			return err
+		}
+		if len(sessions) == 0 {
+			fmt.Println("No saved wtx sessions.")
+			return nil
+		}
+		for _, s := range sessions {
+			fmt.Printf("%s\t%s\t%s\n", s.Branch, s.WorktreePath, s.AgentCommand)
+		}
+		return nil
+	}
+	if core.TmuxIntegrationDisabled() {
+		return errors.New("wtx resume requires tmux (WTX_DISABLE_TMUX is set)")
+	}
+	if _, err := exec.LookPath("tmux"); err != nil {
+		return errors.New("wtx resume requires tmux")
+	}
+	sessions, dropped, err := core.PruneSavedSessions()
+	if err != nil {
+		return err
+	}
+	for _, path := range dropped {
+		fmt.Fprintf(os.Stderr, "wtx resume: dropping %s: worktree no longer exists\n", path)
+	}
+	if len(sessions) == 0 {
+		fmt.Println("No saved wtx sessions.")
+		return nil
+	}
+
+	lockMgr := core.NewLockManager()
+	runner := NewRunner(lockMgr)
+	inTmux := strings.TrimSpace(os.Getenv("TMUX")) != ""
+	tmuxSession := ""
+	resumed := 0
+	for _, s := range sessions {
+		lock, err := lockMgr.Acquire(s.RepoRoot, s.WorktreePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "wtx resume: skipping %s: %v\n", s.WorktreePath, err)
+			continue
+		}
+		runCmd := commandToRunInTmux(s.WorktreePath, false, agentLaunch{Command: s.AgentCommand, Base: s.AgentCommand})
+		if s.Layout == agentLayoutSplit && inTmux {
+			err = runner.openLockedSplitPane(s.WorktreePath, lock, runCmd)
+		} else {
+			tmuxSession, err = runner.openLockedWorktreeWindow(tmuxSession, s.WorktreePath, s.Branch, lock, runCmd)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "wtx resume: %s: %v\n", s.WorktreePath, err)
+			continue
+		}
+		resumed++
+	}
+	if resumed == 0 {
+		fmt.Println("No wtx sessions resumed.")
+		return nil
+	}
+	fmt.Printf("Resumed %d wtx session(s).\n", resumed)
+	if tmuxSession == "" {
+		return nil
+	}
+	return enterTmuxSession(tmuxSession, inTmux)
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+func writeTestSessions(t *testing.T, sessions ...core.SavedSession) {
+	t.Helper()
+	path, err := core.SavedSessionsPath()
+	if err != nil {
+		t.Fatalf("sessions path: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := core.WriteJSONStore(path, core.SavedSessionsFile{Sessions: sessions}); err != nil {
+		t.Fatalf("write sessions: %v", err)
+	}
+}
+
+func TestRememberAndForgetSession(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	writeTestSessions(t,
+		core.SavedSession{RepoRoot: "/repo", WorktreePath: "/repo.wt/wt.1", Branch: "feature/a", Layout: agentLayoutSplit, AgentCommand: "claude"},
+		core.SavedSession{RepoRoot: "/repo", WorktreePath: "/repo.wt/wt.2", Branch: "feature/b", Layout: agentLayoutSplit, AgentCommand: "codex"},
+	)
+
+	if err := core.ForgetSession("/repo.wt/wt.1/"); err != nil {
+		t.Fatalf("forget session: %v", err)
+	}
+	sessions, err := core.ReadSavedSessions()
+	if err != nil {
+		t.Fatalf("read sessions: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].Branch != "feature/b" {
+		t.Fatalf("expected only feature/b to remain, got %+v", sessions)
+	}
+}
+
+func TestPruneSavedSessionsDropsMissingWorktrees(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	live := t.TempDir()
+	writeTestSessions(t,
+		core.SavedSession{WorktreePath: live, Layout: agentLayoutWindow, AgentCommand: "claude"},
+		core.SavedSession{WorktreePath: filepath.Join(live, "gone"), AgentCommand: "codex"},
+	)
+	kept, dropped, err := core.PruneSavedSessions()
+	if err != nil {
+		t.Fatalf("prune sessions: %v", err)
+	}
+	if len(kept) != 1 || kept[0].WorktreePath != live || kept[0].Layout != agentLayoutWindow {
+		t.Fatalf("expected only the live session to remain, got %+v", kept)
+	}
+	if len(dropped) != 1 || dropped[0] != filepath.Join(live, "gone") {
+		t.Fatalf("expected the missing worktree reported as dropped, got %q", dropped)
+	}
+	sessions, err := core.ReadSavedSessions()
+	if err != nil {
+		t.Fatalf("read sessions: %v", err)
+	}
+	if len(sessions) != 1 {
+		t.Fatalf("expected the pruned file to hold one session, got %+v", sessions)
+	}
+}
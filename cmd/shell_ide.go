@@ -4,8 +4,10 @@ import (
 	"errors"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
-	"syscall"
+
+	"github.com/aixolotls/wtx/internal/core"
 )
 
 func runShell() error {
@@ -16,22 +18,13 @@ func runShell() error {
 	if err != nil {
 		return err
 	}
-	cmd := exec.Command("tmux", "split-window", "-v", "-p", "50", "-c", cwd)
-	return cmd.Run()
+	return core.TmuxRun("split-window", "-v", "-p", "50", "-c", tmuxFormatEscape(cwd))
 }
 
 func runIDE(args []string) error {
 	if err := ensureConfigReady(); err != nil {
 		return err
 	}
-	cfg, err := LoadConfig()
-	if err != nil {
-		return err
-	}
-	_, ideCmd, err := ensureIDECommandConfigured(cfg)
-	if err != nil {
-		return err
-	}
 
 	var targetPath string
 	if len(args) > 0 {
@@ -43,10 +36,128 @@ func runIDE(args []string) error {
 	// Clean up trailing slashes from empty subpath input
 	targetPath = strings.TrimSuffix(targetPath, "/")
 
-	cmd := exec.Command(ideCmd, targetPath)
-	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	ideCmd, err := resolveIDECommand(targetPath)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := core.LoadConfigForDir(ideConfigDir(targetPath))
+	if err != nil {
+		return err
+	}
+	launchArgs, err := ideLaunchArgs(ideCmd, targetPath, cfg)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(ideCmd, launchArgs...)
+	cmd.SysProcAttr = core.DetachedProcAttr()
 	cmd.Stdin = nil
 	cmd.Stdout = nil
 	cmd.Stderr = nil
 	return cmd.Start()
 }
+
+// resolveIDECommand prefers the repo's IDE override and falls back to the
+// global setting, prompting for one if neither is set.
+func resolveIDECommand(dir string) (string, error) {
+	dir = ideConfigDir(dir)
+	ideCmd, err := core.RepoIDECommand(dir)
+	if err != nil {
+		return "", err
+	}
+	if ideCmd != "" {
+		return ideCmd, nil
+	}
+	cfg, err := core.LoadConfig()
+	if err != nil {
+		return "", err
+	}
+	_, ideCmd, err = ensureIDECommandConfigured(cfg)
+	return ideCmd, err
+}
+
+const (
+	ideWindowReuse = "reuse"
+	ideWindowNew   = "new"
+)
+
+var ideWindowModes = []string{ideWindowReuse, ideWindowNew}
+
+// jetbrainsCommands are the JetBrains launcher scripts, as installed by the
+// Toolbox app or "Create Command-line Launcher".
+var jetbrainsCommands = []string{"idea", "goland", "pycharm", "webstorm"}
+
+func isJetBrainsCommand(ideCmd string) bool {
+	fields := strings.Fields(ideCmd)
+	if len(fields) == 0 {
+		return false
+	}
+	name := filepath.Base(fields[0])
+	for _, c := range jetbrainsCommands {
+		if name == c {
+			return true
+		}
+	}
+	return false
+}
+
+func ideConfigDir(targetPath string) string {
+	if info, err := os.Stat(targetPath); err == nil && !info.IsDir() {
+		return filepath.Dir(targetPath)
+	}
+	return targetPath
+}
+
+// ideLaunchArgs builds the arguments for opening targetPath. VS Code-family
+// editors take the window choice as a flag. JetBrains launchers have no such
+// flag and open a file inside whatever project is focused, so they always get
+// a project directory; the IDE's "Open project in" setting then picks the
+// window.
+func ideLaunchArgs(ideCmd string, targetPath string, cfg core.Config) ([]string, error) {
+	switch {
+	case isVSCodeCommand(ideCmd):
+		args := []string{}
+		switch cfg.IDEWindow {
+		case ideWindowNew:
+			args = append(args, "--new-window")
+		case ideWindowReuse:
+			args = append(args, "--reuse-window")
+		}
+		if cfg.IDEVSCodeWorkspace {
+			workspace, err := prepareVSCodeWorkspace(targetPath)
+			if err != nil {
+				return nil, err
+			}
+			if cfg.IDEWindow == "" {
+				args = append(args, "--new-window")
+			}
+			return append(args, workspace), nil
+		}
+		return append(args, targetPath), nil
+	case isJetBrainsCommand(ideCmd):
+		return []string{jetbrainsProjectDir(targetPath)}, nil
+	default:
+		return []string{targetPath}, nil
+	}
+}
+
+// jetbrainsProjectDir returns the nearest directory at or above targetPath
+// holding a .idea project, stopping at the worktree root, so opening a
+// subfolder reuses the worktree's project settings instead of creating a new
+// project inside it.
+func jetbrainsProjectDir(targetPath string) string {
+	dir := ideConfigDir(targetPath)
+	root, err := core.RepoRootForDir(dir, "")
+	if err != nil {
+		return dir
+	}
+	for current := dir; ; current = filepath.Dir(current) {
+		if info, err := os.Stat(filepath.Join(current, ".idea")); err == nil && info.IsDir() {
+			return current
+		}
+		if core.SameSessionPath(current, root) || filepath.Dir(current) == current {
+			return dir
+		}
+	}
+}
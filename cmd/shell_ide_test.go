@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+func TestIDELaunchArgsWindowMode(t *testing.T) {
+	tests := []struct {
+		ide    string
+		window string
+		want   []string
+	}{
+		{ide: "code", want: []string{"/wt"}},
+		{ide: "code", window: ideWindowNew, want: []string{"--new-window", "/wt"}},
+		{ide: "cursor", window: ideWindowReuse, want: []string{"--reuse-window", "/wt"}},
+		{ide: "sublime", window: ideWindowNew, want: []string{"/wt"}},
+	}
+	for _, tc := range tests {
+		got, err := ideLaunchArgs(tc.ide, "/wt", core.Config{IDEWindow: tc.window})
+		if err != nil || !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("ideLaunchArgs(%q, %q) = %v, %v; want %v", tc.ide, tc.window, got, err, tc.want)
+		}
+	}
+}
+
+func TestJetBrainsOpensEnclosingProject(t *testing.T) {
+	repo := initGitReadTestRepo(t)
+	sub := filepath.Join(repo, "services", "api")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(sub, "main.go")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, _ := ideLaunchArgs("goland", file, core.Config{}); !reflect.DeepEqual(got, []string{sub}) {
+		t.Fatalf("expected a file to open its directory, got %v", got)
+	}
+	if err := os.Mkdir(filepath.Join(repo, ".idea"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := ideLaunchArgs("idea", sub, core.Config{}); !reflect.DeepEqual(got, []string{repo}) {
+		t.Fatalf("expected the .idea project root, got %v", got)
+	}
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aixolotls/wtx/internal/core"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// offerStash asks whether to stash and retry when err is a switch refused
+// over local changes; any other error is shown as usual.
+func (m model) offerStash(err error) (tea.Model, tea.Cmd) {
+	var dirty *core.DirtyCheckoutError
+	if !errors.As(err, &dirty) {
+		return m.setError(err), nil
+	}
+	files := strings.Join(dirty.Files, ", ")
+	if len(dirty.Files) > 3 {
+		files = fmt.Sprintf("%s and %d more", strings.Join(dirty.Files[:3], ", "), len(dirty.Files)-3)
+	}
+	m.errMsg = ""
+	m.stashPending = dirty
+	m.confirmResult = false
+	m.confirmKind = confirmStashSwitch
+	m.confirmForm = newConfirmForm(
+		"Stash local changes and switch?",
+		fmt.Sprintf("Switching to %s would overwrite %s.\nwtx stashes the changes, switches and pops them back.", dirty.Branch, files),
+		&m.confirmResult,
+	)
+	return m, m.confirmForm.Init()
+}
+
+func stashAndCheckoutCmd(mgr *core.WorktreeManager, dirty *core.DirtyCheckoutError, progress io.Writer) tea.Cmd {
+	return func() tea.Msg {
+		lock, err := mgr.AcquireWorktreeLock(dirty.Path)
+		if err != nil {
+			return openUseReadyMsg{err: err}
+		}
+		if err := mgr.StashAndCheckout(dirty, progress); err != nil {
+			lock.Release()
+			return openUseReadyMsg{err: err}
+		}
+		return openUseReadyMsg{path: dirty.Path, branch: dirty.Branch, lock: lock}
+	}
+}
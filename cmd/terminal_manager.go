@@ -2,9 +2,13 @@ package cmd
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
+
+	"github.com/aixolotls/wtx/internal/core"
 )
 
 var (
@@ -26,7 +30,7 @@ func setITermWTXBranchTab(branch string) {
 }
 
 func setITermTab(title string) {
-	if iTermIntegrationDisabled() {
+	if core.ITermIntegrationDisabled() {
 		return
 	}
 	inTmux := strings.TrimSpace(os.Getenv("TMUX")) != ""
@@ -53,7 +57,7 @@ func setITermTab(title string) {
 }
 
 func resetITermTabColor() {
-	if iTermIntegrationDisabled() {
+	if core.ITermIntegrationDisabled() {
 		return
 	}
 	inTmux := strings.TrimSpace(os.Getenv("TMUX")) != ""
@@ -64,6 +68,54 @@ func resetITermTabColor() {
 	writeTerminalEscape("\x1b]1337;SetTabColor=\x07")
 }
 
+// setTerminalCwd reports path with OSC 7 so terminals open new tabs and
+// splits there.
+func setTerminalCwd(path string) {
+	if core.TerminalEscapesDisabled() || !isInteractiveTerminalFn(os.Stdout) {
+		return
+	}
+	host, _ := os.Hostname()
+	writeTerminalEscape(osc7Sequence(host, path))
+}
+
+func osc7Sequence(host string, path string) string {
+	u := url.URL{Scheme: "file", Host: host, Path: filepath.ToSlash(filepath.Clean(path))}
+	return "\x1b]7;" + u.String() + "\x07"
+}
+
+// notifyTerminal raises a desktop notification through the terminal.
+// Terminals that ignore the sequence drop it silently.
+func notifyTerminal(title string, body string) {
+	if core.TerminalEscapesDisabled() || !isInteractiveTerminalFn(os.Stdout) {
+		return
+	}
+	writeTerminalEscape(terminalNotifySequence(title, body, os.Getenv))
+}
+
+// terminalNotifySequence picks OSC 777 for terminals known to speak it
+// (urxvt, foot, Ghostty, VTE) and OSC 9 otherwise, which iTerm2, WezTerm,
+// kitty, and Windows Terminal understand.
+func terminalNotifySequence(title string, body string, getenv func(string) string) string {
+	clean := strings.NewReplacer("\x07", "", "\x1b", "", ";", ",").Replace
+	term := strings.ToLower(getenv("TERM"))
+	program := strings.ToLower(getenv("TERM_PROGRAM"))
+	if strings.Contains(term, "rxvt") || strings.HasPrefix(term, "foot") || program == "ghostty" || getenv("VTE_VERSION") != "" {
+		return "\x1b]777;notify;" + clean(title) + ";" + clean(body) + "\x07"
+	}
+	return "\x1b]9;" + clean(title) + ": " + clean(body) + "\x07"
+}
+
+func agentFinishedMessage(branch string, exitCode int) string {
+	branch = strings.TrimSpace(branch)
+	if branch == "" {
+		branch = "worktree"
+	}
+	if exitCode != 0 {
+		return fmt.Sprintf("agent in %s exited with code %d", branch, exitCode)
+	}
+	return "agent in " + branch + " finished"
+}
+
 func writeTerminalEscape(seq string) {
 	if strings.TrimSpace(seq) == "" {
 		return
@@ -24,3 +24,22 @@ func TestShouldSkipTabTitleUpdate_AllowsDifferentTitle(t *testing.T) {
 		t.Fatalf("different title should not be skipped")
 	}
 }
+
+func TestOSC7SequenceEscapesPath(t *testing.T) {
+	got := osc7Sequence("box", "/repo.wt/my tree/")
+	if got != "\x1b]7;file://box/repo.wt/my%20tree\x07" {
+		t.Fatalf("unexpected OSC 7 sequence %q", got)
+	}
+}
+
+func TestTerminalNotifySequencePicksOSCByTerminal(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(k string) string { return vars[k] }
+	}
+	if got := terminalNotifySequence("wtx", "agent in a;b finished", env(map[string]string{"TERM_PROGRAM": "iTerm.app"})); got != "\x1b]9;wtx: agent in a,b finished\x07" {
+		t.Fatalf("unexpected OSC 9 sequence %q", got)
+	}
+	if got := terminalNotifySequence("wtx", "done", env(map[string]string{"TERM": "foot"})); got != "\x1b]777;notify;wtx;done\x07" {
+		t.Fatalf("unexpected OSC 777 sequence %q", got)
+	}
+}
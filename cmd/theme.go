@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aixolotls/wtx/internal/core"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	themeDark  = "dark"
+	themeLight = "light"
+)
+
+var themeNames = []string{themeDark, themeLight}
+
+// palette names every color role the TUI, forms, popups and tmux status bar
+// use. Custom colors in the config override roles by their json name.
+type palette struct {
+	Accent   string `json:"accent"`
+	OnAccent string `json:"on_accent"`
+	Text     string `json:"text"`
+	Strong   string `json:"strong"`
+	Muted    string `json:"muted"`
+	Disabled string `json:"disabled"`
+	Faint    string `json:"faint"`
+	Error    string `json:"error"`
+	Warn     string `json:"warn"`
+	Notice   string `json:"notice"`
+	Success  string `json:"success"`
+	// StatusText and StatusBar color the tmux status line of wtx sessions.
+	StatusText string `json:"status_text"`
+	StatusBar  string `json:"status_bar"`
+}
+
+var palettes = map[string]palette{
+	themeDark: {
+		Accent:     "#7D56F4",
+		OnAccent:   "#FFF7DB",
+		Text:       "251",
+		Strong:     "15",
+		Muted:      "245",
+		Disabled:   "241",
+		Faint:      "239",
+		Error:      "1",
+		Warn:       "3",
+		Notice:     "#E8DFA5",
+		Success:    "2",
+		StatusText: "#d0d0d0",
+		StatusBar:  "#3d2a5c",
+	},
+	themeLight: {
+		Accent:     "#5A3FC0",
+		OnAccent:   "#FFFFFF",
+		Text:       "236",
+		Strong:     "0",
+		Muted:      "242",
+		Disabled:   "246",
+		Faint:      "248",
+		Error:      "160",
+		Warn:       "130",
+		Notice:     "94",
+		Success:    "28",
+		StatusText: "#2e2440",
+		StatusBar:  "#e4dcf7",
+	},
+}
+
+// Styles shared by every view. applyPalette rebuilds them, so render code
+// must read these instead of building its own lipgloss colors.
+var (
+	baseStyle                     lipgloss.Style
+	bannerStyle                   lipgloss.Style
+	titleStyle                    lipgloss.Style
+	errorStyle                    lipgloss.Style
+	secondaryStyle                lipgloss.Style
+	actionNormalStyle             lipgloss.Style
+	actionSelectedStyle           lipgloss.Style
+	selectorNormalStyle           lipgloss.Style
+	selectorSelectedStyle         lipgloss.Style
+	selectorDisabledStyle         lipgloss.Style
+	selectorDisabledSelectedStyle lipgloss.Style
+	selectorHeaderStyle           lipgloss.Style
+	branchStyle                   lipgloss.Style
+	branchInlineStyle             lipgloss.Style
+	warnStyle                     lipgloss.Style
+	tmuxStatusDisabledHintStyle   lipgloss.Style
+	updateHintStyle               lipgloss.Style
+	inputStyle                    lipgloss.Style
+	diffAddStyle                  lipgloss.Style
+	diffRemoveStyle               lipgloss.Style
+	staleStyle                    lipgloss.Style
+	noteStyle                     lipgloss.Style
+	accentColor                   lipgloss.Color
+	// activePalette is the palette last applied, for the form theme and the
+	// tmux status line, which are not lipgloss styles.
+	activePalette palette
+)
+
+func init() {
+	applyPalette(palettes[themeDark])
+}
+
+func applyPalette(p palette) {
+	activePalette = p
+	accentColor = lipgloss.Color(p.Accent)
+	baseStyle = lipgloss.NewStyle()
+	bannerStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color(p.OnAccent)).
+		Background(accentColor).
+		Padding(0, 1)
+	titleStyle = lipgloss.NewStyle().Foreground(accentColor).Bold(true)
+	errorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(p.Error)).Bold(true)
+	secondaryStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(p.Muted))
+	actionNormalStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(p.Text))
+	actionSelectedStyle = lipgloss.NewStyle().Foreground(accentColor).Bold(true)
+	selectorNormalStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(p.Text))
+	selectorSelectedStyle = lipgloss.NewStyle().Foreground(accentColor).Bold(true)
+	selectorDisabledStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(p.Disabled))
+	selectorDisabledSelectedStyle = lipgloss.NewStyle().Foreground(accentColor).Bold(true)
+	selectorHeaderStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(p.Strong)).Bold(true)
+	branchStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(p.Strong)).Bold(true)
+	branchInlineStyle = lipgloss.NewStyle().Bold(true)
+	warnStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(p.Warn)).Bold(true)
+	tmuxStatusDisabledHintStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(p.Notice))
+	updateHintStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(p.Faint))
+	inputStyle = lipgloss.NewStyle().Padding(0, 1)
+	diffAddStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(p.Success))
+	diffRemoveStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(p.Error))
+	staleStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(p.Warn))
+	noteStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(p.Notice)).Italic(true)
+}
+
+// resolvePalette picks the configured theme and layers custom colors on
+// top. Unknown themes and color roles are reported but not fatal.
+func resolvePalette(cfg core.Config) (palette, []string) {
+	warnings := []string{}
+	name := strings.TrimSpace(cfg.Theme)
+	if name == "" {
+		name = themeDark
+	}
+	p, ok := palettes[name]
+	if !ok {
+		warnings = append(warnings, fmt.Sprintf("unknown theme %q, using %s", name, themeDark))
+		p = palettes[themeDark]
+	}
+	roles := map[string]*string{
+		"accent":      &p.Accent,
+		"on_accent":   &p.OnAccent,
+		"text":        &p.Text,
+		"strong":      &p.Strong,
+		"muted":       &p.Muted,
+		"disabled":    &p.Disabled,
+		"faint":       &p.Faint,
+		"error":       &p.Error,
+		"warn":        &p.Warn,
+		"notice":      &p.Notice,
+		"success":     &p.Success,
+		"status_text": &p.StatusText,
+		"status_bar":  &p.StatusBar,
+	}
+	keys := make([]string, 0, len(cfg.Colors))
+	for key := range cfg.Colors {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := strings.TrimSpace(cfg.Colors[key])
+		role, ok := roles[strings.TrimSpace(key)]
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("unknown color role %q", key))
+			continue
+		}
+		if value != "" {
+			*role = value
+		}
+	}
+	return p, warnings
+}
+
+// loadConfiguredTheme applies the theme from the global config.
+func loadConfiguredTheme() {
+	cfg, err := core.LoadConfig()
+	if err != nil {
+		return
+	}
+	p, warnings := resolvePalette(cfg)
+	for _, warning := range warnings {
+		fmt.Fprintln(os.Stderr, "wtx warning: "+warning)
+	}
+	applyPalette(p)
+}
+
+// tmuxStatusStyle is the status-style of wtx sessions in the active palette.
+func tmuxStatusStyle() string {
+	return "fg=" + tmuxColor(activePalette.StatusText) + ",bg=" + tmuxColor(activePalette.StatusBar)
+}
+
+// tmuxColor turns a palette color into tmux syntax, where ANSI numbers are
+// written colourN.
+func tmuxColor(c string) string {
+	c = strings.TrimSpace(c)
+	if _, err := strconv.Atoi(c); err == nil {
+		return "colour" + c
+	}
+	return c
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aixolotls/wtx/internal/core"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestResolvePaletteAppliesSchemeAndCustomColors(t *testing.T) {
+	p, warnings := resolvePalette(core.Config{})
+	if p != palettes[themeDark] || len(warnings) != 0 {
+		t.Fatalf("expected dark palette by default, got %+v %v", p, warnings)
+	}
+
+	p, warnings = resolvePalette(core.Config{
+		Theme:  themeLight,
+		Colors: map[string]string{"accent": "#005FAF", "sparkle": "1"},
+	})
+	if p.Accent != "#005FAF" || p.Text != palettes[themeLight].Text {
+		t.Fatalf("expected light palette with custom accent, got %+v", p)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "sparkle") {
+		t.Fatalf("expected a warning for the unknown role, got %v", warnings)
+	}
+
+	p, warnings = resolvePalette(core.Config{Theme: "solarized"})
+	if p != palettes[themeDark] || len(warnings) != 1 {
+		t.Fatalf("expected dark fallback with a warning, got %+v %v", p, warnings)
+	}
+}
+
+func TestApplyPaletteRestylesSharedStyles(t *testing.T) {
+	t.Cleanup(func() { applyPalette(palettes[themeDark]) })
+	applyPalette(palettes[themeLight])
+	if got := secondaryStyle.GetForeground(); got != lipgloss.TerminalColor(lipgloss.Color(palettes[themeLight].Muted)) {
+		t.Fatalf("expected light muted color, got %v", got)
+	}
+	if got := bannerStyle.GetBackground(); got != lipgloss.TerminalColor(accentColor) {
+		t.Fatalf("expected banner to use the accent color, got %v", got)
+	}
+}
+
+func TestTmuxStatusStyleFollowsPalette(t *testing.T) {
+	t.Cleanup(func() { applyPalette(palettes[themeDark]) })
+	if got := tmuxStatusStyle(); got != "fg=#d0d0d0,bg=#3d2a5c" {
+		t.Fatalf("unexpected dark status style %q", got)
+	}
+	light := palettes[themeLight]
+	light.StatusText = "236"
+	applyPalette(light)
+	if got := tmuxStatusStyle(); got != "fg=colour236,bg="+light.StatusBar {
+		t.Fatalf("expected the light status bar with a tmux colour, got %q", got)
+	}
+}
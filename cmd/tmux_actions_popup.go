@@ -12,9 +12,9 @@ import (
 	"time"
 	"unicode/utf8"
 
+	"github.com/aixolotls/wtx/internal/core"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 )
 
 type tmuxAction string
@@ -31,8 +31,28 @@ const (
 	tmuxActionPR          tmuxAction = "pr"
 	tmuxActionBack        tmuxAction = "back_to_wtx"
 	tmuxActionRename      tmuxAction = "rename_branch"
+	tmuxActionHydrateLFS  tmuxAction = "hydrate_lfs"
+	tmuxActionUpdateBase  tmuxAction = "update_from_base"
+	tmuxActionGitUI       tmuxAction = "git_ui"
+	tmuxActionCopyPath    tmuxAction = "copy_path"
+	tmuxActionCopyBranch  tmuxAction = "copy_branch"
+	tmuxActionCopyPRURL   tmuxAction = "copy_pr_url"
+	tmuxActionReveal      tmuxAction = "reveal"
+	tmuxActionKillAgent   tmuxAction = "kill_agent"
+	tmuxActionRestart     tmuxAction = "restart_agent"
 )
 
+var popupKeyTmuxActions = map[keyAction]tmuxAction{
+	keyActionPopupBack:   tmuxActionBack,
+	keyActionPopupIDE:    tmuxActionIDE,
+	keyActionPopupPR:     tmuxActionPR,
+	keyActionPopupRename: tmuxActionRename,
+	keyActionPopupShell:  tmuxActionShellSplit,
+	keyActionPopupTab:    tmuxActionShellTab,
+	keyActionPopupWindow: tmuxActionShellWindow,
+	keyActionPopupGitUI:  tmuxActionGitUI,
+}
+
 type tmuxActionItem struct {
 	Alias       string
 	Label       string
@@ -51,33 +71,74 @@ type tmuxActionsModel struct {
 	chosen     tmuxAction
 	cancel     bool
 	updateHint string
+	header     string
 	renameErr  string
 	renameTo   string
+	keys       keymap
+	usage      popupUsage
+	showHelp   bool
 }
 
 func newTmuxActionsModel(basePath string, prAvailable bool, canOpenITermTab bool, canOpenShellWindow bool) tmuxActionsModel {
 	terminalName := terminalProgramLabel()
 	windowTerminalName := terminalWindowProgramLabel()
+	keys, err := loadKeymap()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "wtx warning: ignoring keybindings:", err)
+	}
 	items := []tmuxActionItem{
-		{Alias: "back", Label: "Back to WTX", Description: "Back to WTX (stop agent)", Keybinding: "ctrl+w", Action: tmuxActionBack},
-		{Alias: "ide", Label: "Open IDE", Description: "Open IDE", Keybinding: "ctrl+l", Action: tmuxActionIDE},
-		{Alias: "pr", Label: "Open PR", Description: "Open PR", Keybinding: "ctrl+p", Action: tmuxActionPR, Disabled: !prAvailable},
-		{Alias: "rename", Label: "Rename branch", Description: "Rename branch", Keybinding: "ctrl+r", Action: tmuxActionRename},
-		{Alias: "shell", Label: "Open shell", Description: "Open shell (split down)", Keybinding: "ctrl+s", Action: tmuxActionShellSplit},
-		{Alias: "tab", Label: fmt.Sprintf("Open shell tab (%s)", terminalName), Description: fmt.Sprintf("Open shell (new %s tab)", terminalName), Keybinding: "ctrl+t", Action: tmuxActionShellTab, Disabled: !canOpenITermTab},
-		{Alias: "window", Label: fmt.Sprintf("Open shell window (%s)", windowTerminalName), Description: fmt.Sprintf("Open shell (new %s window)", windowTerminalName), Keybinding: "ctrl+n", Action: tmuxActionShellWindow, Disabled: !canOpenShellWindow},
-	}
-	sortTmuxActionItems(items)
+		{Alias: "back", Label: "Back to WTX", Description: "Back to WTX (stop agent)", Keybinding: keys.label(keyActionPopupBack), Action: tmuxActionBack},
+		{Alias: "ide", Label: "Open IDE", Description: "Open IDE", Keybinding: keys.label(keyActionPopupIDE), Action: tmuxActionIDE},
+		{Alias: "pr", Label: "Open PR", Description: "Open PR", Keybinding: keys.label(keyActionPopupPR), Action: tmuxActionPR, Disabled: !prAvailable},
+		{Alias: "rename", Label: "Rename branch", Description: "Rename branch", Keybinding: keys.label(keyActionPopupRename), Action: tmuxActionRename},
+		{Alias: "shell", Label: "Open shell", Description: "Open shell (split down)", Keybinding: keys.label(keyActionPopupShell), Action: tmuxActionShellSplit},
+		{Alias: "tab", Label: fmt.Sprintf("Open shell tab (%s)", terminalName), Description: fmt.Sprintf("Open shell (new %s tab)", terminalName), Keybinding: keys.label(keyActionPopupTab), Action: tmuxActionShellTab, Disabled: !canOpenITermTab},
+		{Alias: "window", Label: fmt.Sprintf("Open shell window (%s)", windowTerminalName), Description: fmt.Sprintf("Open shell (new %s window)", windowTerminalName), Keybinding: keys.label(keyActionPopupWindow), Action: tmuxActionShellWindow, Disabled: !canOpenShellWindow},
+		{Alias: "kill", Label: "Kill agent", Description: "Kill agent (keep shell and lock)", Action: tmuxActionKillAgent},
+		{Alias: "restart", Label: "Restart agent", Description: "Restart agent (same command)", Action: tmuxActionRestart},
+		{Alias: "files", Label: "Open in " + fileManagerLabel(), Description: "Open in " + fileManagerLabel(), Action: tmuxActionReveal, Disabled: fileManagerOpener() == ""},
+		{Alias: "path", Label: "Copy path", Description: "Copy worktree path", Action: tmuxActionCopyPath},
+		{Alias: "branch", Label: "Copy branch", Description: "Copy branch name", Action: tmuxActionCopyBranch},
+		{Alias: "url", Label: "Copy PR URL", Description: "Copy PR URL", Action: tmuxActionCopyPRURL, Disabled: !prAvailable},
+		{Alias: "rebase", Label: "Update from base", Description: core.UpdateFromBaseDescription(basePath), Action: tmuxActionUpdateBase},
+	}
+	gitUI := gitUICommand(basePath)
+	items = append(items, tmuxActionItem{Alias: "git", Label: "Open git UI", Description: "Open " + gitUIName(gitUI), Keybinding: keys.label(keyActionPopupGitUI), Action: tmuxActionGitUI, Disabled: gitUI == ""})
+	if root, err := core.RepoRootForDir(basePath, ""); err == nil && core.RepoUsesLFS(root) {
+		items = append(items, tmuxActionItem{Alias: "lfs", Label: "Hydrate LFS files", Description: "Download Git LFS files (git lfs pull)", Action: tmuxActionHydrateLFS})
+	}
+	items = append(items, customActionItems(basePath, items, keys)...)
+	usage := readPopupUsage()
+	sortTmuxActionItems(items, usage)
 	model := tmuxActionsModel{
 		basePath: basePath,
 		items:    items,
+		keys:     keys,
+		usage:    usage,
 	}
 	model.rebuildFiltered()
 	return model
 }
 
+// withoutTmux disables the actions that drive tmux panes, for the standalone
+// palette.
+func (m tmuxActionsModel) withoutTmux() tmuxActionsModel {
+	for i := range m.items {
+		if tmuxOnlyAction(m.items[i].Action) {
+			m.items[i].Disabled = true
+		}
+	}
+	sortTmuxActionItems(m.items, m.usage)
+	m.rebuildFiltered()
+	return m
+}
+
+func tmuxOnlyAction(action tmuxAction) bool {
+	return action == tmuxActionBack || action == tmuxActionShellSplit || action == tmuxActionKillAgent || action == tmuxActionRestart
+}
+
 func (m tmuxActionsModel) Init() tea.Cmd {
-	return checkInteractiveUpdateHintCmd()
+	return tea.Batch(checkInteractiveUpdateHintCmd(), popupHeaderCmd(m.basePath))
 }
 
 func (m tmuxActionsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -85,25 +146,34 @@ func (m tmuxActionsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case interactiveUpdateHintMsg:
 		m.updateHint = strings.TrimSpace(msg.hint)
 		return m, nil
+	case popupHeaderMsg:
+		m.header = msg.header
+		return m, nil
 	case tea.KeyMsg:
+		if m.showHelp {
+			if msg.String() == "ctrl+c" {
+				m.cancel = true
+				return m, tea.Quit
+			}
+			m.showHelp = false
+			return m, nil
+		}
+		if msg.String() == helpKey {
+			m.showHelp = true
+			return m, nil
+		}
+		if action, ok := m.keys.action(msg.String(), true); ok {
+			return m.selectAction(popupKeyTmuxActions[action])
+		}
+		for _, item := range m.items {
+			if item.Keybinding != "" && item.Keybinding == msg.String() && strings.HasPrefix(string(item.Action), customActionPrefix) {
+				return m.selectAction(item.Action)
+			}
+		}
 		switch msg.String() {
 		case "ctrl+c", "esc":
 			m.cancel = true
 			return m, tea.Quit
-		case "ctrl+b", "ctrl+w":
-			return m.selectAction(tmuxActionBack)
-		case "ctrl+s":
-			return m.selectAction(tmuxActionShellSplit)
-		case "ctrl+t":
-			return m.selectAction(tmuxActionShellTab)
-		case "ctrl+n":
-			return m.selectAction(tmuxActionShellWindow)
-		case "ctrl+l":
-			return m.selectAction(tmuxActionIDE)
-		case "ctrl+p":
-			return m.selectAction(tmuxActionPR)
-		case "ctrl+r":
-			return m.selectAction(tmuxActionRename)
 		case "backspace":
 			if m.query != "" {
 				_, size := utf8.DecodeLastRuneInString(m.query)
@@ -167,22 +237,25 @@ func (m tmuxActionsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m tmuxActionsModel) View() string {
+	if m.showHelp {
+		return renderHelpOverlay(m.keys, helpSectionPopup)
+	}
 	var b strings.Builder
-	selectedStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#7D56F4"))
-	normalStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("251"))
-	disabledStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
-	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
 
+	if m.header != "" {
+		b.WriteString(actionNormalStyle.Render(m.header))
+		b.WriteString("\n")
+	}
 	queryLine := "/" + m.query
 	if strings.TrimSpace(m.query) == "" {
 		queryLine = "/command"
 	}
-	b.WriteString(dimStyle.Render(queryLine))
+	b.WriteString(secondaryStyle.Render(queryLine))
 	b.WriteString("\n")
-	b.WriteString(dimStyle.Render("────────────────────────────────────"))
+	b.WriteString(secondaryStyle.Render("────────────────────────────────────"))
 	b.WriteString("\n")
 	if len(m.filtered) == 0 {
-		b.WriteString(disabledStyle.Render("No matching actions"))
+		b.WriteString(selectorDisabledStyle.Render("No matching actions"))
 		b.WriteString("\n")
 	}
 	for listIndex, itemIndex := range m.filtered {
@@ -193,19 +266,19 @@ func (m tmuxActionsModel) View() string {
 		}
 		switch {
 		case item.Disabled:
-			b.WriteString(disabledStyle.Render(row))
+			b.WriteString(selectorDisabledStyle.Render(row))
 		case listIndex == m.index:
-			b.WriteString(selectedStyle.Render(row))
+			b.WriteString(selectorSelectedStyle.Render(row))
 		default:
-			b.WriteString(normalStyle.Render(row))
+			b.WriteString(actionNormalStyle.Render(row))
 		}
 		b.WriteString("\n")
 	}
 	b.WriteString("\n")
-	b.WriteString(dimStyle.Render("enter run • ↑/↓ navigate • esc cancel"))
+	b.WriteString(secondaryStyle.Render("enter run • ↑/↓ navigate • ? help • esc cancel"))
 	if m.updateHint != "" {
 		b.WriteString("\n")
-		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(m.updateHint))
+		b.WriteString(updateHintStyle.Render(m.updateHint))
 	}
 	return b.String()
 }
@@ -316,6 +389,9 @@ func runTmuxActions(args []string) error {
 		forcedAction = parseTmuxAction(positional[1])
 	}
 	basePath = normalizeTmuxActionBasePathCandidate(basePath)
+	if !tmuxAvailable() {
+		return runStandaloneActions(basePath, forcedAction, renameTo)
+	}
 	if basePath == "" {
 		basePath = resolveTmuxActionsBasePathFromPane(sourcePane)
 	}
@@ -330,11 +406,10 @@ func runTmuxActions(args []string) error {
 		return runTmuxAction(basePath, sourcePane, forcedAction, renameTo)
 	}
 
-	canOpenITermTab := canOpenShellInITermTab()
+	canOpenITermTab := canOpenShellTab()
 	canOpenWindow := canOpenShellWindow()
 	prAvailable := hasCurrentPRFromStatusSummary(basePath)
-	program := tea.NewProgram(newTmuxActionsModel(basePath, prAvailable, canOpenITermTab, canOpenWindow))
-	finalModel, err := program.Run()
+	finalModel, err := runProgram(newTmuxActionsModel(basePath, prAvailable, canOpenITermTab, canOpenWindow))
 	if err != nil {
 		return err
 	}
@@ -345,6 +420,38 @@ func runTmuxActions(args []string) error {
 	return runTmuxAction(basePath, sourcePane, m.chosen, m.renameTo)
 }
 
+// runStandaloneActions shows the palette as a full-screen program when there
+// is no tmux to host a popup. The worktree defaults to the one containing the
+// current directory.
+func runStandaloneActions(basePath string, forcedAction tmuxAction, renameTo string) error {
+	if basePath == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		if basePath, err = core.RepoRootForDir(cwd, ""); err != nil {
+			return err
+		}
+	}
+	if tmuxOnlyAction(forcedAction) {
+		return fmt.Errorf("%s requires tmux", forcedAction)
+	}
+	if forcedAction != "" {
+		return runTmuxAction(basePath, "", forcedAction, renameTo)
+	}
+
+	model := newTmuxActionsModel(basePath, hasCurrentPRFromStatusSummary(basePath), canOpenShellTab(), canOpenShellWindow()).withoutTmux()
+	finalModel, err := runProgram(model, tea.WithAltScreen())
+	if err != nil {
+		return err
+	}
+	m := finalModel.(tmuxActionsModel)
+	if m.cancel || m.chosen == "" {
+		return nil
+	}
+	return runTmuxAction(basePath, "", m.chosen, m.renameTo)
+}
+
 func parseTmuxAction(value string) tmuxAction {
 	switch strings.TrimSpace(strings.ToLower(value)) {
 	case string(tmuxActionBack):
@@ -361,21 +468,48 @@ func parseTmuxAction(value string) tmuxAction {
 		return tmuxActionPR
 	case string(tmuxActionRename):
 		return tmuxActionRename
+	case string(tmuxActionHydrateLFS):
+		return tmuxActionHydrateLFS
+	case string(tmuxActionUpdateBase):
+		return tmuxActionUpdateBase
+	case string(tmuxActionGitUI):
+		return tmuxActionGitUI
+	case string(tmuxActionReveal):
+		return tmuxActionReveal
+	case string(tmuxActionKillAgent):
+		return tmuxActionKillAgent
+	case string(tmuxActionRestart):
+		return tmuxActionRestart
+	case string(tmuxActionCopyPath):
+		return tmuxActionCopyPath
+	case string(tmuxActionCopyBranch):
+		return tmuxActionCopyBranch
+	case string(tmuxActionCopyPRURL):
+		return tmuxActionCopyPRURL
 	default:
+		if alias, ok := customActionAlias(tmuxAction(strings.TrimSpace(strings.ToLower(value)))); ok && alias != "" {
+			return customTmuxAction(alias)
+		}
 		return ""
 	}
 }
 
 func runTmuxAction(basePath string, sourcePane string, action tmuxAction, renameTo string) error {
+	recordPopupActionUse(action, time.Now())
 	switch action {
 	case tmuxActionBack:
 		return returnToWTX(basePath, sourcePane)
 	case tmuxActionShellSplit:
-		cmd := exec.Command("tmux", "split-window", "-v", "-p", "50", "-c", basePath)
-		return cmd.Run()
+		return core.TmuxRun("split-window", "-v", "-p", "50", "-c", tmuxFormatEscape(basePath))
 	case tmuxActionShellTab:
+		if term := linuxTerminal(); term != "" {
+			return openShellInLinuxTerminal(term, basePath, true)
+		}
 		return openShellInITermTab(basePath)
 	case tmuxActionShellWindow:
+		if term := linuxTerminal(); term != "" {
+			return openShellInLinuxTerminal(term, basePath, false)
+		}
 		if isITermTerminal(resolveSessionParentTerminalProgram()) {
 			return openShellInITermWindow(basePath)
 		}
@@ -384,15 +518,16 @@ func runTmuxAction(basePath string, sourcePane string, action tmuxAction, rename
 		clearPopupScreen()
 		return runIDEPicker([]string{basePath})
 	case tmuxActionPR:
-		cmd := exec.Command("gh", "pr", "view", "--web")
-		cmd.Dir = basePath
-		out, err := cmd.CombinedOutput()
+		out, err := core.RunExternalCommand(context.Background(), core.CommandSpec{Dir: basePath, Name: "gh", Args: []string{"pr", "view", "--web"}, Timeout: core.GHCommandTimeout})
 		if err != nil {
 			msg := commandErrorMessage(err, out)
 			if isNoPRForCurrentBranchMessage(msg) {
-				fallback := exec.Command("gh", "pr", "list", "--state", "open", "--author", "@me", "--web")
-				fallback.Dir = basePath
-				fallbackOut, fallbackErr := fallback.CombinedOutput()
+				fallbackOut, fallbackErr := core.RunExternalCommand(context.Background(), core.CommandSpec{
+					Dir:     basePath,
+					Name:    "gh",
+					Args:    []string{"pr", "list", "--state", "open", "--author", "@me", "--web"},
+					Timeout: core.GHCommandTimeout,
+				})
 				if fallbackErr == nil {
 					return nil
 				}
@@ -410,11 +545,90 @@ func runTmuxAction(basePath string, sourcePane string, action tmuxAction, rename
 			return renameCurrentBranch(basePath, renameTo)
 		}
 		return runRenameBranchPopup(basePath)
+	case tmuxActionHydrateLFS:
+		clearPopupScreen()
+		if err := core.HydrateLFS(basePath, os.Stdout); err != nil {
+			if showTmuxActionErrorMessage(err.Error()) {
+				return nil
+			}
+			return err
+		}
+		return nil
+	case tmuxActionUpdateBase:
+		clearPopupScreen()
+		return updateFromBase(basePath)
+	case tmuxActionKillAgent, tmuxActionRestart:
+		run := killAgent
+		if action == tmuxActionRestart {
+			run = restartAgent
+		}
+		if err := run(basePath, sourcePane); err != nil {
+			if showTmuxActionErrorMessage(err.Error()) {
+				return nil
+			}
+			return err
+		}
+		refreshTmuxStatusNow()
+		return nil
+	case tmuxActionReveal:
+		if err := revealInFileManager(basePath); err != nil {
+			if showTmuxActionErrorMessage(err.Error()) {
+				return nil
+			}
+			return err
+		}
+		return nil
+	case tmuxActionCopyPath, tmuxActionCopyBranch, tmuxActionCopyPRURL:
+		what := map[tmuxAction]string{tmuxActionCopyPath: copyPath, tmuxActionCopyBranch: copyBranch, tmuxActionCopyPRURL: copyPRURL}[action]
+		if err := copyFromPopup(basePath, what); err != nil {
+			if showTmuxActionErrorMessage(err.Error()) {
+				return nil
+			}
+			return err
+		}
+		return nil
+	case tmuxActionGitUI:
+		if err := openGitUI(basePath); err != nil {
+			if showTmuxActionErrorMessage(err.Error()) {
+				return nil
+			}
+			return err
+		}
+		return nil
 	default:
+		if alias, ok := customActionAlias(action); ok {
+			if err := runCustomAction(basePath, alias); err != nil {
+				if showTmuxActionErrorMessage(err.Error()) {
+					return nil
+				}
+				return err
+			}
+		}
 		return nil
 	}
 }
 
+// updateFromBase runs the update for the popup. On conflicts the worktree
+// stays mid-rebase and, under tmux, a shell opens next to the agent to
+// resolve them.
+func updateFromBase(basePath string) error {
+	_, err := core.NewWorktreeManager(basePath, core.NewLockManager()).UpdateFromBase(basePath, os.Stdout)
+	go refreshTmuxStatusNow()
+	if err == nil {
+		return nil
+	}
+	var conflict *core.UpdateConflictError
+	if errors.As(err, &conflict) && tmuxAvailable() {
+		if splitErr := core.TmuxRun("split-window", "-v", "-p", "50", "-c", tmuxFormatEscape(basePath)); splitErr != nil {
+			return splitErr
+		}
+	}
+	if showTmuxActionErrorMessage(err.Error()) {
+		return nil
+	}
+	return err
+}
+
 func renameCurrentBranch(basePath string, renameTo string) error {
 	basePath = strings.TrimSpace(basePath)
 	if basePath == "" {
@@ -425,12 +639,10 @@ func renameCurrentBranch(basePath string, renameTo string) error {
 		return fmt.Errorf("branch name required")
 	}
 	timeout := renameCurrentBranchTimeout
+	previous := core.CurrentBranchInWorktree(basePath)
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
-	cmd := exec.CommandContext(ctx, "git", "branch", "-m", renameTo)
-	cmd.Dir = basePath
-	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
-	out, err := cmd.CombinedOutput()
+	out, err := core.RunExternalCommand(ctx, core.CommandSpec{Dir: basePath, Name: "git", Args: []string{"branch", "-m", renameTo}})
 	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
 		msg := strings.TrimSpace(string(out))
 		if msg != "" {
@@ -445,6 +657,9 @@ func renameCurrentBranch(basePath string, renameTo string) error {
 		}
 		return err
 	}
+	if _, repoRoot, err := core.RequireGitContext(basePath); err == nil {
+		core.RecordAction(core.AuditRename, repoRoot, basePath, renameTo, map[string]string{"from": previous})
+	}
 	go refreshTmuxStatusNow()
 	return nil
 }
@@ -453,7 +668,7 @@ func runRenameBranchPopup(basePath string) error {
 	errMsg := ""
 	branch := ""
 	for {
-		model, err := tea.NewProgram(newRenameBranchModel(branch, errMsg)).Run()
+		model, err := runProgram(newRenameBranchModel(branch, errMsg))
 		if err != nil {
 			return err
 		}
@@ -519,20 +734,17 @@ func (m renameBranchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m renameBranchModel) View() string {
-	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#7D56F4"))
-	errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("203"))
-	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
 
 	var b strings.Builder
 	b.WriteString(titleStyle.Render("Rename branch to"))
 	b.WriteString("\n")
 	if m.errMsg != "" {
-		b.WriteString(errStyle.Render(m.errMsg))
+		b.WriteString(errorStyle.Render(m.errMsg))
 		b.WriteString("\n")
 	}
 	b.WriteString(m.input.View())
 	b.WriteString("\n")
-	b.WriteString(dimStyle.Render("enter submit • esc cancel"))
+	b.WriteString(secondaryStyle.Render("enter submit • esc cancel"))
 	return b.String()
 }
 
@@ -542,18 +754,15 @@ func refreshTmuxStatusNow() {
 	}
 
 	queryWithTimeout := func(args ...string) (string, error) {
-		ctx, cancel := context.WithTimeout(context.Background(), tmuxStatusRefreshTimeout)
-		defer cancel()
-		out, err := exec.CommandContext(ctx, "tmux", args...).Output()
+		out, err := core.RunExternalCommand(context.Background(), core.CommandSpec{Name: "tmux", Args: args, Timeout: tmuxStatusRefreshTimeout, StdoutOnly: true})
 		if err != nil {
 			return "", err
 		}
 		return strings.TrimSpace(string(out)), nil
 	}
 	runWithTimeout := func(args ...string) error {
-		ctx, cancel := context.WithTimeout(context.Background(), tmuxStatusRefreshTimeout)
-		defer cancel()
-		return exec.CommandContext(ctx, "tmux", args...).Run()
+		_, err := core.RunExternalCommand(context.Background(), core.CommandSpec{Name: "tmux", Args: args, Timeout: tmuxStatusRefreshTimeout})
+		return err
 	}
 
 	sessionID, err := queryWithTimeout("display-message", "-p", "#{session_id}")
@@ -573,15 +782,7 @@ func returnToWTX(basePath string, sourcePane string) error {
 	// Force unlock in "return to WTX" flows so stale pane ownership never blocks reuse.
 	_ = runTmuxAgentExit([]string{"--worktree", basePath, "--code", "130", "--force-unlock"})
 
-	paneID := strings.TrimSpace(sourcePane)
-	if paneID == "" {
-		paneID = strings.TrimSpace(os.Getenv("TMUX_PANE"))
-	}
-	if paneID == "" {
-		if current, err := currentPaneID(); err == nil {
-			paneID = strings.TrimSpace(current)
-		}
-	}
+	paneID := popupSourcePane(sourcePane)
 	if paneID == "" {
 		return fmt.Errorf("unable to resolve active tmux pane")
 	}
@@ -594,12 +795,27 @@ func returnToWTX(basePath string, sourcePane string) error {
 			bin = "wtx"
 		}
 	}
-	command := "exec " + shellQuote(bin)
-	if err := exec.Command("tmux", "respawn-pane", "-k", "-c", basePath, "-t", paneID, command).Run(); err == nil {
+	command := "exec " + core.ShellQuote(bin)
+	if err := core.TmuxRun("respawn-pane", "-k", "-c", tmuxFormatEscape(basePath), "-t", paneID, command); err == nil {
 		return nil
 	}
 	// Fallback to tmux "last active pane" target, which is reliable from popup contexts.
-	return exec.Command("tmux", "respawn-pane", "-k", "-c", basePath, "-t", "!", command).Run()
+	return core.TmuxRun("respawn-pane", "-k", "-c", tmuxFormatEscape(basePath), "-t", "!", command)
+}
+
+// popupSourcePane is the pane the popup was opened from, falling back to
+// the current pane.
+func popupSourcePane(sourcePane string) string {
+	paneID := strings.TrimSpace(sourcePane)
+	if paneID == "" {
+		paneID = strings.TrimSpace(os.Getenv("TMUX_PANE"))
+	}
+	if paneID == "" {
+		if current, err := currentPaneID(); err == nil {
+			paneID = strings.TrimSpace(current)
+		}
+	}
+	return paneID
 }
 
 func hasCurrentPRFromStatusSummary(path string) bool {
@@ -607,7 +823,7 @@ func hasCurrentPRFromStatusSummary(path string) bool {
 	if path == "" {
 		return false
 	}
-	branch := currentBranchInWorktree(path)
+	branch := core.CurrentBranchInWorktree(path)
 	if branch == "" {
 		return false
 	}
@@ -619,8 +835,15 @@ func prSummaryHasNumber(summary string) bool {
 	return prSummaryLabelRe.MatchString(strings.TrimSpace(summary))
 }
 
+func canOpenShellTab() bool {
+	if term := linuxTerminal(); term != "" {
+		return linuxTerminalSupportsTabs(term)
+	}
+	return canOpenShellInITermTab()
+}
+
 func canOpenShellInITermTab() bool {
-	if iTermIntegrationDisabled() {
+	if core.ITermIntegrationDisabled() {
 		return false
 	}
 	if !isITermTerminal(resolveSessionParentTerminalProgram()) {
@@ -651,8 +874,7 @@ on run argv
 	end tell
 end run
 `
-	cmd := exec.Command("osascript", "-e", script, "--", path)
-	return cmd.Run()
+	return core.OsascriptRun("-e", script, "--", path)
 }
 
 func openShellInITermWindow(path string) error {
@@ -672,8 +894,7 @@ on run argv
 	end tell
 end run
 `
-	cmd := exec.Command("osascript", "-e", script, "--", path)
-	return cmd.Run()
+	return core.OsascriptRun("-e", script, "--", path)
 }
 
 func openShellInTerminalWindow(path string) error {
@@ -690,8 +911,7 @@ on run argv
 	end tell
 end run
 `
-	cmd := exec.Command("osascript", "-e", script, "--", path)
-	return cmd.Run()
+	return core.OsascriptRun("-e", script, "--", path)
 }
 
 func clearPopupScreen() {
@@ -713,9 +933,8 @@ func showTmuxActionErrorMessage(message string) bool {
 	if message == "" || strings.TrimSpace(os.Getenv("TMUX")) == "" {
 		return false
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), tmuxStatusRefreshTimeout)
-	defer cancel()
-	return exec.CommandContext(ctx, "tmux", "display-message", "-d", "5000", message).Run() == nil
+	_, err := core.RunExternalCommand(context.Background(), core.CommandSpec{Name: "tmux", Args: []string{"display-message", "-d", "5000", tmuxFormatEscape(message)}, Timeout: tmuxStatusRefreshTimeout})
+	return err == nil
 }
 
 func normalizeTmuxDisplayMessage(message string) string {
@@ -738,19 +957,19 @@ func isNoPRForCurrentBranchMessage(message string) bool {
 }
 
 func tmuxActionsPopupCommand(wtxBin string) string {
-	return fmt.Sprintf("%s tmux-actions", shellQuote(wtxBin))
+	return fmt.Sprintf("%s tmux-actions", core.ShellQuote(wtxBin))
 }
 
 func tmuxActionsCommandWithAction(wtxBin string, action tmuxAction) string {
-	return fmt.Sprintf("%s tmux-actions %s", shellQuote(wtxBin), shellQuote(string(action)))
+	return fmt.Sprintf("%s tmux-actions %s", core.ShellQuote(wtxBin), core.ShellQuote(string(action)))
 }
 
 func tmuxActionsCommandWithPathAndAction(wtxBin string, path string, action tmuxAction) string {
-	return fmt.Sprintf("%s tmux-actions %s %s", shellQuote(wtxBin), shellQuote(path), shellQuote(string(action)))
+	return fmt.Sprintf("%s tmux-actions %s %s", core.ShellQuote(wtxBin), core.ShellQuote(path), core.ShellQuote(string(action)))
 }
 
 func tmuxActionsCommandWithSourcePane(wtxBin string, sourcePane string, action tmuxAction) string {
-	return fmt.Sprintf("%s tmux-actions --source-pane %s %s", shellQuote(wtxBin), shellQuote(sourcePane), shellQuote(string(action)))
+	return fmt.Sprintf("%s tmux-actions --source-pane %s %s", core.ShellQuote(wtxBin), core.ShellQuote(sourcePane), core.ShellQuote(string(action)))
 }
 
 func resolveTmuxActionsBasePathFromPane(sourcePane string) string {
@@ -758,7 +977,7 @@ func resolveTmuxActionsBasePathFromPane(sourcePane string) string {
 	if sourcePane == "" {
 		return ""
 	}
-	out, err := exec.Command("tmux", "display-message", "-p", "-t", sourcePane, "#{pane_current_path}").Output()
+	out, err := core.TmuxOutput("display-message", "-p", "-t", sourcePane, "#{pane_current_path}")
 	if err != nil {
 		return ""
 	}
@@ -768,16 +987,16 @@ func resolveTmuxActionsBasePathFromPane(sourcePane string) string {
 func resolveTmuxActionsBasePath() string {
 	envPath := strings.TrimSpace(os.Getenv("WTX_WORKTREE_PATH"))
 	optionPath := ""
-	if out, err := exec.Command("tmux", "display-message", "-p", "#{@wtx_worktree_path}").Output(); err == nil {
+	if out, err := core.TmuxOutput("display-message", "-p", "#{@wtx_worktree_path}"); err == nil {
 		optionPath = strings.TrimSpace(string(out))
 	}
 	sessionOptionPath := ""
 	sessionEnvPath := ""
-	if sessionID, err := currentSessionID(); err == nil && strings.TrimSpace(sessionID) != "" {
-		if out, err := exec.Command("tmux", "show-options", "-qv", "-t", sessionID, "@wtx_worktree_path").Output(); err == nil {
+	if sessionID, err := core.CurrentSessionID(); err == nil && strings.TrimSpace(sessionID) != "" {
+		if out, err := core.TmuxOutput("show-options", "-qv", "-t", sessionID, "@wtx_worktree_path"); err == nil {
 			sessionOptionPath = strings.TrimSpace(string(out))
 		}
-		if out, err := exec.Command("tmux", "show-environment", "-t", sessionID, "WTX_WORKTREE_PATH").Output(); err == nil {
+		if out, err := core.TmuxOutput("show-environment", "-t", sessionID, "WTX_WORKTREE_PATH"); err == nil {
 			line := strings.TrimSpace(string(out))
 			if strings.HasPrefix(line, "WTX_WORKTREE_PATH=") {
 				sessionEnvPath = strings.TrimSpace(strings.TrimPrefix(line, "WTX_WORKTREE_PATH="))
@@ -814,16 +1033,25 @@ func normalizeTmuxActionBasePathCandidate(path string) string {
 
 var prSummaryLabelRe = regexp.MustCompile(`\bPR\s+#\d+\b`)
 
-func sortTmuxActionItems(items []tmuxActionItem) {
+// sortTmuxActionItems puts available actions first, the most used ahead,
+// and falls back to the alias.
+func sortTmuxActionItems(items []tmuxActionItem, usage popupUsage) {
+	now := time.Now()
 	sort.SliceStable(items, func(i, j int) bool {
 		if items[i].Disabled != items[j].Disabled {
 			return !items[i].Disabled
 		}
+		if si, sj := usage.score(items[i].Action, now), usage.score(items[j].Action, now); si != sj {
+			return si > sj
+		}
 		return strings.ToLower(items[i].Alias) < strings.ToLower(items[j].Alias)
 	})
 }
 
 func terminalProgramLabel() string {
+	if term := linuxTerminal(); term != "" {
+		return linuxTerminalLabel(term)
+	}
 	term := resolveSessionParentTerminalProgram()
 	if isITermTerminal(term) {
 		return "iTerm"
@@ -839,6 +1067,9 @@ func terminalProgramLabel() string {
 }
 
 func terminalWindowProgramLabel() string {
+	if term := linuxTerminal(); term != "" {
+		return linuxTerminalLabel(term)
+	}
 	if isITermTerminal(resolveSessionParentTerminalProgram()) {
 		return "iTerm"
 	}
@@ -849,13 +1080,13 @@ func resolveSessionParentTerminalProgram() string {
 	if term := strings.TrimSpace(os.Getenv("WTX_PARENT_TERMINAL")); term != "" {
 		return term
 	}
-	if sessionID, err := currentSessionID(); err == nil && strings.TrimSpace(sessionID) != "" {
-		if out, err := exec.Command("tmux", "show-options", "-qv", "-t", sessionID, "@wtx_parent_terminal").Output(); err == nil {
+	if sessionID, err := core.CurrentSessionID(); err == nil && strings.TrimSpace(sessionID) != "" {
+		if out, err := core.TmuxOutput("show-options", "-qv", "-t", sessionID, "@wtx_parent_terminal"); err == nil {
 			if term := strings.TrimSpace(string(out)); term != "" {
 				return term
 			}
 		}
-		if out, err := exec.Command("tmux", "show-environment", "-t", sessionID, "WTX_PARENT_TERMINAL").Output(); err == nil {
+		if out, err := core.TmuxOutput("show-environment", "-t", sessionID, "WTX_PARENT_TERMINAL"); err == nil {
 			line := strings.TrimSpace(string(out))
 			if strings.HasPrefix(line, "WTX_PARENT_TERMINAL=") {
 				if term := strings.TrimSpace(strings.TrimPrefix(line, "WTX_PARENT_TERMINAL=")); term != "" {
@@ -885,19 +1116,20 @@ func canControlITerm() bool {
 	if _, err := exec.LookPath("osascript"); err != nil {
 		return false
 	}
-	cmd := exec.Command("osascript", "-e", `tell application "iTerm" to version`)
-	return cmd.Run() == nil
+	return core.OsascriptRun("-e", `tell application "iTerm" to version`) == nil
 }
 
 func canControlTerminal() bool {
 	if _, err := exec.LookPath("osascript"); err != nil {
 		return false
 	}
-	cmd := exec.Command("osascript", "-e", `tell application "Terminal" to version`)
-	return cmd.Run() == nil
+	return core.OsascriptRun("-e", `tell application "Terminal" to version`) == nil
 }
 
 func canOpenShellWindow() bool {
+	if linuxTerminal() != "" {
+		return true
+	}
 	if isITermTerminal(resolveSessionParentTerminalProgram()) {
 		return canOpenShellInITermTab()
 	}
@@ -142,7 +142,7 @@ func TestTmuxActionsModel_ViewShowsShortcutHints(t *testing.T) {
 	if !strings.Contains(view, "ctrl+r") {
 		t.Fatalf("expected ctrl+r hint in view rows, got %q", view)
 	}
-	if !strings.Contains(view, "enter run • ↑/↓ navigate • esc cancel") {
+	if !strings.Contains(view, "enter run • ↑/↓ navigate • ? help • esc cancel") {
 		t.Fatalf("expected minimal footer hint, got %q", view)
 	}
 }
@@ -249,7 +249,7 @@ func TestSortTmuxActionItems_UnavailableLastThenAlias(t *testing.T) {
 		{Alias: "alpha", Disabled: false},
 		{Alias: "gamma", Disabled: true},
 	}
-	sortTmuxActionItems(items)
+	sortTmuxActionItems(items, nil)
 
 	got := []string{
 		items[0].Alias,
@@ -309,7 +309,6 @@ func TestTmuxActionsCommandWithAction_InjectsSourcePane(t *testing.T) {
 	}
 }
 
-
 func TestTmuxActionsCommandWithSourcePane(t *testing.T) {
 	got := tmuxActionsCommandWithSourcePane("/usr/local/bin/wtx", "%12", tmuxActionIDE)
 	if want := "--source-pane"; !strings.Contains(got, want) {
@@ -489,3 +488,17 @@ func TestResolveTmuxActionsBasePathFromCandidates(t *testing.T) {
 		}
 	})
 }
+
+func TestTmuxActionsModel_WithoutTmuxDisablesPaneActions(t *testing.T) {
+	m := newTmuxActionsModel("/tmp", true, false, false).withoutTmux()
+	for _, item := range m.items {
+		unavailable := item.Action == tmuxActionShellTab || item.Action == tmuxActionShellWindow || (item.Action == tmuxActionGitUI && gitUICommand("/tmp") == "") || (item.Action == tmuxActionReveal && fileManagerOpener() == "")
+		if item.Disabled != (tmuxOnlyAction(item.Action) || unavailable) {
+			t.Fatalf("unexpected disabled=%v for %s", item.Disabled, item.Action)
+		}
+	}
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlB})
+	if updated := updatedModel.(tmuxActionsModel); updated.chosen != "" {
+		t.Fatalf("expected ctrl+b to be ignored outside tmux, got %q", updated.chosen)
+	}
+}
@@ -11,14 +11,14 @@ import (
 	"strings"
 	"time"
 
-	"github.com/charmbracelet/lipgloss"
+	"github.com/aixolotls/wtx/internal/core"
 )
 
 const tmuxStatusIntervalSeconds = "10"
 const tmuxStatusRightHint = " ^A actions | ^W back#{?#{>:#{window_panes},1}, | ⌥⇧↑/⌥⇧↓ resize,} "
 
 func ensureFreshTmuxSession(args []string) (bool, error) {
-	if tmuxIntegrationDisabled() {
+	if core.TmuxIntegrationDisabled() {
 		return false, nil
 	}
 	if _, err := exec.LookPath("tmux"); err != nil {
@@ -47,13 +47,12 @@ func ensureFreshTmuxSession(args []string) (bool, error) {
 		"new-session", "-d",
 		"-e", "WTX_STATUS_BIN=" + bin,
 		"-e", "WTX_PARENT_TERMINAL=" + parentTerminal,
-		"-s", session, "-c", cwd,
+		"-s", session, "-c", tmuxFormatEscape(cwd),
 	}
-	if configDir := strings.TrimSpace(os.Getenv(configDirOverrideEnv)); configDir != "" {
-		tmuxArgs = append(tmuxArgs, "-e", configDirOverrideEnv+"="+configDir)
+	if configDir := strings.TrimSpace(os.Getenv(core.ConfigDirOverrideEnv)); configDir != "" {
+		tmuxArgs = append(tmuxArgs, "-e", core.ConfigDirOverrideEnv+"="+configDir)
 	}
-	cmd := exec.Command("tmux", tmuxArgs...)
-	out, err := cmd.CombinedOutput()
+	out, err := core.TmuxCombinedOutput(tmuxArgs...)
 	if err != nil {
 		msg := strings.TrimSpace(string(out))
 		if msg != "" {
@@ -70,7 +69,7 @@ func ensureFreshTmuxSession(args []string) (bool, error) {
 		if err := launchCommandInSession(session, bin, args[1:]); err != nil {
 			return false, err
 		}
-		if err := exec.Command("tmux", "switch-client", "-t", session).Run(); err != nil {
+		if err := core.TmuxRun("switch-client", "-t", session); err != nil {
 			return false, err
 		}
 		// Re-apply after client switch so mouse/table bindings are set in attached context.
@@ -108,16 +107,16 @@ func applyStartupThemeToSession(sessionID string, cwd string, parentTerminal str
 	// Session is detached at startup; avoid destroy-unattached here.
 	applyWTXSessionDefaults(sessionID, false)
 	if cwd != "" {
-		_ = exec.Command("tmux", "set-environment", "-t", sessionID, "WTX_WORKTREE_PATH", cwd).Run()
+		_ = core.TmuxRun("set-environment", "-t", sessionID, "WTX_WORKTREE_PATH", cwd)
 		tmuxSetOption(sessionID, "@wtx_worktree_path", cwd)
 	}
 	parentTerminal = strings.TrimSpace(parentTerminal)
 	if parentTerminal != "" {
-		_ = exec.Command("tmux", "set-environment", "-t", sessionID, "WTX_PARENT_TERMINAL", parentTerminal).Run()
+		_ = core.TmuxRun("set-environment", "-t", sessionID, "WTX_PARENT_TERMINAL", parentTerminal)
 		tmuxSetOption(sessionID, "@wtx_parent_terminal", parentTerminal)
 	}
 	configureTmuxStatus(sessionID, "200", tmuxStatusIntervalSeconds)
-	tmuxSetOption(sessionID, "status-left", " "+banner+" ")
+	tmuxSetOption(sessionID, "status-left", " "+tmuxFormatEscape(banner)+" ")
 }
 
 func launchCommandInSession(sessionID string, bin string, args []string) error {
@@ -126,12 +125,12 @@ func launchCommandInSession(sessionID string, bin string, args []string) error {
 	if sessionID == "" || bin == "" {
 		return fmt.Errorf("session and binary are required")
 	}
-	command := shellQuote(bin)
+	command := core.ShellQuote(bin)
 	for _, arg := range args {
-		command += " " + shellQuote(arg)
+		command += " " + core.ShellQuote(arg)
 	}
 	// Run directly in the pane so the command isn't visibly typed into the shell.
-	return exec.Command("tmux", "respawn-pane", "-k", "-t", sessionID+":0.0", command).Run()
+	return core.TmuxRun("respawn-pane", "-k", "-t", sessionID+":0.0", command)
 }
 
 func launchCommandInSessionAfterSignal(sessionID string, bin string, args []string, signal string) error {
@@ -141,12 +140,12 @@ func launchCommandInSessionAfterSignal(sessionID string, bin string, args []stri
 	if sessionID == "" || bin == "" || signal == "" {
 		return fmt.Errorf("session, binary, and signal are required")
 	}
-	command := shellQuote(bin)
+	command := core.ShellQuote(bin)
 	for _, arg := range args {
-		command += " " + shellQuote(arg)
+		command += " " + core.ShellQuote(arg)
 	}
-	waitCommand := "tmux wait-for " + shellQuote(signal) + "; exec " + command
-	return exec.Command("tmux", "respawn-pane", "-k", "-t", sessionID+":0.0", "/bin/sh", "-lc", waitCommand).Run()
+	waitCommand := "tmux wait-for " + core.ShellQuote(signal) + "; exec " + command
+	return core.TmuxRun("respawn-pane", "-k", "-t", sessionID+":0.0", "/bin/sh", "-lc", waitCommand)
 }
 
 func tmuxSignal(signal string) error {
@@ -154,7 +153,7 @@ func tmuxSignal(signal string) error {
 	if signal == "" {
 		return nil
 	}
-	return exec.Command("tmux", "wait-for", "-S", signal).Run()
+	return core.TmuxRun("wait-for", "-S", signal)
 }
 
 func resolveSelfBinary(args []string) (string, error) {
@@ -180,7 +179,7 @@ func resolveSelfBinary(args []string) (string, error) {
 }
 
 func setStartupStatusBanner() {
-	if tmuxIntegrationDisabled() {
+	if core.TmuxIntegrationDisabled() {
 		return
 	}
 	if strings.TrimSpace(os.Getenv("TMUX")) == "" {
@@ -195,16 +194,73 @@ func setStartupStatusBanner() {
 }
 
 func splitCommandPane(worktreePath string, runCmd string) (string, error) {
-	cmd := exec.Command("tmux", "split-window", "-v", "-p", "70", "-d", "-c", worktreePath, "-P", "-F", "#{pane_id}", "/bin/sh", "-lc", runCmd)
-	out, err := cmd.Output()
+	out, err := core.TmuxOutput("split-window", "-v", "-p", "70", "-d", "-c", tmuxFormatEscape(worktreePath), "-P", "-F", "#{pane_id}", "/bin/sh", "-lc", runCmd)
 	if err != nil {
 		return "", err
 	}
 	return strings.TrimSpace(string(out)), nil
 }
 
+func newCommandWindow(worktreePath string, name string, runCmd string) (string, error) {
+	out, err := core.TmuxOutput("new-window", "-d", "-n", name, "-c", tmuxFormatEscape(worktreePath), "-P", "-F", "#{pane_id}", "/bin/sh", "-lc", runCmd)
+	if err != nil {
+		return "", err
+	}
+	paneID := strings.TrimSpace(string(out))
+	tmuxSetWindowOption(paneID, "@wtx_worktree_path", worktreePath)
+	return paneID, nil
+}
+
+// paneOwnerID builds the tmux lock owner for the window that holds paneID.
+func paneOwnerID(paneID string) (string, error) {
+	out, err := core.TmuxOutput("display-message", "-p", "-t", paneID, "#{session_id}:#{window_id}")
+	if err != nil {
+		return "", err
+	}
+	value := strings.TrimSpace(string(out))
+	if value == "" || !strings.Contains(value, ":") {
+		return "", fmt.Errorf("tmux window not found for pane %s", paneID)
+	}
+	return "tmux:" + value, nil
+}
+
+// openWorktreeWindow starts a fresh wtx tmux session for the first worktree
+// and adds a window per worktree after that.
+func openWorktreeWindow(sessionID string, worktreePath string, name string, runCmd string) (string, string, error) {
+	var args []string
+	if strings.TrimSpace(sessionID) == "" {
+		sessionID = fmt.Sprintf("wtx-%d", time.Now().UnixNano())
+		args = []string{"new-session", "-d", "-s", sessionID, "-n", name, "-c", tmuxFormatEscape(worktreePath), "-P", "-F", "#{pane_id}"}
+		if configDir := strings.TrimSpace(os.Getenv(core.ConfigDirOverrideEnv)); configDir != "" {
+			args = append(args, "-e", core.ConfigDirOverrideEnv+"="+configDir)
+		}
+	} else {
+		args = []string{"new-window", "-d", "-t", sessionID + ":", "-n", name, "-c", tmuxFormatEscape(worktreePath), "-P", "-F", "#{pane_id}"}
+	}
+	args = append(args, "/bin/sh", "-lc", runCmd)
+	out, err := core.TmuxCombinedOutput(args...)
+	if err != nil {
+		return "", "", core.CommandErrorWithOutput(err, out)
+	}
+	return strings.TrimSpace(string(out)), sessionID, nil
+}
+
+// enterTmuxSession applies wtx defaults to sessionID and moves the terminal
+// into it, switching clients when already inside tmux.
+func enterTmuxSession(sessionID string, inTmux bool) error {
+	applyWTXSessionDefaults(sessionID, false)
+	if inTmux {
+		return core.TmuxRun("switch-client", "-t", sessionID)
+	}
+	attach := exec.Command("tmux", "attach-session", "-t", sessionID)
+	attach.Stdin = os.Stdin
+	attach.Stdout = os.Stdout
+	attach.Stderr = os.Stderr
+	return attach.Run()
+}
+
 func tmuxAvailable() bool {
-	if tmuxIntegrationDisabled() {
+	if core.TmuxIntegrationDisabled() {
 		return false
 	}
 	if strings.TrimSpace(os.Getenv("TMUX")) == "" {
@@ -215,7 +271,7 @@ func tmuxAvailable() bool {
 }
 
 func currentPaneID() (string, error) {
-	out, err := exec.Command("tmux", "display-message", "-p", "#{pane_id}").Output()
+	out, err := core.TmuxOutput("display-message", "-p", "#{pane_id}")
 	if err != nil {
 		return "", err
 	}
@@ -223,7 +279,7 @@ func currentPaneID() (string, error) {
 }
 
 func panePID(paneID string) (int, error) {
-	out, err := exec.Command("tmux", "display-message", "-p", "-t", paneID, "#{pane_pid}").Output()
+	out, err := core.TmuxOutput("display-message", "-p", "-t", paneID, "#{pane_pid}")
 	if err != nil {
 		return 0, err
 	}
@@ -238,22 +294,6 @@ func panePID(paneID string) (int, error) {
 	return pid, nil
 }
 
-func currentSessionID() (string, error) {
-	out, err := exec.Command("tmux", "display-message", "-p", "#{session_id}").Output()
-	if err != nil {
-		return "", err
-	}
-	return strings.TrimSpace(string(out)), nil
-}
-
-func currentWindowID() (string, error) {
-	out, err := exec.Command("tmux", "display-message", "-p", "#{window_id}").Output()
-	if err != nil {
-		return "", err
-	}
-	return strings.TrimSpace(string(out)), nil
-}
-
 func renderBanner(branch string, path string, ghSummary string) string {
 	label := "WTX"
 	if branch != "" {
@@ -265,40 +305,35 @@ func renderBanner(branch string, path string, ghSummary string) string {
 	if strings.TrimSpace(ghSummary) != "" {
 		label = label + "  " + strings.TrimSpace(ghSummary)
 	}
-	style := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("#FFF7DB")).
-		Background(lipgloss.Color("#7D56F4")).
-		Padding(0, 1)
-	return style.Render(label)
+	return bannerStyle.Render(label)
 }
 
 func setStatusBanner(banner string) {
-	if tmuxIntegrationDisabled() {
+	if core.TmuxIntegrationDisabled() {
 		return
 	}
 	if strings.TrimSpace(banner) == "" {
 		return
 	}
 	banner = stripANSI(banner)
-	sessionID, err := currentSessionID()
+	sessionID, err := core.CurrentSessionID()
 	if err != nil || strings.TrimSpace(sessionID) == "" {
 		return
 	}
 	ensureWTXSessionDefaults()
 	configureTmuxStatus(sessionID, "200", tmuxStatusIntervalSeconds)
-	tmuxSetOption(sessionID, "status-left", " "+banner+" ")
+	tmuxSetOption(sessionID, "status-left", " "+tmuxFormatEscape(banner)+" ")
 }
 
 func setDynamicWorktreeStatus(worktreePath string) {
-	if tmuxIntegrationDisabled() {
+	if core.TmuxIntegrationDisabled() {
 		return
 	}
 	worktreePath = strings.TrimSpace(worktreePath)
 	if worktreePath == "" {
 		return
 	}
-	sessionID, err := currentSessionID()
+	sessionID, err := core.CurrentSessionID()
 	if err != nil || strings.TrimSpace(sessionID) == "" {
 		return
 	}
@@ -307,14 +342,14 @@ func setDynamicWorktreeStatus(worktreePath string) {
 	if strings.TrimSpace(bin) == "" {
 		return
 	}
-	cmd := "#(" + shellQuote(bin) + " tmux-status --worktree " + shellQuote(worktreePath) + ")"
+	cmd := "#(" + tmuxFormatEscape(core.ShellQuote(bin)+" tmux-status --worktree "+core.ShellQuote(worktreePath)) + ")"
 	configureTmuxStatus(sessionID, "300", tmuxStatusIntervalSeconds)
-	_ = exec.Command("tmux", "set-environment", "-t", sessionID, "WTX_WORKTREE_PATH", worktreePath).Run()
+	_ = core.TmuxRun("set-environment", "-t", sessionID, "WTX_WORKTREE_PATH", worktreePath)
 	tmuxSetOption(sessionID, "@wtx_worktree_path", worktreePath)
 	tmuxSetOption(sessionID, "status-left", " "+cmd+" ")
 	tmuxSetOption(sessionID, "status-right", " ^A actions | ^S split | ^P PR | ^L IDE#{?#{>:#{window_panes},1}, | ⌥↑/⌥↓ move | ⌥⇧↑/⌥⇧↓ resize,} ")
 	tmuxSetOption(sessionID, "status-right-length", "132")
-	titleCmd := "#(" + shellQuote(bin) + " tmux-title --worktree " + shellQuote(worktreePath) + ")"
+	titleCmd := "#(" + tmuxFormatEscape(core.ShellQuote(bin)+" tmux-title --worktree "+core.ShellQuote(worktreePath)) + ")"
 	tmuxSetOption(sessionID, "set-titles", "on")
 	tmuxSetOption(sessionID, "set-titles-string", titleCmd)
 	configureTmuxActionBindings(sessionID, resolveAgentLifecycleBinary())
@@ -322,7 +357,7 @@ func setDynamicWorktreeStatus(worktreePath string) {
 
 func clearScreen() {
 	if tmuxAvailable() {
-		_ = exec.Command("tmux", "clear-history").Run()
+		_ = core.TmuxRun("clear-history")
 	}
 	fmt.Fprint(os.Stdout, "\x1b[2J\x1b[H")
 }
@@ -346,18 +381,18 @@ func stripANSI(value string) string {
 	return string(out)
 }
 
-func shellQuote(value string) string {
-	if value == "" {
-		return "''"
-	}
-	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+// tmuxFormatEscape protects text tmux expands as a format, such as status
+// strings, start directories and #() commands, so a branch or path holding
+// "#" is shown and used literally.
+func tmuxFormatEscape(value string) string {
+	return strings.ReplaceAll(value, "#", "##")
 }
 
 func ensureWTXSessionDefaults() {
-	if tmuxIntegrationDisabled() {
+	if core.TmuxIntegrationDisabled() {
 		return
 	}
-	sessionID, err := currentSessionID()
+	sessionID, err := core.CurrentSessionID()
 	if err != nil || strings.TrimSpace(sessionID) == "" {
 		return
 	}
@@ -390,10 +425,10 @@ func applyWTXSessionDefaults(sessionID string, enableDestroyUnattached bool) {
 	clearLegacyTmuxInputBindings(keyTable)
 
 	// Only resize when split panes are present.
-	_ = exec.Command("tmux", "bind-key", "-r", "-T", keyTable, "M-Up", "if-shell", "-F", "#{>:#{window_panes},1}", "select-pane -U").Run()
-	_ = exec.Command("tmux", "bind-key", "-r", "-T", keyTable, "M-Down", "if-shell", "-F", "#{>:#{window_panes},1}", "select-pane -D").Run()
-	_ = exec.Command("tmux", "bind-key", "-r", "-T", keyTable, "M-S-Up", "if-shell", "-F", "#{>:#{window_panes},1}", "resize-pane -U 3").Run()
-	_ = exec.Command("tmux", "bind-key", "-r", "-T", keyTable, "M-S-Down", "if-shell", "-F", "#{>:#{window_panes},1}", "resize-pane -D 3").Run()
+	_ = core.TmuxRun("bind-key", "-r", "-T", keyTable, "M-Up", "if-shell", "-F", "#{>:#{window_panes},1}", "select-pane -U")
+	_ = core.TmuxRun("bind-key", "-r", "-T", keyTable, "M-Down", "if-shell", "-F", "#{>:#{window_panes},1}", "select-pane -D")
+	_ = core.TmuxRun("bind-key", "-r", "-T", keyTable, "M-S-Up", "if-shell", "-F", "#{>:#{window_panes},1}", "resize-pane -U 3")
+	_ = core.TmuxRun("bind-key", "-r", "-T", keyTable, "M-S-Down", "if-shell", "-F", "#{>:#{window_panes},1}", "resize-pane -D 3")
 	if !shouldDisableTmuxInputEnhancements(terminalProgram) {
 		// Preserve modified key chords (for example Shift+Enter in coding agents) inside wtx-managed tmux sessions.
 		tmuxSetWindowOption(sessionID, "xterm-keys", "on")
@@ -413,7 +448,7 @@ func clearLegacyTmuxInputBindings(keyTable string) {
 		tables = append(tables, keyTable)
 	}
 	for _, table := range tables {
-		_ = exec.Command("tmux", "unbind-key", "-T", table, "M-[").Run()
+		_ = core.TmuxRun("unbind-key", "-T", table, "M-[")
 	}
 }
 
@@ -433,7 +468,7 @@ func configureTmuxMouseBindings(keyTable string) {
 			}
 			args = append(args, "-T", table, binding.key)
 			args = append(args, binding.args...)
-			_ = exec.Command("tmux", args...).Run()
+			_ = core.TmuxRun(args...)
 		}
 	}
 }
@@ -499,17 +534,17 @@ func configureTmuxPaneBadgeBehavior(sessionID string) {
 		return
 	}
 	updateCmd := `if -F "#{>:#{window_panes},1}" "set-window-option -q -t '#{window_id}' pane-border-status top" "set-window-option -q -t '#{window_id}' pane-border-status off"`
-	_ = exec.Command("tmux", "set-hook", "-q", "-t", sessionID, "after-split-window", updateCmd).Run()
-	_ = exec.Command("tmux", "set-hook", "-q", "-t", sessionID, "after-kill-pane", updateCmd).Run()
-	_ = exec.Command("tmux", "set-hook", "-q", "-t", sessionID, "after-join-pane", updateCmd).Run()
-	_ = exec.Command("tmux", "set-hook", "-q", "-t", sessionID, "after-break-pane", updateCmd).Run()
+	_ = core.TmuxRun("set-hook", "-q", "-t", sessionID, "after-split-window", updateCmd)
+	_ = core.TmuxRun("set-hook", "-q", "-t", sessionID, "after-kill-pane", updateCmd)
+	_ = core.TmuxRun("set-hook", "-q", "-t", sessionID, "after-join-pane", updateCmd)
+	_ = core.TmuxRun("set-hook", "-q", "-t", sessionID, "after-break-pane", updateCmd)
 	for _, windowID := range tmuxSessionWindowIDs(sessionID) {
-		_ = exec.Command("tmux", "if-shell", "-F", "-t", windowID, "#{>:#{window_panes},1}", "set-window-option -q -t "+windowID+" pane-border-status top", "set-window-option -q -t "+windowID+" pane-border-status off").Run()
+		_ = core.TmuxRun("if-shell", "-F", "-t", windowID, "#{>:#{window_panes},1}", "set-window-option -q -t "+windowID+" pane-border-status top", "set-window-option -q -t "+windowID+" pane-border-status off")
 	}
 }
 
 func tmuxSessionWindowIDs(sessionID string) []string {
-	out, err := exec.Command("tmux", "list-windows", "-t", sessionID, "-F", "#{window_id}").Output()
+	out, err := core.TmuxOutput("list-windows", "-t", sessionID, "-F", "#{window_id}")
 	if err != nil {
 		return nil
 	}
@@ -540,11 +575,11 @@ func configureTmuxActionBindings(sessionID string, wtxBin string) {
 	ideCmd := tmuxActionsCommandWithSourcePane(wtxBin, "#{pane_id}", tmuxActionIDE)
 	backCmd := tmuxActionsCommandWithSourcePane(wtxBin, "#{pane_id}", tmuxActionBack)
 
-	_ = exec.Command("tmux", "bind-key", "-T", keyTable, "C-a", "popup", "-E", "-d", "#{pane_current_path}", "-w", "72", "-h", "20", actionsPopupCmd+" --source-pane '#{pane_id}'").Run()
-	_ = exec.Command("tmux", "bind-key", "-T", keyTable, "C-s", "run-shell", "-b", splitCmd).Run()
-	_ = exec.Command("tmux", "bind-key", "-T", keyTable, "C-p", "run-shell", "-b", prCmd).Run()
-	_ = exec.Command("tmux", "bind-key", "-T", keyTable, "C-l", "popup", "-E", "-d", "#{pane_current_path}", "-w", "60", "-h", "20", ideCmd).Run()
-	_ = exec.Command("tmux", "bind-key", "-T", keyTable, "C-w", "run-shell", "-b", backCmd).Run()
+	_ = core.TmuxRun("bind-key", "-T", keyTable, "C-a", "popup", "-E", "-d", "#{pane_current_path}", "-w", "72", "-h", "24", actionsPopupCmd+" --source-pane '#{pane_id}'")
+	_ = core.TmuxRun("bind-key", "-T", keyTable, "C-s", "run-shell", "-b", splitCmd)
+	_ = core.TmuxRun("bind-key", "-T", keyTable, "C-p", "run-shell", "-b", prCmd)
+	_ = core.TmuxRun("bind-key", "-T", keyTable, "C-l", "popup", "-E", "-d", "#{pane_current_path}", "-w", "60", "-h", "20", ideCmd)
+	_ = core.TmuxRun("bind-key", "-T", keyTable, "C-w", "run-shell", "-b", backCmd)
 }
 
 func tmuxSessionKeyTable(sessionID string) string {
@@ -571,7 +606,7 @@ func configureTmuxStatusRefreshHooks(sessionID string) {
 		"client-session-changed",
 	}
 	for _, hook := range hooks {
-		_ = exec.Command("tmux", "set-hook", "-q", "-t", sessionID, hook, refreshCmd).Run()
+		_ = core.TmuxRun("set-hook", "-q", "-t", sessionID, hook, refreshCmd)
 	}
 }
 
@@ -579,7 +614,7 @@ func configureTmuxStatus(sessionID string, leftLength string, interval string) {
 	tmuxSetOption(sessionID, "status", "1")
 	tmuxSetOption(sessionID, "status-position", "bottom")
 	tmuxSetOption(sessionID, "status-justify", "left")
-	tmuxSetOption(sessionID, "status-style", "fg=#d0d0d0,bg=#3d2a5c")
+	tmuxSetOption(sessionID, "status-style", tmuxStatusStyle())
 	tmuxSetOption(sessionID, "status-left-length", leftLength)
 	tmuxSetOption(sessionID, "status-right", tmuxStatusRightHint)
 	tmuxSetOption(sessionID, "status-right-length", "64")
@@ -594,71 +629,71 @@ func tmuxSetOption(sessionID string, key string, value string) {
 	if strings.TrimSpace(sessionID) == "" {
 		return
 	}
-	_ = exec.Command("tmux", "set-option", "-q", "-t", sessionID, key, value).Run()
+	_ = core.TmuxRun("set-option", "-q", "-t", sessionID, key, value)
 }
 
 func tmuxSetWindowOption(sessionID string, key string, value string) {
 	if strings.TrimSpace(sessionID) == "" {
 		return
 	}
-	_ = exec.Command("tmux", "set-window-option", "-q", "-t", sessionID, key, value).Run()
+	_ = core.TmuxRun("set-window-option", "-q", "-t", sessionID, key, value)
 }
 
 func tmuxSetServerOption(key string, value string) {
 	if strings.TrimSpace(key) == "" {
 		return
 	}
-	_ = exec.Command("tmux", "set-option", "-s", "-q", key, value).Run()
+	_ = core.TmuxRun("set-option", "-s", "-q", key, value)
 }
 
 func tmuxSetGlobalWindowOption(key string, value string) {
 	if strings.TrimSpace(key) == "" {
 		return
 	}
-	_ = exec.Command("tmux", "set-window-option", "-g", "-q", key, value).Run()
+	_ = core.TmuxRun("set-window-option", "-g", "-q", key, value)
 }
 
 func tmuxAppendServerOption(key string, value string) {
 	if strings.TrimSpace(key) == "" || strings.TrimSpace(value) == "" {
 		return
 	}
-	_ = exec.Command("tmux", "set-option", "-s", "-as", "-q", key, value).Run()
+	_ = core.TmuxRun("set-option", "-s", "-as", "-q", key, value)
 }
 
 func tmuxAppendGlobalOption(key string, value string) {
 	if strings.TrimSpace(key) == "" || strings.TrimSpace(value) == "" {
 		return
 	}
-	_ = exec.Command("tmux", "set-option", "-g", "-as", "-q", key, value).Run()
+	_ = core.TmuxRun("set-option", "-g", "-as", "-q", key, value)
 }
 
 func tmuxBindKey(sessionID string, key string, command string) {
 	if strings.TrimSpace(sessionID) == "" || strings.TrimSpace(key) == "" || strings.TrimSpace(command) == "" {
 		return
 	}
-	_ = exec.Command("tmux", "bind-key", "-t", sessionID, key, "run-shell", command).Run()
+	_ = core.TmuxRun("bind-key", "-t", sessionID, key, "run-shell", command)
 }
 
 func tmuxBindKeyGlobal(key string, command string) {
 	if strings.TrimSpace(key) == "" || strings.TrimSpace(command) == "" {
 		return
 	}
-	_ = exec.Command("tmux", "bind-key", "-n", key, "run-shell", command).Run()
+	_ = core.TmuxRun("bind-key", "-n", key, "run-shell", command)
 }
 
 func resolveStatusCommandBinary() string {
-	if p, err := exec.LookPath("wtx"); err == nil && strings.TrimSpace(p) != "" && fileLooksExecutable(p) {
+	if p, err := exec.LookPath("wtx"); err == nil && strings.TrimSpace(p) != "" && core.FileLooksExecutable(p) {
 		return p
 	}
 	if v := strings.TrimSpace(os.Getenv("WTX_STATUS_BIN")); v != "" {
-		if fileLooksExecutable(v) {
+		if core.FileLooksExecutable(v) {
 			return v
 		}
 	}
-	if p, err := os.Executable(); err == nil && strings.TrimSpace(p) != "" && fileLooksExecutable(p) {
+	if p, err := os.Executable(); err == nil && strings.TrimSpace(p) != "" && core.FileLooksExecutable(p) {
 		return p
 	}
-	if p, err := exec.LookPath("wtx"); err == nil && strings.TrimSpace(p) != "" && fileLooksExecutable(p) {
+	if p, err := exec.LookPath("wtx"); err == nil && strings.TrimSpace(p) != "" && core.FileLooksExecutable(p) {
 		return p
 	}
 	return ""
@@ -666,13 +701,13 @@ func resolveStatusCommandBinary() string {
 
 func resolveAgentLifecycleBinary() string {
 	candidates := make([]string, 0, 3)
-	if p, err := os.Executable(); err == nil && strings.TrimSpace(p) != "" && fileLooksExecutable(p) {
+	if p, err := os.Executable(); err == nil && strings.TrimSpace(p) != "" && core.FileLooksExecutable(p) {
 		candidates = append(candidates, p)
 	}
-	if v := strings.TrimSpace(os.Getenv("WTX_STATUS_BIN")); v != "" && fileLooksExecutable(v) {
+	if v := strings.TrimSpace(os.Getenv("WTX_STATUS_BIN")); v != "" && core.FileLooksExecutable(v) {
 		candidates = append(candidates, v)
 	}
-	if p, err := exec.LookPath("wtx"); err == nil && strings.TrimSpace(p) != "" && fileLooksExecutable(p) {
+	if p, err := exec.LookPath("wtx"); err == nil && strings.TrimSpace(p) != "" && core.FileLooksExecutable(p) {
 		candidates = append(candidates, p)
 	}
 	for _, candidate := range candidates {
@@ -707,16 +742,16 @@ func resolveCurrentTerminalProgram() string {
 }
 
 func resolveCurrentSessionParentTerminalProgram() string {
-	sessionID, err := currentSessionID()
+	sessionID, err := core.CurrentSessionID()
 	if err != nil || strings.TrimSpace(sessionID) == "" {
 		return ""
 	}
-	if out, err := exec.Command("tmux", "show-options", "-qv", "-t", sessionID, "@wtx_parent_terminal").Output(); err == nil {
+	if out, err := core.TmuxOutput("show-options", "-qv", "-t", sessionID, "@wtx_parent_terminal"); err == nil {
 		if term := strings.TrimSpace(string(out)); term != "" {
 			return term
 		}
 	}
-	if out, err := exec.Command("tmux", "show-environment", "-t", sessionID, "WTX_PARENT_TERMINAL").Output(); err == nil {
+	if out, err := core.TmuxOutput("show-environment", "-t", sessionID, "WTX_PARENT_TERMINAL"); err == nil {
 		line := strings.TrimSpace(string(out))
 		if strings.HasPrefix(line, "WTX_PARENT_TERMINAL=") {
 			return strings.TrimSpace(strings.TrimPrefix(line, "WTX_PARENT_TERMINAL="))
@@ -746,21 +781,17 @@ func shouldDisableTmuxInputEnhancements(terminalProgram string) bool {
 	return strings.Contains(term, "ghostty")
 }
 
-type tmuxAgentState struct {
-	State        string `json:"state"`
-	ExitCode     int    `json:"exit_code"`
-	ExitedAtUnix int64  `json:"exited_at_unix"`
-}
-
 func runTmuxAgentStart(args []string) error {
 	worktreePath := parseWorktreeArg(args)
 	if strings.TrimSpace(worktreePath) == "" {
 		return nil
 	}
-	return writeTmuxAgentState(worktreePath, tmuxAgentState{
-		State:        "running",
-		ExitCode:     0,
-		ExitedAtUnix: 0,
+	return writeTmuxAgentState(worktreePath, core.TmuxAgentState{
+		State:         "running",
+		ExitCode:      0,
+		StartedAtUnix: time.Now().Unix(),
+		ExitedAtUnix:  0,
+		AgentCommand:  parseStringArg(args, "--agent"),
 	})
 }
 
@@ -771,17 +802,26 @@ func runTmuxAgentExit(args []string) error {
 	}
 	exitCode := parseIntArg(args, "--code", 0)
 	forceUnlock := parseBoolArg(args, "--force-unlock")
-	if _, repoRoot, err := requireGitContext(worktreePath); err == nil && strings.TrimSpace(repoRoot) != "" {
-		lockMgr := NewLockManager()
+	if _, repoRoot, err := core.RequireGitContext(worktreePath); err == nil && strings.TrimSpace(repoRoot) != "" {
+		lockMgr := core.NewLockManager()
 		_ = lockMgr.ReleaseIfOwned(repoRoot, worktreePath)
 		if forceUnlock {
 			_ = lockMgr.ForceUnlock(repoRoot, worktreePath)
 		}
-	}
-	return writeTmuxAgentState(worktreePath, tmuxAgentState{
-		State:        "exited",
-		ExitCode:     exitCode,
-		ExitedAtUnix: time.Now().Unix(),
+		runHookOrWarn(core.HookAgentExit, core.HookContext{RepoRoot: repoRoot, WorktreePath: worktreePath, Branch: core.CurrentBranchInWorktree(worktreePath), ExitCode: &exitCode})
+	}
+	// 130 comes from the INT/TERM trap (shutdown, tmux kill); keep those resumable.
+	if exitCode != 130 {
+		_ = core.ForgetSession(worktreePath)
+		notifyTerminal("wtx", agentFinishedMessage(core.CurrentBranchInWorktree(worktreePath), exitCode))
+	}
+	previous, _ := core.ReadTmuxAgentState(worktreePath)
+	return writeTmuxAgentState(worktreePath, core.TmuxAgentState{
+		State:         "exited",
+		ExitCode:      exitCode,
+		StartedAtUnix: previous.StartedAtUnix,
+		ExitedAtUnix:  time.Now().Unix(),
+		AgentCommand:  previous.AgentCommand,
 	})
 }
 
@@ -794,6 +834,15 @@ func parseBoolArg(args []string, key string) bool {
 	return false
 }
 
+func parseStringArg(args []string, key string) string {
+	for i := 0; i < len(args); i++ {
+		if args[i] == key && i+1 < len(args) {
+			return strings.TrimSpace(args[i+1])
+		}
+	}
+	return ""
+}
+
 func parseIntArg(args []string, key string, fallback int) int {
 	for i := 0; i < len(args); i++ {
 		if args[i] != key || i+1 >= len(args) {
@@ -813,34 +862,18 @@ func parseIntArg(args []string, key string, fallback int) int {
 }
 
 func tmuxAgentSummary(worktreePath string) string {
-	state, ok := readTmuxAgentState(worktreePath)
+	state, ok := core.ReadTmuxAgentState(worktreePath)
 	if !ok {
 		return ""
 	}
-	if strings.EqualFold(strings.TrimSpace(state.State), "exited") {
-		return "Agent exited (" + strconv.Itoa(state.ExitCode) + ")"
+	if exit := core.FormatAgentExit(state); exit != "" {
+		return "Agent " + exit
 	}
 	return ""
 }
 
-func readTmuxAgentState(worktreePath string) (tmuxAgentState, bool) {
-	path, err := tmuxAgentStatePath(worktreePath)
-	if err != nil {
-		return tmuxAgentState{}, false
-	}
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return tmuxAgentState{}, false
-	}
-	var state tmuxAgentState
-	if err := json.Unmarshal(data, &state); err != nil {
-		return tmuxAgentState{}, false
-	}
-	return state, true
-}
-
-func writeTmuxAgentState(worktreePath string, state tmuxAgentState) error {
-	path, err := tmuxAgentStatePath(worktreePath)
+func writeTmuxAgentState(worktreePath string, state core.TmuxAgentState) error {
+	path, err := core.TmuxAgentStatePath(worktreePath)
 	if err != nil {
 		return err
 	}
@@ -857,35 +890,3 @@ func writeTmuxAgentState(worktreePath string, state tmuxAgentState) error {
 	}
 	return os.Rename(tmp, path)
 }
-
-func tmuxAgentStatePath(worktreePath string) (string, error) {
-	worktreePath = strings.TrimSpace(worktreePath)
-	if worktreePath == "" {
-		return "", os.ErrInvalid
-	}
-	repoRoot, err := repoRootForDir(worktreePath, "git")
-	if err != nil {
-		return "", err
-	}
-	worktreeID, err := worktreeID(repoRoot, worktreePath)
-	if err != nil {
-		return "", err
-	}
-	home := strings.TrimSpace(os.Getenv("HOME"))
-	if home == "" {
-		return "", os.ErrNotExist
-	}
-	return filepath.Join(home, ".wtx", "agent-state", worktreeID+".json"), nil
-}
-
-func fileLooksExecutable(path string) bool {
-	path = strings.TrimSpace(path)
-	if path == "" {
-		return false
-	}
-	info, err := os.Stat(path)
-	if err != nil || info.IsDir() {
-		return false
-	}
-	return info.Mode()&0o111 != 0
-}
@@ -159,3 +159,9 @@ func TestTmuxMouseBindingsCopyModeTable(t *testing.T) {
 		t.Fatalf("expected copy-mode wheel up to scroll by one line, got %q", got)
 	}
 }
+
+func TestTmuxFormatEscape(t *testing.T) {
+	if got := tmuxFormatEscape("/repo.wt/#12 #{session_name}"); got != "/repo.wt/##12 ##{session_name}" {
+		t.Fatalf("expected # to be doubled, got %q", got)
+	}
+}
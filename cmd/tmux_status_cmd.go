@@ -1,22 +1,16 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/aixolotls/wtx/internal/core"
 )
 
-const tmuxStatusGHTTL = 10 * time.Second
 const tmuxStatusGHStaleMaxAge = 2 * time.Minute
 const defaultGHSummary = "PR - | CI - | GH - | Review -"
-
-type ghStatusCacheEntry struct {
-	FetchedAtUnix int64  `json:"fetched_at_unix"`
-	Summary       string `json:"summary"`
-}
+const localOnlyGHSummary = "local only"
 
 func runTmuxStatus(args []string) error {
 	worktreePath := parseWorktreeArg(args)
@@ -45,7 +39,7 @@ func buildTmuxStatusLine(worktreePath string) string {
 	if worktreePath == "" {
 		return label
 	}
-	branch := currentBranchInWorktree(worktreePath)
+	branch := core.CurrentBranchInWorktree(worktreePath)
 	if branch != "" {
 		label += "  " + branch
 	}
@@ -62,50 +56,43 @@ func buildTmuxTitle(worktreePath string) string {
 	if worktreePath == "" {
 		return "wtx"
 	}
-	branch := currentBranchInWorktree(worktreePath)
+	branch := core.CurrentBranchInWorktree(worktreePath)
 	if branch == "" {
 		return "wtx"
 	}
 	return "wtx - " + branch
 }
 
-func currentBranchInWorktree(worktreePath string) string {
-	branch, err := gitOutputInDir(worktreePath, "git", "rev-parse", "--abbrev-ref", "HEAD")
-	if err != nil {
-		return ""
-	}
-	branch = strings.TrimSpace(branch)
-	if branch == "" || strings.EqualFold(branch, "HEAD") || strings.EqualFold(branch, "detached") {
-		return ""
-	}
-	return branch
-}
-
+// ghSummaryForBranchCached formats PR data for branch. Lookups go through
+// the shared PR cache, so a recent fetch by the TUI is reused; when gh fails,
+// a stale cached entry is preferred over an empty summary.
 func ghSummaryForBranchCached(worktreePath string, branch string) string {
 	branch = strings.TrimSpace(branch)
 	if branch == "" {
 		return defaultGHSummary
 	}
-	repoRoot, err := repoRootForDir(worktreePath, "")
+	repoRoot, err := core.RepoRootForDir(worktreePath, "")
 	if err != nil {
 		return defaultGHSummary
 	}
-	if summary, ok := readCachedGHSummary(repoRoot, branch); ok {
-		return summary
+	if !core.RepoHasRemote(repoRoot) {
+		return localOnlyGHSummary
 	}
 	summary, reliable := ghSummaryForRepoBranch(repoRoot, branch)
 	if reliable {
-		_ = writeCachedGHSummary(repoRoot, branch, summary)
 		return summary
 	}
-	if summary, ok := readCachedGHSummaryAllowStale(repoRoot, branch); ok {
-		return summary
+	if entry, ok := core.ReadSharedPRCache(repoRoot, branch, tmuxStatusGHStaleMaxAge); ok {
+		if !entry.Found {
+			return defaultGHSummary
+		}
+		return ghSummaryForPR(entry.Data)
 	}
 	return summary
 }
 
 func ghSummaryForRepoBranch(repoRoot string, branch string) (string, bool) {
-	data, err := NewGHManager().PRDataByBranch(repoRoot, []string{branch})
+	data, err := core.LookupPRData(core.NewGHManager(), repoRoot, []string{branch}, false)
 	if err != nil {
 		return defaultGHSummary, false
 	}
@@ -113,100 +100,42 @@ func ghSummaryForRepoBranch(repoRoot string, branch string) (string, bool) {
 	if !ok {
 		return defaultGHSummary, true
 	}
-	return "PR " + prLabelWithURL(pr) + " | CI " + ciLabel(pr) + " | GH " + ghAPIStatusLabel(pr) + " | Review " + reviewLabel(pr), true
-}
-
-func readCachedGHSummary(repoRoot string, branch string) (string, bool) {
-	return readCachedGHSummaryWithTTL(repoRoot, branch, tmuxStatusGHTTL)
+	return ghSummaryForPR(pr), true
 }
 
-func readCachedGHSummaryAllowStale(repoRoot string, branch string) (string, bool) {
-	return readCachedGHSummaryWithTTL(repoRoot, branch, tmuxStatusGHStaleMaxAge)
-}
-
-func readCachedGHSummaryWithTTL(repoRoot string, branch string, ttl time.Duration) (string, bool) {
-	path, err := ghStatusCachePath(repoRoot, branch)
-	if err != nil {
-		return "", false
-	}
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return "", false
-	}
-	var entry ghStatusCacheEntry
-	if err := json.Unmarshal(data, &entry); err != nil {
-		return "", false
-	}
-	if strings.TrimSpace(entry.Summary) == "" || entry.FetchedAtUnix <= 0 {
-		return "", false
-	}
-	if ttl > 0 && time.Since(time.Unix(entry.FetchedAtUnix, 0)) > ttl {
-		return "", false
-	}
-	return entry.Summary, true
-}
-
-func writeCachedGHSummary(repoRoot string, branch string, summary string) error {
-	path, err := ghStatusCachePath(repoRoot, branch)
-	if err != nil {
-		return err
-	}
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		return err
-	}
-	entry := ghStatusCacheEntry{
-		FetchedAtUnix: time.Now().Unix(),
-		Summary:       summary,
-	}
-	payload, err := json.Marshal(entry)
-	if err != nil {
-		return err
-	}
-	tmp := path + ".tmp"
-	if err := os.WriteFile(tmp, payload, 0o644); err != nil {
-		return err
-	}
-	return os.Rename(tmp, path)
-}
-
-func ghStatusCachePath(repoRoot string, branch string) (string, error) {
-	home := strings.TrimSpace(os.Getenv("HOME"))
-	if home == "" {
-		return "", fmt.Errorf("HOME not set")
-	}
-	key := hashString(strings.TrimSpace(repoRoot) + "|" + strings.TrimSpace(branch))
-	return filepath.Join(home, ".wtx", "status-cache", key+".json"), nil
+func ghSummaryForPR(pr core.PRData) string {
+	return "PR " + prLabelWithURL(pr) + " | CI " + ciLabel(pr) + " | GH " + ghAPIStatusLabel(pr) + " | Review " + reviewLabel(pr)
 }
 
-func prLabel(pr PRData) string {
+func prLabel(pr core.PRData) string {
 	if pr.Number <= 0 {
 		return "-"
 	}
 	return fmt.Sprintf("#%d", pr.Number)
 }
 
-func prLabelWithURL(pr PRData) string {
+func prLabelWithURL(pr core.PRData) string {
 	return prLabel(pr)
 }
 
-func ciLabel(pr PRData) string {
+func ciLabel(pr core.PRData) string {
 	if pr.CITotal == 0 {
 		return "-"
 	}
 	switch pr.CIState {
-	case PRCISuccess:
+	case core.PRCISuccess:
 		return fmt.Sprintf("ok %d/%d", pr.CICompleted, pr.CITotal)
-	case PRCIFail:
+	case core.PRCIFail:
 		return fmt.Sprintf("fail %d/%d", pr.CICompleted, pr.CITotal)
-	case PRCIInProgress:
+	case core.PRCIInProgress:
 		return fmt.Sprintf("run %d/%d", pr.CICompleted, pr.CITotal)
 	default:
 		return "-"
 	}
 }
 
-func reviewLabel(pr PRData) string {
-	required, requiredKnown := ensureRequiredAtLeastApproved(
+func reviewLabel(pr core.PRData) string {
+	required, requiredKnown := core.EnsureRequiredAtLeastApproved(
 		pr.ReviewApproved,
 		pr.ReviewKnown,
 		pr.ReviewRequired,
@@ -226,7 +155,7 @@ func reviewLabel(pr PRData) string {
 	return fmt.Sprintf("%s u:%d", prefix, pr.UnresolvedComments)
 }
 
-func ghAPIStatusLabel(pr PRData) string {
+func ghAPIStatusLabel(pr core.PRData) string {
 	status := strings.TrimSpace(strings.ToLower(pr.Status))
 	switch status {
 	case "conflict":
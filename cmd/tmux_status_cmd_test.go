@@ -1,6 +1,10 @@
 package cmd
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
 
 func TestGHAPIStatusLabel_Mapping(t *testing.T) {
 	tests := []struct {
@@ -22,7 +26,7 @@ func TestGHAPIStatusLabel_Mapping(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			got := ghAPIStatusLabel(PRData{Status: tc.in})
+			got := ghAPIStatusLabel(core.PRData{Status: tc.in})
 			if got != tc.want {
 				t.Fatalf("expected %q, got %q", tc.want, got)
 			}
@@ -31,27 +35,27 @@ func TestGHAPIStatusLabel_Mapping(t *testing.T) {
 }
 
 func TestPRLabel_ShowsNumberOnly(t *testing.T) {
-	if got := prLabel(PRData{Number: 12, Status: "open"}); got != "#12" {
+	if got := prLabel(core.PRData{Number: 12, Status: "open"}); got != "#12" {
 		t.Fatalf("expected #12, got %q", got)
 	}
 }
 
 func TestReviewLabel_UsesReviewProgressWhenRequiredKnown(t *testing.T) {
-	pr := PRData{ReviewApproved: 2, ReviewRequired: 2, UnresolvedComments: 1}
+	pr := core.PRData{ReviewApproved: 2, ReviewRequired: 2, UnresolvedComments: 1}
 	if got := reviewLabel(pr); got != "2/2 u:1" {
 		t.Fatalf("expected 2/2 label, got %q", got)
 	}
 }
 
 func TestReviewLabel_UsesKnownApprovalWhenNoRequiredCount(t *testing.T) {
-	pr := PRData{ReviewApproved: 3, ReviewKnown: true, UnresolvedComments: 0}
+	pr := core.PRData{ReviewApproved: 3, ReviewKnown: true, UnresolvedComments: 0}
 	if got := reviewLabel(pr); got != "3/3 u:0" {
 		t.Fatalf("expected 3/3 label, got %q", got)
 	}
 }
 
 func TestReviewLabel_NormalizesRequiredWhenApprovalsAreHigher(t *testing.T) {
-	pr := PRData{ReviewApproved: 2, ReviewRequired: 1, ReviewKnown: true, UnresolvedComments: 0}
+	pr := core.PRData{ReviewApproved: 2, ReviewRequired: 1, ReviewKnown: true, UnresolvedComments: 0}
 	if got := reviewLabel(pr); got != "2/2 u:0" {
 		t.Fatalf("expected 2/2 label, got %q", got)
 	}
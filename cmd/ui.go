@@ -2,10 +2,12 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/aixolotls/wtx/internal/core"
 	uiview "github.com/aixolotls/wtx/ui"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
@@ -16,10 +18,10 @@ import (
 )
 
 type model struct {
-	mgr                   *WorktreeManager
-	orchestrator          *WorktreeOrchestrator
+	mgr                   *core.WorktreeManager
+	orchestrator          *core.WorktreeOrchestrator
 	runner                *Runner
-	status                WorktreeStatus
+	status                core.WorktreeStatus
 	listIndex             int
 	ready                 bool
 	width                 int
@@ -27,22 +29,36 @@ type model struct {
 	mode                  uiMode
 	branchInput           textinput.Model
 	newBranchInput        textinput.Model
+	noteInput             textinput.Model
+	notePath              string
+	noteBranch            string
 	spinner               spinner.Model
 	ghSpinner             spinner.Model
 	ghPendingByBranch     map[string]bool
-	ghDataByBranch        map[string]PRData
+	ghCovered             map[string]bool
+	ghDataByBranch        map[string]core.PRData
 	ghLoadedKey           string
 	ghFetchingKey         string
 	forceGHRefresh        bool
 	ghWarnMsg             string
+	ghAuth                *core.GHAuthError
+	integrations          []integrationIssue
+	showIntegrations      bool
+	fetchErr              error
+	ghRefreshInterval     time.Duration
+	ghAttemptedAt         time.Time
+	ghUpdatedAt           time.Time
+	ghStale               bool
 	updateHint            string
 	updateHintIsError     bool
 	errMsg                string
 	warnMsg               string
+	keys                  keymap
 	creatingBranch        string
 	creatingBaseRef       string
 	creatingExisting      bool
 	creatingStartedAt     time.Time
+	createLog             *progressLog
 	deletePath            string
 	deleteBranch          string
 	unlockPath            string
@@ -56,7 +72,8 @@ type model struct {
 	pendingPath           string
 	pendingBranch         string
 	pendingOpenShell      bool
-	pendingLock           *WorktreeLock
+	pendingLock           *core.WorktreeLock
+	pendingWarning        string
 	autoActionPath        string
 	openLoading           bool
 	openLoadErr           string
@@ -71,8 +88,10 @@ type model struct {
 	openAllBranches       []openBranchOption
 	openAllLocked         []openBranchOption
 	openAllLoaded         bool
-	openSlots             []openSlotState
+	openSlots             []core.OpenSlotState
 	openPRBranches        []string
+	openPRRequested       map[string]bool
+	openPRInFlight        int
 	openFetchID           string
 	openShowDebug         bool
 	openDebugIndex        int
@@ -86,6 +105,29 @@ type model struct {
 	openPickConfirmPath   string
 	openPickConfirmBranch string
 	openDefaultBaseRef    string
+	openBaseRefFromStatus bool
+	listFilter            string
+	listFiltering         bool
+	listSort              core.ListSortMode
+	showHelp              bool
+	showErrorDetail       bool
+	errDetail             error
+	errorDetailNote       string
+	listMarked            map[string]bool
+	bulkIndex             int
+	bulkAction            bulkAction
+	bulkTargets           []core.WorktreeInfo
+	bulkRunning           bool
+	previewPath           string
+	previewBranch         string
+	previewLines          []string
+	previewOffset         int
+	previewLoading        bool
+	previewFull           bool
+	deleteGrace           time.Duration
+	pendingDeletes        []pendingDelete
+	nextDeleteID          int
+	resolvedBaseRef       string
 	openDefaultFetch      bool
 	openNewBranchForm     *huh.Form
 	openFormBranchPtr     *string
@@ -94,36 +136,57 @@ type model struct {
 	confirmForm           *huh.Form
 	confirmResult         bool
 	confirmKind           confirmKind
+	prForm                *huh.Form
+	prDraft               *prDraft
+	stashPending          *core.DirtyCheckoutError
 	openCreating          bool
 	openCreatingStartedAt time.Time
 }
 
-func (m model) PendingWorktree() (string, string, bool, *WorktreeLock) {
+func (m model) PendingWorktree() (string, string, bool, *core.WorktreeLock) {
 	return m.pendingPath, m.pendingBranch, m.pendingOpenShell, m.pendingLock
 }
 
+// PendingWarning is what went wrong setting up the pending worktree without
+// stopping it, such as a failed post_create hook.
+func (m model) PendingWarning() string {
+	return m.pendingWarning
+}
+
 func newModel() model {
-	lockMgr := NewLockManager()
-	mgr := NewWorktreeManager("", lockMgr)
-	orchestrator := NewWorktreeOrchestrator(mgr, lockMgr, NewGHManager())
+	lockMgr := core.NewLockManager()
+	mgr := core.NewWorktreeManager("", lockMgr)
+	gh := core.NewGHManager()
+	orchestrator := core.NewWorktreeOrchestrator(mgr, lockMgr, gh)
 	m := model{mgr: mgr, orchestrator: orchestrator, runner: NewRunner(lockMgr)}
+	m.ghRefreshInterval = core.DefaultPRRefreshInterval
+	m.deleteGrace = defaultDeleteGrace
 	m.branchInput = newBranchInput()
 	m.newBranchInput = newCreateBranchInput()
+	m.noteInput = newNoteInput()
 	m.spinner = newSpinner()
 	m.ghSpinner = newGHSpinner()
 	m.ghPendingByBranch = map[string]bool{}
-	m.ghDataByBranch = map[string]PRData{}
+	m.ghDataByBranch = map[string]core.PRData{}
 	m.mode = modeOpen
 	m.openStage = openStageMain
 	m.openSelected = 0
 	m.openDefaultFetch = true
-	if cfg, err := LoadConfig(); err == nil {
+	keys, err := loadKeymap()
+	m.keys = keys
+	if err != nil {
+		m.warnMsg = "Ignoring keybindings: " + err.Error()
+	}
+	if cfg, err := core.LoadConfigForDir(""); err == nil {
 		if strings.TrimSpace(cfg.NewBranchBaseRef) != "" {
 			m.openDefaultBaseRef = strings.TrimSpace(cfg.NewBranchBaseRef)
 		}
 		if cfg.NewBranchFetchFirst != nil {
 			m.openDefaultFetch = *cfg.NewBranchFetchFirst
 		}
+		m.ghRefreshInterval = core.PRRefreshInterval(cfg)
+		m.deleteGrace = deleteGracePeriod(cfg)
+		gh.Ttl = m.ghRefreshInterval
 	}
 	return m
 }
@@ -131,10 +194,14 @@ func newModel() model {
 func (m model) Init() tea.Cmd {
 	return tea.Batch(
 		loadOpenScreenCmd(m.orchestrator, m.mgr),
+		resolveBaseRefCmd(m.mgr),
 		m.ghSpinner.Tick,
 		pollGHTickCmd(),
 		pollStatusTickCmd(),
 		checkInteractiveUpdateHintCmd(),
+		checkIntegrationsCmd(),
+		backgroundFetchCmd(m.mgr),
+		startupGCCmd(m.orchestrator, m.mgr),
 	)
 }
 
@@ -153,6 +220,23 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, cmd
 	}
+	if m.prForm != nil {
+		if _, ok := msg.(tea.KeyMsg); ok {
+			return m.updatePRForm(msg)
+		}
+		// The form can stay open a while, so everything else still reaches
+		// the list to keep its polling and refreshes going.
+		next, formCmd := m.updatePRForm(msg)
+		m = next.(model)
+		form, draft := m.prForm, m.prDraft
+		m.prForm, m.prDraft = nil, nil
+		updated, cmd := m.Update(msg)
+		um := updated.(model)
+		if um.prForm == nil {
+			um.prForm, um.prDraft = form, draft
+		}
+		return um, tea.Batch(formCmd, cmd)
+	}
 	if m.openNewBranchForm != nil {
 		applyFormMsg := func(formMsg tea.Msg) (tea.Model, tea.Cmd) {
 			form, cmd := m.openNewBranchForm.Update(formMsg)
@@ -224,9 +308,27 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.updateHint = strings.TrimSpace(msg.hint)
 		m.updateHintIsError = msg.isError
 		return m, nil
+	case integrationsCheckedMsg:
+		m.integrations = msg.issues
+		return m, nil
+	case baseRefResolvedMsg:
+		baseRef := strings.TrimSpace(string(msg))
+		if baseRef == "" {
+			return m, nil
+		}
+		m.resolvedBaseRef = baseRef
+		m.status.BaseRef = baseRef
+		if m.openBaseRefFromStatus {
+			m.openDefaultBaseRef = baseRef
+		}
+		return m, nil
 	case openScreenLoadedMsg:
 		m.ready = true
 		m.status = msg.status
+		if m.resolvedBaseRef != "" {
+			// The load may have started before the background lookup settled.
+			m.status.BaseRef = m.resolvedBaseRef
+		}
 		m.errMsg = ""
 		if msg.err != nil {
 			m.openLoading = false
@@ -255,6 +357,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.openDefaultBaseRef == "" {
 				m.openDefaultBaseRef = resolveNewBranchBaseRef("", msg.status.BaseRef, msg.status.HasRemote)
 			}
+			m.openBaseRefFromStatus = true
 		}
 		if m.openStage == openStageMain {
 			m.newBranchInput.Blur()
@@ -272,12 +375,15 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if len(paths) > 0 {
 			cmds = append(cmds, fetchDirtyStatusCmd(paths))
 		}
-		if len(m.openPRBranches) == 0 {
+		m.openPRRequested = map[string]bool{}
+		m.openPRInFlight = 0
+		prCmd := m.requestVisibleOpenPRData()
+		if prCmd == nil {
 			m.openLoading = false
 			m.openLoadErr = ""
 			return m, tea.Batch(cmds...)
 		}
-		cmds = append(cmds, fetchOpenPRDataCmd(m.orchestrator, m.status.RepoRoot, m.openPRBranches, msg.fetchID))
+		cmds = append(cmds, prCmd)
 		return m, tea.Batch(cmds...)
 	case openAllBranchesLoadedMsg:
 		if msg.err != nil {
@@ -305,17 +411,20 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if strings.TrimSpace(msg.fetchID) == "" || msg.fetchID != m.openFetchID {
 			return m, nil
 		}
-		m.openLoading = false
+		if m.openPRInFlight > 0 {
+			m.openPRInFlight--
+		}
+		m.openLoading = m.openPRInFlight > 0
 		if msg.err != nil {
 			m.openLoadErr = msg.err.Error()
 			return m, nil
 		}
 		m.openLoadErr = ""
 		if m.openSearchAllActive {
-			applyPRDataToOpenState(nil, nil, &m.openSlots, msg.byBranch)
+			applyPRDataToOpenState(nil, nil, &m.openSlots, msg.byBranch, msg.branches)
 			return m, nil
 		}
-		applyPRDataToOpenState(&m.openBranches, &m.openLockedBranches, &m.openSlots, msg.byBranch)
+		applyPRDataToOpenState(&m.openBranches, &m.openLockedBranches, &m.openSlots, msg.byBranch, msg.branches)
 		m.openRecentBranches = m.openBranches
 		m.openRecentLocked = m.openLockedBranches
 		return m, nil
@@ -326,17 +435,79 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 		return m, nil
+	case diffPreviewLoadedMsg:
+		return m.applyDiffPreview(msg), nil
+	case pendingDeleteDueMsg:
+		return m.runDueDelete(msg.id)
+	case bulkActionDoneMsg:
+		return m.applyBulkActionDone(msg)
+	case noteSavedMsg:
+		return m.applyNoteSaved(msg), nil
+	case prPushedMsg:
+		return m.applyPRPushed(msg)
+	case prCreatedMsg:
+		m.warnMsg = ""
+		if msg.err != nil {
+			m = m.setError(msg.err)
+			return m, nil
+		}
+		return m.forceRefresh()
+	case pinSavedMsg:
+		return m.applyPinSaved(msg), nil
+	case ghAuthDoneMsg:
+		if msg.err != nil {
+			m = m.setError(msg.err)
+			return m, nil
+		}
+		if msg.inPane {
+			m.warnMsg = fmt.Sprintf("Finish gh auth in the pane below, then press %s to refresh.", m.keys.label(keyActionRefresh))
+			return m, nil
+		}
+		return m.forceRefresh()
+	case gcDoneMsg:
+		if msg.err != nil {
+			core.DebugLog("startup gc failed", "err", msg.err.Error())
+			return m, nil
+		}
+		if summary := gcSummary(msg.findings); summary != "" {
+			m.warnMsg = summary
+			return m, fetchStatusCmd(m.orchestrator)
+		}
+		return m, nil
+	case backgroundFetchTickMsg:
+		return m, backgroundFetchCmd(m.mgr)
+	case backgroundFetchedMsg:
+		m.fetchErr = msg.err
+		if msg.fetched {
+			return m, tea.Batch(fetchStatusCmd(m.orchestrator), backgroundFetchTickCmd())
+		}
+		return m, backgroundFetchTickCmd()
+	case testsStartedMsg:
+		if msg.err != nil {
+			m = m.setError(msg.err)
+			return m, nil
+		}
+		return m, fetchStatusCmd(m.orchestrator)
+	case worktreesRepairedMsg:
+		if msg.err != nil {
+			m = m.setError(msg.err)
+			return m, nil
+		}
+		return m, fetchStatusCmd(m.orchestrator)
 	case openDeleteWorktreeDoneMsg:
 		if msg.err != nil {
-			m.errMsg = msg.err.Error()
+			m = m.setError(msg.err)
 			return m, nil
 		}
 		m.errMsg = ""
+		if msg.warning != "" {
+			m.warnMsg = msg.warning
+		}
 		m.openLoading = true
 		return m, tea.Batch(loadOpenScreenCmd(m.orchestrator, m.mgr), m.ghSpinner.Tick)
 	case openUnlockWorktreeDoneMsg:
 		if msg.err != nil {
-			m.errMsg = msg.err.Error()
+			m = m.setError(msg.err)
 			return m, nil
 		}
 		m.errMsg = ""
@@ -344,10 +515,13 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, tea.Batch(loadOpenScreenCmd(m.orchestrator, m.mgr), m.ghSpinner.Tick)
 	case openCreateWorktreeDoneMsg:
 		if msg.err != nil {
-			m.errMsg = msg.err.Error()
+			m = m.setError(msg.err)
 			return m, nil
 		}
 		m.errMsg = ""
+		if msg.created.HookWarning != "" {
+			m.warnMsg = msg.created.HookWarning
+		}
 		m.openLoading = true
 		m.openDebugCreating = false
 		m.newBranchInput.Blur()
@@ -357,8 +531,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.openCreating = false
 		m.openCreatingStartedAt = time.Time{}
 		if msg.err != nil {
-			m.errMsg = msg.err.Error()
-			return m, nil
+			return m.offerStash(msg.err)
 		}
 		m.errMsg = ""
 		m.warnMsg = ""
@@ -366,17 +539,21 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.pendingBranch = msg.branch
 		m.pendingOpenShell = msg.openShell
 		m.pendingLock = msg.lock
+		m.pendingWarning = msg.warning
 		return m, tea.Quit
 	case openDefaultsSavedMsg:
 		if msg.err != nil {
-			m.errMsg = msg.err.Error()
+			m = m.setError(msg.err)
 		}
 		return m, nil
 	case statusMsg:
-		m.status = WorktreeStatus(msg)
-		m.listIndex = clampListIndex(m.listIndex, m.status)
+		m.status = core.WorktreeStatus(msg)
+		if m.resolvedBaseRef != "" {
+			m.status.BaseRef = m.resolvedBaseRef
+		}
+		m.listIndex = clampListIndex(m.listIndex, m.listStatus())
 		if m.autoActionPath != "" {
-			if idx, wt, ok := findWorktreeByPath(m.status, m.autoActionPath); ok {
+			if idx, wt, ok := findWorktreeByPath(m.listStatus(), m.autoActionPath); ok {
 				m.listIndex = idx
 				m.mode = modeAction
 				m.actionCreate = false
@@ -389,13 +566,21 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		key := ghDataKeyForStatus(m.status)
 		if key == "" {
 			m.ghPendingByBranch = map[string]bool{}
-			m.ghDataByBranch = map[string]PRData{}
+			m.ghCovered = nil
+			m.ghDataByBranch = map[string]core.PRData{}
 			m.ghLoadedKey = ""
 			m.ghFetchingKey = ""
 			m.ghWarnMsg = ""
+			m.ghAuth = nil
+			m.ghStale = false
 			return m, nil
 		}
 		applyPRDataToStatus(&m.status, m.ghDataByBranch)
+		wasLoading := len(m.ghPendingByBranch) > 0
+		m.ghPendingByBranch = uncoveredBranches(m.status, m.ghCovered)
+		if !wasLoading && len(m.ghPendingByBranch) > 0 {
+			return m, m.ghSpinner.Tick
+		}
 		return m, nil
 	case pollGHTickMsg:
 		if m.mode != modeList && m.mode != modeOpen {
@@ -405,8 +590,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if key == "" || key == m.ghFetchingKey {
 			return m, pollGHTickCmd()
 		}
+		if key == m.ghLoadedKey && !m.forceGHRefresh && time.Since(m.ghAttemptedAt) < m.ghRefreshInterval {
+			return m, pollGHTickCmd()
+		}
+		m.ghAttemptedAt = time.Now()
 		m.ghFetchingKey = key
-		m.ghPendingByBranch = pendingBranchesByName(m.status)
+		m.ghPendingByBranch = uncoveredBranches(m.status, m.ghCovered)
 		force := m.forceGHRefresh
 		m.forceGHRefresh = false
 		cmd := fetchGHDataCmd(m.orchestrator, m.status, key, force)
@@ -426,12 +615,46 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		m.ghWarnMsg = ghWarningFromErr(msg.err)
-		m.ghDataByBranch = msg.byBranch
+		if m.ghAuth, _ = core.AsGHAuthError(msg.err); m.ghAuth != nil {
+			m.ghWarnMsg = ghAuthWarning(m.keys, m.ghAuth)
+		}
+		if msg.err != nil && msg.key == m.ghLoadedKey && len(m.ghDataByBranch) > 0 {
+			// Keep the last good data on screen, flagged as stale, rather than
+			// dropping it or passing it off as current.
+			merged := make(map[string]core.PRData, len(m.ghDataByBranch))
+			for branch, data := range m.ghDataByBranch {
+				merged[branch] = data
+			}
+			for branch, data := range msg.byBranch {
+				merged[branch] = data
+			}
+			m.ghDataByBranch = merged
+			m.ghStale = true
+		} else {
+			m.ghDataByBranch = msg.byBranch
+			m.ghStale = false
+		}
+		if msg.err == nil {
+			m.ghUpdatedAt = time.Now()
+		}
 		applyPRDataToStatus(&m.status, m.ghDataByBranch)
-		m.ghPendingByBranch = map[string]bool{}
+		covered := make(map[string]bool, len(m.ghCovered)+len(msg.branches))
+		for branch := range m.ghCovered {
+			covered[branch] = true
+		}
+		for branch := range msg.branches {
+			covered[branch] = true
+		}
+		m.ghCovered = covered
+		m.ghPendingByBranch = uncoveredBranches(m.status, m.ghCovered)
 		m.ghLoadedKey = msg.key
 		m.ghFetchingKey = ""
-		m.listIndex = clampListIndex(m.listIndex, m.status)
+		m.listIndex = clampListIndex(m.listIndex, m.listStatus())
+		return m, prMergedHooksCmd(m.status, msg.byBranch)
+	case prMergedHooksMsg:
+		if msg.err != nil {
+			m.warnMsg = msg.err.Error()
+		}
 		return m, nil
 	case pollStatusTickMsg:
 		if m.mode == modeList {
@@ -454,10 +677,14 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.creatingStartedAt = time.Time{}
 		m.actionCreate = false
 		if msg.err != nil {
-			m.errMsg = msg.err.Error()
+			m = m.setError(msg.err)
 			return m, nil
 		}
 		m.errMsg = ""
+		if msg.created.HookWarning != "" {
+			m.warnMsg = msg.created.HookWarning
+		}
+		m.listFilter = ""
 		m.autoActionPath = strings.TrimSpace(msg.created.Path)
 		return m, fetchStatusCmd(m.orchestrator)
 	case spinner.TickMsg:
@@ -497,10 +724,39 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		if m.mode == modeOpen {
+			return m, m.requestVisibleOpenPRData()
+		}
 		return m, nil
 	case tea.KeyMsg:
+		if m.showHelp {
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			m.showHelp = false
+			return m, nil
+		}
+		if m.showErrorDetail {
+			return m.updateErrorDetail(msg)
+		}
+		if m.showIntegrations {
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			m.showIntegrations = false
+			return m, nil
+		}
+		if m.mode == modeList && m.errMsg != "" && m.confirmForm == nil && !m.listFiltering && m.keys.translateTableKey(msg.String()) == "e" {
+			m.showErrorDetail = true
+			m.errorDetailNote = ""
+			return m, nil
+		}
+		if msg.String() == helpKey && canShowHelp(m) {
+			m.showHelp = true
+			return m, nil
+		}
 		if m.mode == modeOpen {
-			switch msg.String() {
+			switch m.keys.translateTableKey(msg.String()) {
 			case "q", "ctrl+c":
 				return m, tea.Quit
 			case "ctrl+d":
@@ -523,7 +779,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 							return m, nil
 						}
 						m.errMsg = ""
-						return m, createOpenWorktreeCmd(m.mgr, branch, resolveNewBranchBaseRef(m.openDefaultBaseRef, m.status.BaseRef, m.status.HasRemote))
+						return m, createOpenWorktreeCmd(m.mgr, branch, resolveNewBranchBaseRef(m.openDefaultBaseRef, m.status.BaseRef, m.status.HasRemote), nil)
 					case tea.KeyEsc:
 						m.openDebugCreating = false
 						m.newBranchInput.Blur()
@@ -538,7 +794,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 							return m, nil
 						}
 						m.errMsg = ""
-						return m, createOpenWorktreeCmd(m.mgr, branch, resolveNewBranchBaseRef(m.openDefaultBaseRef, m.status.BaseRef, m.status.HasRemote))
+						return m, createOpenWorktreeCmd(m.mgr, branch, resolveNewBranchBaseRef(m.openDefaultBaseRef, m.status.BaseRef, m.status.HasRemote), nil)
 					}
 					var cmd tea.Cmd
 					m.newBranchInput, cmd = m.newBranchInput.Update(msg)
@@ -645,6 +901,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					if m.openPickIndex == 0 {
 						m.openCreating = true
 						m.openCreatingStartedAt = time.Now()
+						m.createLog = newProgressLog()
 						return m, tea.Batch(m.spinner.Tick, openCmdForCreateTarget(m))
 					}
 					slot, ok := selectedOpenDebugSlot(m.openSlots, m.openPickIndex-1)
@@ -674,6 +931,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 					m.openCreating = true
 					m.openCreatingStartedAt = time.Now()
+					m.createLog = newProgressLog()
 					return m, tea.Batch(m.spinner.Tick, openCmdForTargetOnSlot(m, slot))
 				}
 				return m, nil
@@ -688,11 +946,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "up":
 				filtered := openFilteredIndices(m.openTypeahead, m.openBranches)
 				m.openSelected = moveOpenSelection(m.openSelected, -1, filtered)
-				return m, nil
+				return m, m.requestVisibleOpenPRData()
 			case "down":
 				filtered := openFilteredIndices(m.openTypeahead, m.openBranches)
 				m.openSelected = moveOpenSelection(m.openSelected, 1, filtered)
-				return m, nil
+				return m, m.requestVisibleOpenPRData()
 			case "enter":
 				if m.openSelected == 0 {
 					defaultBase := resolveNewBranchBaseRef(m.openDefaultBaseRef, m.status.BaseRef, m.status.HasRemote)
@@ -702,7 +960,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.openStage = openStageNewBranchConfig
 					m.openFormBranchPtr = &branch
 					m.openFormBaseRefPtr = &baseRef
-					m.openFormFetchPtr = &fetch
+					m.openFormFetchPtr = nil
+					if m.status.HasRemote {
+						m.openFormFetchPtr = &fetch
+					}
 					m.openNewBranchForm = newOpenNewBranchForm(m.openFormBranchPtr, m.openFormBaseRefPtr, m.openFormFetchPtr)
 					m.openTypeahead = ""
 					m.errMsg = ""
@@ -761,7 +1022,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.openSelected = filtered[0] + 1
 				}
 				m.errMsg = ""
-				return m, nil
+				return m, m.requestVisibleOpenPRData()
 			case tea.KeyBackspace, tea.KeyDelete:
 				if m.openTypeahead == "" {
 					return m, nil
@@ -788,7 +1049,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.openSelected = filtered[0] + 1
 				}
 				m.errMsg = ""
-				return m, nil
+				return m, m.requestVisibleOpenPRData()
 			}
 			return m, nil
 		}
@@ -822,20 +1083,19 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m, nil
 				}
 				if !m.actionCreate {
-					row, ok := selectedWorktree(m.status, m.listIndex)
+					row, ok := selectedWorktree(m.listStatus(), m.listIndex)
 					if !ok {
 						m.errMsg = "No worktree selected."
 						return m, nil
 					}
 					lock, err := m.mgr.AcquireWorktreeLock(row.Path)
 					if err != nil {
-						m.errMsg = err.Error()
+						m = m.setError(err)
 						return m, nil
 					}
-					if err := m.mgr.CheckoutNewBranch(row.Path, branch, resolveNewBranchBaseRef(m.openDefaultBaseRef, m.status.BaseRef, m.status.HasRemote), m.openDefaultFetch); err != nil {
+					if err := m.mgr.CheckoutNewBranch(row.Path, branch, resolveNewBranchBaseRef(m.openDefaultBaseRef, m.status.BaseRef, m.status.HasRemote), m.openDefaultFetch, nil); err != nil {
 						lock.Release()
-						m.errMsg = err.Error()
-						return m, nil
+						return m.offerStash(err)
 					}
 					m.errMsg = ""
 					m.warnMsg = ""
@@ -850,12 +1110,13 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.creatingBaseRef = resolveNewBranchBaseRef(m.openDefaultBaseRef, m.status.BaseRef, m.status.HasRemote)
 				m.creatingExisting = false
 				m.creatingStartedAt = time.Now()
+				m.createLog = newProgressLog()
 				m.newBranchInput.Blur()
 				m.newBranchInput.SetValue("")
 				m.errMsg = ""
 				return m, tea.Batch(
 					m.spinner.Tick,
-					createWorktreeCmd(m.mgr, branch, resolveNewBranchBaseRef(m.openDefaultBaseRef, m.status.BaseRef, m.status.HasRemote)),
+					createWorktreeCmd(m.mgr, branch, resolveNewBranchBaseRef(m.openDefaultBaseRef, m.status.BaseRef, m.status.HasRemote), m.createLog.writer()),
 				)
 			}
 			switch msg.String() {
@@ -872,20 +1133,19 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m, nil
 				}
 				if !m.actionCreate {
-					row, ok := selectedWorktree(m.status, m.listIndex)
+					row, ok := selectedWorktree(m.listStatus(), m.listIndex)
 					if !ok {
 						m.errMsg = "No worktree selected."
 						return m, nil
 					}
 					lock, err := m.mgr.AcquireWorktreeLock(row.Path)
 					if err != nil {
-						m.errMsg = err.Error()
+						m = m.setError(err)
 						return m, nil
 					}
-					if err := m.mgr.CheckoutNewBranch(row.Path, branch, resolveNewBranchBaseRef(m.openDefaultBaseRef, m.status.BaseRef, m.status.HasRemote), m.openDefaultFetch); err != nil {
+					if err := m.mgr.CheckoutNewBranch(row.Path, branch, resolveNewBranchBaseRef(m.openDefaultBaseRef, m.status.BaseRef, m.status.HasRemote), m.openDefaultFetch, nil); err != nil {
 						lock.Release()
-						m.errMsg = err.Error()
-						return m, nil
+						return m.offerStash(err)
 					}
 					m.errMsg = ""
 					m.warnMsg = ""
@@ -900,12 +1160,13 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.creatingBaseRef = resolveNewBranchBaseRef(m.openDefaultBaseRef, m.status.BaseRef, m.status.HasRemote)
 				m.creatingExisting = false
 				m.creatingStartedAt = time.Now()
+				m.createLog = newProgressLog()
 				m.newBranchInput.Blur()
 				m.newBranchInput.SetValue("")
 				m.errMsg = ""
 				return m, tea.Batch(
 					m.spinner.Tick,
-					createWorktreeCmd(m.mgr, branch, resolveNewBranchBaseRef(m.openDefaultBaseRef, m.status.BaseRef, m.status.HasRemote)),
+					createWorktreeCmd(m.mgr, branch, resolveNewBranchBaseRef(m.openDefaultBaseRef, m.status.BaseRef, m.status.HasRemote), m.createLog.writer()),
 				)
 			}
 			var cmd tea.Cmd
@@ -942,7 +1203,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					if m.actionIndex == 1 {
 						options, err := availableBranchOptions(m.status, m.mgr, true)
 						if err != nil {
-							m.errMsg = err.Error()
+							m = m.setError(err)
 							return m, nil
 						}
 						m.mode = modeBranchPick
@@ -964,7 +1225,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if m.actionIndex == 2 {
 					options, err := availableBranchOptions(m.status, m.mgr, false)
 					if err != nil {
-						m.errMsg = err.Error()
+						m = m.setError(err)
 						return m, nil
 					}
 					m.mode = modeBranchPick
@@ -976,7 +1237,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m, nil
 				}
 				if m.actionIndex == 3 {
-					if row, ok := selectedWorktree(m.status, m.listIndex); ok {
+					if row, ok := selectedWorktree(m.listStatus(), m.listIndex); ok {
 						m.errMsg = ""
 						m.warnMsg = ""
 						m.pendingPath = row.Path
@@ -987,12 +1248,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 				}
 				if m.actionIndex == 0 {
-					if row, ok := selectedWorktree(m.status, m.listIndex); ok {
+					if row, ok := selectedWorktree(m.listStatus(), m.listIndex); ok {
 						m.errMsg = ""
 						m.warnMsg = ""
 						lock, err := m.mgr.AcquireWorktreeLock(row.Path)
 						if err != nil {
-							m.errMsg = err.Error()
+							m = m.setError(err)
 							return m, nil
 						}
 						m.pendingPath = row.Path
@@ -1039,7 +1300,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					if wt, reusable, reason := reusableWorktreeForBranch(m.status, branch); reusable {
 						lock, err := m.mgr.AcquireWorktreeLock(wt.Path)
 						if err != nil {
-							m.errMsg = err.Error()
+							m = m.setError(err)
 							return m, nil
 						}
 						m.errMsg = ""
@@ -1058,13 +1319,14 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.creatingBaseRef = ""
 					m.creatingExisting = true
 					m.creatingStartedAt = time.Now()
+					m.createLog = newProgressLog()
 					m.branchInput.Blur()
 					m.branchSuggestions = nil
 					m.branchIndex = 0
 					m.errMsg = ""
 					return m, tea.Batch(
 						m.spinner.Tick,
-						createWorktreeFromExistingCmd(m.mgr, branch),
+						createWorktreeFromExistingCmd(m.mgr, branch, m.createLog.writer()),
 					)
 				}
 				branch, ok := selectedBranch(m.branchSuggestions, m.branchIndex)
@@ -1072,20 +1334,19 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.errMsg = "Select an existing branch."
 					return m, nil
 				}
-				row, ok := selectedWorktree(m.status, m.listIndex)
+				row, ok := selectedWorktree(m.listStatus(), m.listIndex)
 				if !ok {
 					m.errMsg = "No worktree selected."
 					return m, nil
 				}
 				lock, err := m.mgr.AcquireWorktreeLock(row.Path)
 				if err != nil {
-					m.errMsg = err.Error()
+					m = m.setError(err)
 					return m, nil
 				}
-				if err := m.mgr.CheckoutExistingBranch(row.Path, branch); err != nil {
+				if err := m.mgr.CheckoutExistingBranch(row.Path, branch, nil); err != nil {
 					lock.Release()
-					m.errMsg = err.Error()
-					return m, nil
+					return m.offerStash(err)
 				}
 				m.errMsg = ""
 				m.warnMsg = ""
@@ -1103,31 +1364,99 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, cmd
 		}
-		switch msg.String() {
+		if m.mode == modeBulkAction {
+			return m.updateBulkMenu(msg)
+		}
+		if m.mode == modePreview {
+			return m.updateDiffPreview(msg)
+		}
+		if m.mode == modeNote {
+			return m.updateNoteEdit(msg)
+		}
+		if m.listFiltering {
+			return m.updateListFilter(msg)
+		}
+		if len(m.markedWorktrees()) > 0 {
+			switch m.keys.translateTableKey(msg.String()) {
+			case "enter":
+				m.mode = modeBulkAction
+				m.bulkIndex = 0
+				m.errMsg = ""
+				return m, nil
+			case "d":
+				return m.startBulkConfirm(bulkDelete)
+			case "u":
+				return m.startBulkConfirm(bulkUnlock)
+			}
+		}
+		switch m.keys.translateTableKey(msg.String()) {
 		case "q", "ctrl+c":
 			return m, tea.Quit
+		case "S":
+			// Keep the same worktree selected across the reorder.
+			selected, hadSelection := selectedWorktree(m.listStatus(), m.listIndex)
+			m.listSort = m.listSort.Next()
+			if hadSelection {
+				if idx, _, ok := findWorktreeByPath(m.listStatus(), selected.Path); ok {
+					m.listIndex = idx
+				}
+			}
+			return m, nil
+		case "/":
+			m.listFiltering = true
+			m.errMsg = ""
+			return m, nil
+		case "esc":
+			if m.listFilter != "" {
+				m.listFilter = ""
+				m.listIndex = clampListIndex(m.listIndex, m.listStatus())
+			} else {
+				m.listMarked = nil
+			}
+			return m, nil
+		case "z":
+			return m.undoLastDelete(), nil
+		case "R":
+			m.errMsg = ""
+			return m, repairWorktreesCmd(m.mgr)
+		case markKey:
+			m = m.toggleMark()
+			m.errMsg = ""
+			return m, nil
 		case "r":
-			// Force refresh on demand, including GH enrichment on next status update.
-			m.ghLoadedKey = ""
-			m.ghFetchingKey = ""
-			m.ghPendingByBranch = map[string]bool{}
-			m.ghDataByBranch = map[string]PRData{}
-			m.ghWarnMsg = ""
-			m.forceGHRefresh = true
-			return m, fetchStatusCmd(m.orchestrator)
+			return m.forceRefresh()
+		case "a":
+			if m.ghAuth == nil {
+				return m, nil
+			}
+			m.errMsg = ""
+			return m, runGHAuthFixCmd(m.status.RepoRoot, m.ghAuth)
+		case "i":
+			m.showIntegrations = len(m.integrationIssues()) > 0
+			return m, nil
+		case "t":
+			return m.runTests()
+		case "c":
+			return m.startCreatePR()
+		case "y":
+			return m.copySelected(copyPath)
+		case "b":
+			return m.copySelected(copyBranch)
+		case "U":
+			return m.copySelected(copyPRURL)
 		case "up", "k":
 			if m.listIndex > 0 {
 				m.listIndex--
 			}
 			return m, nil
 		case "down", "j":
-			maxIndex := selectorRowCount(m.status) - 1
+			maxIndex := selectorRowCount(m.listStatus()) - 1
 			if m.listIndex < maxIndex {
 				m.listIndex++
 			}
 			return m, nil
 		case "enter":
-			if isCreateRow(m.listIndex, m.status) {
+			if isCreateRow(m.listIndex, m.listStatus()) {
 				m.mode = modeAction
 				m.actionCreate = true
 				m.actionBranch = ""
@@ -1135,11 +1464,15 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.errMsg = ""
 				return m, nil
 			}
-			if row, ok := selectedWorktree(m.status, m.listIndex); ok {
+			if row, ok := selectedWorktree(m.listStatus(), m.listIndex); ok {
 				if isOrphanedPath(m.status, row.Path) {
 					m.errMsg = "Cannot open actions for orphaned worktree."
 					return m, nil
 				}
+				if row.Broken != "" {
+					m.errMsg = brokenWorktreeMessage(m.keys, row)
+					return m, nil
+				}
 				if !row.Available {
 					m.errMsg = "Worktree is currently in use."
 					return m, nil
@@ -1152,11 +1485,15 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 		case "s":
-			if row, ok := selectedWorktree(m.status, m.listIndex); ok {
+			if row, ok := selectedWorktree(m.listStatus(), m.listIndex); ok {
 				if isOrphanedPath(m.status, row.Path) {
 					m.errMsg = "Cannot open shell for orphaned worktree."
 					return m, nil
 				}
+				if row.Broken != "" {
+					m.errMsg = brokenWorktreeMessage(m.keys, row)
+					return m, nil
+				}
 				m.errMsg = ""
 				m.warnMsg = ""
 				m.pendingPath = row.Path
@@ -1166,9 +1503,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, tea.Quit
 			}
 		case "d":
-			if row, ok := selectedWorktree(m.status, m.listIndex); ok {
+			if row, ok := selectedWorktree(m.listStatus(), m.listIndex); ok {
 				if err := m.mgr.CanDeleteWorktree(row.Path); err != nil {
-					m.errMsg = err.Error()
+					m = m.setError(err)
 					return m, nil
 				}
 				m.mode = modeDelete
@@ -1184,21 +1521,27 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.errMsg = ""
 				return m, m.confirmForm.Init()
 			}
-		case "p", "P":
-			if row, ok := selectedWorktree(m.status, m.listIndex); ok {
+		case "p":
+			return m.openDiffPreview()
+		case "n":
+			return m.startNoteEdit()
+		case "f":
+			return m.togglePin()
+		case "P":
+			if row, ok := selectedWorktree(m.listStatus(), m.listIndex); ok {
 				if strings.TrimSpace(row.PRURL) == "" {
 					m.errMsg = "No PR URL for selected worktree."
 					return m, nil
 				}
 				if err := m.runner.OpenURL(row.PRURL); err != nil {
-					m.errMsg = err.Error()
+					m = m.setError(err)
 					return m, nil
 				}
 				m.errMsg = ""
 				return m, nil
 			}
 		case "u":
-			if row, ok := selectedWorktree(m.status, m.listIndex); ok {
+			if row, ok := selectedWorktree(m.listStatus(), m.listIndex); ok {
 				if isOrphanedPath(m.status, row.Path) {
 					m.errMsg = "Cannot unlock orphaned worktree."
 					return m, nil
@@ -1236,18 +1579,26 @@ func (m model) handleConfirmDone() (tea.Model, tea.Cmd) {
 	case confirmDelete:
 		m.mode = modeList
 		path := m.deletePath
+		branch := m.deleteBranch
 		m.deletePath = ""
 		m.deleteBranch = ""
 		m.errMsg = ""
 		if !confirmed {
 			return m, nil
 		}
-		force := isOrphanedPath(m.status, path)
-		if err := m.mgr.DeleteWorktree(path, force); err != nil {
-			m.errMsg = err.Error()
+		return m.scheduleDelete([]core.WorktreeInfo{{Path: path, Branch: branch}})
+	case confirmBulk:
+		targets := m.bulkTargets
+		m.bulkTargets = nil
+		m.errMsg = ""
+		if !confirmed {
 			return m, nil
 		}
-		return m, fetchStatusCmd(m.orchestrator)
+		if m.bulkAction == bulkDelete {
+			return m.scheduleDelete(targets)
+		}
+		m.bulkRunning = true
+		return m, bulkActionCmd(m.mgr, m.runner, m.status, m.bulkAction, targets)
 	case confirmUnlock:
 		m.mode = modeList
 		path := m.unlockPath
@@ -1258,10 +1609,17 @@ func (m model) handleConfirmDone() (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		if err := m.mgr.UnlockWorktree(path); err != nil {
-			m.errMsg = err.Error()
+			m = m.setError(err)
 			return m, nil
 		}
 		return m, fetchStatusCmd(m.orchestrator)
+	case confirmStashSwitch:
+		dirty := m.stashPending
+		m.stashPending = nil
+		if !confirmed || dirty == nil {
+			return m, nil
+		}
+		return m, stashAndCheckoutCmd(m.mgr, dirty, nil)
 	case confirmOpenDebugDelete:
 		path := m.openPickConfirmPath
 		m.openPickConfirmPath = ""
@@ -1286,7 +1644,7 @@ func (m model) handleConfirmDone() (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		if err := m.mgr.UnlockWorktree(path); err != nil {
-			m.errMsg = err.Error()
+			m = m.setError(err)
 			return m, nil
 		}
 		if slot, ok := findOpenSlotByPath(m.openSlots, path); ok && slot.Dirty {
@@ -1300,6 +1658,7 @@ func (m model) handleConfirmDone() (tea.Model, tea.Cmd) {
 		if slot, ok := findOpenSlotByPath(m.openSlots, path); ok {
 			m.openCreating = true
 			m.openCreatingStartedAt = time.Now()
+			m.createLog = newProgressLog()
 			return m, tea.Batch(m.spinner.Tick, openCmdForTargetOnSlot(m, slot))
 		}
 		m.openLoading = true
@@ -1310,7 +1669,7 @@ func (m model) handleConfirmDone() (tea.Model, tea.Cmd) {
 			m.openDefaultBaseRef = strings.TrimSpace(m.openTargetBaseRef)
 			saveCmd = saveOpenDefaultsCmd(m.openDefaultBaseRef, m.openDefaultFetch)
 		}
-		if shouldPromptFetchDefault(m.openTargetBaseRef, m.openTargetFetch, m.openDefaultFetch) {
+		if m.status.HasRemote && shouldPromptFetchDefault(m.openTargetBaseRef, m.openTargetFetch, m.openDefaultFetch) {
 			m.confirmResult = false
 			m.confirmKind = confirmOpenFetchDefault
 			m.confirmForm = newConfirmForm(
@@ -1394,7 +1753,7 @@ func (m model) submitOpenNewBranchForm() (tea.Model, tea.Cmd) {
 	if strings.TrimSpace(base) == "" {
 		base = resolveNewBranchBaseRef("", m.status.BaseRef, m.status.HasRemote)
 	}
-	fetch = normalizeFetchForBaseRef(base, fetch)
+	fetch = m.status.HasRemote && normalizeFetchForBaseRef(base, fetch)
 	m.openTargetBranch = branch
 	m.openTargetIsNew = true
 	m.openTargetBaseRef = base
@@ -1415,7 +1774,7 @@ func (m model) submitOpenNewBranchForm() (tea.Model, tea.Cmd) {
 		)
 		return m, m.confirmForm.Init()
 	}
-	if shouldPromptFetchDefault(m.openTargetBaseRef, m.openTargetFetch, m.openDefaultFetch) {
+	if m.status.HasRemote && shouldPromptFetchDefault(m.openTargetBaseRef, m.openTargetFetch, m.openDefaultFetch) {
 		m.confirmResult = false
 		m.confirmKind = confirmOpenFetchDefault
 		m.confirmForm = newConfirmForm(
@@ -1454,6 +1813,7 @@ func (m model) continueOpenTargetSelection(saveCmd tea.Cmd) (tea.Model, tea.Cmd)
 	if slot, ok := m.orchestrator.ResolveOpenTargetSlot(m.openSlots, m.openTargetBranch, m.openTargetIsNew); ok {
 		m.openCreating = true
 		m.openCreatingStartedAt = time.Now()
+		m.createLog = newProgressLog()
 		cmds := []tea.Cmd{m.spinner.Tick, openCmdForTargetOnSlot(m, slot)}
 		if saveCmd != nil {
 			cmds = append([]tea.Cmd{saveCmd}, cmds...)
@@ -1508,21 +1868,34 @@ func syncTabTitleWithSelection(m model) {
 		setITermWTXTab()
 		return
 	}
-	if wt, ok := selectedWorktree(m.status, m.listIndex); ok {
+	if wt, ok := selectedWorktree(m.listStatus(), m.listIndex); ok {
 		setITermWTXBranchTab(wt.Branch)
 		return
 	}
 	setITermWTXTab()
 }
 func (m model) View() string {
+	if m.showHelp {
+		return renderHelpOverlay(m.keys, helpSectionForMode(m.mode))
+	}
+	if m.showErrorDetail {
+		return renderErrorDetail(m)
+	}
+	if m.showIntegrations {
+		return renderIntegrationDetails(m)
+	}
 	var b strings.Builder
 	showTopBar := m.ready && m.status.InRepo && m.mode == modeList
 	if showTopBar {
-		b.WriteString(renderViewHeader())
+		b.WriteString(renderViewHeader(m.listSort, renderPRFreshness(m, time.Now())))
 		b.WriteString("\n\n")
 	}
 
 	if !m.ready {
+		if m.mode == modeOpen {
+			b.WriteString(renderOpenScreenSkeleton(m))
+			return b.String()
+		}
 		b.WriteString("Loading...\n")
 		return b.String()
 	}
@@ -1532,7 +1905,7 @@ func (m model) View() string {
 		b.WriteString("\n")
 		b.WriteString("Install git to use wtx.\n")
 		b.WriteString("\n")
-		b.WriteString("Press q to quit.\n")
+		b.WriteString(fmt.Sprintf("Press %s to quit.\n", m.keys.label(keyActionQuit)))
 		return b.String()
 	}
 
@@ -1543,7 +1916,7 @@ func (m model) View() string {
 			b.WriteString(fmt.Sprintf("CWD: %s\n", m.status.CWD))
 		}
 		b.WriteString("\n")
-		b.WriteString("Press q to quit.\n")
+		b.WriteString(fmt.Sprintf("Press %s to quit.\n", m.keys.label(keyActionQuit)))
 		return b.String()
 	}
 
@@ -1552,11 +1925,31 @@ func (m model) View() string {
 		return b.String()
 	}
 
+	if m.prForm != nil {
+		b.WriteString(renderPRForm(m))
+		return b.String()
+	}
+
 	if m.mode == modeOpen {
 		b.WriteString(renderOpenScreen(m))
 		return b.String()
 	}
 
+	if m.mode == modeBulkAction {
+		b.WriteString(renderBulkMenu(m))
+		return b.String()
+	}
+
+	if m.mode == modePreview {
+		b.WriteString(renderDiffPreview(m))
+		return b.String()
+	}
+
+	if m.mode == modeNote {
+		b.WriteString(renderNoteEdit(m))
+		return b.String()
+	}
+
 	if m.mode == modeAction {
 		title := "Worktree actions:"
 		if m.actionCreate {
@@ -1612,14 +2005,29 @@ func (m model) View() string {
 		b.WriteString("\nPress enter to select, esc to cancel.\n")
 		return b.String()
 	}
-	b.WriteString(baseStyle.Render(renderSelector(m.status, m.listIndex, m.ghPendingByBranch, m.ghSpinner.View())))
+	b.WriteString(baseStyle.Render(renderSelector(m.listStatus(), m.listIndex, m.listMarked, m.ghStale, m.width, m.ghPendingByBranch, m.ghSpinner.View())))
 	b.WriteString("\n")
+	if filterLine := renderListFilter(m); filterLine != "" {
+		b.WriteString(filterLine)
+		b.WriteString("\n")
+	}
 	if m.status.Err != nil {
 		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.status.Err)))
 		b.WriteString("\n")
 	}
 	if m.errMsg != "" {
 		b.WriteString(errorStyle.Render(m.errMsg))
+		if m.mode == modeList {
+			b.WriteString(secondaryStyle.Render(fmt.Sprintf("  (%s for details)", m.keys.label(keyActionError))))
+		}
+		b.WriteString("\n")
+	}
+	if toast := renderPendingDeletes(m, time.Now()); toast != "" {
+		b.WriteString(toast)
+		b.WriteString("\n")
+	}
+	if m.bulkRunning {
+		b.WriteString(secondaryStyle.Render(fmt.Sprintf("Running %s...", strings.ToLower(m.bulkAction.label()))))
 		b.WriteString("\n")
 	}
 	if m.mode == modeCreating {
@@ -1628,6 +2036,7 @@ func (m model) View() string {
 		b.WriteString(" ")
 		b.WriteString(renderCreateProgress(m))
 		b.WriteString("\n")
+		b.WriteString(renderProgressLog(m.createLog, m.width))
 	}
 	if m.warnMsg != "" {
 		b.WriteString(warnStyle.Render(m.warnMsg))
@@ -1649,35 +2058,78 @@ func (m model) View() string {
 			b.WriteString("\n")
 		}
 	}
-	selectedPath := currentWorktreePath(m.status, m.listIndex)
+	selectedPath := currentWorktreePath(m.listStatus(), m.listIndex)
 	if selectedPath != "" {
 		b.WriteString("\n")
-		b.WriteString(secondaryStyle.Render(selectedPath))
+		b.WriteString(secondaryStyle.Render(truncatePathForWidth(selectedPath, m.width, 0)))
 		b.WriteString("\n")
+		if wt, ok := selectedWorktree(m.listStatus(), m.listIndex); ok {
+			if line := formatLockHolderLine(wt); line != "" && !wt.Available {
+				b.WriteString(secondaryStyle.Render(line))
+				b.WriteString("\n")
+			}
+			if wt.Note != "" {
+				b.WriteString(noteStyle.Render("Note: " + wt.Note))
+				b.WriteString("\n")
+			}
+		}
 	}
 
 	b.WriteString("\n")
-	help := "Press r to refresh, q to quit."
-	if m.mode == modeCreating {
+	keys := m.keys
+	tail := fmt.Sprintf("/ to filter, %s to sort, %s to refresh, ? for help, %s to quit.", keys.label(keyActionSort), keys.label(keyActionRefresh), keys.label(keyActionQuit))
+	help := "Press " + tail
+	if n := len(m.markedWorktrees()); n > 0 && !m.listFiltering {
+		help = fmt.Sprintf("%d marked. Press %s for bulk actions, %s to delete, %s to unlock, space to toggle, esc to clear marks.", n, keys.label(keyActionOpen), keys.label(keyActionDelete), keys.label(keyActionUnlock))
+	} else if m.listFiltering {
+		help = "Type to filter by branch, path or PR number. Enter keeps the filter, esc clears it."
+	} else if m.mode == modeCreating {
 		help = "Creating worktree..."
-	} else if isCreateRow(m.listIndex, m.status) {
-		help = "Press enter for actions, r to refresh, q to quit."
-	} else if wt, ok := selectedWorktree(m.status, m.listIndex); ok {
+	} else if isCreateRow(m.listIndex, m.listStatus()) {
+		help = fmt.Sprintf("Press %s for actions, %s", keys.label(keyActionOpen), tail)
+	} else if wt, ok := selectedWorktree(m.listStatus(), m.listIndex); ok {
 		prHint := ""
 		if strings.TrimSpace(wt.PRURL) != "" {
-			prHint = ", p to open PR"
+			prHint = fmt.Sprintf(", %s to open PR", keys.label(keyActionOpenPR))
+		} else if m.status.HasRemote && wt.Branch != "" && wt.Branch != "detached" {
+			prHint = fmt.Sprintf(", %s to create PR", keys.label(keyActionCreatePR))
 		}
 		if !wt.Available && !isOrphanedPath(m.status, wt.Path) {
-			help = "Press u to unlock, d to delete" + prHint + ", r to refresh, q to quit."
+			help = fmt.Sprintf("Press %s to unlock, %s to delete, %s to preview%s, %s", keys.label(keyActionUnlock), keys.label(keyActionDelete), keys.label(keyActionPreview), prHint, tail)
 		} else {
-			help = "Press enter for actions, s for shell, d to delete" + prHint + ", r to refresh, q to quit."
+			help = fmt.Sprintf("Press %s for actions, %s for shell, %s to preview, %s to delete%s, %s", keys.label(keyActionOpen), keys.label(keyActionShell), keys.label(keyActionPreview), keys.label(keyActionDelete), prHint, tail)
 		}
 	}
 	b.WriteString(help + "\n")
+	if footer := renderIntegrationFooter(m); footer != "" {
+		b.WriteString(footer + "\n")
+	}
 	return b.String()
 }
-func renderViewHeader() string {
-	return lipgloss.NewStyle().Foreground(lipgloss.Color("252")).Render("Worktrees")
+func renderViewHeader(sortMode core.ListSortMode, freshness string) string {
+	header := actionNormalStyle.Render("Worktrees")
+	header += secondaryStyle.Render("  sorted by " + sortMode.Label())
+	if freshness != "" {
+		header += "  " + freshness
+	}
+	return header
+}
+
+// renderPRFreshness says how old the PR, CI and review columns are, and warns
+// when they are left over from before a failed gh call. Repos without a
+// remote have no PR columns and are labeled local only.
+func renderPRFreshness(m model, now time.Time) string {
+	if !m.status.HasRemote {
+		return secondaryStyle.Render("local only")
+	}
+	if m.ghUpdatedAt.IsZero() {
+		return ""
+	}
+	age := core.FormatAgentDuration(now.Sub(m.ghUpdatedAt).Truncate(time.Second)) + " ago"
+	if m.ghStale {
+		return staleStyle.Render("PR data stale, updated " + age)
+	}
+	return secondaryStyle.Render("PR data updated " + age)
 }
 
 func renderCreateProgress(m model) string {
@@ -1709,53 +2161,73 @@ func shouldFetchByBranch(key string, loadedKey string, fetchingKey string) bool
 	return key != strings.TrimSpace(loadedKey) && key != strings.TrimSpace(fetchingKey)
 }
 
-type statusMsg WorktreeStatus
+type statusMsg core.WorktreeStatus
+
+// baseRefResolvedMsg carries the base ref for new branches once the
+// background GitHub default branch lookup settles.
+type baseRefResolvedMsg string
 type pollStatusTickMsg time.Time
 type pollGHTickMsg time.Time
 type openPickRefreshTickMsg time.Time
 type ghDataMsg struct {
-	repoRoot        string
-	key             string
-	byBranch        map[string]PRData
+	repoRoot string
+	key      string
+	byBranch map[string]core.PRData
+	// branches are the ones the fetch looked up, found or not.
+	branches        map[string]bool
 	fetchedByBranch bool
 	err             error
 }
 type createWorktreeDoneMsg struct {
-	created WorktreeInfo
+	created core.WorktreeInfo
 	err     error
 }
 type openDeleteWorktreeDoneMsg struct {
-	path string
-	err  error
+	path    string
+	warning string
+	err     error
 }
 type openUnlockWorktreeDoneMsg struct {
 	path string
 	err  error
 }
 type openCreateWorktreeDoneMsg struct {
-	created WorktreeInfo
+	created core.WorktreeInfo
 	err     error
 }
 type openUseReadyMsg struct {
 	path      string
 	branch    string
-	lock      *WorktreeLock
+	lock      *core.WorktreeLock
 	openShell bool
-	err       error
+	// warning is a post_create hook failure, shown once the TUI exits.
+	warning string
+	err     error
 }
 type openDefaultsSavedMsg struct {
 	err error
 }
 
-func fetchStatusCmd(orchestrator *WorktreeOrchestrator) tea.Cmd {
+func fetchStatusCmd(orchestrator *core.WorktreeOrchestrator) tea.Cmd {
 	return func() tea.Msg {
 		if orchestrator == nil {
-			return statusMsg(WorktreeStatus{})
+			return statusMsg(core.WorktreeStatus{})
 		}
 		return statusMsg(orchestrator.Status())
 	}
 }
 
+const baseRefResolveTimeout = 30 * time.Second
+
+func resolveBaseRefCmd(mgr *core.WorktreeManager) tea.Cmd {
+	return func() tea.Msg {
+		if mgr == nil {
+			return baseRefResolvedMsg("")
+		}
+		return baseRefResolvedMsg(mgr.AwaitBaseRef(baseRefResolveTimeout))
+	}
+}
+
 func pollStatusTickCmd() tea.Cmd {
 	return tea.Tick(2*time.Second, func(t time.Time) tea.Msg {
 		return pollStatusTickMsg(t)
@@ -1774,52 +2246,53 @@ func openPickRefreshTickCmd() tea.Cmd {
 	})
 }
 
-func fetchGHDataCmd(orchestrator *WorktreeOrchestrator, status WorktreeStatus, key string, force bool) tea.Cmd {
+func fetchGHDataCmd(orchestrator *core.WorktreeOrchestrator, status core.WorktreeStatus, key string, force bool) tea.Cmd {
 	return func() tea.Msg {
-		var byBranch map[string]PRData
+		var byBranch map[string]core.PRData
 		var byBranchErr error
 		if orchestrator == nil {
-			byBranch = map[string]PRData{}
+			byBranch = map[string]core.PRData{}
 		} else {
 			byBranch, byBranchErr = orchestrator.PRDataForStatusWithError(status, force)
 			if byBranch == nil {
-				byBranch = map[string]PRData{}
+				byBranch = map[string]core.PRData{}
 			}
 		}
 		return ghDataMsg{
 			repoRoot:        status.RepoRoot,
 			key:             key,
 			byBranch:        byBranch,
+			branches:        pendingBranchesByName(status),
 			fetchedByBranch: true,
 			err:             byBranchErr,
 		}
 	}
 }
-func createWorktreeCmd(mgr *WorktreeManager, branch string, baseRef string) tea.Cmd {
+func createWorktreeCmd(mgr *core.WorktreeManager, branch string, baseRef string, progress io.Writer) tea.Cmd {
 	return func() tea.Msg {
-		created, err := mgr.CreateWorktree(branch, baseRef)
+		created, err := mgr.CreateWorktree(branch, baseRef, progress)
 		return createWorktreeDoneMsg{created: created, err: err}
 	}
 }
 
-func createWorktreeFromExistingCmd(mgr *WorktreeManager, branch string) tea.Cmd {
+func createWorktreeFromExistingCmd(mgr *core.WorktreeManager, branch string, progress io.Writer) tea.Cmd {
 	return func() tea.Msg {
-		created, err := mgr.CreateWorktreeFromBranch(branch)
+		created, err := mgr.CreateWorktreeFromBranch(branch, progress)
 		return createWorktreeDoneMsg{created: created, err: err}
 	}
 }
 
-func deleteOpenWorktreeCmd(mgr *WorktreeManager, path string) tea.Cmd {
+func deleteOpenWorktreeCmd(mgr *core.WorktreeManager, path string) tea.Cmd {
 	return func() tea.Msg {
 		if mgr == nil {
 			return openDeleteWorktreeDoneMsg{path: path, err: fmt.Errorf("worktree manager unavailable")}
 		}
-		err := mgr.DeleteWorktree(path, false)
-		return openDeleteWorktreeDoneMsg{path: path, err: err}
+		warnings, err := mgr.DeleteWorktree(path, false)
+		return openDeleteWorktreeDoneMsg{path: path, warning: strings.Join(warnings, "; "), err: err}
 	}
 }
 
-func unlockOpenWorktreeCmd(mgr *WorktreeManager, path string) tea.Cmd {
+func unlockOpenWorktreeCmd(mgr *core.WorktreeManager, path string) tea.Cmd {
 	return func() tea.Msg {
 		if mgr == nil {
 			return openUnlockWorktreeDoneMsg{path: path, err: fmt.Errorf("worktree manager unavailable")}
@@ -1829,17 +2302,17 @@ func unlockOpenWorktreeCmd(mgr *WorktreeManager, path string) tea.Cmd {
 	}
 }
 
-func createOpenWorktreeCmd(mgr *WorktreeManager, branch string, baseRef string) tea.Cmd {
+func createOpenWorktreeCmd(mgr *core.WorktreeManager, branch string, baseRef string, progress io.Writer) tea.Cmd {
 	return func() tea.Msg {
 		if mgr == nil {
 			return openCreateWorktreeDoneMsg{err: fmt.Errorf("worktree manager unavailable")}
 		}
-		created, err := mgr.CreateWorktree(branch, baseRef)
+		created, err := mgr.CreateWorktree(branch, baseRef, progress)
 		return openCreateWorktreeDoneMsg{created: created, err: err}
 	}
 }
 
-func useExistingWorktreeCmd(mgr *WorktreeManager, path string, branch string) tea.Cmd {
+func useExistingWorktreeCmd(mgr *core.WorktreeManager, path string, branch string) tea.Cmd {
 	return func() tea.Msg {
 		lock, err := mgr.AcquireWorktreeLock(path)
 		if err != nil {
@@ -1849,13 +2322,13 @@ func useExistingWorktreeCmd(mgr *WorktreeManager, path string, branch string) te
 	}
 }
 
-func checkoutExistingInWorktreeCmd(mgr *WorktreeManager, path string, branch string) tea.Cmd {
+func checkoutExistingInWorktreeCmd(mgr *core.WorktreeManager, path string, branch string, progress io.Writer) tea.Cmd {
 	return func() tea.Msg {
 		lock, err := mgr.AcquireWorktreeLock(path)
 		if err != nil {
 			return openUseReadyMsg{err: err}
 		}
-		if err := mgr.CheckoutExistingBranch(path, branch); err != nil {
+		if err := mgr.CheckoutExistingBranch(path, branch, progress); err != nil {
 			lock.Release()
 			return openUseReadyMsg{err: err}
 		}
@@ -1863,13 +2336,13 @@ func checkoutExistingInWorktreeCmd(mgr *WorktreeManager, path string, branch str
 	}
 }
 
-func checkoutNewInWorktreeCmd(mgr *WorktreeManager, path string, branch string, baseRef string, doFetch bool) tea.Cmd {
+func checkoutNewInWorktreeCmd(mgr *core.WorktreeManager, path string, branch string, baseRef string, doFetch bool, progress io.Writer) tea.Cmd {
 	return func() tea.Msg {
 		lock, err := mgr.AcquireWorktreeLock(path)
 		if err != nil {
 			return openUseReadyMsg{err: err}
 		}
-		if err := mgr.CheckoutNewBranch(path, branch, baseRef, doFetch); err != nil {
+		if err := mgr.CheckoutNewBranch(path, branch, baseRef, doFetch, progress); err != nil {
 			lock.Release()
 			return openUseReadyMsg{err: err}
 		}
@@ -1877,9 +2350,9 @@ func checkoutNewInWorktreeCmd(mgr *WorktreeManager, path string, branch string,
 	}
 }
 
-func createAndUseExistingWorktreeCmd(mgr *WorktreeManager, branch string) tea.Cmd {
+func createAndUseExistingWorktreeCmd(mgr *core.WorktreeManager, branch string, progress io.Writer) tea.Cmd {
 	return func() tea.Msg {
-		created, err := mgr.CreateWorktreeFromBranch(branch)
+		created, err := mgr.CreateWorktreeFromBranch(branch, progress)
 		if err != nil {
 			return openUseReadyMsg{err: err}
 		}
@@ -1887,18 +2360,18 @@ func createAndUseExistingWorktreeCmd(mgr *WorktreeManager, branch string) tea.Cm
 		if err != nil {
 			return openUseReadyMsg{err: err}
 		}
-		return openUseReadyMsg{path: created.Path, branch: branch, lock: lock}
+		return openUseReadyMsg{path: created.Path, branch: branch, lock: lock, warning: created.HookWarning}
 	}
 }
 
-func createAndUseNewWorktreeCmd(mgr *WorktreeManager, branch string, baseRef string, doFetch bool) tea.Cmd {
+func createAndUseNewWorktreeCmd(mgr *core.WorktreeManager, branch string, baseRef string, doFetch bool, progress io.Writer) tea.Cmd {
 	return func() tea.Msg {
 		if doFetch {
-			if err := mgr.FetchRepoBaseRef(baseRef); err != nil {
+			if err := mgr.FetchRepoBaseRef(baseRef, progress); err != nil {
 				return openUseReadyMsg{err: err}
 			}
 		}
-		created, err := mgr.CreateWorktree(branch, baseRef)
+		created, err := mgr.CreateWorktree(branch, baseRef, progress)
 		if err != nil {
 			return openUseReadyMsg{err: err}
 		}
@@ -1906,22 +2379,22 @@ func createAndUseNewWorktreeCmd(mgr *WorktreeManager, branch string, baseRef str
 		if err != nil {
 			return openUseReadyMsg{err: err}
 		}
-		return openUseReadyMsg{path: created.Path, branch: branch, lock: lock}
+		return openUseReadyMsg{path: created.Path, branch: branch, lock: lock, warning: created.HookWarning}
 	}
 }
 
 func saveOpenDefaultsCmd(baseRef string, fetch bool) tea.Cmd {
 	return func() tea.Msg {
-		cfg, err := LoadConfig()
+		cfg, err := core.LoadConfig()
 		if err != nil {
-			exists, exErr := ConfigExists()
+			exists, exErr := core.ConfigExists()
 			if exErr != nil {
 				return openDefaultsSavedMsg{err: exErr}
 			}
 			if exists {
 				return openDefaultsSavedMsg{err: err}
 			}
-			cfg = Config{}
+			cfg = core.Config{}
 		}
 		baseRef = strings.TrimSpace(baseRef)
 		if baseRef != "" {
@@ -1929,14 +2402,14 @@ func saveOpenDefaultsCmd(baseRef string, fetch bool) tea.Cmd {
 		}
 		v := fetch
 		cfg.NewBranchFetchFirst = &v
-		if err := SaveConfig(cfg); err != nil {
+		if err := core.SaveConfig(cfg); err != nil {
 			return openDefaultsSavedMsg{err: err}
 		}
 		return openDefaultsSavedMsg{}
 	}
 }
 
-func renderSelector(status WorktreeStatus, cursor int, pendingByBranch map[string]bool, loadingGlyph string) string {
+func renderSelector(status core.WorktreeStatus, cursor int, marked map[string]bool, prStale bool, width int, pendingByBranch map[string]bool, loadingGlyph string) string {
 	if !status.InRepo {
 		return ""
 	}
@@ -1945,6 +2418,7 @@ func renderSelector(status WorktreeStatus, cursor int, pendingByBranch map[strin
 	for _, wt := range status.Orphaned {
 		orphaned[wt.Path] = true
 	}
+	now := time.Now()
 	worktrees := worktreesForDisplay(status)
 	for _, wt := range worktrees {
 		label := wt.Branch
@@ -1952,9 +2426,25 @@ func renderSelector(status WorktreeStatus, cursor int, pendingByBranch map[strin
 		if orphaned[wt.Path] {
 			label = fmt.Sprintf("%s (orphaned)", wt.Branch)
 			disabled = true
+		} else if wt.Broken != "" {
+			label = wt.Branch + " (broken: " + wt.Broken + ")"
+			disabled = true
+		} else if wt.Idle {
+			label = wt.Branch + " (idle)"
+			disabled = true
 		} else if !wt.Available {
 			label = wt.Branch + " (in use)"
+			if short := wt.LockHolder.Short(); short != "" {
+				label = wt.Branch + " (in use: " + short + ")"
+			}
 			disabled = true
+		} else if wt.Bootstrap != "" {
+			label = wt.Branch + " (" + wt.Bootstrap + ")"
+		} else if wt.AgentExit != "" {
+			label = wt.Branch + " (" + wt.AgentExit + ")"
+		}
+		if wt.Pinned {
+			label = pinnedGlyph + label
 		}
 		pending := pendingByBranch[strings.TrimSpace(wt.Branch)]
 		rows = append(rows, uiview.WorktreeRow{
@@ -1965,43 +2455,16 @@ func renderSelector(status WorktreeStatus, cursor int, pendingByBranch map[strin
 			CommentsLabel:   formatCommentsLabel(wt, pending, loadingGlyph),
 			UnresolvedLabel: formatUnresolvedLabel(wt, pending, loadingGlyph),
 			PRStatusLabel:   formatPRStatusLabel(wt, pending, loadingGlyph),
+			LastCommitLabel: formatLastCommitLabel(wt, now),
+			TestsLabel:      wt.Tests,
 			Disabled:        disabled,
+			Marked:          marked[wt.Path],
+			Stale:           prStale,
 		})
 	}
 	rows = append(rows, uiview.WorktreeRow{BranchLabel: "+ New worktree"})
-	return uiview.RenderWorktreeSelector(rows, cursor, viewStyles())
-}
-
-var (
-	baseStyle   = lipgloss.NewStyle()
-	bannerStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#FFF7DB")).
-			Background(lipgloss.Color("#7D56F4")).
-			Padding(0, 1)
-	errorStyle            = lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Bold(true)
-	secondaryStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
-	actionNormalStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("251"))
-	actionSelectedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#7D56F4")).Bold(true)
-	selectorNormalStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("251"))
-	selectorSelectedStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#7D56F4")).
-				Bold(true)
-	selectorDisabledStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("241"))
-	selectorDisabledSelectedStyle = lipgloss.NewStyle().
-					Foreground(lipgloss.Color("#7D56F4")).
-					Bold(true)
-	selectorHeaderStyle         = lipgloss.NewStyle().Foreground(lipgloss.Color("15")).Bold(true)
-	branchStyle                 = lipgloss.NewStyle().Foreground(lipgloss.Color("15")).Bold(true)
-	branchInlineStyle           = lipgloss.NewStyle().Bold(true)
-	warnStyle                   = lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Bold(true)
-	tmuxStatusDisabledHintStyle = lipgloss.NewStyle().
-					Foreground(lipgloss.Color("#E8DFA5"))
-	updateHintStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("239"))
-	inputStyle      = lipgloss.NewStyle().
-			Padding(0, 1)
-)
+	return uiview.RenderWorktreeSelector(rows, cursor, width, status.HasRemote, viewStyles())
+}
 
 func renderUpdateHint(hint string, isError bool) string {
 	if isError {
@@ -2018,16 +2481,10 @@ func viewStyles() uiview.Styles {
 		Disabled:         func(s string) string { return selectorDisabledStyle.Render(s) },
 		DisabledSelected: func(s string) string { return selectorDisabledSelectedStyle.Render(s) },
 		Secondary:        func(s string) string { return secondaryStyle.Render(s) },
+		Stale:            func(s string) string { return staleStyle.Render(s) },
 	}
 }
 
-func max(a int, b int) int {
-	if a > b {
-		return a
-	}
-	return b
-}
-
 type uiMode int
 
 const (
@@ -2039,6 +2496,9 @@ const (
 	modeAction
 	modeBranchName
 	modeBranchPick
+	modeBulkAction
+	modePreview
+	modeNote
 )
 
 type openStage int
@@ -2065,7 +2525,7 @@ func newCreateBranchInput() textinput.Model {
 	return ti
 }
 
-func isCreateRow(cursor int, status WorktreeStatus) bool {
+func isCreateRow(cursor int, status core.WorktreeStatus) bool {
 	if !status.InRepo {
 		return false
 	}
@@ -2075,18 +2535,18 @@ func isCreateRow(cursor int, status WorktreeStatus) bool {
 	return cursor == len(worktreesForDisplay(status))
 }
 
-func selectedWorktree(status WorktreeStatus, cursor int) (WorktreeInfo, bool) {
+func selectedWorktree(status core.WorktreeStatus, cursor int) (core.WorktreeInfo, bool) {
 	if !status.InRepo {
-		return WorktreeInfo{}, false
+		return core.WorktreeInfo{}, false
 	}
 	worktrees := worktreesForDisplay(status)
 	if cursor < 0 || cursor >= len(worktrees) {
-		return WorktreeInfo{}, false
+		return core.WorktreeInfo{}, false
 	}
 	return worktrees[cursor], true
 }
 
-func isOrphanedPath(status WorktreeStatus, path string) bool {
+func isOrphanedPath(status core.WorktreeStatus, path string) bool {
 	for _, wt := range status.Orphaned {
 		if wt.Path == path {
 			return true
@@ -2126,7 +2586,7 @@ func currentActionItems(branch string, baseRef string, create bool) []string {
 	return actionItems(branch, baseRef)
 }
 
-func currentWorktreePath(status WorktreeStatus, cursor int) string {
+func currentWorktreePath(status core.WorktreeStatus, cursor int) string {
 	wt, ok := selectedWorktree(status, cursor)
 	if !ok {
 		return ""
@@ -2134,10 +2594,10 @@ func currentWorktreePath(status WorktreeStatus, cursor int) string {
 	return wt.Path
 }
 
-func findWorktreeByPath(status WorktreeStatus, path string) (int, WorktreeInfo, bool) {
+func findWorktreeByPath(status core.WorktreeStatus, path string) (int, core.WorktreeInfo, bool) {
 	needle := strings.TrimSpace(path)
 	if needle == "" {
-		return 0, WorktreeInfo{}, false
+		return 0, core.WorktreeInfo{}, false
 	}
 	worktrees := worktreesForDisplay(status)
 	for i, wt := range worktrees {
@@ -2145,7 +2605,7 @@ func findWorktreeByPath(status WorktreeStatus, path string) (int, WorktreeInfo,
 			return i, wt, true
 		}
 	}
-	return 0, WorktreeInfo{}, false
+	return 0, core.WorktreeInfo{}, false
 }
 
 func greenCheck() string {
@@ -2156,7 +2616,7 @@ func redX() string {
 	return "✗"
 }
 
-func formatPRLabel(wt WorktreeInfo, pending bool, loadingGlyph string) string {
+func formatPRLabel(wt core.WorktreeInfo, pending bool, loadingGlyph string) string {
 	if pending {
 		return loadingGlyph
 	}
@@ -2170,7 +2630,7 @@ func formatPRLabel(wt WorktreeInfo, pending bool, loadingGlyph string) string {
 	return label
 }
 
-func formatPRStatusLabel(wt WorktreeInfo, pending bool, loadingGlyph string) string {
+func formatPRStatusLabel(wt core.WorktreeInfo, pending bool, loadingGlyph string) string {
 	if pending {
 		return loadingGlyph
 	}
@@ -2189,7 +2649,7 @@ func formatPRStatusLabel(wt WorktreeInfo, pending bool, loadingGlyph string) str
 	}
 }
 
-func formatCILabel(wt WorktreeInfo, pending bool, loadingGlyph string) string {
+func formatCILabel(wt core.WorktreeInfo, pending bool, loadingGlyph string) string {
 	if pending {
 		return loadingGlyph
 	}
@@ -2197,21 +2657,21 @@ func formatCILabel(wt WorktreeInfo, pending bool, loadingGlyph string) string {
 		return "-"
 	}
 	switch wt.CIState {
-	case PRCISuccess:
+	case core.PRCISuccess:
 		return fmt.Sprintf("✓ %d/%d", wt.CIDone, wt.CITotal)
-	case PRCIFail:
+	case core.PRCIFail:
 		if names := strings.TrimSpace(wt.CIFailingNames); names != "" {
 			return fmt.Sprintf("✗ %d/%d %s", wt.CIDone, wt.CITotal, names)
 		}
 		return fmt.Sprintf("✗ %d/%d", wt.CIDone, wt.CITotal)
-	case PRCIInProgress:
+	case core.PRCIInProgress:
 		return fmt.Sprintf("… %d/%d", wt.CIDone, wt.CITotal)
 	default:
 		return "-"
 	}
 }
 
-func formatCommentsLabel(wt WorktreeInfo, pending bool, loadingGlyph string) string {
+func formatCommentsLabel(wt core.WorktreeInfo, pending bool, loadingGlyph string) string {
 	if pending {
 		return loadingGlyph
 	}
@@ -2228,7 +2688,7 @@ func formatCommentsLabel(wt WorktreeInfo, pending bool, loadingGlyph string) str
 	return fmt.Sprintf("(%d/%d)", resolved, wt.CommentThreadsTotal)
 }
 
-func formatUnresolvedLabel(wt WorktreeInfo, pending bool, loadingGlyph string) string {
+func formatUnresolvedLabel(wt core.WorktreeInfo, pending bool, loadingGlyph string) string {
 	if pending {
 		return loadingGlyph
 	}
@@ -2242,7 +2702,7 @@ func formatUnresolvedLabel(wt WorktreeInfo, pending bool, loadingGlyph string) s
 	return fmt.Sprintf("%d", unresolved)
 }
 
-func formatReviewLabel(wt WorktreeInfo, pending bool, loadingGlyph string) string {
+func formatReviewLabel(wt core.WorktreeInfo, pending bool, loadingGlyph string) string {
 	if pending {
 		return loadingGlyph
 	}
@@ -2258,7 +2718,41 @@ func formatReviewLabel(wt WorktreeInfo, pending bool, loadingGlyph string) strin
 	return "-"
 }
 
-func uniqueBranches(status WorktreeStatus) []string {
+// lastCommitSubjectWidth caps the subject so the age still fits the column.
+const lastCommitSubjectWidth = 20
+
+// formatLastCommitLabel renders the branch tip as `"fix tests" (3h ago)`.
+func formatLastCommitLabel(wt core.WorktreeInfo, now time.Time) string {
+	subject := strings.TrimSpace(wt.LastCommit.Subject)
+	if subject == "" || wt.LastCommit.CommittedUnix <= 0 {
+		return "-"
+	}
+	if runes := []rune(subject); len(runes) > lastCommitSubjectWidth {
+		subject = string(runes[:lastCommitSubjectWidth-1]) + "…"
+	}
+	age := now.Sub(time.Unix(wt.LastCommit.CommittedUnix, 0))
+	return fmt.Sprintf("\"%s\" (%s)", subject, formatCommitAge(age))
+}
+
+func formatCommitAge(d time.Duration) string {
+	day := 24 * time.Hour
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < day:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	case d < 30*day:
+		return fmt.Sprintf("%dd ago", int(d/day))
+	case d < 365*day:
+		return fmt.Sprintf("%dmo ago", int(d/(30*day)))
+	default:
+		return fmt.Sprintf("%dy ago", int(d/(365*day)))
+	}
+}
+
+func uniqueBranches(status core.WorktreeStatus) []string {
 	seen := make(map[string]bool)
 	out := make([]string, 0, len(status.Worktrees)+1)
 	for _, wt := range status.Worktrees {
@@ -2291,7 +2785,7 @@ func filterBranches(options []string, query string) []string {
 
 const maxBranchSuggestions = 15
 
-func availableBranchOptions(status WorktreeStatus, mgr *WorktreeManager, includeInUse bool) ([]string, error) {
+func availableBranchOptions(status core.WorktreeStatus, mgr *core.WorktreeManager, includeInUse bool) ([]string, error) {
 	options, err := mgr.ListLocalBranchesByRecentUse()
 	if err != nil {
 		return nil, err
@@ -2323,10 +2817,10 @@ func availableBranchOptions(status WorktreeStatus, mgr *WorktreeManager, include
 	return filtered, nil
 }
 
-func reusableWorktreeForBranch(status WorktreeStatus, branch string) (WorktreeInfo, bool, string) {
+func reusableWorktreeForBranch(status core.WorktreeStatus, branch string) (core.WorktreeInfo, bool, string) {
 	branch = strings.TrimSpace(branch)
 	if branch == "" || !status.InRepo {
-		return WorktreeInfo{}, false, ""
+		return core.WorktreeInfo{}, false, ""
 	}
 	orphaned := make(map[string]bool, len(status.Orphaned))
 	for _, wt := range status.Orphaned {
@@ -2338,7 +2832,7 @@ func reusableWorktreeForBranch(status WorktreeStatus, branch string) (WorktreeIn
 			continue
 		}
 		if orphaned[wt.Path] {
-			return WorktreeInfo{}, false, "Branch has an orphaned worktree. Remove it before reuse."
+			return core.WorktreeInfo{}, false, "Branch has an orphaned worktree. Remove it before reuse."
 		}
 		if wt.Available {
 			return wt, true, ""
@@ -2346,9 +2840,9 @@ func reusableWorktreeForBranch(status WorktreeStatus, branch string) (WorktreeIn
 		foundUnavailable = true
 	}
 	if foundUnavailable {
-		return WorktreeInfo{}, false, "Branch already has a worktree in use."
+		return core.WorktreeInfo{}, false, "Branch already has a worktree in use."
 	}
-	return WorktreeInfo{}, false, ""
+	return core.WorktreeInfo{}, false, ""
 }
 
 func selectedBranch(suggestions []string, index int) (string, bool) {
@@ -2359,14 +2853,14 @@ func selectedBranch(suggestions []string, index int) (string, bool) {
 	return value, value != ""
 }
 
-func selectorRowCount(status WorktreeStatus) int {
+func selectorRowCount(status core.WorktreeStatus) int {
 	if !status.InRepo {
 		return 0
 	}
 	return len(worktreesForDisplay(status)) + 1
 }
 
-func pendingBranchesByName(status WorktreeStatus) map[string]bool {
+func pendingBranchesByName(status core.WorktreeStatus) map[string]bool {
 	out := make(map[string]bool, len(status.Worktrees))
 	for _, wt := range status.Worktrees {
 		name := strings.TrimSpace(wt.Branch)
@@ -2378,9 +2872,23 @@ func pendingBranchesByName(status WorktreeStatus) map[string]bool {
 	return out
 }
 
-func ghDataKeyForStatus(status WorktreeStatus) string {
+// uncoveredBranches are the status branches no finished gh fetch has looked
+// up yet. Only their rows show the loading glyph, so a background refresh
+// leaves known PR data on screen.
+func uncoveredBranches(status core.WorktreeStatus, covered map[string]bool) map[string]bool {
+	out := map[string]bool{}
+	for branch := range pendingBranchesByName(status) {
+		if !covered[branch] {
+			out[branch] = true
+		}
+	}
+	return out
+}
+
+func ghDataKeyForStatus(status core.WorktreeStatus) string {
 	repo := strings.TrimSpace(status.RepoRoot)
-	if repo == "" || !status.InRepo {
+	// A repo without a remote has no PRs to look up.
+	if repo == "" || !status.InRepo || !status.HasRemote {
 		return ""
 	}
 	branches := make([]string, 0, len(status.Worktrees))
@@ -2397,6 +2905,20 @@ func ghDataKeyForStatus(status WorktreeStatus) string {
 	return repo + "|" + strings.Join(branches, ",")
 }
 
+// forceRefresh reloads status and GitHub data, bypassing the PR cache.
+func (m model) forceRefresh() (tea.Model, tea.Cmd) {
+	m.ghLoadedKey = ""
+	m.ghFetchingKey = ""
+	m.ghPendingByBranch = map[string]bool{}
+	m.ghCovered = nil
+	m.ghDataByBranch = map[string]core.PRData{}
+	m.ghWarnMsg = ""
+	m.ghAuth = nil
+	m.ghStale = false
+	m.forceGHRefresh = true
+	return m, fetchStatusCmd(m.orchestrator)
+}
+
 func ghWarningFromErr(err error) string {
 	if err == nil {
 		return ""
@@ -2418,7 +2940,7 @@ func ghWarningFromErr(err error) string {
 	}
 }
 
-func worktreesForDisplay(status WorktreeStatus) []WorktreeInfo {
+func worktreesForDisplay(status core.WorktreeStatus) []core.WorktreeInfo {
 	if !status.InRepo {
 		return nil
 	}
@@ -2426,32 +2948,15 @@ func worktreesForDisplay(status WorktreeStatus) []WorktreeInfo {
 	for _, wt := range status.Orphaned {
 		orphaned[wt.Path] = true
 	}
-	out := make([]WorktreeInfo, len(status.Worktrees))
+	out := make([]core.WorktreeInfo, len(status.Worktrees))
 	copy(out, status.Worktrees)
 	sort.SliceStable(out, func(i, j int) bool {
-		iFree := out[i].Available && !orphaned[out[i].Path]
-		jFree := out[j].Available && !orphaned[out[j].Path]
-		if iFree != jFree {
-			return iFree
-		}
-		if iFree && jFree {
-			iLastUsed := out[i].LastUsedUnix
-			jLastUsed := out[j].LastUsedUnix
-			if iLastUsed != jLastUsed {
-				return iLastUsed > jLastUsed
-			}
-		}
-		iBranch := strings.ToLower(strings.TrimSpace(out[i].Branch))
-		jBranch := strings.ToLower(strings.TrimSpace(out[j].Branch))
-		if iBranch != jBranch {
-			return iBranch > jBranch
-		}
-		return out[i].Path > out[j].Path
+		return worktreeLess(status.ListSort, out[i], out[j], orphaned)
 	})
 	return out
 }
 
-func applyPRDataToStatus(status *WorktreeStatus, byBranch map[string]PRData) {
+func applyPRDataToStatus(status *core.WorktreeStatus, byBranch map[string]core.PRData) {
 	if status == nil {
 		return
 	}
@@ -2461,7 +2966,7 @@ func applyPRDataToStatus(status *WorktreeStatus, byBranch map[string]PRData) {
 		status.Worktrees[i].PRNumber = 0
 		status.Worktrees[i].PRURL = ""
 		status.Worktrees[i].PRStatus = ""
-		status.Worktrees[i].CIState = PRCINone
+		status.Worktrees[i].CIState = core.PRCINone
 		status.Worktrees[i].CIDone = 0
 		status.Worktrees[i].CITotal = 0
 		status.Worktrees[i].CIFailingNames = ""
@@ -2497,7 +3002,7 @@ func applyPRDataToStatus(status *WorktreeStatus, byBranch map[string]PRData) {
 	}
 }
 
-func clampListIndex(index int, status WorktreeStatus) int {
+func clampListIndex(index int, status core.WorktreeStatus) int {
 	maxIndex := selectorRowCount(status) - 1
 	if maxIndex < 0 {
 		return 0
@@ -2524,47 +3029,47 @@ func clampOpenDebugIndex(index int, count int) int {
 	return index
 }
 
-func selectedOpenDebugSlot(slots []openSlotState, index int) (openSlotState, bool) {
+func selectedOpenDebugSlot(slots []core.OpenSlotState, index int) (core.OpenSlotState, bool) {
 	if index < 0 || index >= len(slots) {
-		return openSlotState{}, false
+		return core.OpenSlotState{}, false
 	}
 	return slots[index], true
 }
 
-func openCmdForTargetOnSlot(m model, slot openSlotState) tea.Cmd {
+func openCmdForTargetOnSlot(m model, slot core.OpenSlotState) tea.Cmd {
 	if m.openTargetIsNew {
-		return checkoutNewInWorktreeCmd(m.mgr, slot.Path, m.openTargetBranch, m.openTargetBaseRef, m.openTargetFetch)
+		return checkoutNewInWorktreeCmd(m.mgr, slot.Path, m.openTargetBranch, m.openTargetBaseRef, m.openTargetFetch, m.createLog.writer())
 	}
 	if strings.TrimSpace(slot.Branch) == strings.TrimSpace(m.openTargetBranch) {
 		return useExistingWorktreeCmd(m.mgr, slot.Path, m.openTargetBranch)
 	}
-	return checkoutExistingInWorktreeCmd(m.mgr, slot.Path, m.openTargetBranch)
+	return checkoutExistingInWorktreeCmd(m.mgr, slot.Path, m.openTargetBranch, m.createLog.writer())
 }
 
 func openCmdForCreateTarget(m model) tea.Cmd {
 	if m.openTargetIsNew {
-		return createAndUseNewWorktreeCmd(m.mgr, m.openTargetBranch, m.openTargetBaseRef, m.openTargetFetch)
+		return createAndUseNewWorktreeCmd(m.mgr, m.openTargetBranch, m.openTargetBaseRef, m.openTargetFetch, m.createLog.writer())
 	}
-	return createAndUseExistingWorktreeCmd(m.mgr, m.openTargetBranch)
+	return createAndUseExistingWorktreeCmd(m.mgr, m.openTargetBranch, m.createLog.writer())
 }
 
-func findOpenSlotByPath(slots []openSlotState, path string) (openSlotState, bool) {
+func findOpenSlotByPath(slots []core.OpenSlotState, path string) (core.OpenSlotState, bool) {
 	needle := strings.TrimSpace(path)
 	if needle == "" {
-		return openSlotState{}, false
+		return core.OpenSlotState{}, false
 	}
 	for _, slot := range slots {
 		if strings.TrimSpace(slot.Path) == needle {
 			return slot, true
 		}
 	}
-	return openSlotState{}, false
+	return core.OpenSlotState{}, false
 }
 
 func newSpinner() spinner.Model {
 	s := spinner.New()
 	s.Spinner = spinner.Dot
-	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("#7D56F4"))
+	s.Style = lipgloss.NewStyle().Foreground(accentColor)
 	return s
 }
 
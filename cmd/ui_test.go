@@ -2,11 +2,15 @@ package cmd
 
 import (
 	"errors"
+	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/aixolotls/wtx/internal/core"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 func TestRenderCreateProgress_NewBranchFromBase(t *testing.T) {
@@ -34,6 +38,21 @@ func TestRenderCreateProgress_ExistingBranch(t *testing.T) {
 	}
 }
 
+func TestRenderProgressLogShowsTail(t *testing.T) {
+	log := newProgressLog()
+	for i := 1; i <= 8; i++ {
+		fmt.Fprintf(log, "line %d\n", i)
+	}
+	fmt.Fprint(log, "Receiving objects: 50% (1/2)")
+	got := renderProgressLog(log, 80)
+	if strings.Contains(got, "line 3\n") || !strings.Contains(got, "line 4") || !strings.Contains(got, "Receiving objects: 50%") {
+		t.Fatalf("expected the last lines including the partial one, got %q", got)
+	}
+	if renderProgressLog(nil, 80) != "" {
+		t.Fatal("expected nothing for a missing log")
+	}
+}
+
 func TestShouldFetchByBranch(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -123,7 +142,7 @@ func TestOpenScreenKeepsPreviousLoadErrorUntilPRDataResolves(t *testing.T) {
 	m.openLoadErr = "previous fetch failed"
 
 	updatedModel, _ := m.Update(openScreenLoadedMsg{
-		status: WorktreeStatus{},
+		status: core.WorktreeStatus{},
 		branches: []openBranchOption{
 			{Name: "feature/test", PRLoading: true},
 		},
@@ -151,7 +170,7 @@ func TestOpenScreenKeepsPreviousLoadErrorUntilPRDataResolves(t *testing.T) {
 	}
 
 	updatedModel, _ = updated.Update(openScreenLoadedMsg{
-		status: WorktreeStatus{},
+		status: core.WorktreeStatus{},
 		branches: []openBranchOption{
 			{Name: "feature/test", PRLoading: true},
 		},
@@ -163,7 +182,7 @@ func TestOpenScreenKeepsPreviousLoadErrorUntilPRDataResolves(t *testing.T) {
 		t.Fatalf("expected latest load error to remain while next PR fetch is pending, got %q", updated.openLoadErr)
 	}
 
-	updatedModel, _ = updated.Update(openScreenPRDataMsg{fetchID: "fetch-2", byBranch: map[string]PRData{}})
+	updatedModel, _ = updated.Update(openScreenPRDataMsg{fetchID: "fetch-2", byBranch: map[string]core.PRData{}})
 	updated = updatedModel.(model)
 	if updated.openLoadErr != "" {
 		t.Fatalf("expected load error to clear after successful PR fetch, got %q", updated.openLoadErr)
@@ -176,7 +195,7 @@ func TestOpenPickAllowsDirtyWorktreeWhenBranchMatchesTarget(t *testing.T) {
 	m.openStage = openStagePickWorktree
 	m.openTargetBranch = "feature/existing"
 	m.openPickIndex = 1
-	m.openSlots = []openSlotState{
+	m.openSlots = []core.OpenSlotState{
 		{Path: t.TempDir(), Branch: "feature/existing", Dirty: true},
 	}
 
@@ -229,11 +248,11 @@ func TestOpenScreenPRDataIgnoredForSearchAllBranchList(t *testing.T) {
 	m.openSearchAllActive = true
 	m.openFetchID = "fetch-1"
 	m.openBranches = []openBranchOption{{Name: "feature/a", PRLoading: false}}
-	m.openSlots = []openSlotState{{Branch: "feature/a"}}
+	m.openSlots = []core.OpenSlotState{{Branch: "feature/a"}}
 
 	updatedModel, _ := m.Update(openScreenPRDataMsg{
 		fetchID: "fetch-1",
-		byBranch: map[string]PRData{
+		byBranch: map[string]core.PRData{
 			"feature/a": {Number: 42, URL: "https://example.test/pr/42"},
 		},
 	})
@@ -242,3 +261,525 @@ func TestOpenScreenPRDataIgnoredForSearchAllBranchList(t *testing.T) {
 		t.Fatalf("expected search-all branch rows to remain without PR data")
 	}
 }
+
+func TestOpenScreenFetchesPRDataForVisibleRowsOnly(t *testing.T) {
+	m := newModel()
+	m.mode = modeOpen
+	m.openStage = openStageMain
+	m.height = 20
+	branches := make([]openBranchOption, 0, 60)
+	names := make([]string, 0, 60)
+	for i := 0; i < 60; i++ {
+		name := fmt.Sprintf("feature/%02d", i)
+		branches = append(branches, openBranchOption{Name: name, PRLoading: true})
+		names = append(names, name)
+	}
+
+	updatedModel, _ := m.Update(openScreenLoadedMsg{branches: branches, prBranches: names, fetchID: "fetch-1"})
+	updated := updatedModel.(model)
+	limit := openBranchRenderLimit(updated.height)
+	if got, want := len(updated.openPRRequested), limit+openPRPrefetchRows; got != want {
+		t.Fatalf("expected %d branches requested, got %d", want, got)
+	}
+	if updated.openPRRequested["feature/59"] {
+		t.Fatalf("expected rows far below the fold to be skipped")
+	}
+
+	fetched := make([]string, 0, len(updated.openPRRequested))
+	for name := range updated.openPRRequested {
+		fetched = append(fetched, name)
+	}
+	updatedModel, _ = updated.Update(openScreenPRDataMsg{
+		fetchID:  "fetch-1",
+		branches: fetched,
+		byBranch: map[string]core.PRData{"feature/00": {Number: 9}},
+	})
+	updated = updatedModel.(model)
+	if updated.openLoading {
+		t.Fatalf("expected loading to finish once the visible fetch returns")
+	}
+	if !updated.openBranches[0].HasPR || updated.openBranches[59].PRLoading != true {
+		t.Fatalf("expected only fetched rows to be updated, got %+v / %+v", updated.openBranches[0], updated.openBranches[59])
+	}
+
+	updated.openSelected = 60
+	var cmd tea.Cmd
+	updatedModel, cmd = updated.Update(tea.KeyMsg{Type: tea.KeyUp})
+	updated = updatedModel.(model)
+	if cmd == nil || !updated.openPRRequested["feature/59"] || !updated.openLoading {
+		t.Fatalf("expected scrolling to request PR data for newly visible rows")
+	}
+}
+
+func TestOpenScreenRendersBeforeBaseRefResolves(t *testing.T) {
+	m := newModel()
+	m.mode = modeOpen
+	if view := m.View(); !strings.Contains(view, "Choose branch:") || !strings.Contains(view, "<new branch>") {
+		t.Fatalf("expected open screen skeleton before load, got %q", view)
+	}
+
+	updatedModel, _ := m.Update(openScreenLoadedMsg{status: core.WorktreeStatus{InRepo: true, GitInstalled: true, HasRemote: true, BaseRef: "origin/main"}})
+	updated := updatedModel.(model)
+	if updated.openDefaultBaseRef != "origin/main" {
+		t.Fatalf("expected fallback base ref, got %q", updated.openDefaultBaseRef)
+	}
+	updatedModel, _ = updated.Update(baseRefResolvedMsg("origin/develop"))
+	updated = updatedModel.(model)
+	if updated.openDefaultBaseRef != "origin/develop" || updated.status.BaseRef != "origin/develop" {
+		t.Fatalf("expected resolved base ref to replace fallback, got %q / %q", updated.openDefaultBaseRef, updated.status.BaseRef)
+	}
+
+	updatedModel, _ = updated.Update(openScreenLoadedMsg{status: core.WorktreeStatus{InRepo: true, GitInstalled: true, HasRemote: true, BaseRef: "origin/main"}})
+	updated = updatedModel.(model)
+	if updated.status.BaseRef != "origin/develop" {
+		t.Fatalf("expected a stale reload to keep the resolved base ref, got %q", updated.status.BaseRef)
+	}
+}
+
+func TestListFilterNarrowsWorktreeTable(t *testing.T) {
+	m := newModel()
+	m.mode = modeList
+	m.ready = true
+	m.status = core.WorktreeStatus{
+		InRepo:       true,
+		GitInstalled: true,
+		Worktrees: []core.WorktreeInfo{
+			{Path: "/repo/wt.1", Branch: "feature/login", Available: true},
+			{Path: "/repo/wt.2", Branch: "fix/crash", Available: true, PRNumber: 42},
+			{Path: "/repo/wt.3", Branch: "feature/logout", Available: true},
+		},
+	}
+
+	var updatedModel tea.Model = m
+	for _, key := range []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune{'/'}},
+		{Type: tea.KeyRunes, Runes: []rune("#42")},
+	} {
+		updatedModel, _ = updatedModel.Update(key)
+	}
+	updated := updatedModel.(model)
+	if wt, ok := selectedWorktree(updated.listStatus(), updated.listIndex); !ok || wt.Branch != "fix/crash" {
+		t.Fatalf("expected PR number filter to select fix/crash, got %+v", wt)
+	}
+
+	updatedModel, _ = updated.Update(tea.KeyMsg{Type: tea.KeyCtrlU})
+	updatedModel, _ = updatedModel.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("logout")})
+	updatedModel, _ = updatedModel.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	updated = updatedModel.(model)
+	if updated.listFiltering || len(updated.listStatus().Worktrees) != 1 {
+		t.Fatalf("expected enter to keep a one-row filter, got filtering=%v rows=%v", updated.listFiltering, updated.listStatus().Worktrees)
+	}
+	if !strings.Contains(updated.View(), "/logout") {
+		t.Fatalf("expected active filter in view")
+	}
+
+	updatedModel, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	updated = updatedModel.(model)
+	if updated.listFilter != "" || len(updated.listStatus().Worktrees) != 3 {
+		t.Fatalf("expected esc to clear the filter, got %q", updated.listFilter)
+	}
+}
+
+func TestSortKeyCyclesWorktreeTableOrder(t *testing.T) {
+	m := newModel()
+	m.mode = modeList
+	m.ready = true
+	m.status = core.WorktreeStatus{
+		InRepo:       true,
+		GitInstalled: true,
+		Worktrees: []core.WorktreeInfo{
+			{Path: "/repo/wt.1", Branch: "beta", Available: true, LastUsedUnix: 30, PRNumber: 7, CIState: core.PRCISuccess},
+			{Path: "/repo/wt.2", Branch: "alpha", Available: true, LastUsedUnix: 20, CIState: core.PRCINone},
+			{Path: "/repo/wt.3", Branch: "gamma", Available: true, LastUsedUnix: 10, PRNumber: 12, CIState: core.PRCIFail},
+		},
+	}
+	branchesInOrder := func(m model) []string {
+		out := []string{}
+		for _, wt := range worktreesForDisplay(m.listStatus()) {
+			out = append(out, wt.Branch)
+		}
+		return out
+	}
+	want := [][]string{
+		{"alpha", "beta", "gamma"},
+		{"gamma", "beta", "alpha"},
+		{"gamma", "beta", "alpha"},
+		{"beta", "alpha", "gamma"},
+		{"beta", "alpha", "gamma"},
+	}
+	labels := []string{"branch", "PR number", "CI state", "last activity", "status"}
+	var updatedModel tea.Model = m
+	for i := range want {
+		updatedModel, _ = updatedModel.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'S'}})
+		updated := updatedModel.(model)
+		if got := branchesInOrder(updated); !reflect.DeepEqual(got, want[i]) {
+			t.Fatalf("sort %s: expected %v, got %v", updated.listSort.Label(), want[i], got)
+		}
+		if !strings.Contains(updated.View(), "sorted by "+labels[i]) {
+			t.Fatalf("expected header to show sort %q", labels[i])
+		}
+	}
+
+	updated := updatedModel.(model)
+	updated.listIndex = 2
+	updatedModel, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'S'}})
+	if wt, _ := selectedWorktree(updatedModel.(model).listStatus(), updatedModel.(model).listIndex); wt.Branch != "gamma" {
+		t.Fatalf("expected selection to follow gamma across the reorder, got %q", wt.Branch)
+	}
+}
+
+func TestRenderSelectorCollapsesColumnsOnNarrowTerminals(t *testing.T) {
+	status := core.WorktreeStatus{
+		InRepo:    true,
+		HasRemote: true,
+		Worktrees: []core.WorktreeInfo{
+			{Path: "/repo/wt.1", Branch: "feature/login", Available: true, HasPR: true, PRNumber: 12, CIState: core.PRCISuccess, CIDone: 3, CITotal: 3},
+		},
+	}
+	wide := renderSelector(status, 0, nil, false, 0, nil, "")
+	for _, title := range []string{"CI", "Approval", "Comments", "PR Status"} {
+		if !strings.Contains(wide, title) {
+			t.Fatalf("expected %q column at full width, got\n%s", title, wide)
+		}
+	}
+
+	medium := renderSelector(status, 0, nil, false, 80, nil, "")
+	if strings.Contains(medium, "Comments") || strings.Contains(medium, "Approval") {
+		t.Fatalf("expected low priority columns hidden at 80 columns, got\n%s", medium)
+	}
+	for _, line := range strings.Split(strings.TrimRight(medium, "\n"), "\n") {
+		if w := lipgloss.Width(line); w > 80 {
+			t.Fatalf("expected lines to fit 80 columns, got %d: %q", w, line)
+		}
+	}
+
+	narrow := renderSelector(status, 0, nil, false, 40, nil, "")
+	if strings.Contains(narrow, "Branch") || !strings.Contains(narrow, "    #12") {
+		t.Fatalf("expected stacked rows with details underneath, got\n%s", narrow)
+	}
+
+	if got := truncatePathForWidth("/very/long/path/to/some/worktree/slot", 30, 0); got != "/very/long/pa.../worktree/slot" {
+		t.Fatalf("expected middle-truncated path, got %q", got)
+	}
+}
+
+func TestHelpOverlayListsBindingsForCurrentMode(t *testing.T) {
+	m := newModel()
+	m.mode = modeList
+	m.ready = true
+	m.status = core.WorktreeStatus{InRepo: true, GitInstalled: true}
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	updated := updatedModel.(model)
+	if !updated.showHelp {
+		t.Fatalf("expected ? to open the help overlay")
+	}
+	view := updated.View()
+	assertContains := func(want string) {
+		t.Helper()
+		if !strings.Contains(view, want) {
+			t.Fatalf("expected help overlay to contain %q, got:\n%s", want, view)
+		}
+	}
+	assertContains("Worktree table (current)")
+	assertContains("Cycle the table sort order")
+	assertContains("tmux actions popup")
+	if strings.Index(view, "Worktree table") > strings.Index(view, "Open screen") {
+		t.Fatalf("expected the current mode's section first")
+	}
+
+	updatedModel, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if updatedModel.(model).showHelp {
+		t.Fatalf("expected any key to close the help overlay")
+	}
+
+	m.listFiltering = true
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	if updatedModel.(model).showHelp {
+		t.Fatalf("expected ? to be typed into the filter, not open help")
+	}
+}
+
+func TestErrorDetailShowsCommandOutputAndHint(t *testing.T) {
+	m := newModel()
+	m.mode = modeList
+	m.ready = true
+	m.status = core.WorktreeStatus{InRepo: true, GitInstalled: true}
+	m = m.setError(&core.CommandError{
+		Command: "git worktree add -b feature/x /repo.wt/wt.1 origin/main",
+		Dir:     "/repo",
+		Output:  "fatal: invalid reference: origin/main",
+		Err:     errors.New("exit status 128"),
+	})
+	if !strings.Contains(m.View(), "e for details") {
+		t.Fatalf("expected the error line to point at the detail view, got:\n%s", m.View())
+	}
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	updated := updatedModel.(model)
+	if !updated.showErrorDetail {
+		t.Fatalf("expected e to open the error detail view")
+	}
+	view := updated.View()
+	for _, want := range []string{"git worktree add -b feature/x", "/repo", "invalid reference", "Fetch the remote"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("expected error detail to contain %q, got:\n%s", want, view)
+		}
+	}
+
+	updatedModel, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if updatedModel.(model).showErrorDetail {
+		t.Fatalf("expected esc to close the error detail view")
+	}
+
+	m.errMsg = "Branch name required."
+	detail, ok := currentErrorDetail(m)
+	if !ok || detail.Command != "" {
+		t.Fatalf("expected a replaced message not to reuse the old command, got %+v", detail)
+	}
+}
+
+func TestBulkUnlockConfirmsMarkedWorktreesTogether(t *testing.T) {
+	m := newModel()
+	m.mode = modeList
+	m.ready = true
+	m.status = core.WorktreeStatus{
+		InRepo:       true,
+		GitInstalled: true,
+		Worktrees: []core.WorktreeInfo{
+			{Path: "/repo/wt.1", Branch: "alpha", Available: false},
+			{Path: "/repo/wt.2", Branch: "beta", Available: true},
+			{Path: "/repo/wt.3", Branch: "gamma", Available: false},
+		},
+	}
+	m.listSort = core.ListSortBranch
+
+	var updatedModel tea.Model = m
+	for range 3 {
+		updatedModel, _ = updatedModel.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	}
+	updated := updatedModel.(model)
+	if got := len(updated.markedWorktrees()); got != 3 {
+		t.Fatalf("expected 3 marked worktrees, got %d", got)
+	}
+	if !strings.Contains(updated.View(), "* ") {
+		t.Fatalf("expected marked rows to be flagged in the table")
+	}
+
+	updatedModel, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'u'}})
+	updated = updatedModel.(model)
+	if updated.confirmKind != confirmBulk || updated.confirmForm == nil {
+		t.Fatalf("expected a combined bulk confirmation")
+	}
+	if len(updated.bulkTargets) != 2 {
+		t.Fatalf("expected only in-use worktrees to be unlocked, got %+v", updated.bulkTargets)
+	}
+	view := updated.View()
+	if !strings.Contains(view, "Unlock 2 worktrees?") || !strings.Contains(view, "beta (not in use)") {
+		t.Fatalf("expected confirmation to list targets and skipped rows, got:\n%s", view)
+	}
+
+	updated.confirmForm = nil
+	updatedModel, _ = updated.Update(bulkActionDoneMsg{action: bulkUnlock, done: []string{"/repo/wt.1", "/repo/wt.3"}})
+	updated = updatedModel.(model)
+	marked := updated.markedWorktrees()
+	if len(marked) != 1 || marked[0].Branch != "beta" {
+		t.Fatalf("expected only skipped worktrees to stay marked, got %+v", marked)
+	}
+}
+
+func TestFormatLastCommitLabelShowsSubjectAndAge(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	cases := []struct {
+		commit core.BranchCommit
+		want   string
+	}{
+		{core.BranchCommit{}, "-"},
+		{core.BranchCommit{Subject: "fix tests", CommittedUnix: now.Add(-3 * time.Hour).Unix()}, `"fix tests" (3h ago)`},
+		{core.BranchCommit{Subject: "wip", CommittedUnix: now.Add(-20 * time.Second).Unix()}, `"wip" (just now)`},
+		{core.BranchCommit{Subject: "spike a new renderer for diffs", CommittedUnix: now.Add(-75 * 24 * time.Hour).Unix()}, `"spike a new rendere…" (2mo ago)`},
+	}
+	for _, tc := range cases {
+		if got := formatLastCommitLabel(core.WorktreeInfo{LastCommit: tc.commit}, now); got != tc.want {
+			t.Fatalf("expected %q, got %q", tc.want, got)
+		}
+	}
+}
+
+func TestFailedGHRefreshKeepsDataButFlagsItStale(t *testing.T) {
+	m := newModel()
+	m.mode = modeList
+	m.ready = true
+	m.status = core.WorktreeStatus{
+		InRepo:       true,
+		GitInstalled: true,
+		RepoRoot:     "/repo",
+		HasRemote:    true,
+		Worktrees:    []core.WorktreeInfo{{Path: "/repo/wt.1", Branch: "feature", Available: true}},
+	}
+	key := ghDataKeyForStatus(m.status)
+	m.ghFetchingKey = key
+	updatedModel, _ := m.Update(ghDataMsg{
+		repoRoot:        "/repo",
+		key:             key,
+		fetchedByBranch: true,
+		byBranch:        map[string]core.PRData{"feature": {Number: 7, CIState: core.PRCISuccess, CICompleted: 2, CITotal: 2}},
+	})
+	updated := updatedModel.(model)
+	if updated.ghStale || updated.ghUpdatedAt.IsZero() {
+		t.Fatalf("expected fresh PR data after a successful fetch")
+	}
+	if !strings.Contains(updated.View(), "PR data updated") {
+		t.Fatalf("expected freshness in header, got:\n%s", updated.View())
+	}
+
+	updated.ghFetchingKey = key
+	updatedModel, _ = updated.Update(ghDataMsg{
+		repoRoot:        "/repo",
+		key:             key,
+		fetchedByBranch: true,
+		byBranch:        map[string]core.PRData{},
+		err:             errors.New("gh: HTTP 502"),
+	})
+	updated = updatedModel.(model)
+	if !updated.ghStale {
+		t.Fatalf("expected PR data to be flagged stale after gh failed")
+	}
+	if got := updated.status.Worktrees[0].PRNumber; got != 7 {
+		t.Fatalf("expected last good PR data to be kept, got PR %d", got)
+	}
+	if !strings.Contains(updated.View(), "PR data stale") {
+		t.Fatalf("expected stale warning in header, got:\n%s", updated.View())
+	}
+}
+
+func TestConfirmedDeleteCanBeUndoneDuringGracePeriod(t *testing.T) {
+	m := newModel()
+	m.mode = modeDelete
+	m.ready = true
+	m.status = core.WorktreeStatus{
+		InRepo:       true,
+		GitInstalled: true,
+		Worktrees: []core.WorktreeInfo{
+			{Path: "/repo/wt.1", Branch: "keep", Available: true},
+			{Path: "/repo/wt.2", Branch: "oops", Available: true},
+		},
+	}
+	m.deletePath = "/repo/wt.2"
+	m.deleteBranch = "oops"
+	m.confirmKind = confirmDelete
+	m.confirmResult = true
+
+	updatedModel, cmd := m.handleConfirmDone()
+	updated := updatedModel.(model)
+	if cmd == nil || len(updated.pendingDeletes) != 1 {
+		t.Fatalf("expected the delete to wait out a grace period")
+	}
+	if _, _, ok := findWorktreeByPath(updated.listStatus(), "/repo/wt.2"); ok {
+		t.Fatalf("expected the pending delete to be hidden from the table")
+	}
+	if !strings.Contains(updated.View(), "Deleting oops in") {
+		t.Fatalf("expected an undo toast, got:\n%s", updated.View())
+	}
+
+	updatedModel, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'z'}})
+	updated = updatedModel.(model)
+	if len(updated.pendingDeletes) != 0 {
+		t.Fatalf("expected z to undo the delete")
+	}
+	if _, _, ok := findWorktreeByPath(updated.listStatus(), "/repo/wt.2"); !ok {
+		t.Fatalf("expected the worktree back in the table after undo")
+	}
+	if _, cmd = updated.Update(pendingDeleteDueMsg{id: 1}); cmd != nil {
+		t.Fatalf("expected an undone delete not to run when its timer fires")
+	}
+}
+
+func TestDueDeleteWaitsForRunningBulkAction(t *testing.T) {
+	m := newModel()
+	m.ready = true
+	m.status = core.WorktreeStatus{
+		InRepo:       true,
+		GitInstalled: true,
+		Worktrees: []core.WorktreeInfo{
+			{Path: "/repo/wt.1", Branch: "busy", Available: true},
+			{Path: "/repo/wt.2", Branch: "oops", Available: true},
+		},
+	}
+	m.bulkRunning = true
+	m.bulkAction = bulkUnlock
+	m.pendingDeletes = []pendingDelete{{id: 1, worktrees: []core.WorktreeInfo{m.status.Worktrees[1]}}}
+
+	updatedModel, cmd := m.Update(pendingDeleteDueMsg{id: 1})
+	updated := updatedModel.(model)
+	if cmd != nil || updated.bulkAction != bulkUnlock || len(updated.pendingDeletes) != 1 || !updated.pendingDeletes[0].queued {
+		t.Fatalf("expected the due delete to queue behind the running bulk action")
+	}
+	if _, _, ok := findWorktreeByPath(updated.listStatus(), "/repo/wt.2"); ok {
+		t.Fatalf("expected the queued delete to stay hidden from the table")
+	}
+
+	updatedModel, cmd = updated.Update(bulkActionDoneMsg{action: bulkUnlock, done: []string{"/repo/wt.1"}})
+	updated = updatedModel.(model)
+	if cmd == nil || !updated.bulkRunning || updated.bulkAction != bulkDelete || len(updated.pendingDeletes) != 0 {
+		t.Fatalf("expected the queued delete to start once the bulk action finished")
+	}
+}
+
+func TestPRLoadingIsPerRow(t *testing.T) {
+	m := newModel()
+	m.mode = modeList
+	status := core.WorktreeStatus{
+		InRepo:       true,
+		GitInstalled: true,
+		RepoRoot:     "/repo",
+		HasRemote:    true,
+		Worktrees:    []core.WorktreeInfo{{Path: "/repo/wt.1", Branch: "alpha", Available: true}},
+	}
+	updatedModel, _ := m.Update(statusMsg(status))
+	m = updatedModel.(model)
+	if !m.ghPendingByBranch["alpha"] {
+		t.Fatalf("expected alpha to show as loading before any gh fetch")
+	}
+
+	m.ghFetchingKey = ghDataKeyForStatus(m.status)
+	updatedModel, _ = m.Update(ghDataMsg{
+		repoRoot:        "/repo",
+		key:             m.ghFetchingKey,
+		byBranch:        map[string]core.PRData{"alpha": {Number: 7}},
+		branches:        map[string]bool{"alpha": true},
+		fetchedByBranch: true,
+	})
+	m = updatedModel.(model)
+
+	status.Worktrees = append(status.Worktrees, core.WorktreeInfo{Path: "/repo/wt.2", Branch: "beta", Available: true})
+	updatedModel, _ = m.Update(statusMsg(status))
+	m = updatedModel.(model)
+	if m.ghPendingByBranch["alpha"] || !m.ghPendingByBranch["beta"] {
+		t.Fatalf("expected only the new beta row to be loading, got %v", m.ghPendingByBranch)
+	}
+	view := m.View()
+	if !strings.Contains(view, "#7") {
+		t.Fatalf("expected alpha to keep its PR number while beta loads, got:\n%s", view)
+	}
+}
+
+func TestLocalOnlyRepoSkipsPRColumnsAndLookups(t *testing.T) {
+	status := core.WorktreeStatus{
+		InRepo:    true,
+		RepoRoot:  "/repo",
+		Worktrees: []core.WorktreeInfo{{Path: "/repo/wt.1", Branch: "feature", Available: true}},
+	}
+	if key := ghDataKeyForStatus(status); key != "" {
+		t.Fatalf("expected no gh lookups without a remote, got key %q", key)
+	}
+	table := renderSelector(status, 0, nil, false, 0, nil, "")
+	if strings.Contains(table, "PR Status") || strings.Contains(table, "Approval") {
+		t.Fatalf("expected PR columns hidden without a remote, got\n%s", table)
+	}
+	m := newModel()
+	m.status = status
+	if got := stripANSI(renderPRFreshness(m, time.Now())); got != "local only" {
+		t.Fatalf("expected local only label, got %q", got)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/aixolotls/wtx/internal/core"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -14,12 +15,15 @@ type interactiveUpdateHintMsg struct {
 }
 
 func checkInteractiveUpdateHintCmd() tea.Cmd {
+	if !updateChecksEnabled() {
+		return nil
+	}
 	return func() tea.Msg {
-		cur := strings.TrimSpace(currentVersion())
+		cur := strings.TrimSpace(core.CurrentVersion())
 		ctx, cancel := context.WithTimeout(context.Background(), startupUpdateTimeout)
 		defer cancel()
 
-		result, err := checkForUpdatesWithThrottle(ctx, cur, defaultUpdateInterval)
+		result, err := checkForUpdatesWithThrottle(ctx, cur, updateCheckInterval())
 		hint, isError := formatInteractiveUpdateHint(cur, result, err)
 		return interactiveUpdateHintMsg{hint: hint, isError: isError}
 	}
@@ -34,7 +38,7 @@ func formatInteractiveUpdateHint(current string, result updateCheckResult, err e
 		return fmt.Sprintf("wtx update check failed: %v", err), true
 	}
 	if result.UpdateAvailable {
-		return fmt.Sprintf(wtxUpdateCommandFormat, result.CurrentVersion, result.LatestVersion), false
+		return formatUpdateAvailableHint(result), false
 	}
 	if strings.TrimSpace(result.ResolveError) != "" {
 		return fmt.Sprintf("wtx update check failed: %s", strings.TrimSpace(result.ResolveError)), true
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"archive/tar"
+	"archive/zip"
 	"compress/gzip"
 	"context"
 	"crypto/sha256"
@@ -12,12 +13,15 @@ import (
 	"net/http"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"regexp"
 	"runtime"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/aixolotls/wtx/internal/core"
 )
 
 const (
@@ -29,22 +33,44 @@ const (
 	installUpdateTimeout   = 2 * time.Minute
 	updateStateFileName    = "update-state.json"
 	wtxUpdateCommandFormat = "wtx %s -> %s available. Run: wtx update"
+	wtxPrereleaseFormat    = "wtx %s -> %s available (prerelease channel). Run: wtx update"
 	releaseArchiveFormat   = "wtx_%s_%s.tar.gz"
+	releaseZipFormat       = "wtx_%s_%s.zip"
+	replacedExeSuffix      = ".old"
 	releaseDownloadFormat  = "https://github.com/%s/releases/download/%s/%s"
 )
 
-var releaseVersionPattern = regexp.MustCompile(`^v(\d+)\.(\d+)\.(\d+)$`)
+var versionPattern = regexp.MustCompile(`^v(\d+)\.(\d+)\.(\d+)(?:-([0-9A-Za-z]+(?:\.[0-9A-Za-z]+)*))?$`)
 var resolveLatestVersionFn = resolveLatestVersion
+var lookPathForUpdateFn = exec.LookPath
+
+// packageInstall describes a wtx binary owned by a package manager, which
+// self-update must not overwrite.
+type packageInstall struct {
+	Manager        string
+	UpgradeCommand []string
+}
+
+const (
+	updateChannelStable     = "stable"
+	updateChannelPrerelease = "prerelease"
+)
+
+var updateChannels = []string{updateChannelStable, updateChannelPrerelease}
+
+var updateCheckFrequencies = []string{core.UpdateCheckOff, core.UpdateCheckDaily, core.UpdateCheckWeekly}
 
 type parsedVersion struct {
 	Major int
 	Minor int
 	Patch int
+	Pre   string
 }
 
 type updateState struct {
 	LastCheckedUnix int64  `json:"last_checked_unix"`
 	LastSeenVersion string `json:"last_seen_version,omitempty"`
+	LastSeenChannel string `json:"last_seen_channel,omitempty"`
 }
 
 type updateCheckResult struct {
@@ -52,9 +78,10 @@ type updateCheckResult struct {
 	LatestVersion   string
 	UpdateAvailable bool
 	ResolveError    string
+	Channel         string
 }
 
-func runUpdateCommand(checkOnly bool, quiet bool) error {
+func runUpdateCommand(checkOnly bool, quiet bool, skipVerify bool) error {
 	ctx, cancel := context.WithTimeout(context.Background(), resolveUpdateTimeout)
 	defer cancel()
 
@@ -62,16 +89,20 @@ func runUpdateCommand(checkOnly bool, quiet bool) error {
 	if err != nil {
 		return err
 	}
-	cur := currentVersion()
+	cur := core.CurrentVersion()
 
 	result := updateCheckResult{
 		CurrentVersion:  cur,
 		LatestVersion:   latest,
 		UpdateAvailable: isUpdateAvailableForInstall(cur, latest),
+		Channel:         configuredUpdateChannel(),
 	}
 
 	if checkOnly {
 		printUpdateCheckResult(result, quiet)
+		if result.UpdateAvailable && !quiet {
+			printReleaseNotes(os.Stdout, result.LatestVersion)
+		}
 		return nil
 	}
 
@@ -91,7 +122,10 @@ func runUpdateCommand(checkOnly bool, quiet bool) error {
 		stopSpinner = startDelayedSpinner(fmt.Sprintf("Updating wtx to %s...", result.LatestVersion), 0)
 	}
 	defer stopSpinner()
-	if err := installVersion(installCtx, result.LatestVersion); err != nil {
+	if skipVerify {
+		fmt.Fprintln(os.Stderr, "wtx warning: skipping release signature verification")
+	}
+	if err := installRelease(installCtx, result.LatestVersion, !skipVerify); err != nil {
 		return err
 	}
 
@@ -118,6 +152,10 @@ func printUpdateCheckResultTo(w io.Writer, result updateCheckResult, quiet bool)
 	}
 
 	if result.UpdateAvailable {
+		if result.Channel == updateChannelPrerelease {
+			fmt.Fprintf(w, "Update available: wtx %s -> %s (prerelease channel)\n", result.CurrentVersion, result.LatestVersion)
+			return
+		}
 		fmt.Fprintf(w, "Update available: wtx %s -> %s\n", result.CurrentVersion, result.LatestVersion)
 		return
 	}
@@ -125,21 +163,40 @@ func printUpdateCheckResultTo(w io.Writer, result updateCheckResult, quiet bool)
 }
 
 func maybeStartInvocationUpdateCheck(args []string) {
-	if !shouldRunInvocationUpdateCheck(args) {
+	if !shouldRunInvocationUpdateCheck(args) || !updateChecksEnabled() {
 		return
 	}
 	go func() {
 		ctx, cancel := context.WithTimeout(context.Background(), startupUpdateTimeout)
 		defer cancel()
 
-		result, err := checkForUpdatesWithThrottle(ctx, currentVersion(), defaultUpdateInterval)
+		result, err := checkForUpdatesWithThrottle(ctx, core.CurrentVersion(), updateCheckInterval())
 		if err != nil || !result.UpdateAvailable {
 			return
 		}
-		fmt.Fprintf(os.Stderr, wtxUpdateCommandFormat+"\n", result.CurrentVersion, result.LatestVersion)
+		fmt.Fprintln(os.Stderr, formatUpdateAvailableHint(result))
 	}()
 }
 
+// formatUpdateAvailableHint is the one-line nudge shown when a newer version
+// exists on the configured channel.
+func formatUpdateAvailableHint(result updateCheckResult) string {
+	if result.Channel == updateChannelPrerelease {
+		return fmt.Sprintf(wtxPrereleaseFormat, result.CurrentVersion, result.LatestVersion)
+	}
+	return fmt.Sprintf(wtxUpdateCommandFormat, result.CurrentVersion, result.LatestVersion)
+}
+
+// configuredUpdateChannel returns the update_channel from config; stable
+// unless the user opted into prereleases.
+func configuredUpdateChannel() string {
+	cfg, err := core.LoadConfig()
+	if err == nil && strings.EqualFold(strings.TrimSpace(cfg.UpdateChannel), updateChannelPrerelease) {
+		return updateChannelPrerelease
+	}
+	return updateChannelStable
+}
+
 func shouldRunInvocationUpdateCheck(args []string) bool {
 	if len(args) <= 1 {
 		return false
@@ -149,7 +206,7 @@ func shouldRunInvocationUpdateCheck(args []string) bool {
 		return true
 	}
 	switch name {
-	case "-v", "--version", "co", "checkout", "pr", "tmux-status", "tmux-title", "tmux-agent-start", "tmux-agent-exit", "tmux-actions", "completion", "__complete", "__completeNoDesc", "update":
+	case "-v", "--version", "co", "checkout", "pr", "tmux-status", "tmux-title", "tmux-agent-start", "tmux-agent-exit", "tmux-actions", "completion", "__complete", "__completeNoDesc", "update", "daemon", "prompt", "pick", "serve", "mcp", "job-run":
 		return false
 	default:
 		return true
@@ -160,12 +217,20 @@ func checkForUpdatesWithThrottle(ctx context.Context, currentVersion string, int
 	currentVersion = strings.TrimSpace(currentVersion)
 	state, _ := readUpdateState()
 	now := time.Now()
+	channel := configuredUpdateChannel()
+	if stateChannel := strings.TrimSpace(state.LastSeenChannel); channel != stateChannel && !(stateChannel == "" && channel == updateChannelStable) {
+		// The cached version was resolved for another channel.
+		state.LastCheckedUnix = 0
+		state.LastSeenVersion = ""
+	}
+	state.LastSeenChannel = channel
 	cachedLatest := strings.TrimSpace(state.LastSeenVersion)
 	if !shouldCheckForUpdates(state.LastCheckedUnix, now, interval) {
 		return updateCheckResult{
 			CurrentVersion:  currentVersion,
 			LatestVersion:   cachedLatest,
 			UpdateAvailable: isUpdateAvailableForInstall(currentVersion, cachedLatest),
+			Channel:         channel,
 		}, nil
 	}
 
@@ -179,6 +244,7 @@ func checkForUpdatesWithThrottle(ctx context.Context, currentVersion string, int
 			CurrentVersion:  currentVersion,
 			LatestVersion:   latest,
 			UpdateAvailable: isUpdateAvailableForInstall(currentVersion, latest),
+			Channel:         channel,
 		}, nil
 	}
 	if strings.TrimSpace(cachedLatest) != "" {
@@ -189,6 +255,7 @@ func checkForUpdatesWithThrottle(ctx context.Context, currentVersion string, int
 			LatestVersion:   cachedLatest,
 			UpdateAvailable: isUpdateAvailableForInstall(currentVersion, cachedLatest),
 			ResolveError:    err.Error(),
+			Channel:         channel,
 		}, nil
 	}
 	return updateCheckResult{}, err
@@ -206,11 +273,14 @@ func shouldCheckForUpdates(lastCheckedUnix int64, now time.Time, interval time.D
 }
 
 func resolveLatestVersion(ctx context.Context) (string, error) {
+	if base := updateBaseURL(); base != "" {
+		return resolveLatestMirrorVersion(ctx, base)
+	}
 	output, err := runCommand(ctx, "git", []string{"ls-remote", "--tags", "--refs", updateRepoGitURL}, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to resolve latest version: %w", err)
 	}
-	latest, ok := latestVersionFromLSRemoteOutput(output)
+	latest, ok := latestVersionFromLSRemoteOutput(output, configuredUpdateChannel())
 	if !ok {
 		return "", errors.New("failed to resolve latest version: no semver tags found")
 	}
@@ -218,16 +288,32 @@ func resolveLatestVersion(ctx context.Context) (string, error) {
 }
 
 func installVersion(ctx context.Context, targetVersion string) error {
+	return installRelease(ctx, targetVersion, true)
+}
+
+// installRelease downloads and installs targetVersion. verifySignature
+// requires a valid signature over checksums.txt, and fails outright when the
+// binary embeds no release key.
+func installRelease(ctx context.Context, targetVersion string, verifySignature bool) error {
 	targetVersion = strings.TrimSpace(targetVersion)
-	if !isReleaseVersion(targetVersion) {
+	if _, ok := parseVersion(targetVersion); !ok {
 		return fmt.Errorf("invalid target version %q", targetVersion)
 	}
+	if exePath, err := currentExecutablePath(); err == nil {
+		if pkg, ok := detectPackageInstall(exePath); ok {
+			return upgradeWithPackageManager(ctx, pkg)
+		}
+	}
+	if verifySignature && strings.TrimSpace(releaseSigningPublicKey) == "" {
+		return errNoReleaseSigningKey
+	}
 	assetName, err := releaseArchiveName()
 	if err != nil {
 		return err
 	}
-	archiveURL := fmt.Sprintf(releaseDownloadFormat, updateRepoPath, targetVersion, assetName)
-	checksumsURL := fmt.Sprintf(releaseDownloadFormat, updateRepoPath, targetVersion, "checksums.txt")
+	archiveURL := releaseAssetURL(targetVersion, assetName)
+	checksumsURL := releaseAssetURL(targetVersion, "checksums.txt")
+	signatureURL := releaseAssetURL(targetVersion, checksumsSignatureFileName)
 
 	tmpDir, err := os.MkdirTemp("", "wtx-update-*")
 	if err != nil {
@@ -237,7 +323,8 @@ func installVersion(ctx context.Context, targetVersion string) error {
 
 	archivePath := filepath.Join(tmpDir, assetName)
 	checksumsPath := filepath.Join(tmpDir, "checksums.txt")
-	extractedBinPath := filepath.Join(tmpDir, "wtx")
+	signaturePath := filepath.Join(tmpDir, checksumsSignatureFileName)
+	extractedBinPath := filepath.Join(tmpDir, releaseBinaryName())
 
 	if err := downloadFile(ctx, archiveURL, archivePath); err != nil {
 		return fmt.Errorf("failed to download release archive: %w", err)
@@ -245,12 +332,29 @@ func installVersion(ctx context.Context, targetVersion string) error {
 	if err := downloadFile(ctx, checksumsURL, checksumsPath); err != nil {
 		return fmt.Errorf("failed to download checksums: %w", err)
 	}
+	if verifySignature {
+		if err := downloadFile(ctx, signatureURL, signaturePath); err != nil {
+			return fmt.Errorf("failed to download checksums signature: %w", err)
+		}
+		if err := verifyChecksumsSignature(checksumsPath, signaturePath); err != nil {
+			return fmt.Errorf("failed signature verification (use --insecure-skip-verify to bypass): %w", err)
+		}
+	}
 	if err := verifyArchiveChecksum(archivePath, checksumsPath, assetName); err != nil {
 		return fmt.Errorf("failed checksum verification: %w", err)
 	}
-	if err := extractBinaryFromTarGz(archivePath, extractedBinPath); err != nil {
+	extract := extractBinaryFromTarGz
+	if strings.HasSuffix(assetName, ".zip") {
+		extract = extractBinaryFromZip
+	}
+	if err := extract(archivePath, extractedBinPath); err != nil {
 		return fmt.Errorf("failed to extract archive: %w", err)
 	}
+	if exePath, err := currentExecutablePath(); err == nil {
+		if err := backupExecutable(exePath, core.CurrentVersion()); err != nil {
+			fmt.Fprintln(os.Stderr, "wtx warning: could not keep the current version for rollback:", err)
+		}
+	}
 	if err := replaceCurrentExecutable(extractedBinPath); err != nil {
 		return fmt.Errorf("failed to install updated binary: %w", err)
 	}
@@ -260,7 +364,7 @@ func installVersion(ctx context.Context, targetVersion string) error {
 func releaseArchiveName() (string, error) {
 	goos := strings.TrimSpace(runtime.GOOS)
 	switch goos {
-	case "darwin", "linux":
+	case "darwin", "linux", "windows":
 	default:
 		return "", fmt.Errorf("unsupported OS for self-update: %s", goos)
 	}
@@ -270,24 +374,90 @@ func releaseArchiveName() (string, error) {
 	default:
 		return "", fmt.Errorf("unsupported architecture for self-update: %s", goarch)
 	}
+	if goos == "windows" {
+		return fmt.Sprintf(releaseZipFormat, goos, goarch), nil
+	}
 	return fmt.Sprintf(releaseArchiveFormat, goos, goarch), nil
 }
 
+func releaseBinaryName() string {
+	if runtime.GOOS == "windows" {
+		return "wtx.exe"
+	}
+	return "wtx"
+}
+
+var downloadRetryDelays = []time.Duration{time.Second, 3 * time.Second, 9 * time.Second}
+
+type downloadStatusError struct {
+	URL        string
+	Status     string
+	StatusCode int
+	Body       string
+}
+
+func (e *downloadStatusError) Error() string {
+	return fmt.Sprintf("GET %s returned %s: %s", e.URL, e.Status, e.Body)
+}
+
+// downloadFile fetches url into targetPath, retrying transient failures with
+// backoff and resuming from the partial file when the server supports ranges.
 func downloadFile(ctx context.Context, url string, targetPath string) error {
+	_ = os.Remove(targetPath)
+	validator := ""
+	for attempt := 0; ; attempt++ {
+		err := downloadAttempt(ctx, url, targetPath, &validator)
+		if err == nil || !retryableDownloadError(err) || attempt >= len(downloadRetryDelays) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(downloadRetryDelays[attempt]):
+		}
+	}
+}
+
+// downloadAttempt resumes targetPath only when validator (the ETag or
+// Last-Modified seen earlier) proves the partial file came from the same
+// resource; otherwise it starts over.
+func downloadAttempt(ctx context.Context, url string, targetPath string, validator *string) error {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return err
 	}
+	var offset int64
+	if info, err := os.Stat(targetPath); err == nil && info.Size() > 0 && *validator != "" {
+		offset = info.Size()
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		req.Header.Set("If-Range", *validator)
+	}
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0 && contentRangeStart(resp.Header.Get("Content-Range")) == offset:
+		flags = os.O_WRONLY | os.O_APPEND
+	case resp.StatusCode == http.StatusOK:
+	default:
 		body, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
-		return fmt.Errorf("GET %s returned %s: %s", url, resp.Status, strings.TrimSpace(string(body)))
+		if resp.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+			// The partial file no longer lines up; start over next attempt.
+			_ = os.Remove(targetPath)
+			*validator = ""
+		}
+		return &downloadStatusError{URL: url, Status: resp.Status, StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(body))}
 	}
-	f, err := os.Create(targetPath)
+	*validator = resp.Header.Get("ETag")
+	if *validator == "" {
+		*validator = resp.Header.Get("Last-Modified")
+	}
+
+	f, err := os.OpenFile(targetPath, flags, 0o644)
 	if err != nil {
 		return err
 	}
@@ -298,6 +468,35 @@ func downloadFile(ctx context.Context, url string, targetPath string) error {
 	return f.Close()
 }
 
+func contentRangeStart(header string) int64 {
+	header = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(header), "bytes"))
+	start, _, ok := strings.Cut(header, "-")
+	if !ok {
+		return -1
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(start), 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+func retryableDownloadError(err error) bool {
+	var statusErr *downloadStatusError
+	if errors.As(err, &statusErr) {
+		switch statusErr.StatusCode {
+		case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusRequestedRangeNotSatisfiable:
+			return true
+		}
+		return statusErr.StatusCode >= 500
+	}
+	var pathErr *os.PathError
+	if errors.As(err, &pathErr) {
+		return false
+	}
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
 func verifyArchiveChecksum(archivePath string, checksumsPath string, archiveName string) error {
 	checksumLine, err := checksumLineForFile(checksumsPath, archiveName)
 	if err != nil {
@@ -363,7 +562,7 @@ func extractBinaryFromTarGz(archivePath string, outPath string) error {
 		if header.Typeflag != tar.TypeReg {
 			continue
 		}
-		if filepath.Base(strings.TrimSpace(header.Name)) != "wtx" {
+		if filepath.Base(strings.TrimSpace(header.Name)) != releaseBinaryName() {
 			continue
 		}
 		out, err := os.OpenFile(outPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o755)
@@ -379,20 +578,86 @@ func extractBinaryFromTarGz(archivePath string, outPath string) error {
 		}
 		return os.Chmod(outPath, 0o755)
 	}
-	return errors.New("binary wtx not found in archive")
+	return fmt.Errorf("binary %s not found in archive", releaseBinaryName())
 }
 
-func replaceCurrentExecutable(newBinPath string) error {
-	exePath, err := os.Executable()
+func extractBinaryFromZip(archivePath string, outPath string) error {
+	reader, err := zip.OpenReader(archivePath)
 	if err != nil {
 		return err
 	}
+	defer reader.Close()
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() || path.Base(strings.TrimSpace(file.Name)) != releaseBinaryName() {
+			continue
+		}
+		in, err := file.Open()
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		out, err := os.OpenFile(outPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o755)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, in); err != nil {
+			out.Close()
+			return err
+		}
+		return out.Close()
+	}
+	return fmt.Errorf("binary %s not found in archive", releaseBinaryName())
+}
+
+// detectPackageInstall reports whether exePath belongs to Homebrew, Nix or a
+// distro package rather than a self-managed install.
+func detectPackageInstall(exePath string) (packageInstall, bool) {
+	exePath = filepath.ToSlash(filepath.Clean(strings.TrimSpace(exePath)))
+	switch {
+	case strings.Contains(exePath, "/Cellar/") || strings.Contains(exePath, "/linuxbrew/") || strings.HasPrefix(exePath, "/opt/homebrew/"):
+		return packageInstall{Manager: "Homebrew", UpgradeCommand: []string{"brew", "upgrade", "wtx"}}, true
+	case strings.HasPrefix(exePath, "/nix/store/"):
+		return packageInstall{Manager: "Nix"}, true
+	case strings.HasPrefix(exePath, "/usr/bin/") || strings.HasPrefix(exePath, "/usr/lib/") || strings.HasPrefix(exePath, "/usr/libexec/") || strings.HasPrefix(exePath, "/usr/share/"):
+		return packageInstall{Manager: "your system package manager"}, true
+	}
+	return packageInstall{}, false
+}
+
+func upgradeWithPackageManager(ctx context.Context, pkg packageInstall) error {
+	if len(pkg.UpgradeCommand) == 0 {
+		return fmt.Errorf("wtx is installed by %s; upgrade it with %s instead of wtx update", pkg.Manager, pkg.Manager)
+	}
+	command := strings.Join(pkg.UpgradeCommand, " ")
+	if _, err := lookPathForUpdateFn(pkg.UpgradeCommand[0]); err != nil {
+		return fmt.Errorf("wtx is installed by %s; run: %s", pkg.Manager, command)
+	}
+	output, err := runCommand(ctx, pkg.UpgradeCommand[0], pkg.UpgradeCommand[1:], nil)
+	if err != nil {
+		return fmt.Errorf("%s failed: %w", command, core.CommandErrorWithOutput(err, []byte(output)))
+	}
+	return nil
+}
+
+func currentExecutablePath() (string, error) {
+	exePath, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
 	if resolved, err := filepath.EvalSymlinks(exePath); err == nil && strings.TrimSpace(resolved) != "" {
 		exePath = resolved
 	}
 	exePath = filepath.Clean(strings.TrimSpace(exePath))
 	if exePath == "" {
-		return errors.New("current executable path is empty")
+		return "", errors.New("current executable path is empty")
+	}
+	return exePath, nil
+}
+
+func replaceCurrentExecutable(newBinPath string) error {
+	exePath, err := currentExecutablePath()
+	if err != nil {
+		return err
 	}
 	targetDir := filepath.Dir(exePath)
 	tmpPath := filepath.Join(targetDir, ".wtx-update-tmp")
@@ -419,6 +684,9 @@ func replaceCurrentExecutable(newBinPath string) error {
 		_ = os.Remove(tmpPath)
 		return err
 	}
+	if runtime.GOOS == "windows" {
+		return swapRunningExecutable(tmpPath, exePath)
+	}
 	if err := os.Rename(tmpPath, exePath); err != nil {
 		_ = os.Remove(tmpPath)
 		return err
@@ -426,6 +694,37 @@ func replaceCurrentExecutable(newBinPath string) error {
 	return nil
 }
 
+// swapRunningExecutable replaces exePath while it is running. Windows can't
+// overwrite a running exe but can rename it, so the old binary is moved
+// aside and removed by cleanupReplacedExecutable on the next start.
+func swapRunningExecutable(newPath string, exePath string) error {
+	oldPath := exePath + replacedExeSuffix
+	_ = os.Remove(oldPath)
+	if err := os.Rename(exePath, oldPath); err != nil {
+		_ = os.Remove(newPath)
+		return err
+	}
+	if err := os.Rename(newPath, exePath); err != nil {
+		_ = os.Rename(oldPath, exePath)
+		_ = os.Remove(newPath)
+		return err
+	}
+	return nil
+}
+
+// cleanupReplacedExecutable removes the binary a previous Windows update
+// moved aside.
+func cleanupReplacedExecutable() {
+	if runtime.GOOS != "windows" {
+		return
+	}
+	exePath, err := currentExecutablePath()
+	if err != nil {
+		return
+	}
+	_ = os.Remove(exePath + replacedExeSuffix)
+}
+
 func shouldRetryInstallForSumDB(output string) bool {
 	lower := strings.ToLower(strings.TrimSpace(output))
 	if lower == "" {
@@ -443,12 +742,11 @@ func shouldRetryInstallForSumDB(output string) bool {
 	return false
 }
 
-func latestVersionFromLSRemoteOutput(output string) (string, bool) {
-	var bestRaw string
-	var best parsedVersion
-	found := false
-	lines := strings.Split(output, "\n")
-	for _, line := range lines {
+// latestVersionFromLSRemoteOutput picks the highest tag; prerelease tags only
+// count on the prerelease channel.
+func latestVersionFromLSRemoteOutput(output string, channel string) (string, bool) {
+	tags := []string{}
+	for _, line := range strings.Split(output, "\n") {
 		fields := strings.Fields(strings.TrimSpace(line))
 		if len(fields) < 2 {
 			continue
@@ -457,9 +755,19 @@ func latestVersionFromLSRemoteOutput(output string) (string, bool) {
 		if !strings.HasPrefix(ref, "refs/tags/") {
 			continue
 		}
-		candidate := strings.TrimPrefix(ref, "refs/tags/")
-		parsed, ok := parseReleaseVersion(candidate)
-		if !ok {
+		tags = append(tags, strings.TrimPrefix(ref, "refs/tags/"))
+	}
+	return latestVersionFromTags(tags, channel)
+}
+
+func latestVersionFromTags(tags []string, channel string) (string, bool) {
+	var bestRaw string
+	var best parsedVersion
+	found := false
+	for _, candidate := range tags {
+		candidate = strings.TrimSpace(candidate)
+		parsed, ok := parseVersion(candidate)
+		if !ok || (parsed.Pre != "" && channel != updateChannelPrerelease) {
 			continue
 		}
 		if !found || compareReleaseVersions(parsed, best) > 0 {
@@ -472,8 +780,8 @@ func latestVersionFromLSRemoteOutput(output string) (string, bool) {
 }
 
 func isUpdateAvailable(currentVersion string, latestVersion string) bool {
-	current, okCurrent := parseReleaseVersion(strings.TrimSpace(currentVersion))
-	latest, okLatest := parseReleaseVersion(strings.TrimSpace(latestVersion))
+	current, okCurrent := parseVersion(strings.TrimSpace(currentVersion))
+	latest, okLatest := parseVersion(strings.TrimSpace(latestVersion))
 	if !okCurrent || !okLatest {
 		return false
 	}
@@ -489,17 +797,24 @@ func isUpdateAvailableForInstall(currentVersion string, latestVersion string) bo
 	if isUpdateAvailable(currentVersion, latestVersion) {
 		return true
 	}
-	return !isReleaseVersion(currentVersion) && isReleaseVersion(latestVersion)
+	_, okCurrent := parseVersion(currentVersion)
+	_, okLatest := parseVersion(latestVersion)
+	return !okCurrent && okLatest
 }
 
-func isReleaseVersion(version string) bool {
-	_, ok := parseReleaseVersion(version)
-	return ok
+// parseReleaseVersion accepts final releases only (vX.Y.Z).
+func parseReleaseVersion(version string) (parsedVersion, bool) {
+	parsed, ok := parseVersion(version)
+	if !ok || parsed.Pre != "" {
+		return parsedVersion{}, false
+	}
+	return parsed, true
 }
 
-func parseReleaseVersion(version string) (parsedVersion, bool) {
-	match := releaseVersionPattern.FindStringSubmatch(strings.TrimSpace(version))
-	if len(match) != 4 {
+// parseVersion accepts final releases and prereleases (vX.Y.Z-rc.1).
+func parseVersion(version string) (parsedVersion, bool) {
+	match := versionPattern.FindStringSubmatch(strings.TrimSpace(version))
+	if len(match) != 5 {
 		return parsedVersion{}, false
 	}
 	major, err := strconv.Atoi(match[1])
@@ -514,7 +829,7 @@ func parseReleaseVersion(version string) (parsedVersion, bool) {
 	if err != nil {
 		return parsedVersion{}, false
 	}
-	return parsedVersion{Major: major, Minor: minor, Patch: patch}, true
+	return parsedVersion{Major: major, Minor: minor, Patch: patch, Pre: match[4]}, true
 }
 
 func compareReleaseVersions(a parsedVersion, b parsedVersion) int {
@@ -536,15 +851,55 @@ func compareReleaseVersions(a parsedVersion, b parsedVersion) int {
 		}
 		return -1
 	}
+	return comparePrerelease(a.Pre, b.Pre)
+}
+
+// comparePrerelease orders prerelease suffixes per semver: a final release
+// sorts after any prerelease, numeric identifiers compare numerically.
+func comparePrerelease(a string, b string) int {
+	if a == b {
+		return 0
+	}
+	if a == "" {
+		return 1
+	}
+	if b == "" {
+		return -1
+	}
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		aNum, aErr := strconv.Atoi(aParts[i])
+		bNum, bErr := strconv.Atoi(bParts[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if aNum != bNum {
+				if aNum > bNum {
+					return 1
+				}
+				return -1
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(aParts[i], bParts[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	switch {
+	case len(aParts) > len(bParts):
+		return 1
+	case len(aParts) < len(bParts):
+		return -1
+	}
 	return 0
 }
 
 func runCommand(ctx context.Context, name string, args []string, extraEnv []string) (string, error) {
-	cmd := exec.CommandContext(ctx, name, args...)
-	if len(extraEnv) > 0 {
-		cmd.Env = append(os.Environ(), extraEnv...)
-	}
-	output, err := cmd.CombinedOutput()
+	output, err := core.RunExternalCommand(ctx, core.CommandSpec{Name: name, Args: args, Env: extraEnv})
 	if err != nil {
 		if ctxErr := ctx.Err(); ctxErr != nil {
 			return string(output), ctxErr
@@ -590,9 +945,9 @@ func writeUpdateState(state updateState) error {
 }
 
 func updateStatePath() (string, error) {
-	home := strings.TrimSpace(os.Getenv("HOME"))
-	if home == "" {
-		return "", errors.New("HOME not set")
+	home, err := core.UserHomeDir()
+	if err != nil {
+		return "", err
 	}
 	return filepath.Join(home, ".wtx", updateStateFileName), nil
 }
@@ -1,13 +1,20 @@
 package cmd
 
 import (
+	"archive/zip"
 	"context"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/aixolotls/wtx/internal/core"
 )
 
 func TestParseReleaseVersion(t *testing.T) {
@@ -48,7 +55,7 @@ func TestLatestVersionFromLSRemoteOutput(t *testing.T) {
 		"abc refs/tags/v1.10.0\n" +
 		"abc refs/tags/v2.0.0-rc1\n"
 
-	got, ok := latestVersionFromLSRemoteOutput(output)
+	got, ok := latestVersionFromLSRemoteOutput(output, updateChannelStable)
 	if !ok {
 		t.Fatalf("expected to find a version")
 	}
@@ -98,6 +105,11 @@ func TestShouldRunInvocationUpdateCheck(t *testing.T) {
 		{name: "pr command", args: []string{"wtx", "pr"}, want: false},
 		{name: "internal helper", args: []string{"wtx", "tmux-status"}, want: false},
 		{name: "update command", args: []string{"wtx", "update"}, want: false},
+		{name: "shell prompt", args: []string{"wtx", "prompt"}, want: false},
+		{name: "picker", args: []string{"wtx", "pick"}, want: false},
+		{name: "serve", args: []string{"wtx", "serve"}, want: false},
+		{name: "mcp server", args: []string{"wtx", "mcp"}, want: false},
+		{name: "background job", args: []string{"wtx", "job-run"}, want: false},
 		{name: "version long flag", args: []string{"wtx", "--version"}, want: false},
 		{name: "version short flag", args: []string{"wtx", "-v"}, want: false},
 	}
@@ -205,6 +217,9 @@ func TestReleaseArchiveName(t *testing.T) {
 		t.Fatalf("releaseArchiveName: %v", err)
 	}
 	want := "wtx_" + runtime.GOOS + "_" + runtime.GOARCH + ".tar.gz"
+	if runtime.GOOS == "windows" {
+		want = "wtx_" + runtime.GOOS + "_" + runtime.GOARCH + ".zip"
+	}
 	if name != want {
 		t.Fatalf("expected %q, got %q", want, name)
 	}
@@ -229,3 +244,290 @@ func TestChecksumLineForFile(t *testing.T) {
 		t.Fatalf("unexpected line: %q", line)
 	}
 }
+
+func TestDetectPackageInstall(t *testing.T) {
+	tests := []struct {
+		path        string
+		wantOK      bool
+		wantManager string
+	}{
+		{path: "/opt/homebrew/Cellar/wtx/1.2.3/bin/wtx", wantOK: true, wantManager: "Homebrew"},
+		{path: "/usr/local/Cellar/wtx/1.2.3/bin/wtx", wantOK: true, wantManager: "Homebrew"},
+		{path: "/home/linuxbrew/.linuxbrew/Cellar/wtx/1.2.3/bin/wtx", wantOK: true, wantManager: "Homebrew"},
+		{path: "/nix/store/abc-wtx-1.2.3/bin/wtx", wantOK: true, wantManager: "Nix"},
+		{path: "/usr/bin/wtx", wantOK: true, wantManager: "your system package manager"},
+		{path: "/usr/local/bin/wtx", wantOK: false},
+		{path: "/home/me/go/bin/wtx", wantOK: false},
+	}
+	for _, tc := range tests {
+		got, ok := detectPackageInstall(tc.path)
+		if ok != tc.wantOK {
+			t.Fatalf("%s: expected ok=%v, got %v", tc.path, tc.wantOK, ok)
+		}
+		if ok && got.Manager != tc.wantManager {
+			t.Fatalf("%s: expected manager %q, got %q", tc.path, tc.wantManager, got.Manager)
+		}
+	}
+}
+
+func TestUpgradeWithPackageManagerPrintsCommandWhenMissing(t *testing.T) {
+	oldLookPath := lookPathForUpdateFn
+	lookPathForUpdateFn = func(string) (string, error) { return "", errors.New("not found") }
+	t.Cleanup(func() { lookPathForUpdateFn = oldLookPath })
+
+	pkg, _ := detectPackageInstall("/opt/homebrew/Cellar/wtx/1.2.3/bin/wtx")
+	err := upgradeWithPackageManager(context.Background(), pkg)
+	if err == nil || !strings.Contains(err.Error(), "brew upgrade wtx") {
+		t.Fatalf("expected brew upgrade hint, got %v", err)
+	}
+}
+
+func TestLatestVersionFromLSRemoteOutputPrereleaseChannel(t *testing.T) {
+	output := "" +
+		"abc refs/tags/v1.2.3\n" +
+		"abc refs/tags/v1.3.0-rc.1\n" +
+		"abc refs/tags/v1.3.0-rc.2\n" +
+		"abc refs/tags/v1.3.0-beta.9\n"
+
+	got, ok := latestVersionFromLSRemoteOutput(output, updateChannelStable)
+	if !ok || got != "v1.2.3" {
+		t.Fatalf("expected stable channel to pick v1.2.3, got %q", got)
+	}
+	got, ok = latestVersionFromLSRemoteOutput(output, updateChannelPrerelease)
+	if !ok || got != "v1.3.0-rc.2" {
+		t.Fatalf("expected prerelease channel to pick v1.3.0-rc.2, got %q", got)
+	}
+	got, _ = latestVersionFromLSRemoteOutput(output+"abc refs/tags/v1.3.0\n", updateChannelPrerelease)
+	if got != "v1.3.0" {
+		t.Fatalf("expected final release to win over its prereleases, got %q", got)
+	}
+}
+
+func TestIsUpdateAvailableWithPrereleases(t *testing.T) {
+	tests := []struct {
+		current string
+		latest  string
+		want    bool
+	}{
+		{current: "v1.3.0-rc.1", latest: "v1.3.0-rc.2", want: true},
+		{current: "v1.3.0-rc.2", latest: "v1.3.0", want: true},
+		{current: "v1.3.0-rc.10", latest: "v1.3.0-rc.9", want: false},
+		{current: "v1.3.0-rc.1", latest: "v1.2.9", want: false},
+	}
+	for _, tc := range tests {
+		if got := isUpdateAvailableForInstall(tc.current, tc.latest); got != tc.want {
+			t.Fatalf("%s -> %s: expected %v, got %v", tc.current, tc.latest, tc.want, got)
+		}
+	}
+}
+
+func TestFormatUpdateAvailableHintIncludesChannel(t *testing.T) {
+	got := formatUpdateAvailableHint(updateCheckResult{CurrentVersion: "v1.0.0", LatestVersion: "v1.1.0-rc.1", Channel: updateChannelPrerelease})
+	want := "wtx v1.0.0 -> v1.1.0-rc.1 available (prerelease channel). Run: wtx update"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestSummarizeReleaseNotes(t *testing.T) {
+	body := "## Changes\r\n\r\n<!-- generated -->\n- Fix popup\n- Add rollback\n- Add channels\n"
+	got := summarizeReleaseNotes(body, 2)
+	want := []string{"## Changes", "- Fix popup", "… and 2 more line(s)"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestPrintReleaseNotes(t *testing.T) {
+	oldFetch := fetchReleaseNotesFn
+	t.Cleanup(func() { fetchReleaseNotesFn = oldFetch })
+
+	fetchReleaseNotesFn = func(_ context.Context, tag string) (string, error) {
+		return "- Faster status for " + tag, nil
+	}
+	var out strings.Builder
+	printReleaseNotes(&out, "v1.2.0")
+	if !strings.Contains(out.String(), "What's new in v1.2.0:") || !strings.Contains(out.String(), "- Faster status for v1.2.0") {
+		t.Fatalf("unexpected release notes output %q", out.String())
+	}
+
+	fetchReleaseNotesFn = func(context.Context, string) (string, error) {
+		return "", errors.New("offline")
+	}
+	out.Reset()
+	printReleaseNotes(&out, "v1.2.0")
+	if out.String() != "" {
+		t.Fatalf("expected no output when notes are unavailable, got %q", out.String())
+	}
+}
+
+func TestUpdateCheckSettings(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(core.ConfigDirOverrideEnv, "")
+	t.Setenv("WTX_NO_UPDATE_CHECK", "")
+
+	if !updateChecksEnabled() || updateCheckInterval() != defaultUpdateInterval {
+		t.Fatalf("expected default checks every %s", defaultUpdateInterval)
+	}
+	if err := core.SaveConfig(core.Config{AgentCommand: "claude", UpdateCheck: core.UpdateCheckWeekly}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	if got := updateCheckInterval(); got != 7*24*time.Hour {
+		t.Fatalf("expected weekly interval, got %s", got)
+	}
+	if err := core.SaveConfig(core.Config{AgentCommand: "claude", UpdateCheck: core.UpdateCheckOff}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	if updateChecksEnabled() {
+		t.Fatalf("expected update_check=off to disable checks")
+	}
+	if err := core.SaveConfig(core.Config{AgentCommand: "claude"}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	t.Setenv("WTX_NO_UPDATE_CHECK", "1")
+	if updateChecksEnabled() {
+		t.Fatalf("expected WTX_NO_UPDATE_CHECK to disable checks")
+	}
+}
+
+func TestExtractBinaryFromZip(t *testing.T) {
+	dir := t.TempDir()
+	archivePath := filepath.Join(dir, "wtx.zip")
+	f, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatalf("create zip: %v", err)
+	}
+	zw := zip.NewWriter(f)
+	for name, body := range map[string]string{"README.md": "docs", "dist/" + releaseBinaryName(): "binary"} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("zip entry: %v", err)
+		}
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Fatalf("zip write: %v", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("close zip: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("close file: %v", err)
+	}
+
+	out := filepath.Join(dir, "extracted")
+	if err := extractBinaryFromZip(archivePath, out); err != nil {
+		t.Fatalf("extractBinaryFromZip: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil || string(data) != "binary" {
+		t.Fatalf("expected extracted binary, got %q (%v)", data, err)
+	}
+}
+
+func TestSwapRunningExecutable(t *testing.T) {
+	dir := t.TempDir()
+	exePath := filepath.Join(dir, "wtx.exe")
+	newPath := filepath.Join(dir, ".wtx-update-tmp")
+	if err := os.WriteFile(exePath, []byte("old"), 0o755); err != nil {
+		t.Fatalf("write exe: %v", err)
+	}
+	if err := os.WriteFile(newPath, []byte("new"), 0o755); err != nil {
+		t.Fatalf("write new: %v", err)
+	}
+	if err := swapRunningExecutable(newPath, exePath); err != nil {
+		t.Fatalf("swapRunningExecutable: %v", err)
+	}
+	if data, _ := os.ReadFile(exePath); string(data) != "new" {
+		t.Fatalf("expected new binary in place, got %q", data)
+	}
+	if data, _ := os.ReadFile(exePath + replacedExeSuffix); string(data) != "old" {
+		t.Fatalf("expected old binary moved aside, got %q", data)
+	}
+}
+
+func TestDownloadFileResumesAfterInterruptedTransfer(t *testing.T) {
+	oldDelays := downloadRetryDelays
+	downloadRetryDelays = []time.Duration{0, 0}
+	t.Cleanup(func() { downloadRetryDelays = oldDelays })
+
+	payload := strings.Repeat("wtx-release-", 4096)
+	requests := 0
+	var resumedRange string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("ETag", `"v1"`)
+		if requests == 1 {
+			w.Header().Set("Content-Length", strconv.Itoa(len(payload)))
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(payload[:len(payload)/2]))
+			panic(http.ErrAbortHandler)
+		}
+		resumedRange = r.Header.Get("Range")
+		http.ServeContent(w, r, "wtx.tar.gz", time.Time{}, strings.NewReader(payload))
+	}))
+	t.Cleanup(server.Close)
+
+	target := filepath.Join(t.TempDir(), "wtx.tar.gz")
+	if err := downloadFile(context.Background(), server.URL, target); err != nil {
+		t.Fatalf("downloadFile: %v", err)
+	}
+	data, err := os.ReadFile(target)
+	if err != nil || string(data) != payload {
+		t.Fatalf("expected full payload after resume, got %d bytes (%v)", len(data), err)
+	}
+	if resumedRange != "bytes="+strconv.Itoa(len(payload)/2)+"-" {
+		t.Fatalf("expected ranged resume request, got Range %q", resumedRange)
+	}
+}
+
+func TestDownloadFileDoesNotRetryClientErrors(t *testing.T) {
+	oldDelays := downloadRetryDelays
+	downloadRetryDelays = []time.Duration{0, 0}
+	t.Cleanup(func() { downloadRetryDelays = oldDelays })
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		http.NotFound(w, nil)
+	}))
+	t.Cleanup(server.Close)
+
+	err := downloadFile(context.Background(), server.URL, filepath.Join(t.TempDir(), "missing"))
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Fatalf("expected 404 error, got %v", err)
+	}
+	if requests != 1 {
+		t.Fatalf("expected a single request for a 404, got %d", requests)
+	}
+}
+
+func TestUpdateBaseURLMirror(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(core.ConfigDirOverrideEnv, "")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/wtx/tags.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("v1.2.0\nv1.10.0\nv2.0.0-rc.1\nnot-a-tag\n"))
+	}))
+	t.Cleanup(server.Close)
+
+	if got := releaseAssetURL("v1.0.0", "checksums.txt"); got != "https://github.com/aixolotls/wtx/releases/download/v1.0.0/checksums.txt" {
+		t.Fatalf("expected GitHub asset URL by default, got %q", got)
+	}
+	if err := core.SaveConfig(core.Config{AgentCommand: "claude", UpdateBaseURL: server.URL + "/wtx/"}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	if got := releaseAssetURL("v1.0.0", "checksums.txt"); got != server.URL+"/wtx/v1.0.0/checksums.txt" {
+		t.Fatalf("expected mirror asset URL, got %q", got)
+	}
+	latest, err := resolveLatestVersion(context.Background())
+	if err != nil {
+		t.Fatalf("resolveLatestVersion: %v", err)
+	}
+	if latest != "v1.10.0" {
+		t.Fatalf("expected v1.10.0 from mirror, got %q", latest)
+	}
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+// A release mirror serves <base>/tags.txt (one tag per line) and the release
+// assets under <base>/<tag>/<asset>.
+const (
+	mirrorTagsFormat     = "%s/tags.txt"
+	mirrorDownloadFormat = "%s/%s/%s"
+)
+
+// updateBaseURL returns the configured release mirror, or "" for GitHub.
+func updateBaseURL() string {
+	cfg, err := core.LoadConfig()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimRight(strings.TrimSpace(cfg.UpdateBaseURL), "/")
+}
+
+func releaseAssetURL(tag string, asset string) string {
+	if base := updateBaseURL(); base != "" {
+		return fmt.Sprintf(mirrorDownloadFormat, base, tag, asset)
+	}
+	return fmt.Sprintf(releaseDownloadFormat, updateRepoPath, tag, asset)
+}
+
+func resolveLatestMirrorVersion(ctx context.Context, base string) (string, error) {
+	url := fmt.Sprintf(mirrorTagsFormat, base)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve latest version: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to resolve latest version: GET %s returned %s", url, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve latest version: %w", err)
+	}
+	latest, ok := latestVersionFromTags(strings.Split(string(body), "\n"), configuredUpdateChannel())
+	if !ok {
+		return "", errors.New("failed to resolve latest version: no semver tags found on mirror")
+	}
+	return latest, nil
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	releaseNotesURLFormat = "https://api.github.com/repos/%s/releases/tags/%s"
+	releaseNotesTimeout   = 3 * time.Second
+	releaseNotesMaxLines  = 8
+)
+
+var fetchReleaseNotesFn = fetchReleaseNotes
+
+// fetchReleaseNotes returns the GitHub release body for tag.
+func fetchReleaseNotes(ctx context.Context, tag string) (string, error) {
+	if updateBaseURL() != "" {
+		return "", errors.New("release notes are not available from a release mirror")
+	}
+	url := fmt.Sprintf(releaseNotesURLFormat, updateRepoPath, strings.TrimSpace(tag))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GET %s returned %s", url, resp.Status)
+	}
+	var release struct {
+		Body string `json:"body"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&release); err != nil {
+		return "", err
+	}
+	return release.Body, nil
+}
+
+// summarizeReleaseNotes keeps the first few non-empty lines of a release body
+// and notes how many were left out.
+func summarizeReleaseNotes(body string, maxLines int) []string {
+	lines := []string{}
+	for _, line := range strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "<!--") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) <= maxLines {
+		return lines
+	}
+	omitted := len(lines) - maxLines
+	return append(lines[:maxLines], fmt.Sprintf("… and %d more line(s)", omitted))
+}
+
+// printReleaseNotes shows a short changelog for tag; failures are silent so a
+// missing release body never blocks an update.
+func printReleaseNotes(w io.Writer, tag string) {
+	ctx, cancel := context.WithTimeout(context.Background(), releaseNotesTimeout)
+	defer cancel()
+	body, err := fetchReleaseNotesFn(ctx, tag)
+	if err != nil {
+		return
+	}
+	lines := summarizeReleaseNotes(body, releaseNotesMaxLines)
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\nWhat's new in %s:\n", tag)
+	for _, line := range lines {
+		fmt.Fprintf(w, "  %s\n", line)
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+const (
+	versionBackupsFileName = "versions.json"
+	keptVersionBackups     = 3
+)
+
+// versionBackup is a binary replaced by a self-update, kept for rollback.
+type versionBackup struct {
+	Version     string `json:"version"`
+	Path        string `json:"path"`
+	SavedAtUnix int64  `json:"saved_at_unix"`
+}
+
+type versionBackupsFile struct {
+	Backups []versionBackup `json:"backups"`
+}
+
+func runUpdateRollback(quiet bool) error {
+	exePath, err := currentExecutablePath()
+	if err != nil {
+		return err
+	}
+	if pkg, ok := detectPackageInstall(exePath); ok {
+		return fmt.Errorf("wtx is installed by %s; roll back with %s instead", pkg.Manager, pkg.Manager)
+	}
+	backups, err := readVersionBackups()
+	if err != nil {
+		return err
+	}
+	cur := core.CurrentVersion()
+	target, ok := rollbackCandidate(backups, cur)
+	if !ok {
+		return errors.New("no previous wtx version to roll back to")
+	}
+
+	// Stage the old binary first: backing up the current one may prune it.
+	tmpDir, err := os.MkdirTemp("", "wtx-rollback-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	staged := filepath.Join(tmpDir, releaseBinaryName())
+	if err := core.CopyFile(target.Path, staged, 0o755); err != nil {
+		return fmt.Errorf("failed to read %s: %w", target.Path, err)
+	}
+	if err := backupExecutable(exePath, cur); err != nil {
+		return fmt.Errorf("failed to keep current version: %w", err)
+	}
+	if err := replaceCurrentExecutable(staged); err != nil {
+		return fmt.Errorf("failed to install %s: %w", target.Version, err)
+	}
+	if quiet {
+		fmt.Println(target.Version)
+		return nil
+	}
+	fmt.Printf("Rolled back wtx from %s to %s\n", cur, target.Version)
+	return nil
+}
+
+// rollbackCandidate returns the most recent backup of a version other than
+// current whose binary still exists.
+func rollbackCandidate(backups []versionBackup, current string) (versionBackup, bool) {
+	current = strings.TrimSpace(current)
+	for i := len(backups) - 1; i >= 0; i-- {
+		b := backups[i]
+		if b.Version == current {
+			continue
+		}
+		if info, err := os.Stat(b.Path); err != nil || info.IsDir() {
+			continue
+		}
+		return b, true
+	}
+	return versionBackup{}, false
+}
+
+// backupExecutable copies exePath into ~/.wtx/versions/<version>/ and keeps
+// only the newest keptVersionBackups copies.
+func backupExecutable(exePath string, version string) error {
+	version = strings.TrimSpace(version)
+	if version == "" {
+		version = "unknown"
+	}
+	dir, err := versionsDir()
+	if err != nil {
+		return err
+	}
+	versionDir := filepath.Join(dir, strings.ReplaceAll(version, string(filepath.Separator), "_"))
+	target := filepath.Join(versionDir, releaseBinaryName())
+	_ = os.Remove(target)
+	if err := core.CopyFile(exePath, target, 0o755); err != nil {
+		return err
+	}
+
+	backups, err := readVersionBackups()
+	if err != nil {
+		return err
+	}
+	next := make([]versionBackup, 0, len(backups)+1)
+	for _, b := range backups {
+		if b.Version != version {
+			next = append(next, b)
+		}
+	}
+	next = append(next, versionBackup{Version: version, Path: target, SavedAtUnix: time.Now().Unix()})
+	for len(next) > keptVersionBackups {
+		_ = os.RemoveAll(filepath.Dir(next[0].Path))
+		next = next[1:]
+	}
+	return writeVersionBackups(next)
+}
+
+func readVersionBackups() ([]versionBackup, error) {
+	dir, err := versionsDir()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, versionBackupsFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []versionBackup{}, nil
+		}
+		return nil, err
+	}
+	var file versionBackupsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	return file.Backups, nil
+}
+
+func writeVersionBackups(backups []versionBackup) error {
+	dir, err := versionsDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(versionBackupsFile{Backups: backups}, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, versionBackupsFileName)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func versionsDir() (string, error) {
+	home, err := core.WtxHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "versions"), nil
+}
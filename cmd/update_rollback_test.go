@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBackupExecutableKeepsRecentVersions(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	exe := filepath.Join(t.TempDir(), "wtx")
+	for _, v := range []string{"v1.0.0", "v1.1.0", "v1.2.0", "v1.3.0"} {
+		if err := os.WriteFile(exe, []byte(v), 0o755); err != nil {
+			t.Fatalf("write exe: %v", err)
+		}
+		if err := backupExecutable(exe, v); err != nil {
+			t.Fatalf("backup %s: %v", v, err)
+		}
+	}
+	backups, err := readVersionBackups()
+	if err != nil {
+		t.Fatalf("read backups: %v", err)
+	}
+	if len(backups) != keptVersionBackups {
+		t.Fatalf("expected %d backups, got %d", keptVersionBackups, len(backups))
+	}
+	if backups[0].Version != "v1.1.0" {
+		t.Fatalf("expected oldest kept backup v1.1.0, got %q", backups[0].Version)
+	}
+	dir, _ := versionsDir()
+	if _, err := os.Stat(filepath.Join(dir, "v1.0.0")); !os.IsNotExist(err) {
+		t.Fatalf("expected pruned v1.0.0 backup to be removed, got %v", err)
+	}
+	data, err := os.ReadFile(backups[2].Path)
+	if err != nil || string(data) != "v1.3.0" {
+		t.Fatalf("expected v1.3.0 binary contents, got %q (%v)", data, err)
+	}
+}
+
+func TestRollbackCandidateSkipsCurrentAndMissing(t *testing.T) {
+	dir := t.TempDir()
+	present := filepath.Join(dir, "present")
+	if err := os.WriteFile(present, []byte("x"), 0o755); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	backups := []versionBackup{
+		{Version: "v1.0.0", Path: present},
+		{Version: "v1.1.0", Path: filepath.Join(dir, "missing")},
+		{Version: "v1.2.0", Path: present},
+	}
+	got, ok := rollbackCandidate(backups, "v1.2.0")
+	if !ok || got.Version != "v1.0.0" {
+		t.Fatalf("expected v1.0.0, got %+v (ok=%v)", got, ok)
+	}
+	if _, ok := rollbackCandidate(backups[2:], "v1.2.0"); ok {
+		t.Fatalf("expected no candidate when only the current version is kept")
+	}
+}
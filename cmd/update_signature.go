@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// releaseSigningPublicKey is the minisign public key release builds embed via
+// -ldflags. Builds without a key refuse to self-update unless verification is
+// skipped explicitly.
+var releaseSigningPublicKey = ""
+
+var errNoReleaseSigningKey = errors.New("this wtx build has no release signing key; rerun `wtx update --insecure-skip-verify` to install without signature verification")
+
+const (
+	checksumsSignatureFileName = "checksums.txt.minisig"
+	minisignAlgorithm          = "Ed"
+	minisignPrehashAlgorithm   = "ED"
+	minisignTrustedPrefix      = "trusted comment: "
+)
+
+// verifyChecksumsSignature checks the minisign signature over checksums.txt
+// against the embedded release key.
+func verifyChecksumsSignature(checksumsPath string, signaturePath string) error {
+	message, err := os.ReadFile(checksumsPath)
+	if err != nil {
+		return err
+	}
+	signature, err := os.ReadFile(signaturePath)
+	if err != nil {
+		return err
+	}
+	return verifyMinisign(releaseSigningPublicKey, message, signature)
+}
+
+// verifyMinisign verifies a legacy (non-prehashed) minisign signature,
+// including the global signature over the trusted comment.
+func verifyMinisign(publicKey string, message []byte, signature []byte) error {
+	keyID, key, err := parseMinisignPublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+	lines := nonEmptyLines(string(signature))
+	if len(lines) < 4 || !strings.HasPrefix(lines[0], "untrusted comment:") {
+		return errors.New("malformed signature file")
+	}
+	sigBlob, err := base64.StdEncoding.DecodeString(lines[1])
+	if err != nil || len(sigBlob) != 2+8+ed25519.SignatureSize {
+		return errors.New("malformed signature")
+	}
+	switch string(sigBlob[:2]) {
+	case minisignAlgorithm:
+	case minisignPrehashAlgorithm:
+		return errors.New("prehashed signatures are not supported; sign with minisign -S -l")
+	default:
+		return fmt.Errorf("unsupported signature algorithm %q", sigBlob[:2])
+	}
+	if !bytes.Equal(sigBlob[2:10], keyID) {
+		return errors.New("signature was made with a different key")
+	}
+	sig := sigBlob[10:]
+	if !ed25519.Verify(key, message, sig) {
+		return errors.New("signature does not match")
+	}
+	if !strings.HasPrefix(lines[2], minisignTrustedPrefix) {
+		return errors.New("malformed trusted comment")
+	}
+	trusted := strings.TrimPrefix(lines[2], minisignTrustedPrefix)
+	globalSig, err := base64.StdEncoding.DecodeString(lines[3])
+	if err != nil || len(globalSig) != ed25519.SignatureSize {
+		return errors.New("malformed trusted comment signature")
+	}
+	if !ed25519.Verify(key, append(append([]byte{}, sig...), trusted...), globalSig) {
+		return errors.New("trusted comment signature does not match")
+	}
+	return nil
+}
+
+// parseMinisignPublicKey accepts either the bare base64 key or the contents
+// of a minisign .pub file.
+func parseMinisignPublicKey(publicKey string) ([]byte, ed25519.PublicKey, error) {
+	lines := nonEmptyLines(publicKey)
+	if len(lines) == 0 {
+		return nil, nil, errors.New("no release signing key embedded")
+	}
+	blob, err := base64.StdEncoding.DecodeString(lines[len(lines)-1])
+	if err != nil || len(blob) != 2+8+ed25519.PublicKeySize || string(blob[:2]) != minisignAlgorithm {
+		return nil, nil, errors.New("invalid release signing key")
+	}
+	return blob[2:10], ed25519.PublicKey(blob[10:]), nil
+}
+
+func nonEmptyLines(s string) []string {
+	out := []string{}
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
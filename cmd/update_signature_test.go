@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testMinisignFixture(t *testing.T, message []byte, alg string) (string, string) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	keyID := []byte("wtxkey01")
+	publicKey := "untrusted comment: minisign public key\n" +
+		base64.StdEncoding.EncodeToString(append(append([]byte("Ed"), keyID...), pub...)) + "\n"
+	sig := ed25519.Sign(priv, message)
+	trusted := "timestamp:1700000000\tfile:checksums.txt"
+	global := ed25519.Sign(priv, append(append([]byte{}, sig...), trusted...))
+	signature := "untrusted comment: signature from minisign secret key\n" +
+		base64.StdEncoding.EncodeToString(append(append([]byte(alg), keyID...), sig...)) + "\n" +
+		"trusted comment: " + trusted + "\n" +
+		base64.StdEncoding.EncodeToString(global) + "\n"
+	return publicKey, signature
+}
+
+func TestVerifyMinisign(t *testing.T) {
+	message := []byte("abc  wtx_linux_amd64.tar.gz\n")
+	publicKey, signature := testMinisignFixture(t, message, minisignAlgorithm)
+
+	if err := verifyMinisign(publicKey, message, []byte(signature)); err != nil {
+		t.Fatalf("expected valid signature, got %v", err)
+	}
+	if err := verifyMinisign(publicKey, []byte("tampered\n"), []byte(signature)); err == nil {
+		t.Fatalf("expected tampered message to fail")
+	}
+	otherKey, _ := testMinisignFixture(t, message, minisignAlgorithm)
+	if err := verifyMinisign(otherKey, message, []byte(signature)); err == nil {
+		t.Fatalf("expected signature from another key to fail")
+	}
+	forged := strings.Replace(signature, "timestamp:1700000000", "timestamp:1800000000", 1)
+	if err := verifyMinisign(publicKey, message, []byte(forged)); err == nil || !strings.Contains(err.Error(), "trusted comment") {
+		t.Fatalf("expected forged trusted comment to fail, got %v", err)
+	}
+	if err := verifyMinisign("", message, []byte(signature)); err == nil {
+		t.Fatalf("expected missing key to fail")
+	}
+}
+
+func TestVerifyMinisignRejectsPrehashed(t *testing.T) {
+	message := []byte("checksums")
+	publicKey, signature := testMinisignFixture(t, message, minisignPrehashAlgorithm)
+	err := verifyMinisign(publicKey, message, []byte(signature))
+	if err == nil || !strings.Contains(err.Error(), "minisign -S -l") {
+		t.Fatalf("expected prehashed signature to be rejected, got %v", err)
+	}
+}
+
+func TestInstallReleaseRequiresSigningKey(t *testing.T) {
+	oldKey := releaseSigningPublicKey
+	releaseSigningPublicKey = ""
+	t.Cleanup(func() { releaseSigningPublicKey = oldKey })
+	if err := installRelease(context.Background(), "v1.2.3", true); !errors.Is(err, errNoReleaseSigningKey) {
+		t.Fatalf("expected a keyless build to refuse verified installs, got %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+// vscodeCommands share VS Code's workspace file format and CLI flags.
+var vscodeCommands = []string{"code", "code-insiders", "cursor", "codium"}
+
+const vscodeWindowTitleSuffix = "${separator}${activeEditorShort}${separator}${rootName}"
+
+func isVSCodeCommand(ideCmd string) bool {
+	fields := strings.Fields(ideCmd)
+	if len(fields) == 0 {
+		return false
+	}
+	name := filepath.Base(fields[0])
+	for _, c := range vscodeCommands {
+		if name == c {
+			return true
+		}
+	}
+	return false
+}
+
+type vscodeWorkspaceFolder struct {
+	Name string `json:"name,omitempty"`
+	Path string `json:"path"`
+}
+
+type vscodeWorkspace struct {
+	Folders  []vscodeWorkspaceFolder `json:"folders"`
+	Settings map[string]any          `json:"settings,omitempty"`
+}
+
+// prepareVSCodeWorkspace writes a workspace with the target folder and the
+// main checkout next to it, and returns its path. Workspace files live in
+// ~/.wtx/workspaces so they never show up in git status; the worktree's own
+// settings file is only touched when git does not track it.
+func prepareVSCodeWorkspace(targetPath string) (string, error) {
+	root, err := core.RepoRootForDir(targetPath, "")
+	if err != nil {
+		return "", err
+	}
+	gitDir, commonDir, err := core.ResolveGitDirs(root)
+	if err != nil {
+		return "", err
+	}
+	branch := core.BranchFromHEADFile(filepath.Join(gitDir, "HEAD"))
+	title := branch + vscodeWindowTitleSuffix
+
+	ws := vscodeWorkspace{
+		Folders:  []vscodeWorkspaceFolder{{Name: branch, Path: targetPath}},
+		Settings: map[string]any{"window.title": title},
+	}
+	if mainRoot := filepath.Dir(commonDir); filepath.Base(commonDir) == ".git" && !core.SameSessionPath(mainRoot, root) {
+		ws.Folders = append(ws.Folders, vscodeWorkspaceFolder{Name: filepath.Base(mainRoot) + " (main)", Path: mainRoot})
+	}
+
+	home, err := core.WtxHomeDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(home, "workspaces")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%s-%s.code-workspace", filepath.Base(root), core.HashString(filepath.Clean(targetPath))[:8]))
+	data, err := json.MarshalIndent(ws, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return "", err
+	}
+	if err := writeVSCodeWindowTitle(root, title); err != nil {
+		fmt.Fprintln(os.Stderr, "wtx warning: could not update .vscode/settings.json:", err)
+	}
+	return path, nil
+}
+
+// writeVSCodeWindowTitle sets window.title in the worktree's
+// .vscode/settings.json, so the branch shows even when the folder is opened
+// without the workspace. Other settings are kept; a file that is not plain
+// JSON (VS Code allows comments) is left alone, and so is one the repo
+// commits, since the workspace already carries the title.
+func writeVSCodeWindowTitle(worktreeRoot string, title string) error {
+	path := filepath.Join(worktreeRoot, ".vscode", "settings.json")
+	if vscodeSettingsTracked(worktreeRoot) {
+		return nil
+	}
+	settings := map[string]any{}
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return fmt.Errorf("%s is not plain JSON; leaving it unchanged", path)
+		}
+		if settings["window.title"] == title {
+			return nil
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	settings["window.title"] = title
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+func vscodeSettingsTracked(worktreeRoot string) bool {
+	gitPath, err := core.RequireGitPath()
+	if err != nil {
+		return false
+	}
+	return core.GitRunInDir(worktreeRoot, gitPath, "ls-files", "--error-unmatch", "--", ".vscode/settings.json") == nil
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+func TestPrepareVSCodeWorkspaceAddsMainRepoAndTitle(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo := initGitReadTestRepo(t)
+	wt := filepath.Join(t.TempDir(), "wt-newer")
+	runGitInRepo(t, repo, "worktree", "add", "-q", wt, "newer")
+	settingsPath := filepath.Join(wt, ".vscode", "settings.json")
+	if err := os.MkdirAll(filepath.Dir(settingsPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(settingsPath, []byte(`{"editor.tabSize": 2}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := prepareVSCodeWorkspace(wt)
+	if err != nil {
+		t.Fatalf("prepareVSCodeWorkspace: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read workspace: %v", err)
+	}
+	var ws vscodeWorkspace
+	if err := json.Unmarshal(data, &ws); err != nil {
+		t.Fatalf("decode workspace: %v", err)
+	}
+	if len(ws.Folders) != 2 || ws.Folders[0].Path != wt || !core.SameSessionPath(ws.Folders[1].Path, repo) {
+		t.Fatalf("unexpected folders %+v", ws.Folders)
+	}
+	if ws.Settings["window.title"] != "newer"+vscodeWindowTitleSuffix {
+		t.Fatalf("unexpected workspace title %v", ws.Settings["window.title"])
+	}
+
+	var settings map[string]any
+	data, _ = os.ReadFile(settingsPath)
+	if err := json.Unmarshal(data, &settings); err != nil {
+		t.Fatalf("decode settings: %v", err)
+	}
+	if settings["editor.tabSize"] != float64(2) || settings["window.title"] != "newer"+vscodeWindowTitleSuffix {
+		t.Fatalf("expected title merged into settings, got %v", settings)
+	}
+}
+
+func TestPrepareVSCodeWorkspaceLeavesTrackedSettingsAlone(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo := initGitReadTestRepo(t)
+	settingsPath := filepath.Join(repo, ".vscode", "settings.json")
+	if err := os.MkdirAll(filepath.Dir(settingsPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	original := []byte(`{"editor.tabSize": 2}`)
+	if err := os.WriteFile(settingsPath, original, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGitInRepo(t, repo, "add", ".vscode/settings.json")
+	runGitInRepo(t, repo, "commit", "-q", "-m", "vscode settings")
+
+	if _, err := prepareVSCodeWorkspace(repo); err != nil {
+		t.Fatalf("prepareVSCodeWorkspace: %v", err)
+	}
+	data, err := os.ReadFile(settingsPath)
+	if err != nil {
+		t.Fatalf("read settings: %v", err)
+	}
+	if string(data) != string(original) {
+		t.Fatalf("expected tracked settings to stay unchanged, got %s", data)
+	}
+}
+
+func TestIsVSCodeCommand(t *testing.T) {
+	for cmd, want := range map[string]bool{"code": true, "/usr/local/bin/cursor": true, "code --wait": true, "sublime": false, "": false} {
+		if got := isVSCodeCommand(cmd); got != want {
+			t.Fatalf("isVSCodeCommand(%q) = %v, want %v", cmd, got, want)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/aixolotls/wtx/internal/core"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+)
+
+func jobCommand(cfg core.Config, job string) string {
+	switch job {
+	case core.JobBootstrap:
+		return strings.TrimSpace(cfg.BootstrapCommand)
+	case core.JobTests:
+		return strings.TrimSpace(cfg.TestCommand)
+	}
+	return ""
+}
+
+func newJobRunCommand() *cobra.Command {
+	var job string
+	var worktree string
+	cmd := &cobra.Command{
+		Use:    "job-run",
+		Short:  "Run a configured job in a worktree",
+		Args:   cobra.NoArgs,
+		Hidden: true,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			worktree = strings.TrimSpace(worktree)
+			if worktree == "" {
+				return nil
+			}
+			cfg, err := core.LoadConfigForDir(worktree)
+			if err != nil || jobCommand(cfg, job) == "" {
+				return core.ClearWorktreeJob(job, worktree)
+			}
+			return core.RunWorktreeJob(job, worktree, jobCommand(cfg, job))
+		},
+	}
+	cmd.Flags().StringVar(&job, "job", "", "Job to run")
+	cmd.Flags().StringVar(&worktree, "worktree", "", "Worktree path")
+	return cmd
+}
+
+type testsStartedMsg struct {
+	err error
+}
+
+// runTests starts test_command in the selected worktree; the Tests column
+// follows the run from the job state.
+func (m model) runTests() (tea.Model, tea.Cmd) {
+	row, ok := selectedWorktree(m.listStatus(), m.listIndex)
+	if !ok {
+		m.errMsg = "No worktree selected."
+		return m, nil
+	}
+	if isOrphanedPath(m.status, row.Path) {
+		m.errMsg = "Cannot run tests in an orphaned worktree."
+		return m, nil
+	}
+	m.errMsg = ""
+	return m, runTestsCmd(row.Path)
+}
+
+func runTestsCmd(worktreePath string) tea.Cmd {
+	return func() tea.Msg {
+		if state, ok := core.ReadWorktreeJobState(core.JobTests, worktreePath); ok && state.Running() {
+			return testsStartedMsg{err: errors.New("tests are already running in this worktree")}
+		}
+		cfg, err := core.LoadConfigForDir(worktreePath)
+		if err != nil {
+			return testsStartedMsg{err: err}
+		}
+		command := jobCommand(cfg, core.JobTests)
+		if command == "" {
+			return testsStartedMsg{err: errors.New(`no test command configured; set one with wtx config set --repo test_command "make test"`)}
+		}
+		return testsStartedMsg{err: core.StartWorktreeJob(core.JobTests, worktreePath, command, nil)}
+	}
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aixolotls/wtx/internal/core"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// waitForJob polls until the job stops running in the worktree.
+func waitForJob(t *testing.T, job string, worktreePath string) core.WorktreeJobState {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		state, ok := core.ReadWorktreeJobState(job, worktreePath)
+		if ok && state.State != core.JobRunning {
+			return state
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%s still running after 10s: %+v", job, state)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestRunTestsKeyShowsResultColumn(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo := initRenameTestRepo(t)
+	lockMgr := core.NewLockManager()
+	mgr := core.NewWorktreeManager(repo, lockMgr)
+	orch := core.NewWorktreeOrchestrator(mgr, lockMgr, nil)
+
+	m := newModel()
+	m.mode = modeList
+	m.ready = true
+	m.orchestrator = orch
+	m.status = orch.Status()
+	// Column titles are padded to their width.
+	header := "Tests        Last commit"
+	if strings.Contains(m.View(), header) {
+		t.Fatalf("expected no Tests column before a run:\n%s", m.View())
+	}
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	if msg := cmd().(testsStartedMsg); msg.err == nil || !strings.Contains(msg.err.Error(), "no test command configured") {
+		t.Fatalf("expected a missing test command error, got %v", msg.err)
+	}
+
+	runGitInRepo(t, repo, "config", "wtx.test", "git status --short")
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	if msg := cmd().(testsStartedMsg); msg.err != nil {
+		t.Fatalf("run tests: %v", msg.err)
+	}
+	if state := waitForJob(t, core.JobTests, repo); state.State != core.JobDone {
+		t.Fatalf("expected the tests to pass, got %+v", state)
+	}
+	m.status = orch.Status()
+	if view := m.View(); !strings.Contains(view, header) || !strings.Contains(view, "✓ 0s") {
+		t.Fatalf("expected the Tests column with a pass, got:\n%s", view)
+	}
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/aixolotls/wtx/internal/core"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const maxWorktreeNoteLength = 200
+
+func newNoteInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "e.g. waiting on design review"
+	ti.CharLimit = maxWorktreeNoteLength
+	ti.Width = 50
+	return ti
+}
+
+type noteSavedMsg struct {
+	path string
+	note string
+	err  error
+}
+
+func saveNoteCmd(repoRoot string, path string, note string) tea.Cmd {
+	return func() tea.Msg {
+		note = strings.Join(strings.Fields(note), " ")
+		return noteSavedMsg{path: path, note: note, err: core.SetWorktreeNote(repoRoot, path, note)}
+	}
+}
+
+func (m model) startNoteEdit() (tea.Model, tea.Cmd) {
+	row, ok := selectedWorktree(m.listStatus(), m.listIndex)
+	if !ok {
+		m.errMsg = "No worktree selected."
+		return m, nil
+	}
+	m.mode = modeNote
+	m.notePath = row.Path
+	m.noteBranch = row.Branch
+	m.noteInput.SetValue(row.Note)
+	m.noteInput.CursorEnd()
+	m.errMsg = ""
+	return m, m.noteInput.Focus()
+}
+
+func (m model) updateNoteEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.mode = modeList
+		m.noteInput.Blur()
+		return m, nil
+	case "enter":
+		m.mode = modeList
+		m.noteInput.Blur()
+		return m, saveNoteCmd(m.status.RepoRoot, m.notePath, m.noteInput.Value())
+	}
+	var cmd tea.Cmd
+	m.noteInput, cmd = m.noteInput.Update(msg)
+	return m, cmd
+}
+
+// applyNoteSaved updates the note in place so it shows before the next
+// status refresh.
+func (m model) applyNoteSaved(msg noteSavedMsg) model {
+	if msg.err != nil {
+		return m.setError(msg.err)
+	}
+	worktrees := make([]core.WorktreeInfo, len(m.status.Worktrees))
+	copy(worktrees, m.status.Worktrees)
+	for i := range worktrees {
+		if core.SameSessionPath(worktrees[i].Path, msg.path) {
+			worktrees[i].Note = msg.note
+		}
+	}
+	m.status.Worktrees = worktrees
+	m.errMsg = ""
+	return m
+}
+
+func renderNoteEdit(m model) string {
+	var b strings.Builder
+	b.WriteString("Note for " + branchStyle.Render(m.noteBranch) + ":\n")
+	b.WriteString(inputStyle.Render(m.noteInput.View()))
+	b.WriteString("\n")
+	if m.errMsg != "" {
+		b.WriteString(errorStyle.Render(m.errMsg))
+		b.WriteString("\n")
+	}
+	b.WriteString("\nPress enter to save, clear the text to remove the note, esc to cancel.\n")
+	return b.String()
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aixolotls/wtx/internal/core"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestNoteEditSavesAndShowsDetailLine(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m := newModel()
+	m.mode = modeList
+	m.ready = true
+	m.status = core.WorktreeStatus{
+		InRepo:       true,
+		GitInstalled: true,
+		RepoRoot:     "/repo",
+		Worktrees:    []core.WorktreeInfo{{Path: "/repo.wt/wt.1", Branch: "feature/a", Available: true}},
+	}
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	m = updatedModel.(model)
+	if m.mode != modeNote {
+		t.Fatalf("expected n to open the note editor, got mode %v", m.mode)
+	}
+	m.noteInput.SetValue("perf experiment")
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updatedModel.(model)
+	if m.mode != modeList || cmd == nil {
+		t.Fatalf("expected enter to save and return to the table")
+	}
+	updatedModel, _ = m.Update(cmd())
+	m = updatedModel.(model)
+	if !strings.Contains(m.View(), "Note: perf experiment") {
+		t.Fatalf("expected the note under the selected path, got:\n%s", m.View())
+	}
+	notes, _ := core.ReadWorktreeNotes("/repo")
+	if notes["/repo.wt/wt.1"] != "perf experiment" {
+		t.Fatalf("expected note persisted, got %+v", notes)
+	}
+}
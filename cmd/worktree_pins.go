@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"github.com/aixolotls/wtx/internal/core"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const pinnedGlyph = "★ "
+
+type pinSavedMsg struct {
+	path   string
+	pinned bool
+	err    error
+}
+
+func savePinCmd(repoRoot string, path string, pinned bool) tea.Cmd {
+	return func() tea.Msg {
+		return pinSavedMsg{path: path, pinned: pinned, err: core.SetWorktreePinned(repoRoot, path, pinned)}
+	}
+}
+
+func (m model) togglePin() (tea.Model, tea.Cmd) {
+	row, ok := selectedWorktree(m.listStatus(), m.listIndex)
+	if !ok {
+		m.errMsg = "No worktree selected."
+		return m, nil
+	}
+	m.errMsg = ""
+	return m, savePinCmd(m.status.RepoRoot, row.Path, !row.Pinned)
+}
+
+// applyPinSaved moves the worktree in place and keeps it selected.
+func (m model) applyPinSaved(msg pinSavedMsg) model {
+	if msg.err != nil {
+		return m.setError(msg.err)
+	}
+	worktrees := make([]core.WorktreeInfo, len(m.status.Worktrees))
+	copy(worktrees, m.status.Worktrees)
+	for i := range worktrees {
+		if core.SameSessionPath(worktrees[i].Path, msg.path) {
+			worktrees[i].Pinned = msg.pinned
+		}
+	}
+	m.status.Worktrees = worktrees
+	if idx, _, ok := findWorktreeByPath(m.listStatus(), msg.path); ok {
+		m.listIndex = idx
+	}
+	return m
+}
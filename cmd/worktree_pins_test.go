@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/aixolotls/wtx/internal/core"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestPinnedWorktreesSortFirstInEveryMode(t *testing.T) {
+	status := core.WorktreeStatus{
+		InRepo: true,
+		Worktrees: []core.WorktreeInfo{
+			{Path: "/repo/wt.1", Branch: "alpha", Available: true, PRNumber: 9},
+			{Path: "/repo/wt.2", Branch: "main", Available: false, Pinned: true},
+			{Path: "/repo/wt.3", Branch: "beta", Available: true, PRNumber: 12},
+		},
+	}
+	for mode := core.ListSortMode(0); mode < core.ListSortModeCount; mode++ {
+		status.ListSort = mode
+		if got := worktreesForDisplay(status)[0].Branch; got != "main" {
+			t.Fatalf("sort by %s: expected pinned main first, got %s", mode.Label(), got)
+		}
+	}
+}
+
+func TestTogglePinPersistsAndKeepsSelection(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m := newModel()
+	m.mode = modeList
+	m.ready = true
+	m.listSort = core.ListSortBranch
+	m.status = core.WorktreeStatus{
+		InRepo:       true,
+		GitInstalled: true,
+		RepoRoot:     "/repo",
+		Worktrees: []core.WorktreeInfo{
+			{Path: "/repo/wt.1", Branch: "alpha", Available: true},
+			{Path: "/repo/wt.2", Branch: "release", Available: true},
+		},
+	}
+	m.listIndex = 1
+
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	if cmd == nil {
+		t.Fatalf("expected f to save the pin")
+	}
+	updatedModel, _ = updatedModel.Update(cmd())
+	m = updatedModel.(model)
+	if m.listIndex != 0 {
+		t.Fatalf("expected the pinned worktree to stay selected at the top, got index %d", m.listIndex)
+	}
+	pins, err := core.ReadWorktreePins("/repo")
+	if err != nil || !pins["/repo/wt.2"] {
+		t.Fatalf("expected release pinned on disk, got %+v (%v)", pins, err)
+	}
+
+	updatedModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	updatedModel.Update(cmd())
+	pins, _ = core.ReadWorktreePins("/repo")
+	if len(pins) != 0 {
+		t.Fatalf("expected f again to unpin, got %+v", pins)
+	}
+}
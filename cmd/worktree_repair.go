@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/aixolotls/wtx/internal/core"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+)
+
+func brokenWorktreeMessage(keys keymap, wt core.WorktreeInfo) string {
+	return fmt.Sprintf("Worktree is broken (%s). Press %s to run git worktree repair.", wt.Broken, keys.label(keyActionRepair))
+}
+
+type worktreesRepairedMsg struct {
+	err error
+}
+
+func repairWorktreesCmd(mgr *core.WorktreeManager) tea.Cmd {
+	return func() tea.Msg {
+		return worktreesRepairedMsg{err: mgr.RepairWorktrees(nil)}
+	}
+}
+
+func newRepairCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "repair",
+		Short: "Reconnect worktrees whose .git file or admin dir is broken (git worktree repair)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := core.NewWorktreeManager("", core.NewLockManager()).RepairWorktrees(cmd.OutOrStdout()); err != nil {
+				return fmt.Errorf("repair worktrees: %w", err)
+			}
+			return nil
+		},
+	}
+}
@@ -439,6 +439,7 @@ func TestCheckoutValidationErrorNonInteractive(t *testing.T) {
 func TestTmuxStatusWithFakeGH(t *testing.T) {
 	t.Parallel()
 	repo := setupRepoWithManagedWorktree(t)
+	runCmd(t, repo.root, nil, "git", "remote", "add", "origin", "https://github.com/example/repo.git")
 	home := t.TempDir()
 	fakeBin := toolPathDir(t, true)
 	ghLog := filepath.Join(t.TempDir(), "gh.log")
@@ -475,6 +476,7 @@ exit 1
 func TestTmuxStatusWithoutGHFallsBack(t *testing.T) {
 	t.Parallel()
 	repo := setupRepoWithManagedWorktree(t)
+	runCmd(t, repo.root, nil, "git", "remote", "add", "origin", "https://github.com/example/repo.git")
 	home := t.TempDir()
 	fakeBin := toolPathDir(t, true)
 	env := testEnv(home)
@@ -487,6 +489,22 @@ func TestTmuxStatusWithoutGHFallsBack(t *testing.T) {
 	assertContains(t, result.out, "PR - | CI - | GH - | Review -")
 }
 
+func TestTmuxStatusLocalOnlyRepoSkipsGH(t *testing.T) {
+	t.Parallel()
+	repo := setupRepoWithManagedWorktree(t)
+	home := t.TempDir()
+	fakeBin := toolPathDir(t, true)
+	writeExecutable(t, filepath.Join(fakeBin, "gh"), "#!/bin/sh\necho \"gh must not run\" >&2\nexit 1\n")
+	env := testEnv(home)
+	env["PATH"] = fakeBin + string(os.PathListSeparator) + os.Getenv("PATH")
+
+	result := runWTX(t, repo.root, env, "tmux-status", "--worktree", repo.managedWT)
+	if result.err != nil {
+		t.Fatalf("tmux-status in a local-only repo failed: %v\n%s", result.err, result.out)
+	}
+	assertContains(t, result.out, "local only")
+}
+
 func TestDisableTmuxSkipsTmuxBinaryUsage(t *testing.T) {
 	t.Parallel()
 	repo := setupRepoWithManagedWorktree(t)
@@ -799,3 +817,193 @@ func TestE2ECreateBranchFailsInInitializedEmptyRepo(t *testing.T) {
 	assertContains(t, result.out, "no remotes are configured")
 	assertContains(t, result.out, "use --from <local-branch>")
 }
+
+func TestListJSONNonInteractive(t *testing.T) {
+	t.Parallel()
+	repo := setupRepoWithManagedWorktree(t)
+	home := t.TempDir()
+	writeConfig(t, home, "true")
+	env := testEnv(home)
+
+	result := runWTX(t, repo.root, env, "list", "--json", "--no-pr")
+	if result.err != nil {
+		t.Fatalf("list --json failed: %v\n%s", result.err, result.out)
+	}
+	var payload struct {
+		Worktrees []struct {
+			Path      string `json:"path"`
+			Branch    string `json:"branch"`
+			Available bool   `json:"available"`
+		} `json:"worktrees"`
+	}
+	if err := json.Unmarshal([]byte(result.out), &payload); err != nil {
+		t.Fatalf("decode list output: %v\n%s", err, result.out)
+	}
+	found := false
+	for _, wt := range payload.Worktrees {
+		if wt.Branch == "slot/one" {
+			found = true
+			if !wt.Available {
+				t.Fatalf("expected slot/one to be available")
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("expected slot/one in list output, got %s", result.out)
+	}
+}
+
+func TestRepoFlagAndGitDirPickTheRepo(t *testing.T) {
+	t.Parallel()
+	repo := setupRepoWithManagedWorktree(t)
+	home := t.TempDir()
+	writeConfig(t, home, "true")
+	env := testEnv(home)
+
+	result := runWTX(t, t.TempDir(), env, "--repo", repo.root, "list", "--no-pr")
+	if result.err != nil {
+		t.Fatalf("list --repo failed: %v\n%s", result.err, result.out)
+	}
+	assertContains(t, result.out, "slot/one")
+
+	// Git hooks run with GIT_DIR set, possibly from an unrelated directory.
+	env["GIT_DIR"] = filepath.Join(repo.root, ".git")
+	result = runWTX(t, t.TempDir(), env, "list", "--no-pr")
+	if result.err != nil {
+		t.Fatalf("list with GIT_DIR failed: %v\n%s", result.err, result.out)
+	}
+	assertContains(t, result.out, "slot/one")
+}
+
+func TestRootPrintsPlainTableWhenPiped(t *testing.T) {
+	t.Parallel()
+	repo := setupRepoWithManagedWorktree(t)
+	home := t.TempDir()
+	writeConfig(t, home, "true")
+	env := testEnv(home)
+
+	result := runWTX(t, repo.root, env)
+	if result.err != nil {
+		t.Fatalf("piped root command failed: %v\n%s", result.err, result.out)
+	}
+	assertContains(t, result.out, "BRANCH")
+	assertContains(t, result.out, "slot/one")
+
+	result = runWTX(t, repo.root, env, "--json")
+	if result.err != nil {
+		t.Fatalf("root --json failed: %v\n%s", result.err, result.out)
+	}
+	assertContains(t, result.out, `"worktrees"`)
+}
+
+func TestOpenExistingBranchNonInteractive(t *testing.T) {
+	t.Parallel()
+	repo := setupRepoWithManagedWorktree(t)
+	home := t.TempDir()
+	writeConfig(t, home, "true")
+	env := testEnv(home)
+
+	result := runWTX(t, repo.root, env, "open", "feature/existing")
+	if result.err != nil {
+		t.Fatalf("open existing failed: %v\n%s", result.err, result.out)
+	}
+
+	rootBranch := currentBranch(t, repo.root)
+	slotBranch := currentBranch(t, repo.managedWT)
+	if rootBranch != "feature/existing" && slotBranch != "feature/existing" {
+		t.Fatalf("expected feature/existing to be checked out in a worktree, got root=%q slot=%q", rootBranch, slotBranch)
+	}
+}
+
+func TestRmManagedWorktreeWithBranch(t *testing.T) {
+	t.Parallel()
+	repo := setupRepoWithManagedWorktree(t)
+	home := t.TempDir()
+	writeConfig(t, home, "true")
+	env := testEnv(home)
+
+	declined := runWTX(t, repo.root, env, "rm", "slot/one")
+	if _, err := os.Stat(repo.managedWT); err != nil {
+		t.Fatalf("expected unconfirmed rm to keep the worktree: %v\n%s", err, declined.out)
+	}
+
+	result := runWTX(t, repo.root, env, "rm", "slot/one", "--with-branch", "--yes")
+	if result.err != nil {
+		t.Fatalf("rm failed: %v\n%s", result.err, result.out)
+	}
+	if _, err := os.Stat(repo.managedWT); !os.IsNotExist(err) {
+		t.Fatalf("expected managed worktree to be removed, stat err=%v", err)
+	}
+	if branchExists(t, repo.root, "slot/one") {
+		t.Fatalf("expected slot/one branch to be deleted")
+	}
+}
+
+func TestExecRunsCommandInEachWorktree(t *testing.T) {
+	t.Parallel()
+	repo := setupRepoWithManagedWorktree(t)
+	home := t.TempDir()
+	writeConfig(t, home, "true")
+	env := testEnv(home)
+
+	result := runWTX(t, repo.root, env, "exec", "--all", "-j", "2", "--", "git rev-parse --abbrev-ref HEAD")
+	if result.err != nil {
+		t.Fatalf("exec failed: %v\n%s", result.err, result.out)
+	}
+	assertContains(t, result.out, "[slot/one] slot/one")
+	assertContains(t, result.out, "[main] main")
+
+	failed := runWTX(t, repo.root, env, "exec", "--branch", "slot/*", "--", "exit 3")
+	if failed.err == nil {
+		t.Fatalf("expected exec to fail when the command fails\n%s", failed.out)
+	}
+	assertContains(t, failed.out, "exit 3")
+}
+
+func TestRepoConfigPreparesNewWorktree(t *testing.T) {
+	t.Parallel()
+	repoRoot := setupSingleWorktreeRepo(t)
+	repoCfg := `{"worktree_dir": "../custom-wt", "copy_files": [".env"], "bootstrap_command": "touch bootstrapped"}`
+	if err := os.WriteFile(filepath.Join(repoRoot, ".wtx.json"), []byte(repoCfg), 0o644); err != nil {
+		t.Fatalf("write repo config: %v", err)
+	}
+	runCmd(t, repoRoot, nil, "git", "add", ".wtx.json")
+	runCmd(t, repoRoot, nil, "git", "commit", "-m", "add wtx config")
+	runCmd(t, repoRoot, nil, "git", "branch", "-f", "feature/existing", "main")
+	if err := os.WriteFile(filepath.Join(repoRoot, ".env"), []byte("SECRET=1\n"), 0o644); err != nil {
+		t.Fatalf("write .env: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(repoRoot, "README.md"), []byte("dirty\n"), 0o644); err != nil {
+		t.Fatalf("dirty root: %v", err)
+	}
+
+	home := t.TempDir()
+	writeConfig(t, home, "true")
+	env := testEnv(home)
+	// The bootstrap and worktree_dir only apply once the file is trusted.
+	if result := runWTX(t, repoRoot, env, "config", "trust"); result.err != nil {
+		t.Fatalf("config trust failed: %v\n%s", result.err, result.out)
+	}
+
+	result := runWTX(t, repoRoot, env, "open", "feature/existing", "--create")
+	if result.err != nil {
+		t.Fatalf("open --create failed: %v\n%s", result.err, result.out)
+	}
+	created := filepath.Join(filepath.Dir(repoRoot), "custom-wt", "wt.1")
+	if got := currentBranch(t, created); got != "feature/existing" {
+		t.Fatalf("expected feature/existing in %s, got %q", created, got)
+	}
+	if data, err := os.ReadFile(filepath.Join(created, ".env")); err != nil || string(data) != "SECRET=1\n" {
+		t.Fatalf("expected .env to be copied, got %q err=%v", string(data), err)
+	}
+	// The bootstrap runs in the background after the worktree is created.
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		if _, err := os.Stat(filepath.Join(created, "bootstrapped")); err == nil {
+			break
+		} else if time.Now().After(deadline) {
+			t.Fatalf("expected bootstrap command to run: %v", err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
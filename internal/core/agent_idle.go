@@ -0,0 +1,62 @@
+package core
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// tmuxPaneActivityByPID maps pane PIDs to the last output time of their
+// window. Typed input is echoed, so it counts as activity too.
+func tmuxPaneActivityByPID() map[int]int64 {
+	if TmuxIntegrationDisabled() {
+		return map[int]int64{}
+	}
+	if _, err := exec.LookPath("tmux"); err != nil {
+		return map[int]int64{}
+	}
+	out, err := TmuxOutput("list-panes", "-a", "-F", "#{pane_pid} #{window_activity}")
+	if err != nil {
+		return map[int]int64{}
+	}
+	return parsePaneActivity(string(out))
+}
+
+func parsePaneActivity(output string) map[int]int64 {
+	activity := map[int]int64{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		pid, err := strconv.Atoi(fields[0])
+		if err != nil || pid <= 0 {
+			continue
+		}
+		ts, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil || ts <= 0 {
+			continue
+		}
+		activity[pid] = ts
+	}
+	return activity
+}
+
+func agentIdleThreshold(cfg Config) time.Duration {
+	if cfg.AgentIdleMinutes <= 0 {
+		return 0
+	}
+	return time.Duration(cfg.AgentIdleMinutes) * time.Minute
+}
+
+func paneIdle(activity map[int]int64, pid int, threshold time.Duration, now time.Time) bool {
+	if threshold <= 0 || pid <= 0 {
+		return false
+	}
+	last, ok := activity[pid]
+	if !ok {
+		return false
+	}
+	return now.Sub(time.Unix(last, 0)) >= threshold
+}
@@ -0,0 +1,35 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePaneActivity(t *testing.T) {
+	got := parsePaneActivity("123 1700000000\nbad line\n456 0\n789 1700000100\n")
+	if len(got) != 2 {
+		t.Fatalf("expected 2 panes, got %#v", got)
+	}
+	if got[123] != 1700000000 || got[789] != 1700000100 {
+		t.Fatalf("unexpected activity map: %#v", got)
+	}
+}
+
+func TestPaneIdle(t *testing.T) {
+	now := time.Unix(1700003600, 0)
+	activity := map[int]int64{10: 1700000000, 20: 1700003500}
+	threshold := 30 * time.Minute
+
+	if !paneIdle(activity, 10, threshold, now) {
+		t.Fatalf("expected pane 10 to be idle")
+	}
+	if paneIdle(activity, 20, threshold, now) {
+		t.Fatalf("expected pane 20 to be active")
+	}
+	if paneIdle(activity, 30, threshold, now) {
+		t.Fatalf("expected unknown pane not to be idle")
+	}
+	if paneIdle(activity, 10, 0, now) {
+		t.Fatalf("expected idle detection disabled without threshold")
+	}
+}
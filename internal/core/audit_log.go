@@ -0,0 +1,171 @@
+package core
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	AuditCreate       = "create"
+	AuditDelete       = "delete"
+	AuditDeleteBranch = "delete-branch"
+	AuditLock         = "lock"
+	AuditLockSteal    = "lock-steal"
+	AuditUnlock       = "unlock"
+	AuditRename       = "rename"
+	AuditAgent        = "agent"
+)
+
+const auditLogFileName = "history.jsonl"
+
+// auditLogMaxBytes is the size at which history.jsonl is rotated to
+// history.jsonl.1, which log retention then ages out.
+const auditLogMaxBytes = 10 << 20
+
+// AuditEntry is one line of ~/.wtx/history.jsonl.
+type AuditEntry struct {
+	Time     time.Time         `json:"time"`
+	Action   string            `json:"action"`
+	User     string            `json:"user,omitempty"`
+	Host     string            `json:"host,omitempty"`
+	PID      int               `json:"pid"`
+	Repo     string            `json:"repo,omitempty"`
+	Worktree string            `json:"worktree,omitempty"`
+	Branch   string            `json:"branch,omitempty"`
+	Details  map[string]string `json:"details,omitempty"`
+}
+
+func auditLogPath() (string, error) {
+	home, err := WtxHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, auditLogFileName), nil
+}
+
+// RecordAction appends an entry to the audit log. Failing to record never
+// fails the action itself.
+func RecordAction(action string, repoRoot string, worktreePath string, branch string, details map[string]string) {
+	entry := AuditEntry{
+		Time:     time.Now().UTC(),
+		Action:   action,
+		User:     currentUserName(),
+		Host:     hostName(),
+		PID:      os.Getpid(),
+		Repo:     AuditRepo(repoRoot),
+		Worktree: strings.TrimSpace(worktreePath),
+		Branch:   strings.TrimSpace(branch),
+		Details:  details,
+	}
+	if err := appendAuditEntry(entry); err != nil {
+		DebugLog("audit log write failed", "action", action, "err", err.Error())
+	}
+}
+
+// AuditRepo names a repo by its main worktree, so actions taken from linked
+// worktrees are listed with the repo they belong to.
+func AuditRepo(repoRoot string) string {
+	repoRoot = strings.TrimSpace(repoRoot)
+	if repoRoot == "" {
+		return ""
+	}
+	if _, commonDir, err := ResolveGitDirs(repoRoot); err == nil && filepath.Base(commonDir) == ".git" {
+		repoRoot = filepath.Dir(commonDir)
+	}
+	if real, err := realPath(repoRoot); err == nil {
+		return real
+	}
+	return repoRoot
+}
+
+func appendAuditEntry(entry AuditEntry) error {
+	path, err := auditLogPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() > auditLogMaxBytes {
+		_ = os.Rename(path, path+".1")
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	// One write per line keeps concurrent wtx processes from interleaving.
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+type AuditFilter struct {
+	Repo   string
+	Action string
+	Since  time.Time
+}
+
+func (f AuditFilter) match(entry AuditEntry) bool {
+	if f.Repo != "" && entry.Repo != f.Repo {
+		return false
+	}
+	if f.Action != "" && entry.Action != f.Action {
+		return false
+	}
+	return f.Since.IsZero() || !entry.Time.Before(f.Since)
+}
+
+// ReadAuditLog returns matching entries oldest first, including those in the
+// rotated file. Lines that do not parse are skipped so a torn write cannot
+// hide the rest of the history.
+func ReadAuditLog(filter AuditFilter) ([]AuditEntry, error) {
+	path, err := auditLogPath()
+	if err != nil {
+		return nil, err
+	}
+	var entries []AuditEntry
+	for _, file := range []string{path + ".1", path} {
+		if entries, err = readAuditFile(file, filter, entries); err != nil {
+			return nil, err
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.Before(entries[j].Time) })
+	return entries, nil
+}
+
+func readAuditFile(path string, filter AuditFilter, entries []AuditEntry) ([]AuditEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return entries, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for scanner.Scan() {
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if filter.match(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
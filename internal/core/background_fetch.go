@@ -0,0 +1,60 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// fetchStampPath is touched after each successful background fetch of the
+// repo. It is shared by all wtx processes and worktrees of the repo, so only
+// one of them fetches per interval; a failed fetch touches the ".failed" file
+// next to it instead.
+func fetchStampPath(repoRoot string) (string, error) {
+	home, err := WtxHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "fetch", HashString(AuditRepo(repoRoot))[:16]), nil
+}
+
+// BackgroundFetch runs `git fetch --prune` from the preferred remote when
+// no wtx process has fetched in the last interval. A failed fetch is retried
+// an interval later rather than every tick.
+func (m *WorktreeManager) BackgroundFetch(interval time.Duration) (bool, error) {
+	gitPath, repoRoot, err := RequireGitContext(m.Cwd)
+	if err != nil {
+		return false, err
+	}
+	remote := PreferredRemoteName(repoRoot, gitPath)
+	if remote == "" {
+		return false, nil
+	}
+	stamp, err := fetchStampPath(repoRoot)
+	if err != nil {
+		return false, err
+	}
+	failed := stamp + ".failed"
+	if stampedWithin(stamp, interval) || stampedWithin(failed, interval) {
+		return false, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(stamp), 0o755); err != nil {
+		return false, err
+	}
+	if err := RunCommandInDir(repoRoot, gitPath, "fetch", "--prune", remote); err != nil {
+		if writeErr := os.WriteFile(failed, nil, 0o644); writeErr != nil {
+			DebugLog("background fetch stamp failed", "err", writeErr.Error())
+		}
+		return false, err
+	}
+	os.Remove(failed)
+	if err := os.WriteFile(stamp, nil, 0o644); err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
+func stampedWithin(path string, interval time.Duration) bool {
+	info, err := os.Stat(path)
+	return err == nil && time.Since(info.ModTime()) < interval
+}
@@ -0,0 +1,30 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBackgroundFetchFailureIsNotRecordedAsFetched(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	repo := initRenameTestRepo(t)
+	runGitInRepo(t, repo, "remote", "add", "origin", filepath.Join(home, "missing.git"))
+
+	mgr := NewWorktreeManager(repo, NewLockManager())
+	if fetched, err := mgr.BackgroundFetch(time.Hour); err == nil || fetched {
+		t.Fatalf("expected the fetch to fail, got fetched=%v err=%v", fetched, err)
+	}
+	stamp, err := fetchStampPath(repo)
+	if err != nil {
+		t.Fatalf("stamp path: %v", err)
+	}
+	if _, err := os.Stat(stamp); !os.IsNotExist(err) {
+		t.Fatalf("expected no fetch stamp after a failure, stat err=%v", err)
+	}
+	if fetched, err := mgr.BackgroundFetch(time.Hour); err != nil || fetched {
+		t.Fatalf("expected the retry to wait an interval, got fetched=%v err=%v", fetched, err)
+	}
+}
@@ -1,9 +1,8 @@
-package cmd
+package core
 
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -15,27 +14,38 @@ type recentBranchCache struct {
 	Branches []string `json:"branches"`
 }
 
-func wtxHomeDir() (string, error) {
-	home := strings.TrimSpace(os.Getenv("HOME"))
-	if home == "" {
-		return "", errors.New("HOME not set")
+func WtxHomeDir() (string, error) {
+	home, err := UserHomeDir()
+	if err != nil {
+		return "", err
 	}
 	return filepath.Join(home, ".wtx"), nil
 }
 
+// UserHomeDir reads the environment rather than os.UserHomeDir so tests and
+// scripts can redirect wtx state with HOME alone.
+func UserHomeDir() (string, error) {
+	for _, key := range homeEnvKeys {
+		if home := strings.TrimSpace(os.Getenv(key)); home != "" {
+			return home, nil
+		}
+	}
+	return "", errors.New("HOME not set")
+}
+
 func recentBranchCachePath(repoRoot string) (string, error) {
 	repoRoot = strings.TrimSpace(repoRoot)
 	if repoRoot == "" {
 		return "", errors.New("repo root required")
 	}
-	home, err := wtxHomeDir()
+	home, err := WtxHomeDir()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(home, "cache", "recent_branches", hashString(repoRoot)+".json"), nil
+	return filepath.Join(home, "cache", "recent_branches", HashString(repoRoot)+".json"), nil
 }
 
-func readRecentBranches(repoRoot string, limit int) ([]string, error) {
+func ReadRecentBranches(repoRoot string, limit int) ([]string, error) {
 	if limit <= 0 {
 		return []string{}, nil
 	}
@@ -93,7 +103,7 @@ func recordRecentBranch(repoRoot string, branch string) error {
 	if repoRoot == "" || branch == "" || branch == "detached" {
 		return nil
 	}
-	recent, err := readRecentBranches(repoRoot, recentBranchCacheLimit)
+	recent, err := ReadRecentBranches(repoRoot, recentBranchCacheLimit)
 	if err != nil {
 		return err
 	}
@@ -111,16 +121,17 @@ func recordRecentBranch(repoRoot string, branch string) error {
 	return writeRecentBranches(repoRoot, merged)
 }
 
-func recordRecentBranchForWorktree(worktreePath string, branch string) {
+// RecordRecentBranchForWorktree moves branch to the front of the recent
+// branch cache of the repo worktreePath belongs to. Paths outside a git repo
+// are ignored.
+func RecordRecentBranchForWorktree(worktreePath string, branch string) error {
 	branch = strings.TrimSpace(branch)
 	if branch == "" || branch == "detached" {
-		return
+		return nil
 	}
-	_, repoRoot, err := requireGitContext(worktreePath)
+	_, repoRoot, err := RequireGitContext(worktreePath)
 	if err != nil {
-		return
-	}
-	if err := recordRecentBranch(repoRoot, branch); err != nil {
-		fmt.Fprintln(os.Stderr, "wtx warning: failed to update recent branch cache:", err)
+		return nil
 	}
+	return recordRecentBranch(repoRoot, branch)
 }
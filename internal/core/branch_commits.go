@@ -0,0 +1,71 @@
+package core
+
+import (
+	"strconv"
+	"strings"
+)
+
+// branchCommitFormat yields one NUL separated record per local branch.
+const branchCommitFormat = "%(refname)%00%(objectname)%00%(committerdate:unix)%00%(upstream:short)%00%(upstream:track,nobracket)%00%(contents:subject)"
+
+// BranchCommits returns commit metadata for every local branch, keyed the
+// same way as WorktreeInfo.Branch. It costs a single git invocation no matter
+// how many worktrees exist, so callers should fetch once per refresh rather
+// than per worktree.
+func (m *WorktreeManager) BranchCommits() (map[string]BranchCommit, error) {
+	gitPath, repoRoot, err := RequireGitContext(m.Cwd)
+	if err != nil {
+		return nil, err
+	}
+	output, err := CommandOutputInDir(repoRoot, gitPath, "for-each-ref", "--format="+branchCommitFormat, "refs/heads/")
+	if err != nil {
+		return nil, err
+	}
+	return parseBranchCommits(string(output)), nil
+}
+
+func parseBranchCommits(output string) map[string]BranchCommit {
+	commits := map[string]BranchCommit{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(line, "\x00", 6)
+		if len(fields) != 6 || !strings.HasPrefix(fields[0], "refs/heads/") {
+			continue
+		}
+		committed, _ := strconv.ParseInt(strings.TrimSpace(fields[2]), 10, 64)
+		commit := BranchCommit{
+			Hash:          strings.TrimSpace(fields[1]),
+			CommittedUnix: committed,
+			Upstream:      strings.TrimSpace(fields[3]),
+			Subject:       strings.TrimSpace(fields[5]),
+		}
+		commit.Ahead, commit.Behind, commit.UpstreamGone = parseUpstreamTrack(fields[4])
+		commits[ShortBranch(fields[0])] = commit
+	}
+	return commits
+}
+
+// parseUpstreamTrack parses %(upstream:track,nobracket), e.g. "ahead 2,
+// behind 1" or "gone".
+func parseUpstreamTrack(track string) (int, int, bool) {
+	ahead, behind := 0, 0
+	for _, part := range strings.Split(track, ",") {
+		fields := strings.Fields(part)
+		if len(fields) == 1 && fields[0] == "gone" {
+			return 0, 0, true
+		}
+		if len(fields) != 2 {
+			continue
+		}
+		n, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "ahead":
+			ahead = n
+		case "behind":
+			behind = n
+		}
+	}
+	return ahead, behind, false
+}
@@ -0,0 +1,40 @@
+package core
+
+import "testing"
+
+func TestParseBranchCommits(t *testing.T) {
+	output := "refs/heads/main\x00abc\x00100\x00origin/main\x00ahead 2, behind 1\x00Fix: a, b\n" +
+		"refs/heads/feature/x\x00def\x00200\x00origin/feature/x\x00gone\x00WIP\n" +
+		"refs/heads/local\x00fed\x00300\x00\x00\x00Local only\n"
+	commits := parseBranchCommits(output)
+	if len(commits) != 3 {
+		t.Fatalf("expected 3 branches, got %v", commits)
+	}
+	main := commits["main"]
+	if main.Ahead != 2 || main.Behind != 1 || main.Subject != "Fix: a, b" || main.CommittedUnix != 100 {
+		t.Fatalf("unexpected main commit: %+v", main)
+	}
+	if !commits["feature/x"].UpstreamGone {
+		t.Fatalf("expected feature/x upstream gone, got %+v", commits["feature/x"])
+	}
+	if local := commits["local"]; local.Upstream != "" || local.Ahead != 0 || local.Hash != "fed" {
+		t.Fatalf("unexpected local commit: %+v", local)
+	}
+}
+
+func TestBranchCommitsTracksUpstream(t *testing.T) {
+	repo := initGitReadTestRepo(t)
+	runGitInRepo(t, repo, "branch", "--set-upstream-to=older", "newer")
+
+	commits, err := NewWorktreeManager(repo, nil).BranchCommits()
+	if err != nil {
+		t.Fatalf("BranchCommits: %v", err)
+	}
+	newer := commits["newer"]
+	if newer.Subject != "newer" || newer.Upstream != "older" || newer.Ahead != 1 || newer.Behind != 0 {
+		t.Fatalf("unexpected newer commit: %+v", newer)
+	}
+	if commits["older"].Hash == "" {
+		t.Fatalf("expected older branch, got %v", commits)
+	}
+}
@@ -0,0 +1,162 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// gitCommandTimeout is generous because fetches and worktree adds on large
+	// repos legitimately take a while; it only exists so a stuck credential
+	// helper cannot hang wtx forever.
+	gitCommandTimeout       = 2 * time.Minute
+	GHCommandTimeout        = 15 * time.Second
+	tmuxCommandTimeout      = 5 * time.Second
+	osascriptCommandTimeout = 10 * time.Second
+	maxConcurrentCommands   = 16
+	commandWaitDelay        = time.Second
+)
+
+// commandSlots caps how many external processes wtx runs at once, so a burst
+// of status and PR refreshes cannot fork dozens of git and gh processes.
+var commandSlots = make(chan struct{}, maxConcurrentCommands)
+
+// promptDisabledEnv stops git and gh from asking for credentials on a TTY
+// they do not own. A prompt nobody can answer would otherwise sit until the
+// timeout.
+var promptDisabledEnv = []string{"GIT_TERMINAL_PROMPT=0", "GH_PROMPT_DISABLED=1"}
+
+type CommandSpec struct {
+	Dir     string
+	Name    string
+	Args    []string
+	Env     []string
+	Timeout time.Duration
+	// StdoutOnly drops stderr from the returned output, for callers that
+	// parse it as JSON.
+	StdoutOnly bool
+	// Progress, when set, also receives the output as it is produced, for
+	// callers that show long running commands live.
+	Progress io.Writer
+	// Script, when set, runs through the platform shell instead of Name and
+	// Args; Name then only labels the command in logs and errors.
+	Script string
+}
+
+// RunExternalCommand runs a non-interactive command under ctx, the spec
+// timeout and the global concurrency limit. Timeouts are reported as errors
+// wrapping context.DeadlineExceeded.
+func RunExternalCommand(ctx context.Context, spec CommandSpec) ([]byte, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if spec.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, spec.Timeout)
+		defer cancel()
+	}
+	select {
+	case commandSlots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, commandContextError(ctx, spec)
+	}
+	defer func() { <-commandSlots }()
+
+	cmd := exec.CommandContext(ctx, spec.Name, spec.Args...)
+	if spec.Script != "" {
+		cmd = shellScriptCommandContext(ctx, spec.Script, false)
+	}
+	cmd.Dir = spec.Dir
+	cmd.Env = append(append(os.Environ(), promptDisabledEnv...), spec.Env...)
+	cmd.WaitDelay = commandWaitDelay
+	start := time.Now()
+	var out []byte
+	var err error
+	switch {
+	case spec.Progress != nil:
+		var buf bytes.Buffer
+		w := io.MultiWriter(&buf, spec.Progress)
+		cmd.Stdout = w
+		if !spec.StdoutOnly {
+			cmd.Stderr = w
+		}
+		err = cmd.Run()
+		out = buf.Bytes()
+	case spec.StdoutOnly:
+		out, err = cmd.Output()
+	default:
+		out, err = cmd.CombinedOutput()
+	}
+	if err != nil && ctx.Err() != nil {
+		err = commandContextError(ctx, spec)
+	}
+	elapsed := time.Since(start)
+	logCommand(spec, elapsed, err)
+	recordCommandTiming(spec, elapsed)
+	traceCommand(spec, start, elapsed, err)
+	return out, err
+}
+
+// CommandError is a failed command together with what it printed. Its message
+// is the output alone, as before, while the detail view in the TUI shows the
+// command and directory too.
+type CommandError struct {
+	Command string
+	Dir     string
+	Output  string
+	Err     error
+}
+
+func (e *CommandError) Error() string {
+	if e.Output != "" {
+		return e.Output
+	}
+	return e.Err.Error()
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+func commandContextError(ctx context.Context, spec CommandSpec) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%s timed out after %s: %w", commandLabel(spec), spec.Timeout.Round(time.Millisecond), context.DeadlineExceeded)
+	}
+	return fmt.Errorf("%s canceled: %w", commandLabel(spec), ctx.Err())
+}
+
+func commandLabel(spec CommandSpec) string {
+	name := spec.Name
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if len(spec.Args) > 0 && !strings.HasPrefix(spec.Args[0], "-") {
+		return name + " " + spec.Args[0]
+	}
+	return name
+}
+
+func TmuxOutput(args ...string) ([]byte, error) {
+	return RunExternalCommand(context.Background(), CommandSpec{Name: "tmux", Args: args, Timeout: tmuxCommandTimeout, StdoutOnly: true})
+}
+
+func TmuxCombinedOutput(args ...string) ([]byte, error) {
+	return RunExternalCommand(context.Background(), CommandSpec{Name: "tmux", Args: args, Timeout: tmuxCommandTimeout})
+}
+
+func TmuxRun(args ...string) error {
+	_, err := RunExternalCommand(context.Background(), CommandSpec{Name: "tmux", Args: args, Timeout: tmuxCommandTimeout})
+	return err
+}
+
+func OsascriptRun(args ...string) error {
+	_, err := RunExternalCommand(context.Background(), CommandSpec{Name: "osascript", Args: args, Timeout: osascriptCommandTimeout})
+	return err
+}
@@ -0,0 +1,64 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunExternalCommandTimesOut(t *testing.T) {
+	script := "sleep 5"
+	if runtime.GOOS == "windows" {
+		script = "ping -n 6 127.0.0.1 >NUL"
+	}
+	started := time.Now()
+	_, err := RunExternalCommand(context.Background(), CommandSpec{Name: "sleep", Args: []string{"5"}, Script: script, Timeout: 100 * time.Millisecond})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "sleep 5 timed out") {
+		t.Fatalf("expected command in timeout error, got %q", err.Error())
+	}
+	if elapsed := time.Since(started); elapsed > 3*time.Second {
+		t.Fatalf("expected timeout to stop the command quickly, took %s", elapsed)
+	}
+}
+
+func TestRunExternalCommandDisablesPrompts(t *testing.T) {
+	script := `echo "$GIT_TERMINAL_PROMPT $GH_PROMPT_DISABLED $EXTRA"`
+	if runtime.GOOS == "windows" {
+		script = "echo %GIT_TERMINAL_PROMPT% %GH_PROMPT_DISABLED% %EXTRA%"
+	}
+	out, err := RunExternalCommand(context.Background(), CommandSpec{
+		Name:    "echo",
+		Script:  script,
+		Env:     []string{"EXTRA=yes"},
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "0 1 yes" {
+		t.Fatalf("expected prompt env and extra env, got %q", got)
+	}
+}
+
+func TestRunExternalCommandWaitsForSlot(t *testing.T) {
+	for i := 0; i < cap(commandSlots); i++ {
+		commandSlots <- struct{}{}
+	}
+	t.Cleanup(func() {
+		for i := 0; i < cap(commandSlots); i++ {
+			<-commandSlots
+		}
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := RunExternalCommand(ctx, CommandSpec{Name: "true"})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected a canceled wait for a free slot, got %v", err)
+	}
+}
@@ -0,0 +1,246 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type Config struct {
+	Version               int               `json:"version,omitempty"`
+	AgentCommand          string            `json:"agent_command"`
+	AgentProfiles         []AgentProfile    `json:"agent_profiles,omitempty"`
+	DefaultAgentProfile   string            `json:"default_agent_profile,omitempty"`
+	NewBranchBaseRef      string            `json:"new_branch_base_ref,omitempty"`
+	NewBranchFetchFirst   *bool             `json:"new_branch_fetch_first,omitempty"`
+	UpdateStrategy        string            `json:"update_strategy,omitempty"`
+	IDECommand            string            `json:"ide_command,omitempty"`
+	IDEVSCodeWorkspace    bool              `json:"ide_vscode_workspace,omitempty"`
+	IDEWindow             string            `json:"ide_window,omitempty"`
+	GitUICommand          string            `json:"git_ui_command,omitempty"`
+	LinuxTerminal         string            `json:"linux_terminal,omitempty"`
+	MainScreenBranchLimit int               `json:"main_screen_branch_limit,omitempty"`
+	AgentLogs             *bool             `json:"agent_logs,omitempty"`
+	AgentLogKeep          int               `json:"agent_log_keep,omitempty"`
+	LogRetentionDays      int               `json:"log_retention_days,omitempty"`
+	LogMaxMB              int               `json:"log_max_mb,omitempty"`
+	AgentIdleMinutes      int               `json:"agent_idle_minutes,omitempty"`
+	AgentIdleRelease      bool              `json:"agent_idle_release,omitempty"`
+	PRRefreshSeconds      int               `json:"pr_refresh_seconds,omitempty"`
+	BackgroundFetch       bool              `json:"background_fetch,omitempty"`
+	FetchIntervalMinutes  int               `json:"background_fetch_minutes,omitempty"`
+	DeleteGraceSeconds    int               `json:"delete_grace_seconds,omitempty"`
+	GCMergedDays          int               `json:"gc_merged_days,omitempty"`
+	GCUntouchedDays       int               `json:"gc_untouched_days,omitempty"`
+	GCOnStartup           bool              `json:"gc_on_startup,omitempty"`
+	AgentLayout           string            `json:"agent_layout,omitempty"`
+	AgentResumeCommand    string            `json:"agent_resume_command,omitempty"`
+	BootstrapCommand      string            `json:"bootstrap_command,omitempty"`
+	TestCommand           string            `json:"test_command,omitempty"`
+	LFSSkipSmudge         bool              `json:"lfs_skip_smudge,omitempty"`
+	Direnv                bool              `json:"direnv,omitempty"`
+	WorktreeDir           string            `json:"worktree_dir,omitempty"`
+	WorktreeVolume        string            `json:"worktree_volume,omitempty"`
+	CopyFiles             []string          `json:"copy_files,omitempty"`
+	SyncFiles             []SyncFile        `json:"sync_files,omitempty"`
+	CustomActions         []CustomAction    `json:"custom_actions,omitempty"`
+	UpdateCheck           string            `json:"update_check,omitempty"`
+	Theme                 string            `json:"theme,omitempty"`
+	Colors                map[string]string `json:"colors,omitempty"`
+	UpdateChannel         string            `json:"update_channel,omitempty"`
+	UpdateBaseURL         string            `json:"update_base_url,omitempty"`
+	Keybindings           map[string]string `json:"keybindings,omitempty"`
+	Hooks                 map[string]string `json:"hooks,omitempty"`
+}
+
+// AgentProfile is a named agent launch command. An empty command opens a
+// plain shell instead of an agent.
+type AgentProfile struct {
+	Name    string `json:"name"`
+	Command string `json:"command,omitempty"`
+}
+
+const DefaultAgentCommand = "claude"
+
+const DefaultIDECommand = "code"
+
+const DefaultMainScreenBranchLimit = 5
+
+const ConfigDirOverrideEnv = "WTX_CONFIG_DIR"
+
+func LoadConfig() (Config, error) {
+	path, err := ConfigPath()
+	if err != nil {
+		return Config{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	// Older files are upgraded in memory only; `wtx config migrate` is the one
+	// place that rewrites them.
+	migrated, _, _, err := MigrateConfigData(data)
+	if err != nil {
+		return Config{}, err
+	}
+	var cfg Config
+	if err := json.Unmarshal(migrated, &cfg); err != nil {
+		return Config{}, err
+	}
+	cfg.AgentCommand = strings.TrimSpace(cfg.AgentCommand)
+	cfg.IDECommand = strings.TrimSpace(cfg.IDECommand)
+	cfg.GitUICommand = strings.TrimSpace(cfg.GitUICommand)
+	cfg.LinuxTerminal = strings.TrimSpace(cfg.LinuxTerminal)
+	cfg.NewBranchBaseRef = strings.TrimSpace(cfg.NewBranchBaseRef)
+	cfg.UpdateStrategy = strings.TrimSpace(cfg.UpdateStrategy)
+	cfg.AgentResumeCommand = strings.TrimSpace(cfg.AgentResumeCommand)
+	cfg.BootstrapCommand = strings.TrimSpace(cfg.BootstrapCommand)
+	cfg.TestCommand = strings.TrimSpace(cfg.TestCommand)
+	cfg.WorktreeDir = strings.TrimSpace(cfg.WorktreeDir)
+	cfg.WorktreeVolume = strings.TrimSpace(cfg.WorktreeVolume)
+	cfg.CopyFiles = NormalizeCopyFiles(cfg.CopyFiles)
+	cfg.SyncFiles = normalizeSyncFiles(cfg.SyncFiles)
+	cfg.CustomActions = normalizeCustomActions(cfg.CustomActions)
+	cfg.DefaultAgentProfile = strings.TrimSpace(cfg.DefaultAgentProfile)
+	cfg.AgentProfiles = NormalizeAgentProfiles(cfg.AgentProfiles)
+	if cfg.MainScreenBranchLimit <= 0 {
+		cfg.MainScreenBranchLimit = DefaultMainScreenBranchLimit
+	}
+	return cfg, nil
+}
+
+// LoadConfigForDir returns the global config with the overrides of the repo
+// containing dir applied. Use LoadConfig when the result will be saved.
+func LoadConfigForDir(dir string) (Config, error) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return Config{}, err
+		}
+		cfg = Config{MainScreenBranchLimit: DefaultMainScreenBranchLimit}
+	}
+	if strings.TrimSpace(dir) == "" {
+		dir, _ = os.Getwd()
+	}
+	return ApplyRepoConfig(cfg, dir)
+}
+
+func NormalizeCopyFiles(globs []string) []string {
+	out := make([]string, 0, len(globs))
+	for _, g := range globs {
+		if g = strings.TrimSpace(g); g != "" {
+			out = append(out, g)
+		}
+	}
+	return out
+}
+
+func NormalizeAgentProfiles(profiles []AgentProfile) []AgentProfile {
+	out := make([]AgentProfile, 0, len(profiles))
+	seen := make(map[string]bool, len(profiles))
+	for _, p := range profiles {
+		p.Name = strings.TrimSpace(p.Name)
+		p.Command = strings.TrimSpace(p.Command)
+		if p.Name == "" || seen[p.Name] {
+			continue
+		}
+		seen[p.Name] = true
+		out = append(out, p)
+	}
+	return out
+}
+
+func NormalizeMainScreenBranchLimit(input string) (int, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return DefaultMainScreenBranchLimit, nil
+	}
+	limit, err := strconv.Atoi(input)
+	if err != nil || limit <= 0 {
+		return 0, errors.New("main screen branch count must be a positive number")
+	}
+	return limit, nil
+}
+
+func ConfigExists() (bool, error) {
+	path, err := ConfigPath()
+	if err != nil {
+		return false, err
+	}
+	_, err = os.Stat(path)
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return false, err
+}
+
+func SaveConfig(cfg Config) error {
+	path, err := ConfigPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	// Saving would drop keys this wtx does not know about, so a file written
+	// by a newer version is left alone.
+	if version := max(cfg.Version, savedConfigVersion(path)); version > CurrentConfigVersion {
+		return NewerConfigError(path, version)
+	}
+	cfg.Version = CurrentConfigVersion
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	return WriteFileAtomic(path, append(data, '\n'))
+}
+
+// SaveConfigValues writes a raw config object, as `wtx config set` edits it,
+// with the same rules as SaveConfig: the current version is stamped, the
+// write is atomic and a file from a newer wtx is refused.
+func SaveConfigValues(path string, values map[string]json.RawMessage) error {
+	version, err := configDataVersion(values)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if version = max(version, savedConfigVersion(path)); version > CurrentConfigVersion {
+		return NewerConfigError(path, version)
+	}
+	encoded, err := json.Marshal(CurrentConfigVersion)
+	if err != nil {
+		return err
+	}
+	values["version"] = encoded
+	data, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return WriteFileAtomic(path, append(data, '\n'))
+}
+
+func ConfigPath() (string, error) {
+	if dir := strings.TrimSpace(os.Getenv(ConfigDirOverrideEnv)); dir != "" {
+		return filepath.Join(dir, "config.json"), nil
+	}
+	home, err := UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".wtx", "config.json"), nil
+}
+
+const (
+	UpdateCheckOff    = "off"
+	UpdateCheckDaily  = "daily"
+	UpdateCheckWeekly = "weekly"
+)
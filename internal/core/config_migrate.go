@@ -0,0 +1,127 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// configMigrations[i] upgrades a config file from version i to i+1. Append a
+// step whenever a key is renamed or reshaped; never edit an existing one.
+var configMigrations = []func(values map[string]json.RawMessage) error{
+	// v0 -> v1: files written before versioning; the layout is unchanged.
+	func(map[string]json.RawMessage) error { return nil },
+	// v1 -> v2: the update_checks bool folds into update_check; false
+	// becomes "off" unless a frequency was already chosen.
+	func(values map[string]json.RawMessage) error {
+		raw, ok := values["update_checks"]
+		if !ok {
+			return nil
+		}
+		delete(values, "update_checks")
+		var enabled bool
+		if err := json.Unmarshal(raw, &enabled); err != nil {
+			return fmt.Errorf("update_checks: %w", err)
+		}
+		if enabled {
+			return nil
+		}
+		if _, set := values["update_check"]; set {
+			return nil
+		}
+		off, err := json.Marshal(UpdateCheckOff)
+		if err != nil {
+			return err
+		}
+		values["update_check"] = off
+		return nil
+	},
+	// v2 -> v3: color_scheme folds into theme, which now picks the color
+	// scheme for every view. The old form theme names are dropped, so those
+	// files get the default scheme.
+	func(values map[string]json.RawMessage) error {
+		if scheme, ok := values["color_scheme"]; ok {
+			delete(values, "color_scheme")
+			values["theme"] = scheme
+			return nil
+		}
+		raw, ok := values["theme"]
+		if !ok {
+			return nil
+		}
+		var name string
+		if err := json.Unmarshal(raw, &name); err != nil {
+			return fmt.Errorf("theme: %w", err)
+		}
+		switch strings.TrimSpace(name) {
+		case "charm", "dracula", "catppuccin", "base16", "base":
+			delete(values, "theme")
+		}
+		return nil
+	},
+}
+
+var CurrentConfigVersion = len(configMigrations)
+
+// MigrateConfigData upgrades raw config JSON to currentConfigVersion. It
+// reports the version found and whether anything changed.
+func MigrateConfigData(data []byte) ([]byte, int, bool, error) {
+	values := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, 0, false, err
+	}
+	from, err := configDataVersion(values)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	if from >= CurrentConfigVersion {
+		return data, from, false, nil
+	}
+	for v := from; v < CurrentConfigVersion; v++ {
+		if err := configMigrations[v](values); err != nil {
+			return nil, from, false, fmt.Errorf("migrate config v%d to v%d: %w", v, v+1, err)
+		}
+	}
+	encoded, err := json.Marshal(CurrentConfigVersion)
+	if err != nil {
+		return nil, from, false, err
+	}
+	values["version"] = encoded
+	out, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return nil, from, false, err
+	}
+	return append(out, '\n'), from, true, nil
+}
+
+func configDataVersion(values map[string]json.RawMessage) (int, error) {
+	raw, ok := values["version"]
+	if !ok {
+		return 0, nil
+	}
+	var version int
+	if err := json.Unmarshal(raw, &version); err != nil || version < 0 {
+		return 0, fmt.Errorf("invalid config version %s", string(raw))
+	}
+	return version, nil
+}
+
+// savedConfigVersion returns the version of the config file at path, or 0
+// when it is missing or unreadable.
+func savedConfigVersion(path string) int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	values := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return 0
+	}
+	version, _ := configDataVersion(values)
+	return version
+}
+
+func NewerConfigError(path string, version int) error {
+	return fmt.Errorf("%s is version %d, newer than this wtx supports (%d); run `wtx update`", path, version, CurrentConfigVersion)
+}
@@ -0,0 +1,143 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfigPath_UsesOverrideEnv(t *testing.T) {
+	override := t.TempDir()
+	t.Setenv(ConfigDirOverrideEnv, override)
+	t.Setenv("HOME", "")
+
+	path, err := ConfigPath()
+	if err != nil {
+		t.Fatalf("configPath with override: %v", err)
+	}
+	want := filepath.Join(override, "config.json")
+	if path != want {
+		t.Fatalf("expected %q, got %q", want, path)
+	}
+}
+
+func TestConfigPath_UsesHomeByDefault(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv(ConfigDirOverrideEnv, "")
+	t.Setenv("HOME", home)
+
+	path, err := ConfigPath()
+	if err != nil {
+		t.Fatalf("configPath default: %v", err)
+	}
+	want := filepath.Join(home, ".wtx", "config.json")
+	if path != want {
+		t.Fatalf("expected %q, got %q", want, path)
+	}
+}
+
+func TestMigrateConfigDataStampsVersion(t *testing.T) {
+	migrated, from, changed, err := MigrateConfigData([]byte(`{"agent_command": "claude", "custom": 1}`))
+	if err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if from != 0 || !changed {
+		t.Fatalf("expected migration from v0, got from=%d changed=%v", from, changed)
+	}
+	again, from, changed, err := MigrateConfigData(migrated)
+	if err != nil {
+		t.Fatalf("migrate again: %v", err)
+	}
+	if from != CurrentConfigVersion || changed || string(again) != string(migrated) {
+		t.Fatalf("expected migrated config to be stable, got from=%d changed=%v", from, changed)
+	}
+	if _, _, _, err := MigrateConfigData([]byte(`{"version": "x"}`)); err == nil {
+		t.Fatalf("expected invalid version error")
+	}
+}
+
+func TestMigrateConfigDataFoldsUpdateChecks(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{`{"version": 1, "update_checks": false}`, UpdateCheckOff},
+		{`{"version": 1, "update_checks": true}`, ""},
+		{`{"version": 1, "update_checks": false, "update_check": "weekly"}`, UpdateCheckWeekly},
+	}
+	for _, tc := range cases {
+		migrated, _, changed, err := MigrateConfigData([]byte(tc.in))
+		if err != nil {
+			t.Fatalf("migrate %s: %v", tc.in, err)
+		}
+		var values map[string]any
+		if err := json.Unmarshal(migrated, &values); err != nil {
+			t.Fatalf("decode %s: %v", migrated, err)
+		}
+		if _, ok := values["update_checks"]; ok || !changed {
+			t.Fatalf("expected update_checks to be removed from %s, got %s", tc.in, migrated)
+		}
+		got, _ := values["update_check"].(string)
+		if got != tc.want {
+			t.Fatalf("migrate %s: expected update_check %q, got %q", tc.in, tc.want, got)
+		}
+	}
+}
+
+func TestMigrateConfigDataFoldsColorSchemeIntoTheme(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{`{"version": 2, "color_scheme": "light", "theme": "dracula"}`, "light"},
+		{`{"version": 2, "theme": "catppuccin"}`, ""},
+		{`{"version": 2, "theme": "light"}`, "light"},
+	}
+	for _, tc := range cases {
+		migrated, _, _, err := MigrateConfigData([]byte(tc.in))
+		if err != nil {
+			t.Fatalf("migrate %s: %v", tc.in, err)
+		}
+		var values map[string]any
+		if err := json.Unmarshal(migrated, &values); err != nil {
+			t.Fatalf("decode %s: %v", migrated, err)
+		}
+		if _, ok := values["color_scheme"]; ok {
+			t.Fatalf("expected color_scheme to be removed from %s, got %s", tc.in, migrated)
+		}
+		if got, _ := values["theme"].(string); got != tc.want {
+			t.Fatalf("migrate %s: expected theme %q, got %q", tc.in, tc.want, got)
+		}
+	}
+}
+
+func TestSaveConfigRefusesNewerVersion(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(ConfigDirOverrideEnv, dir)
+	path := filepath.Join(dir, "config.json")
+	original := []byte(`{"version": 99, "agent_command": "claude", "future_key": true}`)
+	if err := os.WriteFile(path, original, 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	cfg.AgentCommand = "codex"
+	if err := SaveConfig(cfg); err == nil {
+		t.Fatalf("expected saving over a newer config to fail")
+	}
+	if err := SaveConfig(Config{AgentCommand: "codex"}); err == nil {
+		t.Fatalf("expected saving over a newer config file to fail")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	if !bytes.Equal(data, original) {
+		t.Fatalf("expected the newer config to be left untouched, got %s", data)
+	}
+}
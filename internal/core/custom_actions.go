@@ -0,0 +1,52 @@
+package core
+
+import "strings"
+
+// CustomAction is a custom_actions entry: an extra popup action that runs
+// Command in the worktree. {{path}} and {{branch}} in Command expand to the
+// shell-quoted worktree path and branch.
+type CustomAction struct {
+	Alias   string `json:"alias"`
+	Label   string `json:"label,omitempty"`
+	Key     string `json:"key,omitempty"`
+	Command string `json:"command"`
+}
+
+func normalizeCustomActions(actions []CustomAction) []CustomAction {
+	out := make([]CustomAction, 0, len(actions))
+	for _, a := range actions {
+		a.Alias = strings.ToLower(strings.TrimSpace(a.Alias))
+		a.Label = strings.TrimSpace(a.Label)
+		a.Key = strings.ToLower(strings.TrimSpace(a.Key))
+		a.Command = strings.TrimSpace(a.Command)
+		if a.Alias == "" || strings.ContainsAny(a.Alias, " \t") || a.Command == "" {
+			continue
+		}
+		if a.Label == "" {
+			a.Label = a.Command
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+// mergeCustomActions appends the repo's actions to the global ones; a repo
+// action replaces a global one with the same alias.
+func mergeCustomActions(global []CustomAction, repo []CustomAction) []CustomAction {
+	merged := make([]CustomAction, 0, len(global)+len(repo))
+	for _, a := range global {
+		if FindCustomAction(repo, a.Alias) == nil {
+			merged = append(merged, a)
+		}
+	}
+	return append(merged, repo...)
+}
+
+func FindCustomAction(actions []CustomAction, alias string) *CustomAction {
+	for i := range actions {
+		if strings.EqualFold(actions[i].Alias, alias) {
+			return &actions[i]
+		}
+	}
+	return nil
+}
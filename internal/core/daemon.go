@@ -0,0 +1,279 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	daemonSocketName       = "daemon.sock"
+	DaemonDialTimeout      = 200 * time.Millisecond
+	daemonStatusTimeout    = 5 * time.Second
+	daemonPRDataTimeout    = 45 * time.Second
+	daemonConnReadDeadline = 5 * time.Second
+	// daemonOrchestratorIdle drops the managers kept for a client once it
+	// has not asked for that long; owner IDs are per process, so every wtx
+	// run would otherwise add an entry for good.
+	daemonOrchestratorIdle = 10 * time.Minute
+
+	// The daemon only answers reads. Locks are tied to the PID of the
+	// process holding them, and actions run in the client's terminal, so
+	// both still happen in every client.
+	daemonOpPing   = "ping"
+	daemonOpStatus = "status"
+	daemonOpPRData = "pr_data"
+)
+
+// errDaemonVersionMismatch means the daemon answering runs a different wtx
+// build than the client, for example one started before an upgrade.
+var errDaemonVersionMismatch = errors.New("wtx daemon version differs")
+
+// daemonRequest is one JSON line sent by a client. Each connection carries a
+// single request and response.
+type daemonRequest struct {
+	Op       string   `json:"op"`
+	Version  string   `json:"version,omitempty"`
+	CWD      string   `json:"cwd,omitempty"`
+	OwnerID  string   `json:"owner_id,omitempty"`
+	RepoRoot string   `json:"repo_root,omitempty"`
+	Branches []string `json:"branches,omitempty"`
+	Force    bool     `json:"force,omitempty"`
+}
+
+type daemonResponse struct {
+	Version   string            `json:"version,omitempty"`
+	Error     string            `json:"error,omitempty"`
+	Status    *WorktreeStatus   `json:"status,omitempty"`
+	StatusErr string            `json:"status_err,omitempty"`
+	PRData    map[string]PRData `json:"pr_data,omitempty"`
+}
+
+func daemonSocketPath() (string, error) {
+	home, err := WtxHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, daemonSocketName), nil
+}
+
+// daemonServer keeps managers warm between requests. Orchestrators are kept
+// per working directory and lock owner, since the lock state in a status
+// depends on who is asking.
+type daemonServer struct {
+	version       string
+	mu            sync.Mutex
+	ghMgr         *GHManager
+	orchestrators map[string]*daemonOrchestrator
+}
+
+type daemonOrchestrator struct {
+	orchestrator *WorktreeOrchestrator
+	usedAt       time.Time
+}
+
+func newDaemonServer() *daemonServer {
+	return &daemonServer{
+		version:       CurrentVersion(),
+		ghMgr:         NewGHManager(),
+		orchestrators: map[string]*daemonOrchestrator{},
+	}
+}
+
+func (s *daemonServer) orchestrator(cwd string, ownerID string) *WorktreeOrchestrator {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for key, entry := range s.orchestrators {
+		if now.Sub(entry.usedAt) > daemonOrchestratorIdle {
+			delete(s.orchestrators, key)
+		}
+	}
+	key := cwd + "\x00" + ownerID
+	if entry, ok := s.orchestrators[key]; ok {
+		entry.usedAt = now
+		return entry.orchestrator
+	}
+	lockMgr := NewLockManager()
+	lockMgr.ownerID = ownerID
+	o := NewWorktreeOrchestrator(NewWorktreeManager(cwd, lockMgr), lockMgr, s.ghMgr)
+	// The daemon does the work itself rather than asking itself for it.
+	o.Daemon = false
+	s.orchestrators[key] = &daemonOrchestrator{orchestrator: o, usedAt: now}
+	return o
+}
+
+func (s *daemonServer) handle(req daemonRequest) daemonResponse {
+	switch req.Op {
+	case daemonOpPing:
+		return daemonResponse{}
+	case daemonOpStatus:
+		if strings.TrimSpace(req.CWD) == "" {
+			return daemonResponse{Error: "cwd required"}
+		}
+		status := s.orchestrator(req.CWD, req.OwnerID).Status()
+		resp := daemonResponse{Status: &status}
+		if status.Err != nil {
+			resp.StatusErr = status.Err.Error()
+		}
+		return resp
+	case daemonOpPRData:
+		var data map[string]PRData
+		var err error
+		if req.Force {
+			data, err = s.ghMgr.PRDataByBranchForce(req.RepoRoot, req.Branches)
+		} else {
+			data, err = s.ghMgr.PRDataByBranch(req.RepoRoot, req.Branches)
+		}
+		resp := daemonResponse{PRData: data}
+		if err != nil {
+			resp.Error = err.Error()
+		}
+		return resp
+	default:
+		return daemonResponse{Error: fmt.Sprintf("unknown daemon op %q", req.Op)}
+	}
+}
+
+func (s *daemonServer) serveConn(conn net.Conn) {
+	defer conn.Close()
+	_ = conn.SetReadDeadline(time.Now().Add(daemonConnReadDeadline))
+	var req daemonRequest
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		return
+	}
+	resp := s.handle(req)
+	resp.Version = s.version
+	_ = json.NewEncoder(conn).Encode(resp)
+}
+
+func runDaemon(ctx context.Context) error {
+	path, err := daemonSocketPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if conn, err := net.DialTimeout("unix", path, DaemonDialTimeout); err == nil {
+		conn.Close()
+		return fmt.Errorf("wtx daemon is already running on %s", path)
+	}
+	// Nothing answered, so any socket file left behind is stale.
+	_ = os.Remove(path)
+	listener, err := ListenPrivateSocket(path)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(path)
+
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+	server := newDaemonServer()
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go server.serveConn(conn)
+	}
+}
+
+func RunDaemonCommand() error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return runDaemon(ctx)
+}
+
+// callDaemon sends req to a running daemon. It fails fast when no daemon is
+// listening, or when it runs another wtx version, so callers can fall back
+// to doing the work in process.
+func callDaemon(req daemonRequest, timeout time.Duration) (daemonResponse, error) {
+	req.Version = CurrentVersion()
+	path, err := daemonSocketPath()
+	if err != nil {
+		return daemonResponse{}, err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return daemonResponse{}, err
+	}
+	conn, err := net.DialTimeout("unix", path, DaemonDialTimeout)
+	if err != nil {
+		return daemonResponse{}, err
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return daemonResponse{}, err
+	}
+	var resp daemonResponse
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return daemonResponse{}, err
+	}
+	if resp.Version != req.Version {
+		return daemonResponse{}, fmt.Errorf("%w: daemon %q, client %q", errDaemonVersionMismatch, resp.Version, req.Version)
+	}
+	return resp, nil
+}
+
+func daemonStatus(cwd string) (WorktreeStatus, error) {
+	resp, err := callDaemon(daemonRequest{Op: daemonOpStatus, CWD: cwd, OwnerID: buildOwnerID()}, daemonStatusTimeout)
+	if err != nil {
+		return WorktreeStatus{}, err
+	}
+	if resp.Error != "" {
+		return WorktreeStatus{}, errors.New(resp.Error)
+	}
+	if resp.Status == nil {
+		return WorktreeStatus{}, errors.New("daemon returned no status")
+	}
+	status := *resp.Status
+	if resp.StatusErr != "" {
+		status.Err = errors.New(resp.StatusErr)
+	}
+	return status, nil
+}
+
+// LookupPRData returns PR data from a running daemon, doing the gh lookup in
+// process only when no daemon answers.
+func LookupPRData(gh *GHManager, repoRoot string, branches []string, force bool) (map[string]PRData, error) {
+	if data, ok, err := daemonPRData(repoRoot, branches, force); ok {
+		return data, err
+	}
+	if force {
+		return gh.PRDataByBranchForce(repoRoot, branches)
+	}
+	return gh.PRDataByBranch(repoRoot, branches)
+}
+
+// daemonPRData returns PR data from the daemon. The bool reports whether the
+// daemon answered at all; a gh error is returned alongside partial data just
+// like GHManager does.
+func daemonPRData(repoRoot string, branches []string, force bool) (map[string]PRData, bool, error) {
+	resp, err := callDaemon(daemonRequest{Op: daemonOpPRData, RepoRoot: repoRoot, Branches: branches, Force: force}, daemonPRDataTimeout)
+	if err != nil {
+		return nil, false, nil
+	}
+	data := resp.PRData
+	if data == nil {
+		data = map[string]PRData{}
+	}
+	if resp.Error != "" {
+		return data, true, errors.New(resp.Error)
+	}
+	return data, true, nil
+}
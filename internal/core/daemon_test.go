@@ -0,0 +1,121 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func startTestDaemon(t *testing.T) {
+	t.Helper()
+	// Unix socket paths are length limited, so keep HOME short.
+	home, err := os.MkdirTemp("", "wtx")
+	if err != nil {
+		t.Fatalf("mkdir home: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(home) })
+	t.Setenv("HOME", home)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- runDaemon(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("daemon exited with %v", err)
+		}
+		if path, _ := daemonSocketPath(); path != "" {
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Errorf("expected socket to be removed, got %v", err)
+			}
+		}
+	})
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, err := callDaemon(daemonRequest{Op: daemonOpPing}, time.Second); err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("daemon did not start")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestDaemonServesStatusAndPRData(t *testing.T) {
+	startTestDaemon(t)
+	repo := initGitReadTestRepo(t)
+
+	status, err := daemonStatus(repo)
+	if err != nil {
+		t.Fatalf("daemonStatus: %v", err)
+	}
+	if !status.InRepo || len(status.Worktrees) != 1 || status.Worktrees[0].Branch != "main" || !status.Worktrees[0].Available {
+		t.Fatalf("unexpected daemon status: %+v", status)
+	}
+
+	if err := WriteSharedPRCache(status.RepoRoot, "main", CachedBranchPRData{FetchedAt: time.Now(), Found: true, Data: PRData{Number: 3}}); err != nil {
+		t.Fatalf("writeSharedPRCache: %v", err)
+	}
+	data, ok, err := daemonPRData(status.RepoRoot, []string{"main"}, false)
+	if !ok || err != nil || data["main"].Number != 3 {
+		t.Fatalf("expected PR #3 from daemon, got %+v ok=%v err=%v", data, ok, err)
+	}
+
+	// Windows has no permission bits on socket files.
+	if path, _ := daemonSocketPath(); path != "" && runtime.GOOS != "windows" {
+		if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+			t.Fatalf("expected a private socket, got %v (%v)", info, err)
+		}
+	}
+	if err := runDaemon(context.Background()); err == nil {
+		t.Fatalf("expected a second daemon to refuse to start")
+	}
+}
+
+func TestDaemonClientFallsBackWhenNotRunning(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if _, ok, _ := daemonPRData("/repo", []string{"main"}, false); ok {
+		t.Fatalf("expected no daemon answer")
+	}
+	if _, err := daemonStatus(t.TempDir()); err == nil {
+		t.Fatalf("expected daemonStatus to fail without a daemon")
+	}
+}
+
+func TestDaemonClientFallsBackOnVersionMismatch(t *testing.T) {
+	startTestDaemon(t)
+	old := version
+	version = "v0.0.0-other"
+	t.Cleanup(func() { version = old })
+
+	_, err := callDaemon(daemonRequest{Op: daemonOpPing}, time.Second)
+	if !errors.Is(err, errDaemonVersionMismatch) {
+		t.Fatalf("expected a version mismatch, got %v", err)
+	}
+	if _, ok, _ := daemonPRData("/repo", []string{"main"}, false); ok {
+		t.Fatalf("expected PR data to fall back to in-process lookup")
+	}
+	if _, err := daemonStatus(t.TempDir()); err == nil {
+		t.Fatalf("expected daemonStatus to fail against another daemon version")
+	}
+}
+
+func TestDaemonEvictsIdleOrchestrators(t *testing.T) {
+	server := newDaemonServer()
+	first := server.orchestrator("/repo", "owner-1")
+	if first.Daemon {
+		t.Fatalf("expected the daemon's own orchestrators to work in process")
+	}
+	if server.orchestrator("/repo", "owner-1") != first {
+		t.Fatalf("expected the orchestrator to be reused")
+	}
+	server.orchestrators["/repo\x00owner-1"].usedAt = time.Now().Add(-2 * daemonOrchestratorIdle)
+	server.orchestrator("/repo", "owner-2")
+	if _, ok := server.orchestrators["/repo\x00owner-1"]; ok || len(server.orchestrators) != 1 {
+		t.Fatalf("expected the idle orchestrator to be evicted, got %d entries", len(server.orchestrators))
+	}
+}
@@ -0,0 +1,102 @@
+package core
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const debugEnv = "WTX_DEBUG"
+
+// debugLogMaxBytes is the size at which wtx.log is rotated to wtx.log.1.
+const debugLogMaxBytes = 5 << 20
+
+// debugLogger is nil unless --verbose or WTX_DEBUG is set.
+var (
+	debugLogger  *slog.Logger
+	debugLogFile *os.File
+)
+
+func DebugEnvEnabled() bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(debugEnv))) {
+	case "", "0", "false", "off", "no":
+		return false
+	default:
+		return true
+	}
+}
+
+func DebugLogPath() (string, error) {
+	home, err := WtxHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "logs", "wtx.log"), nil
+}
+
+// EnableDebugLog starts writing JSON entries to ~/.wtx/logs/wtx.log. It sets
+// WTX_DEBUG so the wtx processes this one starts log too.
+func EnableDebugLog() error {
+	path, err := DebugLogPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() > debugLogMaxBytes {
+		_ = os.Rename(path, path+".1")
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	debugLogFile = f
+	debugLogger = slog.New(slog.NewJSONHandler(f, &slog.HandlerOptions{Level: slog.LevelDebug})).With("pid", os.Getpid())
+	_ = os.Setenv(debugEnv, "1")
+	DebugLog("start", "args", os.Args, "version", CurrentVersion())
+	MaybePruneLogs()
+	return nil
+}
+
+func DebugLog(msg string, attrs ...any) {
+	if debugLogger == nil {
+		return
+	}
+	debugLogger.Debug(msg, attrs...)
+}
+
+// logCommand records one external command run by runExternalCommand.
+func logCommand(spec CommandSpec, elapsed time.Duration, err error) {
+	if debugLogger == nil {
+		return
+	}
+	attrs := []any{
+		"cmd", spec.Name,
+		"args", spec.Args,
+		"dir", spec.Dir,
+		"duration_ms", elapsed.Milliseconds(),
+		"exit", commandExitCode(err),
+	}
+	if err != nil {
+		attrs = append(attrs, "err", err.Error())
+	}
+	DebugLog("command", attrs...)
+}
+
+// commandExitCode is the process exit status, or -1 when it did not run to
+// completion (not found, killed on timeout).
+func commandExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
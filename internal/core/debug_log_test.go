@@ -0,0 +1,64 @@
+package core
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestDebugLogRecordsCommands(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(debugEnv, "")
+	t.Cleanup(func() {
+		debugLogger = nil
+		_ = debugLogFile.Close()
+	})
+	if err := EnableDebugLog(); err != nil {
+		t.Fatalf("enableDebugLog: %v", err)
+	}
+	if !DebugEnvEnabled() {
+		t.Fatalf("expected %s to be exported for child processes", debugEnv)
+	}
+
+	dir := t.TempDir()
+	if _, err := RunExternalCommand(context.Background(), CommandSpec{Dir: dir, Name: "git", Args: []string{"--version"}}); err != nil {
+		t.Fatalf("git --version: %v", err)
+	}
+	if _, err := RunExternalCommand(context.Background(), CommandSpec{Dir: dir, Name: "git", Args: []string{"rev-parse", "HEAD"}}); err == nil {
+		t.Fatalf("expected rev-parse outside a repo to fail")
+	}
+
+	path, _ := DebugLogPath()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open log: %v", err)
+	}
+	defer f.Close()
+	var commands []map[string]any
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("log line is not JSON: %q", scanner.Text())
+		}
+		if entry["msg"] == "command" {
+			commands = append(commands, entry)
+		}
+	}
+	if len(commands) != 2 {
+		t.Fatalf("expected 2 command entries, got %v", commands)
+	}
+	if commands[0]["cmd"] != "git" || commands[0]["exit"] != float64(0) || commands[0]["dir"] != dir {
+		t.Fatalf("unexpected success entry %v", commands[0])
+	}
+	if _, ok := commands[0]["duration_ms"]; !ok {
+		t.Fatalf("expected a duration in %v", commands[0])
+	}
+	if commands[1]["exit"] == float64(0) || !strings.Contains(commands[1]["err"].(string), "exit status") {
+		t.Fatalf("expected a failed exit status in %v", commands[1])
+	}
+}
@@ -0,0 +1,151 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+)
+
+// activeTimings collects stage and command durations while `wtx debug
+// timings` runs. It is nil otherwise, so instrumented code costs one load.
+var activeTimings atomic.Pointer[timingRecorder]
+
+type timingRecorder struct {
+	mu       sync.Mutex
+	stages   timingTable
+	commands timingTable
+}
+
+// timingTable keeps entries in the order they were first seen.
+type timingTable struct {
+	entries map[string]*timingEntry
+	order   []string
+}
+
+type timingEntry struct {
+	calls int
+	total time.Duration
+	max   time.Duration
+}
+
+func (t *timingTable) entry(name string) *timingEntry {
+	if t.entries == nil {
+		t.entries = map[string]*timingEntry{}
+	}
+	e, ok := t.entries[name]
+	if !ok {
+		e = &timingEntry{}
+		t.entries[name] = e
+		t.order = append(t.order, name)
+	}
+	return e
+}
+
+func (e *timingEntry) add(elapsed time.Duration) {
+	e.calls++
+	e.total += elapsed
+	if elapsed > e.max {
+		e.max = elapsed
+	}
+}
+
+// timeStage starts timing a named stage; call the result when it ends.
+// Stages are listed in the order they start, and traced as spans when
+// tracing is on.
+func timeStage(name string) func() {
+	endSpan := StartSpan(name)
+	r := activeTimings.Load()
+	if r == nil {
+		return func() { endSpan(nil) }
+	}
+	r.mu.Lock()
+	e := r.stages.entry(name)
+	r.mu.Unlock()
+	start := time.Now()
+	return func() {
+		elapsed := time.Since(start)
+		r.mu.Lock()
+		e.add(elapsed)
+		r.mu.Unlock()
+		endSpan(nil)
+	}
+}
+
+func recordCommandTiming(spec CommandSpec, elapsed time.Duration) {
+	r := activeTimings.Load()
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.commands.entry(commandTimingLabel(spec)).add(elapsed)
+	r.mu.Unlock()
+}
+
+// commandTimingLabel groups commands by program and leading subcommands,
+// e.g. "gh pr view" or "git worktree list".
+func commandTimingLabel(spec CommandSpec) string {
+	parts := []string{commandLabel(CommandSpec{Name: spec.Name})}
+	for _, arg := range spec.Args {
+		if strings.HasPrefix(arg, "-") || len(parts) == 3 {
+			break
+		}
+		parts = append(parts, arg)
+	}
+	return strings.Join(parts, " ")
+}
+
+// RunDebugTimings does what the picker does on a forced refresh, without
+// caches or the daemon, and prints each stage and external command.
+func RunDebugTimings(w io.Writer) error {
+	recorder := &timingRecorder{}
+	activeTimings.Store(recorder)
+	defer activeTimings.Store(nil)
+
+	lockMgr := NewLockManager()
+	orchestrator := NewWorktreeOrchestrator(NewWorktreeManager("", lockMgr), lockMgr, NewGHManager())
+	orchestrator.Daemon = false
+	start := time.Now()
+	status := orchestrator.Status()
+	if status.Err != nil {
+		return status.Err
+	}
+	if !status.InRepo {
+		return ErrNotInGitRepository
+	}
+	_, prErr := orchestrator.PRDataForStatusWithError(status, true)
+
+	fmt.Fprintf(w, "%d worktrees in %s, total %s\n", len(status.Worktrees), status.RepoRoot, FormatTiming(time.Since(start)))
+	if prErr != nil {
+		fmt.Fprintf(w, "PR refresh failed: %v\n", prErr)
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	commands := append([]string(nil), recorder.commands.order...)
+	sort.SliceStable(commands, func(i, j int) bool {
+		return recorder.commands.entries[commands[i]].total > recorder.commands.entries[commands[j]].total
+	})
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	writeTimingTable(tw, "STAGE", recorder.stages.order, recorder.stages.entries)
+	writeTimingTable(tw, "COMMAND", commands, recorder.commands.entries)
+	return tw.Flush()
+}
+
+func writeTimingTable(w io.Writer, title string, names []string, entries map[string]*timingEntry) {
+	fmt.Fprintf(w, "\n%s\tCALLS\tTOTAL\tMAX\n", title)
+	for _, name := range names {
+		e := entries[name]
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", name, e.calls, FormatTiming(e.total), FormatTiming(e.max))
+	}
+}
+
+func FormatTiming(d time.Duration) string {
+	if d < time.Millisecond {
+		return d.Round(time.Microsecond).String()
+	}
+	return d.Round(time.Millisecond).String()
+}
@@ -0,0 +1,43 @@
+package core
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCommandTimingLabel(t *testing.T) {
+	cases := []struct {
+		spec CommandSpec
+		want string
+	}{
+		{CommandSpec{Name: "git", Args: []string{"worktree", "list", "--porcelain"}}, "git worktree list"},
+		{CommandSpec{Name: "/usr/bin/gh", Args: []string{"pr", "list", "--json", "number"}}, "gh pr list"},
+		{CommandSpec{Name: "git", Args: []string{"-C", "/repo", "status"}}, "git"},
+		{CommandSpec{Name: "gh", Args: []string{"api", "graphql", "-f", "query=x"}}, "gh api graphql"},
+	}
+	for _, tc := range cases {
+		if got := commandTimingLabel(tc.spec); got != tc.want {
+			t.Fatalf("commandTimingLabel(%v) = %q, want %q", tc.spec, got, tc.want)
+		}
+	}
+}
+
+func TestRunDebugTimings(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo := initRenameTestRepo(t)
+	t.Chdir(repo)
+
+	var out bytes.Buffer
+	if err := RunDebugTimings(&out); err != nil {
+		t.Fatalf("runDebugTimings: %v", err)
+	}
+	for _, want := range []string{"STAGE", "list worktrees", "lock checks", "COMMAND", "git rev-parse"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in output:\n%s", want, out.String())
+		}
+	}
+	if activeTimings.Load() != nil {
+		t.Fatalf("expected timings to be switched off afterwards")
+	}
+}
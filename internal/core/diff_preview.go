@@ -0,0 +1,53 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DiffPreview describes what a worktree holds: a --stat of uncommitted
+// changes and of commits since baseRef, or with full set the whole diff of
+// the working tree against the merge base with baseRef.
+func (m *WorktreeManager) DiffPreview(worktreePath string, baseRef string, full bool) (string, error) {
+	gitPath, err := RequireGitPath()
+	if err != nil {
+		return "", err
+	}
+	mergeBase := ""
+	if baseRef = strings.TrimSpace(baseRef); baseRef != "" && baseRef != "HEAD" {
+		mergeBase, _ = GitOutputInDir(worktreePath, gitPath, "merge-base", baseRef, "HEAD")
+	}
+	if full {
+		if mergeBase == "" {
+			return "", fmt.Errorf("cannot resolve base ref %q", baseRef)
+		}
+		out, err := CommandOutputInDir(worktreePath, gitPath, "diff", "--no-color", mergeBase)
+		return string(out), err
+	}
+	var b strings.Builder
+	uncommitted, err := GitOutputInDir(worktreePath, gitPath, "diff", "--stat", "--no-color", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	writeDiffSection(&b, "Uncommitted changes", uncommitted)
+	if mergeBase != "" {
+		committed, err := GitOutputInDir(worktreePath, gitPath, "diff", "--stat", "--no-color", mergeBase, "HEAD")
+		if err != nil {
+			return "", err
+		}
+		writeDiffSection(&b, "Committed since "+baseRef, committed)
+	}
+	return b.String(), nil
+}
+
+func writeDiffSection(b *strings.Builder, title string, body string) {
+	if b.Len() > 0 {
+		b.WriteString("\n")
+	}
+	b.WriteString(title + ":\n")
+	if strings.TrimSpace(body) == "" {
+		b.WriteString("  (none)\n")
+		return
+	}
+	b.WriteString(body + "\n")
+}
@@ -0,0 +1,47 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiffPreviewShowsStatAndFullDiff(t *testing.T) {
+	repo := initRenameTestRepo(t)
+	runGitInRepo(t, repo, "branch", "base")
+	if err := os.WriteFile(filepath.Join(repo, "feature.txt"), []byte("hello\n"), 0o644); err != nil {
+		t.Fatalf("write feature.txt: %v", err)
+	}
+	runGitInRepo(t, repo, "add", "feature.txt")
+	runGitInRepo(t, repo, "commit", "-m", "add feature")
+	if err := os.WriteFile(filepath.Join(repo, "README.md"), []byte("seed\nedited\n"), 0o644); err != nil {
+		t.Fatalf("write README: %v", err)
+	}
+
+	mgr := NewWorktreeManager(repo, NewLockManager())
+	stat, err := mgr.DiffPreview(repo, "base", false)
+	if err != nil {
+		t.Fatalf("DiffPreview stat: %v", err)
+	}
+	uncommitted, committed, ok := strings.Cut(stat, "Committed since base:")
+	if !ok {
+		t.Fatalf("expected a committed section, got:\n%s", stat)
+	}
+	if !strings.Contains(uncommitted, "README.md") || strings.Contains(uncommitted, "feature.txt") {
+		t.Fatalf("expected only README.md as uncommitted, got:\n%s", uncommitted)
+	}
+	if !strings.Contains(committed, "feature.txt") {
+		t.Fatalf("expected feature.txt committed since base, got:\n%s", committed)
+	}
+
+	full, err := mgr.DiffPreview(repo, "base", true)
+	if err != nil {
+		t.Fatalf("DiffPreview full: %v", err)
+	}
+	for _, want := range []string{"+hello", "+edited"} {
+		if !strings.Contains(full, want) {
+			t.Fatalf("expected full diff to contain %q, got:\n%s", want, full)
+		}
+	}
+}
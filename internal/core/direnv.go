@@ -0,0 +1,65 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// direnvCommandTimeout bounds `direnv allow`, which only records a hash but
+// could otherwise hang worktree creation on a wedged direnv.
+const direnvCommandTimeout = 30 * time.Second
+
+// direnvEnabled reports whether the direnv setting is on for the worktree
+// and it has an .envrc direnv can load.
+func direnvEnabled(worktreePath string) bool {
+	if runtime.GOOS == "windows" {
+		return false
+	}
+	if _, err := os.Stat(filepath.Join(worktreePath, ".envrc")); err != nil {
+		return false
+	}
+	cfg, err := LoadConfigForDir(worktreePath)
+	if err != nil || !cfg.Direnv {
+		return false
+	}
+	_, err = exec.LookPath("direnv")
+	return err == nil
+}
+
+// allowWorktreeDirenv approves the new worktree's .envrc, which direnv
+// otherwise blocks per directory. Failures are warnings like LFS ones.
+func allowWorktreeDirenv(worktreePath string, progress io.Writer) {
+	if !direnvEnabled(worktreePath) {
+		return
+	}
+	if progress == nil {
+		progress = io.Discard
+	}
+	fmt.Fprintln(progress, "$ direnv allow")
+	_, err := RunExternalCommand(context.Background(), CommandSpec{
+		Dir:     worktreePath,
+		Name:    "direnv",
+		Args:    []string{"allow", worktreePath},
+		Timeout: direnvCommandTimeout,
+	})
+	if err != nil {
+		fmt.Fprintln(progress, "wtx warning: direnv allow failed:", err)
+	}
+}
+
+// DirenvAgentCommand runs the agent under `direnv exec` so it sees the
+// .envrc environment without a shell hook, with direnv's loading chatter
+// silenced so it does not land in the agent pane.
+func DirenvAgentCommand(worktreePath string, runCmd string) string {
+	if strings.TrimSpace(runCmd) == "" || !direnvEnabled(worktreePath) {
+		return runCmd
+	}
+	return "DIRENV_LOG_FORMAT= direnv exec " + ShellQuote(worktreePath) + " /bin/sh -c " + ShellQuote(runCmd)
+}
@@ -0,0 +1,69 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestDirenvAllowedOnCreateWhenEnabled(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("direnv is not used on Windows")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(ConfigDirOverrideEnv, "")
+	bin := filepath.Join(home, "bin")
+	if err := os.MkdirAll(bin, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	calls := filepath.Join(home, "direnv.log")
+	fake := "#!/bin/sh\necho \"$@\" >> " + ShellQuote(calls) + "\n"
+	if err := os.WriteFile(filepath.Join(bin, "direnv"), []byte(fake), 0o755); err != nil {
+		t.Fatalf("write fake direnv: %v", err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	repo := initRenameTestRepo(t)
+	if err := os.WriteFile(filepath.Join(repo, ".envrc"), []byte("export FOO=1\n"), 0o644); err != nil {
+		t.Fatalf("write .envrc: %v", err)
+	}
+	runGitInRepo(t, repo, "add", ".envrc")
+	runGitInRepo(t, repo, "commit", "-m", "envrc")
+
+	mgr := NewWorktreeManager(repo, NewLockManager())
+	off, err := mgr.CreateWorktree("off", "", nil)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := os.Stat(calls); !os.IsNotExist(err) {
+		t.Fatalf("expected direnv left alone until enabled, stat err=%v", err)
+	}
+	if got := DirenvAgentCommand(off.Path, "claude"); got != "claude" {
+		t.Fatalf("expected the agent command unchanged, got %q", got)
+	}
+	writeTrustedRepoConfig(t, repo, `{"direnv": true}`)
+	if direnvEnabled(off.Path) {
+		t.Fatal("expected direnv in the repo config to be ignored")
+	}
+
+	if err := os.MkdirAll(filepath.Join(home, ".wtx"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".wtx", "config.json"), []byte(`{"direnv": true}`), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	on, err := mgr.CreateWorktree("on", "", nil)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	data, err := os.ReadFile(calls)
+	if err != nil || strings.TrimSpace(string(data)) != "allow "+on.Path {
+		t.Fatalf("expected direnv allow for the new worktree, got %q (%v)", data, err)
+	}
+	want := "DIRENV_LOG_FORMAT= direnv exec " + ShellQuote(on.Path) + " /bin/sh -c " + ShellQuote("claude")
+	if got := DirenvAgentCommand(on.Path, "claude"); got != want {
+		t.Fatalf("direnvAgentCommand = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,36 @@
+package core
+
+import (
+	"os"
+	"strings"
+)
+
+func envFlagEnabled(name string) bool {
+	value := strings.ToLower(strings.TrimSpace(os.Getenv(name)))
+	switch value {
+	case "1", "true", "yes", "on":
+		return true
+	default:
+		return false
+	}
+}
+
+func TmuxIntegrationDisabled() bool {
+	return !TmuxSupported || envFlagEnabled("WTX_DISABLE_TMUX")
+}
+
+func TerminalEscapesDisabled() bool {
+	return envFlagEnabled("WTX_DISABLE_OSC")
+}
+
+func ITermIntegrationDisabled() bool {
+	return envFlagEnabled("WTX_DISABLE_ITERM")
+}
+
+func TestModeEnabled() bool {
+	return envFlagEnabled("WTX_TEST_MODE")
+}
+
+func UpdateCheckDisabledByEnv() bool {
+	return envFlagEnabled("WTX_NO_UPDATE_CHECK")
+}
@@ -0,0 +1,71 @@
+package core
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// GHAuthError is a gh failure that logging in, or granting the token more
+// scopes, fixes. Without it PR data would just stay empty.
+type GHAuthError struct {
+	// Scopes lists what the token is missing; empty means not logged in.
+	Scopes []string
+	output string
+}
+
+func (e *GHAuthError) Error() string {
+	if len(e.Scopes) > 0 {
+		return "gh token is missing scopes " + strings.Join(e.Scopes, ", ") + ": " + e.output
+	}
+	return "gh not authenticated: " + e.output
+}
+
+// FixArgs is the gh command that resolves the problem.
+func (e *GHAuthError) FixArgs() []string {
+	if len(e.Scopes) > 0 {
+		return []string{"auth", "refresh", "-s", strings.Join(e.Scopes, ",")}
+	}
+	return []string{"auth", "login"}
+}
+
+var ghMissingScopesRe = regexp.MustCompile(`(?i)scopes?[^\[]*\[([^\]]+)\]`)
+
+// GHAuthErrorFromOutput recognises gh's messages for a missing login, a
+// revoked token and a token without the scopes a query needs.
+func GHAuthErrorFromOutput(output string) *GHAuthError {
+	output = strings.TrimSpace(output)
+	lower := strings.ToLower(output)
+	switch {
+	case strings.Contains(lower, "missing required scope"),
+		strings.Contains(lower, "insufficient_scopes"),
+		strings.Contains(lower, "not been granted the required scopes"):
+		authErr := &GHAuthError{output: output}
+		if m := ghMissingScopesRe.FindStringSubmatch(output); m != nil {
+			for _, scope := range strings.FieldsFunc(m[1], func(r rune) bool { return r == ',' || r == ' ' }) {
+				authErr.Scopes = append(authErr.Scopes, strings.Trim(scope, `'"`))
+			}
+		}
+		if len(authErr.Scopes) == 0 {
+			// Without a list, read:org is what gh pr commands usually lack.
+			authErr.Scopes = []string{"read:org"}
+		}
+		return authErr
+	case strings.Contains(lower, "gh auth login"),
+		strings.Contains(lower, "not logged into"),
+		strings.Contains(lower, "authentication required"),
+		strings.Contains(lower, "requires authentication"),
+		strings.Contains(lower, "bad credentials"),
+		strings.Contains(lower, "http 401"):
+		return &GHAuthError{output: output}
+	}
+	return nil
+}
+
+func AsGHAuthError(err error) (*GHAuthError, bool) {
+	var authErr *GHAuthError
+	if errors.As(err, &authErr) {
+		return authErr, true
+	}
+	return nil, false
+}
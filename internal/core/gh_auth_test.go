@@ -0,0 +1,30 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGHAuthErrorFromOutput(t *testing.T) {
+	cases := []struct {
+		output string
+		want   string
+	}{
+		{"To get started with GitHub CLI, please run:  gh auth login\nAlternatively, populate the GH_TOKEN environment variable with a GitHub API authentication token.", "auth login"},
+		{"HTTP 401: Bad credentials (https://api.github.com/graphql)\nTry authenticating with:  gh auth login", "auth login"},
+		{"error: your authentication token is missing required scopes [read:org]\nTo request it, run:  gh auth refresh -s read:org", "auth refresh -s read:org"},
+		{"GraphQL: Your token has not been granted the required scopes to execute this query. The 'login' field requires one of the following scopes: ['read:org', 'repo'] (INSUFFICIENT_SCOPES)", "auth refresh -s read:org,repo"},
+		{"no pull requests found for branch \"feature\"", ""},
+		{"GraphQL: Could not resolve to a Repository with the name 'a/b'.", ""},
+	}
+	for _, tc := range cases {
+		authErr := GHAuthErrorFromOutput(tc.output)
+		got := ""
+		if authErr != nil {
+			got = strings.Join(authErr.FixArgs(), " ")
+		}
+		if got != tc.want {
+			t.Fatalf("ghAuthErrorFromOutput(%q) fix = %q, want %q", tc.output, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SharedPRCacheEntry is the on-disk form of a cached gh lookup. The TUI and
+// the tmux-status subcommands run as separate processes, so they share PR
+// data through these files instead of each querying gh.
+type SharedPRCacheEntry struct {
+	FetchedAtUnix int64  `json:"fetched_at_unix"`
+	Found         bool   `json:"found"`
+	Data          PRData `json:"data"`
+}
+
+func SharedPRCachePath(repoRoot string, branch string) (string, error) {
+	repoRoot = strings.TrimSpace(repoRoot)
+	branch = strings.TrimSpace(branch)
+	if repoRoot == "" || branch == "" {
+		return "", errors.New("repo root and branch required")
+	}
+	home, err := WtxHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "cache", "pr", HashString(repoRoot+"|"+branch)+".json"), nil
+}
+
+// ReadSharedPRCache returns the cached lookup for branch if it is younger
+// than maxAge.
+func ReadSharedPRCache(repoRoot string, branch string, maxAge time.Duration) (CachedBranchPRData, bool) {
+	path, err := SharedPRCachePath(repoRoot, branch)
+	if err != nil {
+		return CachedBranchPRData{}, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return CachedBranchPRData{}, false
+	}
+	var entry SharedPRCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.FetchedAtUnix <= 0 {
+		return CachedBranchPRData{}, false
+	}
+	fetchedAt := time.Unix(entry.FetchedAtUnix, 0)
+	if time.Since(fetchedAt) > maxAge {
+		return CachedBranchPRData{}, false
+	}
+	return CachedBranchPRData{FetchedAt: fetchedAt, Found: entry.Found, Data: entry.Data}, true
+}
+
+func WriteSharedPRCache(repoRoot string, branch string, cached CachedBranchPRData) error {
+	path, err := SharedPRCachePath(repoRoot, branch)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	payload, err := json.Marshal(SharedPRCacheEntry{
+		FetchedAtUnix: cached.FetchedAt.Unix(),
+		Found:         cached.Found,
+		Data:          cached.Data,
+	})
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(payload); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
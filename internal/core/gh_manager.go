@@ -1,4 +1,4 @@
-package cmd
+package core
 
 import (
 	"context"
@@ -54,18 +54,19 @@ type PRData struct {
 	CommentsRequired    bool
 	CommentsKnown       bool
 	BaseStatus          string
+	MergedAtUnix        int64
 }
 
 type GHManager struct {
 	mu          sync.Mutex
-	branchCache map[string]map[string]cachedBranchPRData
-	ttl         time.Duration
+	branchCache map[string]map[string]CachedBranchPRData
+	Ttl         time.Duration
 }
 
-type cachedBranchPRData struct {
-	fetchedAt time.Time
-	found     bool
-	data      PRData
+type CachedBranchPRData struct {
+	FetchedAt time.Time
+	Found     bool
+	Data      PRData
 }
 
 type ghPR struct {
@@ -140,11 +141,29 @@ type requiredChecksInfo struct {
 	commentsKnown    bool
 }
 
+const (
+	DefaultPRRefreshInterval = 20 * time.Second
+	minPRRefreshInterval     = 5 * time.Second
+)
+
 func NewGHManager() *GHManager {
 	return &GHManager{
-		branchCache: make(map[string]map[string]cachedBranchPRData),
-		ttl:         20 * time.Second,
+		branchCache: make(map[string]map[string]CachedBranchPRData),
+		Ttl:         DefaultPRRefreshInterval,
+	}
+}
+
+// PRRefreshInterval is how long PR, CI and review data is reused before the
+// TUI asks gh again.
+func PRRefreshInterval(cfg Config) time.Duration {
+	if cfg.PRRefreshSeconds <= 0 {
+		return DefaultPRRefreshInterval
+	}
+	interval := time.Duration(cfg.PRRefreshSeconds) * time.Second
+	if interval < minPRRefreshInterval {
+		return minPRRefreshInterval
 	}
+	return interval
 }
 
 func (m *GHManager) PRDataByBranch(repoRoot string, branches []string) (map[string]PRData, error) {
@@ -180,9 +199,9 @@ func (m *GHManager) prDataByBranch(repoRoot string, branches []string, force boo
 	repoCache := m.branchCache[repoRoot]
 	for _, b := range needed {
 		entry, ok := repoCache[b]
-		if !force && ok && now.Sub(entry.fetchedAt) < m.ttl {
-			if entry.found {
-				out[b] = entry.data
+		if !force && ok && now.Sub(entry.FetchedAt) < m.Ttl {
+			if entry.Found {
+				out[b] = entry.Data
 			}
 			continue
 		}
@@ -190,6 +209,28 @@ func (m *GHManager) prDataByBranch(repoRoot string, branches []string, force boo
 	}
 	m.mu.Unlock()
 
+	// Another wtx process may have fetched these recently.
+	if !force {
+		remaining := toFetch[:0]
+		for _, b := range toFetch {
+			entry, ok := ReadSharedPRCache(repoRoot, b, m.Ttl)
+			if !ok {
+				remaining = append(remaining, b)
+				continue
+			}
+			m.mu.Lock()
+			if _, ok := m.branchCache[repoRoot]; !ok {
+				m.branchCache[repoRoot] = make(map[string]CachedBranchPRData)
+			}
+			m.branchCache[repoRoot][b] = entry
+			m.mu.Unlock()
+			if entry.Found {
+				out[b] = entry.Data
+			}
+		}
+		toFetch = remaining
+	}
+
 	var fetchErr error
 	if len(toFetch) > 0 {
 		fetched, err := m.fetchPRDataForBranches(repoRoot, toFetch)
@@ -198,20 +239,29 @@ func (m *GHManager) prDataByBranch(repoRoot string, branches []string, force boo
 		}
 		m.mu.Lock()
 		if _, ok := m.branchCache[repoRoot]; !ok {
-			m.branchCache[repoRoot] = make(map[string]cachedBranchPRData)
+			m.branchCache[repoRoot] = make(map[string]CachedBranchPRData)
 		}
+		fresh := make(map[string]CachedBranchPRData, len(toFetch))
 		for _, b := range toFetch {
 			data, found := fetched[b]
-			m.branchCache[repoRoot][b] = cachedBranchPRData{
-				fetchedAt: time.Now(),
-				found:     found,
-				data:      data,
+			entry := CachedBranchPRData{
+				FetchedAt: time.Now(),
+				Found:     found,
+				Data:      data,
 			}
+			m.branchCache[repoRoot][b] = entry
+			fresh[b] = entry
 			if found {
 				out[b] = data
 			}
 		}
 		m.mu.Unlock()
+		// A failed fetch may have dropped branches, so only share clean results.
+		if err == nil {
+			for b, entry := range fresh {
+				_ = WriteSharedPRCache(repoRoot, b, entry)
+			}
+		}
 	}
 
 	m.mu.Lock()
@@ -221,15 +271,16 @@ func (m *GHManager) prDataByBranch(repoRoot string, branches []string, force boo
 		if _, ok := out[b]; ok {
 			continue
 		}
-		if entry, ok := repoCache[b]; ok && entry.found {
-			out[b] = entry.data
+		if entry, ok := repoCache[b]; ok && entry.Found {
+			out[b] = entry.Data
 		}
 	}
 	return out, fetchErr
 }
 
 func (m *GHManager) fetchPRDataForBranches(repoRoot string, branches []string) (map[string]PRData, error) {
-	if len(branches) == 0 {
+	// A purely local repo has no PRs; asking gh only produces a remote error.
+	if len(branches) == 0 || !RepoHasRemote(repoRoot) {
 		return map[string]PRData{}, nil
 	}
 	if _, err := exec.LookPath("gh"); err != nil {
@@ -278,7 +329,8 @@ func (m *GHManager) fetchPRDataForBranches(repoRoot string, branches []string) (
 	out := make(map[string]PRData, len(branches))
 	var firstErr error
 	for res := range results {
-		if res.err != nil && firstErr == nil {
+		// An auth problem explains every other failure, so report it first.
+		if _, isAuth := AsGHAuthError(res.err); res.err != nil && (firstErr == nil || isAuth) {
 			firstErr = res.err
 		}
 		if res.found {
@@ -289,6 +341,7 @@ func (m *GHManager) fetchPRDataForBranches(repoRoot string, branches []string) (
 }
 
 func ghPRDataForBranch(ghPath string, repoRoot string, owner string, name string, branch string) (PRData, bool, error) {
+	defer timeStage("pr lookup per branch")()
 	pr, found, err := ghPRViewByBranch(ghPath, repoRoot, branch, fullPRListFields, ghPRHeadFullTimeout)
 	if err != nil {
 		pr, found, err = ghPRViewByBranch(ghPath, repoRoot, branch, fallbackPRListFields, ghPRHeadFallbackTimeout)
@@ -351,6 +404,9 @@ func ghPRDataForBranch(ghPath string, repoRoot string, owner string, name string
 		commentsRequired,
 	)
 	data.BaseStatus = baseStatus
+	if mergedAt, err := time.Parse(time.RFC3339, strings.TrimSpace(pr.MergedAt)); err == nil {
+		data.MergedAtUnix = mergedAt.Unix()
+	}
 	if strings.TrimSpace(data.Branch) == "" {
 		data.Branch = branch
 	}
@@ -360,16 +416,11 @@ func ghPRDataForBranch(ghPath string, repoRoot string, owner string, name string
 func ghPRViewByBranch(ghPath string, repoRoot string, branch string, fields string, timeout time.Duration) (ghPR, bool, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
-	cmd := exec.CommandContext(
-		ctx,
-		ghPath,
-		"pr",
-		"view",
-		branch,
-		"--json", fields,
-	)
-	cmd.Dir = repoRoot
-	out, err := cmd.CombinedOutput()
+	out, err := RunExternalCommand(ctx, CommandSpec{
+		Dir:  repoRoot,
+		Name: ghPath,
+		Args: []string{"pr", "view", branch, "--json", fields},
+	})
 	if err != nil {
 		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
 			return ghPR{}, false, fmt.Errorf("gh pr view timed out after %s", timeout.Round(time.Second))
@@ -381,6 +432,9 @@ func ghPRViewByBranch(ghPath string, repoRoot string, branch string, fields stri
 		if strings.Contains(strings.ToLower(msg), "not found") && strings.Contains(strings.ToLower(msg), "pull request") {
 			return ghPR{}, false, nil
 		}
+		if authErr := GHAuthErrorFromOutput(msg); authErr != nil {
+			return ghPR{}, false, authErr
+		}
 		if msg == "" {
 			return ghPR{}, false, err
 		}
@@ -429,11 +483,11 @@ func reviewProgressForPR(ghPath string, repoRoot string, owner string, name stri
 			}
 		}
 	}
-	requiredCount, requiredKnown = ensureRequiredAtLeastApproved(approvedCount, approvedKnown, requiredCount, requiredKnown)
+	requiredCount, requiredKnown = EnsureRequiredAtLeastApproved(approvedCount, approvedKnown, requiredCount, requiredKnown)
 	return approvedCount, requiredCount, approvedKnown || requiredKnown
 }
 
-func ensureRequiredAtLeastApproved(approvedCount int, approvedKnown bool, requiredCount int, requiredKnown bool) (int, bool) {
+func EnsureRequiredAtLeastApproved(approvedCount int, approvedKnown bool, requiredCount int, requiredKnown bool) (int, bool) {
 	if approvedKnown && approvedCount > requiredCount {
 		return approvedCount, true
 	}
@@ -444,9 +498,7 @@ func requiredChecksForBaseBranch(ghPath string, repoRoot string, owner string, n
 	endpoint := fmt.Sprintf("repos/%s/%s/branches/%s/protection", owner, name, url.PathEscape(baseRefName))
 	ctx, cancel := context.WithTimeout(context.Background(), ghProtectionTimeout)
 	defer cancel()
-	cmd := exec.CommandContext(ctx, ghPath, "api", endpoint)
-	cmd.Dir = repoRoot
-	out, err := cmd.CombinedOutput()
+	out, err := RunExternalCommand(ctx, CommandSpec{Dir: repoRoot, Name: ghPath, Args: []string{"api", endpoint}})
 	if err != nil {
 		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
 			return requiredChecksInfo{}, fmt.Errorf("gh api protection timed out after %s", ghProtectionTimeout.Round(time.Second))
@@ -496,9 +548,7 @@ func approvedReviewsCount(ghPath string, repoRoot string, owner string, name str
 	endpoint := fmt.Sprintf("repos/%s/%s/pulls/%d/reviews?per_page=100", owner, name, number)
 	ctx, cancel := context.WithTimeout(context.Background(), ghReviewCountTimeout)
 	defer cancel()
-	cmd := exec.CommandContext(ctx, ghPath, "api", endpoint)
-	cmd.Dir = repoRoot
-	out, err := cmd.CombinedOutput()
+	out, err := RunExternalCommand(ctx, CommandSpec{Dir: repoRoot, Name: ghPath, Args: []string{"api", endpoint}})
 	if err != nil {
 		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
 			return 0, fmt.Errorf("gh api reviews timed out after %s", ghReviewCountTimeout.Round(time.Second))
@@ -662,6 +712,7 @@ func reviewThreadCountsForPR(ghPath string, repoRoot string, owner string, name
 	if owner == "" || name == "" || number <= 0 {
 		return reviewThreadCounts{}, errors.New("repo/number required")
 	}
+	defer timeStage("graphql review threads")()
 	query := `query($owner:String!,$name:String!,$number:Int!,$after:String){repository(owner:$owner,name:$name){pullRequest(number:$number){reviewThreads(first:100,after:$after){totalCount pageInfo{hasNextPage endCursor} nodes{isResolved}}}}}`
 	ctx, cancel := context.WithTimeout(context.Background(), ghUnresolvedPRTimeout)
 	defer cancel()
@@ -674,9 +725,7 @@ func reviewThreadCountsForPR(ghPath string, repoRoot string, owner string, name
 		if after != "" {
 			args = append(args, "-F", "after="+after)
 		}
-		cmd := exec.CommandContext(ctx, ghPath, args...)
-		cmd.Dir = repoRoot
-		out, err := cmd.Output()
+		out, err := RunExternalCommand(ctx, CommandSpec{Dir: repoRoot, Name: ghPath, Args: args, StdoutOnly: true})
 		if err != nil {
 			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
 				return reviewThreadCounts{}, fmt.Errorf("gh api graphql timed out after %s", ghUnresolvedPRTimeout.Round(time.Second))
@@ -717,7 +766,7 @@ func reviewThreadCountsForPR(ghPath string, repoRoot string, owner string, name
 }
 
 func resolveGitHubRepo(repoRoot string) (string, string, error) {
-	remote, err := gitOutputInDir(repoRoot, "git", "remote", "get-url", "origin")
+	remote, err := GitOutputInDir(repoRoot, "git", "remote", "get-url", "origin")
 	if err != nil {
 		return "", "", err
 	}
@@ -1,9 +1,12 @@
-package cmd
+package core
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestEnsureRequiredAtLeastApproved_UsesActualApprovalCount(t *testing.T) {
-	required, known := ensureRequiredAtLeastApproved(2, true, 1, true)
+	required, known := EnsureRequiredAtLeastApproved(2, true, 1, true)
 	if required != 2 {
 		t.Fatalf("expected required=2, got %d", required)
 	}
@@ -13,7 +16,7 @@ func TestEnsureRequiredAtLeastApproved_UsesActualApprovalCount(t *testing.T) {
 }
 
 func TestEnsureRequiredAtLeastApproved_LeavesUnknownUnchanged(t *testing.T) {
-	required, known := ensureRequiredAtLeastApproved(0, false, 1, true)
+	required, known := EnsureRequiredAtLeastApproved(0, false, 1, true)
 	if required != 1 {
 		t.Fatalf("expected required=1, got %d", required)
 	}
@@ -62,3 +65,56 @@ func TestComputePRStatus_Priority(t *testing.T) {
 		})
 	}
 }
+
+func TestPRDataByBranch_UsesSharedDiskCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("PATH", "")
+	repo := "/tmp/wtx-shared-cache-repo"
+	entry := CachedBranchPRData{FetchedAt: time.Now(), Found: true, Data: PRData{Number: 7, Branch: "feature", Status: "open"}}
+	if err := WriteSharedPRCache(repo, "feature", entry); err != nil {
+		t.Fatalf("writeSharedPRCache: %v", err)
+	}
+
+	// gh is not on PATH, so any hit must come from the cache written above.
+	got, err := NewGHManager().PRDataByBranch(repo, []string{"feature"})
+	if err != nil {
+		t.Fatalf("PRDataByBranch: %v", err)
+	}
+	if got["feature"].Number != 7 {
+		t.Fatalf("expected cached PR #7, got %+v", got)
+	}
+
+	entry.FetchedAt = time.Now().Add(-time.Hour)
+	if err := WriteSharedPRCache(repo, "feature", entry); err != nil {
+		t.Fatalf("writeSharedPRCache: %v", err)
+	}
+	if _, ok := ReadSharedPRCache(repo, "feature", time.Minute); ok {
+		t.Fatalf("expected expired entry to be ignored")
+	}
+	if cached, ok := ReadSharedPRCache(repo, "feature", 2*time.Hour); !ok || cached.Data.Number != 7 {
+		t.Fatalf("expected stale entry within max age, got %+v", cached)
+	}
+}
+
+func TestPRDataForLocalOnlyRepoSkipsGH(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo := initRenameTestRepo(t)
+	// Without a remote gh must not be needed at all.
+	t.Setenv("PATH", "")
+	data, err := NewGHManager().fetchPRDataForBranches(repo, []string{"main"})
+	if err != nil || len(data) != 0 {
+		t.Fatalf("expected empty PR data without calling gh, got %v (%v)", data, err)
+	}
+}
+
+func TestPRRefreshIntervalFromConfig(t *testing.T) {
+	if got := PRRefreshInterval(Config{}); got != DefaultPRRefreshInterval {
+		t.Fatalf("expected default interval, got %s", got)
+	}
+	if got := PRRefreshInterval(Config{PRRefreshSeconds: 90}); got != 90*time.Second {
+		t.Fatalf("expected 90s, got %s", got)
+	}
+	if got := PRRefreshInterval(Config{PRRefreshSeconds: 1}); got != minPRRefreshInterval {
+		t.Fatalf("expected interval clamped to %s, got %s", minPRRefreshInterval, got)
+	}
+}
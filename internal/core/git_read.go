@@ -0,0 +1,211 @@
+package core
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func openGitRepo(repoRoot string) (*git.Repository, error) {
+	return git.PlainOpenWithOptions(repoRoot, &git.PlainOpenOptions{
+		DetectDotGit:          true,
+		EnableDotGitCommonDir: true,
+	})
+}
+
+func readGitRemotes(repoRoot string) ([]string, error) {
+	repo, err := openGitRepo(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+	remotes, err := repo.Remotes()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, len(remotes))
+	for _, remote := range remotes {
+		out = append(out, remote.Config().Name)
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+func readLocalBranchExists(repoRoot string, branch string) (bool, error) {
+	repo, err := openGitRepo(repoRoot)
+	if err != nil {
+		return false, err
+	}
+	_, err = repo.Reference(plumbing.NewBranchReferenceName(branch), false)
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// readCurrentBranch returns the checked out branch, or "" when HEAD is
+// detached.
+func readCurrentBranch(repoRoot string) (string, error) {
+	repo, err := openGitRepo(repoRoot)
+	if err != nil {
+		return "", err
+	}
+	head, err := repo.Reference(plumbing.HEAD, false)
+	if err != nil {
+		return "", err
+	}
+	if head.Type() != plumbing.SymbolicReference || !head.Target().IsBranch() {
+		return "", nil
+	}
+	return head.Target().Short(), nil
+}
+
+// readBranchesByCommitDate mirrors `git for-each-ref --sort=-committerdate
+// refs/heads/`. A limit <= 0 returns every branch.
+func readBranchesByCommitDate(repoRoot string, limit int) ([]string, error) {
+	repo, err := openGitRepo(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+	refs, err := repo.Branches()
+	if err != nil {
+		return nil, err
+	}
+	type branchDate struct {
+		Name string
+		Unix int64
+	}
+	branches := []branchDate{}
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		commit, err := repo.CommitObject(ref.Hash())
+		if err != nil {
+			return err
+		}
+		branches = append(branches, branchDate{Name: ref.Name().Short(), Unix: commit.Committer.When.Unix()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(branches, func(i, j int) bool {
+		if branches[i].Unix != branches[j].Unix {
+			return branches[i].Unix > branches[j].Unix
+		}
+		return branches[i].Name < branches[j].Name
+	})
+	if limit > 0 && len(branches) > limit {
+		branches = branches[:limit]
+	}
+	out := make([]string, 0, len(branches))
+	for _, b := range branches {
+		out = append(out, b.Name)
+	}
+	return out, nil
+}
+
+// readWorktrees lists the main and linked worktrees from the repository's
+// worktree metadata, matching `git worktree list`. go-git has no API for
+// linked worktrees, so the files are read directly.
+func readWorktrees(repoRoot string) ([]WorktreeInfo, error) {
+	_, commonDir, err := ResolveGitDirs(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+	if filepath.Base(commonDir) != ".git" {
+		return nil, errors.New("bare repositories are not supported")
+	}
+	mainPath := filepath.Dir(commonDir)
+	if resolved, err := filepath.EvalSymlinks(mainPath); err == nil {
+		mainPath = resolved
+	}
+	worktrees := []WorktreeInfo{{Path: mainPath, Branch: BranchFromHEADFile(filepath.Join(commonDir, "HEAD"))}}
+
+	adminDir := filepath.Join(commonDir, "worktrees")
+	entries, err := os.ReadDir(adminDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return worktrees, nil
+		}
+		return nil, err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dir := filepath.Join(adminDir, entry.Name())
+		data, err := os.ReadFile(filepath.Join(dir, "gitdir"))
+		if err != nil {
+			return nil, err
+		}
+		dotGit := strings.TrimSpace(string(data))
+		if !filepath.IsAbs(dotGit) {
+			dotGit = filepath.Join(dir, dotGit)
+		}
+		worktrees = append(worktrees, WorktreeInfo{
+			Path:   filepath.Dir(filepath.Clean(dotGit)),
+			Branch: BranchFromHEADFile(filepath.Join(dir, "HEAD")),
+		})
+	}
+	return worktrees, nil
+}
+
+// ResolveGitDirs returns the git dir of the worktree at repoRoot and the
+// common dir shared by all worktrees.
+func ResolveGitDirs(repoRoot string) (string, string, error) {
+	dotGit := filepath.Join(repoRoot, ".git")
+	info, err := os.Stat(dotGit)
+	if err != nil {
+		return "", "", err
+	}
+	if info.IsDir() {
+		return dotGit, dotGit, nil
+	}
+	data, err := os.ReadFile(dotGit)
+	if err != nil {
+		return "", "", err
+	}
+	line := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(line, "gitdir:") {
+		return "", "", errors.New("malformed .git file")
+	}
+	gitDir := strings.TrimSpace(strings.TrimPrefix(line, "gitdir:"))
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(repoRoot, gitDir)
+	}
+	commonDir := gitDir
+	if data, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		commonDir = strings.TrimSpace(string(data))
+		if !filepath.IsAbs(commonDir) {
+			commonDir = filepath.Join(gitDir, commonDir)
+		}
+	}
+	return filepath.Clean(gitDir), filepath.Clean(commonDir), nil
+}
+
+func BranchFromHEADFile(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "detached"
+	}
+	head := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(head, "ref:") {
+		return "detached"
+	}
+	return ShortBranch(strings.TrimSpace(strings.TrimPrefix(head, "ref:")))
+}
+
+func CurrentBranchInWorktree(worktreePath string) string {
+	branch, err := GitOutputInDir(worktreePath, "git", "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return ""
+	}
+	branch = strings.TrimSpace(branch)
+	if branch == "" || strings.EqualFold(branch, "HEAD") || strings.EqualFold(branch, "detached") {
+		return ""
+	}
+	return branch
+}
@@ -0,0 +1,154 @@
+package core
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func initGitReadTestRepo(t *testing.T) string {
+	t.Helper()
+	repo := initRenameTestRepo(t)
+	runGitInRepo(t, repo, "branch", "-M", "main")
+	t.Setenv("GIT_COMMITTER_DATE", "2024-01-02T00:00:00Z")
+	runGitInRepo(t, repo, "checkout", "-q", "-b", "older")
+	runGitInRepo(t, repo, "commit", "-q", "--allow-empty", "-m", "older")
+	t.Setenv("GIT_COMMITTER_DATE", "2024-01-03T00:00:00Z")
+	runGitInRepo(t, repo, "checkout", "-q", "-b", "newer")
+	runGitInRepo(t, repo, "commit", "-q", "--allow-empty", "-m", "newer")
+	runGitInRepo(t, repo, "checkout", "-q", "main")
+	runGitInRepo(t, repo, "remote", "add", "upstream", "https://example.com/upstream.git")
+	runGitInRepo(t, repo, "remote", "add", "origin", "https://example.com/origin.git")
+	return repo
+}
+
+func TestReadWorktreesMatchesGit(t *testing.T) {
+	repo := initGitReadTestRepo(t)
+	parent := t.TempDir()
+	runGitInRepo(t, repo, "worktree", "add", "-q", filepath.Join(parent, "wt-newer"), "newer")
+	runGitInRepo(t, repo, "worktree", "add", "-q", "--detach", filepath.Join(parent, "wt-detached"), "older")
+
+	got, err := readWorktrees(repo)
+	if err != nil {
+		t.Fatalf("readWorktrees: %v", err)
+	}
+	want, _, err := parseWorktrees(runGitOutput(t, repo, "worktree", "list", "--porcelain"))
+	if err != nil {
+		t.Fatalf("parseWorktrees: %v", err)
+	}
+	if !sameWorktreeSet(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if got[0].Branch != "main" {
+		t.Fatalf("expected main worktree first, got %v", got)
+	}
+
+	fromLinked, err := readWorktrees(filepath.Join(parent, "wt-newer"))
+	if err != nil {
+		t.Fatalf("readWorktrees from linked worktree: %v", err)
+	}
+	if !sameWorktreeSet(fromLinked, want) {
+		t.Fatalf("expected %v from linked worktree, got %v", want, fromLinked)
+	}
+}
+
+func TestWorktreesWithSpacesUTF8AndHash(t *testing.T) {
+	repo := initGitReadTestRepo(t)
+	path := filepath.Join(t.TempDir(), "wt  two #{x} ü")
+	runGitInRepo(t, repo, "worktree", "add", "-q", "-b", "feat/#12-ünï", path)
+
+	parsed, malformed, err := parseWorktrees(runGitOutput(t, repo, "worktree", "list", "--porcelain", "-z"))
+	if err != nil || len(malformed) != 0 {
+		t.Fatalf("parseWorktrees: %v, malformed %q", err, malformed)
+	}
+	read, err := readWorktrees(repo)
+	if err != nil {
+		t.Fatalf("readWorktrees: %v", err)
+	}
+	for _, worktrees := range [][]WorktreeInfo{parsed, read} {
+		if !sameWorktreeSet(worktrees[1:], []WorktreeInfo{{Path: path, Branch: "feat/#12-ünï"}}) {
+			t.Fatalf("expected path %q on feat/#12-ünï, got %v", path, worktrees)
+		}
+	}
+}
+
+func sameWorktreeSet(a []WorktreeInfo, b []WorktreeInfo) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := map[WorktreeInfo]int{}
+	for _, wt := range a {
+		path, _ := filepath.EvalSymlinks(wt.Path)
+		seen[WorktreeInfo{Path: path, Branch: wt.Branch}]++
+	}
+	for _, wt := range b {
+		path, _ := filepath.EvalSymlinks(wt.Path)
+		seen[WorktreeInfo{Path: path, Branch: wt.Branch}]--
+	}
+	for _, n := range seen {
+		if n != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestReadBranchesAndRemotesMatchGit(t *testing.T) {
+	repo := initGitReadTestRepo(t)
+
+	branches, err := readBranchesByCommitDate(repo, 0)
+	if err != nil {
+		t.Fatalf("readBranchesByCommitDate: %v", err)
+	}
+	want := strings.Fields(runGitOutput(t, repo, "for-each-ref", "--sort=-committerdate", "--format=%(refname:short)", "refs/heads/"))
+	if !reflect.DeepEqual(branches, want) {
+		t.Fatalf("expected %v, got %v", want, branches)
+	}
+	limited, err := readBranchesByCommitDate(repo, 1)
+	if err != nil || !reflect.DeepEqual(limited, want[:1]) {
+		t.Fatalf("expected %v with limit, got %v (%v)", want[:1], limited, err)
+	}
+
+	remotes, err := readGitRemotes(repo)
+	if err != nil {
+		t.Fatalf("readGitRemotes: %v", err)
+	}
+	if !reflect.DeepEqual(remotes, []string{"origin", "upstream"}) {
+		t.Fatalf("expected [origin upstream], got %v", remotes)
+	}
+
+	current, err := readCurrentBranch(repo)
+	if err != nil || current != "main" {
+		t.Fatalf("expected current branch main, got %q (%v)", current, err)
+	}
+	exists, err := readLocalBranchExists(repo, "older")
+	if err != nil || !exists {
+		t.Fatalf("expected branch older to exist, got %v (%v)", exists, err)
+	}
+	exists, err = readLocalBranchExists(repo, "missing")
+	if err != nil || exists {
+		t.Fatalf("expected branch missing to not exist, got %v (%v)", exists, err)
+	}
+}
+
+func TestReadOnlyQueriesWorkWithoutGitBinary(t *testing.T) {
+	repo := initGitReadTestRepo(t)
+	runGitInRepo(t, repo, "worktree", "add", "-q", filepath.Join(t.TempDir(), "wt"), "older")
+	t.Setenv("PATH", "")
+
+	worktrees, malformed, err := ListWorktrees(repo, "git")
+	if err != nil || len(malformed) != 0 || len(worktrees) != 2 {
+		t.Fatalf("expected 2 worktrees without git, got %v %v (%v)", worktrees, malformed, err)
+	}
+	remotes, err := ListGitRemotes(repo, "git")
+	if err != nil || len(remotes) != 2 {
+		t.Fatalf("expected 2 remotes without git, got %v (%v)", remotes, err)
+	}
+	if !LocalBranchExists(repo, "git", "newer") {
+		t.Fatalf("expected branch newer to exist without git")
+	}
+	if got := fallbackBaseBranchNoRemote(repo, "git"); got != "main" {
+		t.Fatalf("expected main, got %q", got)
+	}
+}
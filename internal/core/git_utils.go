@@ -0,0 +1,141 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+var ErrGitNotInstalled = errors.New("git not installed")
+
+var ErrNotInGitRepository = errors.New("not in a git repository")
+
+func GitPath() (string, error) {
+	return exec.LookPath("git")
+}
+
+func RequireGitPath() (string, error) {
+	path, err := GitPath()
+	if err != nil {
+		return "", ErrGitNotInstalled
+	}
+	return path, nil
+}
+
+func RepoRootForDir(dir string, gitBin string) (string, error) {
+	_ = gitBin
+	if dir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return "", ErrNotInGitRepository
+		}
+		dir = wd
+	}
+	current, err := filepath.Abs(dir)
+	if err != nil {
+		return "", ErrNotInGitRepository
+	}
+	for {
+		if isGitDirEntry(filepath.Join(current, ".git")) {
+			return current, nil
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			break
+		}
+		current = parent
+	}
+	return "", ErrNotInGitRepository
+}
+
+func RequireGitContext(dir string) (string, string, error) {
+	repoRoot, err := RepoRootForDir(dir, "git")
+	if err != nil {
+		return "", "", err
+	}
+	return "git", repoRoot, nil
+}
+
+// isGitDirEntry reports whether path is a usable .git: a directory with a
+// HEAD, or a gitdir: file for a linked worktree or submodule. Stray or
+// emptied .git entries in nested folders are skipped so the enclosing repo is
+// found instead.
+func isGitDirEntry(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	if info.IsDir() {
+		_, err := os.Stat(filepath.Join(path, "HEAD"))
+		return err == nil
+	}
+	data, err := os.ReadFile(path)
+	return err == nil && strings.HasPrefix(strings.TrimSpace(string(data)), "gitdir:")
+}
+
+// gitEnvKeys are git's per-repository environment overrides. Hooks set them;
+// wtx runs git across several worktrees, so once they have located the repo
+// they are dropped instead of leaking into git runs in other directories.
+var gitEnvKeys = []string{"GIT_DIR", "GIT_WORK_TREE", "GIT_COMMON_DIR", "GIT_INDEX_FILE", "GIT_PREFIX"}
+
+// PinRepo moves wtx into the repo it should act on: the --repo path when
+// given, otherwise the work tree GIT_DIR or GIT_WORK_TREE name. The current
+// directory is kept when it is already inside that repo.
+func PinRepo(repoFlag string) error {
+	target := strings.TrimSpace(repoFlag)
+	fromEnv := target == ""
+	if fromEnv {
+		target = gitEnvWorkTree()
+	}
+	for _, key := range gitEnvKeys {
+		_ = os.Unsetenv(key)
+	}
+	if target == "" {
+		return nil
+	}
+	targetRoot, err := RepoRootForDir(target, "git")
+	if err != nil {
+		if fromEnv {
+			return nil
+		}
+		return fmt.Errorf("--repo %s: %w", target, err)
+	}
+	if cwdRoot, err := RepoRootForDir("", "git"); err == nil && cwdRoot == targetRoot {
+		return nil
+	}
+	return os.Chdir(target)
+}
+
+// gitEnvWorkTree returns the work tree named by GIT_WORK_TREE or implied by
+// GIT_DIR, or "" when neither is set or GIT_DIR is a bare repo.
+func gitEnvWorkTree() string {
+	if workTree := strings.TrimSpace(os.Getenv("GIT_WORK_TREE")); workTree != "" {
+		if abs, err := filepath.Abs(workTree); err == nil {
+			return abs
+		}
+		return ""
+	}
+	gitDir := strings.TrimSpace(os.Getenv("GIT_DIR"))
+	if gitDir == "" {
+		return ""
+	}
+	gitDir, err := filepath.Abs(gitDir)
+	if err != nil {
+		return ""
+	}
+	if filepath.Base(gitDir) == ".git" {
+		return filepath.Dir(gitDir)
+	}
+	// A linked worktree's admin dir records where its .git file lives.
+	if data, err := os.ReadFile(filepath.Join(gitDir, "gitdir")); err == nil {
+		dotGit := strings.TrimSpace(string(data))
+		if !filepath.IsAbs(dotGit) {
+			dotGit = filepath.Join(gitDir, dotGit)
+		}
+		return filepath.Dir(filepath.Clean(dotGit))
+	}
+	return ""
+}
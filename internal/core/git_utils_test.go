@@ -0,0 +1,60 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRepoRootForDirSkipsStrayGitEntries(t *testing.T) {
+	repo := initRenameTestRepo(t)
+	nested := filepath.Join(repo, "vendor", "lib")
+	if err := os.MkdirAll(filepath.Join(nested, ".git"), 0o755); err != nil {
+		t.Fatalf("mkdir stray .git: %v", err)
+	}
+	if got, err := RepoRootForDir(nested, "git"); err != nil || got != repo {
+		t.Fatalf("expected %s past an empty .git, got %q (%v)", repo, got, err)
+	}
+}
+
+func TestPinRepoFollowsGitDirAndClearsGitEnv(t *testing.T) {
+	repo := initRenameTestRepo(t)
+	worktree := filepath.Join(t.TempDir(), "wt.1")
+	runGitInRepo(t, repo, "worktree", "add", "-q", "-b", "feature", worktree)
+	elsewhere := initRenameTestRepo(t)
+
+	// A post-checkout hook in the linked worktree runs with GIT_DIR set.
+	t.Chdir(elsewhere)
+	t.Setenv("GIT_DIR", filepath.Join(repo, ".git", "worktrees", "wt.1"))
+	t.Setenv("GIT_INDEX_FILE", "index")
+	if err := PinRepo(""); err != nil {
+		t.Fatalf("pinRepo: %v", err)
+	}
+	cwd, _ := os.Getwd()
+	if !sameRealPath(t, cwd, worktree) {
+		t.Fatalf("expected to move into %s, got %s", worktree, cwd)
+	}
+	for _, key := range []string{"GIT_DIR", "GIT_INDEX_FILE"} {
+		if _, ok := os.LookupEnv(key); ok {
+			t.Fatalf("expected %s to be cleared", key)
+		}
+	}
+
+	if err := PinRepo(repo); err != nil {
+		t.Fatalf("pinRepo --repo: %v", err)
+	}
+	cwd, _ = os.Getwd()
+	if !sameRealPath(t, cwd, repo) {
+		t.Fatalf("expected --repo to move into %s, got %s", repo, cwd)
+	}
+	if err := PinRepo(t.TempDir()); err == nil {
+		t.Fatalf("expected --repo outside a git repo to fail")
+	}
+}
+
+func sameRealPath(t *testing.T, a string, b string) bool {
+	t.Helper()
+	realA, errA := filepath.EvalSymlinks(a)
+	realB, errB := filepath.EvalSymlinks(b)
+	return errA == nil && errB == nil && realA == realB
+}
@@ -0,0 +1,46 @@
+package core
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func initRenameTestRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	runGitInRepo(t, dir, "init")
+	runGitInRepo(t, dir, "config", "user.name", "Test User")
+	runGitInRepo(t, dir, "config", "user.email", "test@example.com")
+
+	readmePath := filepath.Join(dir, "README.md")
+	if err := os.WriteFile(readmePath, []byte("seed\n"), 0o644); err != nil {
+		t.Fatalf("write README: %v", err)
+	}
+	runGitInRepo(t, dir, "add", "README.md")
+	runGitInRepo(t, dir, "commit", "-m", "seed")
+	return dir
+}
+
+func runGitInRepo(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, strings.TrimSpace(string(out)))
+	}
+}
+
+func runGitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, strings.TrimSpace(string(out)))
+	}
+	return string(out)
+}
@@ -0,0 +1,127 @@
+package core
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Hook events. Each can be set to a shell command under "hooks" in the global
+// config or .wtx.json; the repo's command wins.
+const (
+	HookPreCreate  = "pre_create"
+	HookPostCreate = "post_create"
+	HookPreDelete  = "pre_delete"
+	HookPostOpen   = "post_open"
+	HookAgentExit  = "agent_exit"
+	HookPRMerged   = "pr_merged"
+)
+
+// HookContext is what a hook learns about the event through WTX_* variables.
+type HookContext struct {
+	RepoRoot     string
+	WorktreePath string
+	Branch       string
+	BaseRef      string
+	PR           *PRData
+	// ExitCode is set for agent_exit.
+	ExitCode *int
+}
+
+func (c HookContext) env(event string) []string {
+	env := []string{
+		"WTX_HOOK=" + event,
+		"WTX_REPO_ROOT=" + c.RepoRoot,
+		"WTX_WORKTREE_PATH=" + c.WorktreePath,
+		"WTX_BRANCH=" + c.Branch,
+	}
+	if c.BaseRef != "" {
+		env = append(env, "WTX_BASE_REF="+c.BaseRef)
+	}
+	if c.PR != nil && c.PR.Number > 0 {
+		env = append(env,
+			"WTX_PR_NUMBER="+strconv.Itoa(c.PR.Number),
+			"WTX_PR_URL="+c.PR.URL,
+			"WTX_PR_STATUS="+c.PR.Status)
+	}
+	if c.ExitCode != nil {
+		env = append(env, "WTX_AGENT_EXIT_CODE="+strconv.Itoa(*c.ExitCode))
+	}
+	return env
+}
+
+// hookTimeout bounds a hook so a stuck one cannot hold up a create or delete
+// forever; setup hooks like "make setup" legitimately take minutes.
+const hookTimeout = 10 * time.Minute
+
+// RunHook runs the command configured for event, in the worktree when it
+// exists and the repo root otherwise. Callers of pre_ hooks abort on the
+// error, so a config that cannot be read fails rather than skipping them.
+func RunHook(event string, ctx HookContext) error {
+	dir := ctx.WorktreePath
+	if info, err := os.Stat(dir); dir == "" || err != nil || !info.IsDir() {
+		dir = ctx.RepoRoot
+	}
+	cfg, err := LoadConfigForDir(dir)
+	if err != nil {
+		return fmt.Errorf("%s hook: %w", event, err)
+	}
+	script := strings.TrimSpace(cfg.Hooks[event])
+	if script == "" {
+		return nil
+	}
+	out, err := RunExternalCommand(context.Background(), CommandSpec{
+		Dir:     dir,
+		Name:    event + " hook",
+		Script:  script,
+		Env:     ctx.env(event),
+		Timeout: hookTimeout,
+	})
+	DebugLog("hook", "event", event, "command", script, "dir", dir, "exit_code", commandExitCode(err))
+	if err != nil {
+		return fmt.Errorf("%s hook failed: %w", event, &CommandError{
+			Command: script,
+			Dir:     dir,
+			Output:  strings.TrimSpace(string(out)),
+			Err:     err,
+		})
+	}
+	return nil
+}
+
+func HookMarkerPath(event string, key string) (string, error) {
+	home, err := WtxHomeDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(home, "hooks", event, hex.EncodeToString(sum[:8])), nil
+}
+
+// ClaimHookMarker creates the marker, failing when it already exists. A
+// marker older than staleAfter, when that is set, is taken over.
+func ClaimHookMarker(path string, staleAfter time.Duration) bool {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return false
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if errors.Is(err, os.ErrExist) && staleAfter > 0 {
+		if info, statErr := os.Stat(path); statErr == nil && time.Since(info.ModTime()) > staleAfter && os.Remove(path) == nil {
+			f, err = os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		}
+	}
+	if err != nil {
+		if !errors.Is(err, os.ErrExist) {
+			DebugLog("hook marker failed", "path", path, "err", err.Error())
+		}
+		return false
+	}
+	return f.Close() == nil
+}
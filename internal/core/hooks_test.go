@@ -0,0 +1,20 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPreCreateHookFailsClosedOnBadConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo := initRenameTestRepo(t)
+	if err := os.WriteFile(filepath.Join(repo, RepoConfigFileName), []byte(`{`), 0o644); err != nil {
+		t.Fatalf("write repo config: %v", err)
+	}
+	mgr := NewWorktreeManager(repo, NewLockManager())
+	if _, err := mgr.CreateWorktree("feature", "HEAD", nil); err == nil || !strings.Contains(err.Error(), "pre_create hook") {
+		t.Fatalf("expected an unreadable config to stop the create, got %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	jsonStoreLockWait  = 2 * time.Second
+	jsonStoreLockStale = 10 * time.Second
+)
+
+// ReadJSONStore decodes the JSON file at path into v; a missing file leaves v
+// untouched.
+func ReadJSONStore(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// UpdateJSONStore reads the file at path into v, lets update change it and
+// writes it back when update reports a change. A lock file next to it keeps
+// concurrent wtx processes from losing each other's writes.
+func UpdateJSONStore(path string, v any, update func() (bool, error)) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	unlock, err := lockJSONStore(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if err := ReadJSONStore(path, v); err != nil {
+		return err
+	}
+	changed, err := update()
+	if err != nil || !changed {
+		return err
+	}
+	return WriteJSONStore(path, v)
+}
+
+// WriteJSONStore replaces the file with v encoded as JSON.
+func WriteJSONStore(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return WriteFileAtomic(path, append(data, '\n'))
+}
+
+// WriteFileAtomic writes data through a temp file and rename, so readers
+// never see a partial write.
+func WriteFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := replaceFile(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// lockJSONStore takes path.lock, waiting for another holder; a lock left by
+// a process that died mid-update is taken over once it is stale.
+func lockJSONStore(path string) (func(), error) {
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(jsonStoreLockWait)
+	for {
+		file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			file.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > jsonStoreLockStale {
+			os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%s is locked by another wtx process", filepath.Base(path))
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
@@ -0,0 +1,34 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentPinsAndNotesAreNotLost(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			path := fmt.Sprintf("/repo/wt.%d", i)
+			if err := SetWorktreePinned("/repo", path, true); err != nil {
+				t.Errorf("pin %s: %v", path, err)
+			}
+			if err := SetWorktreeNote("/repo", path, "note"); err != nil {
+				t.Errorf("note %s: %v", path, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	pins, err := ReadWorktreePins("/repo")
+	if err != nil || len(pins) != 20 {
+		t.Fatalf("expected 20 pins, got %d (%v)", len(pins), err)
+	}
+	notes, err := ReadWorktreeNotes("/repo")
+	if err != nil || len(notes) != 20 {
+		t.Fatalf("expected 20 notes, got %d (%v)", len(notes), err)
+	}
+}
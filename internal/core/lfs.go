@@ -0,0 +1,108 @@
+package core
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const LFSSkipSmudgeEnv = "GIT_LFS_SKIP_SMUDGE=1"
+
+// RepoUsesLFS reports whether the checkout at root routes any path through
+// the LFS filter. Nested .gitattributes are rare enough for LFS that only the
+// top-level file is read.
+func RepoUsesLFS(root string) bool {
+	f, err := os.Open(filepath.Join(root, ".gitattributes"))
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		for _, attr := range fields[1:] {
+			if attr == "filter=lfs" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// LFSCheckoutEnv is the environment for `git worktree add` from sourceRoot:
+// with lfs_skip_smudge, checkout writes pointer files instead of downloading.
+func LFSCheckoutEnv(sourceRoot string) []string {
+	if !RepoUsesLFS(sourceRoot) {
+		return nil
+	}
+	cfg, err := LoadConfigForDir(sourceRoot)
+	if err != nil || !cfg.LFSSkipSmudge {
+		return nil
+	}
+	return []string{LFSSkipSmudgeEnv}
+}
+
+func lfsInstalled(gitPath string) bool {
+	_, err := CommandOutputInDir("", gitPath, "lfs", "version")
+	return err == nil
+}
+
+// lfsFilterConfigured reports whether checkout already ran the LFS smudge
+// filter, i.e. `git lfs install` was run globally or for this repo.
+func lfsFilterConfigured(worktreePath string) bool {
+	return gitConfigValue(worktreePath, "filter.lfs.process") != "" || gitConfigValue(worktreePath, "filter.lfs.smudge") != ""
+}
+
+// prepareWorktreeLFS makes sure a new worktree has its LFS files, or says
+// why it does not. Failures are warnings: the worktree itself is usable.
+func prepareWorktreeLFS(worktreePath string, skipSmudge bool, progress io.Writer) {
+	if !RepoUsesLFS(worktreePath) {
+		return
+	}
+	if progress == nil {
+		progress = io.Discard
+	}
+	if skipSmudge {
+		fmt.Fprintln(progress, "Git LFS files are pointers (lfs_skip_smudge); use the Hydrate LFS action to download them.")
+		return
+	}
+	gitPath, err := RequireGitPath()
+	if err != nil {
+		return
+	}
+	if !lfsInstalled(gitPath) {
+		fmt.Fprintln(progress, "wtx warning: this repo uses Git LFS but git-lfs is not installed; LFS files are left as pointers")
+		return
+	}
+	if lfsFilterConfigured(worktreePath) {
+		fmt.Fprintln(progress, "Git LFS files were downloaded during checkout; set lfs_skip_smudge to create worktrees faster.")
+		return
+	}
+	if err := HydrateLFS(worktreePath, progress); err != nil {
+		fmt.Fprintln(progress, "wtx warning: git lfs pull failed:", err)
+	}
+}
+
+// HydrateLFS installs the LFS hooks for the repo and downloads the files the
+// worktree's checkout needs.
+func HydrateLFS(worktreePath string, progress io.Writer) error {
+	gitPath, err := RequireGitPath()
+	if err != nil {
+		return err
+	}
+	if !lfsInstalled(gitPath) {
+		return fmt.Errorf("git-lfs is not installed")
+	}
+	if !lfsFilterConfigured(worktreePath) {
+		if err := runStreamedInDir(worktreePath, gitPath, progress, "lfs", "install", "--local"); err != nil {
+			return err
+		}
+	}
+	return runStreamedInDir(worktreePath, gitPath, progress, "lfs", "pull")
+}
@@ -0,0 +1,28 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRepoUsesLFS(t *testing.T) {
+	dir := t.TempDir()
+	if RepoUsesLFS(dir) {
+		t.Fatalf("expected no LFS without .gitattributes")
+	}
+	attrs := "\n# *.psd filter=lfs diff=lfs merge=lfs -text\n*.go text eol=lf\n"
+	if err := os.WriteFile(filepath.Join(dir, ".gitattributes"), []byte(attrs), 0o644); err != nil {
+		t.Fatalf("write .gitattributes: %v", err)
+	}
+	if RepoUsesLFS(dir) {
+		t.Fatalf("expected commented LFS pattern to be ignored")
+	}
+	attrs += "*.bin filter=lfs diff=lfs merge=lfs -text\n"
+	if err := os.WriteFile(filepath.Join(dir, ".gitattributes"), []byte(attrs), 0o644); err != nil {
+		t.Fatalf("write .gitattributes: %v", err)
+	}
+	if !RepoUsesLFS(dir) {
+		t.Fatalf("expected LFS pattern to be detected")
+	}
+}
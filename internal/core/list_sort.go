@@ -0,0 +1,32 @@
+package core
+
+// ListSortMode is the worktree table order cycled with the sort key.
+type ListSortMode int
+
+const (
+	listSortStatus ListSortMode = iota
+	ListSortBranch
+	ListSortPR
+	ListSortCI
+	ListSortActivity
+	ListSortModeCount
+)
+
+func (s ListSortMode) Label() string {
+	switch s {
+	case ListSortBranch:
+		return "branch"
+	case ListSortPR:
+		return "PR number"
+	case ListSortCI:
+		return "CI state"
+	case ListSortActivity:
+		return "last activity"
+	default:
+		return "status"
+	}
+}
+
+func (s ListSortMode) Next() ListSortMode {
+	return (s + 1) % ListSortModeCount
+}
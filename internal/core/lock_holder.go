@@ -0,0 +1,136 @@
+package core
+
+import (
+	"context"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+)
+
+// LockHolder describes who holds a worktree lock, for the table and the
+// detail line under it.
+type LockHolder struct {
+	User        string
+	Host        string
+	TmuxSession string
+	Command     string
+}
+
+// Short is the compact form shown next to the branch.
+func (h LockHolder) Short() string {
+	if h.TmuxSession != "" {
+		return "tmux " + h.TmuxSession
+	}
+	return h.UserHost()
+}
+
+func (h LockHolder) String() string {
+	parts := []string{}
+	if uh := h.UserHost(); uh != "" {
+		parts = append(parts, uh)
+	}
+	if h.TmuxSession != "" {
+		parts = append(parts, "tmux session "+h.TmuxSession)
+	}
+	if h.Command != "" {
+		parts = append(parts, "running "+h.Command)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (h LockHolder) UserHost() string {
+	switch {
+	case h.User != "" && h.Host != "":
+		return h.User + "@" + h.Host
+	case h.User != "":
+		return h.User
+	default:
+		return h.Host
+	}
+}
+
+func hostName() string {
+	host, _ := os.Hostname()
+	return host
+}
+
+func currentUserName() string {
+	if name := strings.TrimSpace(os.Getenv("USER")); name != "" {
+		return name
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return ""
+}
+
+// Holder reads who holds the worktree lock. Locks written before user and
+// host were recorded fall back to the user@host prefix of the owner ID.
+func (m *LockManager) Holder(repoRoot string, worktreePath string) (LockHolder, bool) {
+	lockPath, err := m.LockPath(strings.TrimSpace(repoRoot), strings.TrimSpace(worktreePath))
+	if err != nil {
+		return LockHolder{}, false
+	}
+	payload, err := ReadLockPayload(lockPath)
+	if err != nil {
+		return LockHolder{}, false
+	}
+	holder := LockHolder{User: payload.User, Host: payload.Host}
+	if holder.User == "" && holder.Host == "" {
+		if head, _, ok := strings.Cut(payload.OwnerID, ":"); ok && strings.Contains(head, "@") {
+			holder.User, holder.Host, _ = strings.Cut(head, "@")
+		}
+	}
+	if target := tmuxOwnerTarget(payload.OwnerID); target != "" {
+		if out, err := TmuxOutput("display-message", "-p", "-t", target, "#{session_name}\t#{pane_current_command}"); err == nil {
+			name, command, _ := strings.Cut(strings.TrimSpace(string(out)), "\t")
+			holder.TmuxSession = strings.TrimSpace(name)
+			holder.Command = strings.TrimSpace(command)
+		}
+	}
+	if agent := SavedAgentCommand(worktreePath); agent != "" {
+		holder.Command = agent
+	} else if holder.Command == "" && payload.PID > 0 {
+		holder.Command = processName(payload.PID)
+	}
+	return holder, true
+}
+
+// tmuxOwnerTarget turns a tmux owner ID into a display-message target,
+// preferring the window so pane_current_command is the agent's pane.
+func tmuxOwnerTarget(ownerID string) string {
+	sessionID, windowID, ok := parseTmuxOwnerID(ownerID)
+	if !ok {
+		return ""
+	}
+	if windowID != "" {
+		return windowID
+	}
+	return sessionID
+}
+
+func SavedAgentCommand(worktreePath string) string {
+	sessions, err := ReadSavedSessions()
+	if err != nil {
+		return ""
+	}
+	for _, s := range sessions {
+		if SameSessionPath(s.WorktreePath, worktreePath) {
+			return strings.TrimSpace(s.AgentCommand)
+		}
+	}
+	return ""
+}
+
+func processName(pid int) string {
+	out, err := RunExternalCommand(context.Background(), CommandSpec{Name: "ps", Args: []string{"-o", "comm=", "-p", strconv.Itoa(pid)}, Timeout: tmuxCommandTimeout, StdoutOnly: true})
+	if err != nil {
+		return ""
+	}
+	name := strings.TrimSpace(string(out))
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
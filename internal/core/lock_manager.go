@@ -1,4 +1,4 @@
-package cmd
+package core
 
 import (
 	"crypto/rand"
@@ -9,17 +9,21 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
-	"os/user"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
-	"syscall"
 	"time"
 )
 
+// ErrWorktreeLocked is returned by Acquire when another live owner holds the lock.
+var ErrWorktreeLocked = errors.New("worktree locked")
+
 type LockManager struct {
 	staleAfter time.Duration
+	// ownerID overrides the process owner, letting the daemon answer lock
+	// queries on behalf of a client.
+	ownerID string
 }
 
 func NewLockManager() *LockManager {
@@ -31,7 +35,7 @@ type WorktreeLock struct {
 	worktreePath string
 	repoRoot     string
 	ownerID      string
-	pid          int
+	PID          int
 }
 
 var (
@@ -50,7 +54,23 @@ func (m *LockManager) AcquireForPID(repoRoot string, worktreePath string, pid in
 	return m.acquireWithPID(repoRoot, worktreePath, pid)
 }
 
+// AcquireForOwner locks the worktree on behalf of another owner, such as a
+// tmux window other than the one wtx is running in.
+func (m *LockManager) AcquireForOwner(repoRoot string, worktreePath string, ownerID string, pid int) (*WorktreeLock, error) {
+	if pid <= 0 {
+		return nil, errors.New("invalid pid")
+	}
+	if strings.TrimSpace(ownerID) == "" {
+		return nil, errors.New("owner required")
+	}
+	return m.acquire(repoRoot, worktreePath, strings.TrimSpace(ownerID), pid)
+}
+
 func (m *LockManager) acquireWithPID(repoRoot string, worktreePath string, pid int) (*WorktreeLock, error) {
+	return m.acquire(repoRoot, worktreePath, m.SelfOwnerID(), pid)
+}
+
+func (m *LockManager) acquire(repoRoot string, worktreePath string, ownerID string, pid int) (*WorktreeLock, error) {
 	repoRoot = strings.TrimSpace(repoRoot)
 	worktreePath = strings.TrimSpace(worktreePath)
 	if repoRoot == "" {
@@ -60,7 +80,7 @@ func (m *LockManager) acquireWithPID(repoRoot string, worktreePath string, pid i
 		return nil, errors.New("worktree path required")
 	}
 
-	lockPath, err := m.lockPath(repoRoot, worktreePath)
+	lockPath, err := m.LockPath(repoRoot, worktreePath)
 	if err != nil {
 		return nil, err
 	}
@@ -68,7 +88,6 @@ func (m *LockManager) acquireWithPID(repoRoot string, worktreePath string, pid i
 		return nil, err
 	}
 
-	ownerID := buildOwnerID()
 	payload, err := lockPayload(repoRoot, worktreePath, ownerID, pid)
 	if err != nil {
 		return nil, err
@@ -83,7 +102,8 @@ func (m *LockManager) acquireWithPID(repoRoot string, worktreePath string, pid i
 		}
 		_ = file.Close()
 		_ = writeWorktreeLastUsed(repoRoot, worktreePath)
-		return &WorktreeLock{path: lockPath, worktreePath: worktreePath, repoRoot: repoRoot, ownerID: ownerID, pid: pid}, nil
+		RecordAction(AuditLock, repoRoot, worktreePath, "", map[string]string{"owner": ownerID, "pid": strconv.Itoa(pid)})
+		return &WorktreeLock{path: lockPath, worktreePath: worktreePath, repoRoot: repoRoot, ownerID: ownerID, PID: pid}, nil
 	}
 	if !errors.Is(err, os.ErrExist) {
 		return nil, err
@@ -93,40 +113,55 @@ func (m *LockManager) acquireWithPID(repoRoot string, worktreePath string, pid i
 	if statErr != nil {
 		return nil, statErr
 	}
-	current, readErr := readLockPayload(lockPath)
+	current, readErr := ReadLockPayload(lockPath)
 	ownerActive := false
 	if readErr == nil {
-		ownerActive = lockOwnerStillActive(current.OwnerID, current.PID)
+		ownerActive = LockOwnerStillActive(current.OwnerID, current.PID)
 	}
 	if readErr == nil && ownerActive {
 		if current.OwnerID != ownerID {
-			return nil, errors.New("worktree locked")
+			return nil, lockRefused(worktreePath, current, "held by an active owner")
 		}
 	}
 	if time.Since(info.ModTime()) < m.staleAfter {
 		if readErr != nil || (ownerActive && current.OwnerID != ownerID) {
-			return nil, errors.New("worktree locked")
+			return nil, lockRefused(worktreePath, current, "unreadable lock is not stale yet")
 		}
 	}
 
+	previous := current
 	tmpPath := lockPath + "." + randomToken() + ".tmp"
 	if err := os.WriteFile(tmpPath, payload, 0o644); err != nil {
 		return nil, err
 	}
-	if err := os.Rename(tmpPath, lockPath); err != nil {
+	if err := replaceFile(tmpPath, lockPath); err != nil {
 		_ = os.Remove(tmpPath)
 		return nil, err
 	}
 
-	current, err = readLockPayload(lockPath)
+	current, err = ReadLockPayload(lockPath)
 	if err != nil {
 		return nil, err
 	}
 	if current.OwnerID != ownerID || current.PID != pid {
-		return nil, errors.New("worktree locked")
+		return nil, lockRefused(worktreePath, current, "lost the race to take over a stale lock")
 	}
+	DebugLog("lock taken over", "worktree", worktreePath, "owner", ownerID, "pid", pid)
+	details := map[string]string{"owner": ownerID, "pid": strconv.Itoa(pid)}
+	action := AuditLock
+	if previous.OwnerID != ownerID {
+		action = AuditLockSteal
+		details["previous_owner"] = previous.OwnerID
+		details["previous_pid"] = strconv.Itoa(previous.PID)
+	}
+	RecordAction(action, repoRoot, worktreePath, "", details)
 	_ = writeWorktreeLastUsed(repoRoot, worktreePath)
-	return &WorktreeLock{path: lockPath, worktreePath: worktreePath, repoRoot: repoRoot, ownerID: ownerID, pid: pid}, nil
+	return &WorktreeLock{path: lockPath, worktreePath: worktreePath, repoRoot: repoRoot, ownerID: ownerID, PID: pid}, nil
+}
+
+func lockRefused(worktreePath string, holder lockPayloadData, reason string) error {
+	DebugLog("lock refused", "worktree", worktreePath, "holder_owner", holder.OwnerID, "holder_pid", holder.PID, "reason", reason)
+	return ErrWorktreeLocked
 }
 
 func (m *LockManager) IsAvailable(repoRoot string, worktreePath string) (bool, error) {
@@ -139,23 +174,23 @@ func (m *LockManager) IsAvailable(repoRoot string, worktreePath string) (bool, e
 		return false, errors.New("worktree path required")
 	}
 
-	lockPath, err := m.lockPath(repoRoot, worktreePath)
+	lockPath, err := m.LockPath(repoRoot, worktreePath)
 	if err != nil {
 		return false, err
 	}
 	info, err := os.Stat(lockPath)
 	if err == nil {
-		payload, perr := readLockPayload(lockPath)
+		payload, perr := ReadLockPayload(lockPath)
 		if perr != nil {
 			return false, nil
 		}
-		if payload.OwnerID == buildOwnerID() {
+		if payload.OwnerID == m.SelfOwnerID() {
 			return true, nil
 		}
-		if lockOwnerStillActive(payload.OwnerID, payload.PID) {
+		if LockOwnerStillActive(payload.OwnerID, payload.PID) {
 			return false, nil
 		}
-		if time.Since(info.ModTime()) < m.staleAfter && payload.OwnerID != buildOwnerID() {
+		if time.Since(info.ModTime()) < m.staleAfter && payload.OwnerID != m.SelfOwnerID() {
 			return false, nil
 		}
 		return true, nil
@@ -166,6 +201,48 @@ func (m *LockManager) IsAvailable(repoRoot string, worktreePath string) (bool, e
 	return false, err
 }
 
+// HolderPID returns the PID bound to the worktree lock, if any.
+func (m *LockManager) HolderPID(repoRoot string, worktreePath string) (int, bool) {
+	lockPath, err := m.LockPath(strings.TrimSpace(repoRoot), strings.TrimSpace(worktreePath))
+	if err != nil {
+		return 0, false
+	}
+	payload, err := ReadLockPayload(lockPath)
+	if err != nil || payload.PID <= 0 {
+		return 0, false
+	}
+	return payload.PID, true
+}
+
+// HolderOwnerID returns the owner recorded in the worktree lock, if any.
+func (m *LockManager) HolderOwnerID(repoRoot string, worktreePath string) (string, bool) {
+	lockPath, err := m.LockPath(strings.TrimSpace(repoRoot), strings.TrimSpace(worktreePath))
+	if err != nil {
+		return "", false
+	}
+	payload, err := ReadLockPayload(lockPath)
+	if err != nil || strings.TrimSpace(payload.OwnerID) == "" {
+		return "", false
+	}
+	return payload.OwnerID, true
+}
+
+// Adopt returns the lock someone currently holds on the worktree, so its
+// owner can be moved with Rebind without the lock ever being released.
+func (m *LockManager) Adopt(repoRoot string, worktreePath string) (*WorktreeLock, error) {
+	repoRoot = strings.TrimSpace(repoRoot)
+	worktreePath = strings.TrimSpace(worktreePath)
+	lockPath, err := m.LockPath(repoRoot, worktreePath)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := ReadLockPayload(lockPath)
+	if err != nil {
+		return nil, err
+	}
+	return &WorktreeLock{path: lockPath, worktreePath: worktreePath, repoRoot: repoRoot, ownerID: payload.OwnerID, PID: payload.PID}, nil
+}
+
 func (l *WorktreeLock) Release() {
 	if l == nil {
 		return
@@ -183,13 +260,18 @@ func (m *LockManager) ForceUnlock(repoRoot string, worktreePath string) error {
 	if worktreePath == "" {
 		return errors.New("worktree path required")
 	}
-	lockPath, err := m.lockPath(repoRoot, worktreePath)
+	lockPath, err := m.LockPath(repoRoot, worktreePath)
 	if err != nil {
 		return err
 	}
-	if err := os.Remove(lockPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+	holder, _ := ReadLockPayload(lockPath)
+	if err := os.Remove(lockPath); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
 		return err
 	}
+	RecordAction(AuditUnlock, repoRoot, worktreePath, "", map[string]string{"previous_owner": holder.OwnerID, "previous_pid": strconv.Itoa(holder.PID)})
 	return nil
 }
 
@@ -199,18 +281,18 @@ func (m *LockManager) ReleaseIfOwned(repoRoot string, worktreePath string) error
 	if repoRoot == "" || worktreePath == "" {
 		return nil
 	}
-	lockPath, err := m.lockPath(repoRoot, worktreePath)
+	lockPath, err := m.LockPath(repoRoot, worktreePath)
 	if err != nil {
 		return err
 	}
-	payload, err := readLockPayload(lockPath)
+	payload, err := ReadLockPayload(lockPath)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
 			return nil
 		}
 		return err
 	}
-	if payload.OwnerID != buildOwnerID() {
+	if payload.OwnerID != m.SelfOwnerID() {
 		return nil
 	}
 	_ = writeWorktreeLastUsed(repoRoot, worktreePath)
@@ -221,20 +303,32 @@ func (m *LockManager) ReleaseIfOwned(repoRoot string, worktreePath string) error
 }
 
 func (l *WorktreeLock) RebindPID(pid int) error {
+	if l == nil {
+		return errors.New("lock required")
+	}
+	return l.Rebind(l.ownerID, pid)
+}
+
+// Rebind moves the lock to a new owner and PID while keeping it held.
+func (l *WorktreeLock) Rebind(ownerID string, pid int) error {
 	if l == nil {
 		return errors.New("lock required")
 	}
 	if pid <= 0 {
 		return errors.New("invalid pid")
 	}
-	current, err := readLockPayload(l.path)
+	current, err := ReadLockPayload(l.path)
 	if err != nil {
 		return err
 	}
-	if current.OwnerID != l.ownerID || current.PID != l.pid {
+	if current.OwnerID != l.ownerID || current.PID != l.PID {
 		return errors.New("lock ownership lost")
 	}
-	payload, err := lockPayload(l.repoRoot, l.worktreePath, l.ownerID, pid)
+	ownerID = strings.TrimSpace(ownerID)
+	if ownerID == "" {
+		ownerID = l.ownerID
+	}
+	payload, err := lockPayload(l.repoRoot, l.worktreePath, ownerID, pid)
 	if err != nil {
 		return err
 	}
@@ -242,23 +336,24 @@ func (l *WorktreeLock) RebindPID(pid int) error {
 	if err := os.WriteFile(tmpPath, payload, 0o644); err != nil {
 		return err
 	}
-	if err := os.Rename(tmpPath, l.path); err != nil {
+	if err := replaceFile(tmpPath, l.path); err != nil {
 		_ = os.Remove(tmpPath)
 		return err
 	}
 	_ = writeWorktreeLastUsed(l.repoRoot, l.worktreePath)
-	l.pid = pid
+	l.ownerID = ownerID
+	l.PID = pid
 	return nil
 }
 
-func (m *LockManager) lockPath(repoRoot string, worktreePath string) (string, error) {
+func (m *LockManager) LockPath(repoRoot string, worktreePath string) (string, error) {
 	worktreeID, err := worktreeID(repoRoot, worktreePath)
 	if err != nil {
 		return "", err
 	}
-	home := strings.TrimSpace(os.Getenv("HOME"))
-	if home == "" {
-		return "", errors.New("HOME not set")
+	home, err := UserHomeDir()
+	if err != nil {
+		return "", err
 	}
 	lockDir := filepath.Join(home, ".wtx", "locks")
 	return filepath.Join(lockDir, worktreeID+".lock"), nil
@@ -266,8 +361,8 @@ func (m *LockManager) lockPath(repoRoot string, worktreePath string) (string, er
 
 func worktreeID(repoRoot string, worktreePath string) (string, error) {
 	repoIDRoot := repoRoot
-	if gitPath, err := gitPath(); err == nil {
-		commonDir, err := gitOutputInDir(repoRoot, gitPath, "rev-parse", "--path-format=absolute", "--git-common-dir")
+	if gitPath, err := GitPath(); err == nil {
+		commonDir, err := GitOutputInDir(repoRoot, gitPath, "rev-parse", "--path-format=absolute", "--git-common-dir")
 		if err == nil && strings.TrimSpace(commonDir) != "" {
 			repoIDRoot = commonDir
 		}
@@ -276,13 +371,13 @@ func worktreeID(repoRoot string, worktreePath string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	worktreeReal, err := realPathOrAbs(worktreePath)
+	worktreeReal, err := RealPathOrAbs(worktreePath)
 	if err != nil {
 		return "", err
 	}
 
-	repoID := hashString(repoRootReal)
-	worktreeID := hashString(repoID + ":" + worktreeReal)
+	repoID := HashString(repoRootReal)
+	worktreeID := HashString(repoID + ":" + worktreeReal)
 	return worktreeID, nil
 }
 
@@ -294,19 +389,33 @@ func realPath(path string) (string, error) {
 	return filepath.EvalSymlinks(abs)
 }
 
-func realPathOrAbs(path string) (string, error) {
+// RealPathOrAbs resolves symlinks in whatever part of path exists, so a
+// worktree under a symlinked volume hashes the same before it is created and
+// while its disk is unmounted as it does once it is there.
+func RealPathOrAbs(path string) (string, error) {
 	abs, err := filepath.Abs(path)
 	if err != nil {
 		return "", err
 	}
 	real, err := filepath.EvalSymlinks(abs)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
+	if err == nil {
+		return real, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	dir, rest := abs, ""
+	for {
+		parent := filepath.Dir(dir)
+		if parent == dir {
 			return abs, nil
 		}
-		return "", err
+		rest = filepath.Join(filepath.Base(dir), rest)
+		dir = parent
+		if real, err := filepath.EvalSymlinks(dir); err == nil {
+			return filepath.Join(real, rest), nil
+		}
 	}
-	return real, nil
 }
 
 func writeWorktreeLastUsed(repoRoot string, worktreePath string) error {
@@ -338,19 +447,26 @@ func worktreeLastUsedPath(repoRoot string, worktreePath string) (string, error)
 	if err != nil {
 		return "", err
 	}
-	home := strings.TrimSpace(os.Getenv("HOME"))
-	if home == "" {
-		return "", errors.New("HOME not set")
+	home, err := UserHomeDir()
+	if err != nil {
+		return "", err
 	}
 	lastUsedDir := filepath.Join(home, ".wtx", "last_used")
 	return filepath.Join(lastUsedDir, worktreeID), nil
 }
 
-func hashString(value string) string {
+func HashString(value string) string {
 	sum := sha256.Sum256([]byte(value))
 	return hex.EncodeToString(sum[:])
 }
 
+func (m *LockManager) SelfOwnerID() string {
+	if m.ownerID != "" {
+		return m.ownerID
+	}
+	return buildOwnerID()
+}
+
 func buildOwnerID() string {
 	ownerIDOnce.Do(func() {
 		cachedOwnerID = computeOwnerID()
@@ -362,8 +478,8 @@ func computeOwnerID() string {
 	if explicit := strings.TrimSpace(os.Getenv("WTX_OWNER_ID")); explicit != "" {
 		return "explicit:" + explicit
 	}
-	if !tmuxIntegrationDisabled() && strings.TrimSpace(os.Getenv("TMUX")) != "" {
-		if sessionID, err := currentSessionID(); err == nil && strings.TrimSpace(sessionID) != "" {
+	if !TmuxIntegrationDisabled() && strings.TrimSpace(os.Getenv("TMUX")) != "" {
+		if sessionID, err := CurrentSessionID(); err == nil && strings.TrimSpace(sessionID) != "" {
 			if windowID, werr := currentWindowID(); werr == nil && strings.TrimSpace(windowID) != "" {
 				return "tmux:" + sessionID + ":" + windowID
 			}
@@ -380,13 +496,8 @@ func computeOwnerID() string {
 		return "kitty-window:" + window
 	}
 
-	name := os.Getenv("USER")
-	if name == "" {
-		if u, err := user.Current(); err == nil {
-			name = u.Username
-		}
-	}
-	host, _ := os.Hostname()
+	name := currentUserName()
+	host := hostName()
 	if name == "" && host == "" {
 		name = "unknown"
 	}
@@ -397,8 +508,11 @@ func computeOwnerID() string {
 }
 
 type lockPayloadData struct {
-	OwnerID string `json:"owner_id"`
-	PID     int    `json:"pid"`
+	OwnerID      string `json:"owner_id"`
+	PID          int    `json:"pid"`
+	User         string `json:"user"`
+	Host         string `json:"host"`
+	WorktreePath string `json:"worktree_path"`
 }
 
 func lockPayload(repoRoot string, worktreePath string, ownerID string, pid int) ([]byte, error) {
@@ -408,11 +522,13 @@ func lockPayload(repoRoot string, worktreePath string, ownerID string, pid int)
 		"worktree_path": worktreePath,
 		"repo_root":     repoRoot,
 		"timestamp":     time.Now().UTC().Format(time.RFC3339Nano),
+		"user":          currentUserName(),
+		"host":          hostName(),
 	}
 	return json.Marshal(data)
 }
 
-func readLockPayload(path string) (lockPayloadData, error) {
+func ReadLockPayload(path string) (lockPayloadData, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return lockPayloadData{}, err
@@ -424,38 +540,24 @@ func readLockPayload(path string) (lockPayloadData, error) {
 	return payload, nil
 }
 
-func pidAlive(pid int) bool {
-	if pid <= 0 {
-		return false
-	}
-	err := syscall.Kill(pid, 0)
-	if err == nil {
-		return true
-	}
-	if errors.Is(err, syscall.EPERM) {
-		return true
-	}
-	return false
-}
-
-func lockOwnerStillActive(ownerID string, pid int) bool {
+func LockOwnerStillActive(ownerID string, pid int) bool {
 	ownerID = strings.TrimSpace(ownerID)
 	if ownerID == "" {
-		return pidAlive(pid)
+		return PIDAlive(pid)
 	}
 	if strings.HasPrefix(ownerID, "tmux:") {
 		return tmuxOwnerStillActive(ownerID, pid)
 	}
-	return pidAlive(pid)
+	return PIDAlive(pid)
 }
 
 func tmuxOwnerStillActive(ownerID string, pid int) bool {
 	sessionID, windowID, ok := parseTmuxOwnerID(ownerID)
 	if !ok {
-		return pidAlive(pid)
+		return PIDAlive(pid)
 	}
 	if _, err := exec.LookPath("tmux"); err != nil {
-		return pidAlive(pid)
+		return PIDAlive(pid)
 	}
 	if !tmuxSessionExists(sessionID) {
 		return false
@@ -467,7 +569,7 @@ func tmuxOwnerStillActive(ownerID string, pid int) bool {
 		return false
 	}
 	if pid > 0 {
-		return pidAlive(pid)
+		return PIDAlive(pid)
 	}
 	return true
 }
@@ -498,8 +600,7 @@ func tmuxSessionExists(sessionID string) bool {
 	if sessionID == "" {
 		return false
 	}
-	cmd := exec.Command("tmux", "has-session", "-t", sessionID)
-	return cmd.Run() == nil
+	return TmuxRun("has-session", "-t", sessionID) == nil
 }
 
 func tmuxWindowExists(sessionID string, windowID string) bool {
@@ -508,7 +609,7 @@ func tmuxWindowExists(sessionID string, windowID string) bool {
 	if sessionID == "" || windowID == "" {
 		return false
 	}
-	out, err := exec.Command("tmux", "list-windows", "-t", sessionID, "-F", "#{window_id}").Output()
+	out, err := TmuxOutput("list-windows", "-t", sessionID, "-F", "#{window_id}")
 	if err != nil {
 		return false
 	}
@@ -525,7 +626,7 @@ func tmuxSessionAttachedCount(sessionID string) (int, bool) {
 	if sessionID == "" {
 		return 0, false
 	}
-	out, err := exec.Command("tmux", "display-message", "-p", "-t", sessionID, "#{session_attached}").Output()
+	out, err := TmuxOutput("display-message", "-p", "-t", sessionID, "#{session_attached}")
 	if err != nil {
 		return 0, false
 	}
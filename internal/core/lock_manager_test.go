@@ -0,0 +1,107 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseTmuxOwnerID(t *testing.T) {
+	t.Run("session and window", func(t *testing.T) {
+		sessionID, windowID, ok := parseTmuxOwnerID("tmux:$1:@2")
+		if !ok {
+			t.Fatalf("expected tmux owner to parse")
+		}
+		if sessionID != "$1" {
+			t.Fatalf("expected session $1, got %q", sessionID)
+		}
+		if windowID != "@2" {
+			t.Fatalf("expected window @2, got %q", windowID)
+		}
+	})
+
+	t.Run("session only", func(t *testing.T) {
+		sessionID, windowID, ok := parseTmuxOwnerID("tmux:$9")
+		if !ok {
+			t.Fatalf("expected tmux owner to parse")
+		}
+		if sessionID != "$9" {
+			t.Fatalf("expected session $9, got %q", sessionID)
+		}
+		if windowID != "" {
+			t.Fatalf("expected empty window, got %q", windowID)
+		}
+	})
+
+	t.Run("invalid owner", func(t *testing.T) {
+		if _, _, ok := parseTmuxOwnerID("term-session:abc"); ok {
+			t.Fatalf("expected non-tmux owner to fail parsing")
+		}
+		if _, _, ok := parseTmuxOwnerID("tmux:"); ok {
+			t.Fatalf("expected empty tmux owner to fail parsing")
+		}
+	})
+}
+
+func TestLockOwnerStillActive_UnknownOwnerWithoutPID(t *testing.T) {
+	if LockOwnerStillActive("", 0) {
+		t.Fatalf("expected empty owner without pid to be inactive")
+	}
+}
+
+func TestLockHolderReadsPayloadAndSavedAgent(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USER", "alice")
+	repo := t.TempDir()
+	wt := t.TempDir()
+	m := NewLockManager()
+
+	lockPath, err := m.LockPath(repo, wt)
+	if err != nil {
+		t.Fatalf("lock path: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(lockPath, []byte(`{"owner_id":"bob@laptop:12:abc","pid":0}`), 0o644); err != nil {
+		t.Fatalf("write lock: %v", err)
+	}
+	holder, ok := m.Holder(repo, wt)
+	if !ok || holder.String() != "bob@laptop" {
+		t.Fatalf("expected user@host from a legacy owner ID, got %+v", holder)
+	}
+
+	if err := os.Remove(lockPath); err != nil {
+		t.Fatalf("remove lock: %v", err)
+	}
+	if _, err := m.AcquireForOwner(repo, wt, "explicit:test", os.Getpid()); err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	writeTestSessions(t, SavedSession{RepoRoot: repo, WorktreePath: wt, Branch: "feature/a", AgentCommand: "claude"})
+	holder, ok = m.Holder(repo, wt)
+	if !ok || holder.User != "alice" || holder.Host != hostName() || holder.Command != "claude" {
+		t.Fatalf("expected user, host and agent command, got %+v", holder)
+	}
+	if want := "alice@" + hostName() + ", running claude"; holder.String() != want {
+		t.Fatalf("expected %q, got %q", want, holder.String())
+	}
+}
+
+func TestRealPathOrAbsResolvesExistingParent(t *testing.T) {
+	target := t.TempDir()
+	link := filepath.Join(t.TempDir(), "volume")
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+	realTarget, err := filepath.EvalSymlinks(target)
+	if err != nil {
+		t.Fatalf("resolve target: %v", err)
+	}
+	got, err := RealPathOrAbs(filepath.Join(link, "repo.wt", "wt.1"))
+	if err != nil {
+		t.Fatalf("realPathOrAbs: %v", err)
+	}
+	if want := filepath.Join(realTarget, "repo.wt", "wt.1"); got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+}
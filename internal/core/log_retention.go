@@ -0,0 +1,201 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const (
+	defaultLogRetentionDays = 30
+	defaultLogMaxMB         = 500
+	// logPruneInterval throttles the automatic prune run when logs are written.
+	logPruneInterval = 24 * time.Hour
+	logPruneStamp    = ".pruned"
+	// logLiveWindow protects files still being written, such as the log of a
+	// running agent, from the size cap.
+	logLiveWindow = time.Hour
+)
+
+func logRetention(cfg Config) time.Duration {
+	days := cfg.LogRetentionDays
+	if days <= 0 {
+		days = defaultLogRetentionDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+func logMaxBytes(cfg Config) int64 {
+	mb := cfg.LogMaxMB
+	if mb <= 0 {
+		mb = defaultLogMaxMB
+	}
+	return int64(mb) << 20
+}
+
+type prunedLog struct {
+	Path   string
+	Size   int64
+	Reason string
+}
+
+type logFileInfo struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// prunableLogs lists the files retention applies to: agent logs, rotated
+// debug and history logs, and crash reports. The live wtx.log and
+// history.jsonl are rotated by size when written instead.
+func prunableLogs(home string) ([]logFileInfo, map[string][]logFileInfo) {
+	var all []logFileInfo
+	agentLogs := map[string][]logFileInfo{}
+	add := func(path string, info fs.FileInfo) logFileInfo {
+		f := logFileInfo{path: path, size: info.Size(), modTime: info.ModTime()}
+		all = append(all, f)
+		return f
+	}
+	logsDir := filepath.Join(home, "logs")
+	entries, _ := os.ReadDir(logsDir)
+	for _, entry := range entries {
+		path := filepath.Join(logsDir, entry.Name())
+		if !entry.IsDir() {
+			if info, err := entry.Info(); err == nil && filepath.Ext(entry.Name()) == ".1" {
+				add(path, info)
+			}
+			continue
+		}
+		logs, _ := os.ReadDir(path)
+		for _, log := range logs {
+			if log.IsDir() || filepath.Ext(log.Name()) != ".log" {
+				continue
+			}
+			if info, err := log.Info(); err == nil {
+				agentLogs[path] = append(agentLogs[path], add(filepath.Join(path, log.Name()), info))
+			}
+		}
+	}
+	crashes, _ := os.ReadDir(filepath.Join(home, "crashes"))
+	for _, entry := range crashes {
+		if info, err := entry.Info(); err == nil && !entry.IsDir() {
+			add(filepath.Join(home, "crashes", entry.Name()), info)
+		}
+	}
+	if info, err := os.Stat(filepath.Join(home, auditLogFileName+".1")); err == nil {
+		add(filepath.Join(home, auditLogFileName+".1"), info)
+	}
+	return all, agentLogs
+}
+
+// PruneLogs removes logs older than the retention period, agent logs beyond
+// agent_log_keep per repo, and then the oldest files until the total fits
+// log_max_mb. dryRun only reports what would go.
+func PruneLogs(cfg Config, now time.Time, dryRun bool) ([]prunedLog, error) {
+	home, err := WtxHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	all, agentLogs := prunableLogs(home)
+	removed := map[string]bool{}
+	var pruned []prunedLog
+	remove := func(f logFileInfo, reason string) error {
+		if removed[f.path] {
+			return nil
+		}
+		if !dryRun {
+			if err := os.Remove(f.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+		}
+		removed[f.path] = true
+		pruned = append(pruned, prunedLog{Path: f.path, Size: f.size, Reason: reason})
+		return nil
+	}
+
+	retention := logRetention(cfg)
+	for _, f := range all {
+		if now.Sub(f.modTime) > retention {
+			if err := remove(f, fmt.Sprintf("older than %d days", int(retention.Hours()/24))); err != nil {
+				return pruned, err
+			}
+		}
+	}
+	keep := AgentLogKeep(cfg)
+	for _, logs := range agentLogs {
+		sortNewestFirst(logs)
+		if len(logs) <= keep {
+			continue
+		}
+		for _, f := range logs[keep:] {
+			if err := remove(f, fmt.Sprintf("more than %d logs for the repo", keep)); err != nil {
+				return pruned, err
+			}
+		}
+	}
+
+	var total int64
+	for _, f := range all {
+		if !removed[f.path] {
+			total += f.size
+		}
+	}
+	limit := logMaxBytes(cfg)
+	if total > limit {
+		sortNewestFirst(all)
+		for i := len(all) - 1; i >= 0 && total > limit; i-- {
+			f := all[i]
+			if removed[f.path] || now.Sub(f.modTime) < logLiveWindow {
+				continue
+			}
+			if err := remove(f, fmt.Sprintf("logs over %d MB", limit>>20)); err != nil {
+				return pruned, err
+			}
+			total -= f.size
+		}
+	}
+	return pruned, nil
+}
+
+func sortNewestFirst(files []logFileInfo) {
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.After(files[j].modTime)
+	})
+}
+
+// MaybePruneLogs runs pruneLogs at most once a day. It is called where logs
+// are written, so machines that never log never pay for it.
+func MaybePruneLogs() {
+	home, err := WtxHomeDir()
+	if err != nil {
+		return
+	}
+	stamp := filepath.Join(home, "logs", logPruneStamp)
+	if info, err := os.Stat(stamp); err == nil && time.Since(info.ModTime()) < logPruneInterval {
+		return
+	}
+	cfg, err := LoadConfig()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	if _, err := PruneLogs(cfg, time.Now(), false); err != nil {
+		DebugLog("log prune failed", "err", err.Error())
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(stamp), 0o755); err == nil {
+		_ = os.WriteFile(stamp, nil, 0o644)
+	}
+}
+
+const defaultAgentLogKeep = 20
+
+func AgentLogKeep(cfg Config) int {
+	if cfg.AgentLogKeep > 0 {
+		return cfg.AgentLogKeep
+	}
+	return defaultAgentLogKeep
+}
@@ -0,0 +1,104 @@
+package core
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeAgedLog(t *testing.T, path string, size int, age time.Duration) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, bytes.Repeat([]byte("x"), size), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+	ts := time.Now().Add(-age)
+	if err := os.Chtimes(path, ts, ts); err != nil {
+		t.Fatalf("chtimes %s: %v", path, err)
+	}
+}
+
+func TestPruneLogsAppliesRetentionKeepAndSizeCap(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	wtx := filepath.Join(home, ".wtx")
+	day := 24 * time.Hour
+	files := map[string]struct {
+		size int
+		age  time.Duration
+	}{
+		"logs/wtx.log":             {10, 40 * day},
+		"logs/wtx.log.1":           {10, 40 * day},
+		"logs/repo/old.log":        {10, 40 * day},
+		"logs/repo/a.log":          {10, 3 * day},
+		"logs/repo/b.log":          {10, 2 * day},
+		"logs/repo/c.log":          {10, day},
+		"logs/other/big.log":       {2 << 20, 5 * day},
+		"logs/other/running.log":   {2 << 20, time.Minute},
+		"crashes/crash-1.txt":      {10, 31 * day},
+		"crashes/crash-2.txt":      {10, day},
+		"history.jsonl.1":          {10, 2 * day},
+		"history.jsonl":            {10, 40 * day},
+		"logs/repo/notes.txt":      {10, 40 * day},
+		"logs/other/nested/x.log":  {10, 40 * day},
+		"logs/other/readme.md.txt": {10, day},
+	}
+	for name, f := range files {
+		writeAgedLog(t, filepath.Join(wtx, name), f.size, f.age)
+	}
+
+	cfg := Config{LogRetentionDays: 30, AgentLogKeep: 2, LogMaxMB: 3}
+	dry, err := PruneLogs(cfg, time.Now(), true)
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(wtx, "logs/repo/old.log")); err != nil {
+		t.Fatalf("dry run removed a file: %v", err)
+	}
+
+	pruned, err := PruneLogs(cfg, time.Now(), false)
+	if err != nil {
+		t.Fatalf("pruneLogs: %v", err)
+	}
+	if len(pruned) != len(dry) {
+		t.Fatalf("dry run reported %d files, prune removed %d", len(dry), len(pruned))
+	}
+	gone := map[string]bool{
+		"logs/wtx.log.1":      true,
+		"logs/repo/old.log":   true,
+		"logs/repo/a.log":     true,
+		"logs/other/big.log":  true,
+		"crashes/crash-1.txt": true,
+	}
+	for name := range files {
+		_, err := os.Stat(filepath.Join(wtx, name))
+		if exists := err == nil; exists == gone[name] {
+			t.Fatalf("%s exists=%v, want %v", name, exists, !gone[name])
+		}
+	}
+}
+
+func TestAuditLogReadsRotatedFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	path, err := auditLogPath()
+	if err != nil {
+		t.Fatalf("auditLogPath: %v", err)
+	}
+	RecordAction(AuditCreate, "", "/tmp/a", "a", nil)
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	RecordAction(AuditDelete, "", "/tmp/a", "a", nil)
+
+	entries, err := ReadAuditLog(AuditFilter{})
+	if err != nil {
+		t.Fatalf("readAuditLog: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Action != AuditCreate || entries[1].Action != AuditDelete {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+}
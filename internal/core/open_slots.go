@@ -0,0 +1,38 @@
+package core
+
+import "strings"
+
+type OpenSlotState struct {
+	Path      string
+	Branch    string
+	Locked    bool
+	Idle      bool
+	Dirty     bool
+	HasPR     bool
+	PRNumber  int
+	PRLoading bool
+}
+
+func findReusableOpenSlot(slots []OpenSlotState, branch string) (OpenSlotState, bool) {
+	want := strings.TrimSpace(branch)
+	for _, slot := range slots {
+		if strings.TrimSpace(slot.Branch) != want {
+			continue
+		}
+		if slot.Locked || slot.Dirty {
+			continue
+		}
+		return slot, true
+	}
+	return OpenSlotState{}, false
+}
+
+func findAnyAvailableOpenSlot(slots []OpenSlotState) (OpenSlotState, bool) {
+	for _, slot := range slots {
+		if slot.Locked || slot.Dirty {
+			continue
+		}
+		return slot, true
+	}
+	return OpenSlotState{}, false
+}
@@ -0,0 +1,22 @@
+package core
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestUserHomeDirFallsBackToUserProfileOnWindows(t *testing.T) {
+	profile := t.TempDir()
+	t.Setenv("HOME", "")
+	t.Setenv("USERPROFILE", profile)
+	home, err := UserHomeDir()
+	if runtime.GOOS != "windows" {
+		if err == nil {
+			t.Fatalf("expected HOME to be required, got %q", home)
+		}
+		return
+	}
+	if err != nil || home != profile {
+		t.Fatalf("expected USERPROFILE %q, got %q (%v)", profile, home, err)
+	}
+}
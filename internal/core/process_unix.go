@@ -0,0 +1,90 @@
+//go:build !windows
+
+package core
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+const TmuxSupported = true
+
+const LoginShellCommand = "exec \"${SHELL:-/bin/sh}\" -l"
+
+// homeEnvKeys are checked in order for the user's home directory.
+var homeEnvKeys = []string{"HOME"}
+
+func PIDAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	if err == nil {
+		return true
+	}
+	if errors.Is(err, syscall.EPERM) {
+		return true
+	}
+	return false
+}
+
+// DetachedProcAttr starts a child in its own process group so it outlives
+// wtx.
+func DetachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setpgid: true}
+}
+
+// ShellScriptCommand runs script with /bin/sh; login also loads the user's
+// profile so agents see the same PATH as an interactive shell.
+func ShellScriptCommand(script string, login bool) *exec.Cmd {
+	return shellScriptCommandContext(context.Background(), script, login)
+}
+
+func shellScriptCommandContext(ctx context.Context, script string, login bool) *exec.Cmd {
+	if login {
+		return exec.CommandContext(ctx, "/bin/sh", "-lc", script)
+	}
+	return exec.CommandContext(ctx, "/bin/sh", "-c", script)
+}
+
+// sameFilesystem reports whether a and b live on the same device.
+func sameFilesystem(a string, b string) (bool, error) {
+	infoA, err := os.Stat(a)
+	if err != nil {
+		return false, err
+	}
+	infoB, err := os.Stat(b)
+	if err != nil {
+		return false, err
+	}
+	statA, okA := infoA.Sys().(*syscall.Stat_t)
+	statB, okB := infoB.Sys().(*syscall.Stat_t)
+	if !okA || !okB {
+		return true, nil
+	}
+	return statA.Dev == statB.Dev, nil
+}
+
+// ListenPrivateSocket listens on a unix socket at path that only the user can
+// connect to. The umask makes the socket private from the moment it is bound,
+// where a chmod afterwards would leave a window for other users to connect.
+func ListenPrivateSocket(path string) (net.Listener, error) {
+	old := syscall.Umask(0o177)
+	listener, err := net.Listen("unix", path)
+	syscall.Umask(old)
+	return listener, err
+}
+
+// replaceFile atomically moves from over to.
+func replaceFile(from string, to string) error {
+	return os.Rename(from, to)
+}
+
+// ShellScriptQuote quotes an argument for scripts run by shellScriptCommand.
+func ShellScriptQuote(value string) string {
+	return ShellQuote(value)
+}
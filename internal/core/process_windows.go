@@ -0,0 +1,104 @@
+//go:build windows
+
+package core
+
+import (
+	"context"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const createNewProcessGroup = 0x00000200
+
+// tmux does not run natively on Windows; agents and shells run in the current
+// console instead.
+const TmuxSupported = false
+
+// cmd.exe has no login mode; an empty script starts an interactive shell.
+const LoginShellCommand = ""
+
+// HOME wins when set (Git for Windows shells export it) so wtx state matches
+// what git and gh see.
+var homeEnvKeys = []string{"HOME", "USERPROFILE"}
+
+func PIDAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	// FindProcess opens a handle on Windows and fails for exited processes.
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	_ = proc.Release()
+	return true
+}
+
+func DetachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{CreationFlags: createNewProcessGroup}
+}
+
+// ShellScriptCommand runs script with cmd.exe, or %ComSpec% when set. The
+// command line is passed raw: cmd.exe does not understand the backslash
+// escaping exec applies to arguments, and /S strips only the outer quotes.
+func ShellScriptCommand(script string, login bool) *exec.Cmd {
+	return shellScriptCommandContext(context.Background(), script, login)
+}
+
+func shellScriptCommandContext(ctx context.Context, script string, _ bool) *exec.Cmd {
+	comspec := strings.TrimSpace(os.Getenv("ComSpec"))
+	if comspec == "" {
+		comspec = "cmd.exe"
+	}
+	cmd := exec.CommandContext(ctx, comspec)
+	if strings.TrimSpace(script) != "" {
+		cmd.SysProcAttr = &syscall.SysProcAttr{CmdLine: syscall.EscapeArg(comspec) + ` /D /S /C "` + script + `"`}
+	}
+	return cmd
+}
+
+// sameFilesystem reports whether a and b are on the same drive or share.
+// Volumes mounted into folders are not detected.
+func sameFilesystem(a string, b string) (bool, error) {
+	absA, err := filepath.Abs(a)
+	if err != nil {
+		return false, err
+	}
+	absB, err := filepath.Abs(b)
+	if err != nil {
+		return false, err
+	}
+	return strings.EqualFold(filepath.VolumeName(absA), filepath.VolumeName(absB)), nil
+}
+
+// ListenPrivateSocket listens on a unix socket at path. Windows has no umask;
+// the socket inherits the ACL of the user's profile directory it lives in.
+func ListenPrivateSocket(path string) (net.Listener, error) {
+	return net.Listen("unix", path)
+}
+
+// replaceFile moves from over to, retrying briefly: Windows refuses to
+// replace a file another process has open, such as a lock being read by a
+// sibling wtx.
+func replaceFile(from string, to string) error {
+	var err error
+	for attempt := 0; attempt < 10; attempt++ {
+		if err = os.Rename(from, to); err == nil {
+			return nil
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return err
+}
+
+// ShellScriptQuote quotes an argument for cmd.exe scripts. Single quotes mean
+// nothing to cmd.exe; doubled double quotes survive the C runtime's argument
+// parsing that agent CLIs use.
+func ShellScriptQuote(value string) string {
+	return `"` + strings.ReplaceAll(value, `"`, `""`) + `"`
+}
@@ -0,0 +1,204 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const RepoConfigFileName = ".wtx.json"
+
+type RepoConfig struct {
+	AgentCommand     string            `json:"agent_command,omitempty"`
+	AgentProfile     string            `json:"agent_profile,omitempty"`
+	IDECommand       string            `json:"ide_command,omitempty"`
+	IDEWindow        string            `json:"ide_window,omitempty"`
+	GitUICommand     string            `json:"git_ui_command,omitempty"`
+	NewBranchBaseRef string            `json:"new_branch_base_ref,omitempty"`
+	UpdateStrategy   string            `json:"update_strategy,omitempty"`
+	BootstrapCommand string            `json:"bootstrap_command,omitempty"`
+	TestCommand      string            `json:"test_command,omitempty"`
+	LFSSkipSmudge    *bool             `json:"lfs_skip_smudge,omitempty"`
+	WorktreeDir      string            `json:"worktree_dir,omitempty"`
+	CopyFiles        []string          `json:"copy_files,omitempty"`
+	SyncFiles        []SyncFile        `json:"sync_files,omitempty"`
+	CustomActions    []CustomAction    `json:"custom_actions,omitempty"`
+	Hooks            map[string]string `json:"hooks,omitempty"`
+}
+
+func RepoConfigPath(dir string) (string, error) {
+	root, err := RepoRootForDir(dir, "git")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, RepoConfigFileName), nil
+}
+
+func loadRepoConfig(dir string) (RepoConfig, error) {
+	path, err := RepoConfigPath(dir)
+	if err != nil {
+		return RepoConfig{}, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return RepoConfig{}, nil
+		}
+		return RepoConfig{}, err
+	}
+	cfg, err := ParseRepoConfig(data)
+	if err != nil {
+		return RepoConfig{}, err
+	}
+	if keys := cfg.CommandKeys(); len(keys) > 0 && !repoConfigTrusted(path, data) {
+		DebugLog("untrusted repo config ignored", "path", path, "keys", strings.Join(keys, ","))
+		cfg = cfg.withoutCommands()
+	}
+	return cfg, nil
+}
+
+func ParseRepoConfig(data []byte) (RepoConfig, error) {
+	var cfg RepoConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return RepoConfig{}, fmt.Errorf("%s: %w", RepoConfigFileName, err)
+	}
+	cfg.AgentCommand = strings.TrimSpace(cfg.AgentCommand)
+	cfg.AgentProfile = strings.TrimSpace(cfg.AgentProfile)
+	cfg.IDECommand = strings.TrimSpace(cfg.IDECommand)
+	cfg.IDEWindow = strings.TrimSpace(cfg.IDEWindow)
+	cfg.GitUICommand = strings.TrimSpace(cfg.GitUICommand)
+	cfg.NewBranchBaseRef = strings.TrimSpace(cfg.NewBranchBaseRef)
+	cfg.UpdateStrategy = strings.TrimSpace(cfg.UpdateStrategy)
+	cfg.BootstrapCommand = strings.TrimSpace(cfg.BootstrapCommand)
+	cfg.TestCommand = strings.TrimSpace(cfg.TestCommand)
+	cfg.WorktreeDir = strings.TrimSpace(cfg.WorktreeDir)
+	cfg.CopyFiles = NormalizeCopyFiles(cfg.CopyFiles)
+	cfg.SyncFiles = normalizeSyncFiles(cfg.SyncFiles)
+	for i := range cfg.SyncFiles {
+		cfg.SyncFiles[i].fromRepo = true
+	}
+	cfg.CustomActions = normalizeCustomActions(cfg.CustomActions)
+	return cfg, nil
+}
+
+func gitConfigValue(dir string, key string) string {
+	if strings.TrimSpace(dir) == "" {
+		return ""
+	}
+	value, err := GitOutputInDir(dir, "git", "config", "--get", key)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(value)
+}
+
+// ApplyRepoConfig layers repo overrides on top of the global config.
+// Precedence: git config wtx.* > .wtx.json > ~/.wtx/config.json.
+func ApplyRepoConfig(cfg Config, dir string) (Config, error) {
+	repoCfg, err := loadRepoConfig(dir)
+	if err != nil {
+		return cfg, err
+	}
+	if repoCfg.AgentCommand != "" {
+		cfg.AgentCommand = repoCfg.AgentCommand
+	}
+	if repoCfg.AgentProfile != "" {
+		cfg.DefaultAgentProfile = repoCfg.AgentProfile
+	}
+	if repoCfg.IDECommand != "" {
+		cfg.IDECommand = repoCfg.IDECommand
+	}
+	if repoCfg.IDEWindow != "" {
+		cfg.IDEWindow = repoCfg.IDEWindow
+	}
+	if repoCfg.GitUICommand != "" {
+		cfg.GitUICommand = repoCfg.GitUICommand
+	}
+	if repoCfg.NewBranchBaseRef != "" {
+		cfg.NewBranchBaseRef = repoCfg.NewBranchBaseRef
+	}
+	if repoCfg.UpdateStrategy != "" {
+		cfg.UpdateStrategy = repoCfg.UpdateStrategy
+	}
+	if repoCfg.BootstrapCommand != "" {
+		cfg.BootstrapCommand = repoCfg.BootstrapCommand
+	}
+	if repoCfg.TestCommand != "" {
+		cfg.TestCommand = repoCfg.TestCommand
+	}
+	if repoCfg.LFSSkipSmudge != nil {
+		cfg.LFSSkipSmudge = *repoCfg.LFSSkipSmudge
+	}
+	if repoCfg.WorktreeDir != "" {
+		cfg.WorktreeDir = repoCfg.WorktreeDir
+	}
+	if len(repoCfg.CopyFiles) > 0 {
+		cfg.CopyFiles = repoCfg.CopyFiles
+	}
+	if len(repoCfg.SyncFiles) > 0 {
+		// The repo's rules run after the global ones, so they win on a
+		// shared destination.
+		cfg.SyncFiles = append(append([]SyncFile(nil), cfg.SyncFiles...), repoCfg.SyncFiles...)
+	}
+	if len(repoCfg.CustomActions) > 0 {
+		cfg.CustomActions = mergeCustomActions(cfg.CustomActions, repoCfg.CustomActions)
+	}
+	if len(repoCfg.Hooks) > 0 {
+		hooks := make(map[string]string, len(cfg.Hooks)+len(repoCfg.Hooks))
+		for event, command := range cfg.Hooks {
+			hooks[event] = command
+		}
+		for event, command := range repoCfg.Hooks {
+			hooks[event] = command
+		}
+		cfg.Hooks = hooks
+	}
+	if v := gitConfigValue(dir, "wtx.agent"); v != "" {
+		cfg.AgentCommand = v
+	}
+	if v := gitConfigValue(dir, "wtx.agentProfile"); v != "" {
+		cfg.DefaultAgentProfile = v
+	}
+	if v := gitConfigValue(dir, "wtx.ide"); v != "" {
+		cfg.IDECommand = v
+	}
+	if v := gitConfigValue(dir, "wtx.ideWindow"); v != "" {
+		cfg.IDEWindow = v
+	}
+	if v := gitConfigValue(dir, "wtx.baseRef"); v != "" {
+		cfg.NewBranchBaseRef = v
+	}
+	if v := gitConfigValue(dir, "wtx.updateStrategy"); v != "" {
+		cfg.UpdateStrategy = v
+	}
+	if v := gitConfigValue(dir, "wtx.bootstrap"); v != "" {
+		cfg.BootstrapCommand = v
+	}
+	if v := gitConfigValue(dir, "wtx.test"); v != "" {
+		cfg.TestCommand = v
+	}
+	if v, err := strconv.ParseBool(gitConfigValue(dir, "wtx.lfsSkipSmudge")); err == nil {
+		cfg.LFSSkipSmudge = v
+	}
+	if v := gitConfigValue(dir, "wtx.worktreeDir"); v != "" {
+		cfg.WorktreeDir = v
+	}
+	return cfg, nil
+}
+
+// RepoIDECommand returns the IDE command set for the repo containing dir, or
+// "" when only the global setting applies.
+func RepoIDECommand(dir string) (string, error) {
+	if v := gitConfigValue(dir, "wtx.ide"); v != "" {
+		return v, nil
+	}
+	repoCfg, err := loadRepoConfig(dir)
+	if err != nil {
+		return "", err
+	}
+	return repoCfg.IDECommand, nil
+}
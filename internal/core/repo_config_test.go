@@ -0,0 +1,97 @@
+package core
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func initTestRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "init", dir).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	return dir
+}
+
+// writeTrustedRepoConfig writes the repo's .wtx.json and approves it, as
+// `wtx config trust` would.
+func writeTrustedRepoConfig(t *testing.T, repo string, content string) {
+	t.Helper()
+	path := filepath.Join(repo, RepoConfigFileName)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write repo config: %v", err)
+	}
+	if err := TrustRepoConfigData(path, []byte(content)); err != nil {
+		t.Fatalf("trust repo config: %v", err)
+	}
+}
+
+func TestApplyRepoConfig_Precedence(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo := initTestRepo(t)
+	cfg := Config{AgentCommand: "claude"}
+
+	got, err := ApplyRepoConfig(cfg, repo)
+	if err != nil {
+		t.Fatalf("applyRepoConfig: %v", err)
+	}
+	if got.AgentCommand != "claude" {
+		t.Fatalf("expected global agent without overrides, got %q", got.AgentCommand)
+	}
+
+	writeTrustedRepoConfig(t, repo, `{"agent_command": " codex "}`)
+	got, err = ApplyRepoConfig(cfg, repo)
+	if err != nil {
+		t.Fatalf("applyRepoConfig: %v", err)
+	}
+	if got.AgentCommand != "codex" {
+		t.Fatalf("expected .wtx.json agent, got %q", got.AgentCommand)
+	}
+
+	if out, err := exec.Command("git", "-C", repo, "config", "wtx.agent", "gemini").CombinedOutput(); err != nil {
+		t.Fatalf("git config: %v\n%s", err, out)
+	}
+	got, err = ApplyRepoConfig(cfg, repo)
+	if err != nil {
+		t.Fatalf("applyRepoConfig: %v", err)
+	}
+	if got.AgentCommand != "gemini" {
+		t.Fatalf("expected git config agent, got %q", got.AgentCommand)
+	}
+}
+
+func TestApplyRepoConfig_InvalidJSON(t *testing.T) {
+	repo := initTestRepo(t)
+	if err := os.WriteFile(filepath.Join(repo, RepoConfigFileName), []byte(`{`), 0o644); err != nil {
+		t.Fatalf("write repo config: %v", err)
+	}
+	if _, err := ApplyRepoConfig(Config{}, repo); err == nil {
+		t.Fatalf("expected invalid %s to fail", RepoConfigFileName)
+	}
+}
+
+func TestApplyRepoConfig_WorktreeSettings(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo := initTestRepo(t)
+	writeTrustedRepoConfig(t, repo, `{"new_branch_base_ref": "origin/develop", "bootstrap_command": "make setup", "copy_files": [" .env ", ""]}`)
+	cfg := Config{NewBranchBaseRef: "origin/main", WorktreeDir: "/tmp/wt"}
+	got, err := ApplyRepoConfig(cfg, repo)
+	if err != nil {
+		t.Fatalf("applyRepoConfig: %v", err)
+	}
+	if got.NewBranchBaseRef != "origin/develop" {
+		t.Fatalf("expected repo base ref, got %q", got.NewBranchBaseRef)
+	}
+	if got.BootstrapCommand != "make setup" {
+		t.Fatalf("expected repo bootstrap command, got %q", got.BootstrapCommand)
+	}
+	if got.WorktreeDir != "/tmp/wt" {
+		t.Fatalf("expected global worktree dir to survive, got %q", got.WorktreeDir)
+	}
+	if len(got.CopyFiles) != 1 || got.CopyFiles[0] != ".env" {
+		t.Fatalf("expected copy_files [.env], got %v", got.CopyFiles)
+	}
+}
@@ -0,0 +1,152 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// A .wtx.json comes with the repo, so a cloned repo could use it to run
+// anything. Its command keys apply only once the user has approved that exact
+// file content for the repo with `wtx config trust`.
+
+type trustedRepoConfigsFile struct {
+	// Repos maps a repo's main checkout to the hashes of the approved
+	// .wtx.json contents, so branches with different configs can each be
+	// approved.
+	Repos map[string][]string `json:"repos"`
+}
+
+// CommandKeys lists the keys set in c that run commands or read and write
+// files outside the worktree.
+func (c RepoConfig) CommandKeys() []string {
+	var keys []string
+	add := func(set bool, key string) {
+		if set {
+			keys = append(keys, key)
+		}
+	}
+	add(c.AgentCommand != "", "agent_command")
+	add(c.IDECommand != "", "ide_command")
+	add(c.GitUICommand != "", "git_ui_command")
+	add(c.BootstrapCommand != "", "bootstrap_command")
+	add(c.TestCommand != "", "test_command")
+	add(c.WorktreeDir != "", "worktree_dir")
+	add(len(c.SyncFiles) > 0, "sync_files")
+	add(len(c.CustomActions) > 0, "custom_actions")
+	add(len(c.Hooks) > 0, "hooks")
+	return keys
+}
+
+func (c RepoConfig) withoutCommands() RepoConfig {
+	c.AgentCommand = ""
+	c.IDECommand = ""
+	c.GitUICommand = ""
+	c.BootstrapCommand = ""
+	c.TestCommand = ""
+	c.WorktreeDir = ""
+	c.SyncFiles = nil
+	c.CustomActions = nil
+	c.Hooks = nil
+	return c
+}
+
+func trustedRepoConfigsPath() (string, error) {
+	home, err := WtxHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "trusted_repo_configs.json"), nil
+}
+
+// repoTrustKey is the main checkout of the repo holding the .wtx.json at
+// path, so one approval covers every worktree with the same content.
+func repoTrustKey(path string) string {
+	root := WorktreeLayoutRoot(filepath.Dir(path), "git")
+	if real, err := RealPathOrAbs(root); err == nil {
+		return real
+	}
+	return filepath.Clean(root)
+}
+
+func repoConfigTrusted(path string, data []byte) bool {
+	storePath, err := trustedRepoConfigsPath()
+	if err != nil {
+		return false
+	}
+	var file trustedRepoConfigsFile
+	if err := ReadJSONStore(storePath, &file); err != nil {
+		DebugLog("trusted repo configs unreadable", "err", err.Error())
+		return false
+	}
+	return slices.Contains(file.Repos[repoTrustKey(path)], HashString(string(data)))
+}
+
+func TrustRepoConfigData(path string, data []byte) error {
+	storePath, err := trustedRepoConfigsPath()
+	if err != nil {
+		return err
+	}
+	key := repoTrustKey(path)
+	hash := HashString(string(data))
+	var file trustedRepoConfigsFile
+	return UpdateJSONStore(storePath, &file, func() (bool, error) {
+		if slices.Contains(file.Repos[key], hash) {
+			return false, nil
+		}
+		if file.Repos == nil {
+			file.Repos = map[string][]string{}
+		}
+		file.Repos[key] = append(file.Repos[key], hash)
+		return true, nil
+	})
+}
+
+// RepoConfigFileTrusted reports whether the file at path may run commands:
+// it is missing, sets no command keys, or has been approved.
+func RepoConfigFileTrusted(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return errors.Is(err, os.ErrNotExist)
+	}
+	cfg, err := ParseRepoConfig(data)
+	if err != nil {
+		return false
+	}
+	return len(cfg.CommandKeys()) == 0 || repoConfigTrusted(path, data)
+}
+
+// UntrustedRepoConfigKeys returns the command keys the .wtx.json of the repo
+// containing dir sets without being trusted, and the file's path.
+func UntrustedRepoConfigKeys(dir string) (string, []string) {
+	path, err := RepoConfigPath(dir)
+	if err != nil {
+		return "", nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil
+	}
+	cfg, err := ParseRepoConfig(data)
+	if err != nil {
+		return "", nil
+	}
+	keys := cfg.CommandKeys()
+	if len(keys) == 0 || repoConfigTrusted(path, data) {
+		return "", nil
+	}
+	return path, keys
+}
+
+// UntrustedRepoConfigWarning tells API callers, which have no integrations
+// footer, that a new worktree was set up without the repo's commands.
+func UntrustedRepoConfigWarning(dir string) string {
+	path, keys := UntrustedRepoConfigKeys(dir)
+	if len(keys) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s is not trusted, so its %s did not apply; the user can approve it with wtx config trust", path, strings.Join(keys, ", "))
+}
@@ -0,0 +1,146 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type SavedSession struct {
+	RepoRoot     string `json:"repo_root"`
+	WorktreePath string `json:"worktree_path"`
+	Branch       string `json:"branch"`
+	Layout       string `json:"layout"`
+	AgentCommand string `json:"agent_command"`
+	SavedAtUnix  int64  `json:"saved_at_unix"`
+}
+
+type SavedSessionsFile struct {
+	Sessions []SavedSession `json:"sessions"`
+}
+
+// PruneSavedSessions drops sessions whose worktree no longer exists and
+// returns the rest along with the worktree paths it dropped.
+func PruneSavedSessions() ([]SavedSession, []string, error) {
+	path, err := SavedSessionsPath()
+	if err != nil {
+		return nil, nil, err
+	}
+	var file SavedSessionsFile
+	var kept []SavedSession
+	var dropped []string
+	err = UpdateJSONStore(path, &file, func() (bool, error) {
+		kept = make([]SavedSession, 0, len(file.Sessions))
+		dropped = nil
+		for _, s := range validSavedSessions(file.Sessions) {
+			if info, err := os.Stat(s.WorktreePath); err != nil || !info.IsDir() {
+				dropped = append(dropped, s.WorktreePath)
+				continue
+			}
+			kept = append(kept, s)
+		}
+		if len(kept) == len(file.Sessions) {
+			return false, nil
+		}
+		file.Sessions = kept
+		return true, nil
+	})
+	return kept, dropped, err
+}
+
+// RememberSession saves the agent running in worktreePath so wtx resume can
+// start it again. agentCommand is the base command; a one-off prompt is never
+// saved, so resuming does not send the original task twice.
+func RememberSession(worktreePath string, branch string, layout string, agentCommand string) error {
+	worktreePath = strings.TrimSpace(worktreePath)
+	agentCommand = strings.TrimSpace(agentCommand)
+	if worktreePath == "" || agentCommand == "" {
+		return nil
+	}
+	_, repoRoot, err := RequireGitContext(worktreePath)
+	if err != nil {
+		return err
+	}
+	path, err := SavedSessionsPath()
+	if err != nil {
+		return err
+	}
+	var file SavedSessionsFile
+	return UpdateJSONStore(path, &file, func() (bool, error) {
+		next := make([]SavedSession, 0, len(file.Sessions)+1)
+		for _, s := range file.Sessions {
+			if SameSessionPath(s.WorktreePath, worktreePath) {
+				continue
+			}
+			next = append(next, s)
+		}
+		file.Sessions = append(next, SavedSession{
+			RepoRoot:     repoRoot,
+			WorktreePath: worktreePath,
+			Branch:       strings.TrimSpace(branch),
+			Layout:       layout,
+			AgentCommand: agentCommand,
+			SavedAtUnix:  time.Now().Unix(),
+		})
+		return true, nil
+	})
+}
+
+func ForgetSession(worktreePath string) error {
+	path, err := SavedSessionsPath()
+	if err != nil {
+		return err
+	}
+	var file SavedSessionsFile
+	return UpdateJSONStore(path, &file, func() (bool, error) {
+		next := make([]SavedSession, 0, len(file.Sessions))
+		for _, s := range file.Sessions {
+			if SameSessionPath(s.WorktreePath, worktreePath) {
+				continue
+			}
+			next = append(next, s)
+		}
+		if len(next) == len(file.Sessions) {
+			return false, nil
+		}
+		file.Sessions = next
+		return true, nil
+	})
+}
+
+func SameSessionPath(a string, b string) bool {
+	return filepath.Clean(strings.TrimSpace(a)) == filepath.Clean(strings.TrimSpace(b))
+}
+
+func ReadSavedSessions() ([]SavedSession, error) {
+	path, err := SavedSessionsPath()
+	if err != nil {
+		return nil, err
+	}
+	var file SavedSessionsFile
+	if err := ReadJSONStore(path, &file); err != nil {
+		return nil, err
+	}
+	return validSavedSessions(file.Sessions), nil
+}
+
+func validSavedSessions(sessions []SavedSession) []SavedSession {
+	out := make([]SavedSession, 0, len(sessions))
+	for _, s := range sessions {
+		s.WorktreePath = strings.TrimSpace(s.WorktreePath)
+		if s.WorktreePath == "" || strings.TrimSpace(s.AgentCommand) == "" {
+			continue
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+func SavedSessionsPath() (string, error) {
+	home, err := WtxHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "sessions.json"), nil
+}
@@ -0,0 +1,32 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestSessions(t *testing.T, sessions ...SavedSession) {
+	t.Helper()
+	path, err := SavedSessionsPath()
+	if err != nil {
+		t.Fatalf("sessions path: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := WriteJSONStore(path, SavedSessionsFile{Sessions: sessions}); err != nil {
+		t.Fatalf("write sessions: %v", err)
+	}
+}
+
+func TestReadSavedSessionsMissingFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	sessions, err := ReadSavedSessions()
+	if err != nil {
+		t.Fatalf("read sessions: %v", err)
+	}
+	if len(sessions) != 0 {
+		t.Fatalf("expected no sessions, got %d", len(sessions))
+	}
+}
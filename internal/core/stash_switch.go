@@ -0,0 +1,110 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DirtyCheckoutError is a branch switch git refused because it would
+// overwrite local changes. It carries the switch so it can be retried with
+// the changes stashed.
+type DirtyCheckoutError struct {
+	Path      string
+	Branch    string
+	BaseRef   string
+	NewBranch bool
+	Files     []string
+	Err       error
+}
+
+func (e *DirtyCheckoutError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *DirtyCheckoutError) Unwrap() error {
+	return e.Err
+}
+
+// stashPopConflictError reports a switch that worked but whose stashed
+// changes did not apply cleanly. Git keeps the stash until it is dropped.
+type stashPopConflictError struct {
+	Branch string
+	Files  []string
+}
+
+func (e *stashPopConflictError) Error() string {
+	return fmt.Sprintf("switched to %s, but your stashed changes conflict in %s; resolve them, then run git stash drop",
+		e.Branch, strings.Join(e.Files, ", "))
+}
+
+// asDirtyCheckout wraps err from a checkout in path as a dirtyCheckoutError
+// when git refused it over local changes.
+func asDirtyCheckout(err error, path string, branch string, baseRef string, newBranch bool) error {
+	var cmdErr *CommandError
+	if !errors.As(err, &cmdErr) {
+		return err
+	}
+	files, ok := overwrittenFiles(cmdErr.Output)
+	if !ok {
+		return err
+	}
+	return &DirtyCheckoutError{Path: path, Branch: branch, BaseRef: baseRef, NewBranch: newBranch, Files: files, Err: err}
+}
+
+// overwrittenFiles parses the files git lists when a checkout would
+// overwrite tracked or untracked local changes.
+func overwrittenFiles(output string) ([]string, bool) {
+	found := false
+	listing := false
+	var files []string
+	for _, line := range strings.Split(output, "\n") {
+		switch {
+		case strings.Contains(line, "would be overwritten by checkout:"):
+			found = true
+			listing = true
+		case listing && strings.HasPrefix(line, "\t"):
+			files = append(files, strings.TrimSpace(line))
+		default:
+			listing = false
+		}
+	}
+	return files, found
+}
+
+// StashAndCheckout retries a refused switch: it stashes the worktree's
+// changes, including untracked files, switches and pops them back. When the
+// switch still fails the stash is popped onto the original branch.
+func (m *WorktreeManager) StashAndCheckout(dirty *DirtyCheckoutError, progress io.Writer) error {
+	gitPath, _, err := RequireGitContext(m.Cwd)
+	if err != nil {
+		return err
+	}
+	path := dirty.Path
+	before, _ := GitOutputInDir(path, gitPath, "rev-parse", "-q", "--verify", "refs/stash")
+	if err := runStreamedInDir(path, gitPath, progress, "stash", "push", "--include-untracked", "-m", "wtx: switch to "+dirty.Branch); err != nil {
+		return err
+	}
+	// With nothing left to stash, popping would apply an older stash.
+	if after, _ := GitOutputInDir(path, gitPath, "rev-parse", "-q", "--verify", "refs/stash"); after == before {
+		return errors.New("git stash saved nothing; the local changes are gone or ignored")
+	}
+	var switchErr error
+	if dirty.NewBranch {
+		switchErr = m.CheckoutNewBranch(path, dirty.Branch, dirty.BaseRef, false, progress)
+	} else {
+		switchErr = m.CheckoutExistingBranch(path, dirty.Branch, progress)
+	}
+	popErr := runStreamedInDir(path, gitPath, progress, "stash", "pop")
+	if switchErr != nil {
+		return switchErr
+	}
+	if popErr != nil {
+		if files := conflictedFiles(path, gitPath); len(files) > 0 {
+			return &stashPopConflictError{Branch: dirty.Branch, Files: files}
+		}
+		return popErr
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package core
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStashAndCheckoutCarriesLocalChanges(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo := initRenameTestRepo(t)
+	mgr := NewWorktreeManager(repo, NewLockManager())
+	info, err := mgr.CreateWorktree("feature", "", nil)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	write := func(name string, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(info.Path, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	lines := "1\n2\n3\n4\n5\n6\n7\n8\n"
+	write("README.md", lines)
+	runGitInRepo(t, info.Path, "commit", "-am", "lines")
+	runGitInRepo(t, info.Path, "checkout", "-b", "other")
+	write("README.md", "one\n"+lines[2:])
+	runGitInRepo(t, info.Path, "commit", "-am", "other")
+	runGitInRepo(t, info.Path, "checkout", "feature")
+
+	write("README.md", lines[:len(lines)-2]+"eight\n")
+	err = mgr.CheckoutExistingBranch(info.Path, "other", nil)
+	var dirty *DirtyCheckoutError
+	if !errors.As(err, &dirty) {
+		t.Fatalf("expected a dirty checkout error, got %v", err)
+	}
+	if want := []string{"README.md"}; !reflect.DeepEqual(dirty.Files, want) {
+		t.Fatalf("files = %v, want %v", dirty.Files, want)
+	}
+	if err := mgr.StashAndCheckout(dirty, nil); err != nil {
+		t.Fatalf("stash and checkout: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(info.Path, "README.md"))
+	if err != nil || !strings.HasPrefix(string(data), "one\n") || !strings.HasSuffix(string(data), "eight\n") {
+		t.Fatalf("expected the local change on top of other, got %q (%v)", data, err)
+	}
+	runGitInRepo(t, info.Path, "checkout", "--", ".")
+
+	write("README.md", "local\n")
+	err = mgr.CheckoutNewBranch(info.Path, "fresh", "feature", false, nil)
+	if !errors.As(err, &dirty) || !dirty.NewBranch {
+		t.Fatalf("expected a dirty checkout error for the new branch, got %v", err)
+	}
+	err = mgr.StashAndCheckout(dirty, nil)
+	var conflict *stashPopConflictError
+	if !errors.As(err, &conflict) || !reflect.DeepEqual(conflict.Files, []string{"README.md"}) {
+		t.Fatalf("expected the pop to conflict on README.md, got %v", err)
+	}
+	if branch := CurrentBranchInWorktree(info.Path); branch != "fresh" {
+		t.Fatalf("expected the switch to go through, on %q", branch)
+	}
+}
@@ -0,0 +1,161 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// statusRefreshCache lets repeated Status calls skip work for worktrees whose
+// files have not changed since the previous poll.
+type statusRefreshCache struct {
+	mu         sync.Mutex
+	repoRoot   string
+	checks     map[string]cachedWorktreeCheck
+	commitsKey string
+	commits    map[string]BranchCommit
+}
+
+type cachedWorktreeCheck struct {
+	fingerprint worktreeFingerprint
+	check       worktreeCheck
+}
+
+type fileStamp struct {
+	Exists  bool
+	Size    int64
+	ModNano int64
+}
+
+// worktreeFingerprint covers every file a worktree check reads: the worktree
+// itself, its HEAD and admin gitdir, the wtx lock, last-used marker and agent
+// state.
+type worktreeFingerprint [6]fileStamp
+
+func statFileStamp(path string) fileStamp {
+	if path == "" {
+		return fileStamp{}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{Exists: true, Size: info.Size(), ModNano: info.ModTime().UnixNano()}
+}
+
+func (o *WorktreeOrchestrator) worktreeFingerprint(repoRoot string, path string) worktreeFingerprint {
+	var headPath, adminPath, lockPath, lastUsedPath, agentPath string
+	if gitDir, _, err := ResolveGitDirs(path); err == nil {
+		headPath = filepath.Join(gitDir, "HEAD")
+		adminPath = filepath.Join(gitDir, "gitdir")
+	}
+	lockPath, _ = o.LockMgr.LockPath(repoRoot, path)
+	lastUsedPath, _ = worktreeLastUsedPath(repoRoot, path)
+	agentPath, _ = TmuxAgentStatePath(path)
+	return worktreeFingerprint{
+		statFileStamp(path),
+		statFileStamp(headPath),
+		statFileStamp(adminPath),
+		statFileStamp(lockPath),
+		statFileStamp(lastUsedPath),
+		statFileStamp(agentPath),
+	}
+}
+
+// cachedCheck returns the previous check for path if nothing it depends on
+// changed. Locked worktrees are always rechecked because the holder can exit
+// without touching any file.
+func (c *statusRefreshCache) cachedCheck(repoRoot string, path string, fp worktreeFingerprint) (worktreeCheck, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.repoRoot != repoRoot {
+		return worktreeCheck{}, false
+	}
+	cached, ok := c.checks[path]
+	if !ok || cached.fingerprint != fp || !cached.check.Exists || !cached.check.Available {
+		return worktreeCheck{}, false
+	}
+	return cached.check, true
+}
+
+func (c *statusRefreshCache) storeChecks(repoRoot string, paths []string, fps []worktreeFingerprint, checks []worktreeCheck) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.repoRoot != repoRoot {
+		c.repoRoot = repoRoot
+		c.commitsKey = ""
+		c.commits = nil
+	}
+	next := make(map[string]cachedWorktreeCheck, len(paths))
+	for i, path := range paths {
+		if checks[i].Err != nil {
+			continue
+		}
+		next[path] = cachedWorktreeCheck{fingerprint: fps[i], check: checks[i]}
+	}
+	c.checks = next
+}
+
+// branchCommits reuses the last for-each-ref result while no ref has moved.
+func (c *statusRefreshCache) branchCommits(repoRoot string, load func() (map[string]BranchCommit, error)) map[string]BranchCommit {
+	key, keyErr := refsFingerprint(repoRoot)
+	c.mu.Lock()
+	if keyErr == nil && c.repoRoot == repoRoot && c.commits != nil && c.commitsKey == key {
+		commits := c.commits
+		c.mu.Unlock()
+		return commits
+	}
+	c.mu.Unlock()
+
+	commits, err := load()
+	if err != nil {
+		return nil
+	}
+	c.mu.Lock()
+	if keyErr == nil && c.repoRoot == repoRoot {
+		c.commitsKey = key
+		c.commits = commits
+	}
+	c.mu.Unlock()
+	return commits
+}
+
+// refsFingerprint hashes the name and target of every branch and remote
+// tracking ref, which is enough to notice commits, pushes and fetches. The
+// repo config is included since it holds upstream settings.
+func refsFingerprint(repoRoot string) (string, error) {
+	repo, err := openGitRepo(repoRoot)
+	if err != nil {
+		return "", err
+	}
+	refs, err := repo.References()
+	if err != nil {
+		return "", err
+	}
+	lines := []string{}
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Name().IsBranch() || ref.Name().IsRemote() {
+			lines = append(lines, ref.Name().String()+" "+ref.Hash().String())
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(lines)
+	if _, commonDir, err := ResolveGitDirs(repoRoot); err == nil {
+		stamp := statFileStamp(filepath.Join(commonDir, "config"))
+		lines = append(lines, fmt.Sprintf("config %d %d", stamp.Size, stamp.ModNano))
+	}
+	sum := sha256.New()
+	for _, line := range lines {
+		sum.Write([]byte(line + "\n"))
+	}
+	return hex.EncodeToString(sum.Sum(nil)), nil
+}
@@ -0,0 +1,118 @@
+package core
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func CurrentSessionID() (string, error) {
+	out, err := TmuxOutput("display-message", "-p", "#{session_id}")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func currentWindowID() (string, error) {
+	out, err := TmuxOutput("display-message", "-p", "#{window_id}")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func ShellQuote(value string) string {
+	if value == "" {
+		return "''"
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+type TmuxAgentState struct {
+	State         string `json:"state"`
+	ExitCode      int    `json:"exit_code"`
+	StartedAtUnix int64  `json:"started_at_unix,omitempty"`
+	ExitedAtUnix  int64  `json:"exited_at_unix"`
+	AgentCommand  string `json:"agent_command,omitempty"`
+}
+
+// FormatAgentExit renders an exited agent as "exited 0, 42m"; the duration is
+// omitted when the start time was not recorded.
+func FormatAgentExit(state TmuxAgentState) string {
+	if !strings.EqualFold(strings.TrimSpace(state.State), "exited") {
+		return ""
+	}
+	label := "exited " + strconv.Itoa(state.ExitCode)
+	if state.StartedAtUnix > 0 && state.ExitedAtUnix >= state.StartedAtUnix {
+		label += ", " + FormatAgentDuration(time.Duration(state.ExitedAtUnix-state.StartedAtUnix)*time.Second)
+	}
+	return label
+}
+
+func FormatAgentDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return strconv.Itoa(int(d.Seconds())) + "s"
+	case d < time.Hour:
+		return strconv.Itoa(int(d.Minutes())) + "m"
+	default:
+		hours := int(d.Hours())
+		minutes := int(d.Minutes()) - hours*60
+		if minutes == 0 {
+			return strconv.Itoa(hours) + "h"
+		}
+		return strconv.Itoa(hours) + "h" + strconv.Itoa(minutes) + "m"
+	}
+}
+
+func ReadTmuxAgentState(worktreePath string) (TmuxAgentState, bool) {
+	path, err := TmuxAgentStatePath(worktreePath)
+	if err != nil {
+		return TmuxAgentState{}, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return TmuxAgentState{}, false
+	}
+	var state TmuxAgentState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return TmuxAgentState{}, false
+	}
+	return state, true
+}
+
+func TmuxAgentStatePath(worktreePath string) (string, error) {
+	worktreePath = strings.TrimSpace(worktreePath)
+	if worktreePath == "" {
+		return "", os.ErrInvalid
+	}
+	repoRoot, err := RepoRootForDir(worktreePath, "git")
+	if err != nil {
+		return "", err
+	}
+	worktreeID, err := worktreeID(repoRoot, worktreePath)
+	if err != nil {
+		return "", err
+	}
+	home, err := UserHomeDir()
+	if err != nil {
+		return "", os.ErrNotExist
+	}
+	return filepath.Join(home, ".wtx", "agent-state", worktreeID+".json"), nil
+}
+
+func FileLooksExecutable(path string) bool {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return false
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	return info.Mode()&0o111 != 0
+}
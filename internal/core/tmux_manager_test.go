@@ -0,0 +1,23 @@
+package core
+
+import "testing"
+
+func TestFormatAgentExit(t *testing.T) {
+	cases := []struct {
+		name  string
+		state TmuxAgentState
+		want  string
+	}{
+		{name: "running", state: TmuxAgentState{State: "running"}, want: ""},
+		{name: "no start time", state: TmuxAgentState{State: "exited", ExitCode: 1, ExitedAtUnix: 100}, want: "exited 1"},
+		{name: "minutes", state: TmuxAgentState{State: "exited", StartedAtUnix: 100, ExitedAtUnix: 100 + 42*60}, want: "exited 0, 42m"},
+		{name: "hours", state: TmuxAgentState{State: "exited", StartedAtUnix: 1, ExitedAtUnix: 1 + 90*60}, want: "exited 0, 1h30m"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := FormatAgentExit(tc.state); got != tc.want {
+				t.Fatalf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
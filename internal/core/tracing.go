@@ -0,0 +1,363 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// otelEnv turns on tracing. The exporter honours the standard OTLP variables
+// for where to send spans; only OTLP/HTTP with JSON bodies is supported.
+const otelEnv = "WTX_OTEL"
+
+const (
+	defaultOTLPTracesEndpoint = "http://localhost:4318/v1/traces"
+	traceFlushInterval        = 5 * time.Second
+	traceExportTimeout        = 3 * time.Second
+	traceMaxPending           = 512
+)
+
+// OTLP span kinds.
+const (
+	spanKindInternal = 1
+	spanKindClient   = 3
+)
+
+// activeTracer is nil unless WTX_OTEL is set, so instrumented code costs one
+// load otherwise.
+var activeTracer atomic.Pointer[tracer]
+
+type tracer struct {
+	endpoint string
+	headers  map[string]string
+	client   *http.Client
+	service  string
+	traceID  string
+	root     finishedSpan
+
+	mu      sync.Mutex
+	pending []finishedSpan
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+type finishedSpan struct {
+	traceID  string
+	spanID   string
+	parentID string
+	name     string
+	kind     int
+	start    time.Time
+	end      time.Time
+	attrs    []string
+	err      string
+}
+
+func otelEnabled() bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(otelEnv))) {
+	case "", "0", "false", "off", "no":
+		return false
+	default:
+		return true
+	}
+}
+
+func otlpTracesEndpoint() string {
+	if endpoint := strings.TrimSpace(os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")); endpoint != "" {
+		return endpoint
+	}
+	if endpoint := strings.TrimSpace(os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")); endpoint != "" {
+		return strings.TrimRight(endpoint, "/") + "/v1/traces"
+	}
+	return defaultOTLPTracesEndpoint
+}
+
+// otlpHeaders parses OTEL_EXPORTER_OTLP_HEADERS ("k1=v1,k2=v2").
+func otlpHeaders() map[string]string {
+	headers := map[string]string{}
+	for _, key := range []string{"OTEL_EXPORTER_OTLP_HEADERS", "OTEL_EXPORTER_OTLP_TRACES_HEADERS"} {
+		for _, pair := range strings.Split(os.Getenv(key), ",") {
+			name, value, ok := strings.Cut(pair, "=")
+			if name = strings.TrimSpace(name); ok && name != "" {
+				headers[name] = strings.TrimSpace(value)
+			}
+		}
+	}
+	return headers
+}
+
+// EnableTracing starts the span for this invocation. A TRACEPARENT from the
+// environment makes it a child of the caller's trace, and TRACEPARENT is then
+// pointed at this span so wtx processes started from here nest under it.
+func EnableTracing(args []string) {
+	if !otelEnabled() {
+		return
+	}
+	service := strings.TrimSpace(os.Getenv("OTEL_SERVICE_NAME"))
+	if service == "" {
+		service = "wtx"
+	}
+	t := &tracer{
+		endpoint: otlpTracesEndpoint(),
+		headers:  otlpHeaders(),
+		client:   &http.Client{Timeout: traceExportTimeout},
+		service:  service,
+		stop:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	parentID := ""
+	if traceID, spanID, ok := parseTraceparent(os.Getenv("TRACEPARENT")); ok {
+		t.traceID, parentID = traceID, spanID
+	} else {
+		t.traceID = randomHex(16)
+	}
+	name := "wtx"
+	if len(args) > 1 && !strings.HasPrefix(args[1], "-") {
+		name += " " + args[1]
+	}
+	t.root = finishedSpan{
+		traceID:  t.traceID,
+		spanID:   randomHex(8),
+		parentID: parentID,
+		name:     name,
+		kind:     spanKindInternal,
+		start:    time.Now(),
+		attrs:    []string{"wtx.args", strings.Join(args, " ")},
+	}
+	_ = os.Setenv("TRACEPARENT", "00-"+t.traceID+"-"+t.root.spanID+"-01")
+	activeTracer.Store(t)
+	go t.flushLoop()
+}
+
+// FinishTracing ends the invocation span and sends whatever is left.
+func FinishTracing(err error) {
+	t := activeTracer.Swap(nil)
+	if t == nil {
+		return
+	}
+	close(t.stop)
+	<-t.stopped
+	t.root.end = time.Now()
+	if err != nil {
+		t.root.err = err.Error()
+	}
+	t.mu.Lock()
+	t.pending = append(t.pending, t.root)
+	t.mu.Unlock()
+	t.flush()
+}
+
+func parseTraceparent(value string) (string, string, bool) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) != 4 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return "", "", false
+	}
+	if _, err := hex.DecodeString(parts[1] + parts[2]); err != nil {
+		return "", "", false
+	}
+	return strings.ToLower(parts[1]), strings.ToLower(parts[2]), true
+}
+
+func randomHex(n int) string {
+	buf := make([]byte, n)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// StartSpan opens a span under the invocation span; call the result with the
+// outcome when the work ends. attrs are key, value pairs.
+func StartSpan(name string, attrs ...string) func(error) {
+	t := activeTracer.Load()
+	if t == nil {
+		return func(error) {}
+	}
+	start := time.Now()
+	return func(err error) {
+		t.record(name, spanKindInternal, start, time.Now(), attrs, err)
+	}
+}
+
+func traceCommand(spec CommandSpec, start time.Time, elapsed time.Duration, err error) {
+	t := activeTracer.Load()
+	if t == nil {
+		return
+	}
+	attrs := []string{
+		"process.command_args", strings.Join(append([]string{spec.Name}, spec.Args...), " "),
+		"process.working_directory", spec.Dir,
+		"process.exit.code", strconv.Itoa(commandExitCode(err)),
+	}
+	t.record(commandTimingLabel(spec), spanKindClient, start, start.Add(elapsed), attrs, err)
+}
+
+func (t *tracer) record(name string, kind int, start time.Time, end time.Time, attrs []string, err error) {
+	span := finishedSpan{
+		traceID:  t.traceID,
+		spanID:   randomHex(8),
+		parentID: t.root.spanID,
+		name:     name,
+		kind:     kind,
+		start:    start,
+		end:      end,
+		attrs:    attrs,
+	}
+	if err != nil {
+		span.err = err.Error()
+	}
+	t.mu.Lock()
+	if len(t.pending) < traceMaxPending {
+		t.pending = append(t.pending, span)
+	}
+	t.mu.Unlock()
+}
+
+func (t *tracer) flushLoop() {
+	defer close(t.stopped)
+	ticker := time.NewTicker(traceFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-t.stop:
+			return
+		case <-ticker.C:
+			t.flush()
+		}
+	}
+}
+
+// flush posts pending spans. Export failures only reach the debug log:
+// tracing must never get in the way of the command being traced.
+func (t *tracer) flush() {
+	t.mu.Lock()
+	spans := t.pending
+	t.pending = nil
+	t.mu.Unlock()
+	if len(spans) == 0 {
+		return
+	}
+	body, err := json.Marshal(t.exportRequest(spans))
+	if err != nil {
+		DebugLog("trace export failed", "err", err.Error())
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), traceExportTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		DebugLog("trace export failed", "err", err.Error())
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range t.headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		DebugLog("trace export failed", "endpoint", t.endpoint, "err", err.Error())
+		return
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		DebugLog("trace export failed", "endpoint", t.endpoint, "status", resp.StatusCode)
+	}
+}
+
+// The otlp* types are the OTLP/HTTP JSON encoding of an export request.
+type otlpExportRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+func otlpAttributes(pairs ...string) []otlpKeyValue {
+	attrs := make([]otlpKeyValue, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		attrs = append(attrs, otlpKeyValue{Key: pairs[i], Value: otlpValue{StringValue: pairs[i+1]}})
+	}
+	return attrs
+}
+
+func (t *tracer) exportRequest(spans []finishedSpan) otlpExportRequest {
+	out := make([]otlpSpan, 0, len(spans))
+	for _, s := range spans {
+		span := otlpSpan{
+			TraceID:           s.traceID,
+			SpanID:            s.spanID,
+			ParentSpanID:      s.parentID,
+			Name:              s.name,
+			Kind:              s.kind,
+			StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+			Attributes:        otlpAttributes(s.attrs...),
+		}
+		if s.err != "" {
+			// 2 is STATUS_CODE_ERROR.
+			span.Status = otlpStatus{Code: 2, Message: s.err}
+		}
+		out = append(out, span)
+	}
+	return otlpExportRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource: otlpResource{Attributes: otlpAttributes(
+			"service.name", t.service,
+			"service.version", CurrentVersion(),
+			"process.pid", fmt.Sprint(os.Getpid()),
+		)},
+		ScopeSpans: []otlpScopeSpans{{
+			Scope: otlpScope{Name: "github.com/aixolotls/wtx", Version: CurrentVersion()},
+			Spans: out,
+		}},
+	}}}
+}
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+)
+
+func TestTracingExportsSpans(t *testing.T) {
+	var mu sync.Mutex
+	var spans []otlpSpan
+	var headers []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var req otlpExportRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			t.Errorf("decode export: %v", err)
+		}
+		mu.Lock()
+		for _, rs := range req.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				spans = append(spans, ss.Spans...)
+			}
+		}
+		headers = append(headers, r.Header.Get("X-Team"))
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	parentTrace := "0af7651916cd43dd8448eb211c80319c"
+	t.Setenv(otelEnv, "1")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", server.URL)
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "X-Team=dev-platform")
+	t.Setenv("TRACEPARENT", "00-"+parentTrace+"-b7ad6b7169203331-01")
+
+	EnableTracing([]string{"wtx", "list", "--json"})
+	if _, err := RunExternalCommand(context.Background(), CommandSpec{Dir: t.TempDir(), Name: "git", Args: []string{"--version"}}); err != nil {
+		t.Fatalf("git --version: %v", err)
+	}
+	timeStage("status refresh")()
+	StartSpan("agent launch")(errors.New("no agent"))
+	FinishTracing(nil)
+
+	if activeTracer.Load() != nil {
+		t.Fatalf("expected tracing to be off after finishTracing")
+	}
+	if got := os.Getenv("TRACEPARENT"); len(got) != 55 || got[3:35] != parentTrace {
+		t.Fatalf("expected child processes to inherit the trace, got %q", got)
+	}
+	byName := map[string]otlpSpan{}
+	for _, span := range spans {
+		if span.TraceID != parentTrace {
+			t.Fatalf("expected every span in the parent trace, got %+v", span)
+		}
+		byName[span.Name] = span
+	}
+	root, ok := byName["wtx list"]
+	if !ok || root.ParentSpanID != "b7ad6b7169203331" {
+		t.Fatalf("expected a root span under TRACEPARENT, got %+v", spans)
+	}
+	for _, name := range []string{"git", "status refresh", "agent launch"} {
+		span, ok := byName[name]
+		if !ok || span.ParentSpanID != root.SpanID {
+			t.Fatalf("expected %q under the root span, got %+v", name, spans)
+		}
+	}
+	if byName["git"].Kind != spanKindClient || byName["agent launch"].Status.Code != 2 {
+		t.Fatalf("unexpected command or error span: %+v", spans)
+	}
+	if len(headers) == 0 || headers[0] != "dev-platform" {
+		t.Fatalf("expected OTLP headers on the export, got %v", headers)
+	}
+}
+
+func TestTracingOffByDefault(t *testing.T) {
+	t.Setenv(otelEnv, "")
+	EnableTracing([]string{"wtx"})
+	if activeTracer.Load() != nil {
+		t.Fatalf("expected no tracer without %s", otelEnv)
+	}
+	StartSpan("noop")(nil)
+	FinishTracing(nil)
+}
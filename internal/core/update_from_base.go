@@ -0,0 +1,110 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	updateStrategyRebase = "rebase"
+	updateStrategyMerge  = "merge"
+)
+
+var UpdateStrategies = []string{updateStrategyRebase, updateStrategyMerge}
+
+// UpdateConflictError reports a rebase or merge stopped on conflicts. The
+// worktree is left mid-operation so the conflicts can be resolved in place.
+type UpdateConflictError struct {
+	Strategy string
+	BaseRef  string
+	Files    []string
+}
+
+func (e *UpdateConflictError) Error() string {
+	verb := "rebase onto"
+	if e.Strategy == updateStrategyMerge {
+		verb = "merge of"
+	}
+	return fmt.Sprintf("%s %s stopped on conflicts in %s; resolve them, then run git %s --continue (or git %s --abort)",
+		verb, e.BaseRef, strings.Join(e.Files, ", "), e.Strategy, e.Strategy)
+}
+
+func updateStrategy(cfg Config) string {
+	if strings.EqualFold(strings.TrimSpace(cfg.UpdateStrategy), updateStrategyMerge) {
+		return updateStrategyMerge
+	}
+	return updateStrategyRebase
+}
+
+func UpdateFromBaseDescription(worktreePath string) string {
+	cfg, err := LoadConfigForDir(worktreePath)
+	if err == nil && updateStrategy(cfg) == updateStrategyMerge {
+		return "Fetch and merge the base branch"
+	}
+	return "Fetch and rebase onto the base branch"
+}
+
+// UpdateFromBase fetches the base ref new branches start from and rebases
+// (or merges, per update_strategy) the worktree's branch onto it. It returns
+// the ref the branch was updated from.
+func (m *WorktreeManager) UpdateFromBase(worktreePath string, progress io.Writer) (string, error) {
+	worktreePath = strings.TrimSpace(worktreePath)
+	if worktreePath == "" {
+		return "", errors.New("worktree path required")
+	}
+	gitPath, repoRoot, err := RequireGitContext(m.Cwd)
+	if err != nil {
+		return "", err
+	}
+	if CurrentBranchInWorktree(worktreePath) == "" {
+		return "", errors.New("worktree is not on a branch")
+	}
+	dirty, err := GitOutputInDir(worktreePath, gitPath, "status", "--porcelain", "--untracked-files=no")
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(dirty) != "" {
+		return "", errors.New("worktree has uncommitted changes; commit or stash them first")
+	}
+	cfg, err := LoadConfigForDir(worktreePath)
+	if err != nil {
+		return "", err
+	}
+	baseRef := m.ResolveBaseRefForNewBranch()
+	if v := strings.TrimSpace(cfg.NewBranchBaseRef); v != "" && PreferredRemoteName(repoRoot, gitPath) != "" {
+		baseRef = v
+	}
+	if err := m.FetchRepoBaseRef(baseRef, progress); err != nil {
+		return "", err
+	}
+	baseRef = BaseRefForWorktreeAdd(repoRoot, gitPath, baseRef)
+
+	strategy := updateStrategy(cfg)
+	args := []string{"rebase", baseRef}
+	if strategy == updateStrategyMerge {
+		args = []string{"merge", "--no-edit", baseRef}
+	}
+	if _, err := streamCommandInDir(worktreePath, gitPath, progress, args...); err != nil {
+		if files := conflictedFiles(worktreePath, gitPath); len(files) > 0 {
+			return baseRef, &UpdateConflictError{Strategy: strategy, BaseRef: baseRef, Files: files}
+		}
+		return baseRef, err
+	}
+	return baseRef, nil
+}
+
+func conflictedFiles(worktreePath string, gitPath string) []string {
+	out, err := GitOutputInDir(worktreePath, gitPath, "diff", "--name-only", "--diff-filter=U")
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files
+}
@@ -0,0 +1,86 @@
+package core
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUpdateFromBaseRebasesAndStopsOnConflicts(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo := initRenameTestRepo(t)
+	mgr := NewWorktreeManager(repo, NewLockManager())
+	info, err := mgr.CreateWorktree("feature", "", nil)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	commitFile := func(dir string, name string, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		runGitInRepo(t, dir, "add", name)
+		runGitInRepo(t, dir, "commit", "-m", name)
+	}
+	commitFile(info.Path, "feature.txt", "feature\n")
+	commitFile(repo, "base.txt", "base\n")
+
+	if _, err := mgr.UpdateFromBase(info.Path, nil); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	base, err := exec.Command("git", "-C", repo, "rev-parse", "HEAD").Output()
+	if err != nil {
+		t.Fatalf("rev-parse: %v", err)
+	}
+	if err := exec.Command("git", "-C", info.Path, "merge-base", "--is-ancestor", strings.TrimSpace(string(base)), "HEAD").Run(); err != nil {
+		t.Fatalf("expected the base commit in the branch: %v", err)
+	}
+	if err := exec.Command("git", "-C", info.Path, "rev-parse", "--verify", "HEAD^2").Run(); err == nil {
+		t.Fatalf("expected a rebase, not a merge commit")
+	}
+
+	commitFile(info.Path, "shared.txt", "feature\n")
+	commitFile(repo, "shared.txt", "base\n")
+	_, err = mgr.UpdateFromBase(info.Path, nil)
+	var conflict *UpdateConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("expected a conflict error, got %v", err)
+	}
+	if want := []string{"shared.txt"}; !reflect.DeepEqual(conflict.Files, want) {
+		t.Fatalf("conflicted files = %v, want %v", conflict.Files, want)
+	}
+	if files := conflictedFiles(info.Path, "git"); len(files) != 1 {
+		t.Fatalf("expected the rebase left in progress, got conflicts %v", files)
+	}
+	if _, err := mgr.UpdateFromBase(info.Path, nil); err == nil {
+		t.Fatalf("expected a worktree with conflicts to be refused")
+	}
+}
+
+func TestUpdateFromBaseMergesWhenConfigured(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo := initRenameTestRepo(t)
+	runGitInRepo(t, repo, "config", "wtx.updateStrategy", "merge")
+	mgr := NewWorktreeManager(repo, NewLockManager())
+	info, err := mgr.CreateWorktree("feature", "", nil)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	for _, dir := range []string{info.Path, repo} {
+		if err := os.WriteFile(filepath.Join(dir, filepath.Base(dir)+".txt"), []byte("x\n"), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		runGitInRepo(t, dir, "add", ".")
+		runGitInRepo(t, dir, "commit", "-m", "change")
+	}
+	if _, err := mgr.UpdateFromBase(info.Path, nil); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if err := exec.Command("git", "-C", info.Path, "rev-parse", "--verify", "HEAD^2").Run(); err != nil {
+		t.Fatalf("expected a merge commit: %v", err)
+	}
+}
@@ -1,4 +1,4 @@
-package cmd
+package core
 
 import (
 	"runtime/debug"
@@ -9,7 +9,7 @@ var version = "dev"
 
 var readBuildInfo = debug.ReadBuildInfo
 
-func currentVersion() string {
+func CurrentVersion() string {
 	v := strings.TrimSpace(version)
 	if v != "" && v != "dev" {
 		return v
@@ -1,4 +1,4 @@
-package cmd
+package core
 
 import (
 	"runtime/debug"
@@ -20,7 +20,7 @@ func TestCurrentVersion_PrefersExplicitVersion(t *testing.T) {
 		}, true
 	}
 
-	if got := currentVersion(); got != "v9.9.9" {
+	if got := CurrentVersion(); got != "v9.9.9" {
 		t.Fatalf("expected explicit version, got %q", got)
 	}
 }
@@ -40,7 +40,7 @@ func TestCurrentVersion_UsesBuildInfoWhenDev(t *testing.T) {
 		}, true
 	}
 
-	if got := currentVersion(); got != "v1.2.3" {
+	if got := CurrentVersion(); got != "v1.2.3" {
 		t.Fatalf("expected build-info version, got %q", got)
 	}
 }
@@ -60,7 +60,7 @@ func TestCurrentVersion_FallsBackToDev(t *testing.T) {
 		}, true
 	}
 
-	if got := currentVersion(); got != "dev" {
+	if got := CurrentVersion(); got != "dev" {
 		t.Fatalf("expected dev fallback, got %q", got)
 	}
 }
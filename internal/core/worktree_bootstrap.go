@@ -0,0 +1,85 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// prepareNewWorktree copies the configured copy_files globs from the main
+// checkout into a freshly added worktree, applies the create sync_files rules,
+// then starts bootstrap_command in it in the background.
+func prepareNewWorktree(sourceRoot string, worktreePath string, progress io.Writer) error {
+	cfg, err := LoadConfigForDir(worktreePath)
+	if err != nil {
+		return err
+	}
+	if same, err := sameFilesystem(sourceRoot, worktreePath); err == nil && !same && progress != nil {
+		fmt.Fprintln(progress, "Warning: this worktree is on a different filesystem from the repo, so nothing can be hardlinked from it (git clone --local and package caches copy in full), and the worktree needs the repo's disk mounted to work.")
+	}
+	// Bootstrap commands often need the real LFS files, not pointers.
+	prepareWorktreeLFS(worktreePath, cfg.LFSSkipSmudge, progress)
+	if err := copyWorktreeFiles(sourceRoot, worktreePath, cfg.CopyFiles); err != nil {
+		return fmt.Errorf("copy files: %w", err)
+	}
+	if err := syncWorktreeFiles(sourceRoot, worktreePath, cfg.SyncFiles, syncOnCreate); err != nil {
+		return err
+	}
+	allowWorktreeDirenv(worktreePath, progress)
+	if cfg.BootstrapCommand == "" {
+		return nil
+	}
+	return StartWorktreeJob(JobBootstrap, worktreePath, cfg.BootstrapCommand, progress)
+}
+
+func copyWorktreeFiles(sourceRoot string, targetRoot string, globs []string) error {
+	for _, pattern := range globs {
+		if filepath.IsAbs(pattern) {
+			return fmt.Errorf("copy_files pattern %q must be relative", pattern)
+		}
+		matches, err := filepath.Glob(filepath.Join(sourceRoot, pattern))
+		if err != nil {
+			return fmt.Errorf("copy_files pattern %q: %w", pattern, err)
+		}
+		for _, src := range matches {
+			rel, err := filepath.Rel(sourceRoot, src)
+			if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				continue
+			}
+			info, err := os.Stat(src)
+			if err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+			dst := filepath.Join(targetRoot, rel)
+			if _, err := os.Stat(dst); err == nil {
+				continue
+			}
+			if err := CopyFile(src, dst, info.Mode().Perm()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func CopyFile(src string, dst string, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,280 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Worktree jobs are configured commands wtx runs in a worktree in the
+// background: bootstrap_command after creation and test_command on demand.
+const (
+	JobBootstrap = "bootstrap"
+	JobTests     = "tests"
+)
+
+const (
+	JobRunning = "running"
+	jobFailed  = "failed"
+	JobDone    = "done"
+)
+
+// WorktreeJobState is the last run of a job in a worktree. It lives in
+// ~/.wtx/<job> so every wtx process can show it on the worktree's row.
+type WorktreeJobState struct {
+	WorktreePath   string `json:"worktree_path"`
+	Command        string `json:"command"`
+	State          string `json:"state"`
+	PID            int    `json:"pid,omitempty"`
+	ExitCode       int    `json:"exit_code"`
+	StartedAtUnix  int64  `json:"started_at_unix"`
+	FinishedAtUnix int64  `json:"finished_at_unix,omitempty"`
+}
+
+// Running reports whether the job is still going; a run whose process is
+// gone without recording an outcome was interrupted.
+func (s WorktreeJobState) Running() bool {
+	return s.State == JobRunning && PIDAlive(s.PID)
+}
+
+// StartWorktreeJob hands the command to a detached `wtx job-run` so it
+// outlives this process. Without a wtx binary to hand it to (go test), it
+// runs on a goroutine instead.
+func StartWorktreeJob(job string, worktreePath string, command string, progress io.Writer) error {
+	logPath, err := worktreeJobLogPath(job, worktreePath)
+	if err != nil {
+		return err
+	}
+	// Written before the helper starts so its own running state wins.
+	state := WorktreeJobState{
+		WorktreePath:  worktreePath,
+		Command:       command,
+		State:         JobRunning,
+		PID:           os.Getpid(),
+		StartedAtUnix: time.Now().Unix(),
+	}
+	if err := writeWorktreeJobState(job, state); err != nil {
+		return err
+	}
+	if progress != nil {
+		fmt.Fprintf(progress, "$ %s (running in the background, log: %s)\n", command, logPath)
+	}
+	bin := jobHelperBinary()
+	if bin == "" {
+		go func() { _ = RunWorktreeJob(job, worktreePath, command) }()
+		return nil
+	}
+	cmd := exec.Command(bin, "job-run", "--job", job, "--worktree", worktreePath)
+	cmd.SysProcAttr = DetachedProcAttr()
+	if err := cmd.Start(); err != nil {
+		state.State = jobFailed
+		state.ExitCode = -1
+		state.FinishedAtUnix = time.Now().Unix()
+		_ = writeWorktreeJobState(job, state)
+		return fmt.Errorf("%s command failed: %w", job, err)
+	}
+	return cmd.Process.Release()
+}
+
+func jobHelperBinary() string {
+	exe, err := os.Executable()
+	if err != nil || !FileLooksExecutable(exe) {
+		return ""
+	}
+	if strings.HasSuffix(strings.TrimSuffix(filepath.Base(exe), ".exe"), ".test") {
+		return ""
+	}
+	return exe
+}
+
+// RunWorktreeJob runs command in the worktree with its output in the job
+// log, recording the outcome for the worktree row.
+func RunWorktreeJob(job string, worktreePath string, command string) error {
+	state := WorktreeJobState{
+		WorktreePath:  worktreePath,
+		Command:       command,
+		State:         JobRunning,
+		PID:           os.Getpid(),
+		StartedAtUnix: time.Now().Unix(),
+	}
+	if err := writeWorktreeJobState(job, state); err != nil {
+		return err
+	}
+	logPath, err := worktreeJobLogPath(job, worktreePath)
+	if err != nil {
+		return err
+	}
+	logFile, err := os.Create(logPath)
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+	cmd := ShellScriptCommand(command, false)
+	cmd.Dir = worktreePath
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	runErr := cmd.Run()
+	state.State = JobDone
+	if runErr != nil {
+		state.State = jobFailed
+		state.ExitCode = commandExitCode(runErr)
+		fmt.Fprintln(logFile, "wtx:", runErr)
+	}
+	state.FinishedAtUnix = time.Now().Unix()
+	DebugLog("worktree job", "job", job, "worktree", worktreePath, "command", command, "exit_code", state.ExitCode)
+	return writeWorktreeJobState(job, state)
+}
+
+// bootstrapLabel is what the worktree row shows for a bootstrap: nothing once
+// it succeeded.
+func bootstrapLabel(state WorktreeJobState) string {
+	switch {
+	case state.Running():
+		return "bootstrapping…"
+	case state.State == JobRunning:
+		return "bootstrap failed: interrupted"
+	case state.State == jobFailed:
+		return "bootstrap failed: exit " + strconv.Itoa(state.ExitCode)
+	default:
+		return ""
+	}
+}
+
+// testsLabel renders the latest test run as "✓ 42s", "✗ 1m" or
+// "running 12s".
+func testsLabel(state WorktreeJobState, now time.Time) string {
+	switch {
+	case state.Running():
+		return "running " + FormatAgentDuration(now.Sub(time.Unix(state.StartedAtUnix, 0)))
+	case state.State == JobRunning:
+		return "✗ interrupted"
+	case state.State == jobFailed:
+		return "✗ " + FormatAgentDuration(time.Duration(state.FinishedAtUnix-state.StartedAtUnix)*time.Second)
+	case state.State == JobDone:
+		return "✓ " + FormatAgentDuration(time.Duration(state.FinishedAtUnix-state.StartedAtUnix)*time.Second)
+	default:
+		return ""
+	}
+}
+
+// readWorktreeJobStates returns the last run of job in every worktree, keyed
+// by cleaned worktree path.
+func readWorktreeJobStates(job string) map[string]WorktreeJobState {
+	dir, err := worktreeJobDir(job)
+	if err != nil {
+		return nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	out := map[string]WorktreeJobState{}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		var state WorktreeJobState
+		if json.Unmarshal(data, &state) != nil {
+			continue
+		}
+		out[filepath.Clean(state.WorktreePath)] = state
+	}
+	return out
+}
+
+func ReadWorktreeJobState(job string, worktreePath string) (WorktreeJobState, bool) {
+	path, err := worktreeJobStatePath(job, worktreePath)
+	if err != nil {
+		return WorktreeJobState{}, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return WorktreeJobState{}, false
+	}
+	var state WorktreeJobState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return WorktreeJobState{}, false
+	}
+	return state, true
+}
+
+func writeWorktreeJobState(job string, state WorktreeJobState) error {
+	path, err := worktreeJobStatePath(job, state.WorktreePath)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	payload, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, payload, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func ClearWorktreeJob(job string, worktreePath string) error {
+	for _, pathFor := range []func(string, string) (string, error){worktreeJobStatePath, worktreeJobLogPath} {
+		path, err := pathFor(job, worktreePath)
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// clearWorktreeJobs forgets every job run in the worktree, since its path is
+// reused for the next worktree.
+func clearWorktreeJobs(worktreePath string) error {
+	var errs []error
+	for _, job := range []string{JobBootstrap, JobTests} {
+		errs = append(errs, ClearWorktreeJob(job, worktreePath))
+	}
+	return errors.Join(errs...)
+}
+
+func worktreeJobDir(job string) (string, error) {
+	home, err := WtxHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, job), nil
+}
+
+func worktreeJobStatePath(job string, worktreePath string) (string, error) {
+	return worktreeJobFilePath(job, worktreePath, ".json")
+}
+
+func worktreeJobLogPath(job string, worktreePath string) (string, error) {
+	return worktreeJobFilePath(job, worktreePath, ".log")
+}
+
+func worktreeJobFilePath(job string, worktreePath string, ext string) (string, error) {
+	worktreePath = strings.TrimSpace(worktreePath)
+	if worktreePath == "" {
+		return "", os.ErrInvalid
+	}
+	dir, err := worktreeJobDir(job)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, HashString(filepath.Clean(worktreePath))[:16]+ext), nil
+}
@@ -0,0 +1,93 @@
+package core
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// waitForJob polls until the job stops running in the worktree.
+func waitForJob(t *testing.T, job string, worktreePath string) WorktreeJobState {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		state, ok := ReadWorktreeJobState(job, worktreePath)
+		if ok && state.State != JobRunning {
+			return state
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%s still running after 10s: %+v", job, state)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestBootstrapFailureShowsOnRow(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo := initRenameTestRepo(t)
+	runGitInRepo(t, repo, "config", "wtx.bootstrap", "echo setup broke && exit 4")
+
+	lockMgr := NewLockManager()
+	mgr := NewWorktreeManager(repo, lockMgr)
+	wt, err := mgr.CreateWorktree("feature", "", nil)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if state := waitForJob(t, JobBootstrap, wt.Path); state.State != jobFailed || state.ExitCode != 4 {
+		t.Fatalf("expected the bootstrap to fail with 4, got %+v", state)
+	}
+	logPath, _ := worktreeJobLogPath(JobBootstrap, wt.Path)
+	if data, err := os.ReadFile(logPath); err != nil || !strings.Contains(string(data), "setup broke") {
+		t.Fatalf("expected the output in the bootstrap log, got %q (%v)", data, err)
+	}
+
+	status := NewWorktreeOrchestrator(mgr, lockMgr, nil).Status()
+	labels := map[string]string{}
+	for _, info := range status.Worktrees {
+		labels[info.Branch] = info.Bootstrap
+	}
+	if labels["feature"] != "bootstrap failed: exit 4" || labels["main"] != "" {
+		t.Fatalf("expected the failure on the feature row only, got %q", labels)
+	}
+
+	warnings, err := mgr.DeleteWorktree(wt.Path, true)
+	if err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if len(warnings) > 0 {
+		t.Fatalf("expected a clean delete, got warnings %q", warnings)
+	}
+	if _, ok := ReadWorktreeJobState(JobBootstrap, wt.Path); ok {
+		t.Fatalf("expected the bootstrap state removed with the worktree")
+	}
+}
+
+func TestBootstrapLabel(t *testing.T) {
+	if got := bootstrapLabel(WorktreeJobState{State: JobRunning, PID: os.Getpid()}); got != "bootstrapping…" {
+		t.Fatalf("running label = %q", got)
+	}
+	if got := bootstrapLabel(WorktreeJobState{State: JobRunning, PID: 0}); got != "bootstrap failed: interrupted" {
+		t.Fatalf("dead runner label = %q", got)
+	}
+	if got := bootstrapLabel(WorktreeJobState{State: JobDone}); got != "" {
+		t.Fatalf("done label = %q", got)
+	}
+}
+
+func TestTestsLabel(t *testing.T) {
+	now := time.Unix(1000, 0)
+	for _, tc := range []struct {
+		state WorktreeJobState
+		want  string
+	}{
+		{WorktreeJobState{State: JobRunning, PID: os.Getpid(), StartedAtUnix: 988}, "running 12s"},
+		{WorktreeJobState{State: JobDone, StartedAtUnix: 900, FinishedAtUnix: 942}, "✓ 42s"},
+		{WorktreeJobState{State: jobFailed, StartedAtUnix: 800, FinishedAtUnix: 920}, "✗ 2m"},
+		{WorktreeJobState{}, ""},
+	} {
+		if got := testsLabel(tc.state, now); got != tc.want {
+			t.Fatalf("testsLabel(%+v) = %q, want %q", tc.state, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,1098 @@
+// Package core holds the worktree, lock, config and GitHub handling behind
+// wtx. It has no terminal UI dependencies and does not print: problems that
+// should not fail an operation come back to the caller as warnings.
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type WorktreeManager struct {
+	Cwd     string
+	lockMgr *LockManager
+	mu      sync.Mutex
+	byRepo  map[string]repoBaseRefState
+}
+
+type repoBaseRefState struct {
+	Remote  string
+	BaseRef string
+	Warming bool
+	// Warmed is closed when the background gh lookup finishes.
+	Warmed chan struct{}
+}
+
+func NewWorktreeManager(cwd string, lockMgr *LockManager) *WorktreeManager {
+	if strings.TrimSpace(cwd) == "" {
+		cwd, _ = os.Getwd()
+	}
+	return &WorktreeManager{
+		Cwd:     cwd,
+		lockMgr: lockMgr,
+		byRepo:  make(map[string]repoBaseRefState),
+	}
+}
+
+func (m *WorktreeManager) ListForStatusBase() WorktreeStatus {
+	status := WorktreeStatus{}
+	status.CWD = m.Cwd
+	gitPath, err := GitPath()
+	if err != nil {
+		status.GitInstalled = false
+		return status
+	}
+	status.GitInstalled = true
+
+	repoRoot, err := RepoRootForDir(m.Cwd, gitPath)
+	if err != nil {
+		status.InRepo = false
+		return status
+	}
+	status.InRepo = true
+	status.RepoRoot = repoRoot
+	status.HasRemote = RepoHasRemote(repoRoot)
+	done := timeStage("base ref")
+	status.BaseRef = m.ResolveBaseRefForNewBranch()
+	done()
+
+	done = timeStage("list worktrees")
+	worktrees, malformed, err := ListWorktrees(repoRoot, gitPath)
+	done()
+	if err != nil {
+		status.Err = err
+		return status
+	}
+	status.Worktrees = worktrees
+	status.Malformed = malformed
+
+	return status
+}
+
+func (m *WorktreeManager) ResolveBaseRefForNewBranch() string {
+	gitPath, repoRoot, err := RequireGitContext(m.Cwd)
+	if err != nil {
+		return "main"
+	}
+	if cached := m.cachedBaseRef(repoRoot); cached != "" {
+		return cached
+	}
+	remote := m.cachedRemote(repoRoot)
+	if remote == "" {
+		remote = PreferredRemoteName(repoRoot, gitPath)
+		m.setCachedRemote(repoRoot, remote)
+	}
+	fallbackBranch := fallbackBaseBranchNoRemote(repoRoot, gitPath)
+	if strings.TrimSpace(remote) == "" {
+		return fallbackBranch
+	}
+	fallback := remote + "/" + fallbackBranch
+	if branch, stale := persistedDefaultBranch(repoRoot); branch != "" {
+		resolved := remote + "/" + branch
+		m.setCachedBaseRef(repoRoot, remote, resolved)
+		if stale {
+			m.ensureBaseRefWarm(repoRoot, remote, resolved, true)
+		}
+		return resolved
+	}
+	m.ensureBaseRefWarm(repoRoot, remote, fallback, false)
+	return fallback
+}
+
+// AwaitBaseRef returns the base ref for new branches once any background gh
+// lookup has finished, or the best known value after timeout.
+func (m *WorktreeManager) AwaitBaseRef(timeout time.Duration) string {
+	baseRef := m.ResolveBaseRefForNewBranch()
+	_, repoRoot, err := RequireGitContext(m.Cwd)
+	if err != nil {
+		return baseRef
+	}
+	m.mu.Lock()
+	entry := m.byRepo[repoRoot]
+	m.mu.Unlock()
+	if !entry.Warming || entry.Warmed == nil {
+		return baseRef
+	}
+	select {
+	case <-entry.Warmed:
+		return m.ResolveBaseRefForNewBranch()
+	case <-time.After(timeout):
+		return baseRef
+	}
+}
+
+// CreateWorktree adds a worktree on a new branch. Command output is copied to
+// progress when it is non-nil.
+func (m *WorktreeManager) CreateWorktree(branch string, baseRef string, progress io.Writer) (WorktreeInfo, error) {
+	branch = strings.TrimSpace(branch)
+	if branch == "" {
+		return WorktreeInfo{}, errors.New("branch name required")
+	}
+	baseRef = strings.TrimSpace(baseRef)
+	if baseRef == "" {
+		baseRef = "HEAD"
+	}
+
+	gitPath, repoRoot, err := RequireGitContext(m.Cwd)
+	if err != nil {
+		return WorktreeInfo{}, err
+	}
+	layoutRoot := WorktreeLayoutRoot(repoRoot, gitPath)
+
+	target, err := nextWorktreePath(layoutRoot)
+	if err != nil {
+		return WorktreeInfo{}, err
+	}
+	lock, err := m.lockMgr.Acquire(repoRoot, target)
+	if err != nil {
+		return WorktreeInfo{}, err
+	}
+	defer lock.Release()
+
+	baseRef = BaseRefForWorktreeAdd(repoRoot, gitPath, baseRef)
+	hookCtx := HookContext{RepoRoot: repoRoot, WorktreePath: target, Branch: branch, BaseRef: baseRef}
+	if err := RunHook(HookPreCreate, hookCtx); err != nil {
+		return WorktreeInfo{}, err
+	}
+	if _, err := streamCommandInDirWithEnv(layoutRoot, gitPath, progress, LFSCheckoutEnv(layoutRoot), "worktree", "add", "-b", branch, target, baseRef); err != nil {
+		return WorktreeInfo{}, err
+	}
+	RecordAction(AuditCreate, repoRoot, target, branch, map[string]string{"base": baseRef})
+	if err := prepareNewWorktree(layoutRoot, target, progress); err != nil {
+		return WorktreeInfo{Path: target, Branch: branch}, err
+	}
+	created := WorktreeInfo{Path: target, Branch: branch}
+	if err := RunHook(HookPostCreate, hookCtx); err != nil {
+		created.HookWarning = err.Error()
+	}
+	return created, nil
+}
+
+func (m *WorktreeManager) CreateWorktreeFromBranch(branch string, progress io.Writer) (WorktreeInfo, error) {
+	branch = strings.TrimSpace(branch)
+	if branch == "" {
+		return WorktreeInfo{}, errors.New("branch name required")
+	}
+
+	gitPath, repoRoot, err := RequireGitContext(m.Cwd)
+	if err != nil {
+		return WorktreeInfo{}, err
+	}
+	layoutRoot := WorktreeLayoutRoot(repoRoot, gitPath)
+
+	target, err := nextWorktreePath(layoutRoot)
+	if err != nil {
+		return WorktreeInfo{}, err
+	}
+	lock, err := m.lockMgr.Acquire(repoRoot, target)
+	if err != nil {
+		return WorktreeInfo{}, err
+	}
+	defer lock.Release()
+
+	hookCtx := HookContext{RepoRoot: repoRoot, WorktreePath: target, Branch: branch}
+	if err := RunHook(HookPreCreate, hookCtx); err != nil {
+		return WorktreeInfo{}, err
+	}
+	if _, err := streamCommandInDirWithEnv(layoutRoot, gitPath, progress, LFSCheckoutEnv(layoutRoot), "worktree", "add", target, branch); err != nil {
+		return WorktreeInfo{}, err
+	}
+	RecordAction(AuditCreate, repoRoot, target, branch, nil)
+	if err := prepareNewWorktree(layoutRoot, target, progress); err != nil {
+		return WorktreeInfo{Path: target, Branch: branch}, err
+	}
+	created := WorktreeInfo{Path: target, Branch: branch}
+	if err := RunHook(HookPostCreate, hookCtx); err != nil {
+		created.HookWarning = err.Error()
+	}
+	return created, nil
+}
+
+func (m *WorktreeManager) ListLocalBranchesByRecentUse() ([]string, error) {
+	gitPath, repoRoot, err := RequireGitContext(m.Cwd)
+	if err != nil {
+		return nil, err
+	}
+	limit := DefaultMainScreenBranchLimit
+	if cfg, cfgErr := LoadConfig(); cfgErr == nil && cfg.MainScreenBranchLimit > 0 {
+		limit = cfg.MainScreenBranchLimit
+	}
+	if branches, err := readBranchesByCommitDate(repoRoot, limit); err == nil {
+		return branches, nil
+	}
+
+	output, err := CommandOutputInDir(repoRoot, gitPath, "for-each-ref",
+		"--sort=-committerdate",
+		"--format=%(refname:short)",
+		"refs/heads/",
+		"--count", fmt.Sprintf("%d", limit))
+	if err != nil {
+		return nil, err
+	}
+
+	branches := make([]string, 0, limit)
+	for _, line := range strings.Split(string(output), "\n") {
+		name := strings.TrimSpace(line)
+		if name == "" {
+			continue
+		}
+		branches = append(branches, name)
+	}
+	return branches, nil
+}
+
+func (m *WorktreeManager) ListAllLocalBranchesByRecentUse() ([]string, error) {
+	gitPath, repoRoot, err := RequireGitContext(m.Cwd)
+	if err != nil {
+		return nil, err
+	}
+	if branches, err := readBranchesByCommitDate(repoRoot, 0); err == nil {
+		return branches, nil
+	}
+
+	output, err := CommandOutputInDir(repoRoot, gitPath, "for-each-ref",
+		"--sort=-committerdate",
+		"--format=%(refname:short)",
+		"refs/heads/")
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(string(output), "\n")
+	branches := make([]string, 0, len(lines))
+	for _, line := range lines {
+		name := strings.TrimSpace(line)
+		if name == "" {
+			continue
+		}
+		branches = append(branches, name)
+	}
+	return branches, nil
+}
+
+// DeleteWorktree removes the worktree at path. The returned warnings describe
+// per-worktree state that could not be cleaned up after the removal.
+func (m *WorktreeManager) DeleteWorktree(path string, force bool) ([]string, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, errors.New("worktree path required")
+	}
+
+	gitPath, repoRoot, err := RequireGitContext(m.Cwd)
+	if err != nil {
+		return nil, err
+	}
+	if err := EnsureManagedWorktreePath(repoRoot, path); err != nil {
+		return nil, err
+	}
+
+	args := []string{"worktree", "remove"}
+	if force {
+		args = append(args, "--force")
+	}
+	args = append(args, path)
+	lock, err := m.lockMgr.Acquire(repoRoot, path)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Release()
+
+	branch := CurrentBranchInWorktree(path)
+	if err := RunHook(HookPreDelete, HookContext{RepoRoot: repoRoot, WorktreePath: path, Branch: branch}); err != nil {
+		return nil, err
+	}
+	if err := RunCommandInDir(repoRoot, gitPath, args...); err != nil {
+		return nil, err
+	}
+	RecordAction(AuditDelete, repoRoot, path, branch, map[string]string{"force": strconv.FormatBool(force)})
+	// Paths are reused for new worktrees, so the note, pin and job state
+	// must not outlive this one.
+	var warnings []string
+	if err := SetWorktreeNote(repoRoot, path, ""); err != nil {
+		warnings = append(warnings, "remove worktree note: "+err.Error())
+	}
+	if err := SetWorktreePinned(repoRoot, path, false); err != nil {
+		warnings = append(warnings, "remove worktree pin: "+err.Error())
+	}
+	if err := clearWorktreeJobs(path); err != nil {
+		warnings = append(warnings, "remove worktree job state: "+err.Error())
+	}
+	if err := clearSyncedFiles(path); err != nil {
+		warnings = append(warnings, "remove sync_files state: "+err.Error())
+	}
+	return warnings, nil
+}
+
+// DeleteBranch deletes a local branch; force uses -D so unmerged work is dropped.
+func (m *WorktreeManager) DeleteBranch(branch string, force bool) error {
+	branch = strings.TrimSpace(branch)
+	if branch == "" || branch == "detached" {
+		return errors.New("branch name required")
+	}
+	gitPath, repoRoot, err := RequireGitContext(m.Cwd)
+	if err != nil {
+		return err
+	}
+	flag := "-d"
+	if force {
+		flag = "-D"
+	}
+	if err := RunCommandInDir(repoRoot, gitPath, "branch", flag, branch); err != nil {
+		return err
+	}
+	RecordAction(AuditDeleteBranch, repoRoot, "", branch, map[string]string{"force": strconv.FormatBool(force)})
+	return nil
+}
+
+func CommandErrorWithOutput(err error, out []byte) error {
+	msg := strings.TrimSpace(string(out))
+	if msg != "" {
+		return errors.New(msg)
+	}
+	return err
+}
+
+func CommandOutputInDir(dir string, path string, args ...string) ([]byte, error) {
+	return streamCommandInDir(dir, path, nil, args...)
+}
+
+// streamCommandInDir is commandOutputInDir that also copies output to
+// progress, when set, as the command runs.
+func streamCommandInDir(dir string, path string, progress io.Writer, args ...string) ([]byte, error) {
+	return streamCommandInDirWithEnv(dir, path, progress, nil, args...)
+}
+
+func streamCommandInDirWithEnv(dir string, path string, progress io.Writer, env []string, args ...string) ([]byte, error) {
+	if progress != nil {
+		prefix := ""
+		if len(env) > 0 {
+			prefix = strings.Join(env, " ") + " "
+		}
+		fmt.Fprintf(progress, "$ %s%s %s\n", prefix, filepath.Base(path), strings.Join(args, " "))
+	}
+	out, err := RunExternalCommand(context.Background(), CommandSpec{Dir: dir, Name: path, Args: args, Env: env, Timeout: gitCommandTimeout, Progress: progress})
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, err
+		}
+		return nil, &CommandError{
+			Command: filepath.Base(path) + " " + strings.Join(args, " "),
+			Dir:     dir,
+			Output:  strings.TrimSpace(string(out)),
+			Err:     err,
+		}
+	}
+	return out, nil
+}
+
+func RunCommandInDir(dir string, path string, args ...string) error {
+	_, err := CommandOutputInDir(dir, path, args...)
+	return err
+}
+
+func runStreamedInDir(dir string, path string, progress io.Writer, args ...string) error {
+	_, err := streamCommandInDir(dir, path, progress, args...)
+	return err
+}
+
+func (m *WorktreeManager) CanDeleteWorktree(path string) error {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return errors.New("worktree path required")
+	}
+	_, repoRoot, err := RequireGitContext(m.Cwd)
+	if err != nil {
+		return err
+	}
+	return EnsureManagedWorktreePath(repoRoot, path)
+}
+
+func (m *WorktreeManager) CheckoutExistingBranch(worktreePath string, branch string, progress io.Writer) error {
+	worktreePath = strings.TrimSpace(worktreePath)
+	branch = strings.TrimSpace(branch)
+	if worktreePath == "" {
+		return errors.New("worktree path required")
+	}
+	if branch == "" {
+		return errors.New("branch name required")
+	}
+	err := runStreamedInDir(worktreePath, "git", progress, "checkout", branch)
+	return asDirtyCheckout(err, worktreePath, branch, "", false)
+}
+
+func (m *WorktreeManager) CheckoutNewBranch(worktreePath string, branch string, baseRef string, doFetch bool, progress io.Writer) error {
+	worktreePath = strings.TrimSpace(worktreePath)
+	branch = strings.TrimSpace(branch)
+	baseRef = strings.TrimSpace(baseRef)
+	if worktreePath == "" {
+		return errors.New("worktree path required")
+	}
+	if branch == "" {
+		return errors.New("branch name required")
+	}
+	gitPath, repoRoot, err := RequireGitContext(m.Cwd)
+	if err != nil {
+		return err
+	}
+	if doFetch {
+		if err := m.FetchRepoBaseRef(baseRef, progress); err != nil {
+			return err
+		}
+	}
+	if LocalBranchExists(repoRoot, gitPath, branch) {
+		err := runStreamedInDir(worktreePath, gitPath, progress, "checkout", branch)
+		return asDirtyCheckout(err, worktreePath, branch, baseRef, true)
+	}
+	if baseRef == "" {
+		baseRef = "HEAD"
+	}
+	err = runStreamedInDir(worktreePath, gitPath, progress, "checkout", "-b", branch, baseRef)
+	return asDirtyCheckout(err, worktreePath, branch, baseRef, true)
+}
+
+func (m *WorktreeManager) FetchRepo() error {
+	gitPath, repoRoot, err := RequireGitContext(m.Cwd)
+	if err != nil {
+		return err
+	}
+	return RunCommandInDir(repoRoot, gitPath, "fetch")
+}
+
+// FetchBranch updates the remote tracking ref for branch from the preferred
+// remote.
+func (m *WorktreeManager) FetchBranch(branch string) error {
+	branch = strings.TrimSpace(branch)
+	if branch == "" || branch == "detached" {
+		return errors.New("branch required")
+	}
+	gitPath, repoRoot, err := RequireGitContext(m.Cwd)
+	if err != nil {
+		return err
+	}
+	remote := PreferredRemoteName(repoRoot, gitPath)
+	if remote == "" {
+		return errors.New("no git remote configured")
+	}
+	return RunCommandInDir(repoRoot, gitPath, "fetch", remote, branch)
+}
+
+func (m *WorktreeManager) FetchRepoBaseRef(baseRef string, progress io.Writer) error {
+	baseRef = strings.TrimSpace(baseRef)
+	if baseRef == "" || baseRef == "HEAD" {
+		return nil
+	}
+
+	gitPath, repoRoot, err := RequireGitContext(m.Cwd)
+	if err != nil {
+		return err
+	}
+
+	remotes, err := ListGitRemotes(repoRoot, gitPath)
+	if err != nil {
+		return err
+	}
+	if len(remotes) == 0 {
+		return nil
+	}
+	explicitRemote := isExplicitRemoteBaseRef(baseRef, remotes)
+	if !explicitRemote {
+		resolved := BaseRefForWorktreeAdd(repoRoot, gitPath, baseRef)
+		if _, err := GitOutputInDir(repoRoot, gitPath, "rev-parse", "--verify", resolved+"^{commit}"); err == nil {
+			return nil
+		}
+	}
+
+	remote := PreferredRemoteName(repoRoot, gitPath)
+	fetchRemote, fetchRef, ok := fetchRemoteAndRefForBaseRef(baseRef, remotes, remote)
+	if !ok {
+		return nil
+	}
+	if progress != nil {
+		return runStreamedInDir(repoRoot, gitPath, progress, "fetch", "--progress", fetchRemote, fetchRef)
+	}
+	return RunCommandInDir(repoRoot, gitPath, "fetch", fetchRemote, fetchRef)
+}
+
+func (m *WorktreeManager) AcquireWorktreeLock(worktreePath string) (*WorktreeLock, error) {
+	worktreePath = strings.TrimSpace(worktreePath)
+	if worktreePath == "" {
+		return nil, errors.New("worktree path required")
+	}
+	_, repoRoot, err := RequireGitContext(m.Cwd)
+	if err != nil {
+		return nil, err
+	}
+	return m.lockMgr.Acquire(repoRoot, worktreePath)
+}
+
+func (m *WorktreeManager) UnlockWorktree(worktreePath string) error {
+	worktreePath = strings.TrimSpace(worktreePath)
+	if worktreePath == "" {
+		return errors.New("worktree path required")
+	}
+	_, repoRoot, err := RequireGitContext(m.Cwd)
+	if err != nil {
+		return err
+	}
+	return m.lockMgr.ForceUnlock(repoRoot, worktreePath)
+}
+
+func ListWorktrees(repoRoot string, gitPath string) ([]WorktreeInfo, []string, error) {
+	if worktrees, err := readWorktrees(repoRoot); err == nil {
+		return worktrees, nil, nil
+	}
+	output, err := CommandOutputInDir(repoRoot, gitPath, "worktree", "list", "--porcelain", "-z")
+	if err != nil {
+		// -z needs git 2.36.
+		output, err = CommandOutputInDir(repoRoot, gitPath, "worktree", "list", "--porcelain")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return parseWorktrees(string(output))
+}
+
+// parseWorktrees reads `git worktree list --porcelain`, preferably with -z so
+// paths containing spaces or newlines come through intact. Values are taken
+// verbatim after the first space of each attribute.
+func parseWorktrees(output string) ([]WorktreeInfo, []string, error) {
+	var worktrees []WorktreeInfo
+	var malformed []string
+	var current *WorktreeInfo
+
+	sep := "\n"
+	if strings.Contains(output, "\x00") {
+		sep = "\x00"
+	}
+	for _, line := range strings.Split(output, sep) {
+		if sep == "\n" {
+			line = strings.TrimSuffix(line, "\r")
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "worktree":
+			if value == "" {
+				malformed = append(malformed, line)
+				current = nil
+				continue
+			}
+			worktrees = append(worktrees, WorktreeInfo{Path: value})
+			current = &worktrees[len(worktrees)-1]
+		case "branch":
+			if current == nil {
+				malformed = append(malformed, line)
+				continue
+			}
+			current.Branch = ShortBranch(value)
+		case "detached":
+			if current == nil {
+				malformed = append(malformed, line)
+				continue
+			}
+			if current.Branch == "" {
+				current.Branch = "detached"
+			}
+		default:
+			if current == nil {
+				malformed = append(malformed, line)
+			}
+		}
+	}
+
+	for i := range worktrees {
+		if worktrees[i].Branch == "" {
+			worktrees[i].Branch = "detached"
+		}
+	}
+	return worktrees, malformed, nil
+}
+
+func ShortBranch(value string) string {
+	value = strings.TrimSpace(value)
+	value = strings.TrimPrefix(value, "refs/heads/")
+	value = strings.TrimPrefix(value, "refs/remotes/")
+	value = strings.TrimPrefix(value, "origin/")
+	if value == "" {
+		return "detached"
+	}
+	return value
+}
+
+func GitOutputInDir(dir string, path string, args ...string) (string, error) {
+	output, err := CommandOutputInDir(dir, path, args...)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+func GitRunInDir(dir string, path string, args ...string) error {
+	return RunCommandInDir(dir, path, args...)
+}
+
+func fallbackBaseBranchNoRemote(repoRoot string, gitPath string) string {
+	mainExists := LocalBranchExists(repoRoot, gitPath, "main")
+	if current, err := readCurrentBranch(repoRoot); err == nil {
+		return chooseFallbackBaseNoRemote(mainExists, current)
+	}
+	current, err := GitOutputInDir(repoRoot, gitPath, "branch", "--show-current")
+	if err == nil {
+		return chooseFallbackBaseNoRemote(mainExists, current)
+	}
+	return chooseFallbackBaseNoRemote(mainExists, "")
+}
+
+func chooseFallbackBaseNoRemote(mainExists bool, currentBranch string) string {
+	if mainExists {
+		return "main"
+	}
+	currentBranch = strings.TrimSpace(currentBranch)
+	if currentBranch != "" && currentBranch != "detached" {
+		return currentBranch
+	}
+	return "main"
+}
+
+func LocalBranchExists(repoRoot string, gitPath string, branch string) bool {
+	branch = strings.TrimSpace(branch)
+	if branch == "" {
+		return false
+	}
+	if exists, err := readLocalBranchExists(repoRoot, branch); err == nil {
+		return exists
+	}
+	_, err := GitOutputInDir(repoRoot, gitPath, "show-ref", "--verify", "refs/heads/"+branch)
+	return err == nil
+}
+
+func BaseRefForWorktreeAdd(repoRoot string, gitPath string, baseRef string) string {
+	baseRef = strings.TrimSpace(baseRef)
+	if baseRef == "" || baseRef == "HEAD" {
+		return "HEAD"
+	}
+	remote := PreferredRemoteName(repoRoot, gitPath)
+	if remoteRef, ok := asRemoteRef(repoRoot, gitPath, remote, baseRef); ok {
+		return remoteRef
+	}
+	branch := ShortBranch(baseRef)
+	if strings.TrimSpace(branch) != "" && branch != "detached" {
+		if LocalBranchExists(repoRoot, gitPath, branch) {
+			return branch
+		}
+		if remoteRef, ok := asRemoteRef(repoRoot, gitPath, remote, branch); ok {
+			return remoteRef
+		}
+	}
+	if remoteRef, ok := asRemoteRef(repoRoot, gitPath, remote, baseRef); ok {
+		return remoteRef
+	}
+	return baseRef
+}
+
+func defaultBaseRefFromGitHub(repoRoot string) (string, error) {
+	owner, name, err := resolveGitHubRepo(repoRoot)
+	if err != nil {
+		return "", err
+	}
+	ghPath, err := exec.LookPath("gh")
+	if err != nil {
+		return "", err
+	}
+	out, err := RunExternalCommand(context.Background(), CommandSpec{
+		Dir:     repoRoot,
+		Name:    ghPath,
+		Args:    []string{"repo", "view", owner + "/" + name, "--json", "defaultBranchRef", "--jq", ".defaultBranchRef.name"},
+		Timeout: GHCommandTimeout,
+	})
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return "", err
+		}
+		return "", CommandErrorWithOutput(err, out)
+	}
+	ref := strings.TrimSpace(string(out))
+	if ref == "" {
+		return "", errors.New("github default branch not found")
+	}
+	return ref, nil
+}
+
+// defaultBranchCacheTTL is how long a default branch persisted in git config
+// is trusted before gh is asked again in the background.
+const defaultBranchCacheTTL = 7 * 24 * time.Hour
+
+// persistedDefaultBranch returns the GitHub default branch stored in
+// `git config wtx.defaultBranch` and whether it is due for a refresh.
+func persistedDefaultBranch(repoRoot string) (string, bool) {
+	branch := gitConfigValue(repoRoot, "wtx.defaultBranch")
+	if branch == "" {
+		return "", false
+	}
+	checkedAt, _ := strconv.ParseInt(gitConfigValue(repoRoot, "wtx.defaultBranchCheckedAt"), 10, 64)
+	return branch, time.Since(time.Unix(checkedAt, 0)) > defaultBranchCacheTTL
+}
+
+func persistDefaultBranch(repoRoot string, branch string) error {
+	if err := GitRunInDir(repoRoot, "git", "config", "--local", "wtx.defaultBranch", branch); err != nil {
+		return err
+	}
+	return GitRunInDir(repoRoot, "git", "config", "--local", "wtx.defaultBranchCheckedAt", strconv.FormatInt(time.Now().Unix(), 10))
+}
+
+func asRemoteRef(repoRoot string, gitPath string, remote string, ref string) (string, bool) {
+	ref = strings.TrimSpace(ref)
+	remote = strings.TrimSpace(remote)
+	if remote == "" {
+		return "", false
+	}
+	if ref == "" {
+		return "", false
+	}
+	if strings.HasPrefix(ref, remote+"/") {
+		return ref, true
+	}
+	remoteRef := remote + "/" + ref
+	if _, err := GitOutputInDir(repoRoot, gitPath, "show-ref", "--verify", "refs/remotes/"+remoteRef); err == nil {
+		return remoteRef, true
+	}
+	return "", false
+}
+
+func RepoHasRemote(repoRoot string) bool {
+	return PreferredRemoteName(repoRoot, "git") != ""
+}
+
+func PreferredRemoteName(repoRoot string, gitPath string) string {
+	remotes, err := ListGitRemotes(repoRoot, gitPath)
+	if err != nil {
+		return ""
+	}
+	if len(remotes) == 0 {
+		return ""
+	}
+	for _, remote := range remotes {
+		if remote == "origin" {
+			return "origin"
+		}
+	}
+	return remotes[0]
+}
+
+func fetchRemoteAndRefForBaseRef(baseRef string, remotes []string, preferredRemote string) (string, string, bool) {
+	baseRef = strings.TrimSpace(baseRef)
+	if baseRef == "" || baseRef == "HEAD" {
+		return "", "", false
+	}
+
+	for _, remote := range remotes {
+		if remote == "" {
+			continue
+		}
+		if strings.HasPrefix(baseRef, "refs/remotes/"+remote+"/") {
+			ref := strings.TrimPrefix(baseRef, "refs/remotes/"+remote+"/")
+			if strings.TrimSpace(ref) != "" {
+				return remote, ref, true
+			}
+		}
+		prefix := remote + "/"
+		if strings.HasPrefix(baseRef, prefix) {
+			ref := strings.TrimPrefix(baseRef, prefix)
+			if strings.TrimSpace(ref) != "" {
+				return remote, ref, true
+			}
+		}
+	}
+
+	if strings.HasPrefix(baseRef, "refs/heads/") {
+		baseRef = strings.TrimPrefix(baseRef, "refs/heads/")
+	}
+
+	if strings.TrimSpace(preferredRemote) == "" {
+		if len(remotes) == 0 {
+			return "", "", false
+		}
+		preferredRemote = remotes[0]
+	}
+
+	if strings.TrimSpace(baseRef) == "" {
+		return "", "", false
+	}
+	return preferredRemote, baseRef, true
+}
+
+func isExplicitRemoteBaseRef(baseRef string, remotes []string) bool {
+	baseRef = strings.TrimSpace(baseRef)
+	if baseRef == "" {
+		return false
+	}
+	for _, remote := range remotes {
+		remote = strings.TrimSpace(remote)
+		if remote == "" {
+			continue
+		}
+		if strings.HasPrefix(baseRef, remote+"/") {
+			return true
+		}
+		if strings.HasPrefix(baseRef, "refs/remotes/"+remote+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func ListGitRemotes(repoRoot string, gitPath string) ([]string, error) {
+	if remotes, err := readGitRemotes(repoRoot); err == nil {
+		return remotes, nil
+	}
+	remotes, err := GitOutputInDir(repoRoot, gitPath, "remote")
+	if err != nil {
+		return nil, err
+	}
+	list := strings.Split(strings.TrimSpace(remotes), "\n")
+	out := make([]string, 0, len(list))
+	for _, remote := range list {
+		if trimmed := strings.TrimSpace(remote); trimmed != "" {
+			out = append(out, trimmed)
+		}
+	}
+	return out, nil
+}
+
+func (m *WorktreeManager) cachedBaseRef(repoRoot string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return strings.TrimSpace(m.byRepo[repoRoot].BaseRef)
+}
+
+func (m *WorktreeManager) cachedRemote(repoRoot string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return strings.TrimSpace(m.byRepo[repoRoot].Remote)
+}
+
+func (m *WorktreeManager) setCachedRemote(repoRoot string, remote string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry := m.byRepo[repoRoot]
+	entry.Remote = strings.TrimSpace(remote)
+	m.byRepo[repoRoot] = entry
+}
+
+func (m *WorktreeManager) setCachedBaseRef(repoRoot string, remote string, baseRef string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry := m.byRepo[repoRoot]
+	entry.Remote = strings.TrimSpace(remote)
+	entry.BaseRef = strings.TrimSpace(baseRef)
+	m.byRepo[repoRoot] = entry
+}
+
+// ensureBaseRefWarm asks gh for the default branch in the background and
+// persists the answer. With refresh set it runs even though a base ref is
+// already cached, and keeps that base ref if gh cannot be reached.
+func (m *WorktreeManager) ensureBaseRefWarm(repoRoot string, remote string, fallback string, refresh bool) {
+	repoRoot = strings.TrimSpace(repoRoot)
+	if repoRoot == "" {
+		return
+	}
+	m.mu.Lock()
+	entry := m.byRepo[repoRoot]
+	if (strings.TrimSpace(entry.BaseRef) != "" && !refresh) || entry.Warming {
+		m.mu.Unlock()
+		return
+	}
+	entry.Warming = true
+	entry.Warmed = make(chan struct{})
+	entry.Remote = strings.TrimSpace(remote)
+	m.byRepo[repoRoot] = entry
+	m.mu.Unlock()
+
+	go func() {
+		resolved := strings.TrimSpace(fallback)
+		if strings.TrimSpace(entry.Remote) != "" {
+			if ghRef, err := defaultBaseRefFromGitHub(repoRoot); err == nil {
+				ghRef = ShortBranch(ghRef)
+				if ghRef != "" && ghRef != "detached" {
+					resolved = entry.Remote + "/" + ghRef
+					_ = persistDefaultBranch(repoRoot, ghRef)
+				}
+			}
+		}
+		if strings.TrimSpace(resolved) == "" {
+			resolved = "main"
+		}
+		m.mu.Lock()
+		final := m.byRepo[repoRoot]
+		final.Remote = entry.Remote
+		final.BaseRef = resolved
+		final.Warming = false
+		m.byRepo[repoRoot] = final
+		m.mu.Unlock()
+		close(entry.Warmed)
+	}()
+}
+
+func worktreePathExists(path string) (bool, error) {
+	_, err := os.Stat(path)
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return false, err
+}
+
+func nextWorktreePath(repoRoot string) (string, error) {
+	worktreeRoot := ManagedWorktreeRoot(repoRoot)
+	for i := 1; i < 100; i++ {
+		candidate := filepath.Join(worktreeRoot, fmt.Sprintf("wt.%d", i))
+		_, statErr := os.Stat(candidate)
+		if errors.Is(statErr, os.ErrNotExist) {
+			return candidate, nil
+		}
+		if statErr != nil && !errors.Is(statErr, os.ErrNotExist) {
+			return "", statErr
+		}
+	}
+	return "", errors.New("no available worktree path")
+}
+
+func WorktreeLayoutRoot(repoRoot string, gitPath string) string {
+	repoRoot = strings.TrimSpace(repoRoot)
+	if repoRoot == "" || strings.TrimSpace(gitPath) == "" {
+		return repoRoot
+	}
+	commonDir, err := GitOutputInDir(repoRoot, gitPath, "rev-parse", "--path-format=absolute", "--git-common-dir")
+	if err != nil {
+		return repoRoot
+	}
+	commonDir = strings.TrimSpace(commonDir)
+	if strings.EqualFold(filepath.Base(commonDir), ".git") {
+		return filepath.Dir(commonDir)
+	}
+	return repoRoot
+}
+
+func EnsureManagedWorktreePath(repoRoot string, worktreePath string) error {
+	worktreeReal, err := RealPathOrAbs(worktreePath)
+	if err != nil {
+		return err
+	}
+	roots := managedWorktreeRoots(repoRoot)
+	for _, root := range roots {
+		rootReal, err := RealPathOrAbs(root)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(rootReal, worktreeReal)
+		if err != nil {
+			continue
+		}
+		rel = filepath.Clean(strings.TrimSpace(rel))
+		if rel == "." || rel == ".." || filepath.IsAbs(rel) || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		return nil
+	}
+	return fmt.Errorf("cannot delete worktree outside %s", roots[0])
+}
+
+// ManagedWorktreeRoot is <repo>.wt next to the repo unless worktree_dir is
+// configured. A repo's worktree_dir is used as is, resolved against the repo
+// root when relative; an absolute one from the global config and
+// worktree_volume both get a <repo>-<hash>.wt directory per repo, so repos
+// never share a wt.N numbering space.
+func ManagedWorktreeRoot(repoRoot string) string {
+	cfg, err := LoadConfigForDir(repoRoot)
+	if err != nil {
+		return defaultWorktreeRoot(repoRoot)
+	}
+	if cfg.WorktreeDir != "" {
+		dir := configuredDir(repoRoot, cfg.WorktreeDir)
+		if repoWorktreeDir(repoRoot) == "" && globalWorktreeDirShared(cfg.WorktreeDir) {
+			return namespacedWorktreeRoot(dir, repoRoot)
+		}
+		return dir
+	}
+	if cfg.WorktreeVolume != "" {
+		return namespacedWorktreeRoot(configuredDir(repoRoot, cfg.WorktreeVolume), repoRoot)
+	}
+	return defaultWorktreeRoot(repoRoot)
+}
+
+// managedWorktreeRoots is managedWorktreeRoot followed by the roots earlier
+// settings may have put worktrees under, so changing worktree_dir or
+// worktree_volume does not leave existing worktrees undeletable.
+func managedWorktreeRoots(repoRoot string) []string {
+	roots := []string{ManagedWorktreeRoot(repoRoot)}
+	add := func(root string) {
+		for _, r := range roots {
+			if SameSessionPath(r, root) {
+				return
+			}
+		}
+		roots = append(roots, root)
+	}
+	add(defaultWorktreeRoot(repoRoot))
+	if cfg, err := LoadConfig(); err == nil && cfg.WorktreeDir != "" && globalWorktreeDirShared(cfg.WorktreeDir) {
+		add(configuredDir(repoRoot, cfg.WorktreeDir))
+	}
+	return roots
+}
+
+func defaultWorktreeRoot(repoRoot string) string {
+	return filepath.Join(filepath.Dir(repoRoot), filepath.Base(repoRoot)+".wt")
+}
+
+// namespacedWorktreeRoot is the <repo>-<hash>.wt directory for repoRoot
+// under dir; the hash of the repo path keeps repos with the same name apart.
+func namespacedWorktreeRoot(dir string, repoRoot string) string {
+	repoReal, err := RealPathOrAbs(repoRoot)
+	if err != nil {
+		repoReal = repoRoot
+	}
+	return filepath.Join(dir, filepath.Base(repoRoot)+"-"+HashString(repoReal)[:8]+".wt")
+}
+
+// globalWorktreeDirShared reports whether a worktree_dir from the global
+// config names one directory for every repo rather than one per repo.
+func globalWorktreeDirShared(dir string) bool {
+	return filepath.IsAbs(dir) || dir == "~" || strings.HasPrefix(dir, "~/") || strings.HasPrefix(dir, "~"+string(filepath.Separator))
+}
+
+// repoWorktreeDir returns the worktree_dir the repo itself sets, or "".
+func repoWorktreeDir(repoRoot string) string {
+	if v := gitConfigValue(repoRoot, "wtx.worktreeDir"); v != "" {
+		return v
+	}
+	repoCfg, err := loadRepoConfig(repoRoot)
+	if err != nil {
+		return ""
+	}
+	return repoCfg.WorktreeDir
+}
+
+// configuredDir expands ~ in a configured directory and resolves a relative
+// one against the repo root.
+func configuredDir(repoRoot string, dir string) string {
+	if home, err := UserHomeDir(); err == nil && (dir == "~" || strings.HasPrefix(dir, "~/") || strings.HasPrefix(dir, "~"+string(filepath.Separator))) {
+		dir = filepath.Join(home, strings.TrimPrefix(dir, "~"))
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(repoRoot, dir)
+	}
+	return filepath.Clean(dir)
+}
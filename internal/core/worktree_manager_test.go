@@ -1,14 +1,17 @@
-package cmd
+package core
 
 import (
 	"errors"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestCommandErrorWithOutput_PrefersCommandOutput(t *testing.T) {
 	fallback := errors.New("exit status 128")
-	err := commandErrorWithOutput(fallback, []byte("fatal: worktree contains unstaged changes\n"))
+	err := CommandErrorWithOutput(fallback, []byte("fatal: worktree contains unstaged changes\n"))
 	if err == nil {
 		t.Fatalf("expected an error")
 	}
@@ -19,7 +22,7 @@ func TestCommandErrorWithOutput_PrefersCommandOutput(t *testing.T) {
 
 func TestCommandErrorWithOutput_FallsBackToOriginalError(t *testing.T) {
 	fallback := errors.New("exit status 128")
-	err := commandErrorWithOutput(fallback, []byte("   \n\t"))
+	err := CommandErrorWithOutput(fallback, []byte("   \n\t"))
 	if err == nil {
 		t.Fatalf("expected an error")
 	}
@@ -153,3 +156,96 @@ func TestIsExplicitRemoteBaseRef(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveBaseRefForNewBranch_UsesPersistedDefaultBranch(t *testing.T) {
+	repo := initGitReadTestRepo(t)
+	runGitInRepo(t, repo, "config", "wtx.defaultBranch", "develop")
+	runGitInRepo(t, repo, "config", "wtx.defaultBranchCheckedAt", strconv.FormatInt(time.Now().Unix(), 10))
+
+	if got := NewWorktreeManager(repo, nil).ResolveBaseRefForNewBranch(); got != "origin/develop" {
+		t.Fatalf("expected origin/develop, got %q", got)
+	}
+	if branch, stale := persistedDefaultBranch(repo); branch != "develop" || stale {
+		t.Fatalf("expected fresh develop, got %q stale=%v", branch, stale)
+	}
+
+	runGitInRepo(t, repo, "config", "wtx.defaultBranchCheckedAt", "0")
+	if _, stale := persistedDefaultBranch(repo); !stale {
+		t.Fatalf("expected old check to be stale")
+	}
+	// A stale value is still used right away while gh re-resolves in the background.
+	mgr := NewWorktreeManager(repo, nil)
+	if got := mgr.ResolveBaseRefForNewBranch(); got != "origin/develop" {
+		t.Fatalf("expected stale origin/develop, got %q", got)
+	}
+	if got := mgr.AwaitBaseRef(20 * time.Second); got == "" {
+		t.Fatalf("expected a base ref once the background refresh settles")
+	}
+	mgr.mu.Lock()
+	warming := mgr.byRepo[repo].Warming
+	mgr.mu.Unlock()
+	if warming {
+		t.Fatalf("background refresh did not finish")
+	}
+}
+
+func TestManagedWorktreeRootOnVolume(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(ConfigDirOverrideEnv, "")
+	volume := t.TempDir()
+	if err := SaveConfig(Config{WorktreeVolume: volume}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	repoA := initRenameTestRepo(t)
+	repoB := initRenameTestRepo(t)
+
+	rootA := ManagedWorktreeRoot(repoA)
+	if filepath.Dir(rootA) != volume || !strings.HasPrefix(filepath.Base(rootA), filepath.Base(repoA)+"-") || !strings.HasSuffix(rootA, ".wt") {
+		t.Fatalf("expected <repo>-<hash>.wt under %s, got %s", volume, rootA)
+	}
+	if rootB := ManagedWorktreeRoot(repoB); rootB == rootA {
+		t.Fatalf("expected repos to get separate roots, both got %s", rootA)
+	}
+
+	runGitInRepo(t, repoA, "config", "wtx.worktreeDir", "../custom-wt")
+	if got, want := ManagedWorktreeRoot(repoA), filepath.Join(filepath.Dir(repoA), "custom-wt"); got != want {
+		t.Fatalf("expected worktree_dir to win over worktree_volume, got %s want %s", got, want)
+	}
+}
+
+func TestManagedWorktreeRootNamespacesGlobalWorktreeDir(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(ConfigDirOverrideEnv, "")
+	shared := t.TempDir()
+	if err := SaveConfig(Config{WorktreeDir: shared}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	repoA := initRenameTestRepo(t)
+	repoB := initRenameTestRepo(t)
+
+	rootA := ManagedWorktreeRoot(repoA)
+	if filepath.Dir(rootA) != shared || !strings.HasSuffix(rootA, ".wt") {
+		t.Fatalf("expected a per-repo directory under %s, got %s", shared, rootA)
+	}
+	if rootB := ManagedWorktreeRoot(repoB); rootB == rootA {
+		t.Fatalf("expected repos to get separate roots, both got %s", rootA)
+	}
+	if err := EnsureManagedWorktreePath(repoA, filepath.Join(rootA, "wt.1")); err != nil {
+		t.Fatalf("expected the current root to be managed: %v", err)
+	}
+	legacy := filepath.Join(filepath.Dir(repoA), filepath.Base(repoA)+".wt", "wt.1")
+	if err := EnsureManagedWorktreePath(repoA, legacy); err != nil {
+		t.Fatalf("expected worktrees under the default root to stay deletable: %v", err)
+	}
+	if err := EnsureManagedWorktreePath(repoA, filepath.Join(shared, "wt.1")); err != nil {
+		t.Fatalf("expected worktrees under the old shared root to stay deletable: %v", err)
+	}
+	if err := EnsureManagedWorktreePath(repoA, filepath.Join(t.TempDir(), "elsewhere")); err == nil {
+		t.Fatalf("expected a path outside every managed root to be refused")
+	}
+
+	runGitInRepo(t, repoA, "config", "wtx.worktreeDir", shared)
+	if got := ManagedWorktreeRoot(repoA); got != shared {
+		t.Fatalf("expected a repo's own worktree_dir to be used as is, got %s", got)
+	}
+}
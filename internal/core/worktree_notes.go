@@ -0,0 +1,93 @@
+package core
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type worktreeNote struct {
+	RepoRoot     string `json:"repo_root"`
+	WorktreePath string `json:"worktree_path"`
+	Note         string `json:"note"`
+	UpdatedUnix  int64  `json:"updated_unix"`
+}
+
+type worktreeNotesFile struct {
+	Notes []worktreeNote `json:"notes"`
+}
+
+func worktreeNotesPath() (string, error) {
+	home, err := WtxHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "notes.json"), nil
+}
+
+func readAllWorktreeNotes() ([]worktreeNote, error) {
+	path, err := worktreeNotesPath()
+	if err != nil {
+		return nil, err
+	}
+	file := worktreeNotesFile{Notes: []worktreeNote{}}
+	if err := ReadJSONStore(path, &file); err != nil {
+		return nil, err
+	}
+	return file.Notes, nil
+}
+
+// ReadWorktreeNotes returns the notes for repoRoot keyed by cleaned worktree
+// path.
+func ReadWorktreeNotes(repoRoot string) (map[string]string, error) {
+	notes, err := readAllWorktreeNotes()
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]string{}
+	for _, n := range notes {
+		if SameSessionPath(n.RepoRoot, repoRoot) && strings.TrimSpace(n.Note) != "" {
+			out[filepath.Clean(n.WorktreePath)] = n.Note
+		}
+	}
+	return out, nil
+}
+
+// SetWorktreeNote stores note for the worktree, or removes it when note is
+// empty.
+func SetWorktreeNote(repoRoot string, worktreePath string, note string) error {
+	repoRoot = strings.TrimSpace(repoRoot)
+	worktreePath = strings.TrimSpace(worktreePath)
+	if repoRoot == "" || worktreePath == "" {
+		return errors.New("worktree path required")
+	}
+	note = strings.Join(strings.Fields(note), " ")
+	path, err := worktreeNotesPath()
+	if err != nil {
+		return err
+	}
+	var file worktreeNotesFile
+	return UpdateJSONStore(path, &file, func() (bool, error) {
+		next := make([]worktreeNote, 0, len(file.Notes)+1)
+		for _, n := range file.Notes {
+			if SameSessionPath(n.RepoRoot, repoRoot) && SameSessionPath(n.WorktreePath, worktreePath) {
+				continue
+			}
+			next = append(next, n)
+		}
+		if note == "" && len(next) == len(file.Notes) {
+			return false, nil
+		}
+		if note != "" {
+			next = append(next, worktreeNote{
+				RepoRoot:     repoRoot,
+				WorktreePath: filepath.Clean(worktreePath),
+				Note:         note,
+				UpdatedUnix:  time.Now().Unix(),
+			})
+		}
+		file.Notes = next
+		return true, nil
+	})
+}
@@ -0,0 +1,33 @@
+package core
+
+import "testing"
+
+func TestSetWorktreeNoteKeyedByRepoAndPath(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if err := SetWorktreeNote("/repo", "/repo.wt/wt.1", "  waiting on   design review "); err != nil {
+		t.Fatalf("set note: %v", err)
+	}
+	if err := SetWorktreeNote("/other", "/repo.wt/wt.1", "perf experiment"); err != nil {
+		t.Fatalf("set note: %v", err)
+	}
+	notes, err := ReadWorktreeNotes("/repo")
+	if err != nil {
+		t.Fatalf("read notes: %v", err)
+	}
+	if len(notes) != 1 || notes["/repo.wt/wt.1"] != "waiting on design review" {
+		t.Fatalf("expected only the /repo note, normalized, got %+v", notes)
+	}
+
+	if err := SetWorktreeNote("/repo", "/repo.wt/wt.1/", ""); err != nil {
+		t.Fatalf("clear note: %v", err)
+	}
+	notes, _ = ReadWorktreeNotes("/repo")
+	if len(notes) != 0 {
+		t.Fatalf("expected the note removed, got %+v", notes)
+	}
+	notes, _ = ReadWorktreeNotes("/other")
+	if notes["/repo.wt/wt.1"] != "perf experiment" {
+		t.Fatalf("expected the other repo's note kept, got %+v", notes)
+	}
+}
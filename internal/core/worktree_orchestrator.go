@@ -0,0 +1,216 @@
+package core
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+type WorktreeOrchestrator struct {
+	mgr     *WorktreeManager
+	LockMgr *LockManager
+	prMgr   *GHManager
+	refresh statusRefreshCache
+	// Daemon makes status and PR lookups go through a running `wtx daemon`
+	// first, falling back to in-process work when none is listening.
+	Daemon bool
+	// KeepIdleLocks stops Status from releasing the locks of idle agents
+	// when agent_idle_release is set, for callers that only observe.
+	KeepIdleLocks bool
+}
+
+func NewWorktreeOrchestrator(mgr *WorktreeManager, lockMgr *LockManager, prMgr *GHManager) *WorktreeOrchestrator {
+	return &WorktreeOrchestrator{mgr: mgr, LockMgr: lockMgr, prMgr: prMgr, Daemon: true}
+}
+
+func (o *WorktreeOrchestrator) Status() WorktreeStatus {
+	if o == nil || o.mgr == nil {
+		return WorktreeStatus{}
+	}
+	defer timeStage("status refresh")()
+	if o.Daemon {
+		if status, err := daemonStatus(o.mgr.Cwd); err == nil {
+			return status
+		}
+	}
+	status := o.mgr.ListForStatusBase()
+	if status.Err != nil || !status.InRepo || strings.TrimSpace(status.RepoRoot) == "" || o.LockMgr == nil {
+		return status
+	}
+
+	var idleThreshold time.Duration
+	idleRelease := false
+	if cfg, err := LoadConfig(); err == nil {
+		idleThreshold = agentIdleThreshold(cfg)
+		idleRelease = cfg.AgentIdleRelease && !o.KeepIdleLocks
+	}
+	paneActivity := sync.OnceValue(tmuxPaneActivityByPID)
+
+	// Lock and path checks hit the filesystem once or twice per worktree,
+	// which adds up on network storage, so run them concurrently. Worktrees
+	// whose files are unchanged since the last poll reuse the previous result.
+	checks := make([]worktreeCheck, len(status.Worktrees))
+	paths := make([]string, len(status.Worktrees))
+	fingerprints := make([]worktreeFingerprint, len(status.Worktrees))
+	sem := make(chan struct{}, statusCheckWorkers)
+	var wg sync.WaitGroup
+	done := timeStage("lock checks")
+	for i, wt := range status.Worktrees {
+		paths[i] = wt.Path
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fingerprints[i] = o.worktreeFingerprint(status.RepoRoot, wt.Path)
+			if cached, ok := o.refresh.cachedCheck(status.RepoRoot, wt.Path, fingerprints[i]); ok {
+				checks[i] = cached
+				return
+			}
+			checks[i] = o.checkWorktree(status.RepoRoot, wt.Path, idleThreshold, idleRelease, paneActivity)
+		}()
+	}
+	wg.Wait()
+	done()
+	o.refresh.storeChecks(status.RepoRoot, paths, fingerprints, checks)
+	done = timeStage("branch commits")
+	commits := o.refresh.branchCommits(status.RepoRoot, o.mgr.BranchCommits)
+	done()
+	notes, _ := ReadWorktreeNotes(status.RepoRoot)
+	pins, _ := ReadWorktreePins(status.RepoRoot)
+	bootstraps := readWorktreeJobStates(JobBootstrap)
+	tests := readWorktreeJobStates(JobTests)
+	now := time.Now()
+
+	orphaned := make([]WorktreeInfo, 0)
+	for i := range status.Worktrees {
+		check := checks[i]
+		if check.Err != nil {
+			status.Err = check.Err
+			return status
+		}
+		if !check.Exists {
+			status.Worktrees[i].Available = false
+			status.Worktrees[i].LastUsedUnix = 0
+			orphaned = append(orphaned, status.Worktrees[i])
+			continue
+		}
+		status.Worktrees[i].Available = check.Available
+		status.Worktrees[i].Broken = check.Broken
+		status.Worktrees[i].Idle = check.Idle
+		status.Worktrees[i].AgentExit = check.AgentExit
+		status.Worktrees[i].LockHolder = check.Holder
+		status.Worktrees[i].LastUsedUnix = check.LastUsed
+		status.Worktrees[i].LastCommit = commits[status.Worktrees[i].Branch]
+		status.Worktrees[i].Note = notes[filepath.Clean(status.Worktrees[i].Path)]
+		status.Worktrees[i].Pinned = pins[filepath.Clean(status.Worktrees[i].Path)]
+		status.Worktrees[i].Bootstrap = bootstrapLabel(bootstraps[filepath.Clean(status.Worktrees[i].Path)])
+		status.Worktrees[i].Tests = testsLabel(tests[filepath.Clean(status.Worktrees[i].Path)], now)
+	}
+	status.Orphaned = orphaned
+	return status
+}
+
+const statusCheckWorkers = 8
+
+type worktreeCheck struct {
+	Exists    bool
+	Available bool
+	Idle      bool
+	LastUsed  int64
+	AgentExit string
+	Holder    LockHolder
+	Broken    string
+	Err       error
+}
+
+func (o *WorktreeOrchestrator) checkWorktree(repoRoot string, path string, idleThreshold time.Duration, idleRelease bool, paneActivity func() map[int]int64) worktreeCheck {
+	exists, err := worktreePathExists(path)
+	if err != nil || !exists {
+		return worktreeCheck{Err: err}
+	}
+	check := worktreeCheck{Exists: true, LastUsed: worktreeLastUsedUnix(repoRoot, path)}
+	if check.Broken = WorktreeAdminProblem(path); check.Broken != "" {
+		return check
+	}
+	available, err := o.LockMgr.IsAvailable(repoRoot, path)
+	if err != nil {
+		return worktreeCheck{Err: err}
+	}
+	if !available && idleThreshold > 0 {
+		if pid, ok := o.LockMgr.HolderPID(repoRoot, path); ok {
+			check.Idle = paneIdle(paneActivity(), pid, idleThreshold, time.Now())
+		}
+		if check.Idle && idleRelease {
+			if err := o.LockMgr.ForceUnlock(repoRoot, path); err == nil {
+				available = true
+				check.Idle = false
+			}
+		}
+	}
+	check.Available = available
+	if !available {
+		check.Holder, _ = o.LockMgr.Holder(repoRoot, path)
+	}
+	if available {
+		if state, ok := ReadTmuxAgentState(path); ok {
+			check.AgentExit = FormatAgentExit(state)
+		}
+	}
+	return check
+}
+
+func (o *WorktreeOrchestrator) PRDataForStatusWithError(status WorktreeStatus, force bool) (map[string]PRData, error) {
+	if o == nil || o.prMgr == nil {
+		return map[string]PRData{}, nil
+	}
+	if !status.InRepo || strings.TrimSpace(status.RepoRoot) == "" {
+		return map[string]PRData{}, nil
+	}
+	defer timeStage("pr refresh")()
+	branches := make([]string, 0, len(status.Worktrees))
+	for _, wt := range status.Worktrees {
+		b := strings.TrimSpace(wt.Branch)
+		if b == "" || b == "detached" {
+			continue
+		}
+		branches = append(branches, b)
+	}
+	return o.PRDataForBranchesWithError(status.RepoRoot, branches, force)
+}
+
+func (o *WorktreeOrchestrator) PRDataForBranchesWithError(repoRoot string, branches []string, force bool) (map[string]PRData, error) {
+	if o == nil || o.prMgr == nil {
+		return map[string]PRData{}, nil
+	}
+	repoRoot = strings.TrimSpace(repoRoot)
+	if repoRoot == "" {
+		return map[string]PRData{}, nil
+	}
+	if o.Daemon {
+		return LookupPRData(o.prMgr, repoRoot, branches, force)
+	}
+	if force {
+		return o.prMgr.PRDataByBranchForce(repoRoot, branches)
+	}
+	return o.prMgr.PRDataByBranch(repoRoot, branches)
+}
+
+func (o *WorktreeOrchestrator) ResolveOpenTargetSlot(slots []OpenSlotState, targetBranch string, targetIsNew bool) (OpenSlotState, bool) {
+	branch := strings.TrimSpace(targetBranch)
+	if !targetIsNew && branch != "" {
+		for _, slot := range slots {
+			if strings.TrimSpace(slot.Branch) == branch {
+				return slot, true
+			}
+		}
+	}
+	if reusable, ok := findReusableOpenSlot(slots, targetBranch); ok {
+		return reusable, true
+	}
+	if available, ok := findAnyAvailableOpenSlot(slots); ok {
+		return available, true
+	}
+	return OpenSlotState{}, false
+}
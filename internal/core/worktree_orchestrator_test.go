@@ -0,0 +1,148 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveOpenTargetSlot_ExistingBranchUsesAttachedWorktree(t *testing.T) {
+	o := &WorktreeOrchestrator{}
+	slots := []OpenSlotState{
+		{Path: "/wt/1", Branch: "feat/cli", Locked: true},
+		{Path: "/wt/2", Branch: "main"},
+	}
+
+	got, ok := o.ResolveOpenTargetSlot(slots, "feat/cli", false)
+	if !ok {
+		t.Fatalf("expected slot")
+	}
+	if got.Path != "/wt/1" {
+		t.Fatalf("expected attached worktree /wt/1, got %q", got.Path)
+	}
+}
+
+func TestResolveOpenTargetSlot_ExistingBranchFallsBackToAvailableSlot(t *testing.T) {
+	o := &WorktreeOrchestrator{}
+	slots := []OpenSlotState{
+		{Path: "/wt/1", Branch: "main", Locked: true},
+		{Path: "/wt/2", Branch: "dev"},
+	}
+
+	got, ok := o.ResolveOpenTargetSlot(slots, "feat/cli", false)
+	if !ok {
+		t.Fatalf("expected slot")
+	}
+	if got.Path != "/wt/2" {
+		t.Fatalf("expected available fallback /wt/2, got %q", got.Path)
+	}
+}
+
+func TestResolveOpenTargetSlot_NewBranchIgnoresLockedMatchingSlot(t *testing.T) {
+	o := &WorktreeOrchestrator{}
+	slots := []OpenSlotState{
+		{Path: "/wt/1", Branch: "feat/new", Locked: true},
+		{Path: "/wt/2", Branch: "main"},
+	}
+
+	got, ok := o.ResolveOpenTargetSlot(slots, "feat/new", true)
+	if !ok {
+		t.Fatalf("expected slot")
+	}
+	if got.Path != "/wt/2" {
+		t.Fatalf("expected available slot /wt/2, got %q", got.Path)
+	}
+}
+
+func TestResolveOpenTargetSlot_NoSlotAvailable(t *testing.T) {
+	o := &WorktreeOrchestrator{}
+	slots := []OpenSlotState{
+		{Path: "/wt/1", Branch: "main", Locked: true},
+		{Path: "/wt/2", Branch: "dev", Dirty: true},
+	}
+
+	_, ok := o.ResolveOpenTargetSlot(slots, "feat/cli", false)
+	if ok {
+		t.Fatalf("expected no slot")
+	}
+}
+
+func TestStatusChecksWorktreesConcurrentlyAndKeepsOrder(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo := initTestRepo(t)
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("-c", "user.name=wtx", "-c", "user.email=wtx@example.com", "commit", "--allow-empty", "-m", "init")
+	base := t.TempDir()
+	for i := 0; i < statusCheckWorkers+4; i++ {
+		git("worktree", "add", "-b", fmt.Sprintf("slot/%02d", i), filepath.Join(base, fmt.Sprintf("wt.%02d", i)))
+	}
+	orphan := filepath.Join(base, "wt.03")
+	if err := os.RemoveAll(orphan); err != nil {
+		t.Fatalf("remove worktree dir: %v", err)
+	}
+
+	lockMgr := NewLockManager()
+	status := NewWorktreeOrchestrator(NewWorktreeManager(repo, lockMgr), lockMgr, nil).Status()
+	if status.Err != nil {
+		t.Fatalf("status: %v", status.Err)
+	}
+	if len(status.Worktrees) != statusCheckWorkers+5 {
+		t.Fatalf("expected %d worktrees, got %d", statusCheckWorkers+5, len(status.Worktrees))
+	}
+	for i, wt := range status.Worktrees[1:] {
+		if want := fmt.Sprintf("slot/%02d", i); wt.Branch != want {
+			t.Fatalf("expected worktree %d on %s, got %s", i, want, wt.Branch)
+		}
+		if wantAvailable := wt.Path != orphan; wt.Available != wantAvailable {
+			t.Fatalf("%s: expected available=%v", wt.Path, wantAvailable)
+		}
+	}
+	if len(status.Orphaned) != 1 || status.Orphaned[0].Path != orphan {
+		t.Fatalf("expected %s to be orphaned, got %+v", orphan, status.Orphaned)
+	}
+}
+
+func TestStatusReusesChecksForUnchangedWorktrees(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo := initRenameTestRepo(t)
+	wtPath := filepath.Join(t.TempDir(), "wt")
+	runGitInRepo(t, repo, "worktree", "add", "-q", "-b", "feature", wtPath)
+
+	lockMgr := NewLockManager()
+	orch := NewWorktreeOrchestrator(NewWorktreeManager(repo, lockMgr), lockMgr, nil)
+	status := orch.Status()
+	if status.Err != nil || len(status.Worktrees) != 2 {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+	wtPath = status.Worktrees[1].Path
+
+	// Mark the cached entry so a reused result is observable.
+	orch.refresh.mu.Lock()
+	cached := orch.refresh.checks[wtPath]
+	cached.check.AgentExit = "cached"
+	orch.refresh.checks[wtPath] = cached
+	orch.refresh.mu.Unlock()
+	if got := orch.Status().Worktrees[1]; got.AgentExit != "cached" {
+		t.Fatalf("expected unchanged worktree to reuse its check, got %+v", got)
+	}
+
+	if _, err := lockMgr.AcquireForOwner(repo, wtPath, "someone-else", os.Getpid()); err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	if got := orch.Status().Worktrees[1]; got.Available || got.AgentExit == "cached" {
+		t.Fatalf("expected lock change to trigger a recheck, got %+v", got)
+	}
+
+	runGitInRepo(t, wtPath, "commit", "-q", "--allow-empty", "-m", "second")
+	if got := orch.Status().Worktrees[1].LastCommit.Subject; got != "second" {
+		t.Fatalf("expected moved ref to refresh commit data, got %q", got)
+	}
+}
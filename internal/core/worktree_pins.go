@@ -0,0 +1,81 @@
+package core
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+)
+
+type worktreePin struct {
+	RepoRoot     string `json:"repo_root"`
+	WorktreePath string `json:"worktree_path"`
+}
+
+type worktreePinsFile struct {
+	Pins []worktreePin `json:"pins"`
+}
+
+func worktreePinsPath() (string, error) {
+	home, err := WtxHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "pins.json"), nil
+}
+
+func readAllWorktreePins() ([]worktreePin, error) {
+	path, err := worktreePinsPath()
+	if err != nil {
+		return nil, err
+	}
+	file := worktreePinsFile{Pins: []worktreePin{}}
+	if err := ReadJSONStore(path, &file); err != nil {
+		return nil, err
+	}
+	return file.Pins, nil
+}
+
+// ReadWorktreePins returns the pinned worktree paths for repoRoot, cleaned.
+func ReadWorktreePins(repoRoot string) (map[string]bool, error) {
+	pins, err := readAllWorktreePins()
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]bool{}
+	for _, p := range pins {
+		if SameSessionPath(p.RepoRoot, repoRoot) {
+			out[filepath.Clean(p.WorktreePath)] = true
+		}
+	}
+	return out, nil
+}
+
+func SetWorktreePinned(repoRoot string, worktreePath string, pinned bool) error {
+	repoRoot = strings.TrimSpace(repoRoot)
+	worktreePath = strings.TrimSpace(worktreePath)
+	if repoRoot == "" || worktreePath == "" {
+		return errors.New("worktree path required")
+	}
+	path, err := worktreePinsPath()
+	if err != nil {
+		return err
+	}
+	var file worktreePinsFile
+	return UpdateJSONStore(path, &file, func() (bool, error) {
+		next := make([]worktreePin, 0, len(file.Pins)+1)
+		for _, p := range file.Pins {
+			if SameSessionPath(p.RepoRoot, repoRoot) && SameSessionPath(p.WorktreePath, worktreePath) {
+				continue
+			}
+			next = append(next, p)
+		}
+		if !pinned && len(next) == len(file.Pins) {
+			return false, nil
+		}
+		if pinned {
+			next = append(next, worktreePin{RepoRoot: repoRoot, WorktreePath: filepath.Clean(worktreePath)})
+		}
+		file.Pins = next
+		return true, nil
+	})
+}
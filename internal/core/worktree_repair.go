@@ -0,0 +1,78 @@
+package core
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// adminDirElsewhere is the problem `git worktree repair <path>` fixes; the
+// others are fixed by a plain repair from the main worktree.
+const adminDirElsewhere = "admin dir points elsewhere"
+
+// WorktreeAdminProblem describes why the linked worktree at path no longer
+// connects to its repo, or returns "" when it does. Unlike an orphaned
+// worktree the directory is still there; `git worktree repair` can usually
+// fix it.
+func WorktreeAdminProblem(path string) string {
+	dotGit := filepath.Join(path, ".git")
+	info, err := os.Stat(dotGit)
+	if err != nil {
+		return "missing .git file"
+	}
+	if info.IsDir() {
+		return ""
+	}
+	gitDir, _, err := ResolveGitDirs(path)
+	if err != nil {
+		return "unreadable .git file"
+	}
+	if _, err := os.Stat(filepath.Join(gitDir, "HEAD")); err != nil {
+		return "admin dir missing"
+	}
+	data, err := os.ReadFile(filepath.Join(gitDir, "gitdir"))
+	if err != nil {
+		return "admin dir missing gitdir"
+	}
+	backlink := strings.TrimSpace(string(data))
+	if !filepath.IsAbs(backlink) {
+		backlink = filepath.Join(gitDir, backlink)
+	}
+	linked, err := os.Stat(filepath.Dir(filepath.Clean(backlink)))
+	if err != nil {
+		return adminDirElsewhere
+	}
+	if here, err := os.Stat(path); err == nil && !os.SameFile(linked, here) {
+		return adminDirElsewhere
+	}
+	return ""
+}
+
+// RepairWorktrees runs `git worktree repair` for the repo, which rewrites
+// damaged .git files, then again for slots under the managed root whose admin
+// dir points elsewhere because they were moved or restored from a backup.
+func (m *WorktreeManager) RepairWorktrees(progress io.Writer) error {
+	gitPath, repoRoot, err := RequireGitContext(m.Cwd)
+	if err != nil {
+		return err
+	}
+	layoutRoot := WorktreeLayoutRoot(repoRoot, gitPath)
+	if _, err := streamCommandInDir(layoutRoot, gitPath, progress, "worktree", "repair"); err != nil {
+		return err
+	}
+	managedRoot := ManagedWorktreeRoot(layoutRoot)
+	entries, _ := os.ReadDir(managedRoot)
+	args := []string{"worktree", "repair"}
+	for _, entry := range entries {
+		slot := filepath.Join(managedRoot, entry.Name())
+		if WorktreeAdminProblem(slot) == adminDirElsewhere {
+			args = append(args, slot)
+		}
+	}
+	if len(args) == 2 {
+		return nil
+	}
+	_, err = streamCommandInDir(layoutRoot, gitPath, progress, args...)
+	return err
+}
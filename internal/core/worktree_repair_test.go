@@ -0,0 +1,53 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWorktreeAdminProblemAndRepair(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo := initRenameTestRepo(t)
+	managedRoot := ManagedWorktreeRoot(repo)
+	missing := filepath.Join(managedRoot, "wt.1")
+	dangling := filepath.Join(managedRoot, "wt.2")
+	moved := filepath.Join(managedRoot, "wt.3")
+	runGitInRepo(t, repo, "worktree", "add", "-q", "-b", "missing", missing)
+	runGitInRepo(t, repo, "worktree", "add", "-q", "-b", "dangling", dangling)
+	runGitInRepo(t, repo, "worktree", "add", "-q", "-b", "moved", moved)
+
+	if problem := WorktreeAdminProblem(repo); problem != "" {
+		t.Fatalf("expected main worktree to be healthy, got %q", problem)
+	}
+	if problem := WorktreeAdminProblem(missing); problem != "" {
+		t.Fatalf("expected new worktree to be healthy, got %q", problem)
+	}
+	if err := os.Remove(filepath.Join(missing, ".git")); err != nil {
+		t.Fatalf("remove .git: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dangling, ".git"), []byte("gitdir: "+filepath.Join(repo, ".git", "worktrees", "gone")+"\n"), 0o644); err != nil {
+		t.Fatalf("write .git: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, ".git", "worktrees", "wt.3", "gitdir"), []byte(filepath.Join(t.TempDir(), "old", ".git")+"\n"), 0o644); err != nil {
+		t.Fatalf("write gitdir: %v", err)
+	}
+	for path, want := range map[string]string{
+		missing:  "missing .git file",
+		dangling: "admin dir missing",
+		moved:    "admin dir points elsewhere",
+	} {
+		if got := WorktreeAdminProblem(path); got != want {
+			t.Fatalf("%s: expected %q, got %q", filepath.Base(path), want, got)
+		}
+	}
+
+	if err := NewWorktreeManager(repo, NewLockManager()).RepairWorktrees(nil); err != nil {
+		t.Fatalf("RepairWorktrees: %v", err)
+	}
+	for _, path := range []string{missing, dangling, moved} {
+		if problem := WorktreeAdminProblem(path); problem != "" {
+			t.Fatalf("%s: expected repair to fix %q", filepath.Base(path), problem)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	syncModeCopy    = "copy"
+	syncModeSymlink = "symlink"
+	syncOnCreate    = "create"
+	SyncOnOpen      = "open"
+)
+
+// SyncFile is a sync_files rule. Source is resolved like worktree_dir: ~ is
+// expanded and a relative path is taken from the main checkout. Destination
+// is relative to the worktree and defaults to Source.
+type SyncFile struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination,omitempty"`
+	// Mode is copy (the default) or symlink.
+	Mode string `json:"mode,omitempty"`
+	// When is create (the default) or open.
+	When string `json:"when,omitempty"`
+	// fromRepo marks a rule from .wtx.json, whose source must stay inside
+	// the main checkout.
+	fromRepo bool
+}
+
+// syncFilesState is what wtx last wrote at each destination, so an open rule
+// can tell a file the user edited in the worktree from its own copy.
+type syncFilesState struct {
+	// Worktrees maps a cleaned worktree path to destination fingerprints.
+	Worktrees map[string]map[string]string `json:"worktrees"`
+}
+
+func syncFilesStatePath() (string, error) {
+	home, err := WtxHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "sync_files.json"), nil
+}
+
+func normalizeSyncFiles(rules []SyncFile) []SyncFile {
+	out := make([]SyncFile, 0, len(rules))
+	for _, r := range rules {
+		r.Source = strings.TrimSpace(r.Source)
+		r.Destination = strings.TrimSpace(r.Destination)
+		r.Mode = strings.ToLower(strings.TrimSpace(r.Mode))
+		r.When = strings.ToLower(strings.TrimSpace(r.When))
+		if r.Source == "" {
+			continue
+		}
+		if r.Destination == "" {
+			r.Destination = r.Source
+		}
+		if r.Mode == "" {
+			r.Mode = syncModeCopy
+		}
+		if r.When == "" {
+			r.When = syncOnCreate
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// SyncFiles applies the sync_files rules for when to worktreePath. Rules run
+// in order, so a later rule for the same destination wins.
+func (m *WorktreeManager) SyncFiles(worktreePath string, when string) error {
+	gitPath, repoRoot, err := RequireGitContext(m.Cwd)
+	if err != nil {
+		return err
+	}
+	cfg, err := LoadConfigForDir(worktreePath)
+	if err != nil {
+		return err
+	}
+	return syncWorktreeFiles(WorktreeLayoutRoot(repoRoot, gitPath), worktreePath, cfg.SyncFiles, when)
+}
+
+// syncWorktreeFiles replaces the destination, so every worktree ends up with
+// the same files whatever it had before, except that an open rule keeps a
+// destination changed since wtx last wrote it. Missing sources are skipped: a
+// rule in .wtx.json may name a file only some checkouts have.
+func syncWorktreeFiles(sourceRoot string, worktreePath string, rules []SyncFile, when string) error {
+	statePath, err := syncFilesStatePath()
+	if err != nil {
+		return err
+	}
+	var state syncFilesState
+	if err := ReadJSONStore(statePath, &state); err != nil {
+		DebugLog("sync_files state unreadable", "err", err.Error())
+	}
+	last := state.Worktrees[filepath.Clean(worktreePath)]
+	written := map[string]string{}
+	var kept []string
+	for _, rule := range rules {
+		if rule.When != when {
+			continue
+		}
+		dst := filepath.Join(worktreePath, rule.Destination)
+		if recorded, ok := last[rule.Destination]; ok && rule.When == SyncOnOpen {
+			if current := syncFingerprint(dst); current != "" && current != recorded {
+				kept = append(kept, rule.Destination)
+				continue
+			}
+		}
+		if err := syncWorktreeFile(sourceRoot, worktreePath, rule); err != nil {
+			return fmt.Errorf("sync_files %s: %w", rule.Source, err)
+		}
+		if fingerprint := syncFingerprint(dst); fingerprint != "" {
+			written[rule.Destination] = fingerprint
+		}
+	}
+	if len(written) > 0 {
+		if err := recordSyncedFiles(statePath, worktreePath, written); err != nil {
+			DebugLog("sync_files state not saved", "err", err.Error())
+		}
+	}
+	if len(kept) > 0 {
+		return fmt.Errorf("sync_files kept %s: changed since the last sync", strings.Join(kept, ", "))
+	}
+	return nil
+}
+
+func recordSyncedFiles(statePath string, worktreePath string, written map[string]string) error {
+	key := filepath.Clean(worktreePath)
+	var state syncFilesState
+	return UpdateJSONStore(statePath, &state, func() (bool, error) {
+		if state.Worktrees == nil {
+			state.Worktrees = map[string]map[string]string{}
+		}
+		if state.Worktrees[key] == nil {
+			state.Worktrees[key] = map[string]string{}
+		}
+		for dst, fingerprint := range written {
+			state.Worktrees[key][dst] = fingerprint
+		}
+		return true, nil
+	})
+}
+
+// clearSyncedFiles forgets what was synced into the worktree, since its path
+// is reused for the next worktree.
+func clearSyncedFiles(worktreePath string) error {
+	statePath, err := syncFilesStatePath()
+	if err != nil {
+		return err
+	}
+	key := filepath.Clean(worktreePath)
+	var state syncFilesState
+	return UpdateJSONStore(statePath, &state, func() (bool, error) {
+		if _, ok := state.Worktrees[key]; !ok {
+			return false, nil
+		}
+		delete(state.Worktrees, key)
+		return true, nil
+	})
+}
+
+// syncFingerprint identifies what is at path: a symlink by its target and a
+// file by its content. It is "" when there is nothing there.
+func syncFingerprint(path string) string {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return ""
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(path)
+		if err != nil {
+			return ""
+		}
+		return "link:" + target
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return HashString(string(data))
+}
+
+func syncWorktreeFile(sourceRoot string, worktreePath string, rule SyncFile) error {
+	if rule.Mode != syncModeCopy && rule.Mode != syncModeSymlink {
+		return fmt.Errorf("mode %q must be %s or %s", rule.Mode, syncModeCopy, syncModeSymlink)
+	}
+	if rule.When != syncOnCreate && rule.When != SyncOnOpen {
+		return fmt.Errorf("when %q must be %s or %s", rule.When, syncOnCreate, SyncOnOpen)
+	}
+	if filepath.IsAbs(rule.Destination) {
+		return fmt.Errorf("destination %q must be relative", rule.Destination)
+	}
+	dst := filepath.Join(worktreePath, rule.Destination)
+	if rel, err := filepath.Rel(worktreePath, dst); err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("destination %q is outside the worktree", rule.Destination)
+	}
+	if rule.fromRepo && (filepath.IsAbs(rule.Source) || strings.HasPrefix(rule.Source, "~")) {
+		return fmt.Errorf("source %q in %s must be relative to the main checkout", rule.Source, RepoConfigFileName)
+	}
+	src := configuredDir(sourceRoot, rule.Source)
+	if src == dst {
+		return nil
+	}
+	info, err := os.Stat(src)
+	if err != nil {
+		if os.IsNotExist(err) {
+			DebugLog("sync_files source missing", "source", src)
+			return nil
+		}
+		return err
+	}
+	if rule.fromRepo && !pathWithin(sourceRoot, src) {
+		return fmt.Errorf("source %q in %s resolves outside the main checkout", rule.Source, RepoConfigFileName)
+	}
+	if existing, err := os.Lstat(dst); err == nil {
+		if existing.Mode()&os.ModeSymlink != 0 && rule.Mode == syncModeSymlink {
+			if target, err := os.Readlink(dst); err == nil && target == src {
+				return nil
+			}
+		}
+		if existing.IsDir() {
+			return fmt.Errorf("destination %q is a directory", rule.Destination)
+		}
+		if err := os.Remove(dst); err != nil {
+			return err
+		}
+	}
+	if rule.Mode == syncModeSymlink {
+		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+			return err
+		}
+		return os.Symlink(src, dst)
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("copy needs a regular file; use mode %s for directories", syncModeSymlink)
+	}
+	return CopyFile(src, dst, info.Mode().Perm())
+}
+
+// pathWithin reports whether path, with symlinks resolved, is inside root.
+func pathWithin(root string, path string) bool {
+	rootReal, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return false
+	}
+	pathReal, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(rootReal, pathReal)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
@@ -0,0 +1,145 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSyncFilesOnCreateAndOpen(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	repo := initRenameTestRepo(t)
+	secrets := filepath.Join(home, "secrets.env")
+	if err := os.WriteFile(secrets, []byte("TOKEN=1\n"), 0o600); err != nil {
+		t.Fatalf("write secrets: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "local.json"), []byte("{}\n"), 0o644); err != nil {
+		t.Fatalf("write local.json: %v", err)
+	}
+	// Sources outside the repo can only come from the global config.
+	t.Setenv(ConfigDirOverrideEnv, "")
+	if err := os.MkdirAll(filepath.Join(home, ".wtx"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	global := `{"sync_files": [
+		{"source": "~/secrets.env", "destination": ".env"},
+		{"source": "~/secrets.env", "destination": ".env.open", "when": "open"}
+	]}`
+	if err := os.WriteFile(filepath.Join(home, ".wtx", "config.json"), []byte(global), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	repoCfg := `{"sync_files": [
+		{"source": "local.json", "destination": "config/local.json", "mode": "symlink"},
+		{"source": "missing.txt"}
+	]}`
+	writeTrustedRepoConfig(t, repo, repoCfg)
+	// Worktrees read .wtx.json from their own checkout.
+	runGitInRepo(t, repo, "add", RepoConfigFileName)
+	runGitInRepo(t, repo, "commit", "-m", "wtx config")
+
+	mgr := NewWorktreeManager(repo, NewLockManager())
+	wt, err := mgr.CreateWorktree("feature", "", nil)
+	if err != nil {
+		t.Fatalf("CreateWorktree: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(wt.Path, ".env")); err != nil || string(data) != "TOKEN=1\n" {
+		t.Fatalf("expected .env copied on create, got %q (%v)", data, err)
+	}
+	link := filepath.Join(wt.Path, "config", "local.json")
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Fatalf("expected config/local.json to be a symlink, got %v (%v)", info, err)
+	}
+	if data, err := os.ReadFile(link); err != nil || string(data) != "{}\n" {
+		t.Fatalf("expected the symlink to reach the main checkout, got %q (%v)", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(wt.Path, ".env.open")); !os.IsNotExist(err) {
+		t.Fatalf("expected open rules to wait for an open, stat err=%v", err)
+	}
+
+	if err := os.WriteFile(secrets, []byte("TOKEN=2\n"), 0o600); err != nil {
+		t.Fatalf("rewrite secrets: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(wt.Path, ".env.open"), []byte("stale\n"), 0o600); err != nil {
+		t.Fatalf("write stale file: %v", err)
+	}
+	if err := NewWorktreeManager(wt.Path, NewLockManager()).SyncFiles(wt.Path, SyncOnOpen); err != nil {
+		t.Fatalf("SyncFiles: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(wt.Path, ".env.open")); string(data) != "TOKEN=2\n" {
+		t.Fatalf("expected the open rule to replace the destination, got %q", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(wt.Path, ".env")); string(data) != "TOKEN=1\n" {
+		t.Fatalf("expected create rules left alone on open, got %q", data)
+	}
+
+	// An open rule keeps a destination edited since wtx last wrote it.
+	if err := os.WriteFile(filepath.Join(wt.Path, ".env.open"), []byte("TOKEN=mine\n"), 0o600); err != nil {
+		t.Fatalf("edit synced file: %v", err)
+	}
+	if err := os.WriteFile(secrets, []byte("TOKEN=3\n"), 0o600); err != nil {
+		t.Fatalf("rewrite secrets: %v", err)
+	}
+	err = NewWorktreeManager(wt.Path, NewLockManager()).SyncFiles(wt.Path, SyncOnOpen)
+	if err == nil || !strings.Contains(err.Error(), "kept .env.open") {
+		t.Fatalf("expected the edited file to be kept, got %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(wt.Path, ".env.open")); string(data) != "TOKEN=mine\n" {
+		t.Fatalf("expected the edit to survive, got %q", data)
+	}
+}
+
+func TestSyncFilesRejectsBadRules(t *testing.T) {
+	root := t.TempDir()
+	worktree := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, ".env"), []byte("A=1\n"), 0o644); err != nil {
+		t.Fatalf("write .env: %v", err)
+	}
+	for _, tc := range []struct {
+		rule SyncFile
+		want string
+	}{
+		{SyncFile{Source: ".env", Mode: "hardlink"}, "must be copy or symlink"},
+		{SyncFile{Source: ".env", Destination: "../.env"}, "outside the worktree"},
+		{SyncFile{Source: ".", Destination: "dir"}, "needs a regular file"},
+	} {
+		rules := normalizeSyncFiles([]SyncFile{tc.rule})
+		err := syncWorktreeFiles(root, worktree, rules, syncOnCreate)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("rule %+v: expected %q, got %v", tc.rule, tc.want, err)
+		}
+	}
+}
+
+func TestSyncFilesKeepsRepoSourcesInsideTheCheckout(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	outside := t.TempDir()
+	root := t.TempDir()
+	worktree := t.TempDir()
+	if err := os.WriteFile(filepath.Join(outside, "id_rsa"), []byte("KEY\n"), 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+	if err := os.Symlink(filepath.Join(outside, "id_rsa"), filepath.Join(root, "key")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+	for _, tc := range []struct {
+		source string
+		want   string
+	}{
+		{"~/.ssh/id_rsa", "must be relative to the main checkout"},
+		{filepath.Join(outside, "id_rsa"), "must be relative to the main checkout"},
+		{"../" + filepath.Base(outside) + "/id_rsa", "resolves outside the main checkout"},
+		{"key", "resolves outside the main checkout"},
+	} {
+		rules := normalizeSyncFiles([]SyncFile{{Source: tc.source, Destination: "copied"}})
+		rules[0].fromRepo = true
+		err := syncWorktreeFiles(root, worktree, rules, syncOnCreate)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("source %q: expected %q, got %v", tc.source, tc.want, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(worktree, "copied")); !os.IsNotExist(err) {
+		t.Fatalf("expected nothing copied, stat err=%v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package core
+
+import ()
+
+type WorktreeInfo struct {
+	Path      string
+	Branch    string
+	Available bool
+	// Broken says why the worktree's .git file or admin dir is damaged.
+	Broken              string
+	Idle                bool
+	AgentExit           string
+	Bootstrap           string
+	Tests               string
+	LastUsedUnix        int64
+	LastCommit          BranchCommit
+	Note                string
+	Pinned              bool
+	LockHolder          LockHolder
+	PRURL               string
+	PRNumber            int
+	HasPR               bool
+	PRStatus            string
+	CIState             PRCIState
+	CIDone              int
+	CITotal             int
+	CIFailingNames      string
+	Approved            bool
+	ReviewApproved      int
+	ReviewRequired      int
+	ReviewKnown         bool
+	UnresolvedComments  int
+	ResolvedComments    int
+	CommentThreadsTotal int
+	CommentsKnown       bool
+	// HookWarning is set on a worktree just created whose post_create hook
+	// failed, for the caller to show.
+	HookWarning string
+}
+
+type WorktreeStatus struct {
+	GitInstalled bool
+	InRepo       bool
+	RepoRoot     string
+	CWD          string
+	HasRemote    bool
+	BaseRef      string
+	Worktrees    []WorktreeInfo
+	Orphaned     []WorktreeInfo
+	Malformed    []string
+	Err          error `json:"-"`
+	// ListSort orders worktreesForDisplay. Only the table view sets it.
+	ListSort ListSortMode
+}
+
+// BranchCommit is the tip commit of a local branch and how far it has
+// diverged from its upstream.
+type BranchCommit struct {
+	Hash          string
+	Subject       string
+	CommittedUnix int64
+	Upstream      string
+	Ahead         int
+	Behind        int
+	UpstreamGone  bool
+}
@@ -0,0 +1,74 @@
+// Package ghstatus looks up pull request, review and CI state for branches
+// through the gh CLI, with the same caching wtx uses for its table.
+package ghstatus
+
+import "github.com/aixolotls/wtx/internal/core"
+
+// CIState values match the ci_state field of `wtx list --json`.
+const (
+	CINone       = "none"
+	CIInProgress = "in_progress"
+	CIFail       = "fail"
+	CISuccess    = "success"
+)
+
+type PR struct {
+	Number             int
+	URL                string
+	Branch             string
+	Status             string
+	Approved           bool
+	ReviewApproved     int
+	ReviewRequired     int
+	CIState            string
+	CICompleted        int
+	CITotal            int
+	CIFailingNames     string
+	UnresolvedComments int
+	ResolvedComments   int
+}
+
+// Client caches results per repo for a short time, so polling is cheap.
+type Client struct {
+	gh *core.GHManager
+}
+
+func New() *Client {
+	return &Client{gh: core.NewGHManager()}
+}
+
+// ForBranches returns the open or most recent PR for each branch that has
+// one. Branches without a PR are left out of the map.
+func (c *Client) ForBranches(repoRoot string, branches []string) (map[string]PR, error) {
+	return convert(c.gh.PRDataByBranch(repoRoot, branches))
+}
+
+// Refresh is ForBranches bypassing the cache.
+func (c *Client) Refresh(repoRoot string, branches []string) (map[string]PR, error) {
+	return convert(c.gh.PRDataByBranchForce(repoRoot, branches))
+}
+
+func convert(byBranch map[string]core.PRData, err error) (map[string]PR, error) {
+	out := make(map[string]PR, len(byBranch))
+	for branch, d := range byBranch {
+		if d.Number <= 0 {
+			continue
+		}
+		out[branch] = PR{
+			Number:             d.Number,
+			URL:                d.URL,
+			Branch:             d.Branch,
+			Status:             d.Status,
+			Approved:           d.Approved,
+			ReviewApproved:     d.ReviewApproved,
+			ReviewRequired:     d.ReviewRequired,
+			CIState:            string(d.CIState),
+			CICompleted:        d.CICompleted,
+			CITotal:            d.CITotal,
+			CIFailingNames:     d.CIFailingNames,
+			UnresolvedComments: d.UnresolvedComments,
+			ResolvedComments:   d.ResolvedComments,
+		}
+	}
+	return out, err
+}
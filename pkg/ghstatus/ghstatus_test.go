@@ -0,0 +1,39 @@
+package ghstatus
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+func TestCIStatesMatchCore(t *testing.T) {
+	for got, want := range map[string]core.PRCIState{
+		CINone:       core.PRCINone,
+		CIInProgress: core.PRCIInProgress,
+		CIFail:       core.PRCIFail,
+		CISuccess:    core.PRCISuccess,
+	} {
+		if got != string(want) {
+			t.Fatalf("CI state %q does not match core %q", got, want)
+		}
+	}
+}
+
+func TestConvertSkipsBranchesWithoutPR(t *testing.T) {
+	lookupErr := errors.New("gh failed")
+	out, err := convert(map[string]core.PRData{
+		"feature": {Number: 12, URL: "https://example.com/pr/12", Branch: "feature", CIState: core.PRCIFail, CIFailingNames: "lint", ReviewApproved: 1},
+		"main":    {Number: 0, Branch: "main"},
+	}, lookupErr)
+	if !errors.Is(err, lookupErr) {
+		t.Fatalf("expected the lookup error passed through, got %v", err)
+	}
+	if len(out) != 1 {
+		t.Fatalf("expected only the branch with a PR, got %+v", out)
+	}
+	pr := out["feature"]
+	if pr.Number != 12 || pr.CIState != CIFail || pr.CIFailingNames != "lint" || pr.ReviewApproved != 1 {
+		t.Fatalf("unexpected PR %+v", pr)
+	}
+}
@@ -0,0 +1,63 @@
+// Package lock coordinates exclusive use of worktrees between wtx and tools
+// that embed it. Locks are the same files the wtx CLI uses, so a worktree
+// locked here shows as in use in wtx and the other way round.
+package lock
+
+import "github.com/aixolotls/wtx/internal/core"
+
+// ErrLocked is returned by Acquire when another live owner holds the lock.
+var ErrLocked = core.ErrWorktreeLocked
+
+type Manager struct {
+	m *core.LockManager
+}
+
+func New() *Manager {
+	return &Manager{m: core.NewLockManager()}
+}
+
+// Lock is a held worktree lock. Release it when done; a lock whose owning
+// process exits is treated as stale by other owners.
+type Lock struct {
+	l *core.WorktreeLock
+}
+
+func (l *Lock) Release() {
+	if l == nil {
+		return
+	}
+	l.l.Release()
+}
+
+// Holder describes who holds a lock.
+type Holder struct {
+	User        string
+	Host        string
+	TmuxSession string
+	Command     string
+}
+
+func (m *Manager) Acquire(repoRoot string, worktreePath string) (*Lock, error) {
+	l, err := m.m.Acquire(repoRoot, worktreePath)
+	if err != nil {
+		return nil, err
+	}
+	return &Lock{l: l}, nil
+}
+
+func (m *Manager) IsAvailable(repoRoot string, worktreePath string) (bool, error) {
+	return m.m.IsAvailable(repoRoot, worktreePath)
+}
+
+// ForceUnlock removes the lock whoever holds it.
+func (m *Manager) ForceUnlock(repoRoot string, worktreePath string) error {
+	return m.m.ForceUnlock(repoRoot, worktreePath)
+}
+
+func (m *Manager) Holder(repoRoot string, worktreePath string) (Holder, bool) {
+	h, ok := m.m.Holder(repoRoot, worktreePath)
+	if !ok {
+		return Holder{}, false
+	}
+	return Holder{User: h.User, Host: h.Host, TmuxSession: h.TmuxSession, Command: h.Command}, true
+}
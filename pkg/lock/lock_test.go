@@ -0,0 +1,26 @@
+package lock
+
+import "testing"
+
+func TestAcquireReportsHolderUntilReleased(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USER", "alice")
+	t.Setenv("TMUX", "")
+	t.Setenv("TMUX_PANE", "")
+	repo := t.TempDir()
+	wt := t.TempDir()
+
+	m := New()
+	l, err := m.Acquire(repo, wt)
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	holder, ok := m.Holder(repo, wt)
+	if !ok || holder.User != "alice" {
+		t.Fatalf("expected holder alice, got %+v ok=%v", holder, ok)
+	}
+	l.Release()
+	if _, ok := m.Holder(repo, wt); ok {
+		t.Fatalf("expected no holder after release")
+	}
+}
@@ -0,0 +1,129 @@
+// Package worktree lists, creates and removes the worktrees wtx manages for a
+// repository. It has no terminal UI and never exits the process; failures
+// come back as errors carrying git's own message.
+package worktree
+
+import (
+	"errors"
+	"io"
+
+	"github.com/aixolotls/wtx/internal/core"
+)
+
+var (
+	ErrGitNotInstalled = errors.New("git is not installed")
+	ErrNotInRepo       = errors.New("not in a git repository")
+)
+
+type Worktree struct {
+	Path      string
+	Branch    string
+	Available bool
+	// Holder is who is using the worktree when Available is false.
+	Holder            string
+	Note              string
+	Pinned            bool
+	LastCommitSubject string
+	LastCommitUnix    int64
+	LastUsedUnix      int64
+}
+
+type Status struct {
+	RepoRoot string
+	// BaseRef is the ref new branches start from by default.
+	BaseRef   string
+	Worktrees []Worktree
+	// Orphaned worktrees are registered with git but missing on disk.
+	Orphaned []Worktree
+}
+
+type Manager struct {
+	mgr  *core.WorktreeManager
+	orch *core.WorktreeOrchestrator
+}
+
+// Open returns a Manager for the repository containing dir. It does its work
+// in process, never through a running wtx daemon, and Status never releases
+// another process's lock.
+func Open(dir string) (*Manager, error) {
+	lockMgr := core.NewLockManager()
+	mgr := core.NewWorktreeManager(dir, lockMgr)
+	base := mgr.ListForStatusBase()
+	if !base.GitInstalled {
+		return nil, ErrGitNotInstalled
+	}
+	if !base.InRepo {
+		return nil, ErrNotInRepo
+	}
+	orch := core.NewWorktreeOrchestrator(mgr, lockMgr, nil)
+	orch.Daemon = false
+	orch.KeepIdleLocks = true
+	return &Manager{mgr: mgr, orch: orch}, nil
+}
+
+// Status lists worktrees with their lock state. PR data is not included; use
+// package ghstatus for that.
+func (m *Manager) Status() (Status, error) {
+	s := m.orch.Status()
+	if s.Err != nil {
+		return Status{}, s.Err
+	}
+	out := Status{RepoRoot: s.RepoRoot, BaseRef: s.BaseRef}
+	orphaned := map[string]bool{}
+	for _, wt := range s.Orphaned {
+		orphaned[wt.Path] = true
+		out.Orphaned = append(out.Orphaned, convert(wt))
+	}
+	for _, wt := range s.Worktrees {
+		if !orphaned[wt.Path] {
+			out.Worktrees = append(out.Worktrees, convert(wt))
+		}
+	}
+	return out, nil
+}
+
+// Create adds a worktree on a new branch started from baseRef, or the
+// repository default when baseRef is empty. Git output is copied to progress
+// when it is non-nil.
+func (m *Manager) Create(branch string, baseRef string, progress io.Writer) (Worktree, error) {
+	if baseRef == "" {
+		baseRef = m.mgr.ResolveBaseRefForNewBranch()
+	}
+	wt, err := m.mgr.CreateWorktree(branch, baseRef, progress)
+	return convert(wt), err
+}
+
+// CreateFromBranch adds a worktree for an existing local branch.
+func (m *Manager) CreateFromBranch(branch string, progress io.Writer) (Worktree, error) {
+	wt, err := m.mgr.CreateWorktreeFromBranch(branch, progress)
+	return convert(wt), err
+}
+
+// Delete removes a managed worktree. It refuses worktrees that are locked or
+// not created by wtx; force also discards local changes. The returned
+// warnings describe wtx state for the path that could not be cleaned up.
+func (m *Manager) Delete(path string, force bool) ([]string, error) {
+	if err := m.mgr.CanDeleteWorktree(path); err != nil {
+		return nil, err
+	}
+	return m.mgr.DeleteWorktree(path, force)
+}
+
+// Unlock releases the lock on a worktree whoever holds it.
+func (m *Manager) Unlock(path string) error {
+	return m.mgr.UnlockWorktree(path)
+}
+
+func convert(wt core.WorktreeInfo) Worktree {
+	return Worktree{
+		Path:              wt.Path,
+		Branch:            wt.Branch,
+		Available:         wt.Available,
+		Holder:            wt.LockHolder.String(),
+		Note:              wt.Note,
+		Pinned:            wt.Pinned,
+		LastCommitSubject: wt.LastCommit.Subject,
+		LastCommitUnix:    wt.LastCommit.CommittedUnix,
+		LastUsedUnix:      wt.LastUsedUnix,
+	}
+}
@@ -0,0 +1,104 @@
+package worktree
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func initTestRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init"},
+		{"config", "user.name", "Test User"},
+		{"config", "user.email", "test@example.com"},
+	} {
+		runGit(t, dir, args...)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("seed\n"), 0o644); err != nil {
+		t.Fatalf("write README: %v", err)
+	}
+	runGit(t, dir, "add", "README.md")
+	runGit(t, dir, "commit", "-m", "seed")
+	return dir
+}
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, strings.TrimSpace(string(out)))
+	}
+}
+
+func TestOpenOutsideRepo(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if _, err := Open(t.TempDir()); !errors.Is(err, ErrNotInRepo) {
+		t.Fatalf("expected ErrNotInRepo, got %v", err)
+	}
+}
+
+func TestOpenWorksInProcessAndKeepsLocks(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m, err := Open(initTestRepo(t))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if m.orch.Daemon || !m.orch.KeepIdleLocks {
+		t.Fatalf("expected no daemon and no idle lock release, got daemon=%v keep=%v", m.orch.Daemon, m.orch.KeepIdleLocks)
+	}
+}
+
+func TestCreateStatusDelete(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USER", "alice")
+	repo := initTestRepo(t)
+
+	m, err := Open(repo)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	created, err := m.Create("feature", "HEAD", nil)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if created.Branch != "feature" || created.Path == "" {
+		t.Fatalf("unexpected worktree %+v", created)
+	}
+
+	status, err := m.Status()
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	found := false
+	for _, wt := range status.Worktrees {
+		if wt.Path == created.Path {
+			found = true
+			if wt.Branch != "feature" || !wt.Available || wt.LastCommitSubject != "seed" {
+				t.Fatalf("unexpected status row %+v", wt)
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("expected %s in status, got %+v", created.Path, status.Worktrees)
+	}
+
+	if _, err := m.Delete(repo, false); err == nil {
+		t.Fatalf("expected deleting the main worktree to be refused")
+	}
+	warnings, err := m.Delete(created.Path, false)
+	if err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if len(warnings) > 0 {
+		t.Fatalf("expected a clean delete, got warnings %q", warnings)
+	}
+	if _, err := os.Stat(created.Path); !os.IsNotExist(err) {
+		t.Fatalf("expected %s removed, stat err=%v", created.Path, err)
+	}
+}
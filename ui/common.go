@@ -13,6 +13,7 @@ type Styles struct {
 	Disabled         func(string) string
 	DisabledSelected func(string) string
 	Secondary        func(string) string
+	Stale            func(string) string
 }
 
 func PadOrTrim(s string, width int) string {
@@ -59,3 +60,22 @@ func truncateToWidth(s string, maxWidth int) string {
 	}
 	return result.String()
 }
+
+// MiddleEllipsis shortens plain text to width by replacing its middle with
+// "...", which keeps both the root and the leaf of a path visible.
+func MiddleEllipsis(s string, width int) string {
+	runes := []rune(s)
+	if width <= 0 {
+		return ""
+	}
+	if len(runes) <= width {
+		return s
+	}
+	if width <= 3 {
+		return string(runes[len(runes)-width:])
+	}
+	keep := width - 3
+	head := keep / 2
+	tail := keep - head
+	return string(runes[:head]) + "..." + string(runes[len(runes)-tail:])
+}
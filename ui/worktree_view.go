@@ -11,62 +11,189 @@ type WorktreeRow struct {
 	CommentsLabel   string
 	UnresolvedLabel string
 	PRStatusLabel   string
+	LastCommitLabel string
+	TestsLabel      string
 	Disabled        bool
+	Marked          bool
+	// Stale marks PR derived cells as left over from an earlier fetch.
+	Stale bool
 }
 
-func RenderWorktreeSelector(rows []WorktreeRow, cursor int, styles Styles) string {
-	const (
-		branchWidth     = 40
-		prWidth         = 12
-		ciWidth         = 24
-		approvalWidth   = 12
-		commentsWidth   = 10
-		unresolvedWidth = 10
-		prStateWidth    = 17
-	)
+// StackedWidth is the terminal width below which rows are rendered as a
+// branch line with the details underneath instead of as table columns.
+const StackedWidth = 60
+
+const minBranchWidth = 20
+
+type worktreeColumn struct {
+	title string
+	width int
+	// dropOrder is the order columns are hidden in as the terminal narrows;
+	// zero means the column is always shown.
+	dropOrder int
+	// prData columns come from GitHub and are highlighted when stale.
+	prData bool
+	// tests columns are shown only when some worktree has a test result.
+	tests bool
+	value func(WorktreeRow) string
+}
+
+var worktreeColumns = []worktreeColumn{
+	{title: "Branch", width: 40, value: func(r WorktreeRow) string { return r.BranchLabel }},
+	{title: "PR", width: 12, prData: true, value: func(r WorktreeRow) string { return r.PRLabel }},
+	{title: "CI", width: 24, dropOrder: 4, prData: true, value: func(r WorktreeRow) string { return r.CILabel }},
+	{title: "Approval", width: 12, dropOrder: 5, prData: true, value: func(r WorktreeRow) string { return r.ReviewLabel }},
+	{title: "Comments", width: 10, dropOrder: 1, prData: true, value: func(r WorktreeRow) string { return r.CommentsLabel }},
+	{title: "Unresolved", width: 10, dropOrder: 2, prData: true, value: func(r WorktreeRow) string { return r.UnresolvedLabel }},
+	{title: "PR Status", width: 17, dropOrder: 6, prData: true, value: func(r WorktreeRow) string { return r.PRStatusLabel }},
+	{title: "Tests", width: 12, dropOrder: 7, tests: true, value: func(r WorktreeRow) string { return r.TestsLabel }},
+	{title: "Last commit", width: 34, dropOrder: 3, value: func(r WorktreeRow) string { return r.LastCommitLabel }},
+}
+
+// RenderWorktreeSelector renders the worktree table for a terminal of the
+// given width. A width of zero or less means unknown and shows every column.
+// Without showPR the GitHub columns are left out, for repos with no remote.
+// The Tests column appears once a row has a test result.
+func RenderWorktreeSelector(rows []WorktreeRow, cursor int, width int, showPR bool, styles Styles) string {
+	showTests := false
+	for _, row := range rows {
+		showTests = showTests || row.TestsLabel != ""
+	}
+	if width > 0 && width < StackedWidth {
+		return renderStackedWorktreeSelector(rows, cursor, width, showPR, styles)
+	}
+	columns := fitWorktreeColumns(width, showPR, showTests)
 	var b strings.Builder
-	header := formatWorktreeLine("Branch", "PR", "CI", "Approval", "Comments", "Unresolved", "PR Status", branchWidth, prWidth, ciWidth, approvalWidth, commentsWidth, unresolvedWidth, prStateWidth)
-	b.WriteString(styles.Header("  " + header))
+	titles := make([]string, len(columns))
+	for i, col := range columns {
+		titles[i] = col.title
+	}
+	b.WriteString(styles.Header("  " + formatColumns(titles, columns)))
 	b.WriteString("\n")
 	for i, row := range rows {
-		rowStyle := styles.Normal
-		rowSelectedStyle := styles.Selected
-		if row.Disabled {
-			rowStyle = styles.Disabled
-			rowSelectedStyle = styles.DisabledSelected
+		rowStyle, rowSelectedStyle := rowStyles(row, styles)
+		render := rowStyle
+		if i == cursor {
+			render = rowSelectedStyle
+		}
+		if !row.Stale || styles.Stale == nil {
+			values := make([]string, len(columns))
+			for j, col := range columns {
+				values[j] = col.value(row)
+			}
+			b.WriteString(markPrefix(row) + render(formatColumns(values, columns)))
+			b.WriteString("\n")
+			continue
+		}
+		cells := make([]string, len(columns))
+		for j, col := range columns {
+			cell := PadOrTrim(col.value(row), col.width)
+			if col.prData {
+				cells[j] = styles.Stale(cell)
+			} else {
+				cells[j] = render(cell)
+			}
+		}
+		b.WriteString(markPrefix(row) + strings.Join(cells, " "))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// fitWorktreeColumns hides low priority columns and then narrows the branch
+// column until the table fits width.
+func fitWorktreeColumns(width int, showPR bool, showTests bool) []worktreeColumn {
+	columns := make([]worktreeColumn, 0, len(worktreeColumns))
+	for _, col := range worktreeColumns {
+		if (col.prData && !showPR) || (col.tests && !showTests) {
+			continue
+		}
+		columns = append(columns, col)
+	}
+	if width <= 0 {
+		return columns
+	}
+	for drop := 1; columnsWidth(columns) > width; drop++ {
+		next := columns[:0]
+		dropped := false
+		for _, col := range columns {
+			if col.dropOrder == drop {
+				dropped = true
+				continue
+			}
+			next = append(next, col)
+		}
+		columns = next
+		if !dropped {
+			break
 		}
-		line := formatWorktreeLine(
-			row.BranchLabel,
-			row.PRLabel,
-			row.CILabel,
-			row.ReviewLabel,
-			row.CommentsLabel,
-			row.UnresolvedLabel,
-			row.PRStatusLabel,
-			branchWidth,
-			prWidth,
-			ciWidth,
-			approvalWidth,
-			commentsWidth,
-			unresolvedWidth,
-			prStateWidth,
-		)
+	}
+	if over := columnsWidth(columns) - width; over > 0 {
+		columns[0].width = max(minBranchWidth, columns[0].width-over)
+	}
+	return columns
+}
+
+func columnsWidth(columns []worktreeColumn) int {
+	total := 2 + len(columns) - 1
+	for _, col := range columns {
+		total += col.width
+	}
+	return total
+}
+
+func formatColumns(values []string, columns []worktreeColumn) string {
+	parts := make([]string, len(columns))
+	for i, col := range columns {
+		parts[i] = PadOrTrim(values[i], col.width)
+	}
+	return strings.Join(parts, " ")
+}
+
+func renderStackedWorktreeSelector(rows []WorktreeRow, cursor int, width int, showPR bool, styles Styles) string {
+	var b strings.Builder
+	for i, row := range rows {
+		rowStyle, rowSelectedStyle := rowStyles(row, styles)
+		render := rowStyle
 		if i == cursor {
-			b.WriteString("  " + rowSelectedStyle(line))
-		} else {
-			b.WriteString("  " + rowStyle(line))
+			render = rowSelectedStyle
 		}
+		b.WriteString(markPrefix(row) + render(PadOrTrim(row.BranchLabel, width-2)))
 		b.WriteString("\n")
+		details := []string{}
+		for _, col := range worktreeColumns[1:] {
+			if col.prData && !showPR {
+				continue
+			}
+			value := strings.TrimSpace(col.value(row))
+			if value == "" || value == "-" {
+				continue
+			}
+			details = append(details, value)
+		}
+		if len(details) > 0 {
+			detailStyle := styles.Secondary
+			if row.Stale && styles.Stale != nil {
+				detailStyle = styles.Stale
+			}
+			b.WriteString("    " + detailStyle(PadOrTrim(strings.Join(details, " · "), width-4)))
+			b.WriteString("\n")
+		}
 	}
 	return b.String()
 }
 
-func formatWorktreeLine(branch string, pr string, ci string, approval string, comments string, unresolved string, prState string, branchWidth int, prWidth int, ciWidth int, approvalWidth int, commentsWidth int, unresolvedWidth int, prStateWidth int) string {
-	return PadOrTrim(branch, branchWidth) + " " +
-		PadOrTrim(pr, prWidth) + " " +
-		PadOrTrim(ci, ciWidth) + " " +
-		PadOrTrim(approval, approvalWidth) + " " +
-		PadOrTrim(comments, commentsWidth) + " " +
-		PadOrTrim(unresolved, unresolvedWidth) + " " +
-		PadOrTrim(prState, prStateWidth)
+// markPrefix flags rows marked for a bulk action in the left gutter.
+func markPrefix(row WorktreeRow) string {
+	if row.Marked {
+		return "* "
+	}
+	return "  "
+}
+
+func rowStyles(row WorktreeRow, styles Styles) (func(string) string, func(string) string) {
+	if row.Disabled {
+		return styles.Disabled, styles.DisabledSelected
+	}
+	return styles.Normal, styles.Selected
 }